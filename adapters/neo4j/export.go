@@ -0,0 +1,149 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package neo4j
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Export writes the nodes and edges of g to the database using r. Each node
+// is merged as a node with the label given by opts keyed by its ID in the ID
+// property, and each edge is merged as a relationship of the type given by
+// opts with its weight in the weight property. Nodes and edges implementing
+// graph.Attributer have their attributes set as string properties. The edges
+// of an undirected graph are written once, from the node with the lower ID.
+//
+// Nodes and relationships are written in batches by statements that UNWIND a
+// list parameter, so an index or uniqueness constraint on the ID property of
+// the label is needed for large graphs to be written efficiently.
+func Export(r Runner, g graph.Graph, opts Options) error {
+	label := opts.Label
+	if label == "" {
+		label = "Node"
+	}
+	typ := opts.Type
+	if typ == "" {
+		typ = "EDGE"
+	}
+	batch := opts.BatchSize
+	if batch <= 0 {
+		batch = 1000
+	}
+	id := quote(opts.idProperty())
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	nodeStmt := fmt.Sprintf("UNWIND $rows AS row MERGE (n:%s {%s: row.id}) SET n += row.props",
+		quote(label), id)
+	var rows []interface{}
+	for _, n := range nodes {
+		p := props(n)
+		delete(p, opts.idProperty())
+		rows = append(rows, map[string]interface{}{
+			"id":    int64(n.ID()),
+			"props": p,
+		})
+		if len(rows) == batch {
+			if err := run(r, nodeStmt, rows); err != nil {
+				return err
+			}
+			rows = rows[:0]
+		}
+	}
+	if err := run(r, nodeStmt, rows); err != nil {
+		return err
+	}
+
+	edgeStmt := fmt.Sprintf("UNWIND $rows AS row MATCH (a:%[1]s {%[2]s: row.from}), (b:%[1]s {%[2]s: row.to}) "+
+		"MERGE (a)-[r:%[3]s]->(b) SET r += row.props, r.%[4]s = row.weight",
+		quote(label), id, quote(typ), quote(opts.weightProperty()))
+	_, isDirected := g.(graph.Directed)
+	rows = rows[:0]
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected && v.ID() < u.ID() {
+				continue
+			}
+			e := g.Edge(u, v)
+			rows = append(rows, map[string]interface{}{
+				"from":   int64(u.ID()),
+				"to":     int64(v.ID()),
+				"weight": e.Weight(),
+				"props":  props(e),
+			})
+			if len(rows) == batch {
+				if err := run(r, edgeStmt, rows); err != nil {
+					return err
+				}
+				rows = rows[:0]
+			}
+		}
+	}
+	return run(r, edgeStmt, rows)
+}
+
+// run runs the batch statement with the given rows if there are any.
+func run(r Runner, stmt string, rows []interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	// The rows are copied since the
+	// caller reuses the slice.
+	_, err := r.Run(stmt, map[string]interface{}{"rows": append([]interface{}(nil), rows...)})
+	return err
+}
+
+// quote returns name quoted as a Cypher identifier.
+func quote(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// props returns the attributes of v as a property map.
+func props(v interface{}) map[string]interface{} {
+	p := make(map[string]interface{})
+	if a, ok := v.(graph.Attributer); ok {
+		for _, attr := range a.Attributes() {
+			p[attr.Key] = attr.Value
+		}
+	}
+	return p
+}
+
+// attributes returns the scalar properties in p as attributes sorted by key.
+func attributes(p map[string]interface{}) []graph.Attribute {
+	var attrs []graph.Attribute
+	for k, v := range p {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case bool:
+			s = strconv.FormatBool(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		case float64:
+			s = strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			continue
+		}
+		attrs = append(attrs, graph.Attribute{Key: k, Value: s})
+	}
+	sort.Sort(byKey(attrs))
+	return attrs
+}
+
+type byKey []graph.Attribute
+
+func (a byKey) Len() int           { return len(a) }
+func (a byKey) Less(i, j int) bool { return a[i].Key < a[j].Key }
+func (a byKey) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
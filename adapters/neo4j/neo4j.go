@@ -0,0 +1,246 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package neo4j provides export of graphs to, and import of query results
+// from, the Neo4j graph database.
+//
+// The package does not implement the Bolt protocol. Statements are run by a
+// Runner, which is expected to be a thin wrapper around a session of a Bolt
+// driver that converts the driver's node and relationship values to the Node
+// and Relationship types of this package.
+package neo4j
+
+import (
+	"github.com/gonum/graph"
+)
+
+// Runner runs Cypher statements.
+type Runner interface {
+	// Run runs the statement with the
+	// given parameters and returns the
+	// records of the result.
+	Run(cypher string, params map[string]interface{}) ([]Record, error)
+}
+
+// Record is a record of a query result keyed by column name. Values are
+// Cypher values: nil, bool, int64, float64, string, []interface{},
+// map[string]interface{}, Node, Relationship or Path.
+type Record map[string]interface{}
+
+// Node is a Neo4j node. When imported into a graph, Node is a graph.Node.
+type Node struct {
+	id int
+
+	// DBID is the Neo4j ID of the node.
+	DBID int64
+
+	Labels []string
+	Props  map[string]interface{}
+}
+
+// ID returns the ID number of the node in the imported graph.
+func (n Node) ID() int { return n.id }
+
+// Attributes returns the scalar properties of the node sorted by key.
+func (n Node) Attributes() []graph.Attribute { return attributes(n.Props) }
+
+// Relationship is a Neo4j relationship. When imported into a graph,
+// Relationship is a graph.Edge.
+type Relationship struct {
+	F, T Node
+	W    float64
+
+	// DBID, Start and End are the Neo4j
+	// IDs of the relationship and its
+	// start and end nodes.
+	DBID, Start, End int64
+
+	Type  string
+	Props map[string]interface{}
+}
+
+// From returns the from-node of the relationship.
+func (r Relationship) From() graph.Node { return r.F }
+
+// To returns the to-node of the relationship.
+func (r Relationship) To() graph.Node { return r.T }
+
+// Weight returns the weight of the relationship.
+func (r Relationship) Weight() float64 { return r.W }
+
+// Attributes returns the scalar properties of the relationship sorted by key.
+func (r Relationship) Attributes() []graph.Attribute { return attributes(r.Props) }
+
+// Path is a Neo4j path.
+type Path struct {
+	Nodes         []Node
+	Relationships []Relationship
+}
+
+// Options holds options for Export and Import.
+type Options struct {
+	// IDProperty is the property holding
+	// the graph ID of a node. If empty,
+	// "id" is used.
+	IDProperty string
+
+	// WeightProperty is the property
+	// holding the weight of an edge. If
+	// empty, "weight" is used.
+	WeightProperty string
+
+	// Label is the label of exported
+	// nodes. If empty, "Node" is used.
+	Label string
+
+	// Type is the type of exported
+	// relationships. If empty, "EDGE"
+	// is used.
+	Type string
+
+	// BatchSize is the number of nodes
+	// or relationships written by each
+	// exported statement. If not
+	// positive, 1000 is used.
+	BatchSize int
+}
+
+func (o Options) idProperty() string {
+	if o.IDProperty == "" {
+		return "id"
+	}
+	return o.IDProperty
+}
+
+func (o Options) weightProperty() string {
+	if o.WeightProperty == "" {
+		return "weight"
+	}
+	return o.WeightProperty
+}
+
+// Import runs the query with the given parameters and adds to dst the nodes,
+// relationships and paths held anywhere in the records of the result.
+// Relationships are added as Relationship edges between Node nodes.
+//
+// A node is given the graph ID held by its ID property if the property is an
+// integer not already used in dst, and otherwise an ID from dst.NewNodeID.
+// The weight of a relationship is held by its weight property and is one if
+// the property is absent or not a number. Relationships between nodes that
+// are not in the result, and relationships from a node to itself, are
+// omitted.
+func Import(r Runner, query string, params map[string]interface{}, dst graph.Builder, opts Options) error {
+	records, err := r.Run(query, params)
+	if err != nil {
+		return err
+	}
+	im := importer{
+		dst:   dst,
+		opts:  opts,
+		nodes: make(map[int64]Node),
+		rels:  make(map[int64]bool),
+	}
+	for _, rec := range records {
+		for _, v := range rec {
+			im.collect(v)
+		}
+	}
+	for _, rel := range im.pending {
+		f, okf := im.nodes[rel.Start]
+		t, okt := im.nodes[rel.End]
+		if !okf || !okt || rel.Start == rel.End {
+			continue
+		}
+		rel.F, rel.T = f, t
+		rel.W = 1
+		if w, ok := number(rel.Props[opts.weightProperty()]); ok {
+			rel.W = w
+		}
+		dst.SetEdge(rel)
+	}
+	return nil
+}
+
+type importer struct {
+	dst  graph.Builder
+	opts Options
+
+	nodes   map[int64]Node
+	rels    map[int64]bool
+	pending []Relationship
+}
+
+// collect adds the nodes held in v to the destination and queues the
+// relationships held in v.
+func (im *importer) collect(v interface{}) {
+	switch v := v.(type) {
+	case Node:
+		im.node(v)
+	case Relationship:
+		if !im.rels[v.DBID] {
+			im.rels[v.DBID] = true
+			im.pending = append(im.pending, v)
+		}
+	case Path:
+		for _, n := range v.Nodes {
+			im.collect(n)
+		}
+		for _, r := range v.Relationships {
+			im.collect(r)
+		}
+	case []interface{}:
+		for _, e := range v {
+			im.collect(e)
+		}
+	case map[string]interface{}:
+		for _, e := range v {
+			im.collect(e)
+		}
+	}
+}
+
+func (im *importer) node(n Node) {
+	if _, ok := im.nodes[n.DBID]; ok {
+		return
+	}
+	id, ok := n.Props[im.opts.idProperty()].(int64)
+	if ok && int64(int(id)) == id && !im.has(int(id)) {
+		n.id = int(id)
+	} else {
+		n.id = im.dst.NewNodeID()
+	}
+	im.dst.AddNode(n)
+	im.nodes[n.DBID] = n
+}
+
+// has returns whether the destination holds a node with the given ID.
+// Destinations that are not graphs are assumed to hold only the nodes
+// added by the import.
+func (im *importer) has(id int) bool {
+	if g, ok := im.dst.(graph.Graph); ok {
+		return g.Has(idNode(id))
+	}
+	for _, n := range im.nodes {
+		if n.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// idNode is a graph.Node used to query graphs by ID.
+type idNode int
+
+func (n idNode) ID() int { return int(n) }
+
+// number returns v as a float64 if it is a number.
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
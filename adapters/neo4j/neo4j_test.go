@@ -0,0 +1,195 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package neo4j
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// fakeDB is a Runner that interprets the statements written by Export
+// and returns all its nodes and relationships for any other query.
+type fakeDB struct {
+	stmts []string
+	nodes map[int64]Node
+	rels  []Relationship
+	fail  bool
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{nodes: make(map[int64]Node)}
+}
+
+func (db *fakeDB) Run(cypher string, params map[string]interface{}) ([]Record, error) {
+	if db.fail {
+		return nil, errors.New("unavailable")
+	}
+	db.stmts = append(db.stmts, cypher)
+	switch {
+	case strings.HasPrefix(cypher, "UNWIND $rows AS row MERGE (n:"):
+		for _, row := range params["rows"].([]interface{}) {
+			row := row.(map[string]interface{})
+			id := row["id"].(int64)
+			props := map[string]interface{}{"id": id}
+			for k, v := range row["props"].(map[string]interface{}) {
+				props[k] = v
+			}
+			// Neo4j IDs are unrelated
+			// to the graph IDs.
+			db.nodes[id] = Node{DBID: 100 + id, Labels: []string{"Node"}, Props: props}
+		}
+	case strings.HasPrefix(cypher, "UNWIND $rows AS row MATCH"):
+		for _, row := range params["rows"].([]interface{}) {
+			row := row.(map[string]interface{})
+			from, to := row["from"].(int64), row["to"].(int64)
+			props := map[string]interface{}{"weight": row["weight"]}
+			for k, v := range row["props"].(map[string]interface{}) {
+				props[k] = v
+			}
+			db.rels = append(db.rels, Relationship{
+				DBID:  int64(len(db.rels)),
+				Start: db.nodes[from].DBID,
+				End:   db.nodes[to].DBID,
+				Type:  "EDGE",
+				Props: props,
+			})
+		}
+	default:
+		var recs []Record
+		for _, r := range db.rels {
+			var start, end Node
+			for _, n := range db.nodes {
+				if n.DBID == r.Start {
+					start = n
+				}
+				if n.DBID == r.End {
+					end = n
+				}
+			}
+			recs = append(recs, Record{"p": Path{Nodes: []Node{start, end}, Relationships: []Relationship{r}}})
+		}
+		return recs, nil
+	}
+	return nil, nil
+}
+
+func TestExportImport(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 2},
+		{F: simple.Node(1), T: simple.Node(2), W: 3},
+		{F: simple.Node(2), T: simple.Node(0), W: 4},
+		{F: simple.Node(2), T: simple.Node(3), W: 5},
+		{F: simple.Node(4), T: simple.Node(3), W: 6},
+	} {
+		g.SetEdge(e)
+	}
+
+	db := newFakeDB()
+	err := Export(db, g, Options{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	// Five nodes and five edges are
+	// written in batches of two.
+	if len(db.stmts) != 6 {
+		t.Errorf("unexpected number of statements: got:%d want:6", len(db.stmts))
+	}
+	if !strings.Contains(db.stmts[0], "MERGE (n:`Node` {`id`: row.id})") {
+		t.Errorf("unexpected node statement: %s", db.stmts[0])
+	}
+
+	dst := simple.NewDirectedGraph(0, math.Inf(1))
+	err = Import(db, "MATCH p=()-->() RETURN p", nil, dst, Options{})
+	if err != nil {
+		t.Fatalf("unexpected import error: %v", err)
+	}
+	if got, want := ids(dst.Nodes()), ids(g.Nodes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected imported nodes: got:%v want:%v", got, want)
+	}
+	if len(dst.Edges()) != len(g.Edges()) {
+		t.Errorf("unexpected number of imported edges: got:%d want:%d", len(dst.Edges()), len(g.Edges()))
+	}
+	for _, e := range g.Edges() {
+		got := dst.Edge(e.From(), e.To())
+		if got == nil || got.Weight() != e.Weight() {
+			t.Errorf("unexpected imported edge %d→%d: got:%v want weight:%v", e.From().ID(), e.To().ID(), got, e.Weight())
+			continue
+		}
+		if _, ok := got.(Relationship); !ok {
+			t.Errorf("unexpected imported edge type: %T", got)
+		}
+	}
+
+	db.fail = true
+	if Export(db, g, Options{}) == nil {
+		t.Error("expected export error")
+	}
+	if Import(db, "MATCH (n) RETURN n", nil, simple.NewDirectedGraph(0, math.Inf(1)), Options{}) == nil {
+		t.Error("expected import error")
+	}
+}
+
+func TestImportIDs(t *testing.T) {
+	recs := []Record{
+		{"n": Node{DBID: 1, Props: map[string]interface{}{"id": int64(5), "name": "a"}}},
+		{"n": Node{DBID: 2, Props: map[string]interface{}{"id": int64(5)}}},
+		{"n": Node{DBID: 3, Props: map[string]interface{}{"id": "x"}}},
+		{"r": []interface{}{
+			Relationship{DBID: 1, Start: 1, End: 2, Props: map[string]interface{}{"weight": int64(7)}},
+			Relationship{DBID: 2, Start: 2, End: 2},
+			Relationship{DBID: 3, Start: 3, End: 9},
+		}},
+	}
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := Import(runnerFunc(func(string, map[string]interface{}) ([]Record, error) {
+		return recs, nil
+	}), "", nil, dst, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nodes := dst.Nodes()
+	if len(nodes) != 3 {
+		t.Fatalf("unexpected number of nodes: got:%d want:3", len(nodes))
+	}
+	if !dst.Has(simple.Node(5)) {
+		t.Error("expected node with ID property to keep its ID")
+	}
+	if got := dst.Node(5).(Node).Attributes(); !reflect.DeepEqual(got, []graph.Attribute{{Key: "id", Value: "5"}, {Key: "name", Value: "a"}}) {
+		t.Errorf("unexpected attributes: got:%v", got)
+	}
+	edges := dst.Edges()
+	if len(edges) != 1 || edges[0].Weight() != 7 {
+		t.Errorf("unexpected edges: got:%v", edges)
+	}
+}
+
+type runnerFunc func(string, map[string]interface{}) ([]Record, error)
+
+func (f runnerFunc) Run(cypher string, params map[string]interface{}) ([]Record, error) {
+	return f(cypher, params)
+}
+
+func TestQuote(t *testing.T) {
+	if got, want := quote("we`ird"), "`we``ird`"; got != want {
+		t.Errorf("unexpected quoting: got:%s want:%s", got, want)
+	}
+}
+
+func ids(nodes []graph.Node) []int {
+	id := make([]int, len(nodes))
+	for i, n := range nodes {
+		id[i] = n.ID()
+	}
+	sort.Ints(id)
+	return id
+}
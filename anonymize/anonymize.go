@@ -0,0 +1,327 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package anonymize provides structural anonymization of graphs for the
+// publication of graph data sets.
+package anonymize
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// IsKDegreeAnonymous returns whether every node of g shares its degree with
+// at least k-1 other nodes. For directed graphs the degree of a node is the
+// sum of its in- and out-degrees.
+func IsKDegreeAnonymous(g graph.Graph, k int) bool {
+	count := make(map[int]int)
+	for _, n := range g.Nodes() {
+		count[degree(g, n)]++
+	}
+	for _, c := range count {
+		if c < k {
+			return false
+		}
+	}
+	return true
+}
+
+func degree(g graph.Graph, n graph.Node) int {
+	d := len(g.From(n))
+	if dg, ok := g.(graph.Directed); ok {
+		d += len(dg.To(n))
+	}
+	return d
+}
+
+// KDegree places in dst a copy of the undirected graph g with edges added so
+// that the result is k-degree anonymous, as described by Liu and Terzi,
+// doi:10.1145/1376616.1376629. The target degree sequence is the k-anonymous
+// sequence closest to the degree sequence of g that only increases degrees,
+// found by dynamic programming, and it is realized by greedily joining the
+// nodes with the largest remaining degree deficits. When the target cannot
+// be realized by adding edges, the degree sequence of the augmented graph is
+// anonymized again until the result is k-degree anonymous.
+//
+// Added edges are simple.Edge values with a weight of 1. KDegree returns an
+// error if g has fewer than k nodes and is not empty.
+func KDegree(dst graph.UndirectedBuilder, g graph.Undirected, k int) error {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	if len(nodes) != 0 && len(nodes) < k {
+		return errors.New("anonymize: fewer than k nodes")
+	}
+
+	adj := make(map[int]map[int]bool, len(nodes))
+	byID := make(map[int]graph.Node, len(nodes))
+	for _, u := range nodes {
+		byID[u.ID()] = u
+		adj[u.ID()] = make(map[int]bool)
+		for _, v := range g.From(u) {
+			if v.ID() != u.ID() {
+				adj[u.ID()][v.ID()] = true
+			}
+		}
+	}
+	var added [][2]int
+	join := func(u, v int) {
+		adj[u][v] = true
+		adj[v][u] = true
+		added = append(added, [2]int{u, v})
+	}
+
+	for {
+		deficit := anonymousDeficits(nodes, adj, k)
+		if deficit == nil {
+			break
+		}
+		u := byDeficit(nodes, deficit)[0]
+		if !realize(nodes, adj, deficit, join) {
+			// The deficit nodes are mutually adjacent
+			// so join the node with the largest deficit
+			// to the non-adjacent node with the lowest
+			// degree to make progress.
+			v := -1
+			for _, n := range nodes {
+				id := n.ID()
+				if id == u || adj[u][id] {
+					continue
+				}
+				if v < 0 || len(adj[id]) < len(adj[v]) {
+					v = id
+				}
+			}
+			join(u, v)
+		}
+	}
+
+	for _, n := range nodes {
+		if !dst.Has(n) {
+			dst.AddNode(n)
+		}
+	}
+	for _, u := range nodes {
+		for _, v := range g.From(u) {
+			if v.ID() >= u.ID() {
+				dst.SetEdge(g.Edge(u, v))
+			}
+		}
+	}
+	for _, e := range added {
+		dst.SetEdge(simple.Edge{F: byID[e[0]], T: byID[e[1]], W: 1})
+	}
+	return nil
+}
+
+// anonymousDeficits returns the degree increase required for each node to
+// reach the closest k-anonymous degree sequence, keyed on node ID, or nil
+// if the degree sequence is already k-anonymous.
+func anonymousDeficits(nodes []graph.Node, adj map[int]map[int]bool, k int) map[int]int {
+	order := make([]int, len(nodes))
+	for i, n := range nodes {
+		order[i] = n.ID()
+	}
+	sort.Sort(byDegree{ids: order, adj: adj})
+	d := make([]int, len(order))
+	for i, id := range order {
+		d[i] = len(adj[id])
+	}
+
+	// cost(i, j) is the cost of raising the degrees
+	// d[i:j+1] to d[i], calculated from prefix sums.
+	sum := make([]int, len(d)+1)
+	for i, v := range d {
+		sum[i+1] = sum[i] + v
+	}
+	cost := func(i, j int) int {
+		return (j-i+1)*d[i] - (sum[j+1] - sum[i])
+	}
+
+	// best[j] is the least cost of anonymizing d[:j]
+	// and start[j] is the start of its last group.
+	// Groups of at least 2k nodes can be split at no
+	// greater cost so only smaller groups are tried.
+	best := make([]int, len(d)+1)
+	start := make([]int, len(d)+1)
+	for j := 1; j <= len(d); j++ {
+		best[j] = math.MaxInt64
+		for i := j - 2*k + 1; i <= j-k; i++ {
+			if i < 0 || best[i] == math.MaxInt64 {
+				continue
+			}
+			if c := best[i] + cost(i, j-1); c < best[j] {
+				best[j] = c
+				start[j] = i
+			}
+		}
+	}
+	if best[len(d)] == 0 {
+		return nil
+	}
+
+	deficit := make(map[int]int)
+	for j := len(d); j > 0; j = start[j] {
+		i := start[j]
+		for l := i; l < j; l++ {
+			if d[i] > d[l] {
+				deficit[order[l]] = d[i] - d[l]
+			}
+		}
+	}
+	return deficit
+}
+
+// realize adds edges between nodes with positive deficits, largest first,
+// using join and reducing the deficits. It returns whether any edge was added.
+func realize(nodes []graph.Node, adj map[int]map[int]bool, deficit map[int]int, join func(u, v int)) bool {
+	var progress bool
+	for {
+		order := byDeficit(nodes, deficit)
+		if len(order) == 0 {
+			return progress
+		}
+		u := order[0]
+		var joined bool
+		for _, v := range order[1:] {
+			if deficit[u] == 0 {
+				break
+			}
+			if adj[u][v] {
+				continue
+			}
+			join(u, v)
+			joined = true
+			deficit[u]--
+			deficit[v]--
+			if deficit[v] == 0 {
+				delete(deficit, v)
+			}
+		}
+		// Any deficit remaining for u cannot be met
+		// by joining u to another deficit node.
+		delete(deficit, u)
+		progress = progress || joined
+	}
+}
+
+// byDeficit returns the IDs of the nodes with positive deficits ordered by
+// decreasing deficit and then by ID.
+func byDeficit(nodes []graph.Node, deficit map[int]int) []int {
+	var ids []int
+	for _, n := range nodes {
+		if deficit[n.ID()] > 0 {
+			ids = append(ids, n.ID())
+		}
+	}
+	sort.Stable(byValueDesc{ids: ids, val: deficit})
+	return ids
+}
+
+// byDegree sorts node IDs by decreasing degree and then by ID.
+type byDegree struct {
+	ids []int
+	adj map[int]map[int]bool
+}
+
+func (s byDegree) Len() int { return len(s.ids) }
+func (s byDegree) Less(i, j int) bool {
+	di, dj := len(s.adj[s.ids[i]]), len(s.adj[s.ids[j]])
+	if di == dj {
+		return s.ids[i] < s.ids[j]
+	}
+	return di > dj
+}
+func (s byDegree) Swap(i, j int) { s.ids[i], s.ids[j] = s.ids[j], s.ids[i] }
+
+// byValueDesc sorts IDs by decreasing value.
+type byValueDesc struct {
+	ids []int
+	val map[int]int
+}
+
+func (s byValueDesc) Len() int           { return len(s.ids) }
+func (s byValueDesc) Less(i, j int) bool { return s.val[s.ids[i]] > s.val[s.ids[j]] }
+func (s byValueDesc) Swap(i, j int)      { s.ids[i], s.ids[j] = s.ids[j], s.ids[i] }
+
+// Randomize places in dst a copy of the undirected graph g with its edges
+// rewired by the given number of attempted random edge switches, as
+// described by Ying and Wu, doi:10.1137/1.9781611972788.67. Each switch
+// replaces a pair of edges u-v and x-y with the edges u-y and x-v, each
+// new edge keeping the weight of the edge it replaces, randomizing the
+// neighborhoods of the nodes while preserving their degrees. Switches that
+// would create a self edge or join a pair of nodes already joined are
+// rejected. Edges of dst are simple.Edge values. If src is not nil it is
+// used as the random source, otherwise rand.Intn is used.
+func Randomize(dst graph.UndirectedBuilder, g graph.Undirected, switches int, src *rand.Rand) {
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = src.Intn
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []simple.Edge
+	has := make(map[[2]int]bool)
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if v.ID() < u.ID() {
+				continue
+			}
+			edges = append(edges, simple.Edge{F: u, T: v, W: g.Edge(u, v).Weight()})
+			has[key(u, v)] = true
+		}
+	}
+
+	for i := 0; i < switches && len(edges) > 1; i++ {
+		a, b := rnd(len(edges)), rnd(len(edges))
+		if a == b {
+			continue
+		}
+		u, v := edges[a].F, edges[a].T
+		x, y := edges[b].F, edges[b].T
+		if rnd(2) == 0 {
+			x, y = y, x
+		}
+		if u.ID() == y.ID() || x.ID() == v.ID() {
+			continue
+		}
+		uy, xv := key(u, y), key(x, v)
+		if uy == xv || has[uy] || has[xv] {
+			continue
+		}
+		delete(has, key(u, v))
+		delete(has, key(x, y))
+		has[uy] = true
+		has[xv] = true
+		edges[a] = simple.Edge{F: u, T: y, W: edges[a].W}
+		edges[b] = simple.Edge{F: x, T: v, W: edges[b].W}
+	}
+
+	for _, n := range nodes {
+		if !dst.Has(n) {
+			dst.AddNode(n)
+		}
+	}
+	for _, e := range edges {
+		dst.SetEdge(e)
+	}
+}
+
+// key returns an order-independent key for the pair of nodes.
+func key(u, v graph.Node) [2]int {
+	if u.ID() > v.ID() {
+		return [2]int{v.ID(), u.ID()}
+	}
+	return [2]int{u.ID(), v.ID()}
+}
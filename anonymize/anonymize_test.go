@@ -0,0 +1,109 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anonymize
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+func randomGraph(n, m int, rnd *rand.Rand) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < m; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u != v {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: float64(1 + rnd.Intn(4))})
+		}
+	}
+	return g
+}
+
+func TestKDegree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ n, m, k int }{
+		{n: 10, m: 15, k: 2},
+		{n: 50, m: 100, k: 3},
+		{n: 100, m: 400, k: 5},
+		{n: 100, m: 50, k: 10},
+		{n: 12, m: 60, k: 12},
+	} {
+		g := randomGraph(test.n, test.m, rnd)
+		dst := simple.NewUndirectedGraph(0, math.Inf(1))
+		if err := KDegree(dst, g, test.k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !IsKDegreeAnonymous(dst, test.k) {
+			t.Errorf("result is not %d-degree anonymous for n=%d m=%d", test.k, test.n, test.m)
+		}
+		if len(dst.Nodes()) != test.n {
+			t.Errorf("unexpected number of nodes: got:%d want:%d", len(dst.Nodes()), test.n)
+		}
+		for _, e := range g.Edges() {
+			got := dst.EdgeBetween(e.From(), e.To())
+			if got == nil || got.Weight() != e.Weight() {
+				t.Errorf("edge %d-%d of original graph not retained", e.From().ID(), e.To().ID())
+			}
+		}
+	}
+
+	// An already anonymous graph is unaltered.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 6; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 6), W: 1})
+	}
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := KDegree(dst, g, 6); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dst.Edges()) != 6 {
+		t.Errorf("unexpected number of edges for anonymous graph: got:%d want:6", len(dst.Edges()))
+	}
+
+	if err := KDegree(simple.NewUndirectedGraph(0, math.Inf(1)), g, 7); err == nil {
+		t.Errorf("expected error for k greater than order")
+	}
+}
+
+func TestRandomize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := randomGraph(100, 300, rnd)
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	Randomize(dst, g, 1000, rnd)
+
+	if len(dst.Edges()) != len(g.Edges()) {
+		t.Errorf("unexpected number of edges: got:%d want:%d", len(dst.Edges()), len(g.Edges()))
+	}
+	var weight, dstWeight float64
+	for _, e := range g.Edges() {
+		weight += e.Weight()
+	}
+	var same int
+	for _, e := range dst.Edges() {
+		dstWeight += e.Weight()
+		if e.From().ID() == e.To().ID() {
+			t.Errorf("unexpected self edge at %d", e.From().ID())
+		}
+		if g.HasEdgeBetween(e.From(), e.To()) {
+			same++
+		}
+	}
+	if weight != dstWeight {
+		t.Errorf("unexpected total weight: got:%v want:%v", dstWeight, weight)
+	}
+	if same == len(g.Edges()) {
+		t.Errorf("edges not randomized")
+	}
+	for _, n := range g.Nodes() {
+		if got, want := degree(dst, n), degree(g, n); got != want {
+			t.Errorf("unexpected degree for node %d: got:%d want:%d", n.ID(), got, want)
+		}
+	}
+}
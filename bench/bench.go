@@ -0,0 +1,193 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bench provides standardized benchmark workloads over generated
+// graphs and alternative graph representations, so that the performance of
+// graph algorithms and graph storage can be measured and compared.
+//
+// The benchmarks in this package are run with
+//
+//  go test -run NONE -bench . github.com/gonum/graph/bench
+//
+// and are named Benchmark<Workload><Representation>_<order>, so changes in
+// performance can be compared with benchcmp.
+package bench
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/network"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/traverse"
+)
+
+// Degree is the mean out-degree of the graphs returned by Graph.
+const Degree = 8
+
+// Scales holds the standard graph orders used by the benchmarks.
+var Scales = []int{100, 1000, 10000}
+
+// Graph returns a directed Erdős-Rényi graph with n nodes with IDs 0 to n-1
+// and approximately n*degree edges with weights uniformly distributed in
+// [1, 2). The graph is generated deterministically from seed.
+func Graph(n, degree int, seed int64) *simple.DirectedGraph {
+	rnd := rand.New(rand.NewSource(seed))
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	u := simple.NewDirectedGraph(0, math.Inf(1))
+	err := gen.Gnm(u, n, n*degree, rnd)
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range Edges(u) {
+		g.SetEdge(simple.Edge{F: e.From(), T: e.To(), W: 1 + rnd.Float64()})
+	}
+	return g
+}
+
+// Edges returns the edges of g in a deterministic order.
+func Edges(g graph.Directed) []graph.Edge {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []graph.Edge
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			edges = append(edges, g.Edge(u, v))
+		}
+	}
+	return edges
+}
+
+// Representation is a graph storage representation.
+type Representation struct {
+	// Name is the name of the
+	// representation.
+	Name string
+
+	// From returns a graph in the
+	// representation holding the
+	// nodes and weighted edges of g,
+	// which has node IDs from zero
+	// to the order of g less one.
+	From func(g graph.Directed) graph.Directed
+}
+
+// Representations holds the graph representations compared by the
+// benchmarks: the map-based simple.DirectedGraph, the dense
+// simple.DirectedMatrix, the slice-based simple.ArenaDirectedGraph and the
+// compressed sparse row CSR.
+var Representations = []Representation{
+	{Name: "Map", From: func(g graph.Directed) graph.Directed {
+		dst := simple.NewDirectedGraph(0, math.Inf(1))
+		graph.Copy(dst, g)
+		return dst
+	}},
+	{Name: "Matrix", From: func(g graph.Directed) graph.Directed {
+		dst := simple.NewDirectedMatrixFrom(g.Nodes(), math.Inf(1), 0, math.Inf(1))
+		for _, u := range g.Nodes() {
+			for _, v := range g.From(u) {
+				dst.SetEdge(g.Edge(u, v))
+			}
+		}
+		return dst
+	}},
+	{Name: "Arena", From: func(g graph.Directed) graph.Directed {
+		dst := simple.NewArenaDirectedGraph(0, math.Inf(1))
+		graph.Copy(dst, g)
+		return dst
+	}},
+	{Name: "CSR", From: func(g graph.Directed) graph.Directed { return NewCSR(g) }},
+}
+
+// Workload is a benchmark workload.
+type Workload struct {
+	// Name is the name of the workload.
+	Name string
+
+	// Run runs the workload on g, which
+	// has node IDs from zero to the order
+	// of g less one.
+	Run func(g graph.Directed)
+}
+
+// Workloads holds the standard read-only workloads.
+var Workloads = []Workload{
+	{Name: "BFS", Run: BFS},
+	{Name: "Dijkstra", Run: Dijkstra},
+	{Name: "PageRank", Run: PageRank},
+}
+
+// BFS performs a breadth-first traversal of g from node zero.
+func BFS(g graph.Directed) {
+	var bf traverse.BreadthFirst
+	bf.Walk(g, simple.Node(0), nil)
+}
+
+// Dijkstra finds the shortest paths from node zero in g.
+func Dijkstra(g graph.Directed) {
+	path.DijkstraFrom(simple.Node(0), g)
+}
+
+// PageRank calculates the PageRank of the nodes of g.
+func PageRank(g graph.Directed) {
+	network.PageRankSparse(g, 0.85, 1e-6)
+}
+
+// Insert adds the given edges to dst.
+func Insert(dst graph.EdgeSetter, edges []graph.Edge) {
+	for _, e := range edges {
+		dst.SetEdge(e)
+	}
+}
+
+// Run runs the workload b.N times on g, excluding any setup from the timing.
+func Run(b *testing.B, w Workload, g graph.Directed) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Run(g)
+	}
+}
+
+// Result is the result of benchmarking a workload on a representation.
+type Result struct {
+	Workload       string
+	Representation string
+	Order          int
+	testing.BenchmarkResult
+}
+
+// Compare benchmarks each workload on each representation of graphs
+// returned by Graph with the given orders and Degree, and returns the
+// results. Compare is intended for use by tools that report performance
+// across representations; individual measurements are available as
+// benchmarks in this package.
+func Compare(workloads []Workload, reps []Representation, orders []int) []Result {
+	var results []Result
+	for _, n := range orders {
+		g := Graph(n, Degree, 1)
+		for _, r := range reps {
+			h := r.From(g)
+			for _, w := range workloads {
+				results = append(results, Result{
+					Workload:        w.Name,
+					Representation:  r.Name,
+					Order:           n,
+					BenchmarkResult: testing.Benchmark(func(b *testing.B) { Run(b, w, h) }),
+				})
+			}
+		}
+	}
+	return results
+}
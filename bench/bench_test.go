@@ -0,0 +1,263 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/testgraphs"
+)
+
+func TestGraph(t *testing.T) {
+	const n = 100
+	g := Graph(n, Degree, 1)
+	if len(g.Nodes()) != n {
+		t.Errorf("unexpected order: got:%d want:%d", len(g.Nodes()), n)
+	}
+	if m := len(g.Edges()); m != n*Degree {
+		t.Errorf("unexpected size: got:%d want:%d", m, n*Degree)
+	}
+	if !reflect.DeepEqual(Edges(g), Edges(Graph(n, Degree, 1))) {
+		t.Error("graph generation is not deterministic")
+	}
+}
+
+func TestRepresentations(t *testing.T) {
+	g := Graph(50, 4, 1)
+	want := Edges(g)
+	for _, r := range Representations {
+		h := r.From(g)
+		if err := testgraphs.Check(h); err != nil {
+			t.Errorf("%s: %v", r.Name, err)
+		}
+		got := Edges(h)
+		if len(got) != len(want) {
+			t.Errorf("%s: unexpected number of edges: got:%d want:%d", r.Name, len(got), len(want))
+			continue
+		}
+		for i, e := range got {
+			if e.From().ID() != want[i].From().ID() || e.To().ID() != want[i].To().ID() || e.Weight() != want[i].Weight() {
+				t.Errorf("%s: unexpected edge: got:%d->%d:%v want:%d->%d:%v", r.Name,
+					e.From().ID(), e.To().ID(), e.Weight(), want[i].From().ID(), want[i].To().ID(), want[i].Weight())
+				break
+			}
+		}
+	}
+}
+
+func TestCSRFixtures(t *testing.T) {
+	for _, f := range testgraphs.Fixtures {
+		if !f.Directed {
+			continue
+		}
+		c := NewCSR(f.Graph().(graph.Directed))
+		if err := testgraphs.CheckFixture(c, f); err != nil {
+			t.Errorf("unexpected error for %s: %v", f.Name, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping benchmark comparison in short mode")
+	}
+	results := Compare(Workloads[:1], Representations, []int{10})
+	if len(results) != len(Representations) {
+		t.Fatalf("unexpected number of results: got:%d want:%d", len(results), len(Representations))
+	}
+	for i, r := range results {
+		if r.Representation != Representations[i].Name || r.Workload != Workloads[0].Name || r.Order != 10 {
+			t.Errorf("unexpected result labels: %+v", r)
+		}
+		if r.N == 0 {
+			t.Errorf("no iterations run for %s", r.Representation)
+		}
+	}
+}
+
+// graphs holds generated graphs keyed by
+// representation name and order.
+var graphs = make(map[string]graph.Directed)
+
+func graphFor(rep string, n int) graph.Directed {
+	key := rep + strconv.Itoa(n)
+	if g, ok := graphs[key]; ok {
+		return g
+	}
+	var g graph.Directed
+	for _, r := range Representations {
+		if r.Name == rep {
+			g = r.From(Graph(n, Degree, 1))
+		}
+	}
+	graphs[key] = g
+	return g
+}
+
+func benchmarkWorkload(b *testing.B, w func(graph.Directed), rep string, n int) {
+	Run(b, Workload{Run: w}, graphFor(rep, n))
+}
+
+func benchmarkInsert(b *testing.B, newBuilder func() graph.EdgeSetter, n int) {
+	edges := Edges(graphFor("Map", n))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		dst := newBuilder()
+		b.StartTimer()
+		Insert(dst, edges)
+	}
+}
+
+func newMap(int) graph.EdgeSetter { return simple.NewDirectedGraph(0, math.Inf(1)) }
+func newMapWithCapacity(n int) graph.EdgeSetter {
+	return simple.NewDirectedGraphWithCapacity(n, Degree, 0, math.Inf(1))
+}
+func newArena(int) graph.EdgeSetter { return simple.NewArenaDirectedGraph(0, math.Inf(1)) }
+func newMatrix(n int) graph.EdgeSetter {
+	return simple.NewDirectedMatrix(n, math.Inf(1), 0, math.Inf(1))
+}
+
+func BenchmarkBFSMap_100(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Map", 100)
+}
+func BenchmarkBFSMap_1000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Map", 1000)
+}
+func BenchmarkBFSMap_10000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Map", 10000)
+}
+func BenchmarkBFSMatrix_100(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Matrix", 100)
+}
+func BenchmarkBFSMatrix_1000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Matrix", 1000)
+}
+func BenchmarkBFSCSR_100(b *testing.B) {
+	benchmarkWorkload(b, BFS, "CSR", 100)
+}
+func BenchmarkBFSCSR_1000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "CSR", 1000)
+}
+func BenchmarkBFSCSR_10000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "CSR", 10000)
+}
+
+func BenchmarkDijkstraMap_100(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Map", 100)
+}
+func BenchmarkDijkstraMap_1000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Map", 1000)
+}
+func BenchmarkDijkstraMap_10000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Map", 10000)
+}
+func BenchmarkDijkstraMatrix_100(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Matrix", 100)
+}
+func BenchmarkDijkstraMatrix_1000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Matrix", 1000)
+}
+func BenchmarkDijkstraCSR_100(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "CSR", 100)
+}
+func BenchmarkDijkstraCSR_1000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "CSR", 1000)
+}
+func BenchmarkDijkstraCSR_10000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "CSR", 10000)
+}
+
+func BenchmarkPageRankMap_100(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Map", 100)
+}
+func BenchmarkPageRankMap_1000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Map", 1000)
+}
+func BenchmarkPageRankMap_10000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Map", 10000)
+}
+func BenchmarkPageRankMatrix_100(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Matrix", 100)
+}
+func BenchmarkPageRankMatrix_1000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Matrix", 1000)
+}
+func BenchmarkPageRankCSR_100(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "CSR", 100)
+}
+func BenchmarkPageRankCSR_1000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "CSR", 1000)
+}
+func BenchmarkPageRankCSR_10000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "CSR", 10000)
+}
+
+func BenchmarkInsertMap_100(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMap(100) }, 100)
+}
+func BenchmarkInsertMap_1000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMap(1000) }, 1000)
+}
+func BenchmarkInsertMap_10000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMap(10000) }, 10000)
+}
+func BenchmarkInsertMatrix_100(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMatrix(100) }, 100)
+}
+func BenchmarkInsertMatrix_1000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMatrix(1000) }, 1000)
+}
+
+func BenchmarkBFSArena_100(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Arena", 100)
+}
+func BenchmarkBFSArena_1000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Arena", 1000)
+}
+func BenchmarkBFSArena_10000(b *testing.B) {
+	benchmarkWorkload(b, BFS, "Arena", 10000)
+}
+func BenchmarkDijkstraArena_100(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Arena", 100)
+}
+func BenchmarkDijkstraArena_1000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Arena", 1000)
+}
+func BenchmarkDijkstraArena_10000(b *testing.B) {
+	benchmarkWorkload(b, Dijkstra, "Arena", 10000)
+}
+func BenchmarkPageRankArena_100(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Arena", 100)
+}
+func BenchmarkPageRankArena_1000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Arena", 1000)
+}
+func BenchmarkPageRankArena_10000(b *testing.B) {
+	benchmarkWorkload(b, PageRank, "Arena", 10000)
+}
+func BenchmarkInsertArena_100(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newArena(100) }, 100)
+}
+func BenchmarkInsertArena_1000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newArena(1000) }, 1000)
+}
+func BenchmarkInsertArena_10000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newArena(10000) }, 10000)
+}
+func BenchmarkInsertMapWithCapacity_100(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMapWithCapacity(100) }, 100)
+}
+func BenchmarkInsertMapWithCapacity_1000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMapWithCapacity(1000) }, 1000)
+}
+func BenchmarkInsertMapWithCapacity_10000(b *testing.B) {
+	benchmarkInsert(b, func() graph.EdgeSetter { return newMapWithCapacity(10000) }, 10000)
+}
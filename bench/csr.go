@@ -0,0 +1,167 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bench
+
+import (
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// CSR is an immutable directed graph stored in compressed sparse row form.
+// The out-edges of each node are held contiguously in a single slice, and the
+// in-edges in a second slice, so CSR has low memory overhead and good locality
+// for traversal.
+type CSR struct {
+	nodes   []graph.Node
+	indexOf map[int]int
+
+	// out holds the indices of the out-neighbors
+	// of node i in out[outStart[i]:outStart[i+1]],
+	// sorted by index, with the edge weights in the
+	// corresponding elements of weights.
+	outStart []int
+	out      []int
+	weights  []float64
+
+	// in holds the indices of the in-neighbors of
+	// node i in in[inStart[i]:inStart[i+1]].
+	inStart []int
+	in      []int
+}
+
+// NewCSR returns a CSR holding the nodes and weighted edges of g.
+func NewCSR(g graph.Directed) *CSR {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	c := &CSR{
+		nodes:    nodes,
+		indexOf:  make(map[int]int, len(nodes)),
+		outStart: make([]int, len(nodes)+1),
+		inStart:  make([]int, len(nodes)+1),
+	}
+	for i, n := range nodes {
+		c.indexOf[n.ID()] = i
+	}
+	indeg := make([]int, len(nodes))
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := c.indexOf[v.ID()]
+			c.out = append(c.out, j)
+			c.weights = append(c.weights, g.Edge(u, v).Weight())
+			indeg[j]++
+		}
+		c.outStart[i+1] = len(c.out)
+	}
+	for i, d := range indeg {
+		c.inStart[i+1] = c.inStart[i] + d
+	}
+	c.in = make([]int, len(c.out))
+	next := append([]int(nil), c.inStart[:len(nodes)]...)
+	for i := range nodes {
+		for _, j := range c.out[c.outStart[i]:c.outStart[i+1]] {
+			c.in[next[j]] = i
+			next[j]++
+		}
+	}
+	return c
+}
+
+// Has returns whether the node exists within the graph.
+func (c *CSR) Has(n graph.Node) bool {
+	_, ok := c.indexOf[n.ID()]
+	return ok
+}
+
+// Nodes returns all the nodes in the graph.
+func (c *CSR) Nodes() []graph.Node {
+	return append([]graph.Node(nil), c.nodes...)
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (c *CSR) From(n graph.Node) []graph.Node {
+	i, ok := c.indexOf[n.ID()]
+	if !ok {
+		return nil
+	}
+	return c.nodesAt(c.out[c.outStart[i]:c.outStart[i+1]])
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (c *CSR) To(n graph.Node) []graph.Node {
+	i, ok := c.indexOf[n.ID()]
+	if !ok {
+		return nil
+	}
+	return c.nodesAt(c.in[c.inStart[i]:c.inStart[i+1]])
+}
+
+func (c *CSR) nodesAt(idx []int) []graph.Node {
+	nodes := make([]graph.Node, len(idx))
+	for k, j := range idx {
+		nodes[k] = c.nodes[j]
+	}
+	return nodes
+}
+
+// edge returns the position of the edge from u to v in out, or -1 if there
+// is no such edge.
+func (c *CSR) edge(u, v graph.Node) int {
+	i, ok := c.indexOf[u.ID()]
+	if !ok {
+		return -1
+	}
+	j, ok := c.indexOf[v.ID()]
+	if !ok {
+		return -1
+	}
+	lo, hi := c.outStart[i], c.outStart[i+1]
+	k := lo + sort.SearchInts(c.out[lo:hi], j)
+	if k == hi || c.out[k] != j {
+		return -1
+	}
+	return k
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y
+// without considering direction.
+func (c *CSR) HasEdgeBetween(x, y graph.Node) bool {
+	return c.edge(x, y) >= 0 || c.edge(y, x) >= 0
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (c *CSR) HasEdgeFromTo(u, v graph.Node) bool {
+	return c.edge(u, v) >= 0
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (c *CSR) Edge(u, v graph.Node) graph.Edge {
+	k := c.edge(u, v)
+	if k < 0 {
+		return nil
+	}
+	return simple.Edge{F: c.nodes[c.indexOf[u.ID()]], T: c.nodes[c.out[k]], W: c.weights[k]}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node the weight is zero,
+// and if there is no joining edge the weight is +Inf. Weight returns true
+// if x and y are the same node or an edge exists between them.
+func (c *CSR) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return 0, true
+	}
+	k := c.edge(x, y)
+	if k < 0 {
+		return math.Inf(1), false
+	}
+	return c.weights[k], true
+}
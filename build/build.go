@@ -0,0 +1,181 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package build provides terse construction of simple graphs from compact
+// edge list descriptions, intended for tests and examples.
+//
+// A description is a list of statements separated by commas, semicolons or
+// newlines. Each statement is either a single node name, or a chain of node
+// names joined by edge operators with an optional weight suffix:
+//
+//  a
+//  a->b
+//  a->b->c:0.5
+//
+// Directed graphs use the -> operator and undirected graphs use the --
+// operator. The weight of an edge defaults to 1 and a weight suffix on a
+// chain applies to every edge in the chain. A later statement for an edge
+// replaces the weight of an earlier one.
+//
+// Node names that are integers are used as node IDs and the nodes are
+// simple.Node values. Other names are given the unused IDs returned by the
+// graph's NewNodeID method in order of first appearance, and the nodes are
+// Node values.
+package build
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Node is a named graph node.
+type Node struct {
+	id   int
+	name string
+}
+
+// ID returns the ID of the node.
+func (n Node) ID() int { return n.id }
+
+// DOTID returns the name of the node.
+func (n Node) DOTID() string { return n.name }
+
+// String returns the name of the node.
+func (n Node) String() string { return n.name }
+
+// Directed returns a directed graph constructed from the description s.
+// Directed will panic if s is not a valid directed graph description.
+func Directed(s string) *simple.DirectedGraph {
+	g, err := ParseDirected(s)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Undirected returns an undirected graph constructed from the description s.
+// Undirected will panic if s is not a valid undirected graph description.
+func Undirected(s string) *simple.UndirectedGraph {
+	g, err := ParseUndirected(s)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// ParseDirected returns a directed graph constructed from the description s.
+func ParseDirected(s string) (*simple.DirectedGraph, error) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	err := parse(g, s, "->", "--")
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ParseUndirected returns an undirected graph constructed from the
+// description s.
+func ParseUndirected(s string) (*simple.UndirectedGraph, error) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := parse(g, s, "--", "->")
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// statement is a parsed chain of nodes.
+type statement struct {
+	names  []string
+	weight float64
+}
+
+func parse(dst graph.Builder, s, op, wrong string) error {
+	stmts, err := statements(s, op, wrong)
+	if err != nil {
+		return err
+	}
+
+	// Add nodes with integer names first so that
+	// allocated IDs do not collide with them.
+	nodes := make(map[string]graph.Node)
+	for _, st := range stmts {
+		for _, name := range st.names {
+			if _, ok := nodes[name]; ok {
+				continue
+			}
+			id, err := strconv.Atoi(name)
+			if err != nil {
+				continue
+			}
+			n := simple.Node(id)
+			nodes[name] = n
+			dst.AddNode(n)
+		}
+	}
+	for _, st := range stmts {
+		for _, name := range st.names {
+			if _, ok := nodes[name]; ok {
+				continue
+			}
+			n := Node{id: dst.NewNodeID(), name: name}
+			nodes[name] = n
+			dst.AddNode(n)
+		}
+	}
+
+	for _, st := range stmts {
+		for i := 1; i < len(st.names); i++ {
+			u, v := nodes[st.names[i-1]], nodes[st.names[i]]
+			if u.ID() == v.ID() {
+				return fmt.Errorf("build: self edge on %s", st.names[i])
+			}
+			dst.SetEdge(simple.Edge{F: u, T: v, W: st.weight})
+		}
+	}
+	return nil
+}
+
+func statements(s, op, wrong string) ([]statement, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+	var stmts []statement
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		st := statement{weight: 1}
+		i := strings.LastIndex(f, ":")
+		if i >= 0 {
+			w, err := strconv.ParseFloat(strings.TrimSpace(f[i+1:]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("build: invalid weight in %q", f)
+			}
+			st.weight = w
+			f = f[:i]
+		}
+		if strings.Contains(f, wrong) {
+			return nil, fmt.Errorf("build: unexpected %s in %q", wrong, f)
+		}
+		for _, name := range strings.Split(f, op) {
+			name = strings.TrimSpace(name)
+			if name == "" || strings.ContainsAny(name, " \t\r:") {
+				return nil, fmt.Errorf("build: invalid node name %q in %q", name, f)
+			}
+			st.names = append(st.names, name)
+		}
+		if len(st.names) == 1 && i >= 0 {
+			return nil, fmt.Errorf("build: weight on node statement %q", f)
+		}
+		stmts = append(stmts, st)
+	}
+	return stmts, nil
+}
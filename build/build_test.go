@@ -0,0 +1,120 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package build
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+type edge struct {
+	from, to string
+	weight   float64
+}
+
+func edgesOf(g graph.Graph, directed bool) []edge {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []edge
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !directed && v.ID() < u.ID() {
+				continue
+			}
+			edges = append(edges, edge{from: name(u), to: name(v), weight: g.Edge(u, v).Weight()})
+		}
+	}
+	return edges
+}
+
+func name(n graph.Node) string {
+	if n, ok := n.(Node); ok {
+		return n.name
+	}
+	return "#" + strconv.Itoa(n.ID())
+}
+
+func TestDirected(t *testing.T) {
+	g := Directed("a->b:2, b->c; c->a:0.5\nd")
+	got := edgesOf(g, true)
+	want := []edge{
+		{from: "a", to: "b", weight: 2},
+		{from: "b", to: "c", weight: 1},
+		{from: "c", to: "a", weight: 0.5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected edges:\ngot: %v\nwant:%v", got, want)
+	}
+	if len(g.Nodes()) != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", len(g.Nodes()))
+	}
+	for i, name := range []string{"a", "b", "c", "d"} {
+		if n := g.Node(i); n == nil || n.(Node).DOTID() != name {
+			t.Errorf("unexpected node for ID %d: got:%v want:%s", i, n, name)
+		}
+	}
+}
+
+func TestUndirectedChain(t *testing.T) {
+	g := Undirected("a -- b -- c : 3, c--a")
+	got := edgesOf(g, false)
+	want := []edge{
+		{from: "a", to: "b", weight: 3},
+		{from: "a", to: "c", weight: 1},
+		{from: "b", to: "c", weight: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected edges:\ngot: %v\nwant:%v", got, want)
+	}
+}
+
+func TestIntegerNames(t *testing.T) {
+	// The named node x must not take
+	// the ID of the integer node 0.
+	g := Directed("x->0, 0->2")
+	got := edgesOf(g, true)
+	want := []edge{
+		{from: "#0", to: "#2", weight: 1},
+		{from: "x", to: "#0", weight: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected edges:\ngot: %v\nwant:%v", got, want)
+	}
+}
+
+var parseErrorTests = []struct {
+	directed bool
+	desc     string
+}{
+	{directed: true, desc: "a--b"},
+	{directed: false, desc: "a->b"},
+	{directed: true, desc: "a->b:x"},
+	{directed: true, desc: "a->"},
+	{directed: true, desc: "a b->c"},
+	{directed: true, desc: "a:1"},
+	{directed: true, desc: "a->a"},
+	{directed: false, desc: "1--1"},
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range parseErrorTests {
+		var err error
+		if test.directed {
+			_, err = ParseDirected(test.desc)
+		} else {
+			_, err = ParseUndirected(test.desc)
+		}
+		if err == nil {
+			t.Errorf("expected error for %q", test.desc)
+		}
+	}
+}
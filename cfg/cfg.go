@@ -0,0 +1,174 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cfg provides control flow graph analyses.
+//
+// The functions in this package consider only the nodes of a graph that
+// are reachable from the entry node. Results are ordered by node ID where
+// no other order is specified.
+package cfg
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// flowGraph is the part of a directed graph reachable from an entry node
+// with nodes numbered in reverse postorder of a depth first search.
+type flowGraph struct {
+	g graph.Directed
+
+	// nodes holds the reachable nodes in
+	// reverse postorder, so the entry is
+	// nodes[0], and rpo holds the index of
+	// each node into nodes, keyed by ID.
+	nodes []graph.Node
+	rpo   map[int]int
+
+	// idom holds the index of the immediate
+	// dominator of each node. The entry is
+	// its own immediate dominator.
+	idom []int
+}
+
+func newFlowGraph(entry graph.Node, g graph.Directed) *flowGraph {
+	f := &flowGraph{g: g, rpo: make(map[int]int)}
+	if !g.Has(entry) {
+		return f
+	}
+
+	// Visit successors in ID order so that
+	// results are deterministic.
+	type frame struct {
+		n    graph.Node
+		next []graph.Node
+	}
+	successors := func(n graph.Node) []graph.Node {
+		s := g.From(n)
+		sort.Sort(ordered.ByID(s))
+		return s
+	}
+	seen := map[int]bool{entry.ID(): true}
+	stack := []frame{{n: entry, next: successors(entry)}}
+	for len(stack) != 0 {
+		top := &stack[len(stack)-1]
+		if len(top.next) == 0 {
+			f.nodes = append(f.nodes, top.n)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		v := top.next[0]
+		top.next = top.next[1:]
+		if !seen[v.ID()] {
+			seen[v.ID()] = true
+			stack = append(stack, frame{n: v, next: successors(v)})
+		}
+	}
+	for i, j := 0, len(f.nodes)-1; i < j; i, j = i+1, j-1 {
+		f.nodes[i], f.nodes[j] = f.nodes[j], f.nodes[i]
+	}
+	for i, n := range f.nodes {
+		f.rpo[n.ID()] = i
+	}
+
+	f.dominators()
+	return f
+}
+
+// preds returns the indices of the reachable predecessors of the node at i.
+func (f *flowGraph) preds(i int) []int {
+	var p []int
+	for _, u := range f.g.To(f.nodes[i]) {
+		if j, ok := f.rpo[u.ID()]; ok {
+			p = append(p, j)
+		}
+	}
+	sort.Ints(p)
+	return p
+}
+
+// succs returns the indices of the successors of the node at i.
+func (f *flowGraph) succs(i int) []int {
+	var s []int
+	for _, v := range f.g.From(f.nodes[i]) {
+		s = append(s, f.rpo[v.ID()])
+	}
+	sort.Ints(s)
+	return s
+}
+
+// dominators computes the immediate dominators of the reachable nodes
+// using the iterative algorithm of Cooper, Harvey and Kennedy.
+func (f *flowGraph) dominators() {
+	f.idom = make([]int, len(f.nodes))
+	for i := range f.idom {
+		f.idom[i] = -1
+	}
+	f.idom[0] = 0
+	for changed := true; changed; {
+		changed = false
+		for i := 1; i < len(f.nodes); i++ {
+			d := -1
+			for _, p := range f.preds(i) {
+				if f.idom[p] < 0 {
+					continue
+				}
+				if d < 0 {
+					d = p
+					continue
+				}
+				for d != p {
+					for d > p {
+						d = f.idom[d]
+					}
+					for p > d {
+						p = f.idom[p]
+					}
+				}
+			}
+			if f.idom[i] != d {
+				f.idom[i] = d
+				changed = true
+			}
+		}
+	}
+}
+
+// dominates returns whether the node at a dominates the node at b.
+func (f *flowGraph) dominates(a, b int) bool {
+	for b > a {
+		b = f.idom[b]
+	}
+	return a == b
+}
+
+// IsReducible returns whether the part of g reachable from entry is a
+// reducible flow graph, that is, whether every cycle is entered only
+// through a node that dominates the other nodes of the cycle.
+func IsReducible(entry graph.Node, g graph.Directed) bool {
+	f := newFlowGraph(entry, g)
+
+	// A flow graph is reducible if and only if each
+	// retreating edge of a depth first search leads
+	// to a node dominating its source.
+	for i := range f.nodes {
+		for _, j := range f.succs(i) {
+			if j <= i && !f.dominates(j, i) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func sortedNodes(f *flowGraph, idx []int) []graph.Node {
+	nodes := make([]graph.Node, len(idx))
+	for i, j := range idx {
+		nodes[i] = f.nodes[j]
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return nodes
+}
@@ -0,0 +1,162 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+func directed(edges ...[2]int) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	return g
+}
+
+func ids(nodes []graph.Node) []int {
+	if nodes == nil {
+		return nil
+	}
+	s := make([]int, len(nodes))
+	for i, n := range nodes {
+		s[i] = n.ID()
+	}
+	return s
+}
+
+func TestLoopsNested(t *testing.T) {
+	g := directed(
+		[2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 2},
+		[2]int{3, 4}, [2]int{4, 1}, [2]int{4, 5},
+	)
+	f := Loops(simple.Node(0), g)
+	if len(f.Loops) != 2 || len(f.Roots) != 1 {
+		t.Fatalf("unexpected loop count: got:%d loops %d roots want:2 loops 1 root", len(f.Loops), len(f.Roots))
+	}
+	outer, inner := f.Loops[0], f.Loops[1]
+	if outer.Header.ID() != 1 || inner.Header.ID() != 2 {
+		t.Fatalf("unexpected headers: got:%d,%d want:1,2", outer.Header.ID(), inner.Header.ID())
+	}
+	if got, want := ids(outer.Nodes), []int{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected outer loop nodes: got:%v want:%v", got, want)
+	}
+	if got, want := ids(inner.Nodes), []int{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected inner loop nodes: got:%v want:%v", got, want)
+	}
+	if got, want := ids(outer.Latches), []int{4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected outer loop latches: got:%v want:%v", got, want)
+	}
+	if inner.Parent != outer || len(outer.Children) != 1 || outer.Children[0] != inner || f.Roots[0] != outer {
+		t.Errorf("unexpected loop nesting")
+	}
+	for id, want := range []int{0, 1, 2, 2, 1, 0} {
+		if got := f.Depth(simple.Node(id)); got != want {
+			t.Errorf("unexpected depth for %d: got:%d want:%d", id, got, want)
+		}
+	}
+	if f.Innermost(simple.Node(3)) != inner || f.Innermost(simple.Node(5)) != nil {
+		t.Errorf("unexpected innermost loops")
+	}
+	if !IsReducible(simple.Node(0), g) {
+		t.Error("expected reducible graph")
+	}
+}
+
+func TestIrreducible(t *testing.T) {
+	g := directed([2]int{0, 1}, [2]int{0, 2}, [2]int{1, 2}, [2]int{2, 1}, [2]int{2, 3})
+	if IsReducible(simple.Node(0), g) {
+		t.Error("expected irreducible graph")
+	}
+	if f := Loops(simple.Node(0), g); len(f.Loops) != 0 {
+		t.Errorf("unexpected natural loops in irreducible cycle: got:%d", len(f.Loops))
+	}
+	seq := DerivedSequence(simple.Node(0), g)
+	if last := seq[len(seq)-1]; len(last) == 1 {
+		t.Errorf("derived sequence of irreducible graph reached a single interval")
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	g := directed(
+		[2]int{0, 1}, [2]int{1, 2}, [2]int{2, 1}, [2]int{2, 3},
+		[2]int{3, 4}, [2]int{4, 3}, [2]int{4, 5},
+		// Unreachable from the entry.
+		[2]int{6, 1},
+	)
+	ivs := Intervals(simple.Node(0), g)
+	var got [][]int
+	for _, iv := range ivs {
+		if iv.Header.ID() != iv.Nodes[0].ID() {
+			t.Errorf("interval header is not first node: %v", ids(iv.Nodes))
+		}
+		got = append(got, ids(iv.Nodes))
+	}
+	want := [][]int{{0}, {1, 2}, {3, 4, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected intervals: got:%v want:%v", got, want)
+	}
+
+	seq := DerivedSequence(simple.Node(0), g)
+	if len(seq) != 2 || len(seq[1]) != 1 {
+		t.Errorf("unexpected derived sequence length: got:%d", len(seq))
+	}
+}
+
+func TestRandomFlowGraphs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		const n = 8
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 1; i < n; i++ {
+			g.SetEdge(simple.Edge{F: simple.Node(rnd.Intn(i)), T: simple.Node(i), W: 1})
+		}
+		for k := rnd.Intn(4); k > 0; k-- {
+			u, v := rnd.Intn(n), rnd.Intn(n)
+			if u != v {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+		entry := simple.Node(0)
+
+		seq := DerivedSequence(entry, g)
+		reducible := len(seq[len(seq)-1]) == 1
+		if got := IsReducible(entry, g); got != reducible {
+			t.Errorf("trial %d: reducibility mismatch: IsReducible:%t derived sequence:%t", trial, got, reducible)
+		}
+
+		var covered int
+		for _, iv := range seq[0] {
+			covered += len(iv.Nodes)
+		}
+		if covered != n {
+			t.Errorf("trial %d: intervals do not partition graph: got:%d nodes want:%d", trial, covered, n)
+		}
+
+		dom := path.Dominators(entry, g)
+		for _, l := range Loops(entry, g).Loops {
+			for _, m := range l.Nodes {
+				if !dom[m.ID()].Has(l.Header) {
+					t.Errorf("trial %d: loop header %d does not dominate %d", trial, l.Header.ID(), m.ID())
+				}
+			}
+			for _, latch := range l.Latches {
+				if !g.HasEdgeFromTo(latch, l.Header) {
+					t.Errorf("trial %d: latch %d has no edge to header %d", trial, latch.ID(), l.Header.ID())
+				}
+			}
+		}
+	}
+}
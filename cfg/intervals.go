@@ -0,0 +1,155 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Interval is a maximal single-entry subgraph of a control flow graph in
+// which every cycle passes through the header.
+type Interval struct {
+	// Header is the only node of
+	// the interval with predecessors
+	// outside the interval, unless
+	// it is the entry node.
+	Header graph.Node
+
+	// Nodes holds the nodes of the
+	// interval in the order they were
+	// added, starting with Header.
+	Nodes []graph.Node
+}
+
+// Intervals returns the Allen-Cocke interval partition of the part of g
+// reachable from entry. The first interval is headed by entry.
+func Intervals(entry graph.Node, g graph.Directed) []Interval {
+	f := newFlowGraph(entry, g)
+	if len(f.nodes) == 0 {
+		return nil
+	}
+
+	// of holds the interval index of each
+	// node, or -1 if it is unassigned.
+	of := make([]int, len(f.nodes))
+	for i := range of {
+		of[i] = -1
+	}
+	var intervals []Interval
+	headers := []int{0}
+	queued := map[int]bool{0: true}
+	for len(headers) != 0 {
+		h := headers[0]
+		headers = headers[1:]
+		k := len(intervals)
+		of[h] = k
+		members := []int{h}
+
+		// Add nodes whose predecessors are all in
+		// the interval until no more can be added.
+		for changed := true; changed; {
+			changed = false
+			for n := 1; n < len(f.nodes); n++ {
+				if of[n] >= 0 || queued[n] {
+					continue
+				}
+				preds := f.preds(n)
+				all := len(preds) != 0
+				for _, p := range preds {
+					if of[p] != k {
+						all = false
+						break
+					}
+				}
+				if all {
+					of[n] = k
+					members = append(members, n)
+					changed = true
+				}
+			}
+		}
+
+		// Nodes outside the interval reached from it
+		// head later intervals.
+		var next []int
+		for _, m := range members {
+			for _, s := range f.succs(m) {
+				if of[s] < 0 && !queued[s] {
+					queued[s] = true
+					next = append(next, s)
+				}
+			}
+		}
+		sort.Ints(next)
+		headers = append(headers, next...)
+
+		iv := Interval{Header: f.nodes[h], Nodes: make([]graph.Node, len(members))}
+		for i, m := range members {
+			iv.Nodes[i] = f.nodes[m]
+		}
+		intervals = append(intervals, iv)
+	}
+	return intervals
+}
+
+// DerivedSequence returns the derived sequence of interval partitions of the
+// part of g reachable from entry. The first element of the sequence is the
+// interval partition of g and each following element is the partition of the
+// graph obtained by collapsing each interval of the previous partition to its
+// header. The sequence ends when no further collapse is possible. The graph
+// is reducible if and only if the last partition has a single interval.
+func DerivedSequence(entry graph.Node, g graph.Directed) [][]Interval {
+	var seq [][]Interval
+	for {
+		ivs := Intervals(entry, g)
+		if ivs == nil {
+			return seq
+		}
+		seq = append(seq, ivs)
+		if len(ivs) == 1 {
+			return seq
+		}
+		n := 0
+		for _, iv := range ivs {
+			n += len(iv.Nodes)
+		}
+		if len(ivs) == n {
+			// Every interval is a single node,
+			// so the graph is its own limit.
+			return seq
+		}
+		g = limit(g, ivs)
+	}
+}
+
+// limit returns the graph obtained from g by collapsing each interval to
+// its header.
+func limit(g graph.Directed, ivs []Interval) graph.Directed {
+	header := make(map[int]graph.Node)
+	for _, iv := range ivs {
+		for _, n := range iv.Nodes {
+			header[n.ID()] = iv.Header
+		}
+	}
+	l := simple.NewDirectedGraph(0, 0)
+	for _, iv := range ivs {
+		l.AddNode(iv.Header)
+	}
+	for _, iv := range ivs {
+		for _, n := range iv.Nodes {
+			for _, v := range g.From(n) {
+				h, ok := header[v.ID()]
+				if !ok || h.ID() == iv.Header.ID() {
+					continue
+				}
+				l.SetEdge(simple.Edge{F: iv.Header, T: h, W: 1})
+			}
+		}
+	}
+	return l
+}
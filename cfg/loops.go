@@ -0,0 +1,192 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cfg
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Loop is a natural loop in a control flow graph. The natural loops of
+// all back edges to a header are merged into a single loop.
+type Loop struct {
+	// Header is the single entry node
+	// of the loop. It dominates all
+	// nodes of the loop.
+	Header graph.Node
+
+	// Latches holds the sources of the
+	// back edges to Header.
+	Latches []graph.Node
+
+	// Nodes holds all the nodes of the
+	// loop, including Header and the
+	// nodes of nested loops.
+	Nodes []graph.Node
+
+	// Parent is the innermost loop
+	// containing the loop, or nil for
+	// an outermost loop, and Children
+	// holds the loops immediately
+	// nested within the loop.
+	Parent   *Loop
+	Children []*Loop
+
+	// Depth is the nesting depth of the
+	// loop. Outermost loops have depth 1.
+	Depth int
+}
+
+// Forest is a loop nesting forest.
+type Forest struct {
+	// Roots holds the outermost loops
+	// ordered by header ID.
+	Roots []*Loop
+
+	// Loops holds all loops ordered
+	// by header ID.
+	Loops []*Loop
+
+	innermost map[int]*Loop
+}
+
+// Innermost returns the innermost loop containing n, or nil if n is not
+// in a loop.
+func (f *Forest) Innermost(n graph.Node) *Loop {
+	return f.innermost[n.ID()]
+}
+
+// Depth returns the loop nesting depth of n, which is zero if n is not in
+// a loop.
+func (f *Forest) Depth(n graph.Node) int {
+	l := f.innermost[n.ID()]
+	if l == nil {
+		return 0
+	}
+	return l.Depth
+}
+
+// Loops returns the loop nesting forest of the natural loops of the part of
+// g reachable from entry. A back edge is an edge whose target dominates its
+// source. Cycles of an irreducible graph that are not entered through a
+// dominating node have no back edge and so do not form natural loops.
+func Loops(entry graph.Node, g graph.Directed) *Forest {
+	f := newFlowGraph(entry, g)
+
+	forest := &Forest{innermost: make(map[int]*Loop)}
+	var members [][]int
+	for h := range f.nodes {
+		var latches []int
+		for _, p := range f.preds(h) {
+			if f.dominates(h, p) {
+				latches = append(latches, p)
+			}
+		}
+		if latches == nil {
+			continue
+		}
+
+		// Collect the nodes that reach a latch
+		// without passing through the header.
+		in := map[int]bool{h: true}
+		body := []int{h}
+		stack := append([]int(nil), latches...)
+		for len(stack) != 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if in[n] {
+				continue
+			}
+			in[n] = true
+			body = append(body, n)
+			stack = append(stack, f.preds(n)...)
+		}
+
+		forest.Loops = append(forest.Loops, &Loop{
+			Header:  f.nodes[h],
+			Latches: sortedNodes(f, latches),
+			Nodes:   sortedNodes(f, body),
+		})
+		members = append(members, body)
+	}
+
+	// Natural loops with distinct headers are either
+	// disjoint or nested, so the smallest loop
+	// containing a node is its innermost loop and
+	// the smallest other loop containing the header
+	// of a loop is its parent.
+	bySize := make([]int, len(forest.Loops))
+	for i := range bySize {
+		bySize[i] = i
+	}
+	sort.Stable(loopsBySize{idx: bySize, loops: forest.Loops})
+	contains := make([]map[int]bool, len(forest.Loops))
+	for i, body := range members {
+		contains[i] = make(map[int]bool, len(body))
+		for _, n := range body {
+			contains[i][n] = true
+		}
+	}
+	for k, i := range bySize {
+		l := forest.Loops[i]
+		for _, n := range members[i] {
+			id := f.nodes[n].ID()
+			if forest.innermost[id] == nil {
+				forest.innermost[id] = l
+			}
+		}
+		h := f.rpo[l.Header.ID()]
+		for _, j := range bySize[k+1:] {
+			if contains[j][h] {
+				l.Parent = forest.Loops[j]
+				break
+			}
+		}
+	}
+	for _, l := range forest.Loops {
+		if l.Parent == nil {
+			forest.Roots = append(forest.Roots, l)
+		} else {
+			l.Parent.Children = append(l.Parent.Children, l)
+		}
+	}
+	for _, l := range forest.Loops {
+		for p := l; p != nil; p = p.Parent {
+			l.Depth++
+		}
+		sortLoops(l.Children)
+	}
+	sortLoops(forest.Loops)
+	sortLoops(forest.Roots)
+	return forest
+}
+
+// loopsBySize sorts loop indices by increasing loop size.
+type loopsBySize struct {
+	idx   []int
+	loops []*Loop
+}
+
+func (l loopsBySize) Len() int { return len(l.idx) }
+func (l loopsBySize) Less(i, j int) bool {
+	return len(l.loops[l.idx[i]].Nodes) < len(l.loops[l.idx[j]].Nodes)
+}
+func (l loopsBySize) Swap(i, j int) { l.idx[i], l.idx[j] = l.idx[j], l.idx[i] }
+
+// sortLoops sorts loops by header ID.
+func sortLoops(loops []*Loop) {
+	headers := make([]graph.Node, len(loops))
+	byID := make(map[int]*Loop, len(loops))
+	for i, l := range loops {
+		headers[i] = l.Header
+		byID[l.Header.ID()] = l
+	}
+	sort.Sort(ordered.ByID(headers))
+	for i, h := range headers {
+		loops[i] = byID[h.ID()]
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/encoding/graphml"
+	graphjson "github.com/gonum/graph/encoding/json"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/render/html"
+	"github.com/gonum/graph/simple"
+)
+
+// builder is a graph that can be built by the decoders.
+type builder interface {
+	graph.Graph
+	graph.Builder
+}
+
+func newGraph(directed bool) builder {
+	if directed {
+		return simple.NewDirectedGraph(0, math.Inf(1))
+	}
+	return simple.NewUndirectedGraph(0, math.Inf(1))
+}
+
+// decode reads a graph in the given format from r. If format is empty
+// it is determined from the file name.
+func decode(r io.Reader, format, name string, directed bool) (graph.Graph, error) {
+	if format == "" {
+		switch {
+		case strings.HasSuffix(name, ".json"):
+			format = "json"
+		case strings.HasSuffix(name, ".dot"), strings.HasSuffix(name, ".gv"):
+			format = "dot"
+		default:
+			format = "edgelist"
+		}
+	}
+	switch format {
+	case "edgelist":
+		return decodeEdgeList(r, directed)
+	case "json":
+		return decodeJSON(r)
+	case "dot":
+		return decodeDOT(r)
+	default:
+		return nil, fmt.Errorf("unknown input encoding %q", format)
+	}
+}
+
+func decodeEdgeList(r io.Reader, directed bool) (graph.Graph, error) {
+	g := newGraph(directed)
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		if len(f) > 3 {
+			return nil, fmt.Errorf("line %d: too many fields", line)
+		}
+		var id [2]int
+		if len(f) == 1 {
+			n, err := strconv.Atoi(f[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if !g.Has(simple.Node(n)) {
+				g.AddNode(simple.Node(n))
+			}
+			continue
+		}
+		for i := range id {
+			var err error
+			id[i], err = strconv.Atoi(f[i])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		w := 1.0
+		if len(f) == 3 {
+			var err error
+			w, err = strconv.ParseFloat(f[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		if id[0] == id[1] {
+			return nil, fmt.Errorf("line %d: self edge", line)
+		}
+		g.SetEdge(simple.Edge{F: simple.Node(id[0]), T: simple.Node(id[1]), W: w})
+	}
+	return g, sc.Err()
+}
+
+// node is a graph node that holds attributes.
+type node struct {
+	id    int
+	attrs []graph.Attribute
+}
+
+func (n *node) ID() int                       { return n.id }
+func (n *node) Attributes() []graph.Attribute { return n.attrs }
+func (n *node) SetAttribute(a graph.Attribute) error {
+	n.attrs = setAttribute(n.attrs, a)
+	return nil
+}
+
+// edge is a graph edge that holds attributes.
+type edge struct {
+	f, t  graph.Node
+	w     float64
+	attrs []graph.Attribute
+}
+
+func (e *edge) From() graph.Node              { return e.f }
+func (e *edge) To() graph.Node                { return e.t }
+func (e *edge) Weight() float64               { return e.w }
+func (e *edge) Attributes() []graph.Attribute { return e.attrs }
+func (e *edge) SetAttribute(a graph.Attribute) error {
+	e.attrs = setAttribute(e.attrs, a)
+	return nil
+}
+func (e *edge) SetWeight(w float64) { e.w = w }
+
+// setAttribute sets a in attrs, replacing any attribute with the same key.
+func setAttribute(attrs []graph.Attribute, a graph.Attribute) []graph.Attribute {
+	for i, o := range attrs {
+		if o.Key == a.Key {
+			attrs[i] = a
+			return attrs
+		}
+	}
+	return append(attrs, a)
+}
+
+func decodeJSON(r io.Reader) (graph.Graph, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var header struct {
+		Directed bool `json:"directed"`
+	}
+	err = json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, err
+	}
+	g := newGraph(header.Directed)
+	err = graphjson.Unmarshal(data, g)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// dotWeight is the DOT edge attribute holding edge weights.
+var dotWeight = &dot.WeightAttribute{Key: "weight"}
+
+// decodeDOT reads a single DOT graph from r. Nodes with non-negative integer
+// DOT IDs keep them as node IDs. Other nodes are given unused IDs and, unless
+// they have one, a label attribute holding their DOT ID. Edge weights are read
+// from the weight attribute and default to 1.
+func decodeDOT(r io.Reader) (graph.Graph, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := dot.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Graphs) != 1 {
+		return nil, fmt.Errorf("found %d graphs, expected one", len(f.Graphs))
+	}
+	directed := f.Graphs[0].Directed
+	var src builder
+	if directed {
+		src = dotDirected{simple.NewDirectedGraph(0, math.Inf(1))}
+	} else {
+		src = dotUndirected{simple.NewUndirectedGraph(0, math.Inf(1))}
+	}
+	reg := &dotRegistry{g: src, nodes: make(map[string]graph.Node), keys: make(map[int]string)}
+	_, err = dot.UnmarshalWith(data, src.(dot.Builder), dot.DecodeOptions{Weight: dotWeight, Registry: reg})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := src.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	ids := make(map[int]int, len(nodes))
+	used := make(map[int]bool, len(nodes))
+	for _, n := range nodes {
+		id, err := strconv.Atoi(reg.keys[n.ID()])
+		if err == nil && id >= 0 {
+			ids[n.ID()] = id
+			used[id] = true
+		}
+	}
+	g := newGraph(directed)
+	remapped := make(map[int]graph.Node, len(nodes))
+	next := 0
+	for _, n := range nodes {
+		n := n.(*node)
+		id, ok := ids[n.id]
+		if !ok {
+			for used[next] {
+				next++
+			}
+			id = next
+			ids[n.id] = id
+			used[id] = true
+			if !hasAttribute(n.attrs, "label") {
+				n.attrs = append(n.attrs, graph.Attribute{Key: "label", Value: reg.keys[n.id]})
+			}
+		}
+		remapped[n.id] = &node{id: id, attrs: n.attrs}
+		g.AddNode(remapped[n.id])
+	}
+	for _, u := range nodes {
+		for _, v := range src.From(u) {
+			if !directed && v.ID() < u.ID() {
+				continue
+			}
+			e := src.Edge(u, v).(*edge)
+			var attrs []graph.Attribute
+			for _, a := range e.attrs {
+				if a.Key != dotWeight.Key {
+					attrs = append(attrs, a)
+				}
+			}
+			g.SetEdge(&edge{f: remapped[u.ID()], t: remapped[v.ID()], w: e.w, attrs: attrs})
+		}
+	}
+	return g, nil
+}
+
+// hasAttribute returns whether attrs holds an attribute with the given key.
+func hasAttribute(attrs []graph.Attribute, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// dotRegistry creates the nodes of a decoded DOT graph, recording
+// the DOT ID of each node.
+type dotRegistry struct {
+	g     builder
+	nodes map[string]graph.Node
+	keys  map[int]string
+}
+
+func (r *dotRegistry) GetOrCreate(key string) graph.Node {
+	if n, ok := r.nodes[key]; ok {
+		return n
+	}
+	n := &node{id: r.g.NewNodeID()}
+	r.g.AddNode(n)
+	r.nodes[key] = n
+	r.keys[n.id] = key
+	return n
+}
+
+// dotDirected and dotUndirected are the DOT decoding
+// destinations for directed and undirected graphs.
+type dotDirected struct{ *simple.DirectedGraph }
+
+func (g dotDirected) NewNode() graph.Node { return &node{id: g.NewNodeID()} }
+func (g dotDirected) NewEdge(from, to graph.Node) graph.Edge {
+	return &edge{f: from, t: to, w: 1}
+}
+
+type dotUndirected struct{ *simple.UndirectedGraph }
+
+func (g dotUndirected) NewNode() graph.Node { return &node{id: g.NewNodeID()} }
+func (g dotUndirected) NewEdge(from, to graph.Node) graph.Edge {
+	return &edge{f: from, t: to, w: 1}
+}
+
+// encode writes g to w in the given format.
+func encode(w io.Writer, g graph.Graph, format string) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch format {
+	case "dot":
+		b, err = dot.Marshal(g, "", "", "\t", false)
+		b = append(b, '\n')
+	case "graphml":
+		b, err = graphml.Marshal(g)
+	case "edgelist":
+		b = marshalEdgeList(g)
+	case "json":
+		b, err = graphjson.Marshal(g)
+	case "html":
+		b, err = html.Marshal(g, "graph")
+	case "svg":
+		b = marshalSVG(g)
+	default:
+		return fmt.Errorf("unknown output encoding %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// edges returns the nodes and edges of g in a deterministic order.
+// Undirected edges are returned once.
+func edges(g graph.Graph) ([]graph.Node, []graph.Edge) {
+	_, directed := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []graph.Edge
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if directed || u.ID() < v.ID() {
+				edges = append(edges, g.Edge(u, v))
+			}
+		}
+	}
+	return nodes, edges
+}
+
+func marshalEdgeList(g graph.Graph) []byte {
+	nodes, edges := edges(g)
+	var b []byte
+	linked := make(map[int]bool)
+	for _, e := range edges {
+		linked[e.From().ID()] = true
+		linked[e.To().ID()] = true
+		b = append(b, fmt.Sprintf("%d %d %v\n", e.From().ID(), e.To().ID(), e.Weight())...)
+	}
+	for _, n := range nodes {
+		if !linked[n.ID()] {
+			b = append(b, fmt.Sprintf("%d\n", n.ID())...)
+		}
+	}
+	return b
+}
+
+// marshalSVG renders g as an SVG image using a force-directed layout.
+func marshalSVG(g graph.Graph) []byte {
+	const (
+		scale  = 60.0
+		margin = 20.0
+		radius = 10.0
+	)
+	_, directed := g.(graph.Directed)
+	nodes, edges := edges(g)
+	pos := layout.ForceDirected{Src: rand.New(rand.NewSource(1))}.Layout(g)
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	if len(pos) == 0 {
+		minX, maxX, minY, maxY = 0, 0, 0, 0
+	}
+	at := func(id int) (x, y float64) {
+		p := pos[id]
+		return (p.X-minX)*scale + margin, (p.Y-minY)*scale + margin
+	}
+	width := (maxX-minX)*scale + 2*margin
+	height := (maxY-minY)*scale + 2*margin
+
+	var b []byte
+	b = append(b, fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f">`+"\n", width, height)...)
+	marker := ""
+	if directed {
+		b = append(b, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>`+"\n"...)
+		marker = ` marker-end="url(#arrow)"`
+	}
+	for _, e := range edges {
+		x1, y1 := at(e.From().ID())
+		x2, y2 := at(e.To().ID())
+		if d := math.Hypot(x2-x1, y2-y1); d > 2*radius {
+			// Stop edges at the node boundary.
+			x1, y1 = x1+(x2-x1)*radius/d, y1+(y2-y1)*radius/d
+			x2, y2 = x2-(x2-x1)*radius/(d-radius), y2-(y2-y1)*radius/(d-radius)
+		}
+		b = append(b, fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"%s/>`+"\n", x1, y1, x2, y2, marker)...)
+	}
+	for _, n := range nodes {
+		x, y := at(n.ID())
+		b = append(b, fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="%.0f" fill="white" stroke="black"/>`+"\n", x, y, radius)...)
+		b = append(b, fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="10" text-anchor="middle" dominant-baseline="central">%d</text>`+"\n", x, y, n.ID())...)
+	}
+	return append(b, "</svg>\n"...)
+}
@@ -0,0 +1,240 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The graph command inspects and converts graphs.
+//
+// Usage:
+//  graph <command> [flags] [file]
+//
+// The commands are:
+//  convert     convert a graph to another encoding
+//  summary     print summary statistics
+//  components  print the connected components
+//  path        print a shortest path between two nodes
+//  pagerank    print the PageRank of each node
+//
+// Graphs are read from the named file or from standard input if no file is
+// given. The supported input encodings are an edge list of lines holding two
+// node IDs and an optional weight, with blank lines and lines starting with #
+// ignored, JSON objects of the form
+//  {"directed": true, "nodes": [0, 1], "edges": [{"from": 0, "to": 1, "weight": 1}]}
+// and DOT graphs with edge weights held in the weight attribute. DOT nodes
+// with non-negative integer IDs keep them; other nodes are numbered from the
+// lowest unused ID and labelled with their DOT ID. The input encoding is taken
+// from the -in flag, or is JSON if the file name ends in .json, DOT if it ends
+// in .dot or .gv and an edge list otherwise.
+//
+// The convert command writes to standard output in the encoding given by its
+// -out flag; one of dot, graphml, edgelist, json, html or svg.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/network"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, out io.Writer) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "convert", usage: "convert a graph to another encoding", run: convert},
+		{name: "summary", usage: "print summary statistics", run: summary},
+		{name: "components", usage: "print the connected components", run: components},
+		{name: "path", usage: "print a shortest path between two nodes", run: shortest},
+		{name: "pagerank", usage: "print the PageRank of each node", run: pagerank},
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			err := c.run(os.Args[2:], os.Stdout)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "graph %s: %v\n", c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: graph <command> [flags] [file]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", c.name, c.usage)
+	}
+}
+
+// input holds the flags common to all commands.
+type input struct {
+	format   string
+	directed bool
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *input) {
+	var in input
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&in.format, "in", "", "input encoding: edgelist, json or dot")
+	fs.BoolVar(&in.directed, "directed", false, "read edge lists as directed graphs")
+	return fs, &in
+}
+
+// read parses the flags in args and returns the graph they describe.
+func read(fs *flag.FlagSet, in *input, args []string) (graph.Graph, error) {
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		r    io.Reader = os.Stdin
+		name string
+	)
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		name = fs.Arg(0)
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	default:
+		return nil, fmt.Errorf("too many arguments")
+	}
+	return decode(r, in.format, name, in.directed)
+}
+
+func convert(args []string, out io.Writer) error {
+	fs, in := newFlagSet("convert")
+	format := fs.String("out", "dot", "output encoding: dot, graphml, edgelist, json, html or svg")
+	g, err := read(fs, in, args)
+	if err != nil {
+		return err
+	}
+	return encode(out, g, *format)
+}
+
+func summary(args []string, out io.Writer) error {
+	fs, in := newFlagSet("summary")
+	g, err := read(fs, in, args)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(out, network.Summary(g))
+	return err
+}
+
+func components(args []string, out io.Writer) error {
+	fs, in := newFlagSet("components")
+	strong := fs.Bool("strong", false, "print strongly connected components of directed graphs")
+	g, err := read(fs, in, args)
+	if err != nil {
+		return err
+	}
+	var cc [][]graph.Node
+	switch g := g.(type) {
+	case graph.Directed:
+		if *strong {
+			cc = topo.TarjanSCC(g)
+		} else {
+			cc = topo.ConnectedComponents(graph.Undirect{G: g})
+		}
+	case graph.Undirected:
+		cc = topo.ConnectedComponents(g)
+	}
+	for _, c := range cc {
+		sort.Sort(ordered.ByID(c))
+	}
+	sort.Sort(byFirstID(cc))
+	for _, c := range cc {
+		for i, n := range c {
+			if i != 0 {
+				fmt.Fprint(out, " ")
+			}
+			fmt.Fprint(out, n.ID())
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
+
+// byFirstID sorts sorted components by their first node ID.
+type byFirstID [][]graph.Node
+
+func (c byFirstID) Len() int           { return len(c) }
+func (c byFirstID) Less(i, j int) bool { return c[i][0].ID() < c[j][0].ID() }
+func (c byFirstID) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+func shortest(args []string, out io.Writer) error {
+	fs, in := newFlagSet("path")
+	from := fs.Int("from", 0, "path start node ID")
+	to := fs.Int("to", 0, "path end node ID")
+	g, err := read(fs, in, args)
+	if err != nil {
+		return err
+	}
+	u, v := simple.Node(*from), simple.Node(*to)
+	if !g.Has(u) || !g.Has(v) {
+		return fmt.Errorf("node not in graph")
+	}
+	p, w := path.DijkstraFrom(u, g).To(v)
+	if math.IsInf(w, 1) {
+		return fmt.Errorf("no path from %d to %d", *from, *to)
+	}
+	for i, n := range p {
+		if i != 0 {
+			fmt.Fprint(out, " ")
+		}
+		fmt.Fprint(out, n.ID())
+	}
+	_, err = fmt.Fprintf(out, "\nweight %v\n", w)
+	return err
+}
+
+func pagerank(args []string, out io.Writer) error {
+	fs, in := newFlagSet("pagerank")
+	damp := fs.Float64("damp", 0.85, "damping factor")
+	tol := fs.Float64("tol", 1e-8, "convergence tolerance")
+	g, err := read(fs, in, args)
+	if err != nil {
+		return err
+	}
+	d, ok := g.(graph.Directed)
+	if !ok {
+		dg := simple.NewDirectedGraph(0, math.Inf(1))
+		graph.Copy(dg, g)
+		d = dg
+	}
+	rank := network.PageRank(d, *damp, *tol)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		fmt.Fprintf(out, "%d\t%.6g\n", n.ID(), rank[n.ID()])
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const edgeList = `# A triangle with a tail.
+0 1 1
+1 2 1
+2 0 1
+2 3 4
+5
+`
+
+func writeTemp(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	err = ioutil.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("failed to write temporary file: %v", err)
+	}
+	return path
+}
+
+func TestCommands(t *testing.T) {
+	file := writeTemp(t, "g.txt", edgeList)
+	defer os.RemoveAll(filepath.Dir(file))
+
+	for _, test := range []struct {
+		run  func([]string, *bytes.Buffer) error
+		args []string
+		want []string
+	}{
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return components(a, b) },
+			args: []string{file},
+			want: []string{"0 1 2 3\n5\n"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return components(a, b) },
+			args: []string{"-directed", "-strong", file},
+			want: []string{"0 1 2\n3\n5\n"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return shortest(a, b) },
+			args: []string{"-from", "3", "-to", "1", file},
+			want: []string{"3 2 1\nweight 5\n"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return summary(a, b) },
+			args: []string{file},
+			want: []string{"5", "4"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return pagerank(a, b) },
+			args: []string{"-directed", file},
+			want: []string{"0\t", "5\t"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return convert(a, b) },
+			args: []string{"-out", "dot", file},
+			want: []string{"graph {", "2 -- 3"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return convert(a, b) },
+			args: []string{"-out", "graphml", "-directed", file},
+			want: []string{`edgedefault="directed"`, `<edge source="n2" target="n3">`, `>4</data>`},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return convert(a, b) },
+			args: []string{"-out", "svg", "-directed", file},
+			want: []string{"<svg", "marker-end", ">5</text>"},
+		},
+		{
+			run:  func(a []string, b *bytes.Buffer) error { return convert(a, b) },
+			args: []string{"-out", "html", file},
+			want: []string{"<html"},
+		},
+	} {
+		var buf bytes.Buffer
+		err := test.run(test.args, &buf)
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", test.args, err)
+			continue
+		}
+		for _, w := range test.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("missing %q in output for %v:\n%s", w, test.args, buf.String())
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		g, err := decodeEdgeList(strings.NewReader(edgeList), directed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, format := range []string{"edgelist", "json"} {
+			var buf bytes.Buffer
+			err = encode(&buf, g, format)
+			if err != nil {
+				t.Fatalf("unexpected error encoding %s: %v", format, err)
+			}
+			got, err := decode(bytes.NewReader(buf.Bytes()), format, "", directed)
+			if err != nil {
+				t.Fatalf("unexpected error decoding %s: %v", format, err)
+			}
+			var again bytes.Buffer
+			encode(&again, got, format)
+			if again.String() != buf.String() {
+				t.Errorf("%s round trip mismatch for directed=%t:\ngot:\n%s\nwant:\n%s", format, directed, again.String(), buf.String())
+			}
+		}
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	const src = `{
+	"directed": true,
+	"nodes": [0, 1],
+	"node_attributes": {"0": {"color": "red", "label": "a < b"}},
+	"edges": [{"from": 0, "to": 1, "weight": 2, "attributes": {"style": "bold"}}]
+}`
+	g, err := decode(strings.NewReader(src), "json", "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		format string
+		want   []string
+	}{
+		{
+			format: "json",
+			want: []string{
+				`"node_attributes": {`, `"color": "red"`, `"label": "a \u003c b"`,
+				`"attributes": {`, `"style": "bold"`,
+			},
+		},
+		{
+			format: "graphml",
+			want: []string{
+				`<key id="d1" for="node" attr.name="color" attr.type="string"></key>`,
+				`<data key="d2">a &lt; b</data>`,
+				`<key id="d3" for="edge" attr.name="style" attr.type="string"></key>`,
+				`<data key="d3">bold</data>`,
+			},
+		},
+		{
+			format: "dot",
+			want:   []string{`color=red`, `label="a < b"`, `0 -> 1 [style=bold]`},
+		},
+	} {
+		var buf bytes.Buffer
+		err = encode(&buf, g, test.format)
+		if err != nil {
+			t.Fatalf("unexpected error encoding %s: %v", test.format, err)
+		}
+		for _, w := range test.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("missing %q in %s output:\n%s", w, test.format, buf.String())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encode(&buf, g, "json")
+	got, err := decode(bytes.NewReader(buf.Bytes()), "json", "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var again bytes.Buffer
+	encode(&again, got, "json")
+	if again.String() != buf.String() {
+		t.Errorf("json round trip mismatch:\ngot:\n%s\nwant:\n%s", again.String(), buf.String())
+	}
+}
+
+const dotGraph = `digraph {
+	1 -> 0 [weight=2];
+	0 -> a [color=red];
+	a [shape=box];
+	b;
+}
+`
+
+func TestConvertDOT(t *testing.T) {
+	gv := writeTemp(t, "g.gv", dotGraph)
+	defer os.RemoveAll(filepath.Dir(gv))
+	txt := writeTemp(t, "g.txt", dotGraph)
+	defer os.RemoveAll(filepath.Dir(txt))
+
+	for _, test := range []struct {
+		args []string
+		want []string
+	}{
+		{
+			args: []string{"-out", "edgelist", gv},
+			want: []string{"0 2 1\n1 0 2\n3\n"},
+		},
+		{
+			args: []string{"-in", "dot", "-out", "edgelist", txt},
+			want: []string{"0 2 1\n1 0 2\n3\n"},
+		},
+		{
+			args: []string{"-out", "json", gv},
+			want: []string{
+				`"directed": true`,
+				`"2": {`, `"label": "a"`, `"shape": "box"`,
+				`"3": {`, `"label": "b"`,
+				`"color": "red"`,
+			},
+		},
+		{
+			args: []string{"-out", "dot", gv},
+			want: []string{"1 -> 0", "0 -> 2 [color=red]", "label=a"},
+		},
+	} {
+		var buf bytes.Buffer
+		err := convert(test.args, &buf)
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", test.args, err)
+			continue
+		}
+		for _, w := range test.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("missing %q in output for %v:\n%s", w, test.args, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "weight=") || strings.Contains(buf.String(), `"weight": "`) {
+			t.Errorf("unexpected weight attribute in output for %v:\n%s", test.args, buf.String())
+		}
+	}
+
+	_, err := decode(strings.NewReader("graph { a -- a; }"), "dot", "", false)
+	if err == nil {
+		t.Error("expected error for DOT self edge")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, in := range []string{
+		"0 x\n",
+		"0 1 2 3\n",
+		"1 1\n",
+	} {
+		_, err := decodeEdgeList(strings.NewReader(in), false)
+		if err == nil {
+			t.Errorf("expected error for %q", in)
+		}
+	}
+}
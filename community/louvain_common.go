@@ -81,11 +81,29 @@ type ReducedGraph interface {
 // graph.Undirect may be used as a shim to allow modularization of
 // directed graphs with the undirected modularity function.
 func Modularize(g graph.Graph, resolution float64, src *rand.Rand) ReducedGraph {
+	r, _ := modularize(g, resolution, src, nil)
+	return r
+}
+
+// ModularizeWith returns the hierarchical modularization of g with the same
+// semantics as Modularize, using the WithResolution, WithRandom and WithContext
+// options. The default resolution is 1. If the context is done before the
+// modularization completes, the modularization reached so far is returned with
+// the context's error.
+func ModularizeWith(g graph.Graph, opts ...Option) (ReducedGraph, error) {
+	o := options{resolution: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return modularize(g, o.resolution, o.src, o.ctx)
+}
+
+func modularize(g graph.Graph, resolution float64, src *rand.Rand, ctx Context) (ReducedGraph, error) {
 	switch g := g.(type) {
 	case graph.Undirected:
-		return louvainUndirected(g, resolution, src)
+		return louvainUndirected(g, resolution, src, ctx)
 	case graph.Directed:
-		return louvainDirected(g, resolution, src)
+		return louvainDirected(g, resolution, src, ctx)
 	default:
 		panic(fmt.Sprintf("community: invalid graph type: %T", g))
 	}
@@ -71,9 +71,10 @@ func qDirected(g graph.Directed, communities [][]graph.Node, resolution float64)
 
 // louvainDirected returns the hierarchical modularization of g at the given
 // resolution using the Louvain algorithm. If src is nil, rand.Intn is used
-// as the random generator. louvainDirected will panic if g has any edge with negative
-// edge weight.
-func louvainDirected(g graph.Directed, resolution float64, src *rand.Rand) ReducedGraph {
+// as the random generator. If ctx is done before the modularization completes
+// the partial result is returned with the context's error. louvainDirected will
+// panic if g has any edge with negative edge weight.
+func louvainDirected(g graph.Directed, resolution float64, src *rand.Rand, ctx Context) (ReducedGraph, error) {
 	// See louvain.tex for a detailed description
 	// of the algorithm used here.
 
@@ -83,12 +84,15 @@ func louvainDirected(g graph.Directed, resolution float64, src *rand.Rand) Reduc
 		rnd = src.Intn
 	}
 	for {
+		if err := canceled(ctx); err != nil {
+			return c, err
+		}
 		l := newDirectedLocalMover(c, c.communities, resolution)
 		if l == nil {
-			return c
+			return c, nil
 		}
 		if done := l.localMovingHeuristic(rnd); done {
-			return c
+			return c, nil
 		}
 		c = reduceDirected(c, l.communities)
 	}
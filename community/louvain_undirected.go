@@ -69,11 +69,12 @@ func qUndirected(g graph.Undirected, communities [][]graph.Node, resolution floa
 
 // louvainUndirected returns the hierarchical modularization of g at the given
 // resolution using the Louvain algorithm. If src is nil, rand.Intn is used as
-// the random generator. louvainUndirected will panic if g has any edge with negative edge
-// weight.
+// the random generator. If ctx is done before the modularization completes the
+// partial result is returned with the context's error. louvainUndirected will
+// panic if g has any edge with negative edge weight.
 //
 // graph.Undirect may be used as a shim to allow modularization of directed graphs.
-func louvainUndirected(g graph.Undirected, resolution float64, src *rand.Rand) *ReducedUndirected {
+func louvainUndirected(g graph.Undirected, resolution float64, src *rand.Rand, ctx Context) (*ReducedUndirected, error) {
 	// See louvain.tex for a detailed description
 	// of the algorithm used here.
 
@@ -83,12 +84,15 @@ func louvainUndirected(g graph.Undirected, resolution float64, src *rand.Rand) *
 		rnd = src.Intn
 	}
 	for {
+		if err := canceled(ctx); err != nil {
+			return c, err
+		}
 		l := newUndirectedLocalMover(c, c.communities, resolution)
 		if l == nil {
-			return c
+			return c, nil
 		}
 		if done := l.localMovingHeuristic(rnd); done {
-			return c
+			return c, nil
 		}
 		c = reduceUndirected(c, l.communities)
 	}
@@ -0,0 +1,56 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import "math/rand"
+
+// Option is a functional option for the community detection functions
+// that accept options. Each function documents the options it uses and
+// ignores the others.
+type Option func(*options)
+
+type options struct {
+	resolution float64
+	src        *rand.Rand
+	ctx        Context
+}
+
+// Context is the part of context.Context used to cancel community detection.
+// It is satisfied by any context.Context.
+type Context interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
+// WithResolution returns an Option specifying the modularity resolution, γ
+// as defined in Reichardt and Bornholdt doi:10.1103/PhysRevE.74.016110.
+func WithResolution(resolution float64) Option {
+	return func(o *options) { o.resolution = resolution }
+}
+
+// WithRandom returns an Option specifying the random source. If src is nil,
+// rand.Intn is used.
+func WithRandom(src *rand.Rand) Option {
+	return func(o *options) { o.src = src }
+}
+
+// WithContext returns an Option specifying a context that cancels the
+// detection when it is done.
+func WithContext(ctx Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// canceled returns the error of ctx if it is done.
+func canceled(ctx Context) error {
+	if ctx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
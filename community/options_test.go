@@ -0,0 +1,57 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+// doneContext is a Context that is already done.
+type doneContext struct{}
+
+var errDone = errors.New("done")
+
+func (doneContext) Done() <-chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}
+func (doneContext) Err() error { return errDone }
+
+func TestModularizeWith(t *testing.T) {
+	for _, test := range communityUndirectedQTests {
+		g := simple.NewUndirectedGraph(0, 0)
+		for u, e := range test.g {
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+
+		want := Modularize(g, 0.5, rand.New(rand.NewSource(1)))
+		got, err := ModularizeWith(g, WithResolution(0.5), WithRandom(rand.New(rand.NewSource(1))))
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(got.Communities(), want.Communities()) {
+			t.Errorf("unexpected communities for %q:\ngot: %v\nwant:%v", test.name, got.Communities(), want.Communities())
+		}
+
+		r, err := ModularizeWith(g, WithContext(doneContext{}))
+		if err != errDone {
+			t.Errorf("unexpected error for %q with done context: got:%v want:%v", test.name, err, errDone)
+		}
+		if r == nil {
+			t.Errorf("expected partial result for %q with done context", test.name)
+		}
+	}
+}
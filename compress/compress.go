@@ -0,0 +1,249 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package compress provides compact read-only graph representations for
+// large static graphs.
+//
+// Adjacency lists are held sorted and gap encoded as variable length integers,
+// as described by Boldi and Vigna, doi:10.1145/988672.988752, so that graphs
+// with locality in their node IDs, such as web link graphs ordered by URL, are
+// stored in a few bytes per edge. Edge weights may be quantized to further
+// reduce the storage required.
+package compress
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Options holds options for the construction of compressed graphs.
+type Options struct {
+	// QuantizationBits is the number
+	// of bits used to hold each edge
+	// weight. Weights are quantized
+	// to evenly spaced levels between
+	// the least and greatest weights
+	// in the graph. If it is zero,
+	// weights are held exactly. It
+	// must be no greater than 32.
+	QuantizationBits uint
+}
+
+// adjacency holds gap encoded adjacency lists.
+type adjacency struct {
+	// start holds the offset of the
+	// list of node i in data and base
+	// holds the ordinal of its first
+	// edge. Both have an element for
+	// each node and a final element.
+	start []int
+	base  []int
+	data  []byte
+}
+
+// newAdjacency returns the adjacency lists of the given nodes where adj
+// returns the sorted neighbor indices of the node with index i.
+func newAdjacency(n int, adj func(i int) []int) adjacency {
+	a := adjacency{
+		start: make([]int, n+1),
+		base:  make([]int, n+1),
+	}
+	var buf [binary.MaxVarintLen64]byte
+	for i := 0; i < n; i++ {
+		prev := i
+		for k, j := range adj(i) {
+			var v uint64
+			if k == 0 {
+				// The first neighbor is encoded relative
+				// to the node itself and so may be negative.
+				v = uint64(j-prev)<<1 ^ uint64((j-prev)>>63)
+			} else {
+				v = uint64(j - prev - 1)
+			}
+			a.data = append(a.data, buf[:binary.PutUvarint(buf[:], v)]...)
+			prev = j
+			a.base[i+1]++
+		}
+		a.base[i+1] += a.base[i]
+		a.start[i+1] = len(a.data)
+	}
+	return a
+}
+
+// degree returns the number of neighbors of the node with index i.
+func (a *adjacency) degree(i int) int {
+	return a.base[i+1] - a.base[i]
+}
+
+// each calls fn with the ordinal and the index of each neighbor of
+// the node with index i in ascending order until fn returns false.
+func (a *adjacency) each(i int, fn func(k, j int) bool) {
+	data := a.data[a.start[i]:a.start[i+1]]
+	j := i
+	for k := a.base[i]; len(data) != 0; k++ {
+		v, n := binary.Uvarint(data)
+		data = data[n:]
+		if k == a.base[i] {
+			j += int(v>>1) ^ -int(v&1)
+		} else {
+			j += int(v) + 1
+		}
+		if !fn(k, j) {
+			return
+		}
+	}
+}
+
+// find returns the ordinal of the edge from the node with index i to the
+// node with index j, or -1 if there is no such edge.
+func (a *adjacency) find(i, j int) int {
+	found := -1
+	a.each(i, func(k, l int) bool {
+		if l == j {
+			found = k
+		}
+		return l < j
+	})
+	return found
+}
+
+// size returns the number of bytes used to hold the lists.
+func (a *adjacency) size() int {
+	return len(a.data) + 8*(len(a.start)+len(a.base))
+}
+
+// weights holds edge weights indexed by edge ordinal.
+type weights struct {
+	// constant is the weight of all
+	// edges if isConstant is true.
+	constant   float64
+	isConstant bool
+
+	exact []float64
+
+	// bits is the number of bits
+	// holding each quantized weight
+	// in packed, with weights given
+	// by min + level*step.
+	bits      uint
+	min, step float64
+	packed    []uint64
+}
+
+// newWeights returns the m weights given by w in the form specified by bits.
+func newWeights(m int, w func(k int) float64, bits uint) weights {
+	if bits > 32 {
+		panic(fmt.Sprintf("compress: bad quantization bits: %d", bits))
+	}
+	if m == 0 {
+		return weights{isConstant: true}
+	}
+	first := w(0)
+	min, max := first, first
+	constant := true
+	for k := 0; k < m; k++ {
+		x := w(k)
+		if x != first && !(math.IsNaN(x) && math.IsNaN(first)) {
+			constant = false
+		}
+		min = math.Min(min, x)
+		max = math.Max(max, x)
+	}
+	switch {
+	case constant:
+		return weights{constant: first, isConstant: true}
+	case bits == 0:
+		exact := make([]float64, m)
+		for k := range exact {
+			exact[k] = w(k)
+		}
+		return weights{exact: exact}
+	}
+	if math.IsNaN(min) || math.IsInf(min, 0) || math.IsInf(max, 0) {
+		panic("compress: cannot quantize non-finite weight")
+	}
+	levels := uint64(1)<<bits - 1
+	q := weights{
+		bits:   bits,
+		min:    min,
+		step:   (max - min) / float64(levels),
+		packed: make([]uint64, (uint64(m)*uint64(bits)+63)/64),
+	}
+	for k := 0; k < m; k++ {
+		l := uint64(math.Floor((w(k)-min)/q.step + 0.5))
+		if l > levels {
+			l = levels
+		}
+		q.set(k, l)
+	}
+	return q
+}
+
+func (q *weights) set(k int, l uint64) {
+	off := uint64(k) * uint64(q.bits)
+	q.packed[off/64] |= l << (off % 64)
+	if s := off%64 + uint64(q.bits); s > 64 {
+		q.packed[off/64+1] |= l >> (64 - off%64)
+	}
+}
+
+// at returns the weight of the edge with ordinal k.
+func (q *weights) at(k int) float64 {
+	switch {
+	case q.isConstant:
+		return q.constant
+	case q.exact != nil:
+		return q.exact[k]
+	}
+	off := uint64(k) * uint64(q.bits)
+	l := q.packed[off/64] >> (off % 64)
+	if s := off%64 + uint64(q.bits); s > 64 {
+		l |= q.packed[off/64+1] << (64 - off%64)
+	}
+	return q.min + float64(l&(uint64(1)<<q.bits-1))*q.step
+}
+
+// size returns the number of bytes used to hold the weights.
+func (q *weights) size() int {
+	return 8*len(q.exact) + 8*len(q.packed)
+}
+
+// nodeIndex maps node IDs to indices.
+type nodeIndex []int
+
+// newNodeIndex returns the sorted IDs of the nodes of g.
+func newNodeIndex(g graph.Graph) nodeIndex {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	ids := make(nodeIndex, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+// index returns the index of the node with the given ID, or -1 if there
+// is no such node.
+func (ids nodeIndex) index(id int) int {
+	i := sort.SearchInts(ids, id)
+	if i == len(ids) || ids[i] != id {
+		return -1
+	}
+	return i
+}
+
+// neighbors returns the sorted indices of the given nodes.
+func (ids nodeIndex) neighbors(nodes []graph.Node) []int {
+	idx := make([]int, len(nodes))
+	for k, n := range nodes {
+		idx[k] = ids.index(n.ID())
+	}
+	sort.Ints(idx)
+	return idx
+}
@@ -0,0 +1,165 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package compress
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// weightedEdges sets random weights on the edges of g.
+func weightedEdges(g graph.Builder, src graph.Graph, rnd *rand.Rand) {
+	for _, u := range src.Nodes() {
+		for _, v := range src.From(u) {
+			g.SetEdge(simple.Edge{F: u, T: v, W: float64(rnd.Intn(1000)) / 10})
+		}
+	}
+}
+
+func sortedIDs(nodes []graph.Node) []int {
+	sort.Sort(ordered.ByID(nodes))
+	ids := make([]int, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+func TestDirected(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, bits := range []uint{0, 4, 13, 32} {
+		for _, n := range []int{0, 1, 10, 300} {
+			topology := simple.NewDirectedGraph(0, math.Inf(1))
+			err := gen.Gnp(topology, n, 0.1, rnd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// Sparse node IDs exercise negative first gaps.
+			src := simple.NewDirectedGraph(0, math.Inf(1))
+			for _, u := range topology.Nodes() {
+				src.AddNode(simple.Node(3 * u.ID()))
+			}
+			for _, u := range topology.Nodes() {
+				for _, v := range topology.From(u) {
+					src.SetEdge(simple.Edge{F: simple.Node(3 * u.ID()), T: simple.Node(3 * v.ID()), W: float64(rnd.Intn(1000)) / 10})
+				}
+			}
+
+			c := NewDirected(src, 0, math.Inf(1), Options{QuantizationBits: bits})
+			tol := 0.0
+			if bits != 0 {
+				tol = 99.9 / float64(uint64(1)<<bits-1) / 2 * (1 + 1e-9)
+			}
+			if !reflect.DeepEqual(sortedIDs(c.Nodes()), sortedIDs(src.Nodes())) {
+				t.Fatalf("unexpected nodes for n=%d bits=%d", n, bits)
+			}
+			for _, u := range src.Nodes() {
+				if got, want := sortedIDs(c.From(u)), sortedIDs(src.From(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected from nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				if got, want := sortedIDs(c.To(u)), sortedIDs(src.To(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected to nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				if c.Degree(u) != src.Degree(u) {
+					t.Errorf("unexpected degree for %d: got:%d want:%d", u.ID(), c.Degree(u), src.Degree(u))
+				}
+				for _, v := range src.Nodes() {
+					if c.HasEdgeFromTo(u, v) != src.HasEdgeFromTo(u, v) {
+						t.Errorf("unexpected edge existence for %d->%d", u.ID(), v.ID())
+					}
+					if c.HasEdgeBetween(u, v) != src.HasEdgeBetween(u, v) {
+						t.Errorf("unexpected edge between existence for %d--%d", u.ID(), v.ID())
+					}
+					got, gotOK := c.Weight(u, v)
+					want, wantOK := src.Weight(u, v)
+					if gotOK != wantOK || (math.Abs(got-want) > tol && !math.IsInf(want, 1)) {
+						t.Errorf("unexpected weight for %d->%d with bits=%d: got:%v,%t want:%v,%t",
+							u.ID(), v.ID(), bits, got, gotOK, want, wantOK)
+					}
+				}
+			}
+			if c.Has(simple.Node(1)) || c.Node(1) != nil || c.From(simple.Node(1)) != nil {
+				t.Errorf("unexpected node 1")
+			}
+		}
+	}
+}
+
+func TestUndirected(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, bits := range []uint{0, 8} {
+		for _, n := range []int{0, 1, 10, 300} {
+			topology := simple.NewUndirectedGraph(0, math.Inf(1))
+			err := gen.Gnp(topology, n, 0.1, rnd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			src := simple.NewUndirectedGraph(0, math.Inf(1))
+			for _, u := range topology.Nodes() {
+				src.AddNode(u)
+			}
+			weightedEdges(src, topology, rnd)
+
+			c := NewUndirected(src, 0, math.Inf(1), Options{QuantizationBits: bits})
+			tol := 0.0
+			if bits != 0 {
+				tol = 99.9 / float64(uint64(1)<<bits-1) / 2 * (1 + 1e-9)
+			}
+			for _, u := range src.Nodes() {
+				if got, want := sortedIDs(c.From(u)), sortedIDs(src.From(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected from nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				for _, v := range src.Nodes() {
+					got, gotOK := c.Weight(u, v)
+					want, wantOK := src.Weight(u, v)
+					if gotOK != wantOK || (math.Abs(got-want) > tol && !math.IsInf(want, 1)) {
+						t.Errorf("unexpected weight for %d--%d with bits=%d: got:%v,%t want:%v,%t",
+							u.ID(), v.ID(), bits, got, gotOK, want, wantOK)
+					}
+					if c.HasEdgeBetween(u, v) && c.EdgeBetween(u, v).Weight() != c.EdgeBetween(v, u).Weight() {
+						t.Errorf("asymmetric weight for %d--%d", u.ID(), v.ID())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	// A graph with locality in its node IDs.
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	const n = 10000
+	for i := 0; i < n; i++ {
+		for d := 1; d <= 8; d++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + d*d) % n), W: float64(d)})
+		}
+	}
+
+	exact := NewDirected(g, 0, math.Inf(1), Options{})
+	quantized := NewDirected(g, 0, math.Inf(1), Options{QuantizationBits: 4})
+	m := 8 * n
+
+	// Adjacency lists should take about one byte
+	// per edge in each direction.
+	if perEdge := float64(exact.Size()-8*m) / float64(m); perEdge > 8 {
+		t.Errorf("unexpected adjacency size: got:%.1f bytes per edge", perEdge)
+	}
+	if saved := exact.Size() - quantized.Size(); saved < 7*m {
+		t.Errorf("unexpected quantization saving: got:%d bytes want:>=%d", saved, 7*m)
+	}
+	for d := 1; d <= 8; d++ {
+		if w, _ := quantized.Weight(simple.Node(0), simple.Node(d*d)); math.Abs(w-float64(d)) > 0.25 {
+			t.Errorf("unexpected quantized weight: got:%v want:%d", w, d)
+		}
+	}
+}
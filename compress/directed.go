@@ -0,0 +1,167 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package compress
+
+import (
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Directed is a compressed read-only directed graph.
+//
+// Only the IDs of nodes and the end points and weights of edges are retained,
+// so nodes are returned as simple.Node values and edges as simple.Edge values.
+// Edge lookup takes time linear in the out-degree of the from node.
+type Directed struct {
+	ids     nodeIndex
+	from    adjacency
+	to      adjacency
+	weights weights
+
+	self, absent float64
+}
+
+// NewDirected returns a Directed holding the nodes and weighted edges of g,
+// with the specified self and absent edge weight values. NewDirected panics
+// if opts.QuantizationBits is greater than 32, or if weights are quantized
+// and an edge weight is not finite.
+func NewDirected(g graph.Directed, self, absent float64, opts Options) *Directed {
+	ids := newNodeIndex(g)
+	c := &Directed{
+		ids:    ids,
+		self:   self,
+		absent: absent,
+	}
+	c.from = newAdjacency(len(ids), func(i int) []int {
+		return ids.neighbors(g.From(simple.Node(ids[i])))
+	})
+	c.to = newAdjacency(len(ids), func(i int) []int {
+		return ids.neighbors(g.To(simple.Node(ids[i])))
+	})
+
+	// Edge ordinals follow the order of the from
+	// adjacency lists.
+	var ends [][2]int
+	for i := range ids {
+		c.from.each(i, func(_, j int) bool {
+			ends = append(ends, [2]int{i, j})
+			return true
+		})
+	}
+	c.weights = newWeights(len(ends), func(k int) float64 {
+		return g.Edge(simple.Node(ids[ends[k][0]]), simple.Node(ids[ends[k][1]])).Weight()
+	}, opts.QuantizationBits)
+	return c
+}
+
+// Size returns the number of bytes used to hold the nodes and edges of
+// the graph, excluding fixed overheads.
+func (c *Directed) Size() int {
+	return 8*len(c.ids) + c.from.size() + c.to.size() + c.weights.size()
+}
+
+// Node returns the node in the graph with the given ID, or nil if no
+// such node exists.
+func (c *Directed) Node(id int) graph.Node {
+	if c.ids.index(id) < 0 {
+		return nil
+	}
+	return simple.Node(id)
+}
+
+// Has returns whether the node exists within the graph.
+func (c *Directed) Has(n graph.Node) bool {
+	return c.ids.index(n.ID()) >= 0
+}
+
+// Nodes returns all the nodes in the graph.
+func (c *Directed) Nodes() []graph.Node {
+	nodes := make([]graph.Node, len(c.ids))
+	for i, id := range c.ids {
+		nodes[i] = simple.Node(id)
+	}
+	return nodes
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (c *Directed) From(n graph.Node) []graph.Node {
+	return c.nodesOf(&c.from, n)
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (c *Directed) To(n graph.Node) []graph.Node {
+	return c.nodesOf(&c.to, n)
+}
+
+func (c *Directed) nodesOf(a *adjacency, n graph.Node) []graph.Node {
+	i := c.ids.index(n.ID())
+	if i < 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, 0, a.degree(i))
+	a.each(i, func(_, j int) bool {
+		nodes = append(nodes, simple.Node(c.ids[j]))
+		return true
+	})
+	return nodes
+}
+
+// edge returns the ordinal of the edge from u to v, or -1 if there is
+// no such edge.
+func (c *Directed) edge(u, v graph.Node) int {
+	i := c.ids.index(u.ID())
+	if i < 0 {
+		return -1
+	}
+	j := c.ids.index(v.ID())
+	if j < 0 {
+		return -1
+	}
+	return c.from.find(i, j)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y
+// without considering direction.
+func (c *Directed) HasEdgeBetween(x, y graph.Node) bool {
+	return c.edge(x, y) >= 0 || c.edge(y, x) >= 0
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (c *Directed) HasEdgeFromTo(u, v graph.Node) bool {
+	return c.edge(u, v) >= 0
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (c *Directed) Edge(u, v graph.Node) graph.Edge {
+	k := c.edge(u, v)
+	if k < 0 {
+		return nil
+	}
+	return simple.Edge{F: simple.Node(u.ID()), T: simple.Node(v.ID()), W: c.weights.at(k)}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (c *Directed) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return c.self, true
+	}
+	if k := c.edge(x, y); k >= 0 {
+		return c.weights.at(k), true
+	}
+	return c.absent, false
+}
+
+// Degree returns the in+out degree of n in g.
+func (c *Directed) Degree(n graph.Node) int {
+	i := c.ids.index(n.ID())
+	if i < 0 {
+		return 0
+	}
+	return c.from.degree(i) + c.to.degree(i)
+}
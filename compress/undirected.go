@@ -0,0 +1,153 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package compress
+
+import (
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Undirected is a compressed read-only undirected graph.
+//
+// Only the IDs of nodes and the end points and weights of edges are retained,
+// so nodes are returned as simple.Node values and edges as simple.Edge values.
+// Edge lookup takes time linear in the degree of the first node.
+type Undirected struct {
+	ids     nodeIndex
+	adj     adjacency
+	weights weights
+
+	self, absent float64
+}
+
+// NewUndirected returns an Undirected holding the nodes and weighted edges
+// of g, with the specified self and absent edge weight values. NewUndirected
+// panics if opts.QuantizationBits is greater than 32, or if weights are
+// quantized and an edge weight is not finite.
+func NewUndirected(g graph.Undirected, self, absent float64, opts Options) *Undirected {
+	ids := newNodeIndex(g)
+	c := &Undirected{
+		ids:    ids,
+		self:   self,
+		absent: absent,
+	}
+	c.adj = newAdjacency(len(ids), func(i int) []int {
+		return ids.neighbors(g.From(simple.Node(ids[i])))
+	})
+
+	// Each edge is held in the lists of both
+	// its end points, with a weight for each.
+	var ends [][2]int
+	for i := range ids {
+		c.adj.each(i, func(_, j int) bool {
+			ends = append(ends, [2]int{i, j})
+			return true
+		})
+	}
+	c.weights = newWeights(len(ends), func(k int) float64 {
+		return g.EdgeBetween(simple.Node(ids[ends[k][0]]), simple.Node(ids[ends[k][1]])).Weight()
+	}, opts.QuantizationBits)
+	return c
+}
+
+// Size returns the number of bytes used to hold the nodes and edges of
+// the graph, excluding fixed overheads.
+func (c *Undirected) Size() int {
+	return 8*len(c.ids) + c.adj.size() + c.weights.size()
+}
+
+// Node returns the node in the graph with the given ID, or nil if no
+// such node exists.
+func (c *Undirected) Node(id int) graph.Node {
+	if c.ids.index(id) < 0 {
+		return nil
+	}
+	return simple.Node(id)
+}
+
+// Has returns whether the node exists within the graph.
+func (c *Undirected) Has(n graph.Node) bool {
+	return c.ids.index(n.ID()) >= 0
+}
+
+// Nodes returns all the nodes in the graph.
+func (c *Undirected) Nodes() []graph.Node {
+	nodes := make([]graph.Node, len(c.ids))
+	for i, id := range c.ids {
+		nodes[i] = simple.Node(id)
+	}
+	return nodes
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (c *Undirected) From(n graph.Node) []graph.Node {
+	i := c.ids.index(n.ID())
+	if i < 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, 0, c.adj.degree(i))
+	c.adj.each(i, func(_, j int) bool {
+		nodes = append(nodes, simple.Node(c.ids[j]))
+		return true
+	})
+	return nodes
+}
+
+// edge returns the ordinal of the edge between x and y, or -1 if there
+// is no such edge.
+func (c *Undirected) edge(x, y graph.Node) int {
+	i := c.ids.index(x.ID())
+	if i < 0 {
+		return -1
+	}
+	j := c.ids.index(y.ID())
+	if j < 0 {
+		return -1
+	}
+	return c.adj.find(i, j)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (c *Undirected) HasEdgeBetween(x, y graph.Node) bool {
+	return c.edge(x, y) >= 0
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (c *Undirected) Edge(u, v graph.Node) graph.Edge {
+	return c.EdgeBetween(u, v)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (c *Undirected) EdgeBetween(x, y graph.Node) graph.Edge {
+	k := c.edge(x, y)
+	if k < 0 {
+		return nil
+	}
+	return simple.Edge{F: simple.Node(x.ID()), T: simple.Node(y.ID()), W: c.weights.at(k)}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (c *Undirected) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return c.self, true
+	}
+	if k := c.edge(x, y); k >= 0 {
+		return c.weights.at(k), true
+	}
+	return c.absent, false
+}
+
+// Degree returns the degree of n in g.
+func (c *Undirected) Degree(n graph.Node) int {
+	i := c.ids.index(n.ID())
+	if i < 0 {
+		return 0
+	}
+	return c.adj.degree(i)
+}
@@ -0,0 +1,125 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestCopyWithOptionsMerge(t *testing.T) {
+	src := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 2},
+		{F: simple.Node(1), T: simple.Node(0), W: 5},
+		{F: simple.Node(1), T: simple.Node(2), W: 3},
+	} {
+		src.SetEdge(e)
+	}
+
+	var calls int
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	graph.CopyWithOptions(dst, src, graph.CopyOptions{
+		Merge: func(x, y float64, xe, ye graph.Edge) float64 {
+			calls++
+			if xe.Weight() != x || ye.Weight() != y {
+				t.Errorf("weights do not match edges: got:%v %v want:%v %v", x, y, xe.Weight(), ye.Weight())
+			}
+			return math.Max(x, y)
+		},
+	})
+	if calls != 1 {
+		t.Errorf("unexpected number of merges: got:%d want:1", calls)
+	}
+
+	for _, test := range []struct {
+		u, v int
+		want float64
+	}{
+		{u: 0, v: 1, want: 5},
+		{u: 1, v: 0, want: 5},
+		{u: 1, v: 2, want: 3},
+	} {
+		e := dst.EdgeBetween(simple.Node(test.u), simple.Node(test.v))
+		if e == nil {
+			t.Errorf("missing edge between %d and %d", test.u, test.v)
+			continue
+		}
+		if w := e.Weight(); w != test.want {
+			t.Errorf("unexpected weight between %d and %d: got:%v want:%v", test.u, test.v, w, test.want)
+		}
+	}
+	if _, ok := dst.EdgeBetween(simple.Node(0), simple.Node(1)).(graph.EdgePair); !ok {
+		t.Errorf("expected merged edge to be an EdgePair")
+	}
+	if n := len(dst.Edges()); n != 2 {
+		t.Errorf("unexpected number of edges: got:%d want:2", n)
+	}
+}
+
+func TestCopyWithOptionsNoMerge(t *testing.T) {
+	src := simple.NewDirectedGraph(0, math.Inf(1))
+	src.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 2})
+	src.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0), W: 2})
+
+	dst := simple.NewDirectedGraph(0, math.Inf(1))
+	graph.CopyWithOptions(dst, src, graph.CopyOptions{
+		Merge: func(x, y float64, _, _ graph.Edge) float64 {
+			t.Errorf("unexpected merge for directed destination")
+			return x
+		},
+	})
+	if n := len(dst.Edges()); n != 2 {
+		t.Errorf("unexpected number of edges: got:%d want:2", n)
+	}
+}
+
+func TestCopyWith(t *testing.T) {
+	src := simple.NewDirectedGraph(0, math.Inf(1))
+	src.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 2})
+	src.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 3})
+	src.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0), W: 4})
+
+	shift := func(n graph.Node) graph.Node { return simple.Node(n.ID() + 10) }
+
+	// Remap node IDs, copying edges implicitly.
+	dst := simple.NewDirectedGraph(0, math.Inf(1))
+	graph.CopyWith(dst, src, shift, nil)
+	for _, e := range src.Edges() {
+		got := dst.Edge(shift(e.From()), shift(e.To()))
+		if got == nil {
+			t.Errorf("missing remapped edge %d->%d", e.From().ID()+10, e.To().ID()+10)
+			continue
+		}
+		if got.Weight() != e.Weight() {
+			t.Errorf("unexpected weight for remapped edge %d->%d: got:%v want:%v",
+				e.From().ID()+10, e.To().ID()+10, got.Weight(), e.Weight())
+		}
+	}
+	if n := len(dst.Nodes()); n != 3 {
+		t.Errorf("unexpected number of nodes: got:%d want:3", n)
+	}
+
+	// Rescale weights and drop heavy edges.
+	dst = simple.NewDirectedGraph(0, math.Inf(1))
+	graph.CopyWith(dst, src, nil, func(e graph.Edge) graph.Edge {
+		if e.Weight() > 3 {
+			return nil
+		}
+		return simple.Edge{F: e.From(), T: e.To(), W: e.Weight() / 2}
+	})
+	if n := len(dst.Edges()); n != 2 {
+		t.Errorf("unexpected number of edges: got:%d want:2", n)
+	}
+	if w := dst.Edge(simple.Node(1), simple.Node(2)).Weight(); w != 1.5 {
+		t.Errorf("unexpected rescaled weight: got:%v want:1.5", w)
+	}
+	if dst.HasEdgeFromTo(simple.Node(2), simple.Node(0)) {
+		t.Errorf("unexpected dropped edge in destination")
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package embed provides graph node embedding functions.
+package embed
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+)
+
+// Spectral returns a dims-dimensional spectral embedding of the nodes of the
+// undirected graph g using the Laplacian eigenmaps of Belkin and Niyogi,
+// doi:10.1162/089976603321780317. The coordinates of each node are given by
+// the eigenvectors of the normalized Laplacian
+//  L = I - D^{-1/2} A D^{-1/2}
+// with the smallest non-trivial eigenvalues, rescaled by D^{-1/2}. The returned
+// map is keyed on the graph node IDs.
+//
+// The eigenvectors are found by the thick-restart Lanczos method, so only
+// sparse matrix-vector products are required and memory use is linear in the
+// order of g for a fixed number of dimensions. If g has more than one
+// connected component, the leading coordinates distinguish between
+// components. Isolated nodes are placed at the origin.
+//
+// If g does not implement graph.Weighter, path.UniformCost is used. Spectral
+// will panic if g has a negative edge weight or if dims is not positive and
+// less than the number of nodes in g.
+func Spectral(g graph.Undirected, dims int) map[int][]float64 {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	if dims < 1 || dims >= len(nodes) {
+		panic("embed: invalid embedding dimension")
+	}
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// Construct the normalized adjacency matrix
+	//  S = D^{-1/2} A D^{-1/2}.
+	s := make([][]entry, len(nodes))
+	deg := make([]float64, len(nodes))
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			j := indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			w, ok := weight(u, v)
+			if !ok {
+				panic("embed: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("embed: negative edge weight")
+			}
+			s[i] = append(s[i], entry{col: j, val: w})
+			deg[i] += w
+		}
+	}
+	invSqrt := make([]float64, len(nodes))
+	for i, d := range deg {
+		if d != 0 {
+			invSqrt[i] = 1 / math.Sqrt(d)
+		}
+	}
+	for i, row := range s {
+		for k := range row {
+			row[k].val *= invSqrt[i] * invSqrt[row[k].col]
+		}
+	}
+
+	// The smallest eigenvalues of L are the largest
+	// eigenvalues of I + S, which are found most
+	// quickly by the Lanczos method.
+	mul := func(dst, x []float64) {
+		for i, row := range s {
+			v := x[i]
+			for _, e := range row {
+				v += e.val * x[e.col]
+			}
+			dst[i] = v
+		}
+	}
+	vecs := lanczos(mul, len(nodes), dims+1, subspaceDim(len(nodes), dims+1))
+
+	coords := make(map[int][]float64, len(nodes))
+	for i, n := range nodes {
+		c := make([]float64, dims)
+		for d := range c {
+			c[d] = vecs[d+1][i] * invSqrt[i]
+		}
+		coords[n.ID()] = c
+	}
+	return coords
+}
+
+// entry is a sparse matrix element.
+type entry struct {
+	col int
+	val float64
+}
+
+// subspaceDim returns the Krylov subspace dimension used to find k
+// eigenvectors of an n×n matrix.
+func subspaceDim(n, k int) int {
+	m := 4*k + 40
+	if m > n {
+		m = n
+	}
+	return m
+}
+
+// maxRestarts is the maximum number of restarts of the Lanczos method.
+const maxRestarts = 500
+
+// lanczos returns the k eigenvectors with the largest eigenvalues of the n×n
+// symmetric matrix represented by mul, which places the product of the matrix
+// and x in dst. The eigenvectors are found by the thick-restart Lanczos method
+// of Wu and Simon, doi:10.1137/S0895479898334605, with full
+// reorthogonalization using a Krylov subspace of dimension m. The eigenvectors
+// are returned in order of decreasing eigenvalue with the sign chosen to make
+// the element with the largest magnitude positive.
+func lanczos(mul func(dst, x []float64), n, k, m int) [][]float64 {
+	const tol = 1e-10
+
+	rnd := rand.New(rand.NewSource(1))
+	random := func() []float64 {
+		v := make([]float64, n)
+		for i := range v {
+			v[i] = rnd.Float64() - 0.5
+		}
+		return v
+	}
+	// orthogonalize orthogonalizes x against basis twice,
+	// maintaining orthogonality in finite precision,
+	// and returns the norm of the result.
+	orthogonalize := func(x []float64, basis [][]float64) float64 {
+		for pass := 0; pass < 2; pass++ {
+			for _, b := range basis {
+				floats.AddScaled(x, -floats.Dot(x, b), b)
+			}
+		}
+		return floats.Norm(x, 2)
+	}
+
+	// basis holds an orthonormal basis and av
+	// holds the product of the matrix and each
+	// basis vector.
+	var basis, av [][]float64
+	next := random()
+	normalize(next)
+
+	// keep is the number of Ritz vectors
+	// retained at each restart.
+	keep := k + (m-k)/2
+	if keep >= m {
+		keep = m - 1
+	}
+
+	for restart := 0; ; restart++ {
+		for len(basis) < m {
+			v := next
+			w := make([]float64, n)
+			mul(w, v)
+			basis = append(basis, v)
+			av = append(av, w)
+
+			next = append([]float64(nil), w...)
+			nrm := orthogonalize(next, basis)
+			for nrm < 1e-10 && len(basis) < n {
+				// The Krylov subspace is invariant, so
+				// continue with a random vector orthogonal
+				// to the basis.
+				next = random()
+				nrm = orthogonalize(next, basis)
+			}
+			floats.Scale(1/nrm, next)
+		}
+
+		// Find the Ritz pairs of the projection
+		// of the matrix onto the basis.
+		h := make([][]float64, len(basis))
+		for i := range h {
+			h[i] = make([]float64, len(basis))
+			for j := range h {
+				h[i][j] = floats.Dot(basis[i], av[j])
+			}
+		}
+		for i := range h {
+			for j := 0; j < i; j++ {
+				sym := (h[i][j] + h[j][i]) / 2
+				h[i][j], h[j][i] = sym, sym
+			}
+		}
+		vals, evecs := jacobi(h)
+		order := make([]int, len(vals))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Sort(byValueDesc{order: order, vals: vals})
+
+		retain := keep
+		if len(basis) == n {
+			// The basis spans the space, so
+			// the Ritz pairs are exact.
+			retain = k
+		}
+		ritz := make([][]float64, retain)
+		aritz := make([][]float64, retain)
+		converged := true
+		for r := range ritz {
+			x := make([]float64, n)
+			ax := make([]float64, n)
+			col := order[r]
+			for j := range basis {
+				floats.AddScaled(x, evecs[j][col], basis[j])
+				floats.AddScaled(ax, evecs[j][col], av[j])
+			}
+			ritz[r], aritz[r] = x, ax
+			if r < k {
+				res := append([]float64(nil), ax...)
+				floats.AddScaled(res, -vals[col], x)
+				if floats.Norm(res, 2) > tol*math.Max(1, math.Abs(vals[col])) {
+					converged = false
+				}
+			}
+		}
+
+		if converged || len(basis) == n || restart == maxRestarts {
+			vecs := ritz[:k]
+			for _, x := range vecs {
+				normalize(x)
+				var max float64
+				for _, e := range x {
+					if math.Abs(e) > math.Abs(max) {
+						max = e
+					}
+				}
+				if max < 0 {
+					floats.Scale(-1, x)
+				}
+			}
+			return vecs
+		}
+
+		// Restart with the retained Ritz vectors, continuing
+		// the Krylov subspace from the residual direction.
+		basis, av = ritz, aritz
+	}
+}
+
+// jacobi returns the eigenvalues and eigenvectors of the symmetric matrix a
+// using the cyclic Jacobi method. The eigenvectors are the columns of the
+// returned matrix. The matrix a is overwritten.
+func jacobi(a [][]float64) (vals []float64, vecs [][]float64) {
+	n := len(a)
+	vecs = make([][]float64, n)
+	for i := range vecs {
+		vecs[i] = make([]float64, n)
+		vecs[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vecs[k][p], vecs[k][q]
+					vecs[k][p] = c*vkp - s*vkq
+					vecs[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	vals = make([]float64, n)
+	for i := range vals {
+		vals[i] = a[i][i]
+	}
+	return vals, vecs
+}
+
+// byValueDesc sorts indices by decreasing value.
+type byValueDesc struct {
+	order []int
+	vals  []float64
+}
+
+func (s byValueDesc) Len() int           { return len(s.order) }
+func (s byValueDesc) Less(i, j int) bool { return s.vals[s.order[i]] > s.vals[s.order[j]] }
+func (s byValueDesc) Swap(i, j int)      { s.order[i], s.order[j] = s.order[j], s.order[i] }
+
+func normalize(x []float64) {
+	nrm := floats.Norm(x, 2)
+	if nrm == 0 {
+		return
+	}
+	floats.Scale(1/nrm, x)
+}
@@ -0,0 +1,128 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/floats"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func TestSpectralPath(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	const n = 10
+	for i := 1; i < n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i - 1), T: simple.Node(i), W: 1})
+	}
+	coords := Spectral(g, 1)
+	if len(coords) != n {
+		t.Fatalf("unexpected number of embedded nodes: got:%d want:%d", len(coords), n)
+	}
+	// The Fiedler vector of a path is monotonic.
+	increasing := coords[1][0] > coords[0][0]
+	for i := 1; i < n; i++ {
+		if (coords[i][0] > coords[i-1][0]) != increasing {
+			t.Errorf("embedding of path is not monotonic: %v", coords)
+			break
+		}
+	}
+	// The embedding is symmetric about the center of the path.
+	for i := 0; i < n/2; i++ {
+		if math.Abs(coords[i][0]+coords[n-1-i][0]) > 1e-10 {
+			t.Errorf("embedding of path is not symmetric at %d: %v %v", i, coords[i][0], coords[n-1-i][0])
+		}
+	}
+}
+
+func TestSpectralBarbell(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, off := range []int{0, 6} {
+		for i := 0; i < 6; i++ {
+			for j := i + 1; j < 6; j++ {
+				g.SetEdge(simple.Edge{F: simple.Node(off + i), T: simple.Node(off + j), W: 1})
+			}
+		}
+	}
+	g.SetEdge(simple.Edge{F: simple.Node(5), T: simple.Node(6), W: 1})
+
+	coords := Spectral(g, 2)
+	side := coords[0][0] > 0
+	for id, c := range coords {
+		if len(c) != 2 {
+			t.Fatalf("unexpected embedding dimension: got:%d want:2", len(c))
+		}
+		if (c[0] > 0) != (side == (id < 6)) {
+			t.Errorf("first coordinate does not separate cliques: node %d at %v", id, c)
+		}
+	}
+}
+
+func TestLanczos(t *testing.T) {
+	// Compare the Lanczos eigenvectors of a random sparse graph's
+	// normalized adjacency with the defining eigen-equation.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := gen.Gnp(g, 300, 0.05, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("failed to generate graph: %v", err)
+	}
+	nodes := g.Nodes()
+	indexOf := make(map[int]int)
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	mul := func(dst, x []float64) {
+		for i, u := range nodes {
+			v := x[i]
+			for _, w := range g.From(u) {
+				v += x[indexOf[w.ID()]]
+			}
+			dst[i] = v
+		}
+	}
+	const k = 3
+	vecs := lanczos(mul, len(nodes), k, subspaceDim(len(nodes), k))
+	av := make([]float64, len(nodes))
+	for i, v := range vecs {
+		mul(av, v)
+		lambda := floats.Dot(av, v)
+		floats.AddScaled(av, -lambda, v)
+		if r := floats.Norm(av, 2); r > 1e-6*math.Abs(lambda) {
+			t.Errorf("large residual for eigenvector %d: |Av-λv|=%v λ=%v", i, r, lambda)
+		}
+		for j := 0; j < i; j++ {
+			if d := floats.Dot(v, vecs[j]); math.Abs(d) > 1e-8 {
+				t.Errorf("eigenvectors %d and %d not orthogonal: %v", i, j, d)
+			}
+		}
+	}
+}
+
+func TestJacobi(t *testing.T) {
+	a := [][]float64{
+		{4, 1, 2},
+		{1, 3, 0},
+		{2, 0, 5},
+	}
+	orig := make([][]float64, len(a))
+	for i := range a {
+		orig[i] = append([]float64(nil), a[i]...)
+	}
+	vals, vecs := jacobi(a)
+	for c, lambda := range vals {
+		for i := range orig {
+			var av float64
+			for j := range orig {
+				av += orig[i][j] * vecs[j][c]
+			}
+			if math.Abs(av-lambda*vecs[i][c]) > 1e-12 {
+				t.Errorf("eigenpair %d does not satisfy Av=λv at row %d", c, i)
+			}
+		}
+	}
+}
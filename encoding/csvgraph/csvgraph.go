@@ -0,0 +1,360 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package csvgraph implements decoding of edge lists held in CSV files into
+// graphs.
+package csvgraph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gonum/graph"
+)
+
+// Node is a graph node identified in a CSV file by a key.
+type Node struct {
+	id  int
+	key string
+}
+
+// ID returns the ID number of the node.
+func (n Node) ID() int { return n.id }
+
+// Key returns the key identifying the node in the CSV file.
+func (n Node) Key() string { return n.key }
+
+// Attributes returns the key of the node as a "key" attribute.
+func (n Node) Attributes() []graph.Attribute {
+	return []graph.Attribute{{Key: "key", Value: n.key}}
+}
+
+// Edge is an edge decoded from a row of a CSV file.
+type Edge struct {
+	F, T Node
+	W    float64
+
+	// Attrs holds the values of the
+	// attribute columns of the row in
+	// the order of the mapping.
+	Attrs []graph.Attribute
+}
+
+// From returns the from-node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// Weight returns the weight of the edge.
+func (e Edge) Weight() float64 { return e.W }
+
+// Attributes returns the attribute columns of the row holding the edge.
+func (e Edge) Attributes() []graph.Attribute { return e.Attrs }
+
+// Mapping describes the layout of a CSV edge list.
+type Mapping struct {
+	// Header indicates that the first
+	// record of the file names the
+	// columns.
+	Header bool
+
+	// Source and Target are the columns
+	// holding the keys of the from and to
+	// nodes of each edge. Columns are
+	// identified by name if Header is
+	// true, and otherwise by their
+	// zero-based index in decimal.
+	Source, Target string
+
+	// Weight is the column holding the
+	// weight of each edge. If Weight is
+	// empty, edges have weight one.
+	Weight string
+
+	// Attributes are the columns held
+	// as attributes of each edge, keyed
+	// by the column identifier.
+	Attributes []string
+
+	// Comma is the field delimiter.
+	// If Comma is zero, ',' is used.
+	Comma rune
+
+	// Comment, if not zero, is the
+	// character starting comment lines.
+	Comment rune
+
+	// MaxErrors, if positive, is the
+	// number of row errors after which
+	// decoding is abandoned.
+	MaxErrors int
+
+	// Progress, if not nil, is called
+	// after every ProgressInterval rows
+	// and once decoding is complete.
+	// If ProgressInterval is not
+	// positive, 10000 is used.
+	Progress         func(Progress)
+	ProgressInterval int
+}
+
+// Progress is the state of a decoding.
+type Progress struct {
+	// Rows is the number of
+	// records read, including
+	// the header.
+	Rows int
+
+	// Bytes is the number of
+	// bytes read from the file.
+	Bytes int64
+
+	// Nodes is the number of
+	// nodes added to the graph
+	// and Edges the number of
+	// rows added as edges.
+	Nodes, Edges int
+
+	// Errors is the number of
+	// rows with errors.
+	Errors int
+}
+
+// RowError is an error decoding a row of a CSV file.
+type RowError struct {
+	// Row is the one-based record
+	// number of the row, counting
+	// the header.
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("csvgraph: row %d: %v", e.Row, e.Err)
+}
+
+// ErrTooManyErrors is returned by Decode when the number of row errors
+// exceeds the mapping's MaxErrors.
+var ErrTooManyErrors = errors.New("csvgraph: too many row errors")
+
+// Decode reads the CSV edge list from r, one record at a time, and adds the
+// edges it describes to dst as Edge values. The first occurrence of each key
+// in the source or target column adds a Node to dst with an ID from
+// dst.NewNodeID. If dst is undirected and a pair of nodes is joined by more
+// than one row, the last row is retained.
+//
+// Rows that cannot be decoded, because they are malformed, lack a mapped
+// column, have a weight that is not a number or join a node to itself, are
+// skipped and returned as RowErrors. The returned error is non-nil if the
+// mapping does not match the file, reading fails or there are more than
+// MaxErrors row errors.
+func Decode(r io.Reader, dst graph.Builder, m Mapping) ([]RowError, error) {
+	cr := &countingReader{r: r}
+	c := csv.NewReader(cr)
+	if m.Comma != 0 {
+		c.Comma = m.Comma
+	}
+	c.Comment = m.Comment
+	c.FieldsPerRecord = -1
+
+	d := decoder{
+		dst:   dst,
+		m:     m,
+		nodes: make(map[string]Node),
+	}
+	interval := m.ProgressInterval
+	if interval <= 0 {
+		interval = 10000
+	}
+	report := func() {
+		if m.Progress != nil {
+			d.progress.Bytes = cr.n
+			d.progress.Errors = len(d.errs)
+			m.Progress(d.progress)
+		}
+	}
+
+	if m.Header {
+		header, err := c.Read()
+		if err == io.EOF {
+			return nil, errors.New("csvgraph: missing header")
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.progress.Rows++
+		err = d.columns(header)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err := d.columns(nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		record, err := c.Read()
+		if err == io.EOF {
+			break
+		}
+		d.progress.Rows++
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return d.errs, err
+			}
+			d.errs = append(d.errs, RowError{Row: d.progress.Rows, Err: err})
+		} else {
+			err = d.row(record)
+			if err != nil {
+				d.errs = append(d.errs, RowError{Row: d.progress.Rows, Err: err})
+			}
+		}
+		if m.MaxErrors > 0 && len(d.errs) > m.MaxErrors {
+			report()
+			return d.errs, ErrTooManyErrors
+		}
+		if d.progress.Rows%interval == 0 {
+			report()
+		}
+	}
+	report()
+	return d.errs, nil
+}
+
+type decoder struct {
+	dst graph.Builder
+	m   Mapping
+
+	// source, target and weight are the
+	// indexes of the mapped columns, and
+	// attrs the indexes of the attribute
+	// columns. weight is -1 if no weight
+	// column is mapped.
+	source, target, weight int
+	attrs                  []int
+
+	nodes    map[string]Node
+	errs     []RowError
+	progress Progress
+}
+
+// columns resolves the columns of the mapping using the given header.
+func (d *decoder) columns(header []string) error {
+	index := func(col string) (int, error) {
+		if d.m.Header {
+			for i, name := range header {
+				if name == col {
+					return i, nil
+				}
+			}
+			return 0, fmt.Errorf("csvgraph: no column %q in header", col)
+		}
+		i, err := strconv.Atoi(col)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("csvgraph: invalid column index %q", col)
+		}
+		return i, nil
+	}
+
+	var err error
+	d.source, err = index(d.m.Source)
+	if err != nil {
+		return err
+	}
+	d.target, err = index(d.m.Target)
+	if err != nil {
+		return err
+	}
+	d.weight = -1
+	if d.m.Weight != "" {
+		d.weight, err = index(d.m.Weight)
+		if err != nil {
+			return err
+		}
+	}
+	d.attrs = make([]int, len(d.m.Attributes))
+	for i, col := range d.m.Attributes {
+		d.attrs[i], err = index(col)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// row adds the edge held in record to the destination.
+func (d *decoder) row(record []string) error {
+	field := func(i int) (string, error) {
+		if i >= len(record) {
+			return "", fmt.Errorf("missing column %d", i)
+		}
+		return record[i], nil
+	}
+
+	src, err := field(d.source)
+	if err != nil {
+		return err
+	}
+	dst, err := field(d.target)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return fmt.Errorf("self edge on %q", src)
+	}
+	w := 1.0
+	if d.weight >= 0 {
+		f, err := field(d.weight)
+		if err != nil {
+			return err
+		}
+		w, err = strconv.ParseFloat(f, 64)
+		if err != nil {
+			return err
+		}
+	}
+	var attrs []graph.Attribute
+	for i, col := range d.attrs {
+		v, err := field(col)
+		if err != nil {
+			return err
+		}
+		attrs = append(attrs, graph.Attribute{Key: d.m.Attributes[i], Value: v})
+	}
+
+	d.dst.SetEdge(Edge{F: d.node(src), T: d.node(dst), W: w, Attrs: attrs})
+	d.progress.Edges++
+	return nil
+}
+
+// node returns the node with the given key, adding it to the
+// destination if it has not been seen.
+func (d *decoder) node(key string) Node {
+	n, ok := d.nodes[key]
+	if !ok {
+		n = Node{id: d.dst.NewNodeID(), key: key}
+		d.dst.AddNode(n)
+		d.nodes[key] = n
+		d.progress.Nodes++
+	}
+	return n
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.n += int64(n)
+	return n, err
+}
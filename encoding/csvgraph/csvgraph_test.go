@@ -0,0 +1,148 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csvgraph
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+const transactions = `from,to,amount,channel
+alice,bob,10.5,web
+bob,carol,3,app
+carol,alice,x,app
+dave,dave,1,web
+carol,erin
+"bad"quote,bob,1,web
+erin,alice,7,web
+`
+
+func TestDecode(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	var last Progress
+	errs, err := Decode(strings.NewReader(transactions), g, Mapping{
+		Header:     true,
+		Source:     "from",
+		Target:     "to",
+		Weight:     "amount",
+		Attributes: []string{"channel"},
+		Progress:   func(p Progress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gotRows []int
+	for _, e := range errs {
+		gotRows = append(gotRows, e.Row)
+	}
+	if want := []int{4, 5, 6, 7}; !reflect.DeepEqual(gotRows, want) {
+		t.Errorf("unexpected error rows: got:%v want:%v errors:%v", gotRows, want, errs)
+	}
+
+	keys := make(map[string]graph.Node)
+	for _, n := range g.Nodes() {
+		keys[n.(Node).Key()] = n
+	}
+	if len(keys) != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", len(keys))
+	}
+	e, ok := g.Edge(keys["alice"], keys["bob"]).(Edge)
+	if !ok {
+		t.Fatal("missing edge alice→bob")
+	}
+	if e.W != 10.5 || !reflect.DeepEqual(e.Attrs, []graph.Attribute{{Key: "channel", Value: "web"}}) {
+		t.Errorf("unexpected edge alice→bob: got:%+v", e)
+	}
+	if g.Edge(keys["erin"], keys["alice"]) == nil {
+		t.Error("missing edge erin→alice")
+	}
+	if len(g.Edges()) != 3 {
+		t.Errorf("unexpected number of edges: got:%d want:3", len(g.Edges()))
+	}
+
+	want := Progress{Rows: 8, Bytes: int64(len(transactions)), Nodes: 4, Edges: 3, Errors: 4}
+	if last != want {
+		t.Errorf("unexpected final progress: got:%+v want:%+v", last, want)
+	}
+}
+
+func TestDecodeNoHeader(t *testing.T) {
+	var data []string
+	data = append(data, "# generated edges")
+	for i := 0; i < 25; i++ {
+		data = append(data, fmt.Sprintf("n%d\tn%d", i, i+1))
+	}
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	var calls []int
+	errs, err := Decode(strings.NewReader(strings.Join(data, "\n")), g, Mapping{
+		Source:           "0",
+		Target:           "1",
+		Comma:            '\t',
+		Comment:          '#',
+		Progress:         func(p Progress) { calls = append(calls, p.Rows) },
+		ProgressInterval: 10,
+	})
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v %v", err, errs)
+	}
+	if want := []int{10, 20, 25}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("unexpected progress calls: got:%v want:%v", calls, want)
+	}
+	if len(g.Nodes()) != 26 || len(g.Edges()) != 25 {
+		t.Errorf("unexpected graph size: got:%d nodes %d edges want:26 nodes 25 edges", len(g.Nodes()), len(g.Edges()))
+	}
+	for _, e := range g.Edges() {
+		if e.Weight() != 1 {
+			t.Errorf("unexpected edge weight: got:%v want:1", e.Weight())
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+		m    Mapping
+		want error
+	}{
+		{
+			name: "missing column",
+			data: "a,b\n",
+			m:    Mapping{Header: true, Source: "a", Target: "c"},
+		},
+		{
+			name: "invalid index",
+			data: "a,b\n",
+			m:    Mapping{Source: "0", Target: "one"},
+		},
+		{
+			name: "missing header",
+			data: "",
+			m:    Mapping{Header: true, Source: "a", Target: "b"},
+		},
+		{
+			name: "too many errors",
+			data: "a,b\nx\nx\nx\n",
+			m:    Mapping{Header: true, Source: "a", Target: "b", MaxErrors: 2},
+			want: ErrTooManyErrors,
+		},
+	} {
+		_, err := Decode(strings.NewReader(test.data), simple.NewDirectedGraph(0, math.Inf(1)), test.m)
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+			continue
+		}
+		if test.want != nil && err != test.want {
+			t.Errorf("%s: unexpected error: got:%v want:%v", test.name, err, test.want)
+		}
+	}
+}
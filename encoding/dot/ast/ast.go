@@ -0,0 +1,442 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ast declares the types used to represent abstract syntax trees of
+// GraphViz DOT files.
+//
+// IDs and attribute values hold their source text, including any quotes, so
+// that the String methods reproduce the original lexical forms. Elements of
+// parsed syntax trees hold their position in the source, so the trees may be
+// used by editor tooling to locate and rewrite the elements they describe.
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Pos is a position in DOT source. Lines and columns are counted from one,
+// with columns counted in bytes. The zero Pos is not a valid position and
+// is held by elements that were not parsed from source.
+type Pos struct {
+	Line, Column int
+}
+
+// IsValid returns whether p is a valid position.
+func (p Pos) IsValid() bool { return p.Line > 0 }
+
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Element is an element of a syntax tree.
+type Element interface {
+	fmt.Stringer
+
+	// Position returns the position
+	// of the first token of the
+	// element in the source.
+	Position() Pos
+}
+
+// File is a DOT file holding one or more graphs.
+type File struct {
+	Graphs []*Graph
+
+	// Comments holds the comments
+	// following the last graph.
+	Comments []*Comment
+}
+
+// Position returns the position of the first graph of the file.
+func (f *File) Position() Pos {
+	if len(f.Graphs) == 0 {
+		return Pos{}
+	}
+	return f.Graphs[0].Position()
+}
+
+func (f *File) String() string {
+	var buf bytes.Buffer
+	f.write(&buf, "\t")
+	return buf.String()
+}
+
+func (f *File) write(buf *bytes.Buffer, indent string) {
+	for i, g := range f.Graphs {
+		if i != 0 {
+			buf.WriteByte('\n')
+		}
+		g.write(buf, indent)
+		buf.WriteByte('\n')
+	}
+	for _, c := range f.Comments {
+		buf.WriteString(c.String())
+		buf.WriteByte('\n')
+	}
+}
+
+// Graph is a DOT graph.
+type Graph struct {
+	// Comments holds the comments
+	// preceding the graph.
+	Comments []*Comment
+
+	// Pos is the position of the
+	// strict or graph keyword.
+	Pos Pos
+
+	Strict   bool
+	Directed bool
+	ID       string
+	Stmts    []Stmt
+}
+
+// Position returns the position of the graph.
+func (g *Graph) Position() Pos { return g.Pos }
+
+func (g *Graph) String() string {
+	var buf bytes.Buffer
+	g.write(&buf, "\t")
+	return buf.String()
+}
+
+func (g *Graph) write(buf *bytes.Buffer, indent string) {
+	for _, c := range g.Comments {
+		buf.WriteString(c.String())
+		buf.WriteByte('\n')
+	}
+	if g.Strict {
+		buf.WriteString("strict ")
+	}
+	if g.Directed {
+		buf.WriteString("digraph ")
+	} else {
+		buf.WriteString("graph ")
+	}
+	if g.ID != "" {
+		buf.WriteString(g.ID)
+		buf.WriteByte(' ')
+	}
+	writeBlock(buf, g.Stmts, indent, "")
+}
+
+// writeBlock writes the statements in a braced block. The statements are
+// indented by the concatenation of prefix and indent, and the closing brace
+// by prefix.
+func writeBlock(buf *bytes.Buffer, stmts []Stmt, indent, prefix string) {
+	buf.WriteString("{\n")
+	for _, s := range stmts {
+		buf.WriteString(prefix)
+		buf.WriteString(indent)
+		if sub, ok := s.(*Subgraph); ok {
+			sub.write(buf, indent, prefix+indent)
+		} else {
+			buf.WriteString(s.String())
+		}
+		if _, ok := s.(*Comment); !ok {
+			buf.WriteByte(';')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(prefix)
+	buf.WriteByte('}')
+}
+
+// Stmt is a statement in a graph or subgraph.
+type Stmt interface {
+	Element
+	isStmt()
+}
+
+func (*NodeStmt) isStmt() {}
+func (*EdgeStmt) isStmt() {}
+func (*AttrStmt) isStmt() {}
+func (*Attr) isStmt()     {}
+func (*Subgraph) isStmt() {}
+func (*Comment) isStmt()  {}
+
+// Vertex is a terminal of an edge; a node or a subgraph.
+type Vertex interface {
+	Element
+	isVertex()
+}
+
+func (*Node) isVertex()     {}
+func (*Subgraph) isVertex() {}
+
+// NodeStmt is a node statement.
+type NodeStmt struct {
+	Node  *Node
+	Attrs []*Attr
+}
+
+// Position returns the position of the node.
+func (s *NodeStmt) Position() Pos { return s.Node.Pos }
+
+func (s *NodeStmt) String() string {
+	return s.Node.String() + attrList(s.Attrs)
+}
+
+// EdgeStmt is an edge statement. An edge statement may describe a
+// chain of edges sharing the attributes.
+type EdgeStmt struct {
+	From  Vertex
+	To    *Edge
+	Attrs []*Attr
+}
+
+// Position returns the position of the first vertex of the edge statement.
+func (s *EdgeStmt) Position() Pos { return s.From.Position() }
+
+func (s *EdgeStmt) String() string {
+	return s.From.String() + s.To.String() + attrList(s.Attrs)
+}
+
+// Edge is the right hand side of an edge statement.
+type Edge struct {
+	// Pos is the position
+	// of the edge operator.
+	Pos Pos
+
+	Directed bool
+	Vertex   Vertex
+
+	// To is the next edge of
+	// a chain, or nil.
+	To *Edge
+}
+
+// Position returns the position of the edge operator.
+func (e *Edge) Position() Pos { return e.Pos }
+
+func (e *Edge) String() string {
+	op := " -- "
+	if e.Directed {
+		op = " -> "
+	}
+	s := op + e.Vertex.String()
+	if e.To != nil {
+		s += e.To.String()
+	}
+	return s
+}
+
+// Kind is the kind of an attribute statement.
+type Kind int
+
+const (
+	// GraphKind is a graph attribute statement.
+	GraphKind Kind = iota
+	// NodeKind is a default node attribute
+	// statement.
+	NodeKind
+	// EdgeKind is a default edge attribute
+	// statement.
+	EdgeKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case GraphKind:
+		return "graph"
+	case NodeKind:
+		return "node"
+	case EdgeKind:
+		return "edge"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// AttrStmt is an attribute statement, setting default attributes
+// for the graph, nodes or edges.
+type AttrStmt struct {
+	// Pos is the position
+	// of the kind keyword.
+	Pos Pos
+
+	Kind  Kind
+	Attrs []*Attr
+}
+
+// Position returns the position of the attribute statement.
+func (s *AttrStmt) Position() Pos { return s.Pos }
+
+func (s *AttrStmt) String() string {
+	// Unlike node and edge statements, an attribute
+	// statement requires its attribute list.
+	if len(s.Attrs) == 0 {
+		return s.Kind.String() + " []"
+	}
+	return s.Kind.String() + " " + strings.TrimPrefix(attrList(s.Attrs), " ")
+}
+
+// Attr is an attribute. As a statement, an Attr is a graph attribute
+// assignment.
+type Attr struct {
+	// Pos and ValPos are the
+	// positions of the key
+	// and value.
+	Pos, ValPos Pos
+
+	Key, Val string
+}
+
+// Position returns the position of the attribute key.
+func (a *Attr) Position() Pos { return a.Pos }
+
+func (a *Attr) String() string {
+	return a.Key + "=" + a.Val
+}
+
+func attrList(attrs []*Attr) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	s := make([]string, len(attrs))
+	for i, a := range attrs {
+		s[i] = a.String()
+	}
+	return " [" + strings.Join(s, " ") + "]"
+}
+
+// Subgraph is a subgraph. A Subgraph may be a statement or
+// the terminal of an edge.
+type Subgraph struct {
+	// Pos is the position of the
+	// subgraph keyword or, if the
+	// keyword is omitted, the
+	// opening brace.
+	Pos Pos
+
+	ID    string
+	Stmts []Stmt
+}
+
+// Position returns the position of the subgraph.
+func (s *Subgraph) Position() Pos { return s.Pos }
+
+// String returns the DOT form of the subgraph. The statements are written on
+// a single line, as is usual for subgraph edge terminals, unless the subgraph
+// holds comments.
+func (s *Subgraph) String() string {
+	var buf bytes.Buffer
+	if hasComments(s.Stmts) {
+		s.write(&buf, "\t", "")
+		return buf.String()
+	}
+	if s.ID != "" {
+		buf.WriteString("subgraph ")
+		buf.WriteString(s.ID)
+		buf.WriteByte(' ')
+	}
+	buf.WriteByte('{')
+	for i, stmt := range s.Stmts {
+		if i != 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(stmt.String())
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// hasComments returns whether stmts or any subgraphs within them
+// hold comments.
+func hasComments(stmts []Stmt) bool {
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *Comment:
+			return true
+		case *Subgraph:
+			if hasComments(s.Stmts) {
+				return true
+			}
+		case *EdgeStmt:
+			if sub, ok := s.From.(*Subgraph); ok && hasComments(sub.Stmts) {
+				return true
+			}
+			for to := s.To; to != nil; to = to.To {
+				if sub, ok := to.Vertex.(*Subgraph); ok && hasComments(sub.Stmts) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (s *Subgraph) write(buf *bytes.Buffer, indent, prefix string) {
+	if s.ID != "" {
+		buf.WriteString("subgraph ")
+		buf.WriteString(s.ID)
+		buf.WriteByte(' ')
+	}
+	if len(s.Stmts) == 0 {
+		buf.WriteString("{}")
+		return
+	}
+	writeBlock(buf, s.Stmts, indent, prefix)
+}
+
+// Node is a node ID with an optional port.
+type Node struct {
+	// Pos is the position
+	// of the node ID.
+	Pos Pos
+
+	ID   string
+	Port *Port
+}
+
+// Position returns the position of the node ID.
+func (n *Node) Position() Pos { return n.Pos }
+
+func (n *Node) String() string {
+	if n.Port == nil {
+		return n.ID
+	}
+	return n.ID + n.Port.String()
+}
+
+// Port is a node port. Either of ID and CompassPoint
+// may be empty, but not both.
+type Port struct {
+	// Pos is the position of
+	// the colon introducing
+	// the port.
+	Pos Pos
+
+	ID           string
+	CompassPoint string
+}
+
+// Position returns the position of the port.
+func (p *Port) Position() Pos { return p.Pos }
+
+func (p *Port) String() string {
+	switch {
+	case p.ID == "":
+		return ":" + p.CompassPoint
+	case p.CompassPoint == "":
+		return ":" + p.ID
+	}
+	return ":" + p.ID + ":" + p.CompassPoint
+}
+
+// Comment is a comment. The text includes the comment
+// delimiters.
+type Comment struct {
+	Pos  Pos
+	Text string
+}
+
+// Position returns the position of the comment.
+func (c *Comment) Position() Pos { return c.Pos }
+
+func (c *Comment) String() string { return c.Text }
@@ -0,0 +1,354 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ast
+
+import "strings"
+
+// The editing methods of Graph change the syntax tree in place, leaving
+// statements they do not need to change, including comments and statements
+// they do not describe, as they are. IDs, attribute keys and values are
+// given in their source form, with quotes if they are needed, and are
+// compared with the IDs in the tree in their canonical form, so "a" and a
+// are the same node. Ports of nodes are ignored when matching nodes.
+//
+// Edges between a node and a subgraph terminal are not matched by the
+// edge methods.
+
+// Canonical returns the canonical form of a DOT ID, removing the quotes and
+// escaped quotes and line continuations of quoted strings.
+func Canonical(id string) string {
+	if len(id) < 2 || id[0] != '"' {
+		return id
+	}
+	id = id[1 : len(id)-1]
+	id = strings.Replace(id, "\\\r\n", "", -1)
+	id = strings.Replace(id, "\\\n", "", -1)
+	return strings.Replace(id, `\"`, `"`, -1)
+}
+
+// AddNode appends a node statement for the node with the given ID and
+// attributes to the graph and returns it.
+func (g *Graph) AddNode(id string, attrs ...*Attr) *NodeStmt {
+	s := &NodeStmt{Node: &Node{ID: id}, Attrs: attrs}
+	g.Stmts = append(g.Stmts, s)
+	return s
+}
+
+// AddEdge appends an edge statement for an edge between the nodes with the
+// given IDs with the given attributes to the graph and returns it.
+func (g *Graph) AddEdge(from, to string, attrs ...*Attr) *EdgeStmt {
+	s := &EdgeStmt{
+		From:  &Node{ID: from},
+		To:    &Edge{Directed: g.Directed, Vertex: &Node{ID: to}},
+		Attrs: attrs,
+	}
+	g.Stmts = append(g.Stmts, s)
+	return s
+}
+
+// RenameNode changes the ID of each mention of the node old to new and
+// returns the number of mentions changed.
+func (g *Graph) RenameNode(old, new string) int {
+	var n int
+	old = Canonical(old)
+	Inspect(g, func(e Element) bool {
+		if node, ok := e.(*Node); ok && Canonical(node.ID) == old {
+			node.ID = new
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// RemoveNode removes the node statements for the node with the given ID and
+// the edges that join it, and returns the number of statements and edges
+// removed. Edge chains holding the node are split. The other terminals of a
+// removed edge are retained as node or subgraph statements if they are not
+// joined by a remaining edge of the chain.
+func (g *Graph) RemoveNode(id string) int {
+	var n int
+	g.Stmts = removeNode(g.Stmts, Canonical(id), &n)
+	return n
+}
+
+func removeNode(stmts []Stmt, id string, n *int) []Stmt {
+	var out []Stmt
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *NodeStmt:
+			if Canonical(s.Node.ID) == id {
+				*n++
+				continue
+			}
+		case *Subgraph:
+			s.Stmts = removeNode(s.Stmts, id, n)
+		case *EdgeStmt:
+			vs, edges := chainOf(s)
+			drop := make([]bool, len(vs))
+			for i, v := range vs {
+				switch v := v.(type) {
+				case *Node:
+					drop[i] = Canonical(v.ID) == id
+				case *Subgraph:
+					v.Stmts = removeNode(v.Stmts, id, n)
+				}
+			}
+			cut := make([]bool, len(edges))
+			for i := range edges {
+				cut[i] = drop[i] || drop[i+1]
+				if cut[i] {
+					*n++
+				}
+			}
+			out = append(out, split(s, vs, edges, drop, cut)...)
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// RemoveEdge removes the edges between the nodes with the given IDs and
+// returns the number of edges removed. If the graph is undirected, edges in
+// either direction are removed. Edge chains holding the edge are split and
+// terminals of a removed edge are retained as node or subgraph statements if
+// they are not joined by a remaining edge of the chain.
+func (g *Graph) RemoveEdge(from, to string) int {
+	var n int
+	g.Stmts = g.editEdges(g.Stmts, Canonical(from), Canonical(to), func(s *EdgeStmt, vs []Vertex, edges []*Edge, match []bool) []Stmt {
+		for _, m := range match {
+			if m {
+				n++
+			}
+		}
+		return split(s, vs, edges, make([]bool, len(vs)), match)
+	})
+	return n
+}
+
+// SetEdgeAttr sets the attribute key of the edges between the nodes with the
+// given IDs to val. If the graph is undirected, edges in either direction are
+// changed. An edge in a chain is split from the chain so that the attribute
+// of the other edges of the chain is unchanged. If the graph has no such
+// edge, an edge statement holding the attribute is appended to the graph.
+func (g *Graph) SetEdgeAttr(from, to, key, val string) {
+	var found bool
+	g.Stmts = g.editEdges(g.Stmts, Canonical(from), Canonical(to), func(s *EdgeStmt, vs []Vertex, edges []*Edge, match []bool) []Stmt {
+		found = true
+		if len(edges) == 1 {
+			s.Attrs = setAttr(s.Attrs, key, val)
+			return []Stmt{s}
+		}
+		// Split the chain, keeping the
+		// unmatched edges together.
+		var out []Stmt
+		start := 0
+		for i, m := range match {
+			if !m {
+				continue
+			}
+			if i > start {
+				out = append(out, chainStmt(s, vs[start:i+1], edges[start:i]))
+			}
+			e := chainStmt(s, vs[i:i+2], edges[i:i+1]).(*EdgeStmt)
+			e.Attrs = setAttr(append([]*Attr(nil), s.Attrs...), key, val)
+			out = append(out, e)
+			start = i + 1
+		}
+		if start < len(edges) {
+			out = append(out, chainStmt(s, vs[start:], edges[start:]))
+		}
+		return out
+	})
+	if !found {
+		g.AddEdge(from, to, &Attr{Key: key, Val: val})
+	}
+}
+
+// editEdges replaces each edge statement in stmts, and within subgraphs, that
+// holds an edge between from and to with the statements returned by fn. The
+// function fn is called with the statement, its terminals and edges, and
+// whether each edge joins from and to.
+func (g *Graph) editEdges(stmts []Stmt, from, to string, fn func(s *EdgeStmt, vs []Vertex, edges []*Edge, match []bool) []Stmt) []Stmt {
+	var out []Stmt
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *Subgraph:
+			s.Stmts = g.editEdges(s.Stmts, from, to, fn)
+		case *EdgeStmt:
+			vs, edges := chainOf(s)
+			for _, v := range vs {
+				if sub, ok := v.(*Subgraph); ok {
+					sub.Stmts = g.editEdges(sub.Stmts, from, to, fn)
+				}
+			}
+			match := make([]bool, len(edges))
+			var any bool
+			for i := range edges {
+				u, uok := vs[i].(*Node)
+				v, vok := vs[i+1].(*Node)
+				if !uok || !vok {
+					continue
+				}
+				uid, vid := Canonical(u.ID), Canonical(v.ID)
+				match[i] = (uid == from && vid == to) || (!g.Directed && uid == to && vid == from)
+				any = any || match[i]
+			}
+			if any {
+				out = append(out, fn(s, vs, edges, match)...)
+				continue
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// chainOf returns the terminals and edges of the edge statement s.
+func chainOf(s *EdgeStmt) ([]Vertex, []*Edge) {
+	vs := []Vertex{s.From}
+	var edges []*Edge
+	for e := s.To; e != nil; e = e.To {
+		vs = append(vs, e.Vertex)
+		edges = append(edges, e)
+	}
+	return vs, edges
+}
+
+// split returns the statements remaining from the edge statement s with
+// terminals vs and edges when the terminals marked by drop and the edges
+// marked by cut are removed. Runs of joined terminals are written as edge
+// statements with the attributes of s and lone terminals as node or
+// subgraph statements without attributes.
+func split(s *EdgeStmt, vs []Vertex, edges []*Edge, drop, cut []bool) []Stmt {
+	var out []Stmt
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		out = append(out, chainStmt(s, vs[start:end+1], edges[start:end]))
+		start = -1
+	}
+	for i := range vs {
+		if drop[i] {
+			flush(i - 1)
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		if i == len(edges) || cut[i] {
+			flush(i)
+		}
+	}
+	return out
+}
+
+// chainStmt returns a statement for the chain of terminals vs joined by the
+// given edges of the statement s. A chain with a single terminal is returned
+// as a node or subgraph statement.
+func chainStmt(s *EdgeStmt, vs []Vertex, edges []*Edge) Stmt {
+	if len(vs) == 1 {
+		switch v := vs[0].(type) {
+		case *Node:
+			return &NodeStmt{Node: v}
+		case *Subgraph:
+			return v
+		}
+		panic("ast: unknown vertex type")
+	}
+	c := &EdgeStmt{From: vs[0], Attrs: s.Attrs}
+	var last *Edge
+	for i, e := range edges {
+		n := &Edge{Pos: e.Pos, Directed: e.Directed, Vertex: vs[i+1]}
+		if last == nil {
+			c.To = n
+		} else {
+			last.To = n
+		}
+		last = n
+	}
+	return c
+}
+
+// SetNodeAttr sets the attribute key of the node with the given ID to val.
+// The attribute is changed in each node statement for the node that holds
+// it. If no statement holds it, it is appended to the first node statement
+// for the node or, if there is none, to a new node statement appended to the
+// graph.
+func (g *Graph) SetNodeAttr(id, key, val string) {
+	id = Canonical(id)
+	var (
+		first *NodeStmt
+		found bool
+	)
+	Inspect(g, func(e Element) bool {
+		s, ok := e.(*NodeStmt)
+		if !ok || Canonical(s.Node.ID) != id {
+			return true
+		}
+		if first == nil {
+			first = s
+		}
+		for _, a := range s.Attrs {
+			if Canonical(a.Key) == Canonical(key) {
+				a.Val = val
+				found = true
+			}
+		}
+		return true
+	})
+	switch {
+	case found:
+	case first != nil:
+		first.Attrs = append(first.Attrs, &Attr{Key: key, Val: val})
+	default:
+		g.AddNode(id, &Attr{Key: key, Val: val})
+	}
+}
+
+// SetAttr sets the graph attribute key to val. The attribute is changed in
+// each graph attribute assignment and graph attribute statement of the
+// graph, outside subgraphs, that holds it. If none holds it, an attribute
+// assignment is appended to the graph.
+func (g *Graph) SetAttr(key, val string) {
+	var found bool
+	set := func(a *Attr) {
+		if Canonical(a.Key) == Canonical(key) {
+			a.Val = val
+			found = true
+		}
+	}
+	for _, s := range g.Stmts {
+		switch s := s.(type) {
+		case *Attr:
+			set(s)
+		case *AttrStmt:
+			if s.Kind == GraphKind {
+				for _, a := range s.Attrs {
+					set(a)
+				}
+			}
+		}
+	}
+	if !found {
+		g.Stmts = append(g.Stmts, &Attr{Key: key, Val: val})
+	}
+}
+
+// setAttr sets the attribute key in attrs to val, appending it if absent.
+func setAttr(attrs []*Attr, key, val string) []*Attr {
+	for i, a := range attrs {
+		if Canonical(a.Key) == Canonical(key) {
+			c := *a
+			c.Val = val
+			attrs[i] = &c
+			return attrs
+		}
+	}
+	return append(attrs, &Attr{Key: key, Val: val})
+}
@@ -0,0 +1,42 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ast
+
+import (
+	"bytes"
+	"io"
+)
+
+// Config controls the formatting of syntax trees.
+type Config struct {
+	// Indent is the indentation of
+	// each level of statements. If
+	// empty, a tab is used.
+	Indent string
+}
+
+// Fprint writes the formatted DOT form of e to w. Graphs and statement
+// subgraphs are written with one statement on each line, indented by
+// c.Indent for each level of nesting. Subgraphs that are edge terminals
+// are written on a single line unless they hold comments.
+func (c Config) Fprint(w io.Writer, e Element) error {
+	indent := c.Indent
+	if indent == "" {
+		indent = "\t"
+	}
+	var buf bytes.Buffer
+	switch e := e.(type) {
+	case *File:
+		e.write(&buf, indent)
+	case *Graph:
+		e.write(&buf, indent)
+	case *Subgraph:
+		e.write(&buf, indent, "")
+	default:
+		buf.WriteString(e.String())
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
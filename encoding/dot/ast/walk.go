@@ -0,0 +1,91 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ast
+
+import "fmt"
+
+// Visitor visits the elements of a syntax tree. The Visit method is called
+// for each element encountered by Walk. If the result visitor w is not nil,
+// Walk visits each of the children of the element with the visitor w,
+// followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(e Element) (w Visitor)
+}
+
+// Walk traverses a syntax tree in depth-first order. It starts by calling
+// v.Visit(e), and then visits the children of e in source order, with
+// comments preceding a graph visited before the graph's statements.
+func Walk(v Visitor, e Element) {
+	if v = v.Visit(e); v == nil {
+		return
+	}
+	switch e := e.(type) {
+	case *File:
+		for _, g := range e.Graphs {
+			Walk(v, g)
+		}
+		for _, c := range e.Comments {
+			Walk(v, c)
+		}
+	case *Graph:
+		for _, c := range e.Comments {
+			Walk(v, c)
+		}
+		walkStmts(v, e.Stmts)
+	case *Subgraph:
+		walkStmts(v, e.Stmts)
+	case *NodeStmt:
+		Walk(v, e.Node)
+		walkAttrs(v, e.Attrs)
+	case *EdgeStmt:
+		Walk(v, e.From)
+		Walk(v, e.To)
+		walkAttrs(v, e.Attrs)
+	case *Edge:
+		Walk(v, e.Vertex)
+		if e.To != nil {
+			Walk(v, e.To)
+		}
+	case *AttrStmt:
+		walkAttrs(v, e.Attrs)
+	case *Node:
+		if e.Port != nil {
+			Walk(v, e.Port)
+		}
+	case *Attr, *Port, *Comment:
+		// Leaf elements.
+	default:
+		panic(fmt.Sprintf("ast: unexpected element type %T", e))
+	}
+	v.Visit(nil)
+}
+
+func walkStmts(v Visitor, stmts []Stmt) {
+	for _, s := range stmts {
+		Walk(v, s)
+	}
+}
+
+func walkAttrs(v Visitor, attrs []*Attr) {
+	for _, a := range attrs {
+		Walk(v, a)
+	}
+}
+
+type inspector func(Element) bool
+
+func (f inspector) Visit(e Element) Visitor {
+	if f(e) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a syntax tree in depth-first order. It starts by calling
+// f(e), and if f returns true, Inspect calls itself for each of the children
+// of e, followed by a call of f(nil).
+func Inspect(e Element, f func(Element) bool) {
+	Walk(inspector(f), e)
+}
@@ -0,0 +1,512 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot/ast"
+)
+
+// Builder is a graph that can have user-defined nodes and edges added.
+type Builder interface {
+	graph.Graph
+	graph.Builder
+
+	// NewNode returns a new node with
+	// a unique node ID for the graph.
+	NewNode() graph.Node
+
+	// NewEdge returns a new edge from
+	// the source to the destination
+	// node.
+	NewEdge(from, to graph.Node) graph.Edge
+}
+
+// DOTIDSetter is implemented by graph.Node values that can hold their DOT
+// node ID.
+type DOTIDSetter interface {
+	SetDOTID(id string)
+}
+
+// WeightSetter is implemented by graph.Edge values that can hold a weight
+// decoded from an edge attribute.
+type WeightSetter interface {
+	SetWeight(w float64)
+}
+
+// AttributeSetter is implemented by graph.Node and graph.Edge values that
+// can hold DOT attributes. SetAttribute should return an error if the
+// attribute is not recognised or its value is not valid.
+type AttributeSetter interface {
+	SetAttribute(Attribute) error
+}
+
+// DecodeOptions holds options for UnmarshalWith.
+type DecodeOptions struct {
+	// Preserve specifies that the decoder
+	// should return the Metadata needed
+	// to re-encode the graph in the form
+	// of the original source. Attributes
+	// that are rejected by the decoded
+	// nodes and edges are retained in the
+	// Metadata instead of causing an error.
+	Preserve bool
+
+	// Merge specifies how duplicate
+	// edges in a strict graph are
+	// handled.
+	Merge MergePolicy
+
+	// Weight specifies the edge
+	// attribute that holds edge
+	// weights. If Weight is not
+	// nil, the parsed value of the
+	// attribute is passed to edges
+	// implementing WeightSetter.
+	Weight *WeightAttribute
+
+	// Registry, if not nil, is used
+	// to obtain nodes by their DOT
+	// ID with quotes removed instead
+	// of creating them with NewNode.
+	// Nodes returned by Registry
+	// that are not in the destination
+	// are added to it.
+	Registry NodeRegistry
+}
+
+// NodeRegistry is a mapping from external keys to graph nodes, such as
+// simple.Registry.
+type NodeRegistry interface {
+	GetOrCreate(key string) graph.Node
+}
+
+// MergePolicy specifies how the attributes of duplicate edges in a strict DOT
+// graph are merged.
+type MergePolicy int
+
+const (
+	// MergeLast merges the attributes of a duplicate edge into the
+	// existing edge, with later values overriding earlier values.
+	// This is the behaviour of Graphviz.
+	MergeLast MergePolicy = iota
+
+	// MergeFirst ignores the attributes of a duplicate edge, keeping
+	// the attributes of the first edge.
+	MergeFirst
+
+	// MergeError causes decoding to fail when a duplicate edge is found.
+	MergeError
+)
+
+// Unmarshal parses the DOT encoding of a single graph in data and adds the
+// nodes and edges it describes to dst. Nodes are created by dst.NewNode
+// and edges by dst.NewEdge. The DOT IDs of nodes and the attributes of nodes
+// and edges are passed to nodes and edges implementing DOTIDSetter and
+// AttributeSetter, in the lexical form they have in data, so quoted strings
+// retain their quotes. Nodes and edges implementing graph.AttributeSetter but
+// not AttributeSetter are passed attributes with quotes removed.
+//
+// Default attributes given by node and edge attribute statements are applied
+// to the nodes and edges created after the statement in the same graph or
+// subgraph, or in subgraphs nested within it, before the attributes given
+// where the node or edge is declared. Attributes of the root graph, given by
+// graph attribute statements or ID=ID statements, are passed to dst if it
+// implements AttributeSetter or graph.AttributeSetter; graph attributes within subgraphs are ignored.
+//
+// If the DOT graph is strict, an edge that duplicates an edge already in dst
+// is not added. Instead its attributes are merged into the existing edge,
+// with later values overriding earlier values. UnmarshalWith allows the merge
+// policy to be specified. Duplicate edges in a graph that is not strict are
+// passed to dst.SetEdge.
+//
+// Unmarshal returns an error if the directedness of the DOT graph does not
+// match that of dst or if the DOT graph holds an edge from a node to itself,
+// since graph builders do not hold self edges.
+func Unmarshal(data []byte, dst Builder) error {
+	_, err := UnmarshalWith(data, dst, DecodeOptions{})
+	return err
+}
+
+// UnmarshalWith parses the DOT encoding of a single graph in data and adds the
+// nodes and edges it describes to dst in the same way as Unmarshal, using the
+// provided options. If opts.Preserve is true, the returned Metadata may be
+// used to re-encode the graph, otherwise it is nil.
+func UnmarshalWith(data []byte, dst Builder, opts DecodeOptions) (*Metadata, error) {
+	f, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Graphs) != 1 {
+		return nil, fmt.Errorf("dot: found %d graphs, expected one", len(f.Graphs))
+	}
+	g := f.Graphs[0]
+	if _, isDirected := dst.(graph.Directed); isDirected != g.Directed {
+		if g.Directed {
+			return nil, errors.New("dot: cannot decode directed graph into undirected destination")
+		}
+		return nil, errors.New("dot: cannot decode undirected graph into directed destination")
+	}
+
+	d := decoder{
+		dst:    dst,
+		nodes:  make(map[string]graph.Node),
+		opts:   opts,
+		strict: g.Strict,
+	}
+	if opts.Preserve {
+		d.meta = &Metadata{
+			file:      f,
+			ids:       make(map[int]string),
+			unknown:   make(map[*ast.Attr]bool),
+			inherited: make(map[key]map[string]string),
+			final:     make(map[key]map[string]*ast.Attr),
+			weight:    opts.Weight,
+			expanded:  make(map[*ast.EdgeStmt][][2]int),
+		}
+	}
+	err = d.stmts(g.Stmts, defaults{}, true)
+	if err != nil {
+		return nil, err
+	}
+	return d.meta, nil
+}
+
+type decoder struct {
+	dst    Builder
+	opts   DecodeOptions
+	strict bool
+
+	// nodes holds the decoded nodes
+	// keyed by canonical DOT ID.
+	nodes map[string]graph.Node
+
+	// members holds the nodes of the
+	// subgraph edge terminals being
+	// decoded.
+	members []*members
+
+	meta *Metadata
+}
+
+// defaults holds the default attributes in scope
+// for nodes and edges.
+type defaults struct {
+	node, edge []*ast.Attr
+}
+
+// stmts decodes the statements of a graph or subgraph using the given
+// default attributes. The root parameter specifies whether the statements
+// are those of the root graph.
+func (d *decoder) stmts(stmts []ast.Stmt, def defaults, root bool) error {
+	for _, s := range stmts {
+		var err error
+		switch s := s.(type) {
+		case *ast.NodeStmt:
+			var n graph.Node
+			n, err = d.node(s.Node.ID, def)
+			if err == nil {
+				err = d.setAttributes(n, s.Attrs)
+				d.record(key{from: n.ID(), to: n.ID()}, s.Attrs, false)
+			}
+		case *ast.EdgeStmt:
+			err = d.edge(s, def)
+		case *ast.AttrStmt:
+			switch s.Kind {
+			case ast.NodeKind:
+				// Slice to capacity so that appending in
+				// this scope does not alter the defaults
+				// of an enclosing scope.
+				def.node = append(def.node[:len(def.node):len(def.node)], s.Attrs...)
+			case ast.EdgeKind:
+				def.edge = append(def.edge[:len(def.edge):len(def.edge)], s.Attrs...)
+			case ast.GraphKind:
+				if root {
+					err = d.setAttributes(d.dst, s.Attrs)
+				}
+			}
+		case *ast.Attr:
+			if root {
+				err = d.setAttributes(d.dst, []*ast.Attr{s})
+			}
+		case *ast.Subgraph:
+			err = d.stmts(s.Stmts, def, false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// node returns the node with the given DOT ID, creating it with the
+// default node attributes if necessary.
+func (d *decoder) node(id string, def defaults) (graph.Node, error) {
+	cid := canonicalID(id)
+	if n, ok := d.nodes[cid]; ok {
+		d.mention(n)
+		return n, nil
+	}
+	var n graph.Node
+	if d.opts.Registry != nil {
+		n = d.opts.Registry.GetOrCreate(cid)
+	} else {
+		n = d.dst.NewNode()
+	}
+	d.mention(n)
+	if s, ok := n.(DOTIDSetter); ok {
+		s.SetDOTID(id)
+	}
+	err := d.setDefaults(key{from: n.ID(), to: n.ID()}, n, def.node)
+	if err != nil {
+		return nil, err
+	}
+	if !d.dst.Has(n) {
+		d.dst.AddNode(n)
+	}
+	d.nodes[cid] = n
+	if d.meta != nil {
+		d.meta.ids[n.ID()] = id
+	}
+	return n, nil
+}
+
+// mention records n as a member of the subgraphs being decoded
+// as edge terminals.
+func (d *decoder) mention(n graph.Node) {
+	for _, m := range d.members {
+		if !m.seen[n.ID()] {
+			m.seen[n.ID()] = true
+			m.nodes = append(m.nodes, n)
+		}
+	}
+}
+
+// members holds the nodes of a subgraph
+// in the order they are first mentioned.
+type members struct {
+	nodes []graph.Node
+	seen  map[int]bool
+}
+
+// edge adds the edges described by s. Subgraph terminals are decoded
+// and expanded to the nodes they contain, so a -> {b c} describes the
+// edges a -> b and a -> c.
+func (d *decoder) edge(s *ast.EdgeStmt, def defaults) error {
+	from, err := d.vertex(s.From, def)
+	if err != nil {
+		return err
+	}
+	expanded := d.meta != nil && chain(s) == nil
+	for to := s.To; to != nil; to = to.To {
+		nodes, err := d.vertex(to.Vertex, def)
+		if err != nil {
+			return err
+		}
+		for _, u := range from {
+			for _, v := range nodes {
+				err = d.setEdge(u, v, s.Attrs, def)
+				if err != nil {
+					return err
+				}
+				if expanded {
+					d.meta.expanded[s] = append(d.meta.expanded[s], [2]int{u.ID(), v.ID()})
+				}
+			}
+		}
+		from = nodes
+	}
+	return nil
+}
+
+// vertex returns the nodes described by the edge terminal v.
+func (d *decoder) vertex(v ast.Vertex, def defaults) ([]graph.Node, error) {
+	switch v := v.(type) {
+	case *ast.Node:
+		n, err := d.node(v.ID, def)
+		if err != nil {
+			return nil, err
+		}
+		return []graph.Node{n}, nil
+	case *ast.Subgraph:
+		m := &members{seen: make(map[int]bool)}
+		d.members = append(d.members, m)
+		err := d.stmts(v.Stmts, def, false)
+		d.members = d.members[:len(d.members)-1]
+		if err != nil {
+			return nil, err
+		}
+		return m.nodes, nil
+	default:
+		panic(fmt.Sprintf("dot: unknown vertex type %T", v))
+	}
+}
+
+// setEdge adds an edge from u to v with the given attributes to the
+// destination, merging it with an existing edge if the graph is strict.
+// Default edge attributes are applied only to new edges.
+func (d *decoder) setEdge(u, v graph.Node, attrs []*ast.Attr, def defaults) error {
+	if u.ID() == v.ID() {
+		return fmt.Errorf("dot: self edge on node %s", nodeID(u))
+	}
+	if d.strict {
+		if e := d.dst.Edge(u, v); e != nil {
+			switch d.opts.Merge {
+			case MergeLast:
+				err := d.setAttributes(e, attrs)
+				if err != nil {
+					return err
+				}
+				d.record(d.edgeKey(u, v), attrs, false)
+				return d.setWeight(e, attrs)
+			case MergeFirst:
+				return nil
+			case MergeError:
+				return fmt.Errorf("dot: duplicate edge from %s to %s in strict graph", nodeID(u), nodeID(v))
+			default:
+				panic("dot: invalid merge policy")
+			}
+		}
+	}
+	e := d.dst.NewEdge(u, v)
+	err := d.setDefaults(d.edgeKey(u, v), e, def.edge)
+	if err != nil {
+		return err
+	}
+	err = d.setWeight(e, def.edge)
+	if err != nil {
+		return err
+	}
+	err = d.setAttributes(e, attrs)
+	if err != nil {
+		return err
+	}
+	// The new edge replaces any previous edge, so its
+	// attributes come only from this statement.
+	d.record(d.edgeKey(u, v), attrs, true)
+	err = d.setWeight(e, attrs)
+	if err != nil {
+		return err
+	}
+	d.dst.SetEdge(e)
+	return nil
+}
+
+// edgeKey returns the key for an edge from u to v in the destination.
+func (d *decoder) edgeKey(u, v graph.Node) key {
+	_, isDirected := d.dst.(graph.Directed)
+	return edgeKey(isDirected, u.ID(), v.ID())
+}
+
+// record records attrs in the metadata as the source of the current
+// values of their keys for the node or edge with key k if the source is
+// being preserved. If reset is true, sources recorded earlier for k are
+// discarded.
+func (d *decoder) record(k key, attrs []*ast.Attr, reset bool) {
+	if d.meta == nil {
+		return
+	}
+	final := d.meta.final[k]
+	if final == nil || reset {
+		final = make(map[string]*ast.Attr)
+		d.meta.final[k] = final
+	}
+	for _, a := range attrs {
+		final[a.Key] = a
+	}
+}
+
+// setAttributes passes attrs to v if it is an AttributeSetter or a
+// graph.AttributeSetter. Rejected attributes are recorded in the metadata
+// if the source is being preserved.
+func (d *decoder) setAttributes(v interface{}, attrs []*ast.Attr) error {
+	s, ok := attributeSetter(v)
+	for _, a := range attrs {
+		if !ok {
+			if d.meta != nil {
+				d.meta.unknown[a] = true
+			}
+			continue
+		}
+		err := s.SetAttribute(Attribute{Key: a.Key, Value: a.Val})
+		if err != nil {
+			if d.meta == nil {
+				return err
+			}
+			d.meta.unknown[a] = true
+		}
+	}
+	return nil
+}
+
+// setWeight passes the weight held by the weight attribute in attrs to e
+// if weights are being decoded and e is a WeightSetter.
+func (d *decoder) setWeight(e graph.Edge, attrs []*ast.Attr) error {
+	w := d.opts.Weight
+	if w == nil {
+		return nil
+	}
+	s, ok := e.(WeightSetter)
+	if !ok {
+		return nil
+	}
+	for _, a := range attrs {
+		if canonicalID(a.Key) != w.Key {
+			continue
+		}
+		v, err := w.parse(canonicalID(a.Val))
+		if err != nil {
+			return fmt.Errorf("dot: invalid %s attribute %s: %v", w.Key, a.Val, err)
+		}
+		s.SetWeight(v)
+	}
+	return nil
+}
+
+// setDefaults passes the default attributes to v, recording them in the
+// metadata as inherited by the node or edge with key k if the source is
+// being preserved.
+func (d *decoder) setDefaults(k key, v interface{}, def []*ast.Attr) error {
+	err := d.setAttributes(v, def)
+	if err != nil || d.meta == nil || len(def) == 0 {
+		return err
+	}
+	inherited := make(map[string]string)
+	for _, a := range def {
+		inherited[a.Key] = a.Val
+	}
+	d.meta.inherited[k] = inherited
+	return nil
+}
+
+// attributeSetter returns an AttributeSetter for v if it implements
+// AttributeSetter or graph.AttributeSetter.
+func attributeSetter(v interface{}) (AttributeSetter, bool) {
+	switch v := v.(type) {
+	case AttributeSetter:
+		return v, true
+	case graph.AttributeSetter:
+		return unquotingSetter{v}, true
+	default:
+		return nil, false
+	}
+}
+
+// unquotingSetter is an AttributeSetter that passes attributes to a
+// graph.AttributeSetter in their canonical form.
+type unquotingSetter struct {
+	graph.AttributeSetter
+}
+
+func (s unquotingSetter) SetAttribute(a Attribute) error {
+	return s.AttributeSetter.SetAttribute(graph.Attribute{Key: canonicalID(a.Key), Value: canonicalID(a.Value)})
+}
+
+// canonicalID returns the canonical form of a DOT ID, removing the quotes
+// and escaped quotes and line continuations of quoted strings.
+func canonicalID(id string) string { return ast.Canonical(id) }
@@ -0,0 +1,887 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// dotNode is a node that holds its DOT ID and attributes.
+// Attributes with keys starting with "x_" are rejected.
+type dotNode struct {
+	id    int
+	dotID string
+	attrs []Attribute
+}
+
+func (n *dotNode) ID() int                    { return n.id }
+func (n *dotNode) DOTID() string              { return n.dotID }
+func (n *dotNode) SetDOTID(id string)         { n.dotID = id }
+func (n *dotNode) DOTAttributes() []Attribute { return n.attrs }
+func (n *dotNode) SetAttribute(a Attribute) error {
+	return setAttr(&n.attrs, a)
+}
+
+// dotEdge is an edge that holds its DOT attributes.
+type dotEdge struct {
+	from, to graph.Node
+	attrs    []Attribute
+}
+
+func (e *dotEdge) From() graph.Node           { return e.from }
+func (e *dotEdge) To() graph.Node             { return e.to }
+func (e *dotEdge) Weight() float64            { return 1 }
+func (e *dotEdge) DOTAttributes() []Attribute { return e.attrs }
+func (e *dotEdge) SetAttribute(a Attribute) error {
+	return setAttr(&e.attrs, a)
+}
+
+func setAttr(attrs *[]Attribute, a Attribute) error {
+	if strings.HasPrefix(a.Key, "x_") {
+		return fmt.Errorf("unknown attribute %q", a.Key)
+	}
+	for i, o := range *attrs {
+		if o.Key == a.Key {
+			(*attrs)[i] = a
+			return nil
+		}
+	}
+	*attrs = append(*attrs, a)
+	return nil
+}
+
+type dotDirectedGraph struct {
+	*simple.DirectedGraph
+}
+
+func newDotDirectedGraph() dotDirectedGraph {
+	return dotDirectedGraph{simple.NewDirectedGraph(0, math.Inf(1))}
+}
+
+func (g dotDirectedGraph) NewNode() graph.Node { return &dotNode{id: g.NewNodeID()} }
+func (g dotDirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &dotEdge{from: from, to: to}
+}
+
+type dotUndirectedGraph struct {
+	*simple.UndirectedGraph
+}
+
+func newDotUndirectedGraph() dotUndirectedGraph {
+	return dotUndirectedGraph{simple.NewUndirectedGraph(0, math.Inf(1))}
+}
+
+func (g dotUndirectedGraph) NewNode() graph.Node { return &dotNode{id: g.NewNodeID()} }
+func (g dotUndirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &dotEdge{from: from, to: to}
+}
+
+// describe returns a canonical description of the nodes
+// and edges of g using DOT IDs and attributes.
+func describe(g graph.Graph) string {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var lines []string
+	for _, u := range nodes {
+		n := u.(*dotNode)
+		lines = append(lines, fmt.Sprintf("%s %v", n.dotID, n.attrs))
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			e := g.Edge(u, v).(*dotEdge)
+			lines = append(lines, fmt.Sprintf("%s>%s %v", n.dotID, v.(*dotNode).dotID, e.attrs))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var parseTests = []string{
+	`digraph {}`,
+	`strict graph G {
+	a -- b -- c [color=red];
+	subgraph cluster_0 {
+		label="x";
+		d;
+	};
+	{e f} -- g;
+	node [shape=box];
+	h:p:n -- i:s;
+}`,
+	`// leading
+digraph "quoted \" ID" {
+	/* block
+	   comment */
+	a -> b [label=<<b>bold</b>>];
+	# not a line start, but a comment here
+	c;
+}
+// trailing`,
+	`graph {
+	node [];
+	edge [];
+	graph [];
+	a [];
+}`,
+}
+
+func TestParse(t *testing.T) {
+	for i, src := range parseTests {
+		f, err := Parse([]byte(src))
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		got := f.String()
+		f2, err := Parse([]byte(got))
+		if err != nil {
+			t.Errorf("unexpected error reparsing test %d: %v\n%s", i, err, got)
+			continue
+		}
+		if again := f2.String(); again != got {
+			t.Errorf("unstable formatting for test %d:\ngot:\n%s\nwant:\n%s", i, again, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want string
+	}{
+		{src: ``, want: "no graph"},
+		{src: `digraph { a -- b }`, want: "1:13: undirected edge in directed graph"},
+		{src: `graph {
+	a -> b
+}`, want: "2:4: directed edge in undirected graph"},
+		{src: `graph { a = }`, want: `1:13: unexpected '}', expected ID`},
+		{src: `graph { "a }`, want: "unterminated string"},
+		{src: `graph { a [b=c }`, want: `unexpected '}', expected ID`},
+	} {
+		_, err := Parse([]byte(test.src))
+		if err == nil {
+			t.Errorf("expected error for %q", test.src)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("unexpected error for %q: got:%v want:%s", test.src, err, test.want)
+		}
+	}
+}
+
+func TestParseErrorList(t *testing.T) {
+	const src = `digraph {
+	a -> ;
+	b [color=];
+	c -- d;
+	e @ f;
+	subgraph { g -> }
+}`
+	want := ErrorList{
+		{Pos: Pos{Line: 2, Column: 7}, Msg: "unexpected ';', expected node or subgraph", Expected: []string{"node", "subgraph"}, Lit: ";"},
+		{Pos: Pos{Line: 3, Column: 11}, Msg: "unexpected ']', expected ID", Expected: []string{"ID"}, Lit: "]"},
+		{Pos: Pos{Line: 4, Column: 4}, Msg: "undirected edge in directed graph", Expected: []string{"'->'"}, Lit: "--"},
+		{Pos: Pos{Line: 5, Column: 4}, Msg: "unexpected character '@'", Lit: "@"},
+		{Pos: Pos{Line: 6, Column: 18}, Msg: "unexpected '}', expected node or subgraph", Expected: []string{"node", "subgraph"}, Lit: "}"},
+	}
+	_, err := Parse([]byte(src))
+	got, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("unexpected error type: got:%T want:%T", err, ErrorList(nil))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected errors:\ngot: %v\nwant:%v", got, want)
+		for _, e := range got {
+			t.Logf("%#v", e)
+		}
+	}
+	if got, want := err.Error(), "dot: 2:7: unexpected ';', expected node or subgraph (and 4 more errors)"; got != want {
+		t.Errorf("unexpected error text: got:%q want:%q", got, want)
+	}
+
+	b, err := json.Marshal(got[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantJSON := `{"pos":{"line":2,"column":7},"msg":"unexpected ';', expected node or subgraph","expected":["node","subgraph"],"lit":";"}`
+	if string(b) != wantJSON {
+		t.Errorf("unexpected JSON: got:%s want:%s", b, wantJSON)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	const src = `digraph {
+	a [color=red];
+	a -> b -> "c" [style=bold];
+	subgraph s {
+		c -> a;
+	}
+	d;
+}`
+	g := newDotDirectedGraph()
+	err := Unmarshal([]byte(src), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `a [{color red}]
+a>b [{style bold}]
+b []
+b>"c" [{style bold}]
+"c" []
+"c">a []
+d []`
+	if got := describe(g); got != want {
+		t.Errorf("unexpected decoded graph:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	err = Unmarshal([]byte(src), newDotUndirectedGraph())
+	if err == nil {
+		t.Errorf("expected error for directedness mismatch")
+	}
+	err = Unmarshal([]byte(`graph { a [x_y=1] }`), newDotUndirectedGraph())
+	if err == nil {
+		t.Errorf("expected error for rejected attribute")
+	}
+	err = Unmarshal([]byte(`digraph { a -> a }`), newDotDirectedGraph())
+	if err == nil {
+		t.Errorf("expected error for self edge")
+	}
+	err = Unmarshal([]byte(`strict graph { a -- b; {b c} -- b }`), newDotUndirectedGraph())
+	if err == nil {
+		t.Errorf("expected error for self edge from subgraph")
+	}
+}
+
+func TestPreserve(t *testing.T) {
+	const src = `// A small graph.
+digraph G {
+	rankdir=LR;
+	node [shape=box];
+	// The start node.
+	a [color=red x_note="keep me"];
+	a -> b -> c [style=bold];
+	d -> c;
+	c;
+}
+`
+	g := newDotDirectedGraph()
+	m, err := UnmarshalWith([]byte(src), g, DecodeOptions{Preserve: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `// A small graph.
+digraph G {
+	rankdir=LR;
+	node [shape=box];
+	// The start node.
+	a [color=red x_note="keep me"];
+	a -> b -> c [style=bold];
+	d -> c;
+	c;
+}
+`
+	if got := string(b); got != want {
+		t.Errorf("unexpected unmodified round trip:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	nodes := make(map[string]*dotNode)
+	for _, n := range g.Nodes() {
+		nodes[n.(*dotNode).dotID] = n.(*dotNode)
+	}
+	nodes["a"].SetAttribute(Attribute{Key: "color", Value: "blue"})
+	nodes["a"].SetAttribute(Attribute{Key: "label", Value: `"A"`})
+	g.Edge(nodes["b"], nodes["c"]).(*dotEdge).SetAttribute(Attribute{Key: "style", Value: "dashed"})
+	g.RemoveNode(nodes["d"])
+	e := g.NewEdge(nodes["c"], nodes["a"]).(*dotEdge)
+	e.SetAttribute(Attribute{Key: "weight", Value: "2"})
+	g.SetEdge(e)
+	n := g.NewNode().(*dotNode)
+	n.SetDOTID("e")
+	g.AddNode(n)
+
+	b, err = m.Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = `// A small graph.
+digraph G {
+	rankdir=LR;
+	node [shape=box];
+	// The start node.
+	a [color=blue x_note="keep me" label="A"];
+	a -> b [style=bold];
+	b -> c [style=dashed];
+	c;
+	e;
+	c -> a [weight=2];
+}
+`
+	if got := string(b); got != want {
+		t.Errorf("unexpected modified round trip:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	const src = `%sgraph {
+	a -- b [color=red weight=1];
+	b -- a [color=blue];
+}`
+	for _, test := range []struct {
+		strict bool
+		merge  MergePolicy
+		want   string
+	}{
+		{
+			strict: true, merge: MergeLast,
+			want: `a []
+a>b [{color blue} {weight 1}]
+b []
+b>a [{color blue} {weight 1}]`,
+		},
+		{
+			strict: true, merge: MergeFirst,
+			want: `a []
+a>b [{color red} {weight 1}]
+b []
+b>a [{color red} {weight 1}]`,
+		},
+		{
+			strict: true, merge: MergeError,
+			want: "dot: duplicate edge from b to a in strict graph",
+		},
+		{
+			strict: false, merge: MergeError,
+			want: `a []
+a>b [{color blue}]
+b []
+b>a [{color blue}]`,
+		},
+	} {
+		var prefix string
+		if test.strict {
+			prefix = "strict "
+		}
+		g := newDotUndirectedGraph()
+		_, err := UnmarshalWith([]byte(fmt.Sprintf(src, prefix)), g, DecodeOptions{Merge: test.merge})
+		var got string
+		if err != nil {
+			got = err.Error()
+		} else {
+			got = describe(g)
+		}
+		if got != test.want {
+			t.Errorf("unexpected result for strict=%t merge=%d:\ngot:\n%s\nwant:\n%s",
+				test.strict, test.merge, got, test.want)
+		}
+	}
+}
+
+// dotAttrGraph is a directed graph that holds its graph attributes.
+type dotAttrGraph struct {
+	dotDirectedGraph
+	attrs []Attribute
+}
+
+func (g *dotAttrGraph) SetAttribute(a Attribute) error {
+	return setAttr(&g.attrs, a)
+}
+
+func TestUnmarshalDefaults(t *testing.T) {
+	const src = `digraph {
+	rankdir=LR;
+	node [shape=box];
+	edge [color=red];
+	a -> b;
+	subgraph s {
+		graph [label=sub];
+		node [shape=circle color=blue];
+		edge [style=bold];
+		c [shape=point];
+		a -> c;
+		subgraph t {
+			node [color=green];
+			d;
+		}
+		e;
+	}
+	f;
+	graph [bgcolor=grey];
+	b -> f [color=black];
+}`
+	g := &dotAttrGraph{dotDirectedGraph: newDotDirectedGraph()}
+	err := Unmarshal([]byte(src), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `a [{shape box}]
+a>b [{color red}]
+a>c [{color red} {style bold}]
+b [{shape box}]
+b>f [{color black}]
+c [{shape point} {color blue}]
+d [{shape circle} {color green}]
+e [{shape circle} {color blue}]
+f [{shape box}]`
+	if got := describe(g); got != want {
+		t.Errorf("unexpected decoded graph:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	wantGraph := []Attribute{{Key: "rankdir", Value: "LR"}, {Key: "bgcolor", Value: "grey"}}
+	if !reflect.DeepEqual(g.attrs, wantGraph) {
+		t.Errorf("unexpected graph attributes: got:%v want:%v", g.attrs, wantGraph)
+	}
+}
+
+func TestUnmarshalSubgraphTerminals(t *testing.T) {
+	const src = `digraph {
+	a -> {b c} -> d;
+	{e; f [color=blue]} -> g [color=red];
+	h -> subgraph s {i -> j};
+}`
+	g := newDotDirectedGraph()
+	err := Unmarshal([]byte(src), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `a []
+a>b []
+a>c []
+b []
+b>d []
+c []
+c>d []
+d []
+e []
+e>g [{color red}]
+f [{color blue}]
+f>g [{color red}]
+g []
+h []
+h>i []
+h>j []
+i []
+i>j []
+j []`
+	if got := describe(g); got != want {
+		t.Errorf("unexpected decoded graph:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPreserveSubgraphTerminals(t *testing.T) {
+	const src = `digraph {
+	a -> {b; c} -> d;
+	h -> {i -> j};
+}
+`
+	g := newDotDirectedGraph()
+	m, err := UnmarshalWith([]byte(src), g, DecodeOptions{Preserve: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(b); got != src {
+		t.Errorf("unexpected unmodified round trip:\ngot:\n%s\nwant:\n%s", got, src)
+	}
+
+	nodes := make(map[string]*dotNode)
+	for _, n := range g.Nodes() {
+		nodes[n.(*dotNode).dotID] = n.(*dotNode)
+	}
+	g.RemoveNode(nodes["c"])
+	g.RemoveEdge(g.Edge(nodes["h"], nodes["j"]))
+	b, err = m.Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `digraph {
+	a -> {b} -> d;
+	{
+		i -> j;
+	};
+	h -> i;
+}
+`
+	if got := string(b); got != want {
+		t.Errorf("unexpected modified round trip:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Removing every node of a subgraph terminal
+	// drops the subgraph. The node e remains but
+	// its statement is gone, so it is appended.
+	const emptied = `digraph {
+	b -> {c d};
+	e -> {f};
+}
+`
+	g = newDotDirectedGraph()
+	m, err = UnmarshalWith([]byte(emptied), g, DecodeOptions{Preserve: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nodes = make(map[string]*dotNode)
+	for _, n := range g.Nodes() {
+		nodes[n.(*dotNode).dotID] = n.(*dotNode)
+	}
+	g.RemoveNode(nodes["c"])
+	g.RemoveNode(nodes["f"])
+	b, err = m.Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = `digraph {
+	b -> {d};
+	e;
+}
+`
+	if got := string(b); got != want {
+		t.Errorf("unexpected round trip with emptied subgraph:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPreserveDuplicates(t *testing.T) {
+	const src = `strict digraph {
+	a [color=red];
+	a [color=green];
+	a -> b [color=red];
+	a -> b [style=dashed];
+	a -> b [color=blue];
+}
+`
+	for _, test := range []struct {
+		merge MergePolicy
+		edit  func(n *dotNode, e *dotEdge)
+		want  string
+	}{
+		{
+			merge: MergeLast,
+			want:  src,
+		},
+		{
+			merge: MergeFirst,
+			want:  src,
+		},
+		{
+			merge: MergeLast,
+			edit: func(n *dotNode, e *dotEdge) {
+				n.SetAttribute(Attribute{Key: "color", Value: "black"})
+				e.SetAttribute(Attribute{Key: "color", Value: "white"})
+				e.SetAttribute(Attribute{Key: "style", Value: "bold"})
+			},
+			want: `strict digraph {
+	a [color=red];
+	a [color=black];
+	a -> b [color=red];
+	a -> b [style=bold];
+	a -> b [color=white];
+}
+`,
+		},
+		{
+			// Duplicates after the first are
+			// ignored and keep their values.
+			merge: MergeFirst,
+			edit: func(n *dotNode, e *dotEdge) {
+				e.SetAttribute(Attribute{Key: "color", Value: "white"})
+			},
+			want: `strict digraph {
+	a [color=red];
+	a [color=green];
+	a -> b [color=white];
+	a -> b [style=dashed];
+	a -> b [color=blue];
+}
+`,
+		},
+	} {
+		g := newDotDirectedGraph()
+		m, err := UnmarshalWith([]byte(src), g, DecodeOptions{Preserve: true, Merge: test.merge})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if test.edit != nil {
+			nodes := make(map[string]*dotNode)
+			for _, n := range g.Nodes() {
+				nodes[n.(*dotNode).dotID] = n.(*dotNode)
+			}
+			test.edit(nodes["a"], g.Edge(nodes["a"], nodes["b"]).(*dotEdge))
+		}
+		b, err := m.Marshal(g)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := string(b); got != test.want {
+			t.Errorf("unexpected round trip for merge policy %d:\ngot:\n%s\nwant:\n%s", test.merge, got, test.want)
+		}
+	}
+}
+
+// genNode and genEdge hold encoding-independent attributes.
+type genNode struct {
+	id    int
+	attrs []graph.Attribute
+}
+
+func (n *genNode) ID() int                       { return n.id }
+func (n *genNode) Attributes() []graph.Attribute { return n.attrs }
+func (n *genNode) SetAttribute(a graph.Attribute) error {
+	n.attrs = append(n.attrs, a)
+	return nil
+}
+
+type genEdge struct {
+	from, to graph.Node
+	attrs    []graph.Attribute
+}
+
+func (e *genEdge) From() graph.Node              { return e.from }
+func (e *genEdge) To() graph.Node                { return e.to }
+func (e *genEdge) Weight() float64               { return 1 }
+func (e *genEdge) Attributes() []graph.Attribute { return e.attrs }
+func (e *genEdge) SetAttribute(a graph.Attribute) error {
+	e.attrs = append(e.attrs, a)
+	return nil
+}
+
+type genGraph struct {
+	*simple.UndirectedGraph
+	attrs []graph.Attribute
+}
+
+func (g *genGraph) NewNode() graph.Node { return &genNode{id: g.NewNodeID()} }
+func (g *genGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &genEdge{from: from, to: to}
+}
+func (g *genGraph) Attributes() []graph.Attribute { return g.attrs }
+func (g *genGraph) SetAttribute(a graph.Attribute) error {
+	g.attrs = append(g.attrs, a)
+	return nil
+}
+
+func TestGraphAttributes(t *testing.T) {
+	g := &genGraph{
+		UndirectedGraph: simple.NewUndirectedGraph(0, math.Inf(1)),
+		attrs:           []graph.Attribute{{Key: "label", Value: "a graph"}},
+	}
+	u := &genNode{id: 0, attrs: []graph.Attribute{{Key: "label", Value: `say "hi"`}, {Key: "width", Value: "1.5"}}}
+	v := &genNode{id: 1, attrs: []graph.Attribute{{Key: "shape", Value: "node"}}}
+	g.AddNode(u)
+	g.AddNode(v)
+	g.SetEdge(&genEdge{from: u, to: v, attrs: []graph.Attribute{{Key: "color", Value: "red"}}})
+
+	b, err := Marshal(g, "", "", "\t", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `graph {
+	graph [
+		label="a graph"
+	];
+
+	// Node definitions.
+	0 [
+		label="say \"hi\""
+		width=1.5
+	];
+	1 [shape="node"];
+
+	// Edge definitions.
+	0 -- 1 [color=red];
+}`
+	if string(b) != want {
+		t.Errorf("unexpected DOT encoding:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+
+	dst := &genGraph{UndirectedGraph: simple.NewUndirectedGraph(0, math.Inf(1))}
+	err = Unmarshal(b, dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst.attrs, g.attrs) {
+		t.Errorf("unexpected graph attributes: got:%v want:%v", dst.attrs, g.attrs)
+	}
+	nodes := dst.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for i, want := range []*genNode{u, v} {
+		if got := nodes[i].(*genNode).attrs; !reflect.DeepEqual(got, want.attrs) {
+			t.Errorf("unexpected attributes for node %d: got:%v want:%v", i, got, want.attrs)
+		}
+	}
+	e := dst.Edge(nodes[0], nodes[1]).(*genEdge)
+	if wantAttrs := []graph.Attribute{{Key: "color", Value: "red"}}; !reflect.DeepEqual(e.attrs, wantAttrs) {
+		t.Errorf("unexpected edge attributes: got:%v want:%v", e.attrs, wantAttrs)
+	}
+}
+
+func TestMarshalBackslash(t *testing.T) {
+	g := &genGraph{UndirectedGraph: simple.NewUndirectedGraph(0, math.Inf(1))}
+	values := []string{`C:\dir\`, `a\"b`, `a\\`, `a\b`}
+	for i, v := range values {
+		g.AddNode(&genNode{id: i, attrs: []graph.Attribute{{Key: "label", Value: v}}})
+	}
+
+	b, err := Marshal(g, "", "", "\t", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dst := &genGraph{UndirectedGraph: simple.NewUndirectedGraph(0, math.Inf(1))}
+	err = Unmarshal(b, dst)
+	if err != nil {
+		t.Fatalf("unexpected error parsing marshaled DOT: %v\n%s", err, b)
+	}
+	nodes := dst.Nodes()
+	if len(nodes) != len(values) {
+		t.Fatalf("unexpected number of nodes: got:%d want:%d\n%s", len(nodes), len(values), b)
+	}
+	// Values without backslashes before a quote or
+	// at the end are unchanged by the round trip.
+	sort.Sort(ordered.ByID(nodes))
+	if got := nodes[3].(*genNode).attrs[0].Value; got != values[3] {
+		t.Errorf("unexpected label: got:%q want:%q", got, values[3])
+	}
+}
+
+// weightEdge is an edge that can hold a decoded weight.
+type weightEdge struct {
+	dotEdge
+	w float64
+}
+
+func (e *weightEdge) Weight() float64     { return e.w }
+func (e *weightEdge) SetWeight(w float64) { e.w = w }
+
+type weightGraph struct {
+	dotDirectedGraph
+}
+
+func (g weightGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &weightEdge{dotEdge: dotEdge{from: from, to: to}, w: 1}
+}
+
+func TestWeightAttribute(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 2.5})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: -1})
+	b, err := MarshalWith(g, "", "", "\t", false, EncodeOptions{Weight: &WeightAttribute{Key: "len"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"0 -- 1 [len=2.5];", "1 -- 2 [len=-1];"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("missing %q in output:\n%s", want, b)
+		}
+	}
+
+	const src = `digraph {
+	edge [capacity=3];
+	a -> b;
+	b -> c [capacity="1.50"];
+	c -> d [capacity=2 label=x];
+}
+`
+	capacity := &WeightAttribute{Key: "capacity"}
+	dst := weightGraph{newDotDirectedGraph()}
+	m, err := UnmarshalWith([]byte(src), dst, DecodeOptions{Preserve: true, Weight: capacity})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nodes := make(map[string]graph.Node)
+	for _, n := range dst.Nodes() {
+		nodes[n.(*dotNode).dotID] = n
+	}
+	for _, test := range []struct {
+		from, to string
+		want     float64
+	}{
+		{from: "a", to: "b", want: 3},
+		{from: "b", to: "c", want: 1.5},
+		{from: "c", to: "d", want: 2},
+	} {
+		e := dst.Edge(nodes[test.from], nodes[test.to])
+		if w := e.Weight(); w != test.want {
+			t.Errorf("unexpected weight for %s -> %s: got:%v want:%v", test.from, test.to, w, test.want)
+		}
+	}
+
+	b, err = m.Marshal(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != src {
+		t.Errorf("unexpected unmodified round trip:\ngot:\n%s\nwant:\n%s", b, src)
+	}
+	dst.Edge(nodes["b"], nodes["c"]).(*weightEdge).SetWeight(4)
+	dst.Edge(nodes["a"], nodes["b"]).(*weightEdge).SetWeight(0.5)
+	b, err = m.Marshal(dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `digraph {
+	edge [capacity=3];
+	a -> b [capacity=0.5];
+	b -> c [capacity=4];
+	c -> d [capacity=2 label=x];
+}
+`
+	if string(b) != want {
+		t.Errorf("unexpected modified round trip:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+
+	_, err = UnmarshalWith([]byte(`digraph { a -> b [capacity=wide] }`), weightGraph{newDotDirectedGraph()}, DecodeOptions{Weight: capacity})
+	if err == nil {
+		t.Errorf("expected error for invalid weight")
+	}
+}
+
+func TestUnmarshalRegistry(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	r := simple.NewRegistry(g)
+	existing := r.GetOrCreate("b c")
+
+	_, err := UnmarshalWith([]byte(`digraph { a -> "b c" -> node_1 }`), keyedGraph{g}, DecodeOptions{Registry: r})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Len() != 3 {
+		t.Errorf("unexpected number of registered nodes: got:%d want:3", r.Len())
+	}
+	a, _ := r.Node("a")
+	if !g.HasEdgeFromTo(a, existing) {
+		t.Errorf("edge not added to registered node")
+	}
+
+	b, err := Marshal(g, "", "", "\t", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{`a -> "b c";`, `"b c" -> node_1;`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("missing %q in output:\n%s", want, b)
+		}
+	}
+}
+
+type keyedGraph struct {
+	*simple.DirectedGraph
+}
+
+func (g keyedGraph) NewNode() graph.Node { panic("dot: unexpected call to NewNode") }
+func (g keyedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return simple.Edge{F: from, T: to, W: 1}
+}
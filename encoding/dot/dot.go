@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/gonum/graph"
@@ -90,10 +91,70 @@ type Subgrapher interface {
 // however, advanced GraphViz DOT features provided by Marshal depend on
 // implementation of the Node, Attributer, Porter, Attributers, Structurer,
 // Subgrapher and Graph interfaces.
+//
+// Nodes that do not implement Node but have a Key() string method, such as
+// simple.KeyedNode, are written with their key as the DOT ID, quoted as
+// needed.
+//
+// Nodes and edges implementing graph.Attributer but not Attributer have
+// their attributes written with values quoted as needed, as do graphs
+// implementing graph.Attributer but not Attributers.
 func Marshal(g graph.Graph, name, prefix, indent string, strict bool) ([]byte, error) {
+	return MarshalWith(g, name, prefix, indent, strict, EncodeOptions{})
+}
+
+// EncodeOptions holds options for MarshalWith.
+type EncodeOptions struct {
+	// Weight specifies the edge
+	// attribute that holds edge
+	// weights. If Weight is nil,
+	// weights are not written.
+	Weight *WeightAttribute
+}
+
+// WeightAttribute maps a DOT edge attribute to edge weights.
+type WeightAttribute struct {
+	// Key is the attribute key,
+	// for example "weight" or "len".
+	Key string
+
+	// Format returns the attribute
+	// value for a weight. If Format
+	// is nil, the weight is written
+	// in the shortest representation
+	// that represents it exactly.
+	Format func(w float64) string
+
+	// Parse returns the weight for an
+	// attribute value with quotes
+	// removed. If Parse is nil,
+	// strconv.ParseFloat is used.
+	Parse func(s string) (float64, error)
+}
+
+func (w *WeightAttribute) format(v float64) string {
+	if w.Format != nil {
+		return w.Format(v)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (w *WeightAttribute) parse(s string) (float64, error) {
+	if w.Parse != nil {
+		return w.Parse(s)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// MarshalWith returns the DOT encoding for the graph g in the same way as
+// Marshal, using the provided options. If opts.Weight is not nil, the weight
+// of each edge is written as the attribute with key opts.Weight.Key,
+// replacing any attribute of the edge with the same key.
+func MarshalWith(g graph.Graph, name, prefix, indent string, strict bool, opts EncodeOptions) ([]byte, error) {
 	var p printer
 	p.indent = indent
 	p.prefix = prefix
+	p.weight = opts.Weight
 	p.visited = make(map[edge]bool)
 	if strict {
 		p.buf.WriteString("strict ")
@@ -114,6 +175,10 @@ type printer struct {
 
 	visited map[edge]bool
 
+	// weight specifies the attribute
+	// holding edge weights, if any.
+	weight *WeightAttribute
+
 	err error
 }
 
@@ -153,6 +218,8 @@ func (p *printer) print(g graph.Graph, name string, needsIndent, isSubgraph bool
 	p.openBlock(" {")
 	if a, ok := g.(Attributers); ok {
 		p.writeAttributeComplex(a)
+	} else if a, ok := g.(graph.Attributer); ok {
+		p.writeAttributeComplex(graphAttributes{a})
 	}
 	if s, ok := g.(Structurer); ok {
 		for _, g := range s.Structure() {
@@ -193,7 +260,7 @@ func (p *printer) print(g graph.Graph, name string, needsIndent, isSubgraph bool
 		}
 		p.newline()
 		p.writeNode(n)
-		if a, ok := n.(Attributer); ok {
+		if a, ok := dotAttributer(n); ok {
 			p.writeAttributeList(a)
 		}
 		p.buf.WriteByte(';')
@@ -261,7 +328,7 @@ func (p *printer) print(g graph.Graph, name string, needsIndent, isSubgraph bool
 				p.writePorts(e.ToPort())
 			}
 
-			if a, ok := g.Edge(n, t).(Attributer); ok {
+			if a, ok := edgeAttributer(g.Edge(n, t), p.weight); ok {
 				p.writeAttributeList(a)
 			}
 
@@ -288,10 +355,18 @@ func (p *printer) writePorts(port, cp string) {
 	}
 }
 
+// keyer is implemented by nodes with an external string key,
+// such as simple.KeyedNode.
+type keyer interface {
+	Key() string
+}
+
 func nodeID(n graph.Node) string {
 	switch n := n.(type) {
 	case Node:
 		return n.DOTID()
+	case keyer:
+		return quoteID(n.Key())
 	default:
 		return fmt.Sprint(n.ID())
 	}
@@ -306,6 +381,158 @@ func graphID(g graph.Graph, n graph.Node) string {
 	}
 }
 
+// dotAttributer returns the DOT attributes of v if it implements Attributer
+// or graph.Attributer.
+func dotAttributer(v interface{}) (Attributer, bool) {
+	switch v := v.(type) {
+	case Attributer:
+		return v, true
+	case graph.Attributer:
+		return quotedAttributes{v}, true
+	default:
+		return nil, false
+	}
+}
+
+// edgeAttributer returns the DOT attributes of the edge e as returned by
+// dotAttributer, with the edge weight added as an attribute if w is not nil.
+func edgeAttributer(e graph.Edge, w *WeightAttribute) (Attributer, bool) {
+	a, ok := dotAttributer(e)
+	if w == nil {
+		return a, ok
+	}
+	return weightedAttributes{a: a, key: w.Key, value: quoteID(w.format(e.Weight()))}, true
+}
+
+// weightedAttributes is an Attributer that adds a weight attribute to
+// the attributes of an edge, replacing any attribute with the same key.
+type weightedAttributes struct {
+	a          Attributer
+	key, value string
+}
+
+func (a weightedAttributes) DOTAttributes() []Attribute {
+	var attrs []Attribute
+	if a.a != nil {
+		attrs = a.a.DOTAttributes()
+	}
+	weighted := make([]Attribute, 0, len(attrs)+1)
+	found := false
+	for _, attr := range attrs {
+		if canonicalID(attr.Key) == a.key {
+			if found {
+				continue
+			}
+			attr.Value = a.value
+			found = true
+		}
+		weighted = append(weighted, attr)
+	}
+	if !found {
+		weighted = append(weighted, Attribute{Key: a.key, Value: a.value})
+	}
+	return weighted
+}
+
+// quotedAttributes is an Attributer that returns the attributes of a
+// graph.Attributer with values quoted as needed to be valid DOT IDs.
+type quotedAttributes struct {
+	graph.Attributer
+}
+
+func (a quotedAttributes) DOTAttributes() []Attribute {
+	attrs := a.Attributes()
+	if attrs == nil {
+		return nil
+	}
+	dotAttrs := make([]Attribute, len(attrs))
+	for i, attr := range attrs {
+		dotAttrs[i] = Attribute{Key: quoteID(attr.Key), Value: quoteID(attr.Value)}
+	}
+	return dotAttrs
+}
+
+// graphAttributes is an Attributers that holds only graph attributes.
+type graphAttributes struct {
+	graph.Attributer
+}
+
+func (a graphAttributes) DOTAttributers() (graph, node, edge Attributer) {
+	return quotedAttributes{a.Attributer}, noAttributes{}, noAttributes{}
+}
+
+type noAttributes struct{}
+
+func (noAttributes) DOTAttributes() []Attribute { return nil }
+
+// quoteID returns s if it is a valid unquoted DOT ID, otherwise it returns
+// s as a quoted string. Quotes in s are escaped, and runs of backslashes that
+// precede a quote or end s are doubled so that they do not escape the quote
+// or the closing quote.
+func quoteID(s string) string {
+	if isPlainID(s) {
+		return s
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			j := i
+			for j < len(s) && s[j] == '\\' {
+				j++
+			}
+			buf.WriteString(s[i:j])
+			if j == len(s) || s[j] == '"' {
+				buf.WriteString(s[i:j])
+			}
+			i = j - 1
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// isPlainID returns whether s is a DOT identifier or numeral that
+// is not a keyword.
+func isPlainID(s string) bool {
+	if s == "" {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "node", "edge", "graph", "digraph", "subgraph", "strict":
+		return false
+	}
+	if isIDStart(s[0]) {
+		for i := 1; i < len(s); i++ {
+			if !isIDStart(s[i]) && !isDigit(s[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	i := 0
+	if s[0] == '-' {
+		i++
+	}
+	digits, dot := 0, false
+	for ; i < len(s); i++ {
+		switch {
+		case isDigit(s[i]):
+			digits++
+		case s[i] == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits != 0
+}
+
 func (p *printer) writeAttributeList(a Attributer) {
 	attributes := a.DOTAttributes()
 	switch len(attributes) {
@@ -0,0 +1,153 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gonum/graph/encoding/dot/ast"
+)
+
+var editTests = []struct {
+	name string
+	src  string
+	edit func(g *ast.Graph) int
+	n    int
+	want string
+}{
+	{
+		name: "add",
+		src:  `digraph { a }`,
+		edit: func(g *ast.Graph) int {
+			g.AddNode("b", &ast.Attr{Key: "shape", Val: "box"})
+			g.AddEdge("a", `"b"`)
+			return 0
+		},
+		want: `digraph { a; b [shape=box]; a -> "b" }`,
+	},
+	{
+		name: "remove node in chain",
+		src:  `digraph { /* keep */ a -> b -> c -> d [w=1]; b; e -> b }`,
+		edit: func(g *ast.Graph) int { return g.RemoveNode(`"b"`) },
+		n:    4,
+		want: `digraph { /* keep */ a; c -> d [w=1]; e }`,
+	},
+	{
+		name: "remove node in subgraph",
+		src:  `graph { a -- {b c}; subgraph s { c; d } }`,
+		edit: func(g *ast.Graph) int { return g.RemoveNode("c") },
+		n:    2,
+		want: `graph { a -- {b}; subgraph s { d } }`,
+	},
+	{
+		name: "remove directed edge",
+		src:  `digraph { a -> b -> c; b -> a }`,
+		edit: func(g *ast.Graph) int { return g.RemoveEdge("a", "b") },
+		n:    1,
+		want: `digraph { a; b -> c; b -> a }`,
+	},
+	{
+		name: "remove undirected edge",
+		src:  `graph { a -- b -- c; b -- a }`,
+		edit: func(g *ast.Graph) int { return g.RemoveEdge("a", "b") },
+		n:    2,
+		want: `graph { a; b -- c; b; a }`,
+	},
+	{
+		name: "rename",
+		src:  `digraph { a -> b; subgraph { "a" [x=1] } }`,
+		edit: func(g *ast.Graph) int { return g.RenameNode("a", "z") },
+		n:    2,
+		want: `digraph { z -> b; subgraph { z [x=1] } }`,
+	},
+	{
+		name: "set node attr existing",
+		src:  `digraph { a [color=red]; a [label=x] }`,
+		edit: func(g *ast.Graph) int { g.SetNodeAttr("a", "color", "blue"); return 0 },
+		want: `digraph { a [color=blue]; a [label=x] }`,
+	},
+	{
+		name: "set node attr new",
+		src:  `digraph { a -> b; b [label=x] }`,
+		edit: func(g *ast.Graph) int {
+			g.SetNodeAttr("b", "color", "blue")
+			g.SetNodeAttr("c", "color", "red")
+			return 0
+		},
+		want: `digraph { a -> b; b [label=x color=blue]; c [color=red] }`,
+	},
+	{
+		name: "set edge attr in chain",
+		src:  `digraph { a -> b -> c -> d [w=1] }`,
+		edit: func(g *ast.Graph) int { g.SetEdgeAttr("b", "c", "w", "2"); return 0 },
+		want: `digraph { a -> b [w=1]; b -> c [w=2]; c -> d [w=1] }`,
+	},
+	{
+		name: "set edge attr single",
+		src:  `graph { b -- a; a -- c }`,
+		edit: func(g *ast.Graph) int {
+			g.SetEdgeAttr("a", "b", "color", "red")
+			g.SetEdgeAttr("c", "d", "color", "blue")
+			return 0
+		},
+		want: `graph { b -- a [color=red]; a -- c; c -- d [color=blue] }`,
+	},
+	{
+		name: "set graph attr",
+		src:  `digraph { rankdir=LR; graph [size=1] }`,
+		edit: func(g *ast.Graph) int {
+			g.SetAttr("size", "2")
+			g.SetAttr("label", `"x"`)
+			return 0
+		},
+		want: `digraph { rankdir=LR; graph [size=2]; label="x" }`,
+	},
+}
+
+func TestEdit(t *testing.T) {
+	for _, test := range editTests {
+		f, err := Parse([]byte(test.src))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.name, err)
+		}
+		n := test.edit(f.Graphs[0])
+		if n != test.n {
+			t.Errorf("unexpected number of changes for %q: got:%d want:%d", test.name, n, test.n)
+		}
+
+		var got bytes.Buffer
+		err = ast.Config{}.Fprint(&got, f)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.name, err)
+		}
+		want, err := Format([]byte(test.want), ast.Config{})
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.name, err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("unexpected result for %q:\ngot:\n%s\nwant:\n%s", test.name, &got, want)
+		}
+
+		// The edited tree must round-trip.
+		_, err = Parse(got.Bytes())
+		if err != nil {
+			t.Errorf("unexpected error reparsing %q: %v", test.name, err)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for _, test := range []struct{ id, want string }{
+		{id: `a`, want: `a`},
+		{id: `"a"`, want: `a`},
+		{id: `"a \"b\""`, want: `a "b"`},
+		{id: "\"a\\\nb\"", want: `ab`},
+	} {
+		if got := ast.Canonical(test.id); got != test.want {
+			t.Errorf("unexpected canonical form of %q: got:%q want:%q", test.id, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph/encoding/dot/ast"
+)
+
+const positionSrc = `// header
+digraph G {
+	node [shape=box];
+	a:p:n -> b -> {c d};
+	subgraph s { e }
+	rankdir=LR
+}`
+
+func TestPositions(t *testing.T) {
+	f, err := Parse([]byte(positionSrc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	ast.Inspect(f, func(e ast.Element) bool {
+		if e == nil {
+			return false
+		}
+		var desc string
+		switch e := e.(type) {
+		case *ast.File, *ast.NodeStmt, *ast.EdgeStmt:
+			return true
+		case *ast.Graph:
+			desc = "graph"
+		case *ast.Comment:
+			desc = e.Text
+		case *ast.AttrStmt:
+			desc = e.Kind.String()
+		case *ast.Attr:
+			desc = fmt.Sprintf("%s=%s@%v", e.Key, e.Val, e.ValPos)
+		case *ast.Node:
+			desc = "node " + e.ID
+		case *ast.Port:
+			desc = "port"
+		case *ast.Edge:
+			desc = "edge"
+		case *ast.Subgraph:
+			desc = "subgraph " + e.ID
+		}
+		got = append(got, fmt.Sprintf("%v %s", e.Position(), desc))
+		return true
+	})
+	want := []string{
+		"2:1 graph",
+		"1:1 // header",
+		"3:2 node",
+		"3:8 shape=box@3:14",
+		"4:2 node a",
+		"4:3 port",
+		"4:8 edge",
+		"4:11 node b",
+		"4:13 edge",
+		"4:16 subgraph ",
+		"4:17 node c",
+		"4:19 node d",
+		"5:2 subgraph s",
+		"5:15 node e",
+		"6:2 rankdir=LR@6:10",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected elements:\ngot: %q\nwant:%q", got, want)
+	}
+}
+
+func TestWalkPostOrder(t *testing.T) {
+	f, err := Parse([]byte(`graph { a -- b }`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var depth, max int
+	ast.Inspect(f, func(e ast.Element) bool {
+		if e == nil {
+			depth--
+			return false
+		}
+		depth++
+		if depth > max {
+			max = depth
+		}
+		return true
+	})
+	if depth != 0 {
+		t.Errorf("unbalanced walk: final depth %d", depth)
+	}
+	// File, graph, edge statement,
+	// edge and node.
+	if max != 5 {
+		t.Errorf("unexpected maximum depth: got:%d want:5", max)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	src := `/* c */ digraph { a->b [x=1]; subgraph s { c; subgraph t { d } } }`
+	got, err := Format([]byte(src), ast.Config{Indent: "  "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `/* c */
+digraph {
+  a -> b [x=1];
+  subgraph s {
+    c;
+    subgraph t {
+      d;
+    };
+  };
+}
+`
+	if string(got) != want {
+		t.Errorf("unexpected formatting:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	def, err := Format([]byte(src), ast.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f, _ := Parse([]byte(src))
+	if string(def) != f.String() {
+		t.Errorf("default formatting differs from String:\ngot:\n%s\nwant:\n%s", def, f.String())
+	}
+	if strings.Contains(string(def), "  ") {
+		t.Error("unexpected space indentation with default config")
+	}
+
+	if _, err := Format([]byte(`graph {`), ast.Config{}); err == nil {
+		t.Error("expected error formatting invalid source")
+	}
+}
@@ -0,0 +1,863 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gonum/graph/encoding/dot/ast"
+)
+
+// ParseFile parses the DOT file with the given name.
+func ParseFile(filename string) (*ast.File, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses the DOT source in b. Comments are retained in the returned
+// syntax tree. Comments within a statement are placed before the statement.
+//
+// If the source has syntax errors, Parse returns an ErrorList holding the
+// errors found. Parsing continues after an erroneous statement, so more than
+// one error may be reported.
+func Parse(b []byte) (*ast.File, error) {
+	p := parser{lex: lexer{src: b, line: 1, col: 1, lineStart: true}}
+	f, err := p.file()
+	if err != nil {
+		p.error(err)
+	}
+	if len(p.errs) != 0 {
+		return nil, p.errs
+	}
+	return f, nil
+}
+
+// Format parses the DOT source in src and returns it formatted according to
+// cfg. Comments are retained, but comments within a statement are moved
+// before the statement. If the source has syntax errors, Format returns the
+// ErrorList returned by Parse.
+func Format(src []byte, cfg ast.Config) ([]byte, error) {
+	f, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = cfg.Fprint(&buf, f)
+	return buf.Bytes(), err
+}
+
+// Pos is a position in DOT source. Lines and columns are counted from one,
+// with columns counted in bytes.
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Column) }
+
+// ast returns p as a syntax tree position.
+func (p Pos) ast() ast.Pos { return ast.Pos{Line: p.Line, Column: p.Column} }
+
+// Error is a DOT syntax error.
+type Error struct {
+	// Pos is the position of
+	// the error in the source.
+	Pos Pos `json:"pos"`
+
+	// Msg describes the error.
+	Msg string `json:"msg"`
+
+	// Expected holds the tokens
+	// that would have been valid
+	// at Pos, if known.
+	Expected []string `json:"expected,omitempty"`
+
+	// Lit is the text of the
+	// offending token, if any.
+	Lit string `json:"lit,omitempty"`
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("dot: %v: %s", e.Pos, e.Msg) }
+
+// ErrorList is a list of DOT syntax errors in source order.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "dot: no errors"
+	case 1:
+		return l[0].Error()
+	case 2:
+		return fmt.Sprintf("%v (and 1 more error)", l[0])
+	}
+	return fmt.Sprintf("%v (and %d more errors)", l[0], len(l)-1)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokID
+	tokComment
+	tokLBrace
+	tokRBrace
+	tokLBracket
+	tokRBracket
+	tokSemicolon
+	tokComma
+	tokEqual
+	tokColon
+	tokPlus
+	tokUndirected
+	tokDirected
+)
+
+var tokenNames = [...]string{
+	tokEOF:        "end of file",
+	tokID:         "ID",
+	tokComment:    "comment",
+	tokLBrace:     "'{'",
+	tokRBrace:     "'}'",
+	tokLBracket:   "'['",
+	tokRBracket:   "']'",
+	tokSemicolon:  "';'",
+	tokComma:      "','",
+	tokEqual:      "'='",
+	tokColon:      "':'",
+	tokPlus:       "'+'",
+	tokUndirected: "'--'",
+	tokDirected:   "'->'",
+}
+
+func (k tokenKind) String() string { return tokenNames[k] }
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  Pos
+
+	// quoted is true for
+	// quoted string IDs.
+	quoted bool
+}
+
+// keyword returns whether the token is the given keyword.
+// Keywords are case-insensitive.
+func (t token) keyword(k string) bool {
+	return t.kind == tokID && !t.quoted && strings.EqualFold(t.text, k)
+}
+
+func (t token) String() string {
+	if t.kind == tokID || t.kind == tokComment {
+		return fmt.Sprintf("%q", t.text)
+	}
+	return t.kind.String()
+}
+
+// lexer splits DOT source into tokens.
+type lexer struct {
+	src       []byte
+	off       int
+	line, col int
+
+	// lineStart is true if only
+	// white space has been read
+	// on the current line.
+	lineStart bool
+}
+
+func (l *lexer) peekByte(n int) byte {
+	if l.off+n < len(l.src) {
+		return l.src[l.off+n]
+	}
+	return 0
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.off < len(l.src); i++ {
+		if l.src[l.off] == '\n' {
+			l.line++
+			l.col = 1
+			l.lineStart = true
+		} else {
+			l.col++
+		}
+		l.off++
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v' {
+			l.advance(1)
+			continue
+		}
+		break
+	}
+	start := Pos{Line: l.line, Column: l.col}
+	if l.off >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	atLineStart := l.lineStart
+	l.lineStart = false
+
+	c := l.src[l.off]
+	single := map[byte]tokenKind{
+		'{': tokLBrace, '}': tokRBrace, '[': tokLBracket, ']': tokRBracket,
+		';': tokSemicolon, ',': tokComma, '=': tokEqual, ':': tokColon, '+': tokPlus,
+	}
+	if k, ok := single[c]; ok {
+		l.advance(1)
+		return token{kind: k, text: string(c), pos: start}, nil
+	}
+	switch {
+	case c == '#' && atLineStart:
+		return l.lineComment(start), nil
+	case c == '/' && l.peekByte(1) == '/':
+		return l.lineComment(start), nil
+	case c == '/' && l.peekByte(1) == '*':
+		end := strings.Index(string(l.src[l.off+2:]), "*/")
+		if end < 0 {
+			l.advance(len(l.src) - l.off)
+			return token{}, &Error{Pos: start, Msg: "unterminated comment"}
+		}
+		text := string(l.src[l.off : l.off+end+4])
+		l.advance(len(text))
+		return token{kind: tokComment, text: text, pos: start}, nil
+	case c == '-' && l.peekByte(1) == '-':
+		l.advance(2)
+		return token{kind: tokUndirected, text: "--", pos: start}, nil
+	case c == '-' && l.peekByte(1) == '>':
+		l.advance(2)
+		return token{kind: tokDirected, text: "->", pos: start}, nil
+	case c == '"':
+		return l.quoted(start)
+	case c == '<':
+		return l.html(start)
+	case c == '-' || c == '.' || isDigit(c):
+		return l.numeral(start)
+	case isIDStart(c):
+		i := l.off
+		for i < len(l.src) && (isIDStart(l.src[i]) || isDigit(l.src[i])) {
+			i++
+		}
+		text := string(l.src[l.off:i])
+		l.advance(i - l.off)
+		return token{kind: tokID, text: text, pos: start}, nil
+	}
+	r, size := utf8.DecodeRune(l.src[l.off:])
+	l.advance(size)
+	return token{}, &Error{Pos: start, Msg: fmt.Sprintf("unexpected character %q", r), Lit: string(r)}
+}
+
+func (l *lexer) lineComment(start Pos) token {
+	i := l.off
+	for i < len(l.src) && l.src[i] != '\n' {
+		i++
+	}
+	text := strings.TrimRight(string(l.src[l.off:i]), "\r")
+	l.advance(i - l.off)
+	return token{kind: tokComment, text: text, pos: start}
+}
+
+func (l *lexer) quoted(start Pos) (token, error) {
+	i := l.off + 1
+	for i < len(l.src) {
+		switch l.src[i] {
+		case '\\':
+			i += 2
+			continue
+		case '"':
+			text := string(l.src[l.off : i+1])
+			l.advance(len(text))
+			return token{kind: tokID, text: text, pos: start, quoted: true}, nil
+		}
+		i++
+	}
+	l.advance(len(l.src) - l.off)
+	return token{}, &Error{Pos: start, Msg: "unterminated string"}
+}
+
+func (l *lexer) html(start Pos) (token, error) {
+	depth := 0
+	for i := l.off; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				text := string(l.src[l.off : i+1])
+				l.advance(len(text))
+				return token{kind: tokID, text: text, pos: start}, nil
+			}
+		}
+	}
+	l.advance(len(l.src) - l.off)
+	return token{}, &Error{Pos: start, Msg: "unterminated HTML string"}
+}
+
+func (l *lexer) numeral(start Pos) (token, error) {
+	i := l.off
+	if l.src[i] == '-' {
+		i++
+	}
+	digits := 0
+	for i < len(l.src) && isDigit(l.src[i]) {
+		i++
+		digits++
+	}
+	if i < len(l.src) && l.src[i] == '.' {
+		i++
+		for i < len(l.src) && isDigit(l.src[i]) {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		lit := string(l.src[l.off:i])
+		l.advance(i - l.off)
+		return token{}, &Error{Pos: start, Msg: "invalid numeral", Lit: lit}
+	}
+	text := string(l.src[l.off:i])
+	l.advance(i - l.off)
+	return token{kind: tokID, text: text, pos: start}, nil
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func isIDStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+// parser is a recursive descent DOT parser.
+type parser struct {
+	lex lexer
+
+	// tok is the lookahead token,
+	// valid if peeked is true.
+	tok    token
+	peeked bool
+
+	// comments holds the comments
+	// read since the last flush.
+	comments []*ast.Comment
+
+	// errs holds the errors found
+	// so far.
+	errs ErrorList
+}
+
+// error records err. Errors at the position of the last recorded
+// error are discarded since they are likely to be a consequence of it.
+func (p *parser) error(err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		panic(fmt.Sprintf("dot: unexpected error type %T", err))
+	}
+	if n := len(p.errs); n != 0 && p.errs[n-1].Pos == e.Pos {
+		return
+	}
+	p.errs = append(p.errs, e)
+}
+
+// peek returns the next non-comment token without consuming it.
+// Lexical errors are recorded and the offending text is skipped.
+func (p *parser) peek() (token, error) {
+	for !p.peeked {
+		t, err := p.lex.next()
+		if err != nil {
+			p.error(err)
+			continue
+		}
+		if t.kind == tokComment {
+			p.comments = append(p.comments, &ast.Comment{Pos: t.pos.ast(), Text: t.text})
+			continue
+		}
+		p.tok = t
+		p.peeked = true
+	}
+	return p.tok, nil
+}
+
+// next consumes and returns the next non-comment token.
+func (p *parser) next() (token, error) {
+	t, err := p.peek()
+	p.peeked = false
+	return t, err
+}
+
+// expect consumes the next token, returning an error if it is not of kind k.
+// The token is not consumed if it is not of kind k.
+func (p *parser) expect(k tokenKind) (token, error) {
+	t, err := p.peek()
+	if err != nil {
+		return t, err
+	}
+	if t.kind != k {
+		return t, unexpected(t, k.String())
+	}
+	p.peeked = false
+	return t, nil
+}
+
+// accept consumes the next token if it is of kind k and returns whether it did.
+func (p *parser) accept(k tokenKind) (bool, error) {
+	t, err := p.peek()
+	if err != nil {
+		return false, err
+	}
+	if t.kind != k {
+		return false, nil
+	}
+	p.peeked = false
+	return true, nil
+}
+
+// flush returns and clears the pending comments.
+func (p *parser) flush() []*ast.Comment {
+	c := p.comments
+	p.comments = nil
+	return c
+}
+
+// sync skips tokens following an error in a statement until the end of the
+// statement. It consumes a terminating semicolon, but stops before an
+// unmatched closing brace, the end of the file, or the first token at the
+// same nesting depth on a line after the line of the error.
+func (p *parser) sync(err error) {
+	line := err.(*Error).Pos.Line
+	depth := 0
+	for {
+		t, _ := p.peek()
+		switch t.kind {
+		case tokEOF:
+			return
+		case tokLBrace, tokLBracket:
+			depth++
+		case tokRBrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case tokRBracket:
+			if depth != 0 {
+				depth--
+			}
+		case tokSemicolon:
+			if depth == 0 {
+				p.next()
+				return
+			}
+		default:
+			if depth == 0 && t.pos.Line > line {
+				return
+			}
+		}
+		p.next()
+	}
+}
+
+// unexpected returns an error for the unexpected token t where one of
+// the tokens described by want was expected.
+func unexpected(t token, want ...string) error {
+	var expected string
+	switch len(want) {
+	case 1:
+		expected = want[0]
+	default:
+		expected = strings.Join(want[:len(want)-1], ", ") + " or " + want[len(want)-1]
+	}
+	return &Error{
+		Pos:      t.pos,
+		Msg:      fmt.Sprintf("unexpected %v, expected %s", t, expected),
+		Expected: want,
+		Lit:      t.text,
+	}
+}
+
+func (p *parser) file() (*ast.File, error) {
+	var f ast.File
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == tokEOF {
+			break
+		}
+		g, err := p.graph()
+		if err != nil {
+			return nil, err
+		}
+		f.Graphs = append(f.Graphs, g)
+	}
+	if len(f.Graphs) == 0 {
+		return nil, &Error{Pos: Pos{Line: p.lex.line, Column: p.lex.col}, Msg: "no graph"}
+	}
+	f.Comments = p.flush()
+	return &f, nil
+}
+
+func (p *parser) graph() (*ast.Graph, error) {
+	var g ast.Graph
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	g.Comments = p.flush()
+	g.Pos = t.pos.ast()
+	if t.keyword("strict") {
+		g.Strict = true
+		t, err = p.next()
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case t.keyword("graph"):
+	case t.keyword("digraph"):
+		g.Directed = true
+	default:
+		return nil, unexpected(t, "graph", "digraph")
+	}
+	t, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == tokID {
+		g.ID, err = p.id()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.expect(tokLBrace)
+	if err != nil {
+		return nil, err
+	}
+	g.Stmts, err = p.stmts(g.Directed)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// stmts parses a statement list and the closing brace.
+func (p *parser) stmts(directed bool) ([]ast.Stmt, error) {
+	var stmts []ast.Stmt
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range p.flush() {
+			stmts = append(stmts, c)
+		}
+		switch t.kind {
+		case tokRBrace:
+			p.next()
+			return stmts, nil
+		case tokSemicolon:
+			p.next()
+			continue
+		case tokEOF:
+			return nil, unexpected(t, tokRBrace.String())
+		}
+		s, err := p.stmt(directed)
+		if err != nil {
+			p.error(err)
+			p.sync(err)
+			continue
+		}
+		for _, c := range p.flush() {
+			stmts = append(stmts, c)
+		}
+		stmts = append(stmts, s)
+	}
+}
+
+func (p *parser) stmt(directed bool) (ast.Stmt, error) {
+	t, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case t.keyword("graph"), t.keyword("node"), t.keyword("edge"):
+		p.next()
+		kind := ast.GraphKind
+		switch {
+		case t.keyword("node"):
+			kind = ast.NodeKind
+		case t.keyword("edge"):
+			kind = ast.EdgeKind
+		}
+		attrs, err := p.attrLists(true)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.AttrStmt{Pos: t.pos.ast(), Kind: kind, Attrs: attrs}, nil
+	case t.keyword("subgraph"), t.kind == tokLBrace:
+		sub, err := p.subgraph(directed)
+		if err != nil {
+			return nil, err
+		}
+		return p.edgeOrVertex(sub, directed)
+	case t.kind == tokID:
+		id, err := p.id()
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := p.accept(tokEqual); err != nil || ok {
+			if err != nil {
+				return nil, err
+			}
+			val, valPos, err := p.idPos()
+			if err != nil {
+				return nil, err
+			}
+			return &ast.Attr{Pos: t.pos.ast(), ValPos: valPos, Key: id, Val: val}, nil
+		}
+		n, err := p.port(&ast.Node{Pos: t.pos.ast(), ID: id})
+		if err != nil {
+			return nil, err
+		}
+		return p.edgeOrVertex(n, directed)
+	}
+	return nil, unexpected(t, "statement")
+}
+
+// edgeOrVertex parses the remainder of an edge, node or subgraph statement
+// beginning with v.
+func (p *parser) edgeOrVertex(v ast.Vertex, directed bool) (ast.Stmt, error) {
+	t, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == tokUndirected || t.kind == tokDirected {
+		to, err := p.edge(directed)
+		if err != nil {
+			return nil, err
+		}
+		attrs, err := p.attrLists(false)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.EdgeStmt{From: v, To: to, Attrs: attrs}, nil
+	}
+	switch v := v.(type) {
+	case *ast.Node:
+		attrs, err := p.attrLists(false)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.NodeStmt{Node: v, Attrs: attrs}, nil
+	case *ast.Subgraph:
+		return v, nil
+	}
+	panic("dot: unknown vertex type")
+}
+
+func (p *parser) edge(directed bool) (*ast.Edge, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if (t.kind == tokDirected) != directed {
+		// Record the error and continue parsing
+		// the edge as if the operator were valid.
+		if directed {
+			p.error(&Error{Pos: t.pos, Msg: "undirected edge in directed graph", Expected: []string{tokDirected.String()}, Lit: t.text})
+		} else {
+			p.error(&Error{Pos: t.pos, Msg: "directed edge in undirected graph", Expected: []string{tokUndirected.String()}, Lit: t.text})
+		}
+	}
+	e := &ast.Edge{Pos: t.pos.ast(), Directed: directed}
+	t, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case t.keyword("subgraph"), t.kind == tokLBrace:
+		e.Vertex, err = p.subgraph(directed)
+	case t.kind == tokID:
+		var id string
+		id, err = p.id()
+		if err == nil {
+			e.Vertex, err = p.port(&ast.Node{Pos: t.pos.ast(), ID: id})
+		}
+	default:
+		return nil, unexpected(t, "node", "subgraph")
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == tokUndirected || t.kind == tokDirected {
+		e.To, err = p.edge(directed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (p *parser) subgraph(directed bool) (*ast.Subgraph, error) {
+	var sub ast.Subgraph
+	t, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	sub.Pos = t.pos.ast()
+	if t.keyword("subgraph") {
+		p.next()
+		t, err = p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == tokID {
+			sub.ID, err = p.id()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	_, err = p.expect(tokLBrace)
+	if err != nil {
+		return nil, err
+	}
+	sub.Stmts, err = p.stmts(directed)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// port parses an optional port following a node ID.
+func (p *parser) port(n *ast.Node) (*ast.Node, error) {
+	t, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := p.accept(tokColon)
+	if err != nil || !ok {
+		return n, err
+	}
+	n.Port = &ast.Port{Pos: t.pos.ast()}
+	n.Port.ID, err = p.id()
+	if err != nil {
+		return nil, err
+	}
+	ok, err = p.accept(tokColon)
+	if err != nil || !ok {
+		return n, err
+	}
+	n.Port.CompassPoint, err = p.id()
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// attrLists parses a sequence of attribute lists. At least one list
+// is required if required is true.
+func (p *parser) attrLists(required bool) ([]*ast.Attr, error) {
+	var attrs []*ast.Attr
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tokLBracket {
+			if required {
+				return nil, unexpected(t, "'['")
+			}
+			return attrs, nil
+		}
+		required = false
+		p.next()
+		for {
+			t, err := p.peek()
+			if err != nil {
+				return nil, err
+			}
+			if t.kind == tokRBracket {
+				p.next()
+				break
+			}
+			key, keyPos, err := p.idPos()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.expect(tokEqual)
+			if err != nil {
+				return nil, err
+			}
+			val, valPos, err := p.idPos()
+			if err != nil {
+				return nil, err
+			}
+			attrs = append(attrs, &ast.Attr{Pos: keyPos, ValPos: valPos, Key: key, Val: val})
+			if ok, err := p.accept(tokComma); err != nil {
+				return nil, err
+			} else if !ok {
+				if _, err := p.accept(tokSemicolon); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// idPos parses an ID and returns it with its position.
+func (p *parser) idPos() (string, ast.Pos, error) {
+	t, err := p.peek()
+	if err != nil {
+		return "", ast.Pos{}, err
+	}
+	id, err := p.id()
+	return id, t.pos.ast(), err
+}
+
+// id parses an ID, joining concatenated quoted strings.
+func (p *parser) id() (string, error) {
+	t, err := p.expect(tokID)
+	if err != nil {
+		return "", err
+	}
+	if !t.quoted {
+		return t.text, nil
+	}
+	text := t.text
+	for {
+		ok, err := p.accept(tokPlus)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return text, nil
+		}
+		t, err := p.peek()
+		if err != nil {
+			return "", err
+		}
+		if t.kind != tokID || !t.quoted {
+			return "", unexpected(t, "quoted string")
+		}
+		p.next()
+		text = text[:len(text)-1] + t.text[1:]
+	}
+}
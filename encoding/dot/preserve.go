@@ -0,0 +1,525 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot/ast"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Metadata holds side-band information about a decoded DOT graph: the
+// original statements in order, the comments, the DOT IDs of the decoded
+// nodes and the attributes that were not accepted by the decoded nodes and
+// edges. It is returned by UnmarshalWith when the Preserve option is set.
+type Metadata struct {
+	file *ast.File
+
+	// ids holds the source DOT
+	// IDs keyed by node ID.
+	ids map[int]string
+
+	// unknown holds attributes
+	// not accepted by the nodes
+	// and edges.
+	unknown map[*ast.Attr]bool
+
+	// inherited holds the default
+	// attributes applied to each
+	// node and edge.
+	inherited map[key]map[string]string
+
+	// final holds the source attribute
+	// that set the decoded value of each
+	// attribute key of each node and edge.
+	final map[key]map[string]*ast.Attr
+
+	// expanded holds the node ID pairs
+	// of the edges described by edge
+	// statements with subgraph terminals.
+	expanded map[*ast.EdgeStmt][][2]int
+
+	// weight is the weight attribute
+	// used when decoding.
+	weight *WeightAttribute
+}
+
+// Marshal returns the DOT encoding of g using the statement order, comments
+// and unknown attributes of the source described by m. The graph g is
+// expected to be the decoded graph, possibly after modification.
+//
+// Statements for nodes and edges that are no longer in g are omitted. The
+// attributes of nodes and edges that implement Attributer are taken from their
+// current attributes, keeping the positions of attributes present in the
+// source and appending new attributes to the first statement for the node or
+// edge; unknown attributes are written as they appeared in the source.
+// Only the source attribute that set the decoded value of an attribute is
+// changed, so duplicate statements for a node or edge keep their own values.
+// Attributes inherited from node and edge attribute statements are not
+// written unless their values have changed. If the graph was decoded with a
+// weight attribute, the weights of edges implementing WeightSetter are written
+// to that attribute, keeping the source value if it is unchanged. Edge chains
+// are split if their edges no longer share attributes. Nodes and edges that
+// are not described by the source are appended to the end of the graph.
+func (m *Metadata) Marshal(g graph.Graph) ([]byte, error) {
+	src := m.file.Graphs[0]
+	r := replayer{
+		m:        m,
+		g:        g,
+		byID:     make(map[int]graph.Node),
+		keys:     make(map[key]map[string]bool),
+		written:  make(map[key]bool),
+		emitted:  make(map[int]bool),
+		dotIDs:   make(map[string]int),
+		directed: src.Directed,
+	}
+	for _, n := range g.Nodes() {
+		r.byID[n.ID()] = n
+	}
+	for id, dotID := range m.ids {
+		r.dotIDs[canonicalID(dotID)] = id
+	}
+	r.collect(src.Stmts)
+
+	dst := *src
+	dst.Stmts = r.stmts(src.Stmts)
+	dst.Stmts = append(dst.Stmts, r.additions()...)
+	f := ast.File{Graphs: []*ast.Graph{&dst}, Comments: m.file.Comments}
+	return []byte(f.String()), nil
+}
+
+// key identifies a node or an edge. Nodes have
+// the to field set to the from field.
+type key struct {
+	from, to int
+}
+
+type replayer struct {
+	m        *Metadata
+	g        graph.Graph
+	directed bool
+
+	byID   map[int]graph.Node
+	dotIDs map[string]int
+
+	// keys holds the attribute keys in
+	// the source for each node and edge.
+	keys map[key]map[string]bool
+
+	// written holds the nodes and edges
+	// that have had new attributes written.
+	written map[key]bool
+
+	// emitted holds the nodes mentioned
+	// in the output.
+	emitted map[int]bool
+}
+
+func (r *replayer) edgeKey(u, v int) key {
+	return edgeKey(r.directed, u, v)
+}
+
+// edgeKey returns the key for an edge from u to v.
+func edgeKey(directed bool, u, v int) key {
+	if !directed && v < u {
+		u, v = v, u
+	}
+	return key{from: u, to: v}
+}
+
+// node returns the ID of the node with the given DOT ID and whether
+// it is in the graph. A node whose ID has been reused by a node with
+// a different DOT ID is not considered to be in the graph.
+func (r *replayer) node(dotID string) (int, bool) {
+	id, ok := r.dotIDs[canonicalID(dotID)]
+	if !ok {
+		return 0, false
+	}
+	n, ok := r.byID[id]
+	if !ok {
+		return 0, false
+	}
+	if n, isNode := n.(Node); isNode && canonicalID(n.DOTID()) != canonicalID(dotID) {
+		return 0, false
+	}
+	return id, true
+}
+
+// collect records the attribute keys in the source for each node and edge.
+func (r *replayer) collect(stmts []ast.Stmt) {
+	add := func(k key, attrs []*ast.Attr) {
+		if r.keys[k] == nil {
+			r.keys[k] = make(map[string]bool)
+		}
+		for _, a := range attrs {
+			r.keys[k][a.Key] = true
+		}
+	}
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *ast.NodeStmt:
+			if id, ok := r.dotIDs[canonicalID(s.Node.ID)]; ok {
+				add(key{from: id, to: id}, s.Attrs)
+			}
+		case *ast.EdgeStmt:
+			if pairs, ok := r.m.expanded[s]; ok {
+				for _, p := range pairs {
+					add(r.edgeKey(p[0], p[1]), s.Attrs)
+				}
+				r.collect(subgraphs(s))
+				continue
+			}
+			for _, p := range chain(s) {
+				u, uok := r.dotIDs[canonicalID(p[0].ID)]
+				v, vok := r.dotIDs[canonicalID(p[1].ID)]
+				if uok && vok {
+					add(r.edgeKey(u, v), s.Attrs)
+				}
+			}
+		case *ast.Subgraph:
+			r.collect(s.Stmts)
+		}
+	}
+}
+
+// chain returns the node pairs of the edges of s, or nil if
+// s has a subgraph terminal.
+func chain(s *ast.EdgeStmt) [][2]*ast.Node {
+	var pairs [][2]*ast.Node
+	from := s.From
+	for to := s.To; to != nil; to = to.To {
+		u, uok := from.(*ast.Node)
+		v, vok := to.Vertex.(*ast.Node)
+		if !uok || !vok {
+			return nil
+		}
+		pairs = append(pairs, [2]*ast.Node{u, v})
+		from = to.Vertex
+	}
+	return pairs
+}
+
+// subgraphs returns the subgraph terminals of s.
+func subgraphs(s *ast.EdgeStmt) []ast.Stmt {
+	var subs []ast.Stmt
+	if sub, ok := s.From.(*ast.Subgraph); ok {
+		subs = append(subs, sub)
+	}
+	for to := s.To; to != nil; to = to.To {
+		if sub, ok := to.Vertex.(*ast.Subgraph); ok {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// vertex returns the replayed form of the edge terminal v.
+func (r *replayer) vertex(v ast.Vertex) ast.Vertex {
+	sub, ok := v.(*ast.Subgraph)
+	if !ok {
+		return v
+	}
+	c := *sub
+	c.Stmts = r.stmts(sub.Stmts)
+	return &c
+}
+
+func (r *replayer) stmts(stmts []ast.Stmt) []ast.Stmt {
+	var out []ast.Stmt
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *ast.NodeStmt:
+			id, ok := r.node(s.Node.ID)
+			if !ok {
+				continue
+			}
+			r.emitted[id] = true
+			n := *s
+			n.Attrs = r.attrs(key{from: id, to: id}, r.byID[id], s.Attrs)
+			out = append(out, &n)
+		case *ast.EdgeStmt:
+			out = append(out, r.edgeStmt(s)...)
+		case *ast.Subgraph:
+			sub := *s
+			sub.Stmts = r.stmts(s.Stmts)
+			out = append(out, &sub)
+		default:
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (r *replayer) edgeStmt(s *ast.EdgeStmt) []ast.Stmt {
+	var (
+		pairs [][2]*ast.Node
+		subs  []ast.Stmt
+	)
+	expanded, isExpanded := r.m.expanded[s]
+	if isExpanded {
+		// Replay the subgraph terminals so that their
+		// contents are kept if the statement is split.
+		c := *s
+		c.From = r.vertex(s.From)
+		var last *ast.Edge
+		for to := s.To; to != nil; to = to.To {
+			e := *to
+			e.Vertex = r.vertex(to.Vertex)
+			if last == nil {
+				c.To = &e
+			} else {
+				last.To = &e
+			}
+			last = &e
+		}
+		s = &c
+		for _, sub := range subgraphs(s) {
+			// Drop emptied subgraphs.
+			if len(sub.(*ast.Subgraph).Stmts) != 0 {
+				subs = append(subs, sub)
+			}
+		}
+		for _, p := range expanded {
+			pairs = append(pairs, [2]*ast.Node{{ID: r.m.ids[p[0]]}, {ID: r.m.ids[p[1]]}})
+		}
+	} else {
+		pairs = chain(s)
+		if pairs == nil {
+			return []ast.Stmt{s}
+		}
+	}
+
+	type edge struct {
+		pair  [2]*ast.Node
+		attrs []*ast.Attr
+	}
+	var (
+		edges   []edge
+		keys    = make(map[key]bool)
+		missing bool
+		differ  bool
+	)
+	for _, p := range pairs {
+		u, uok := r.node(p[0].ID)
+		v, vok := r.node(p[1].ID)
+		if !uok || !vok {
+			missing = true
+			continue
+		}
+		e := r.edge(u, v)
+		if e == nil {
+			missing = true
+			continue
+		}
+		k := r.edgeKey(u, v)
+		attrs := r.attrs(k, e, s.Attrs)
+		if len(edges) != 0 && !equalAttrs(attrs, edges[0].attrs) {
+			differ = true
+		}
+		edges = append(edges, edge{pair: p, attrs: attrs})
+		keys[k] = true
+		r.emitted[u] = true
+		r.emitted[v] = true
+	}
+	if len(edges) == 0 {
+		return subs
+	}
+	if !differ && (!missing || (isExpanded && r.expandsTo(s, keys))) {
+		// The statement, with removed nodes dropped
+		// from its subgraph terminals, still describes
+		// exactly the remaining edges.
+		c := *s
+		c.Attrs = edges[0].attrs
+		return []ast.Stmt{&c}
+	}
+	out := make([]ast.Stmt, len(edges))
+	for i, e := range edges {
+		out[i] = &ast.EdgeStmt{
+			From:  e.pair[0],
+			To:    &ast.Edge{Directed: r.directed, Vertex: e.pair[1]},
+			Attrs: e.attrs,
+		}
+	}
+	return append(subs, out...)
+}
+
+// expandsTo returns whether the edge statement s, with subgraph terminals,
+// describes exactly the edges with the given keys.
+func (r *replayer) expandsTo(s *ast.EdgeStmt, keys map[key]bool) bool {
+	want := make(map[key]bool)
+	from := r.terminal(s.From)
+	for to := s.To; to != nil; to = to.To {
+		ids := r.terminal(to.Vertex)
+		for _, u := range from {
+			for _, v := range ids {
+				if u == v {
+					continue
+				}
+				k := r.edgeKey(u, v)
+				if !keys[k] {
+					return false
+				}
+				want[k] = true
+			}
+		}
+		from = ids
+	}
+	return len(want) == len(keys)
+}
+
+// terminal returns the IDs of the nodes in the graph described by the
+// edge terminal v.
+func (r *replayer) terminal(v ast.Vertex) []int {
+	var ids []int
+	seen := make(map[int]bool)
+	ast.Inspect(v, func(e ast.Element) bool {
+		if n, ok := e.(*ast.Node); ok {
+			if id, ok := r.node(n.ID); ok && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return true
+	})
+	return ids
+}
+
+// edge returns the edge between the nodes with IDs u and v in the
+// direction of the source graph, or nil if there is no edge.
+func (r *replayer) edge(u, v int) graph.Edge {
+	x, y := r.byID[u], r.byID[v]
+	if d, ok := r.g.(graph.Directed); ok {
+		if !d.HasEdgeFromTo(x, y) {
+			return nil
+		}
+	} else if !r.g.HasEdgeBetween(x, y) {
+		return nil
+	}
+	return r.g.Edge(x, y)
+}
+
+// attrs returns the attributes to write for the node or edge with key k
+// and value v given the attributes of its source statement.
+func (r *replayer) attrs(k key, v interface{}, src []*ast.Attr) []*ast.Attr {
+	var (
+		a      Attributer
+		ok     bool
+		weight *WeightAttribute
+		w      float64
+	)
+	if e, isEdge := v.(WeightSetter); isEdge && r.m.weight != nil {
+		weight = r.m.weight
+		w = e.(graph.Edge).Weight()
+		a, ok = edgeAttributer(e.(graph.Edge), weight)
+	} else {
+		a, ok = dotAttributer(v)
+	}
+	if !ok {
+		return src
+	}
+	current := make(map[string]string)
+	var order []string
+	for _, attr := range a.DOTAttributes() {
+		if _, dup := current[attr.Key]; !dup {
+			order = append(order, attr.Key)
+		}
+		current[attr.Key] = attr.Value
+	}
+
+	var attrs []*ast.Attr
+	for _, attr := range src {
+		if r.m.unknown[attr] || r.m.final[k][attr.Key] != attr {
+			// Attributes that were rejected or
+			// overridden keep their source values.
+			attrs = append(attrs, attr)
+			continue
+		}
+		if weight != nil && canonicalID(attr.Key) == weight.Key {
+			// Keep the source form of an unchanged weight.
+			if v, err := weight.parse(canonicalID(attr.Val)); err == nil && v == w {
+				attrs = append(attrs, attr)
+				continue
+			}
+		}
+		if val, ok := current[attr.Key]; ok {
+			attrs = append(attrs, &ast.Attr{Key: attr.Key, Val: val})
+		}
+	}
+	if !r.written[k] {
+		r.written[k] = true
+		for _, key := range order {
+			if val, ok := r.m.inherited[k][key]; ok && val == current[key] {
+				continue
+			}
+			if !r.keys[k][key] {
+				attrs = append(attrs, &ast.Attr{Key: key, Val: current[key]})
+			}
+		}
+	}
+	return attrs
+}
+
+func equalAttrs(a, b []*ast.Attr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// additions returns statements for the nodes and edges of the graph
+// that are not described by the source.
+func (r *replayer) additions() []ast.Stmt {
+	var stmts []ast.Stmt
+	nodes := r.g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		if r.emitted[n.ID()] && r.keys[key{from: n.ID(), to: n.ID()}] != nil {
+			continue
+		}
+		k := key{from: n.ID(), to: n.ID()}
+		attrs := r.attrs(k, n, nil)
+		if r.emitted[n.ID()] && len(attrs) == 0 {
+			continue
+		}
+		stmts = append(stmts, &ast.NodeStmt{Node: &ast.Node{ID: r.dotID(n)}, Attrs: attrs})
+	}
+	for _, u := range nodes {
+		to := r.g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			k := r.edgeKey(u.ID(), v.ID())
+			if r.keys[k] != nil || (!r.directed && u.ID() > v.ID()) {
+				continue
+			}
+			r.keys[k] = map[string]bool{}
+			e := r.g.Edge(u, v)
+			stmts = append(stmts, &ast.EdgeStmt{
+				From:  &ast.Node{ID: r.dotID(u)},
+				To:    &ast.Edge{Directed: r.directed, Vertex: &ast.Node{ID: r.dotID(v)}},
+				Attrs: r.attrs(k, e, nil),
+			})
+		}
+	}
+	return stmts
+}
+
+// dotID returns the DOT ID of n from the source, or as it would be
+// written by Marshal.
+func (r *replayer) dotID(n graph.Node) string {
+	if _, ok := n.(Node); ok {
+		return nodeID(n)
+	}
+	if id, ok := r.m.ids[n.ID()]; ok {
+		return id
+	}
+	return nodeID(n)
+}
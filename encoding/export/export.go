@@ -0,0 +1,102 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package export implements the export of graphs as tensors for machine
+// learning tools.
+//
+// Tensors are written in the NumPy array formats described at
+// https://docs.scipy.org/doc/numpy/neps/npy-format.html, which may be read
+// by NumPy and, through it, by PyTorch Geometric, and by Gorgonia. An .npz
+// archive written by WriteNPZ holds arrays named for the attributes of a
+// PyTorch Geometric Data object, so in Python
+//
+//  arrays = numpy.load("graph.npz")
+//  data = torch_geometric.data.Data(**{k: torch.from_numpy(v) for k, v in arrays.items()})
+//
+// gives a graph ready for training.
+package export
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Tensors is a graph held as a coordinate list of edges and a matrix of
+// node features. Nodes are identified by their index in Nodes rather than
+// by their ID.
+type Tensors struct {
+	// Nodes holds the nodes of the
+	// graph in ascending order of ID.
+	Nodes []graph.Node
+
+	// EdgeIndex holds the 2×E edge index
+	// in row-major order: the indices of
+	// the from nodes of the E edges
+	// followed by the indices of the to
+	// nodes. The edges are ordered by
+	// from and then to index. Each edge
+	// of an undirected graph is held in
+	// both directions.
+	EdgeIndex []int64
+
+	// EdgeWeight holds the weight of
+	// each edge in EdgeIndex.
+	EdgeWeight []float64
+
+	// Features holds the N×F feature
+	// matrix in row-major order, where
+	// row i is the features of Nodes[i].
+	Features []float64
+
+	// NumFeatures is F, the number of
+	// features of each node.
+	NumFeatures int
+}
+
+// NumEdges returns the number of edges, E, held in t.EdgeIndex.
+func (t Tensors) NumEdges() int { return len(t.EdgeWeight) }
+
+// FromGraph returns the tensors of g. If features is not nil, it is called
+// with each node of g and must return the same number of features for each
+// node, otherwise the tensors have no features. The weight of each edge is
+// taken from the edges of g. FromGraph will panic if features returns a
+// different number of features for two nodes.
+func FromGraph(g graph.Graph, features func(graph.Node) []float64) Tensors {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int64, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = int64(i)
+	}
+
+	t := Tensors{Nodes: nodes}
+	var from, to []int64
+	for i, u := range nodes {
+		adj := g.From(u)
+		sort.Sort(ordered.ByID(adj))
+		for _, v := range adj {
+			from = append(from, int64(i))
+			to = append(to, indexOf[v.ID()])
+			t.EdgeWeight = append(t.EdgeWeight, g.Edge(u, v).Weight())
+		}
+	}
+	t.EdgeIndex = append(from, to...)
+
+	if features == nil {
+		return t
+	}
+	for i, n := range nodes {
+		f := features(n)
+		if i == 0 {
+			t.NumFeatures = len(f)
+			t.Features = make([]float64, 0, len(nodes)*len(f))
+		} else if len(f) != t.NumFeatures {
+			panic("export: inconsistent number of features")
+		}
+		t.Features = append(t.Features, f...)
+	}
+	return t
+}
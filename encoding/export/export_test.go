@@ -0,0 +1,133 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestFromGraph(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(10), T: simple.Node(20), W: 2})
+	g.SetEdge(simple.Edge{F: simple.Node(30), T: simple.Node(20), W: 3})
+
+	tn := FromGraph(g, func(n graph.Node) []float64 {
+		return []float64{float64(n.ID()), 1}
+	})
+	if tn.NumEdges() != 4 {
+		t.Errorf("unexpected number of edges: got:%d want:4", tn.NumEdges())
+	}
+	wantIndex := []int64{
+		0, 1, 1, 2,
+		1, 0, 2, 1,
+	}
+	if !reflect.DeepEqual(tn.EdgeIndex, wantIndex) {
+		t.Errorf("unexpected edge index: got:%v want:%v", tn.EdgeIndex, wantIndex)
+	}
+	if want := []float64{2, 2, 3, 3}; !reflect.DeepEqual(tn.EdgeWeight, want) {
+		t.Errorf("unexpected edge weights: got:%v want:%v", tn.EdgeWeight, want)
+	}
+	if want := []float64{10, 1, 20, 1, 30, 1}; !reflect.DeepEqual(tn.Features, want) || tn.NumFeatures != 2 {
+		t.Errorf("unexpected features: got:%v with %d features want:%v", tn.Features, tn.NumFeatures, want)
+	}
+
+	d := simple.NewDirectedGraph(0, math.Inf(1))
+	d.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0), W: 1})
+	if got := FromGraph(d, nil); !reflect.DeepEqual(got.EdgeIndex, []int64{1, 0}) || got.Features != nil {
+		t.Errorf("unexpected directed tensors: got:%+v", got)
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		FromGraph(g, func(n graph.Node) []float64 { return make([]float64, n.ID()) })
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for inconsistent features")
+	}
+}
+
+func TestWriteNPZ(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 0.5})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1.5})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0), W: 2.5})
+	tn := FromGraph(g, func(n graph.Node) []float64 { return []float64{float64(n.ID())} })
+
+	var buf bytes.Buffer
+	err := tn.WriteNPZ(&buf)
+	if err != nil {
+		t.Fatalf("failed to write npz: %v", err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read npz: %v", err)
+	}
+
+	want := map[string]struct {
+		header string
+		data   interface{}
+	}{
+		"edge_index.npy":  {header: "{'descr': '<i8', 'fortran_order': False, 'shape': (2, 3), }", data: tn.EdgeIndex},
+		"edge_weight.npy": {header: "{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }", data: tn.EdgeWeight},
+		"x.npy":           {header: "{'descr': '<f8', 'fortran_order': False, 'shape': (3, 1), }", data: tn.Features},
+	}
+	if len(z.File) != len(want) {
+		t.Errorf("unexpected number of arrays: got:%d want:%d", len(z.File), len(want))
+	}
+	for _, f := range z.File {
+		w, ok := want[f.Name]
+		if !ok {
+			t.Errorf("unexpected array %q", f.Name)
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %q: %v", f.Name, err)
+		}
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", f.Name, err)
+		}
+
+		if !strings.HasPrefix(string(b), npyMagic) {
+			t.Errorf("%s: missing magic string", f.Name)
+			continue
+		}
+		n := int(binary.LittleEndian.Uint16(b[len(npyMagic):]))
+		start := len(npyMagic) + 2 + n
+		if start%64 != 0 {
+			t.Errorf("%s: data not aligned: offset %d", f.Name, start)
+		}
+		header := string(b[len(npyMagic)+2 : start])
+		if got := strings.TrimRight(header, " \n"); got != w.header || !strings.HasSuffix(header, "\n") {
+			t.Errorf("%s: unexpected header: got:%q want:%q", f.Name, header, w.header)
+		}
+
+		got := reflect.New(reflect.TypeOf(w.data)).Elem()
+		got.Set(reflect.MakeSlice(got.Type(), reflect.ValueOf(w.data).Len(), reflect.ValueOf(w.data).Len()))
+		err = binary.Read(bytes.NewReader(b[start:]), binary.LittleEndian, got.Interface())
+		if err != nil {
+			t.Errorf("%s: failed to read data: %v", f.Name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Interface(), w.data) {
+			t.Errorf("%s: unexpected data: got:%v want:%v", f.Name, got.Interface(), w.data)
+		}
+	}
+}
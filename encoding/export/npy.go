@@ -0,0 +1,94 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteEdgeIndex writes t.EdgeIndex to w as a 2×E NumPy array of int64.
+func (t Tensors) WriteEdgeIndex(w io.Writer) error {
+	return writeNPY(w, "<i8", []int{2, t.NumEdges()}, t.EdgeIndex)
+}
+
+// WriteEdgeWeight writes t.EdgeWeight to w as a NumPy vector of float64
+// with length E.
+func (t Tensors) WriteEdgeWeight(w io.Writer) error {
+	return writeNPY(w, "<f8", []int{t.NumEdges()}, t.EdgeWeight)
+}
+
+// WriteFeatures writes t.Features to w as an N×F NumPy array of float64.
+func (t Tensors) WriteFeatures(w io.Writer) error {
+	return writeNPY(w, "<f8", []int{len(t.Nodes), t.NumFeatures}, t.Features)
+}
+
+// WriteNPZ writes t to w as a NumPy .npz archive holding the arrays
+// "edge_index", "edge_weight" and, if t has features, "x".
+func (t Tensors) WriteNPZ(w io.Writer) error {
+	z := zip.NewWriter(w)
+	arrays := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{name: "edge_index.npy", write: t.WriteEdgeIndex},
+		{name: "edge_weight.npy", write: t.WriteEdgeWeight},
+	}
+	if t.NumFeatures != 0 {
+		arrays = append(arrays, struct {
+			name  string
+			write func(io.Writer) error
+		}{name: "x.npy", write: t.WriteFeatures})
+	}
+	for _, a := range arrays {
+		f, err := z.Create(a.name)
+		if err != nil {
+			return err
+		}
+		err = a.write(f)
+		if err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// npyMagic is the magic string and version 1.0 of the NumPy format.
+const npyMagic = "\x93NUMPY\x01\x00"
+
+// writeNPY writes data to w as a C-ordered NumPy array with the given
+// type description and shape. The data must be a slice of fixed size
+// values of the described type.
+func writeNPY(w io.Writer, descr string, shape []int, data interface{}) error {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = fmt.Sprint(d)
+	}
+	dimList := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		dimList += ","
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, dimList)
+
+	// The header is padded with spaces and
+	// terminated by a newline so the data
+	// starts on a 64 byte boundary.
+	n := len(npyMagic) + 2 + len(header) + 1
+	header += strings.Repeat(" ", (64-n%64)%64) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	_, err := w.Write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
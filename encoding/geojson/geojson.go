@@ -0,0 +1,270 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package geojson implements decoding of GeoJSON line features into graphs,
+// as described in RFC 7946.
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/spatial"
+)
+
+// Node is a graph node at a geographic position. Node satisfies the
+// spatial.Positioned interface with the X and Y coordinates of its
+// position holding its longitude and latitude.
+type Node struct {
+	id       int
+	lon, lat float64
+}
+
+// ID returns the ID number of the node.
+func (n Node) ID() int { return n.id }
+
+// Pos returns the longitude and latitude of the node as the X and Y
+// coordinates of a point.
+func (n Node) Pos() layout.Point { return layout.Point{X: n.lon, Y: n.lat} }
+
+// Attributes returns the position of the node as "lon" and "lat" attributes.
+func (n Node) Attributes() []graph.Attribute {
+	return []graph.Attribute{
+		{Key: "lon", Value: strconv.FormatFloat(n.lon, 'g', -1, 64)},
+		{Key: "lat", Value: strconv.FormatFloat(n.lat, 'g', -1, 64)},
+	}
+}
+
+// Edge is a segment of a line feature. The weight of the edge is the
+// great-circle distance between its terminal nodes in metres.
+type Edge struct {
+	F, T Node
+	W    float64
+
+	// Properties holds the properties
+	// of the feature holding the edge.
+	Properties []graph.Attribute
+}
+
+// From returns the from-node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// Weight returns the weight of the edge.
+func (e Edge) Weight() float64 { return e.W }
+
+// Attributes returns the properties of the feature holding the edge.
+func (e Edge) Attributes() []graph.Attribute { return e.Properties }
+
+// DecodeOptions holds options for UnmarshalWith.
+type DecodeOptions struct {
+	// Oneway is the name of a feature
+	// property that indicates edges
+	// of the feature are traversable
+	// in only one direction when the
+	// destination is directed. A value
+	// of "yes", "true" or "1" indicates
+	// the direction of the coordinates
+	// and "-1" the reverse direction.
+	// If Oneway is empty, all features
+	// are traversable in both directions.
+	Oneway string
+}
+
+// Unmarshal decodes the GeoJSON in data and adds a node to dst for each
+// distinct position in the LineString and MultiLineString geometries it
+// holds, and an edge between the nodes of consecutive positions. Features,
+// FeatureCollections and GeometryCollections are decoded, and other geometry
+// types are ignored. Positions are distinct if their longitude or latitude
+// differ; altitudes are ignored. Nodes are given IDs from dst.NewNodeID and
+// edges are Edge values. Feature properties are held by the edges of the
+// feature in the order of their keys, with string values unquoted and other
+// values in their JSON encoding. If dst is a graph.Directed, edges are added
+// in both directions.
+func Unmarshal(data []byte, dst graph.Builder) error {
+	return UnmarshalWith(data, dst, DecodeOptions{})
+}
+
+// UnmarshalWith decodes the GeoJSON in data and adds the nodes and edges
+// it describes to dst in the same way as Unmarshal, using the provided
+// options.
+func UnmarshalWith(data []byte, dst graph.Builder, opts DecodeOptions) error {
+	var obj object
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return err
+	}
+	_, directed := dst.(graph.Directed)
+	d := decoder{
+		dst:      dst,
+		directed: directed,
+		opts:     opts,
+		nodes:    make(map[[2]float64]Node),
+	}
+	return d.object(&obj, nil)
+}
+
+// object is a GeoJSON object of any type.
+type object struct {
+	Type        string                     `json:"type"`
+	Features    []*object                  `json:"features"`
+	Geometry    *object                    `json:"geometry"`
+	Geometries  []*object                  `json:"geometries"`
+	Properties  map[string]json.RawMessage `json:"properties"`
+	Coordinates json.RawMessage            `json:"coordinates"`
+}
+
+type decoder struct {
+	dst      graph.Builder
+	directed bool
+	opts     DecodeOptions
+	nodes    map[[2]float64]Node
+}
+
+// object adds the lines held by obj to the destination, labelling
+// edges with the given feature properties.
+func (d *decoder) object(obj *object, props map[string]json.RawMessage) error {
+	switch obj.Type {
+	case "FeatureCollection":
+		for _, f := range obj.Features {
+			if f == nil || f.Type != "Feature" {
+				return errors.New("geojson: invalid feature in collection")
+			}
+			err := d.object(f, nil)
+			if err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil
+		}
+		return d.object(obj.Geometry, obj.Properties)
+	case "GeometryCollection":
+		for _, g := range obj.Geometries {
+			if g == nil {
+				return errors.New("geojson: invalid geometry in collection")
+			}
+			err := d.object(g, props)
+			if err != nil {
+				return err
+			}
+		}
+	case "LineString":
+		var line [][]float64
+		err := json.Unmarshal(obj.Coordinates, &line)
+		if err != nil {
+			return fmt.Errorf("geojson: invalid LineString coordinates: %v", err)
+		}
+		return d.line(line, props)
+	case "MultiLineString":
+		var lines [][][]float64
+		err := json.Unmarshal(obj.Coordinates, &lines)
+		if err != nil {
+			return fmt.Errorf("geojson: invalid MultiLineString coordinates: %v", err)
+		}
+		for _, line := range lines {
+			err = d.line(line, props)
+			if err != nil {
+				return err
+			}
+		}
+	case "Point", "MultiPoint", "Polygon", "MultiPolygon":
+	default:
+		return fmt.Errorf("geojson: unknown object type: %q", obj.Type)
+	}
+	return nil
+}
+
+// line adds the nodes and edges of a line to the destination.
+func (d *decoder) line(line [][]float64, props map[string]json.RawMessage) error {
+	if len(line) < 2 {
+		return errors.New("geojson: LineString with fewer than two positions")
+	}
+	forward, backward := true, d.directed
+	if d.directed && d.opts.Oneway != "" {
+		if v, ok := props[d.opts.Oneway]; ok {
+			switch value(v) {
+			case "yes", "true", "1":
+				backward = false
+			case "-1":
+				forward, backward = false, true
+			}
+		}
+	}
+	attrs := attributes(props)
+
+	var prev Node
+	for i, c := range line {
+		if len(c) < 2 {
+			return fmt.Errorf("geojson: invalid position: %v", c)
+		}
+		n, err := d.node(c[0], c[1])
+		if err != nil {
+			return err
+		}
+		if i != 0 && n.id != prev.id {
+			w := spatial.Haversine(prev.Pos(), n.Pos())
+			if forward {
+				d.dst.SetEdge(Edge{F: prev, T: n, W: w, Properties: attrs})
+			}
+			if backward {
+				d.dst.SetEdge(Edge{F: n, T: prev, W: w, Properties: attrs})
+			}
+		}
+		prev = n
+	}
+	return nil
+}
+
+// node returns the node at the given position, adding it to
+// the destination if it has not yet been seen.
+func (d *decoder) node(lon, lat float64) (Node, error) {
+	if lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+		return Node{}, fmt.Errorf("geojson: position out of range: [%v, %v]", lon, lat)
+	}
+	k := [2]float64{lon, lat}
+	n, ok := d.nodes[k]
+	if !ok {
+		n = Node{id: d.dst.NewNodeID(), lon: lon, lat: lat}
+		d.dst.AddNode(n)
+		d.nodes[k] = n
+	}
+	return n, nil
+}
+
+// attributes returns the feature properties as attributes
+// sorted by key.
+func attributes(props map[string]json.RawMessage) []graph.Attribute {
+	if len(props) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]graph.Attribute, len(keys))
+	for i, k := range keys {
+		attrs[i] = graph.Attribute{Key: k, Value: value(props[k])}
+	}
+	return attrs
+}
+
+// value returns the unquoted value of a JSON string, or the
+// JSON text of other values.
+func value(v json.RawMessage) string {
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		return s
+	}
+	return string(v)
+}
@@ -0,0 +1,131 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geojson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/spatial"
+)
+
+const roads = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"name": "High Street", "highway": "primary", "lanes": 2},
+      "geometry": {"type": "LineString", "coordinates": [[0, 0], [0.01, 0], [0.02, 0]]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"name": "Mill Lane", "oneway": "yes"},
+      "geometry": {"type": "LineString", "coordinates": [[0.02, 0, 12.5], [0.02, 0.01]]}
+    },
+    {
+      "type": "Feature",
+      "properties": null,
+      "geometry": {
+        "type": "MultiLineString",
+        "coordinates": [[[0, 0], [0, 0.01]], [[0, 0.01], [0.02, 0.01]]]
+      }
+    },
+    {
+      "type": "Feature",
+      "properties": {"name": "Post Office"},
+      "geometry": {"type": "Point", "coordinates": [0.01, 0.005]}
+    }
+  ]
+}`
+
+func TestUnmarshal(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := Unmarshal([]byte(roads), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(g.Nodes()); n != 5 {
+		t.Errorf("unexpected number of nodes: got:%d want:5", n)
+	}
+	var edges int
+	for _, u := range g.Nodes() {
+		edges += len(g.From(u))
+	}
+	if edges/2 != 5 {
+		t.Errorf("unexpected number of edges: got:%d want:5", edges/2)
+	}
+
+	index := spatial.IndexNodes(g)
+	a, _ := index.NearestNode(layout.Point{X: 0, Y: 0})
+	b, _ := index.NearestNode(layout.Point{X: 0.01, Y: 0})
+	e := g.Edge(a, b).(Edge)
+	if want := spatial.Haversine(a.Pos(), b.Pos()); e.W != want {
+		t.Errorf("unexpected edge weight: got:%v want:%v", e.W, want)
+	}
+	if math.Abs(e.W-1111.95) > 0.01 {
+		t.Errorf("unexpected edge length: got:%v want:~1111.95", e.W)
+	}
+	wantProps := []graph.Attribute{
+		{Key: "highway", Value: "primary"},
+		{Key: "lanes", Value: "2"},
+		{Key: "name", Value: "High Street"},
+	}
+	if !reflect.DeepEqual(e.Properties, wantProps) {
+		t.Errorf("unexpected edge properties: got:%v want:%v", e.Properties, wantProps)
+	}
+	wantAttrs := []graph.Attribute{{Key: "lon", Value: "0.01"}, {Key: "lat", Value: "0"}}
+	if got := b.(Node).Attributes(); !reflect.DeepEqual(got, wantAttrs) {
+		t.Errorf("unexpected node attributes: got:%v want:%v", got, wantAttrs)
+	}
+}
+
+func TestUnmarshalOneway(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	err := UnmarshalWith([]byte(roads), g, DecodeOptions{Oneway: "oneway"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	index := spatial.IndexNodes(g)
+	s, _ := index.NearestNode(layout.Point{X: 0.02, Y: 0.01})
+	u, _ := index.NearestNode(layout.Point{X: 0.02, Y: 0})
+	if g.HasEdgeFromTo(s, u) {
+		t.Errorf("unexpected edge against oneway direction")
+	}
+	if !g.HasEdgeFromTo(u, s) {
+		t.Errorf("missing edge in oneway direction")
+	}
+
+	// Routing from s to u must go around the block.
+	pt, _ := path.AStar(s, u, g, spatial.GreatCircleHeuristic)
+	p, w := pt.To(u)
+	if len(p) != 5 {
+		t.Errorf("unexpected path length: got:%d want:5", len(p))
+	}
+	if math.Abs(w-5*1111.95) > 1 {
+		t.Errorf("unexpected path weight: got:%v want:~%v", w, 5*1111.95)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"type": "LineString", "coordinates": [[0, 0]]}`,
+		`{"type": "LineString", "coordinates": [[0, 0], [200, 0]]}`,
+		`{"type": "LineString", "coordinates": [[0, 0], [1]]}`,
+		`{"type": "LineString", "coordinates": "0,0"}`,
+		`{"type": "Line", "coordinates": [[0, 0], [1, 1]]}`,
+		`{"type": "FeatureCollection", "features": [{"type": "Point", "coordinates": [0, 0]}]}`,
+		`[`,
+	} {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		if err := Unmarshal([]byte(data), g); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+}
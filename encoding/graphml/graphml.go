@@ -0,0 +1,136 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package graphml implements encoding of graphs as GraphML.
+package graphml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Marshal returns the GraphML encoding of g. Nodes are identified by their
+// ID prefixed with "n" and are written in ID order, followed by the edges
+// in the order of their terminal IDs. The edges of an undirected graph are
+// written once. Edge weights are held by the "weight" data key and the
+// attributes of nodes and edges implementing graph.Attributer by string data
+// keys declared in the order they are first seen.
+func Marshal(g graph.Graph) ([]byte, error) {
+	var gml graphML
+	gml.XMLNS = "http://graphml.graphdrawing.org/xmlns"
+	gml.Keys = []key{{ID: "weight", For: "edge", Name: "weight", Type: "double"}}
+	gml.Graph.EdgeDefault = "undirected"
+	if _, ok := g.(graph.Directed); ok {
+		gml.Graph.EdgeDefault = "directed"
+	}
+	nodeKeys := keys{gml: &gml, kind: "node", ids: make(map[string]string)}
+	edgeKeys := keys{gml: &gml, kind: "edge", ids: make(map[string]string)}
+	nodes, edges := edges(g)
+	for _, n := range nodes {
+		gml.Graph.Nodes = append(gml.Graph.Nodes, node{
+			ID:   fmt.Sprintf("n%d", n.ID()),
+			Data: nodeKeys.data(attributes(n)),
+		})
+	}
+	for _, e := range edges {
+		ge := edge{Source: fmt.Sprintf("n%d", e.From().ID()), Target: fmt.Sprintf("n%d", e.To().ID())}
+		ge.Data = append(ge.Data, data{Key: "weight", Value: strconv.FormatFloat(e.Weight(), 'g', -1, 64)})
+		ge.Data = append(ge.Data, edgeKeys.data(attributes(e))...)
+		gml.Graph.Edges = append(gml.Graph.Edges, ge)
+	}
+	b, err := xml.MarshalIndent(gml, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// graphML is the GraphML encoding of a graph.
+type graphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   struct {
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []node `xml:"node"`
+		Edges       []edge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type key struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type node struct {
+	ID   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type edge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// keys holds the GraphML keys of node or edge attributes.
+type keys struct {
+	gml  *graphML
+	kind string
+	ids  map[string]string
+}
+
+// data returns the GraphML data for attrs, declaring keys as needed.
+func (k *keys) data(attrs []graph.Attribute) []data {
+	var d []data
+	for _, a := range attrs {
+		id, ok := k.ids[a.Key]
+		if !ok {
+			id = fmt.Sprintf("d%d", len(k.gml.Keys))
+			k.ids[a.Key] = id
+			k.gml.Keys = append(k.gml.Keys, key{ID: id, For: k.kind, Name: a.Key, Type: "string"})
+		}
+		d = append(d, data{Key: id, Value: a.Value})
+	}
+	return d
+}
+
+// edges returns the nodes and edges of g in a deterministic order.
+// Undirected edges are returned once.
+func edges(g graph.Graph) ([]graph.Node, []graph.Edge) {
+	_, directed := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []graph.Edge
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if directed || u.ID() < v.ID() {
+				edges = append(edges, g.Edge(u, v))
+			}
+		}
+	}
+	return nodes, edges
+}
+
+// attributes returns the attributes of v if it implements graph.Attributer.
+func attributes(v interface{}) []graph.Attribute {
+	if a, ok := v.(graph.Attributer); ok {
+		return a.Attributes()
+	}
+	return nil
+}
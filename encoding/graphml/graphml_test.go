@@ -0,0 +1,94 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+type attrNode struct {
+	simple.Node
+	attrs []graph.Attribute
+}
+
+func (n attrNode) Attributes() []graph.Attribute { return n.attrs }
+
+type attrEdge struct {
+	simple.Edge
+	attrs []graph.Attribute
+}
+
+func (e attrEdge) Attributes() []graph.Attribute { return e.attrs }
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.AddNode(attrNode{Node: 1, attrs: []graph.Attribute{{Key: "label", Value: "a < b"}, {Key: "color", Value: "red"}}})
+	g.AddNode(attrNode{Node: 0, attrs: []graph.Attribute{{Key: "color", Value: "blue"}}})
+	g.AddNode(simple.Node(2))
+	g.SetEdge(attrEdge{Edge: simple.Edge{F: simple.Node(1), T: simple.Node(0), W: 2.5}, attrs: []graph.Attribute{{Key: "style", Value: "bold"}}})
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2), W: 1})
+
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="weight" for="edge" attr.name="weight" attr.type="double"></key>
+	<key id="d1" for="node" attr.name="color" attr.type="string"></key>
+	<key id="d2" for="node" attr.name="label" attr.type="string"></key>
+	<key id="d3" for="edge" attr.name="style" attr.type="string"></key>
+	<graph edgedefault="directed">
+		<node id="n0">
+			<data key="d1">blue</data>
+		</node>
+		<node id="n1">
+			<data key="d2">a &lt; b</data>
+			<data key="d1">red</data>
+		</node>
+		<node id="n2"></node>
+		<edge source="n0" target="n2">
+			<data key="weight">1</data>
+		</edge>
+		<edge source="n1" target="n0">
+			<data key="weight">2.5</data>
+			<data key="d3">bold</data>
+		</edge>
+	</graph>
+</graphml>
+`
+	if string(b) != want {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+func TestMarshalUndirected(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="weight" for="edge" attr.name="weight" attr.type="double"></key>
+	<graph edgedefault="undirected">
+		<node id="n0"></node>
+		<node id="n1"></node>
+		<edge source="n0" target="n1">
+			<data key="weight">1</data>
+		</edge>
+	</graph>
+</graphml>
+`
+	if string(b) != want {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+}
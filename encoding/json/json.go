@@ -0,0 +1,228 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package json implements encoding and decoding of graphs as JSON objects
+// of the form
+//  {"directed": true, "nodes": [0, 1], "edges": [{"from": 0, "to": 1, "weight": 1}]}
+// Node attributes are held in a "node_attributes" object keyed by the
+// decimal node ID, and edge attributes in an "attributes" object of each
+// edge.
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Node is a graph node decoded from JSON.
+type Node struct {
+	id    int
+	attrs []graph.Attribute
+}
+
+// ID returns the ID number of the node.
+func (n *Node) ID() int { return n.id }
+
+// Attributes returns the attributes of the node.
+func (n *Node) Attributes() []graph.Attribute { return n.attrs }
+
+// SetAttribute sets the attribute a, replacing any attribute with the same
+// key.
+func (n *Node) SetAttribute(a graph.Attribute) error {
+	n.attrs = setAttribute(n.attrs, a)
+	return nil
+}
+
+// Edge is a graph edge decoded from JSON.
+type Edge struct {
+	F, T  graph.Node
+	W     float64
+	Attrs []graph.Attribute
+}
+
+// From returns the from-node of the edge.
+func (e *Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e *Edge) To() graph.Node { return e.T }
+
+// Weight returns the weight of the edge.
+func (e *Edge) Weight() float64 { return e.W }
+
+// Attributes returns the attributes of the edge.
+func (e *Edge) Attributes() []graph.Attribute { return e.Attrs }
+
+// SetAttribute sets the attribute a, replacing any attribute with the same
+// key.
+func (e *Edge) SetAttribute(a graph.Attribute) error {
+	e.Attrs = setAttribute(e.Attrs, a)
+	return nil
+}
+
+// setAttribute sets a in attrs, replacing any attribute with the same key.
+func setAttribute(attrs []graph.Attribute, a graph.Attribute) []graph.Attribute {
+	for i, o := range attrs {
+		if o.Key == a.Key {
+			attrs[i] = a
+			return attrs
+		}
+	}
+	return append(attrs, a)
+}
+
+// jsonGraph is the JSON encoding of a graph.
+type jsonGraph struct {
+	Directed       bool                         `json:"directed"`
+	Nodes          []int                        `json:"nodes"`
+	NodeAttributes map[string]map[string]string `json:"node_attributes,omitempty"`
+	Edges          []jsonEdge                   `json:"edges"`
+}
+
+type jsonEdge struct {
+	From       int               `json:"from"`
+	To         int               `json:"to"`
+	Weight     *float64          `json:"weight,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Marshal returns the indented JSON encoding of g. Nodes are written in ID
+// order and edges in the order of their terminal IDs, with the edges of an
+// undirected graph written once. The attributes of nodes and edges
+// implementing graph.Attributer are written if there are any.
+func Marshal(g graph.Graph) ([]byte, error) {
+	_, directed := g.(graph.Directed)
+	nodes, edges := edges(g)
+	jg := jsonGraph{Directed: directed, Nodes: make([]int, len(nodes))}
+	for i, n := range nodes {
+		jg.Nodes[i] = n.ID()
+		if attrs := attributeMap(attributes(n)); attrs != nil {
+			if jg.NodeAttributes == nil {
+				jg.NodeAttributes = make(map[string]map[string]string)
+			}
+			jg.NodeAttributes[strconv.Itoa(n.ID())] = attrs
+		}
+	}
+	for _, e := range edges {
+		w := e.Weight()
+		jg.Edges = append(jg.Edges, jsonEdge{
+			From:       e.From().ID(),
+			To:         e.To().ID(),
+			Weight:     &w,
+			Attributes: attributeMap(attributes(e)),
+		})
+	}
+	b, err := json.MarshalIndent(jg, "", "\t")
+	return append(b, '\n'), err
+}
+
+// Unmarshal decodes the JSON encoding of a graph in data and adds the nodes
+// and edges it describes to dst. Nodes are *Node values and edges are *Edge
+// values, holding their attributes in the order of their keys. Edges without
+// a weight are given a weight of 1. The nodes of the JSON graph should not
+// already be in dst.
+//
+// Unmarshal returns an error if the directedness of the JSON graph does not
+// match that of dst or if the JSON graph holds an edge from a node to itself.
+func Unmarshal(data []byte, dst graph.Builder) error {
+	var jg jsonGraph
+	err := json.Unmarshal(data, &jg)
+	if err != nil {
+		return err
+	}
+	if _, isDirected := dst.(graph.Directed); isDirected != jg.Directed {
+		if jg.Directed {
+			return errors.New("json: cannot decode directed graph into undirected destination")
+		}
+		return errors.New("json: cannot decode undirected graph into directed destination")
+	}
+	nodes := make(map[int]graph.Node)
+	node := func(id int) graph.Node {
+		n, ok := nodes[id]
+		if !ok {
+			n = &Node{id: id, attrs: attributeList(jg.NodeAttributes[strconv.Itoa(id)])}
+			nodes[id] = n
+		}
+		return n
+	}
+	for _, id := range jg.Nodes {
+		if _, ok := nodes[id]; ok {
+			continue
+		}
+		dst.AddNode(node(id))
+	}
+	for _, e := range jg.Edges {
+		if e.From == e.To {
+			return fmt.Errorf("json: self edge on node %d", e.From)
+		}
+		w := 1.0
+		if e.Weight != nil {
+			w = *e.Weight
+		}
+		dst.SetEdge(&Edge{F: node(e.From), T: node(e.To), W: w, Attrs: attributeList(e.Attributes)})
+	}
+	return nil
+}
+
+// attributeMap returns attrs as a map, or nil if attrs is empty.
+func attributeMap(attrs []graph.Attribute) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value
+	}
+	return m
+}
+
+// attributeList returns the attributes in m ordered by key, or nil if m is
+// empty.
+func attributeList(m map[string]string) []graph.Attribute {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]graph.Attribute, len(keys))
+	for i, k := range keys {
+		attrs[i] = graph.Attribute{Key: k, Value: m[k]}
+	}
+	return attrs
+}
+
+// edges returns the nodes and edges of g in a deterministic order.
+// Undirected edges are returned once.
+func edges(g graph.Graph) ([]graph.Node, []graph.Edge) {
+	_, directed := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []graph.Edge
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if directed || u.ID() < v.ID() {
+				edges = append(edges, g.Edge(u, v))
+			}
+		}
+	}
+	return nodes, edges
+}
+
+// attributes returns the attributes of v if it implements graph.Attributer.
+func attributes(v interface{}) []graph.Attribute {
+	if a, ok := v.(graph.Attributer); ok {
+		return a.Attributes()
+	}
+	return nil
+}
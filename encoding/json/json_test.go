@@ -0,0 +1,142 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+const attributed = `{
+	"directed": true,
+	"nodes": [
+		0,
+		1,
+		2,
+		3
+	],
+	"node_attributes": {
+		"0": {
+			"color": "red",
+			"label": "a \u003c b"
+		}
+	},
+	"edges": [
+		{
+			"from": 0,
+			"to": 1,
+			"weight": 2,
+			"attributes": {
+				"style": "bold"
+			}
+		},
+		{
+			"from": 1,
+			"to": 3,
+			"weight": 1
+		}
+	]
+}
+`
+
+func TestUnmarshal(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	err := Unmarshal([]byte(attributed), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(g.Nodes()); n != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", n)
+	}
+	want := []graph.Attribute{{Key: "color", Value: "red"}, {Key: "label", Value: "a < b"}}
+	if got := g.Node(0).(graph.Attributer).Attributes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected node attributes: got:%v want:%v", got, want)
+	}
+	e := g.Edge(simple.Node(0), simple.Node(1))
+	if e.Weight() != 2 {
+		t.Errorf("unexpected edge weight: got:%v want:2", e.Weight())
+	}
+	want = []graph.Attribute{{Key: "style", Value: "bold"}}
+	if got := e.(graph.Attributer).Attributes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected edge attributes: got:%v want:%v", got, want)
+	}
+
+	g = simple.NewDirectedGraph(0, math.Inf(1))
+	err = Unmarshal([]byte(`{"directed": true, "edges": [{"from": 1, "to": 3}]}`), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(g.Nodes()); n != 2 {
+		t.Errorf("unexpected number of nodes for edge terminals not in node list: got:%d want:2", n)
+	}
+	if w, ok := g.Weight(simple.Node(1), simple.Node(3)); !ok || w != 1 {
+		t.Errorf("unexpected weight for edge without weight: got:%v want:1", w)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	err := Unmarshal([]byte(attributed), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != attributed {
+		t.Errorf("unexpected round trip:\ngot:\n%s\nwant:\n%s", b, attributed)
+	}
+}
+
+func TestMarshalUndirected(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 0.5})
+	g.AddNode(simple.Node(2))
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = `{
+	"directed": false,
+	"nodes": [
+		0,
+		1,
+		2
+	],
+	"edges": [
+		{
+			"from": 0,
+			"to": 1,
+			"weight": 0.5
+		}
+	]
+}
+`
+	if string(b) != want {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range []struct {
+		src string
+		dst graph.Builder
+	}{
+		{src: `{"directed": true}`, dst: simple.NewUndirectedGraph(0, math.Inf(1))},
+		{src: `{"directed": false}`, dst: simple.NewDirectedGraph(0, math.Inf(1))},
+		{src: `{"directed": false, "edges": [{"from": 1, "to": 1}]}`, dst: simple.NewUndirectedGraph(0, math.Inf(1))},
+		{src: `{"directed": false, "nodes": [`, dst: simple.NewUndirectedGraph(0, math.Inf(1))},
+	} {
+		err := Unmarshal([]byte(test.src), test.dst)
+		if err == nil {
+			t.Errorf("expected error for %s", test.src)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package osm implements decoding of OpenStreetMap PBF files into road
+// network graphs.
+package osm
+
+import (
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/spatial"
+)
+
+// Node is a graph node at the position of an OpenStreetMap node. Node
+// satisfies the spatial.Positioned interface with the X and Y coordinates
+// of its position holding its longitude and latitude.
+type Node struct {
+	id       int
+	osm      int64
+	lon, lat float64
+}
+
+// ID returns the ID number of the node.
+func (n Node) ID() int { return n.id }
+
+// OSMID returns the OpenStreetMap ID of the node.
+func (n Node) OSMID() int64 { return n.osm }
+
+// Pos returns the longitude and latitude of the node as the X and Y
+// coordinates of a point.
+func (n Node) Pos() layout.Point { return layout.Point{X: n.lon, Y: n.lat} }
+
+// Attributes returns the OpenStreetMap ID and position of the node as
+// "osm_id", "lon" and "lat" attributes.
+func (n Node) Attributes() []graph.Attribute {
+	return []graph.Attribute{
+		{Key: "osm_id", Value: strconv.FormatInt(n.osm, 10)},
+		{Key: "lon", Value: strconv.FormatFloat(n.lon, 'g', -1, 64)},
+		{Key: "lat", Value: strconv.FormatFloat(n.lat, 'g', -1, 64)},
+	}
+}
+
+// Edge is a section of an OpenStreetMap way between two graph nodes. The
+// weight of the edge is the great-circle length of the section in metres.
+type Edge struct {
+	F, T Node
+	W    float64
+
+	// Way is the OpenStreetMap ID of
+	// the way holding the section.
+	Way int64
+
+	// Tags holds the tags of the way
+	// sorted by key.
+	Tags []graph.Attribute
+}
+
+// From returns the from-node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// Weight returns the weight of the edge.
+func (e Edge) Weight() float64 { return e.W }
+
+// Attributes returns the tags of the way holding the edge.
+func (e Edge) Attributes() []graph.Attribute { return e.Tags }
+
+// DecodeOptions holds options for DecodeWith.
+type DecodeOptions struct {
+	// Filter returns whether a way
+	// with the given tags is included
+	// in the graph. If Filter is nil,
+	// ways with a highway tag are
+	// included.
+	Filter func(tags map[string]string) bool
+}
+
+// Decode reads an OpenStreetMap PBF file from r and adds the road network it
+// describes to dst. Ways with a highway tag are split at nodes they share with
+// other included ways, and each section is added as an Edge between Nodes at
+// its ends, with nodes given IDs from dst.NewNodeID. Sections are traversable
+// in both directions unless the way is one-way, as given by its oneway tag, or
+// implied for motorways and roundabouts. If more than one section joins a pair
+// of nodes in the same direction, the shortest is retained. Closed sections
+// joining a node to itself are omitted.
+//
+// Nodes are held in memory until all ways have been read, so PBF files are
+// expected to have nodes before ways, as the specification requires. Sections
+// of ways referring to nodes absent from the file are omitted.
+func Decode(r io.Reader, dst graph.DirectedBuilder) error {
+	return DecodeWith(r, dst, DecodeOptions{})
+}
+
+// DecodeWith reads an OpenStreetMap PBF file from r and adds the road network
+// it describes to dst in the same way as Decode, using the provided options.
+func DecodeWith(r io.Reader, dst graph.DirectedBuilder, opts DecodeOptions) error {
+	filter := opts.Filter
+	if filter == nil {
+		filter = func(tags map[string]string) bool {
+			_, ok := tags["highway"]
+			return ok
+		}
+	}
+
+	coords := make(map[int64]layout.Point)
+	var ways []way
+	br := blobReader{r: r}
+	for {
+		typ, data, err := br.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case "OSMHeader":
+			err = header(data)
+		case "OSMData":
+			err = primitives(data,
+				func(id int64, lat, lon float64) {
+					coords[id] = layout.Point{X: lon, Y: lat}
+				},
+				func(id int64, tags map[string]string, refs []int64) {
+					if filter(tags) {
+						ways = append(ways, way{id: id, tags: tags, refs: refs})
+					}
+				},
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Split ways into runs of nodes present in the file
+	// and count the uses of each node so that ways are
+	// split at intersections and at their ends.
+	uses := make(map[int64]int)
+	for i, w := range ways {
+		var runs [][]int64
+		start := -1
+		for j, ref := range w.refs {
+			_, ok := coords[ref]
+			switch {
+			case ok && start < 0:
+				start = j
+			case !ok && start >= 0:
+				runs = append(runs, w.refs[start:j])
+				start = -1
+			}
+		}
+		if start >= 0 {
+			runs = append(runs, w.refs[start:])
+		}
+		for _, run := range runs {
+			for j, ref := range run {
+				uses[ref]++
+				if j == 0 || j == len(run)-1 {
+					// Ends are always split points.
+					uses[ref]++
+				}
+			}
+		}
+		ways[i].runs = runs
+	}
+
+	nodes := make(map[int64]Node)
+	node := func(ref int64) Node {
+		n, ok := nodes[ref]
+		if !ok {
+			p := coords[ref]
+			n = Node{id: dst.NewNodeID(), osm: ref, lon: p.X, lat: p.Y}
+			dst.AddNode(n)
+			nodes[ref] = n
+		}
+		return n
+	}
+	setEdge := func(e Edge) {
+		if e.F.id == e.T.id {
+			return
+		}
+		if old := dst.Edge(e.F, e.T); old != nil && old.Weight() <= e.W {
+			return
+		}
+		dst.SetEdge(e)
+	}
+	for _, w := range ways {
+		forward, backward := oneway(w.tags)
+		attrs := tags(w.tags)
+		for _, run := range w.runs {
+			from := run[0]
+			var length float64
+			for j := 1; j < len(run); j++ {
+				length += spatial.Haversine(coords[run[j-1]], coords[run[j]])
+				if uses[run[j]] < 2 {
+					continue
+				}
+				u, v := node(from), node(run[j])
+				if forward {
+					setEdge(Edge{F: u, T: v, W: length, Way: w.id, Tags: attrs})
+				}
+				if backward {
+					setEdge(Edge{F: v, T: u, W: length, Way: w.id, Tags: attrs})
+				}
+				from, length = run[j], 0
+			}
+		}
+	}
+	return nil
+}
+
+// way is an OpenStreetMap way.
+type way struct {
+	id   int64
+	tags map[string]string
+	refs []int64
+
+	// runs holds the sequences of
+	// refs present in the file.
+	runs [][]int64
+}
+
+// oneway returns the directions in which a way with the given tags may be
+// traversed relative to the order of its nodes.
+func oneway(tags map[string]string) (forward, backward bool) {
+	switch tags["oneway"] {
+	case "yes", "true", "1":
+		return true, false
+	case "-1", "reverse":
+		return false, true
+	case "no", "false", "0":
+		return true, true
+	}
+	if tags["highway"] == "motorway" || tags["junction"] == "roundabout" {
+		return true, false
+	}
+	return true, true
+}
+
+// tags returns the tags as attributes sorted by key.
+func tags(t map[string]string) []graph.Attribute {
+	if len(t) == 0 {
+		return nil
+	}
+	attrs := make([]graph.Attribute, 0, len(t))
+	for k, v := range t {
+		attrs = append(attrs, graph.Attribute{Key: k, Value: v})
+	}
+	sort.Sort(byKey(attrs))
+	return attrs
+}
+
+// byKey sorts attributes by key.
+type byKey []graph.Attribute
+
+func (a byKey) Len() int           { return len(a) }
+func (a byKey) Less(i, j int) bool { return a[i].Key < a[j].Key }
+func (a byKey) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
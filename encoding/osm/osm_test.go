@@ -0,0 +1,264 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/spatial"
+)
+
+// message is a protocol buffer message encoder.
+type message []byte
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (m message) varint(field int, v uint64) message {
+	m = appendUvarint(m, uint64(field<<3|varint))
+	return appendUvarint(m, v)
+}
+
+func (m message) bytes(field int, b []byte) message {
+	m = appendUvarint(m, uint64(field<<3|bytesT))
+	m = appendUvarint(m, uint64(len(b)))
+	return append(m, b...)
+}
+
+func (m message) packed(field int, v []uint64) message {
+	var b []byte
+	for _, x := range v {
+		b = appendUvarint(b, x)
+	}
+	return m.bytes(field, b)
+}
+
+func sint(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+func deltas(v []int64) []uint64 {
+	d := make([]uint64, len(v))
+	var prev int64
+	for i, x := range v {
+		d[i] = sint(x - prev)
+		prev = x
+	}
+	return d
+}
+
+type testNode struct {
+	id       int64
+	lat, lon float64
+}
+
+type testWay struct {
+	id   int64
+	tags [][2]string
+	refs []int64
+}
+
+// pbf returns a PBF file holding the given nodes and ways. The first node
+// is encoded as a non-dense node and the remainder as dense nodes.
+func pbf(nodes []testNode, ways []testWay, compress bool) []byte {
+	var buf bytes.Buffer
+	blob := func(typ string, data []byte) {
+		var b message
+		if compress {
+			var z bytes.Buffer
+			w := zlib.NewWriter(&z)
+			w.Write(data)
+			w.Close()
+			b = b.varint(2, uint64(len(data))).bytes(3, z.Bytes())
+		} else {
+			b = b.bytes(1, data)
+		}
+		h := message(nil).bytes(1, []byte(typ)).varint(3, uint64(len(b)))
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(h)))
+		buf.Write(size[:])
+		buf.Write(h)
+		buf.Write(b)
+	}
+
+	blob("OSMHeader", message(nil).bytes(4, []byte("OsmSchema-V0.6")).bytes(4, []byte("DenseNodes")))
+
+	const granularity = 100
+	coord := func(x float64) int64 { return int64(math.Floor(x*1e9/granularity + 0.5)) }
+	strs := []string{""}
+	index := make(map[string]uint64)
+	str := func(s string) uint64 {
+		i, ok := index[s]
+		if !ok {
+			i = uint64(len(strs))
+			index[s] = i
+			strs = append(strs, s)
+		}
+		return i
+	}
+
+	n := nodes[0]
+	plain := message(nil).varint(1, sint(n.id)).varint(8, sint(coord(n.lat))).varint(9, sint(coord(n.lon)))
+	var ids, lats, lons []int64
+	for _, n := range nodes[1:] {
+		ids = append(ids, n.id)
+		lats = append(lats, coord(n.lat))
+		lons = append(lons, coord(n.lon))
+	}
+	dense := message(nil).packed(1, deltas(ids)).packed(8, deltas(lats)).packed(9, deltas(lons))
+	var wayGroup message
+	for _, w := range ways {
+		var keys, vals []uint64
+		for _, t := range w.tags {
+			keys = append(keys, str(t[0]))
+			vals = append(vals, str(t[1]))
+		}
+		wm := message(nil).varint(1, uint64(w.id)).packed(2, keys).packed(3, vals).packed(8, deltas(w.refs))
+		wayGroup = wayGroup.bytes(3, wm)
+	}
+	var table message
+	for _, s := range strs {
+		table = table.bytes(1, []byte(s))
+	}
+	block := message(nil).
+		bytes(2, message(nil).bytes(1, plain).bytes(2, dense)).
+		bytes(2, wayGroup).
+		bytes(1, table).
+		varint(17, granularity)
+	blob("OSMData", block)
+	return buf.Bytes()
+}
+
+// The test network is a square of primary roads with a one-way
+// road crossing it, a building outline and a way that leaves the
+// extract.
+//
+//	4 --- 5 --- 6
+//	|     ^     |
+//	|     |     |
+//	1 --- 2 --- 3
+var (
+	testNodes = []testNode{
+		{id: 1, lat: 0, lon: 0},
+		{id: 2, lat: 0, lon: 0.01},
+		{id: 3, lat: 0, lon: 0.02},
+		{id: 4, lat: 0.01, lon: 0},
+		{id: 5, lat: 0.01, lon: 0.01},
+		{id: 6, lat: 0.01, lon: 0.02},
+		{id: 7, lat: 0.005, lon: 0.01},
+		{id: 8, lat: 0.02, lon: 0.02},
+	}
+	testWays = []testWay{
+		{id: 100, tags: [][2]string{{"highway", "primary"}, {"name", "South Road"}}, refs: []int64{1, 2, 3}},
+		{id: 101, tags: [][2]string{{"highway", "primary"}}, refs: []int64{3, 6, 5, 4, 1}},
+		{id: 102, tags: [][2]string{{"highway", "residential"}, {"oneway", "yes"}}, refs: []int64{2, 7, 5}},
+		{id: 103, tags: [][2]string{{"building", "yes"}}, refs: []int64{1, 2, 5, 4, 1}},
+		{id: 104, tags: [][2]string{{"highway", "service"}}, refs: []int64{6, 8, 9}},
+	}
+)
+
+func TestDecode(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		err := Decode(bytes.NewReader(pbf(testNodes, testWays, compress)), g)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		osm := make(map[int64]graph.Node)
+		for _, n := range g.Nodes() {
+			osm[n.(Node).OSMID()] = n
+		}
+		if len(osm) != 6 {
+			t.Errorf("unexpected number of nodes: got:%d want:6", len(osm))
+		}
+		for _, id := range []int64{4, 7} {
+			if _, ok := osm[id]; ok {
+				t.Errorf("unexpected node for way interior: %d", id)
+			}
+		}
+
+		const block = 1111.95
+		for _, test := range []struct {
+			from, to int64
+			want     float64
+		}{
+			{from: 1, to: 2, want: block},
+			{from: 2, to: 1, want: block},
+			{from: 2, to: 5, want: block},
+			{from: 5, to: 2, want: math.Inf(1)},
+			{from: 6, to: 5, want: block},
+			{from: 6, to: 8, want: block},
+			{from: 1, to: 5, want: 2 * block},
+			{from: 1, to: 6, want: math.Inf(1)},
+		} {
+			e := g.Edge(osm[test.from], osm[test.to])
+			var got float64
+			if e == nil {
+				got = math.Inf(1)
+			} else {
+				got = e.Weight()
+			}
+			if math.Abs(got-test.want) > 0.1 && !(math.IsInf(got, 1) && math.IsInf(test.want, 1)) {
+				t.Errorf("unexpected weight for edge %d->%d: got:%v want:%v", test.from, test.to, got, test.want)
+			}
+		}
+
+		e := g.Edge(osm[1], osm[2]).(Edge)
+		wantTags := []graph.Attribute{{Key: "highway", Value: "primary"}, {Key: "name", Value: "South Road"}}
+		if e.Way != 100 || !reflect.DeepEqual(e.Tags, wantTags) {
+			t.Errorf("unexpected edge way: got:%d %v want:100 %v", e.Way, e.Tags, wantTags)
+		}
+
+		pt, _ := path.AStar(osm[5], osm[2], g, spatial.GreatCircleHeuristic)
+		p, w := pt.To(osm[2])
+		if len(p) != 3 || math.Abs(w-3*block) > 0.1 {
+			t.Errorf("unexpected path against oneway: got:%d nodes weight %v want:3 nodes weight %v", len(p), w, 3*block)
+		}
+	}
+}
+
+func TestDecodeFilter(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	err := DecodeWith(bytes.NewReader(pbf(testNodes, testWays, true)), g, DecodeOptions{
+		Filter: func(tags map[string]string) bool { return tags["highway"] == "primary" },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the ends of the primary roads are split
+	// points, so the square becomes a pair of parallel
+	// sections of which the shorter is retained.
+	nodes := g.Nodes()
+	if len(nodes) != 2 {
+		t.Fatalf("unexpected number of nodes: got:%d want:2", len(nodes))
+	}
+	for _, e := range [][2]graph.Node{{nodes[0], nodes[1]}, {nodes[1], nodes[0]}} {
+		got := g.Edge(e[0], e[1]).(Edge)
+		if got.Way != 100 || math.Abs(got.W-2*1111.95) > 0.1 {
+			t.Errorf("unexpected retained section: got:way %d weight %v want:way 100 weight %v", got.Way, got.W, 2*1111.95)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	data := pbf(testNodes, testWays, true)
+	// Truncate within the final data blob.
+	for i := len(data) - 50; i < len(data); i++ {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		if err := Decode(bytes.NewReader(data[:i]), g); err == nil {
+			t.Errorf("expected error for truncated file at %d bytes", i)
+		}
+	}
+}
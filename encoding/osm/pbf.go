@@ -0,0 +1,382 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// The PBF format is described at http://wiki.openstreetmap.org/wiki/PBF_Format.
+// Only the protocol buffer fields needed to construct road networks are
+// decoded; all other fields are skipped.
+
+const (
+	// maxHeaderSize and maxBlobSize are the
+	// limits given by the PBF specification.
+	maxHeaderSize = 64 << 10
+	maxBlobSize   = 32 << 20
+)
+
+// Protocol buffer wire types.
+const (
+	varint  = 0
+	fixed64 = 1
+	bytesT  = 2
+	fixed32 = 5
+)
+
+// fields calls fn for each field of the protocol buffer message in b.
+// For varint fields, v holds the value, and for length-delimited fields
+// data holds the field's bytes. Fixed width fields are skipped.
+func fields(b []byte, fn func(field, wire int, v uint64, data []byte) error) error {
+	for len(b) != 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errBadMessage
+		}
+		b = b[n:]
+		field, wire := int(key>>3), int(key&7)
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wire {
+		case varint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errBadMessage
+			}
+			b = b[n:]
+		case fixed64:
+			if len(b) < 8 {
+				return errBadMessage
+			}
+			b = b[8:]
+			continue
+		case bytesT:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errBadMessage
+			}
+			data = b[n : n+int(l)]
+			b = b[n+int(l):]
+		case fixed32:
+			if len(b) < 4 {
+				return errBadMessage
+			}
+			b = b[4:]
+			continue
+		default:
+			return errBadMessage
+		}
+		err := fn(field, wire, v, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errBadMessage = errors.New("osm: malformed protocol buffer message")
+
+// packed calls fn for each varint value of a repeated field, which may be
+// encoded as a packed or unpacked field.
+func packed(wire int, v uint64, data []byte, fn func(uint64)) error {
+	if wire == varint {
+		fn(v)
+		return nil
+	}
+	for len(data) != 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errBadMessage
+		}
+		fn(v)
+		data = data[n:]
+	}
+	return nil
+}
+
+// zigzag decodes a protocol buffer sint64 value.
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// blobReader reads the blobs of a PBF file.
+type blobReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// next returns the type and decompressed data of the next blob in the
+// file. At the end of the file next returns io.EOF.
+func (br *blobReader) next() (typ string, data []byte, err error) {
+	var size [4]byte
+	_, err = io.ReadFull(br.r, size[:])
+	if err != nil {
+		return "", nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxHeaderSize {
+		return "", nil, fmt.Errorf("osm: blob header too large: %d bytes", n)
+	}
+	header, err := br.read(int(n))
+	if err != nil {
+		return "", nil, err
+	}
+	var blobSize uint64
+	err = fields(header, func(field, wire int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			typ = string(data)
+		case 3:
+			blobSize = v
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if blobSize > maxBlobSize {
+		return "", nil, fmt.Errorf("osm: blob too large: %d bytes", blobSize)
+	}
+	blob, err := br.read(int(blobSize))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		raw, compressed []byte
+		rawSize         uint64
+		unsupported     bool
+	)
+	err = fields(blob, func(field, wire int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			raw = data
+		case 2:
+			rawSize = v
+		case 3:
+			compressed = data
+		case 4, 5, 6, 7:
+			unsupported = true
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return "", nil, err
+	case raw != nil:
+		return typ, raw, nil
+	case compressed != nil:
+		if rawSize > maxBlobSize {
+			return "", nil, fmt.Errorf("osm: blob too large: %d bytes", rawSize)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return "", nil, err
+		}
+		data, err = ioutil.ReadAll(io.LimitReader(zr, maxBlobSize))
+		if err != nil {
+			return "", nil, err
+		}
+		return typ, data, zr.Close()
+	case unsupported:
+		return "", nil, errors.New("osm: unsupported blob compression")
+	}
+	return typ, nil, nil
+}
+
+// read returns the next n bytes of the file. The returned slice is
+// only valid until the next call to read.
+func (br *blobReader) read(n int) ([]byte, error) {
+	if cap(br.buf) < n {
+		br.buf = make([]byte, n)
+	}
+	b := br.buf[:n]
+	_, err := io.ReadFull(br.r, b)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+// supported is the set of required features that the decoder handles.
+var supported = map[string]bool{
+	"OsmSchema-V0.6": true,
+	"DenseNodes":     true,
+}
+
+// header checks that the features required by an OSMHeader block are
+// supported.
+func header(data []byte) error {
+	return fields(data, func(field, wire int, v uint64, data []byte) error {
+		if field == 4 && !supported[string(data)] {
+			return fmt.Errorf("osm: unsupported required feature: %q", data)
+		}
+		return nil
+	})
+}
+
+// block is the decoding state of an OSMData primitive block.
+type block struct {
+	strings     [][]byte
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+// primitives decodes an OSMData block, calling node for each node
+// and way for each way it holds.
+func primitives(data []byte, node func(id int64, lat, lon float64), way func(id int64, tags map[string]string, refs []int64)) error {
+	b := block{granularity: 100}
+	var groups [][]byte
+	err := fields(data, func(field, wire int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			return fields(data, func(field, wire int, v uint64, data []byte) error {
+				if field == 1 {
+					b.strings = append(b.strings, data)
+				}
+				return nil
+			})
+		case 2:
+			groups = append(groups, data)
+		case 17:
+			b.granularity = int64(v)
+		case 19:
+			b.latOffset = int64(v)
+		case 20:
+			b.lonOffset = int64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Groups are decoded after the block's fields
+	// since the string table and coordinate scaling
+	// may follow them.
+	for _, g := range groups {
+		err = fields(g, func(field, wire int, v uint64, data []byte) error {
+			switch field {
+			case 1:
+				return b.node(data, node)
+			case 2:
+				return b.dense(data, node)
+			case 3:
+				return b.way(data, way)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *block) coord(offset, v int64) float64 {
+	return 1e-9 * float64(offset+b.granularity*v)
+}
+
+func (b *block) node(data []byte, node func(id int64, lat, lon float64)) error {
+	var id, lat, lon int64
+	err := fields(data, func(field, wire int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			id = zigzag(v)
+		case 8:
+			lat = zigzag(v)
+		case 9:
+			lon = zigzag(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	node(id, b.coord(b.latOffset, lat), b.coord(b.lonOffset, lon))
+	return nil
+}
+
+func (b *block) dense(data []byte, node func(id int64, lat, lon float64)) error {
+	var ids, lats, lons []int64
+	err := fields(data, func(field, wire int, v uint64, data []byte) error {
+		var dst *[]int64
+		switch field {
+		case 1:
+			dst = &ids
+		case 8:
+			dst = &lats
+		case 9:
+			dst = &lons
+		default:
+			return nil
+		}
+		return packed(wire, v, data, func(v uint64) { *dst = append(*dst, zigzag(v)) })
+	})
+	if err != nil {
+		return err
+	}
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return errors.New("osm: mismatched dense node field lengths")
+	}
+	var id, lat, lon int64
+	for i := range ids {
+		id += ids[i]
+		lat += lats[i]
+		lon += lons[i]
+		node(id, b.coord(b.latOffset, lat), b.coord(b.lonOffset, lon))
+	}
+	return nil
+}
+
+func (b *block) way(data []byte, way func(id int64, tags map[string]string, refs []int64)) error {
+	var (
+		id         int64
+		keys, vals []uint64
+		refs       []int64
+		ref        int64
+	)
+	err := fields(data, func(field, wire int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			id = int64(v)
+		case 2:
+			return packed(wire, v, data, func(v uint64) { keys = append(keys, v) })
+		case 3:
+			return packed(wire, v, data, func(v uint64) { vals = append(vals, v) })
+		case 8:
+			return packed(wire, v, data, func(v uint64) {
+				ref += zigzag(v)
+				refs = append(refs, ref)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(keys) != len(vals) {
+		return fmt.Errorf("osm: mismatched tag lengths for way %d", id)
+	}
+	tags := make(map[string]string, len(keys))
+	for i, k := range keys {
+		if k >= uint64(len(b.strings)) || vals[i] >= uint64(len(b.strings)) {
+			return fmt.Errorf("osm: string table index out of range for way %d", id)
+		}
+		tags[string(b.strings[k])] = string(b.strings[vals[i]])
+	}
+	way(id, tags, refs)
+	return nil
+}
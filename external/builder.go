@@ -0,0 +1,343 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package external provides construction of, and read-only access to, graphs
+// stored on disk in compressed sparse row form, for graphs too large to hold
+// in memory.
+//
+// The file format is little-endian and is laid out as
+//
+//	magic    [4]byte  "GCSR"
+//	version  uint32   1
+//	n, m     uint64   the number of nodes and edges
+//	ids      [n]int64 the sorted node IDs
+//	out      [n+1]uint64
+//	in       [n+1]uint64
+//	targets  [m]int64
+//	weights  [m]float64
+//	sources  [m]int64
+//
+// The edges from the node ids[i] lead to the nodes with IDs held in
+// targets[out[i]:out[i+1]], in ascending order and with the corresponding
+// weights in weights, and the edges to the node are from the nodes with IDs
+// held in sources[in[i]:in[i+1]], in ascending order.
+package external
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+)
+
+const (
+	magic   = "GCSR"
+	version = 1
+
+	headerSize = 24
+)
+
+// DefaultRunSize is the number of records held in memory by a Builder
+// before they are written to a run file if no run size is specified.
+const DefaultRunSize = 1 << 20
+
+// Builder constructs a graph file from an unordered stream of nodes and
+// edges that may be larger than the available memory. Nodes and edges are
+// sorted externally, in runs written to temporary files, and the runs are
+// merged when the graph file is built.
+type Builder struct {
+	nodes, edges sorter
+	seq          int64
+	built        bool
+}
+
+// NewBuilder returns a Builder that holds runSize records in memory and
+// writes its run files in the directory dir. If dir is empty, the default
+// directory for temporary files is used. If runSize is not positive,
+// DefaultRunSize is used. Each record uses 32 bytes of memory, and each
+// added edge creates two node records and, when the graph file is built,
+// a second edge record.
+func NewBuilder(dir string, runSize int) *Builder {
+	if runSize <= 0 {
+		runSize = DefaultRunSize
+	}
+	return &Builder{
+		nodes: sorter{dir: dir, size: runSize},
+		edges: sorter{dir: dir, size: runSize},
+	}
+}
+
+var errBuilt = errors.New("external: builder already used")
+
+// AddNode adds a node with the given ID to the graph. Adding a node
+// more than once has no further effect.
+func (b *Builder) AddNode(id int) error {
+	if b.built {
+		return errBuilt
+	}
+	return b.nodes.add(record{a: int64(id)})
+}
+
+// AddEdge adds an edge from the node with ID from to the node with ID to,
+// with the weight w, adding the nodes if they have not been added. If the
+// edge is added more than once, the last added weight is retained.
+func (b *Builder) AddEdge(from, to int, w float64) error {
+	if b.built {
+		return errBuilt
+	}
+	err := b.nodes.add(record{a: int64(from)})
+	if err != nil {
+		return err
+	}
+	err = b.nodes.add(record{a: int64(to)})
+	if err != nil {
+		return err
+	}
+	b.seq++
+	return b.edges.add(record{a: int64(from), b: int64(to), w: w, seq: b.seq})
+}
+
+// Build merges the added nodes and edges and writes the graph file to dst.
+// The Builder's temporary files are removed, and the Builder may not be
+// used after Build has been called.
+func (b *Builder) Build(dst io.Writer) (err error) {
+	if b.built {
+		return errBuilt
+	}
+	b.built = true
+	defer b.Close()
+
+	dir := b.nodes.dir
+	nodes, err := ioutil.TempFile(dir, "graph-nodes-")
+	if err != nil {
+		return err
+	}
+	defer remove(nodes)
+	edges, err := ioutil.TempFile(dir, "graph-edges-")
+	if err != nil {
+		return err
+	}
+	defer remove(edges)
+	rev, err := ioutil.TempFile(dir, "graph-rev-")
+	if err != nil {
+		return err
+	}
+	defer remove(rev)
+
+	// Write the unique node IDs.
+	var n int64
+	nw := bufio.NewWriter(nodes)
+	last := int64(math.MinInt64)
+	err = b.nodes.merge(func(r record) error {
+		if n != 0 && r.a == last {
+			return nil
+		}
+		last = r.a
+		n++
+		return binary.Write(nw, binary.LittleEndian, r.a)
+	})
+	if err != nil {
+		return err
+	}
+	err = nw.Flush()
+	if err != nil {
+		return err
+	}
+
+	// Write the edges retaining the last added of any
+	// duplicates, and sort them by their to node.
+	var m int64
+	ew := bufio.NewWriter(edges)
+	reversed := sorter{dir: dir, size: b.edges.size}
+	defer reversed.remove()
+	var pending record
+	emit := func() error {
+		m++
+		err := binary.Write(ew, binary.LittleEndian, [3]uint64{uint64(pending.a), uint64(pending.b), math.Float64bits(pending.w)})
+		if err != nil {
+			return err
+		}
+		return reversed.add(record{a: pending.b, b: pending.a})
+	}
+	var seen bool
+	err = b.edges.merge(func(r record) error {
+		if seen && (r.a != pending.a || r.b != pending.b) {
+			err := emit()
+			if err != nil {
+				return err
+			}
+		}
+		pending, seen = r, true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if seen {
+		err = emit()
+		if err != nil {
+			return err
+		}
+	}
+	err = ew.Flush()
+	if err != nil {
+		return err
+	}
+	rw := bufio.NewWriter(rev)
+	err = reversed.merge(func(r record) error {
+		return binary.Write(rw, binary.LittleEndian, [2]int64{r.a, r.b})
+	})
+	if err != nil {
+		return err
+	}
+	err = rw.Flush()
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(dst)
+	_, err = w.WriteString(magic)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(w, binary.LittleEndian, uint32(version))
+	if err != nil {
+		return err
+	}
+	err = binary.Write(w, binary.LittleEndian, [2]uint64{uint64(n), uint64(m)})
+	if err != nil {
+		return err
+	}
+	_, err = nodes.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, bufio.NewReader(nodes))
+	if err != nil {
+		return err
+	}
+	err = offsets(w, nodes, edges, 24)
+	if err != nil {
+		return err
+	}
+	err = offsets(w, nodes, rev, 16)
+	if err != nil {
+		return err
+	}
+	err = column(w, edges, 24, 8)
+	if err != nil {
+		return err
+	}
+	err = column(w, edges, 24, 16)
+	if err != nil {
+		return err
+	}
+	err = column(w, rev, 16, 8)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// Close removes the Builder's temporary files. The Builder may not be used
+// after Close has been called.
+func (b *Builder) Close() error {
+	b.built = true
+	b.nodes.remove()
+	b.edges.remove()
+	b.nodes.buf = nil
+	b.edges.buf = nil
+	return nil
+}
+
+func remove(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// offsets writes the CSR offsets of the edge file, which holds records of
+// the given size starting with the from node ID, for each node in nodes.
+func offsets(w io.Writer, nodes, edges *os.File, size int) error {
+	_, err := nodes.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+	_, err = edges.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+	nr := bufio.NewReader(nodes)
+	er := bufio.NewReader(edges)
+	rec := make([]byte, size)
+	var (
+		off  uint64
+		from int64
+		more bool
+	)
+	next := func() error {
+		_, err := io.ReadFull(er, rec)
+		if err == io.EOF {
+			more = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		more = true
+		from = int64(binary.LittleEndian.Uint64(rec))
+		return nil
+	}
+	err = next()
+	if err != nil {
+		return err
+	}
+	var id [8]byte
+	for {
+		err = binary.Write(w, binary.LittleEndian, off)
+		if err != nil {
+			return err
+		}
+		_, err = io.ReadFull(nr, id[:])
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for more && from == int64(binary.LittleEndian.Uint64(id[:])) {
+			off++
+			err = next()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// column writes the 8 byte field at offset off of each record of the
+// given size in the file f.
+func column(w io.Writer, f *os.File, size, off int) error {
+	_, err := f.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+	rec := make([]byte, size)
+	for {
+		_, err = io.ReadFull(r, rec)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(rec[off : off+8])
+		if err != nil {
+			return err
+		}
+	}
+}
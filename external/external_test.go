@@ -0,0 +1,174 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+var _ graph.Directed = (*Graph)(nil)
+
+func ids(nodes []graph.Node) []int {
+	sort.Sort(ordered.ByID(nodes))
+	ids := make([]int, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+func TestBuilder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph-external-test-")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 20, 200} {
+		for _, runSize := range []int{1, 7, 0} {
+			want := simple.NewDirectedGraph(0, math.Inf(1))
+			b := NewBuilder(dir, runSize)
+			for i := 0; i < n; i++ {
+				// Isolated nodes are added explicitly and
+				// may be added more than once.
+				if rnd.Intn(4) == 0 {
+					id := rnd.Intn(3*n) - n
+					if !want.Has(simple.Node(id)) {
+						want.AddNode(simple.Node(id))
+					}
+					err = b.AddNode(id)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				}
+			}
+			for i := 0; i < 5*n; i++ {
+				u, v := rnd.Intn(3*n)-n, rnd.Intn(3*n)-n
+				if u == v {
+					continue
+				}
+				w := float64(rnd.Intn(100))
+				// Later duplicates override earlier edges.
+				want.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: w})
+				err = b.AddEdge(u, v, w)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			var buf bytes.Buffer
+			err = b.Build(&buf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err = b.AddNode(0); err == nil {
+				t.Errorf("expected error adding to built graph")
+			}
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(files) != 0 {
+				t.Errorf("temporary files not removed: %d remain", len(files))
+			}
+
+			g, err := NewGraph(bytes.NewReader(buf.Bytes()), 0, math.Inf(1))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(ids(g.Nodes()), ids(want.Nodes())) {
+				t.Fatalf("unexpected nodes for n=%d runSize=%d:\ngot: %v\nwant:%v",
+					n, runSize, ids(g.Nodes()), ids(want.Nodes()))
+			}
+			for _, u := range want.Nodes() {
+				if got, want := ids(g.From(u)), ids(want.From(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected from nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				if got, want := ids(g.To(u)), ids(want.To(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected to nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				if g.Degree(u) != want.Degree(u) {
+					t.Errorf("unexpected degree for %d: got:%d want:%d", u.ID(), g.Degree(u), want.Degree(u))
+				}
+				for _, v := range want.Nodes() {
+					got, gotOK := g.Weight(u, v)
+					want, wantOK := want.Weight(u, v)
+					if got != want || gotOK != wantOK {
+						t.Errorf("unexpected weight for %d->%d: got:%v,%t want:%v,%t", u.ID(), v.ID(), got, gotOK, want, wantOK)
+					}
+				}
+			}
+			if g.Has(simple.Node(4 * n)) {
+				t.Errorf("unexpected node %d", 4*n)
+			}
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph-external-test-")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := NewBuilder(dir, 2)
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 0}, {0, 2}} {
+		err = b.AddEdge(e[0], e[1], float64(e[0]+e[1]))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	name := filepath.Join(dir, "graph.csr")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = b.Build(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Close()
+
+	g, err := Open(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer g.Close()
+	if e := g.Edge(simple.Node(0), simple.Node(2)); e == nil || e.Weight() != 2 {
+		t.Errorf("unexpected edge: got:%v want:0->2 weight 2", e)
+	}
+	if got := ids(g.To(simple.Node(2))); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("unexpected to nodes: got:%v want:[0 1]", got)
+	}
+
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range [][]byte{
+		nil,
+		[]byte("not a graph file at all"),
+		data[:len(data)-1],
+	} {
+		_, err = NewGraph(bytes.NewReader(bad), 0, math.Inf(1))
+		if err == nil {
+			t.Errorf("expected error for invalid graph file of length %d", len(bad))
+		}
+	}
+}
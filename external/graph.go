@@ -0,0 +1,262 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Graph is a read-only directed graph held in a graph file. Nodes and edges
+// are read from the file as they are needed, so methods that find a node or
+// edge take time logarithmic in the number of nodes plus a small number of
+// reads. Graph methods panic if the file cannot be read.
+//
+// Only the IDs of nodes and the end points and weights of edges are held
+// in the file, so nodes are returned as simple.Node values and edges as
+// simple.Edge values.
+type Graph struct {
+	r      io.ReaderAt
+	closer io.Closer
+
+	n, m int64
+
+	// Offsets of the sections of the file.
+	ids, out, in, targets, weights, sources int64
+
+	self, absent float64
+}
+
+// Open opens the named graph file. The returned Graph has self and absent
+// edge weight values of zero and +Inf.
+func Open(name string) (*Graph, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	g, err := NewGraph(f, 0, math.Inf(1))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	g.closer = f
+	return g, nil
+}
+
+// NewGraph returns a Graph reading the graph file held in r, with the
+// specified self and absent edge weight values.
+func NewGraph(r io.ReaderAt, self, absent float64) (*Graph, error) {
+	var header [headerSize]byte
+	_, err := r.ReadAt(header[:], 0)
+	if err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != magic {
+		return nil, errors.New("external: not a graph file")
+	}
+	if v := binary.LittleEndian.Uint32(header[4:]); v != version {
+		return nil, fmt.Errorf("external: unsupported graph file version: %d", v)
+	}
+	g := &Graph{
+		r:      r,
+		n:      int64(binary.LittleEndian.Uint64(header[8:])),
+		m:      int64(binary.LittleEndian.Uint64(header[16:])),
+		self:   self,
+		absent: absent,
+	}
+	g.ids = headerSize
+	g.out = g.ids + 8*g.n
+	g.in = g.out + 8*(g.n+1)
+	g.targets = g.in + 8*(g.n+1)
+	g.weights = g.targets + 8*g.m
+	g.sources = g.weights + 8*g.m
+
+	// Check that the file is complete.
+	var last [8]byte
+	if g.n != 0 || g.m != 0 {
+		_, err = r.ReadAt(last[:], g.sources+8*g.m-8)
+		if err != nil {
+			return nil, fmt.Errorf("external: truncated graph file: %v", err)
+		}
+	}
+	return g, nil
+}
+
+// Close closes the graph file if the Graph was returned by Open.
+func (g *Graph) Close() error {
+	if g.closer == nil {
+		return nil
+	}
+	return g.closer.Close()
+}
+
+// word returns the 8 byte word at offset off in the file.
+func (g *Graph) word(off int64) uint64 {
+	var buf [8]byte
+	_, err := g.r.ReadAt(buf[:], off)
+	if err != nil {
+		panic(fmt.Sprintf("external: %v", err))
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// words returns the n 8 byte words at offset off in the file.
+func (g *Graph) words(off, n int64) []uint64 {
+	buf := make([]byte, 8*n)
+	_, err := g.r.ReadAt(buf, off)
+	if err != nil && !(err == io.EOF && n == 0) {
+		panic(fmt.Sprintf("external: %v", err))
+	}
+	w := make([]uint64, n)
+	for i := range w {
+		w[i] = binary.LittleEndian.Uint64(buf[8*i:])
+	}
+	return w
+}
+
+// search returns the smallest index in [lo, hi) of the int64 column at
+// offset off with a value not less than id, and whether the value at the
+// index is id.
+func (g *Graph) search(off, lo, hi, id int64) (int64, bool) {
+	i := lo + int64(sort.Search(int(hi-lo), func(i int) bool {
+		return int64(g.word(off+8*(lo+int64(i)))) >= id
+	}))
+	return i, i < hi && int64(g.word(off+8*i)) == id
+}
+
+// index returns the index of the node with the given ID, or -1 if there is
+// no such node.
+func (g *Graph) index(id int) int64 {
+	i, ok := g.search(g.ids, 0, g.n, int64(id))
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// span returns the range of edge positions for the node with index i in
+// the offsets section at off.
+func (g *Graph) span(off, i int64) (lo, hi int64) {
+	w := g.words(off+8*i, 2)
+	return int64(w[0]), int64(w[1])
+}
+
+// Node returns the node in the graph with the given ID, or nil if no
+// such node exists.
+func (g *Graph) Node(id int) graph.Node {
+	if g.index(id) < 0 {
+		return nil
+	}
+	return simple.Node(id)
+}
+
+// Has returns whether the node exists within the graph.
+func (g *Graph) Has(n graph.Node) bool {
+	return g.index(n.ID()) >= 0
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *Graph) Nodes() []graph.Node {
+	return nodes(g.words(g.ids, g.n))
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *Graph) From(n graph.Node) []graph.Node {
+	i := g.index(n.ID())
+	if i < 0 {
+		return nil
+	}
+	lo, hi := g.span(g.out, i)
+	return nodes(g.words(g.targets+8*lo, hi-lo))
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (g *Graph) To(n graph.Node) []graph.Node {
+	i := g.index(n.ID())
+	if i < 0 {
+		return nil
+	}
+	lo, hi := g.span(g.in, i)
+	return nodes(g.words(g.sources+8*lo, hi-lo))
+}
+
+func nodes(ids []uint64) []graph.Node {
+	nodes := make([]graph.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = simple.Node(int64(id))
+	}
+	return nodes
+}
+
+// edge returns the position of the edge from u to v, or -1 if there is
+// no such edge.
+func (g *Graph) edge(u, v graph.Node) int64 {
+	i := g.index(u.ID())
+	if i < 0 {
+		return -1
+	}
+	lo, hi := g.span(g.out, i)
+	k, ok := g.search(g.targets, lo, hi, int64(v.ID()))
+	if !ok {
+		return -1
+	}
+	return k
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *Graph) HasEdgeBetween(x, y graph.Node) bool {
+	return g.edge(x, y) >= 0 || g.edge(y, x) >= 0
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *Graph) HasEdgeFromTo(u, v graph.Node) bool {
+	return g.edge(u, v) >= 0
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *Graph) Edge(u, v graph.Node) graph.Edge {
+	k := g.edge(u, v)
+	if k < 0 {
+		return nil
+	}
+	w := math.Float64frombits(g.word(g.weights + 8*k))
+	return simple.Edge{F: simple.Node(u.ID()), T: simple.Node(v.ID()), W: w}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (g *Graph) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return g.self, true
+	}
+	if k := g.edge(x, y); k >= 0 {
+		return math.Float64frombits(g.word(g.weights + 8*k)), true
+	}
+	return g.absent, false
+}
+
+// Degree returns the in+out degree of n in g.
+func (g *Graph) Degree(n graph.Node) int {
+	i := g.index(n.ID())
+	if i < 0 {
+		return 0
+	}
+	lo, hi := g.span(g.out, i)
+	d := hi - lo
+	lo, hi = g.span(g.in, i)
+	return int(d + hi - lo)
+}
@@ -0,0 +1,211 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+)
+
+// record is an element of an external sort. Edge records hold the
+// end points of the edge in a and b, and node records hold the node
+// ID in a.
+type record struct {
+	a, b int64
+	w    float64
+
+	// seq is the order in which the
+	// record was added, used to order
+	// records with equal keys.
+	seq int64
+}
+
+const recordSize = 32
+
+func (r *record) encode(buf []byte) {
+	binary.LittleEndian.PutUint64(buf[0:], uint64(r.a))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(r.b))
+	binary.LittleEndian.PutUint64(buf[16:], math.Float64bits(r.w))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(r.seq))
+}
+
+func (r *record) decode(buf []byte) {
+	r.a = int64(binary.LittleEndian.Uint64(buf[0:]))
+	r.b = int64(binary.LittleEndian.Uint64(buf[8:]))
+	r.w = math.Float64frombits(binary.LittleEndian.Uint64(buf[16:]))
+	r.seq = int64(binary.LittleEndian.Uint64(buf[24:]))
+}
+
+// less orders records by a, then b, then seq.
+func less(x, y record) bool {
+	if x.a != y.a {
+		return x.a < y.a
+	}
+	if x.b != y.b {
+		return x.b < y.b
+	}
+	return x.seq < y.seq
+}
+
+// sorter is an external merge sort of records. Records are held in
+// memory until size records are added, and are then sorted and written
+// to a run file in dir.
+type sorter struct {
+	dir  string
+	size int
+	buf  []record
+	runs []string
+}
+
+func (s *sorter) add(r record) error {
+	s.buf = append(s.buf, r)
+	if len(s.buf) >= s.size {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered records to a new run file.
+func (s *sorter) flush() (err error) {
+	if len(s.buf) == 0 {
+		return nil
+	}
+	sort.Sort(records(s.buf))
+	f, err := ioutil.TempFile(s.dir, "graph-run-")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f.Name())
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	w := bufio.NewWriter(f)
+	var rec [recordSize]byte
+	for i := range s.buf {
+		s.buf[i].encode(rec[:])
+		_, err = w.Write(rec[:])
+		if err != nil {
+			return err
+		}
+	}
+	s.buf = s.buf[:0]
+	return w.Flush()
+}
+
+// merge calls fn for each added record in order and removes the
+// sorter's run files.
+func (s *sorter) merge(fn func(record) error) error {
+	defer s.remove()
+	if len(s.runs) == 0 {
+		// All the records fit in memory.
+		sort.Sort(records(s.buf))
+		for _, r := range s.buf {
+			err := fn(r)
+			if err != nil {
+				return err
+			}
+		}
+		s.buf = nil
+		return nil
+	}
+	err := s.flush()
+	if err != nil {
+		return err
+	}
+	s.buf = nil
+
+	var h runHeap
+	for _, name := range s.runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r := &run{r: bufio.NewReader(f)}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h = append(h, r)
+		}
+	}
+	heap.Init(&h)
+	for len(h) != 0 {
+		r := h[0]
+		err = fn(r.rec)
+		if err != nil {
+			return err
+		}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	return nil
+}
+
+// remove removes the sorter's run files.
+func (s *sorter) remove() {
+	for _, name := range s.runs {
+		os.Remove(name)
+	}
+	s.runs = nil
+}
+
+// run is a sorted run file being merged.
+type run struct {
+	r   *bufio.Reader
+	rec record
+	buf [recordSize]byte
+}
+
+// next reads the next record of the run, returning false at the end
+// of the run.
+func (r *run) next() (bool, error) {
+	_, err := io.ReadFull(r.r, r.buf[:])
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	r.rec.decode(r.buf[:])
+	return true, nil
+}
+
+// runHeap is a min-heap of runs ordered by their current record.
+type runHeap []*run
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return less(h[i].rec, h[j].rec) }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(*run)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// records sorts records by a, then b, then seq.
+type records []record
+
+func (r records) Len() int           { return len(r) }
+func (r records) Less(i, j int) bool { return less(r[i], r[j]) }
+func (r records) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
@@ -61,6 +61,18 @@ type Directed interface {
 	To(Node) []Node
 }
 
+// Mixed is a graph that may hold both directed and undirected edges.
+// An undirected edge between u and v may be traversed in either
+// direction, so it is reported by From and To for both of its
+// terminal nodes and HasEdgeFromTo is true for both orderings.
+type Mixed interface {
+	Directed
+
+	// IsUndirected returns whether the edge
+	// from u to v is an undirected edge.
+	IsUndirected(u, v Node) bool
+}
+
 // Weighter defines graphs that can report edge weights.
 type Weighter interface {
 	// Weight returns the weight for the edge between
@@ -74,6 +86,27 @@ type Weighter interface {
 	Weight(x, y Node) (w float64, ok bool)
 }
 
+// Attribute is an encoding-independent key value attribute pair.
+type Attribute struct {
+	Key, Value string
+}
+
+// Attributer defines Graph, Node or Edge values that can specify
+// attributes. Encoders write the attributes in the form of their
+// encoding; where an encoding defines its own attribute interface,
+// values implementing it take precedence.
+type Attributer interface {
+	Attributes() []Attribute
+}
+
+// AttributeSetter defines Graph, Node or Edge values that can hold
+// attributes. Decoders pass the attributes of decoded values to
+// SetAttribute, which should return an error if the attribute is not
+// recognised or its value is not valid.
+type AttributeSetter interface {
+	SetAttribute(Attribute) error
+}
+
 // NodeAdder is an interface for adding arbitrary nodes to a graph.
 type NodeAdder interface {
 	// NewNodeID returns a new unique arbitrary ID.
@@ -129,6 +162,20 @@ type DirectedBuilder interface {
 	Builder
 }
 
+// MixedBuilder is a mixed graph builder. Edges
+// added by SetEdge are directed.
+type MixedBuilder interface {
+	Mixed
+	Builder
+
+	// SetUndirectedEdge adds an undirected edge
+	// between the terminal nodes of e. If the nodes
+	// do not exist, they are added. If the IDs
+	// returned by e.From and e.To are equal,
+	// SetUndirectedEdge will panic.
+	SetUndirectedEdge(e Edge)
+}
+
 // Copy copies nodes and edges as undirected edges from the source to the destination
 // without first clearing the destination. Copy will panic if a node ID in the source
 // graph matches a node ID in the destination.
@@ -139,15 +186,110 @@ type DirectedBuilder interface {
 // If the source is a directed graph, the destination is undirected, and a fundamental
 // cycle exists with two nodes where the edge weights differ, the resulting destination
 // graph's edge weight between those nodes is undefined. If there is a defined function
-// to resolve such conflicts, an Undirect may be used to do this.
+// to resolve such conflicts, CopyWithOptions or an Undirect may be used to do this.
 func Copy(dst Builder, src Graph) {
+	CopyWithOptions(dst, src, CopyOptions{})
+}
+
+// CopyOptions holds options for CopyWithOptions.
+type CopyOptions struct {
+	// Merge defines how the weights of
+	// reciprocal edges in a directed
+	// source are resolved when copying
+	// to an undirected destination. The
+	// edges corresponding to the two
+	// weights are also passed, in the
+	// same order. The order of weight
+	// parameters passed to Merge is not
+	// defined, so the function should
+	// be commutative.
+	// If Merge is nil, the weight of
+	// the destination edge is undefined
+	// as described for Copy.
+	Merge func(x, y float64, xe, ye Edge) float64
+}
+
+// CopyWithOptions copies nodes and edges from the source to the destination in the
+// same way as Copy, using opts to resolve the weights of reciprocal edges when the
+// source is directed and the destination is undirected. In that case each pair of
+// reciprocal edges is copied to the destination as a single EdgePair holding both
+// source edges and the merged weight. Edges without a reciprocal and self edges are
+// copied unaltered.
+func CopyWithOptions(dst Builder, src Graph, opts CopyOptions) {
 	nodes := src.Nodes()
 	for _, n := range nodes {
 		dst.AddNode(n)
 	}
+
+	d, isDirected := src.(Directed)
+	_, toUndirected := dst.(Undirected)
+	merge := opts.Merge != nil && isDirected && toUndirected
+	for _, u := range nodes {
+		for _, v := range src.From(u) {
+			if !merge || u.ID() == v.ID() || !d.HasEdgeFromTo(v, u) {
+				dst.SetEdge(src.Edge(u, v))
+				continue
+			}
+			if u.ID() > v.ID() {
+				// The pair is set when
+				// visited from v.
+				continue
+			}
+			uv, vu := src.Edge(u, v), src.Edge(v, u)
+			dst.SetEdge(EdgePair{
+				E: [2]Edge{uv, vu},
+				W: opts.Merge(uv.Weight(), vu.Weight(), uv, vu),
+			})
+		}
+	}
+}
+
+// CopyWith copies nodes and edges from the source to the destination in the same
+// way as Copy, transforming them on the way. The nodeFn function is applied to each
+// node of src and the returned node is added to dst. The edgeFn function is applied
+// to each edge of src and the returned edge is set in dst, unless it is nil in which
+// case the edge is not copied. Edges returned by edgeFn must have terminal nodes that
+// are consistent with the mapping performed by nodeFn.
+//
+// If nodeFn is nil, nodes are copied unaltered. If edgeFn is nil, edges are copied
+// with their weight and with their terminal nodes replaced by the result of nodeFn.
+func CopyWith(dst Builder, src Graph, nodeFn func(Node) Node, edgeFn func(Edge) Edge) {
+	nodes := src.Nodes()
+	mapped := make(map[int]Node, len(nodes))
+	for _, n := range nodes {
+		m := n
+		if nodeFn != nil {
+			m = nodeFn(n)
+		}
+		mapped[n.ID()] = m
+		dst.AddNode(m)
+	}
 	for _, u := range nodes {
 		for _, v := range src.From(u) {
-			dst.SetEdge(src.Edge(u, v))
+			e := src.Edge(u, v)
+			if edgeFn != nil {
+				e = edgeFn(e)
+				if e == nil {
+					continue
+				}
+			} else if nodeFn != nil {
+				e = mappedEdge{
+					f: mapped[e.From().ID()],
+					t: mapped[e.To().ID()],
+					w: e.Weight(),
+				}
+			}
+			dst.SetEdge(e)
 		}
 	}
 }
+
+// mappedEdge is an edge with terminal nodes remapped by CopyWith.
+type mappedEdge struct {
+	f, t Node
+	w    float64
+}
+
+func (e mappedEdge) From() Node      { return e.f }
+func (e mappedEdge) To() Node        { return e.t }
+func (e mappedEdge) Weight() float64 { return e.w }
@@ -0,0 +1,363 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/layout"
+)
+
+// PointNode is a graph node with a position in the plane. The position
+// is reported as a "pos" attribute in the form "x,y". PointNode
+// satisfies the spatial.Positioned interface.
+type PointNode struct {
+	id  int
+	pos layout.Point
+}
+
+// NewPointNode returns a new PointNode with the given ID and position.
+func NewPointNode(id int, pos layout.Point) PointNode {
+	return PointNode{id: id, pos: pos}
+}
+
+// ID returns the ID number of the node.
+func (n PointNode) ID() int { return n.id }
+
+// Pos returns the position of the node.
+func (n PointNode) Pos() layout.Point { return n.pos }
+
+// Attributes returns the position of the node as a "pos" attribute.
+func (n PointNode) Attributes() []graph.Attribute {
+	return []graph.Attribute{{
+		Key:   "pos",
+		Value: strconv.FormatFloat(n.pos.X, 'g', -1, 64) + "," + strconv.FormatFloat(n.pos.Y, 'g', -1, 64),
+	}}
+}
+
+// pointEdge is an edge between two PointNodes weighted
+// by the Euclidean distance between them.
+type pointEdge struct {
+	f, t PointNode
+}
+
+func (e pointEdge) From() graph.Node { return e.f }
+func (e pointEdge) To() graph.Node   { return e.t }
+func (e pointEdge) Weight() float64  { return dist(e.f.pos, e.t.pos) }
+
+func dist(a, b layout.Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// addPoints adds a PointNode with ID i for each of the points to dst if
+// there is no node with that ID already in dst, and returns the nodes.
+func addPoints(dst GraphBuilder, points []layout.Point) []PointNode {
+	nodes := make([]PointNode, len(points))
+	for i, p := range points {
+		nodes[i] = NewPointNode(i, p)
+		if !dst.Has(nodes[i]) {
+			dst.AddNode(nodes[i])
+		}
+	}
+	return nodes
+}
+
+// RandomGeometric constructs a random geometric graph in the destination, dst, of
+// order n. The nodes are PointNodes placed uniformly at random in the unit square
+// and edges are formed between nodes separated by a Euclidean distance of at most
+// radius. Edges are weighted by the distance between their terminal nodes. If src
+// is not nil it is used as the random source, otherwise rand.Float64 is used.
+// The graph is constructed in O(n+m) expected time where m is the number of edges
+// added.
+func RandomGeometric(dst GraphBuilder, n int, radius float64, src *rand.Rand) error {
+	if !(radius >= 0) {
+		return fmt.Errorf("gen: bad radius: r=%v", radius)
+	}
+	var r func() float64
+	if src == nil {
+		r = rand.Float64
+	} else {
+		r = src.Float64
+	}
+
+	points := make([]layout.Point, n)
+	for i := range points {
+		points[i] = layout.Point{X: r(), Y: r()}
+	}
+	nodes := addPoints(dst, points)
+
+	// Bin the nodes into a grid of cells no narrower than
+	// radius so that only nodes in adjacent cells need to
+	// be considered, keeping the number of cells linear in n.
+	cells := int(math.Sqrt(float64(n))) + 1
+	if radius > 0 && 1/radius < float64(cells) {
+		cells = int(1 / radius)
+		if cells < 1 {
+			cells = 1
+		}
+	}
+	cell := func(x float64) int {
+		c := int(x * float64(cells))
+		if c == cells {
+			c--
+		}
+		return c
+	}
+	grid := make([][]int, cells*cells)
+	for i, p := range points {
+		c := cell(p.X)*cells + cell(p.Y)
+		grid[c] = append(grid[c], i)
+	}
+
+	for i, p := range points {
+		cx, cy := cell(p.X), cell(p.Y)
+		for x := cx - 1; x <= cx+1; x++ {
+			if x < 0 || x >= cells {
+				continue
+			}
+			for y := cy - 1; y <= cy+1; y++ {
+				if y < 0 || y >= cells {
+					continue
+				}
+				for _, j := range grid[x*cells+y] {
+					if j <= i || dist(p, points[j]) > radius {
+						continue
+					}
+					dst.SetEdge(pointEdge{f: nodes[i], t: nodes[j]})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Delaunay constructs the Delaunay triangulation of points in the destination, dst.
+// The nodes are PointNodes with IDs corresponding to the indices of points, and edges
+// are weighted by the distance between their terminal nodes. If four or more points
+// lie on a common circle the choice of triangulation between them is arbitrary.
+// If all the points are collinear, the constructed graph is a path through the points.
+// Delaunay returns an error if points holds duplicate or non-finite positions.
+//
+// The triangulation is constructed using the Bowyer-Watson algorithm,
+// doi:10.1093/comjnl/24.2.162 and doi:10.1093/comjnl/24.2.167, in O(n^2)
+// worst case time for n points.
+func Delaunay(dst GraphBuilder, points []layout.Point) error {
+	seen := make(map[layout.Point]int, len(points))
+	for i, p := range points {
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+			return fmt.Errorf("gen: bad point: %d at %v", i, p)
+		}
+		if j, ok := seen[p]; ok {
+			return fmt.Errorf("gen: duplicate point: %d and %d at %v", j, i, p)
+		}
+		seen[p] = i
+	}
+	nodes := addPoints(dst, points)
+	if len(points) < 2 {
+		return nil
+	}
+
+	// Find an initial triangle, or if there is none
+	// join the collinear points in order along the line.
+	a, b, c := 0, 1, -1
+	for i := 2; i < len(points); i++ {
+		if orient(points[a], points[b], points[i]) != 0 {
+			c = i
+			break
+		}
+	}
+	if c < 0 {
+		dir := layout.Point{X: points[b].X - points[a].X, Y: points[b].Y - points[a].Y}
+		line := make([]neighbor, len(points))
+		for i, p := range points {
+			line[i] = neighbor{id: i, dist: (p.X-points[a].X)*dir.X + (p.Y-points[a].Y)*dir.Y}
+		}
+		sort.Sort(byDistance(line))
+		for i := 1; i < len(line); i++ {
+			u, v := line[i-1].id, line[i].id
+			if u > v {
+				u, v = v, u
+			}
+			dst.SetEdge(pointEdge{f: nodes[u], t: nodes[v]})
+		}
+		return nil
+	}
+	if orient(points[a], points[b], points[c]) < 0 {
+		b, c = c, b
+	}
+
+	// The triangulation is held as counter-clockwise
+	// triangles and ghost triangles joining each convex
+	// hull edge to a vertex at infinity, so that points
+	// outside the hull are handled in the same way as
+	// points within it.
+	tris := []triangle{
+		{a, b, c},
+		{b, a, infinity}, {c, b, infinity}, {a, c, infinity},
+	}
+	for i := range points {
+		if i == a || i == b || i == c {
+			continue
+		}
+
+		// Remove the triangles in conflict with the
+		// new point and retriangulate the cavity.
+		var keep []triangle
+		boundary := make(map[[2]int]bool)
+		for _, t := range tris {
+			if !t.conflicts(points, points[i]) {
+				keep = append(keep, t)
+				continue
+			}
+			for k := range t {
+				e := [2]int{t[k], t[(k+1)%3]}
+				if boundary[[2]int{e[1], e[0]}] {
+					// Shared edges are interior to the cavity.
+					delete(boundary, [2]int{e[1], e[0]})
+					continue
+				}
+				boundary[e] = true
+			}
+		}
+		edges := make([][2]int, 0, len(boundary))
+		for e := range boundary {
+			edges = append(edges, e)
+		}
+		sort.Sort(byTerminals(edges))
+		for _, e := range edges {
+			switch infinity {
+			case e[0]:
+				keep = append(keep, triangle{e[1], i, infinity})
+			case e[1]:
+				keep = append(keep, triangle{i, e[0], infinity})
+			default:
+				keep = append(keep, triangle{e[0], e[1], i})
+			}
+		}
+		tris = keep
+	}
+
+	pairs := make(map[[2]int]bool)
+	for _, t := range tris {
+		if t[2] == infinity {
+			continue
+		}
+		for k := range t {
+			u, v := t[k], t[(k+1)%3]
+			if u > v {
+				u, v = v, u
+			}
+			pairs[[2]int{u, v}] = true
+		}
+	}
+	edges := make([][2]int, 0, len(pairs))
+	for e := range pairs {
+		edges = append(edges, e)
+	}
+	sort.Sort(byTerminals(edges))
+	for _, e := range edges {
+		dst.SetEdge(pointEdge{f: nodes[e[0]], t: nodes[e[1]]})
+	}
+	return nil
+}
+
+// infinity is the index of the vertex at infinity of ghost triangles.
+const infinity = -1
+
+// triangle is a counter-clockwise triangle of point indices. Ghost
+// triangles hold infinity as their third vertex and the convex hull
+// edge with the exterior of the triangulation on its left.
+type triangle [3]int
+
+// conflicts returns whether p lies strictly within the circumcircle of
+// the triangle. The circumcircle of a ghost triangle is the open half
+// plane left of its edge together with the open edge itself.
+func (t triangle) conflicts(points []layout.Point, p layout.Point) bool {
+	a, b := points[t[0]], points[t[1]]
+	if t[2] == infinity {
+		o := orient(a, b, p)
+		return o > 0 || (o == 0 && (p.X-a.X)*(p.X-b.X)+(p.Y-a.Y)*(p.Y-b.Y) < 0)
+	}
+	return inCircumcircle(a, b, points[t[2]], p)
+}
+
+// orient returns a positive value if a, b and c are in counter-clockwise
+// order, a negative value if they are in clockwise order and zero if they
+// are collinear.
+func orient(a, b, c layout.Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// inCircumcircle returns whether p lies strictly within the circumcircle
+// of the counter-clockwise triangle a, b, c.
+func inCircumcircle(a, b, c, p layout.Point) bool {
+	ax, ay := a.X-p.X, a.Y-p.Y
+	bx, by := b.X-p.X, b.Y-p.Y
+	cx, cy := c.X-p.X, c.Y-p.Y
+	det := (ax*ax+ay*ay)*(bx*cy-cx*by) -
+		(bx*bx+by*by)*(ax*cy-cx*ay) +
+		(cx*cx+cy*cy)*(ax*by-bx*ay)
+	return det > 0
+}
+
+// byTerminals sorts edges by their terminal indices.
+type byTerminals [][2]int
+
+func (e byTerminals) Len() int      { return len(e) }
+func (e byTerminals) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byTerminals) Less(i, j int) bool {
+	return e[i][0] < e[j][0] || (e[i][0] == e[j][0] && e[i][1] < e[j][1])
+}
+
+// KNearestNeighbors constructs the k-nearest neighbor graph of points in the
+// destination, dst. The nodes are PointNodes with IDs corresponding to the indices
+// of points, and each node is joined to the k other nodes closest to it, with ties
+// broken in favor of lower IDs. If dst is directed, edges are directed from each
+// node to its neighbors, otherwise nodes are adjacent if either is a neighbor of the
+// other. Edges are weighted by the distance between their terminal nodes.
+func KNearestNeighbors(dst GraphBuilder, points []layout.Point, k int) error {
+	if k < 0 || (len(points) != 0 && k >= len(points)) {
+		return fmt.Errorf("gen: bad neighbor count: k=%v", k)
+	}
+	nodes := addPoints(dst, points)
+	if k == 0 {
+		return nil
+	}
+
+	byDist := make([]neighbor, 0, len(points)-1)
+	for i, p := range points {
+		byDist = byDist[:0]
+		for j, q := range points {
+			if j != i {
+				byDist = append(byDist, neighbor{id: j, dist: dist(p, q)})
+			}
+		}
+		sort.Sort(byDistance(byDist))
+		for _, nb := range byDist[:k] {
+			dst.SetEdge(pointEdge{f: nodes[i], t: nodes[nb.id]})
+		}
+	}
+	return nil
+}
+
+// neighbor is a point index and its distance from a query point.
+type neighbor struct {
+	id   int
+	dist float64
+}
+
+// byDistance sorts neighbors by ascending distance and then by index.
+type byDistance []neighbor
+
+func (n byDistance) Len() int      { return len(n) }
+func (n byDistance) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+func (n byDistance) Less(i, j int) bool {
+	return n[i].dist < n[j].dist || (n[i].dist == n[j].dist && n[i].id < n[j].id)
+}
@@ -0,0 +1,238 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/simple"
+)
+
+func TestRandomGeometric(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 100} {
+		for _, radius := range []float64{0, 0.05, 0.2, 0.5, 2} {
+			g := simple.NewUndirectedGraph(0, math.Inf(1))
+			err := RandomGeometric(g, n, radius, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("unexpected error: n=%d radius=%v: %v", n, radius, err)
+			}
+			nodes := g.Nodes()
+			if len(nodes) != n {
+				t.Fatalf("unexpected number of nodes: got:%d want:%d", len(nodes), n)
+			}
+			for _, u := range nodes {
+				for _, v := range nodes {
+					if u.ID() == v.ID() {
+						continue
+					}
+					d := dist(u.(PointNode).Pos(), v.(PointNode).Pos())
+					if g.HasEdgeBetween(u, v) != (d <= radius) {
+						t.Errorf("unexpected adjacency for n=%d radius=%v: %d--%d at distance %v",
+							n, radius, u.ID(), v.ID(), d)
+					}
+				}
+			}
+		}
+	}
+
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := RandomGeometric(g, 10, -1, nil); err == nil {
+		t.Errorf("expected error for negative radius")
+	}
+}
+
+func TestPointNodeAttributes(t *testing.T) {
+	attrs := NewPointNode(0, layout.Point{X: 0.5, Y: -2}).Attributes()
+	if len(attrs) != 1 || attrs[0].Key != "pos" || attrs[0].Value != "0.5,-2" {
+		t.Errorf("unexpected attributes: got:%v want:[{pos 0.5,-2}]", attrs)
+	}
+}
+
+func TestDelaunay(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, n := range []int{3, 10, 50, 200} {
+		points := make([]layout.Point, n)
+		for i := range points {
+			points[i] = layout.Point{X: src.Float64(), Y: src.Float64()}
+		}
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		err := Delaunay(g, points)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// A triangulation of n points with h on the
+		// convex hull has 3n-3-h edges.
+		var edges int
+		for _, u := range g.Nodes() {
+			edges += len(g.From(u))
+		}
+		edges /= 2
+		h := len(convexHull(points))
+		if edges != 3*n-3-h {
+			t.Errorf("unexpected number of edges for n=%d: got:%d want:%d", n, edges, 3*n-3-h)
+		}
+
+		// The Delaunay triangulation contains the
+		// nearest neighbor graph.
+		for i, p := range points {
+			nearest := -1
+			for j, q := range points {
+				if j != i && (nearest < 0 || dist(p, q) < dist(p, points[nearest])) {
+					nearest = j
+				}
+			}
+			if !g.HasEdgeBetween(simple.Node(i), simple.Node(nearest)) {
+				t.Errorf("missing nearest neighbor edge for n=%d: %d--%d", n, i, nearest)
+			}
+		}
+	}
+}
+
+func TestDelaunayDegenerate(t *testing.T) {
+	// Cocircular points.
+	square := []layout.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}}
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := Delaunay(g, square); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range square {
+		if !g.HasEdgeBetween(simple.Node(i), simple.Node((i+1)%4)) {
+			t.Errorf("missing square side: %d--%d", i, (i+1)%4)
+		}
+	}
+	if g.HasEdgeBetween(simple.Node(0), simple.Node(2)) == g.HasEdgeBetween(simple.Node(1), simple.Node(3)) {
+		t.Errorf("expected exactly one diagonal of the square")
+	}
+
+	// Cocircular points on a grid.
+	var grid []layout.Point
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			grid = append(grid, layout.Point{X: float64(x), Y: float64(y)})
+		}
+	}
+	g = simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := Delaunay(g, grid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gridEdges int
+	for _, u := range g.Nodes() {
+		gridEdges += len(g.From(u))
+	}
+	// A triangulated 10x10 grid has 9*10*2 sides
+	// and 9*9 diagonals.
+	if want := 9*10*2 + 9*9; gridEdges/2 != want {
+		t.Errorf("unexpected number of edges for grid: got:%d want:%d", gridEdges/2, want)
+	}
+
+	// Collinear points.
+	line := []layout.Point{{X: 2}, {X: 0}, {X: 3}, {X: 1}}
+	g = simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := Delaunay(g, line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range [][2]int{{1, 3}, {3, 0}, {0, 2}} {
+		if !g.HasEdgeBetween(simple.Node(e[0]), simple.Node(e[1])) {
+			t.Errorf("missing path edge: %d--%d", e[0], e[1])
+		}
+	}
+	var edges int
+	for _, u := range g.Nodes() {
+		edges += len(g.From(u))
+	}
+	if edges/2 != 3 {
+		t.Errorf("unexpected number of edges for collinear points: got:%d want:3", edges/2)
+	}
+
+	g = simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := Delaunay(g, []layout.Point{{X: 1}, {X: 1}}); err == nil {
+		t.Errorf("expected error for duplicate points")
+	}
+}
+
+// convexHull returns the points on the convex hull of points,
+// excluding points that lie along the hull's edges.
+func convexHull(points []layout.Point) []layout.Point {
+	p := append([]layout.Point(nil), points...)
+	sort.Sort(byXY(p))
+	cross := func(o, a, b layout.Point) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+	var hull []layout.Point
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, q := range p {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], q) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, q)
+		}
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+			p[i], p[j] = p[j], p[i]
+		}
+	}
+	return hull
+}
+
+type byXY []layout.Point
+
+func (p byXY) Len() int      { return len(p) }
+func (p byXY) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byXY) Less(i, j int) bool {
+	return p[i].X < p[j].X || (p[i].X == p[j].X && p[i].Y < p[j].Y)
+}
+
+func TestKNearestNeighbors(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	points := make([]layout.Point, 30)
+	for i := range points {
+		points[i] = layout.Point{X: src.Float64(), Y: src.Float64()}
+	}
+	for k := 0; k < len(points); k++ {
+		d := simple.NewDirectedGraph(0, math.Inf(1))
+		err := KNearestNeighbors(d, points, k)
+		if err != nil {
+			t.Fatalf("unexpected error: k=%d: %v", k, err)
+		}
+		u := simple.NewUndirectedGraph(0, math.Inf(1))
+		err = KNearestNeighbors(u, points, k)
+		if err != nil {
+			t.Fatalf("unexpected error: k=%d: %v", k, err)
+		}
+		for i, p := range points {
+			to := d.From(simple.Node(i))
+			if len(to) != k {
+				t.Errorf("unexpected out degree for k=%d: got:%d want:%d", k, len(to), k)
+			}
+			var farthest float64
+			for _, v := range to {
+				farthest = math.Max(farthest, dist(p, points[v.ID()]))
+				if !u.HasEdgeBetween(simple.Node(i), v) {
+					t.Errorf("missing undirected edge for k=%d: %d--%d", k, i, v.ID())
+				}
+			}
+			var closer int
+			for j, q := range points {
+				if j != i && dist(p, q) < farthest {
+					closer++
+				}
+			}
+			if k != 0 && closer >= k {
+				t.Errorf("neighbors of %d are not nearest for k=%d", i, k)
+			}
+		}
+	}
+
+	d := simple.NewDirectedGraph(0, math.Inf(1))
+	if err := KNearestNeighbors(d, points, len(points)); err == nil {
+		t.Errorf("expected error for k equal to number of points")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// UniformSpanningTree constructs a spanning tree of g in the destination, dst,
+// chosen uniformly at random from all the spanning trees of g. If g is not
+// connected, a spanning tree of each connected component is constructed.
+// The nodes of g that are not in dst are added to it, and the tree edges are
+// added using the edges returned by g. If src is not nil it is used as the
+// random source, otherwise rand.Float64 is used.
+//
+// The tree is constructed by Wilson's algorithm using loop-erased random walks
+// as described in http://dl.acm.org/citation.cfm?id=237880.
+func UniformSpanningTree(dst GraphBuilder, g graph.Undirected, src *rand.Rand) {
+	wilson(dst, g, src, func(u, v graph.Node) float64 { return 1 })
+}
+
+// WeightedSpanningTree constructs a random spanning tree of g in the destination,
+// dst, in the same way as UniformSpanningTree, except that the probability of a
+// tree being chosen is proportional to the product of the weights of its edges.
+// Edge weights are obtained from g if it is a graph.Weighter, otherwise from the
+// edges returned by g. WeightedSpanningTree returns an error if an edge weight is
+// not positive and finite, in which case dst is not altered.
+func WeightedSpanningTree(dst GraphBuilder, g graph.Undirected, src *rand.Rand) error {
+	weight := func(u, v graph.Node) float64 { return g.Edge(u, v).Weight() }
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = func(u, v graph.Node) float64 {
+			w, _ := wg.Weight(u, v)
+			return w
+		}
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			w := weight(u, v)
+			if !(w > 0) || math.IsInf(w, 1) {
+				return fmt.Errorf("gen: bad edge weight between %d and %d: w=%v", u.ID(), v.ID(), w)
+			}
+		}
+	}
+	wilson(dst, g, src, weight)
+	return nil
+}
+
+// wilson constructs a random spanning forest of g in dst where each step of
+// the random walks follows an edge with probability proportional to weight.
+func wilson(dst GraphBuilder, g graph.Undirected, src *rand.Rand, weight func(u, v graph.Node) float64) {
+	var r func() float64
+	if src == nil {
+		r = rand.Float64
+	} else {
+		r = src.Float64
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		if !dst.Has(n) {
+			dst.AddNode(n)
+		}
+	}
+
+	// steps holds the sorted neighbours of each node
+	// visited by a walk with the cumulative weights of
+	// the edges to them.
+	type steps struct {
+		to  []graph.Node
+		cum []float64
+	}
+	stepsFrom := make(map[int]steps)
+	step := func(u graph.Node) graph.Node {
+		s, ok := stepsFrom[u.ID()]
+		if !ok {
+			s.to = g.From(u)
+			sort.Sort(ordered.ByID(s.to))
+			s.cum = make([]float64, len(s.to))
+			var sum float64
+			for i, v := range s.to {
+				sum += weight(u, v)
+				s.cum[i] = sum
+			}
+			stepsFrom[u.ID()] = s
+		}
+		i := sort.SearchFloat64s(s.cum, r()*s.cum[len(s.cum)-1])
+		if i == len(s.to) {
+			// Guard against rounding.
+			i--
+		}
+		return s.to[i]
+	}
+
+	inTree := make(map[int]bool, len(nodes))
+	next := make(map[int]graph.Node)
+	for _, root := range nodes {
+		if inTree[root.ID()] {
+			continue
+		}
+
+		// Each unvisited node is the root of a new
+		// component. Walks from the other nodes of the
+		// component stop when they reach the tree, so
+		// the component is found first.
+		inTree[root.ID()] = true
+		component := []graph.Node{root}
+		seen := map[int]bool{root.ID(): true}
+		for i := 0; i < len(component); i++ {
+			to := g.From(component[i])
+			sort.Sort(ordered.ByID(to))
+			for _, v := range to {
+				if !seen[v.ID()] {
+					seen[v.ID()] = true
+					component = append(component, v)
+				}
+			}
+		}
+		sort.Sort(ordered.ByID(component))
+
+		for _, u := range component {
+			// Walk until reaching the tree, recording only the
+			// last exit from each node, which erases loops.
+			for v := u; !inTree[v.ID()]; v = next[v.ID()] {
+				next[v.ID()] = step(v)
+			}
+			for v := u; !inTree[v.ID()]; v = next[v.ID()] {
+				inTree[v.ID()] = true
+				dst.SetEdge(g.Edge(v, next[v.ID()]))
+			}
+		}
+	}
+}
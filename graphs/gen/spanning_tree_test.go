@@ -0,0 +1,131 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+// treeKey returns a canonical description of the edges of g.
+func treeKey(g graph.Undirected) string {
+	var edges []string
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if u.ID() < v.ID() {
+				edges = append(edges, fmt.Sprintf("%d-%d", u.ID(), v.ID()))
+			}
+		}
+	}
+	return fmt.Sprint(edges)
+}
+
+func TestUniformSpanningTree(t *testing.T) {
+	// The complete graph on four nodes has 16 spanning trees.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: 1})
+		}
+	}
+
+	const n = 16000
+	src := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		dst := simple.NewUndirectedGraph(0, math.Inf(1))
+		UniformSpanningTree(dst, g, src)
+		if len(dst.Nodes()) != 4 {
+			t.Fatalf("unexpected number of nodes: got:%d want:4", len(dst.Nodes()))
+		}
+		if len(topo.ConnectedComponents(dst)) != 1 {
+			t.Fatalf("tree is not connected: %s", treeKey(dst))
+		}
+		counts[treeKey(dst)]++
+	}
+	if len(counts) != 16 {
+		t.Errorf("unexpected number of distinct trees: got:%d want:16", len(counts))
+	}
+	for tree, c := range counts {
+		if math.Abs(float64(c)-n/16) > 0.1*n/16 {
+			t.Errorf("tree %s sampled with unexpected frequency: got:%d want:~%d", tree, c, n/16)
+		}
+	}
+}
+
+func TestUniformSpanningTreeForest(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(3), T: simple.Node(4), W: 1})
+	g.AddNode(simple.Node(5))
+
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	UniformSpanningTree(dst, g, rand.New(rand.NewSource(1)))
+	if got := len(topo.ConnectedComponents(dst)); got != 3 {
+		t.Errorf("unexpected number of trees: got:%d want:3", got)
+	}
+	var edges int
+	for _, u := range dst.Nodes() {
+		edges += len(dst.From(u))
+	}
+	if edges/2 != 3 {
+		t.Errorf("unexpected number of edges: got:%d want:3", edges/2)
+	}
+}
+
+func TestWeightedSpanningTree(t *testing.T) {
+	// The spanning trees of a triangle are its pairs of edges,
+	// so with edge weights 1, 2 and 3 the trees have relative
+	// probabilities 2, 3 and 6.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 2})
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2), W: 3})
+	want := map[string]float64{
+		"[0-1 1-2]": 2.0 / 11,
+		"[0-2 1-2]": 6.0 / 11,
+		"[0-1 0-2]": 3.0 / 11,
+	}
+
+	const n = 11000
+	src := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		dst := simple.NewUndirectedGraph(0, math.Inf(1))
+		err := WeightedSpanningTree(dst, g, src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		counts[treeKey(dst)]++
+	}
+	for tree, p := range want {
+		if got := float64(counts[tree]) / n; math.Abs(got-p) > 0.1*p {
+			t.Errorf("tree %s sampled with unexpected frequency: got:%.3f want:%.3f", tree, got, p)
+		}
+	}
+
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3), W: 0})
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	if err := WeightedSpanningTree(dst, g, src); err == nil {
+		t.Errorf("expected error for zero weight edge")
+	}
+	if len(dst.Nodes()) != 0 {
+		t.Errorf("destination altered on error")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+)
+
+// Hash returns an isomorphism-invariant hash of the graph g based on
+// Weisfeiler-Lehman color refinement. Isomorphic graphs always hash to
+// the same value, so Hash may be used as a map key to deduplicate
+// structurally identical graphs. Distinct hash values guarantee that
+// graphs are not isomorphic, but equal hash values do not guarantee
+// isomorphism; graphs that are not distinguished by 1-dimensional
+// Weisfeiler-Lehman refinement, for example some pairs of regular
+// graphs, will collide. Node IDs and edge weights are not considered.
+//
+// If g is a Directed graph, edge direction is taken into account.
+func Hash(g Graph) uint64 {
+	nodes := g.Nodes()
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	from := make([][]int, len(nodes))
+	to := make([][]int, len(nodes))
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			from[i] = append(from[i], indexOf[v.ID()])
+		}
+	}
+	if d, ok := g.(Directed); ok {
+		for i, u := range nodes {
+			for _, v := range d.To(u) {
+				to[i] = append(to[i], indexOf[v.ID()])
+			}
+		}
+	}
+
+	// Initial labels are the node degrees.
+	labels := make([]uint64, len(nodes))
+	for i := range nodes {
+		labels[i] = uint64(len(from[i]))<<32 | uint64(len(to[i]))
+	}
+
+	h := fnv.New64a()
+	var buf [8]byte
+	write := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+
+	sum := fnv.New64a()
+	writeSum := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		sum.Write(buf[:])
+	}
+	writeSum(uint64(len(nodes)))
+
+	// fold writes the sorted multiset of the current
+	// labels into the graph hash.
+	sorted := make(uint64Slice, len(nodes))
+	fold := func() {
+		copy(sorted, labels)
+		sort.Sort(sorted)
+		for _, l := range sorted {
+			writeSum(l)
+		}
+	}
+	fold()
+
+	next := make([]uint64, len(nodes))
+	var neigh uint64Slice
+	classes := distinct(labels)
+	for iter := 0; iter <= len(nodes); iter++ {
+		for i := range nodes {
+			h.Reset()
+			write(labels[i])
+			neigh = neigh[:0]
+			for _, j := range from[i] {
+				neigh = append(neigh, labels[j])
+			}
+			sort.Sort(neigh)
+			write(uint64(len(neigh)))
+			for _, l := range neigh {
+				write(l)
+			}
+			neigh = neigh[:0]
+			for _, j := range to[i] {
+				neigh = append(neigh, labels[j])
+			}
+			sort.Sort(neigh)
+			write(uint64(len(neigh)))
+			for _, l := range neigh {
+				write(l)
+			}
+			next[i] = h.Sum64()
+		}
+		labels, next = next, labels
+		fold()
+
+		n := distinct(labels)
+		if n == classes {
+			break
+		}
+		classes = n
+	}
+
+	return sum.Sum64()
+}
+
+// distinct returns the number of distinct values in s.
+func distinct(s []uint64) int {
+	seen := make(map[uint64]struct{}, len(s))
+	for _, v := range s {
+		seen[v] = struct{}{}
+	}
+	return len(seen)
+}
+
+// uint64Slice implements sort.Interface for a []uint64.
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,66 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestHash(t *testing.T) {
+	path := func(ids ...int) *simple.UndirectedGraph {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for i := range ids[:len(ids)-1] {
+			g.SetEdge(simple.Edge{F: simple.Node(ids[i]), T: simple.Node(ids[i+1])})
+		}
+		return g
+	}
+	if graph.Hash(path(0, 1, 2, 3)) != graph.Hash(path(7, 3, 9, 1)) {
+		t.Error("unexpected hash mismatch for isomorphic graphs")
+	}
+	star := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 1; i < 4; i++ {
+		star.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(i)})
+	}
+	if graph.Hash(path(0, 1, 2, 3)) == graph.Hash(star) {
+		t.Error("unexpected hash match for non-isomorphic graphs")
+	}
+
+	fwd := simple.NewDirectedGraph(0, math.Inf(1))
+	fwd.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	fwd.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	out := simple.NewDirectedGraph(0, math.Inf(1))
+	out.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	out.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	if graph.Hash(fwd) == graph.Hash(out) {
+		t.Error("unexpected hash match for non-isomorphic directed graphs")
+	}
+
+	// C4∪K4 and a connected graph with the same degree sequence
+	// share a stable degree partition but are distinguished by a
+	// single refinement.
+	undirected := func(edges [][2]int) *simple.UndirectedGraph {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for _, e := range edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+		}
+		return g
+	}
+	c4k4 := undirected([][2]int{
+		{0, 1}, {1, 2}, {2, 3}, {3, 0},
+		{4, 5}, {4, 6}, {4, 7}, {5, 6}, {5, 7}, {6, 7},
+	})
+	linked := undirected([][2]int{
+		{0, 1}, {2, 3},
+		{4, 5}, {5, 6}, {6, 7}, {7, 4},
+		{0, 4}, {1, 5}, {2, 6}, {3, 7},
+	})
+	if graph.Hash(c4k4) == graph.Hash(linked) {
+		t.Error("unexpected hash match for graphs distinguished by refinement")
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package instrument provides metrics instrumentation for graphs and graph
+// algorithms.
+package instrument
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gonum/graph"
+)
+
+// Metric names reported by Graph and Measure.
+const (
+	// Nodes and Edges are gauges holding
+	// the order and size of the graph.
+	Nodes = "graph_nodes"
+	Edges = "graph_edges"
+
+	// NodeAdditions, NodeRemovals,
+	// EdgeSets and EdgeRemovals are
+	// counters of graph mutations.
+	NodeAdditions = "graph_node_additions_total"
+	NodeRemovals  = "graph_node_removals_total"
+	EdgeSets      = "graph_edge_sets_total"
+	EdgeRemovals  = "graph_edge_removals_total"
+
+	// AlgorithmSeconds is a histogram
+	// of algorithm run times. It is
+	// labelled with the algorithm name.
+	AlgorithmSeconds = "graph_algorithm_seconds"
+)
+
+// Metrics receives measurements. Implementations bind the measurements to a
+// metrics system and must be safe for concurrent use if the instrumented
+// values are used concurrently.
+type Metrics interface {
+	// SetGauge sets the value of the named gauge.
+	SetGauge(name string, v float64)
+
+	// AddCounter adds delta to the named counter.
+	AddCounter(name string, delta float64)
+
+	// Observe adds an observation to the named
+	// histogram with the given label.
+	Observe(name, label string, v float64)
+}
+
+// Mutable is a graph that can have nodes and edges added and removed.
+type Mutable interface {
+	graph.Graph
+	graph.Builder
+	graph.NodeRemover
+	graph.EdgeRemover
+}
+
+// Graph is a graph wrapper that reports the order and size of the graph and
+// the mutations made through it. Mutations must not be made to the wrapped
+// graph except through the Graph.
+type Graph struct {
+	Mutable
+
+	m            Metrics
+	nodes, edges int
+	directed     bool
+}
+
+// New returns a Graph wrapping g that reports to m. The current order and size
+// of g are reported immediately.
+func New(g Mutable, m Metrics) *Graph {
+	_, directed := g.(graph.Directed)
+	ig := &Graph{Mutable: g, m: m, directed: directed}
+	for _, u := range g.Nodes() {
+		ig.nodes++
+		for _, v := range g.From(u) {
+			if directed || u.ID() <= v.ID() {
+				ig.edges++
+			}
+		}
+	}
+	ig.report()
+	return ig
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an
+// existing node ID.
+func (g *Graph) AddNode(n graph.Node) {
+	g.Mutable.AddNode(n)
+	g.nodes++
+	g.m.AddCounter(NodeAdditions, 1)
+	g.report()
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it. If
+// the node is not in the graph it is a no-op.
+func (g *Graph) RemoveNode(n graph.Node) {
+	if !g.Has(n) {
+		return
+	}
+	removed := len(g.From(n))
+	if g.directed {
+		removed += len(g.Mutable.(graph.Directed).To(n))
+	}
+	g.Mutable.RemoveNode(n)
+	g.nodes--
+	g.edges -= removed
+	g.m.AddCounter(NodeRemovals, 1)
+	g.m.AddCounter(EdgeRemovals, float64(removed))
+	g.report()
+}
+
+// SetEdge adds e to the graph. Terminal nodes that are not in the graph are
+// added and counted as node additions.
+func (g *Graph) SetEdge(e graph.Edge) {
+	var added int
+	for _, n := range []graph.Node{e.From(), e.To()} {
+		if !g.Has(n) {
+			added++
+		}
+	}
+	exists := added == 0 && g.Edge(e.From(), e.To()) != nil
+	g.Mutable.SetEdge(e)
+	if added != 0 {
+		g.nodes += added
+		g.m.AddCounter(NodeAdditions, float64(added))
+	}
+	if !exists {
+		g.edges++
+	}
+	g.m.AddCounter(EdgeSets, 1)
+	g.report()
+}
+
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If the edge
+// does not exist it is a no-op.
+func (g *Graph) RemoveEdge(e graph.Edge) {
+	if !g.Has(e.From()) || !g.Has(e.To()) || g.Edge(e.From(), e.To()) == nil {
+		return
+	}
+	g.Mutable.RemoveEdge(e)
+	g.edges--
+	g.m.AddCounter(EdgeRemovals, 1)
+	g.report()
+}
+
+func (g *Graph) report() {
+	g.m.SetGauge(Nodes, float64(g.nodes))
+	g.m.SetGauge(Edges, float64(g.edges))
+}
+
+// Measure starts timing the named algorithm and returns a function that
+// reports the elapsed time in seconds to the AlgorithmSeconds histogram of m
+// when called. It is intended to be deferred:
+//  defer instrument.Measure(m, "dijkstra")()
+func Measure(m Metrics, name string) (stop func()) {
+	start := time.Now()
+	return func() {
+		m.Observe(AlgorithmSeconds, name, time.Since(start).Seconds())
+	}
+}
+
+// Recorder is a Metrics that holds the most recent measurements in memory.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu           sync.Mutex
+	gauges       map[string]float64
+	counters     map[string]float64
+	observations map[[2]string][]float64
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		gauges:       make(map[string]float64),
+		counters:     make(map[string]float64),
+		observations: make(map[[2]string][]float64),
+	}
+}
+
+// SetGauge sets the value of the named gauge.
+func (r *Recorder) SetGauge(name string, v float64) {
+	r.mu.Lock()
+	r.gauges[name] = v
+	r.mu.Unlock()
+}
+
+// AddCounter adds delta to the named counter.
+func (r *Recorder) AddCounter(name string, delta float64) {
+	r.mu.Lock()
+	r.counters[name] += delta
+	r.mu.Unlock()
+}
+
+// Observe adds an observation to the named histogram with the given label.
+func (r *Recorder) Observe(name, label string, v float64) {
+	r.mu.Lock()
+	k := [2]string{name, label}
+	r.observations[k] = append(r.observations[k], v)
+	r.mu.Unlock()
+}
+
+// Gauge returns the value of the named gauge.
+func (r *Recorder) Gauge(name string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gauges[name]
+}
+
+// Counter returns the value of the named counter.
+func (r *Recorder) Counter(name string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counters[name]
+}
+
+// Observations returns the observations of the named histogram with the
+// given label.
+func (r *Recorder) Observations(name, label string) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]float64(nil), r.observations[[2]string{name, label}]...)
+}
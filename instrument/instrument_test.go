@@ -0,0 +1,96 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+func TestGraph(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		g    Mutable
+	}{
+		{name: "directed", g: simple.NewDirectedGraph(0, math.Inf(1))},
+		{name: "undirected", g: simple.NewUndirectedGraph(0, math.Inf(1))},
+	} {
+		test.g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+		r := NewRecorder()
+		g := New(test.g, r)
+		check := func(step string, nodes, edges int) {
+			if got := r.Gauge(Nodes); got != float64(nodes) {
+				t.Errorf("unexpected node count for %s after %s: got:%v want:%d", test.name, step, got, nodes)
+			}
+			if got := r.Gauge(Edges); got != float64(edges) {
+				t.Errorf("unexpected edge count for %s after %s: got:%v want:%d", test.name, step, got, edges)
+			}
+		}
+		check("wrap", 2, 1)
+
+		g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+		check("adding edge", 3, 2)
+		g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 3})
+		check("replacing edge", 3, 2)
+		g.AddNode(simple.Node(3))
+		check("adding node", 4, 2)
+		g.RemoveEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3)})
+		check("removing absent edge", 4, 2)
+		g.RemoveNode(simple.Node(1))
+		check("removing node", 3, 0)
+		g.RemoveNode(simple.Node(1))
+		check("removing absent node", 3, 0)
+
+		for _, c := range []struct {
+			name string
+			want float64
+		}{
+			{name: NodeAdditions, want: 2},
+			{name: NodeRemovals, want: 1},
+			{name: EdgeSets, want: 2},
+			{name: EdgeRemovals, want: 2},
+		} {
+			if got := r.Counter(c.name); got != c.want {
+				t.Errorf("unexpected %s for %s: got:%v want:%v", c.name, test.name, got, c.want)
+			}
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	r := NewRecorder()
+	func() {
+		defer Measure(r, "test")()
+	}()
+	obs := r.Observations(AlgorithmSeconds, "test")
+	if len(obs) != 1 || obs[0] < 0 {
+		t.Errorf("unexpected observations: %v", obs)
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := Stats{Expanded: 1, Relaxed: 2, HeapOps: 3, Iterations: 4}
+	s.Add(Stats{Expanded: 10, Relaxed: 20, HeapOps: 30, Iterations: 40})
+	want := Stats{Expanded: 11, Relaxed: 22, HeapOps: 33, Iterations: 44}
+	if s != want {
+		t.Errorf("unexpected sum: got:%v want:%v", s, want)
+	}
+
+	r := NewRecorder()
+	s.Report(r, "test")
+	s.Report(r, "test")
+	for name, want := range map[string]float64{
+		"test_expanded_total":   22,
+		"test_relaxed_total":    44,
+		"test_heap_ops_total":   66,
+		"test_iterations_total": 88,
+	} {
+		if got := r.Counter(name); got != want {
+			t.Errorf("unexpected value for %s: got:%v want:%v", name, got, want)
+		}
+	}
+}
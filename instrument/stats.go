@@ -0,0 +1,53 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instrument
+
+import "fmt"
+
+// Stats holds counts of the work performed by a graph algorithm. Algorithms
+// that report Stats document which fields they fill; the others are left
+// unaltered.
+type Stats struct {
+	// Expanded is the number of nodes
+	// whose neighbors were examined.
+	Expanded int
+
+	// Relaxed is the number of edges
+	// examined for relaxation.
+	Relaxed int
+
+	// HeapOps is the number of push,
+	// pop and update operations on a
+	// priority queue.
+	HeapOps int
+
+	// Iterations is the number of
+	// iterations performed by an
+	// iterative algorithm.
+	Iterations int
+}
+
+// Add adds the counts in t to s.
+func (s *Stats) Add(t Stats) {
+	s.Expanded += t.Expanded
+	s.Relaxed += t.Relaxed
+	s.HeapOps += t.HeapOps
+	s.Iterations += t.Iterations
+}
+
+// Report adds the counts in s to counters of m named by prefix and the
+// field name, for example "dijkstra_expanded_total".
+func (s Stats) Report(m Metrics, prefix string) {
+	m.AddCounter(prefix+"_expanded_total", float64(s.Expanded))
+	m.AddCounter(prefix+"_relaxed_total", float64(s.Relaxed))
+	m.AddCounter(prefix+"_heap_ops_total", float64(s.HeapOps))
+	m.AddCounter(prefix+"_iterations_total", float64(s.Iterations))
+}
+
+// String returns a summary of the counts in s.
+func (s Stats) String() string {
+	return fmt.Sprintf("expanded=%d relaxed=%d heap=%d iterations=%d",
+		s.Expanded, s.Relaxed, s.HeapOps, s.Iterations)
+}
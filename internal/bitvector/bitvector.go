@@ -0,0 +1,184 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bitvector provides an immutable bit vector with constant time rank
+// and logarithmic time select queries.
+package bitvector
+
+import "fmt"
+
+// blockWords is the number of words
+// in each block of the rank directory.
+const blockWords = 8
+
+// Builder constructs a Vector by appending bits.
+type Builder struct {
+	words []uint64
+	n     int
+}
+
+// Append appends a bit to the vector under construction.
+func (b *Builder) Append(bit bool) {
+	if b.n%64 == 0 {
+		b.words = append(b.words, 0)
+	}
+	if bit {
+		b.words[b.n/64] |= 1 << uint(b.n%64)
+	}
+	b.n++
+}
+
+// AppendN appends n copies of a bit to the vector under construction.
+func (b *Builder) AppendN(bit bool, n int) {
+	for i := 0; i < n; i++ {
+		b.Append(bit)
+	}
+}
+
+// Vector returns the constructed Vector. The Builder must not be
+// used after Vector is called.
+func (b *Builder) Vector() *Vector {
+	v := &Vector{
+		words: b.words,
+		n:     b.n,
+		ranks: make([]int, (len(b.words)+blockWords-1)/blockWords+1),
+	}
+	for i, w := range v.words {
+		v.ranks[i/blockWords+1] += popcount(w)
+	}
+	for i := 1; i < len(v.ranks); i++ {
+		v.ranks[i] += v.ranks[i-1]
+	}
+	b.words = nil
+	return v
+}
+
+// Vector is an immutable bit vector.
+type Vector struct {
+	words []uint64
+	n     int
+
+	// ranks holds the number of set
+	// bits before each block of words
+	// and a final total count.
+	ranks []int
+}
+
+// Len returns the number of bits in the vector.
+func (v *Vector) Len() int { return v.n }
+
+// Ones returns the number of set bits in the vector.
+func (v *Vector) Ones() int { return v.ranks[len(v.ranks)-1] }
+
+// Get returns whether bit i is set. Get panics if i is out of range.
+func (v *Vector) Get(i int) bool {
+	if i < 0 || i >= v.n {
+		panic(fmt.Sprintf("bitvector: index out of range: %d", i))
+	}
+	return v.words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Rank1 returns the number of set bits before bit i. Rank1 panics if i is
+// not in [0, v.Len()].
+func (v *Vector) Rank1(i int) int {
+	if i < 0 || i > v.n {
+		panic(fmt.Sprintf("bitvector: index out of range: %d", i))
+	}
+	w := i / 64
+	r := v.ranks[w/blockWords]
+	for _, x := range v.words[w/blockWords*blockWords : w] {
+		r += popcount(x)
+	}
+	if i%64 != 0 {
+		r += popcount(v.words[w] & (1<<uint(i%64) - 1))
+	}
+	return r
+}
+
+// Rank0 returns the number of unset bits before bit i. Rank0 panics if i is
+// not in [0, v.Len()].
+func (v *Vector) Rank0(i int) int {
+	return i - v.Rank1(i)
+}
+
+// Select1 returns the position of the set bit with rank k, so that
+// Rank1(Select1(k)) == k and bit Select1(k) is set. Select1 panics if k
+// is not in [0, v.Ones()).
+func (v *Vector) Select1(k int) int {
+	if k < 0 || k >= v.Ones() {
+		panic(fmt.Sprintf("bitvector: rank out of range: %d", k))
+	}
+	return v.select1(k)
+}
+
+func (v *Vector) select1(k int) int {
+	// Find the last block with fewer than k+1 set
+	// bits before it and scan its words.
+	lo, hi := 0, len(v.ranks)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if v.ranks[mid] <= k {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	k -= v.ranks[lo]
+	for w := lo * blockWords; ; w++ {
+		x := v.words[w]
+		c := popcount(x)
+		if k < c {
+			return w*64 + selectWord(x, k)
+		}
+		k -= c
+	}
+}
+
+// Select0 returns the position of the unset bit with rank k, so that
+// Rank0(Select0(k)) == k and bit Select0(k) is unset. Select0 panics if k
+// is not in [0, v.Len()-v.Ones()).
+func (v *Vector) Select0(k int) int {
+	if k < 0 || k >= v.n-v.Ones() {
+		panic(fmt.Sprintf("bitvector: rank out of range: %d", k))
+	}
+	lo, hi := 0, len(v.ranks)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if mid*blockWords*64-v.ranks[mid] <= k {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	k -= lo*blockWords*64 - v.ranks[lo]
+	for w := lo * blockWords; ; w++ {
+		x := ^v.words[w]
+		c := popcount(x)
+		if k < c {
+			return w*64 + selectWord(x, k)
+		}
+		k -= c
+	}
+}
+
+// popcount returns the number of set bits in x.
+func popcount(x uint64) int {
+	x -= (x >> 1) & 0x5555555555555555
+	x = (x & 0x3333333333333333) + ((x >> 2) & 0x3333333333333333)
+	x = (x + (x >> 4)) & 0x0f0f0f0f0f0f0f0f
+	return int((x * 0x0101010101010101) >> 56)
+}
+
+// selectWord returns the position of the set bit of x with rank k.
+func selectWord(x uint64, k int) int {
+	var pos uint
+	for s := uint(32); s != 0; s /= 2 {
+		if c := popcount(x & (1<<s - 1)); k >= c {
+			k -= c
+			x >>= s
+			pos += s
+		}
+	}
+	return int(pos)
+}
@@ -0,0 +1,80 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bitvector
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestVector(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 63, 64, 65, 511, 512, 513, 5000} {
+		for _, p := range []float64{0, 0.01, 0.5, 0.99, 1} {
+			bits := make([]bool, n)
+			var b Builder
+			for i := range bits {
+				bits[i] = rnd.Float64() < p
+				b.Append(bits[i])
+			}
+			v := b.Vector()
+			if v.Len() != n {
+				t.Fatalf("unexpected length: got:%d want:%d", v.Len(), n)
+			}
+
+			var ones, zeros []int
+			for i, bit := range bits {
+				if v.Get(i) != bit {
+					t.Errorf("unexpected bit %d for n=%d p=%v", i, n, p)
+				}
+				if got := v.Rank1(i); got != len(ones) {
+					t.Errorf("unexpected rank1 at %d for n=%d p=%v: got:%d want:%d", i, n, p, got, len(ones))
+				}
+				if got := v.Rank0(i); got != len(zeros) {
+					t.Errorf("unexpected rank0 at %d for n=%d p=%v: got:%d want:%d", i, n, p, got, len(zeros))
+				}
+				if bit {
+					ones = append(ones, i)
+				} else {
+					zeros = append(zeros, i)
+				}
+			}
+			if got := v.Rank1(n); got != len(ones) {
+				t.Errorf("unexpected final rank1 for n=%d p=%v: got:%d want:%d", n, p, got, len(ones))
+			}
+			if v.Ones() != len(ones) {
+				t.Errorf("unexpected number of ones for n=%d p=%v: got:%d want:%d", n, p, v.Ones(), len(ones))
+			}
+			for k, want := range ones {
+				if got := v.Select1(k); got != want {
+					t.Errorf("unexpected select1 of %d for n=%d p=%v: got:%d want:%d", k, n, p, got, want)
+				}
+			}
+			for k, want := range zeros {
+				if got := v.Select0(k); got != want {
+					t.Errorf("unexpected select0 of %d for n=%d p=%v: got:%d want:%d", k, n, p, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestPopcount(t *testing.T) {
+	for _, test := range []struct {
+		x    uint64
+		want int
+	}{
+		{x: 0, want: 0},
+		{x: 1, want: 1},
+		{x: 1 << 63, want: 1},
+		{x: 0xff, want: 8},
+		{x: ^uint64(0), want: 64},
+		{x: 0x5555555555555555, want: 32},
+	} {
+		if got := popcount(test.x); got != test.want {
+			t.Errorf("unexpected popcount for %#x: got:%d want:%d", test.x, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kernel provides vectorized kernels for dense graph algorithms.
+//
+// On amd64 the kernels are implemented in assembly. Building with the noasm
+// or appengine tags selects the pure Go implementations.
+package kernel
+
+// MinPlus sets each element dst[i] to the minimum of dst[i] and a+s[i].
+// Elements for which a+s[i] is NaN are left unaltered. MinPlus will panic
+// if s is shorter than dst.
+func MinPlus(dst, s []float64, a float64) {
+	if len(s) < len(dst) {
+		panic("kernel: slice length mismatch")
+	}
+	minPlus(dst, s, a)
+}
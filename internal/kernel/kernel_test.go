@@ -0,0 +1,87 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kernel
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func minPlusNaive(dst, s []float64, a float64) {
+	for i, d := range dst {
+		if v := a + s[i]; v < d {
+			dst[i] = v
+		}
+	}
+}
+
+func TestMinPlus(t *testing.T) {
+	inf := math.Inf(1)
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 20; n++ {
+		for _, a := range []float64{0, 1.5, -2, inf, math.Inf(-1), math.NaN()} {
+			dst := make([]float64, n)
+			s := make([]float64, n+1)
+			for i := range dst {
+				dst[i] = rnd.NormFloat64()
+				s[i] = rnd.NormFloat64()
+				switch rnd.Intn(5) {
+				case 0:
+					dst[i] = inf
+				case 1:
+					s[i] = inf
+				case 2:
+					s[i] = math.NaN()
+				}
+			}
+			want := append([]float64(nil), dst...)
+			minPlusNaive(want, s, a)
+			MinPlus(dst, s, a)
+			for i := range dst {
+				if dst[i] != want[i] && !(math.IsNaN(dst[i]) && math.IsNaN(want[i])) {
+					t.Errorf("unexpected result for n=%d a=%v at %d: got:%v want:%v", n, a, i, dst[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestMinPlusPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for short slice")
+		}
+	}()
+	MinPlus(make([]float64, 3), make([]float64, 2), 0)
+}
+
+func benchmarkMinPlus(b *testing.B, n int) {
+	dst := make([]float64, n)
+	s := make([]float64, n)
+	for i := range s {
+		dst[i] = float64(n - i)
+		s[i] = float64(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MinPlus(dst, s, 1)
+	}
+}
+
+func BenchmarkMinPlus_1000(b *testing.B) { benchmarkMinPlus(b, 1000) }
+
+func BenchmarkMinPlusNaive_1000(b *testing.B) {
+	dst := make([]float64, 1000)
+	s := make([]float64, 1000)
+	for i := range s {
+		dst[i] = float64(1000 - i)
+		s[i] = float64(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		minPlusNaive(dst, s, 1)
+	}
+}
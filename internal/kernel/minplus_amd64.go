@@ -0,0 +1,9 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//+build !noasm,!appengine
+
+package kernel
+
+func minPlus(dst, s []float64, a float64)
@@ -0,0 +1,45 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//+build !noasm,!appengine
+
+#include "textflag.h"
+
+// func minPlus(dst, s []float64, a float64)
+TEXT ·minPlus(SB), NOSPLIT, $0-56
+	MOVQ  dst_base+0(FP), DI
+	MOVQ  dst_len+8(FP), CX
+	MOVQ  s_base+24(FP), SI
+	MOVSD a+48(FP), X0
+	SHUFPD $0, X0, X0
+	XORQ  AX, AX
+	MOVQ  CX, BX
+	ANDQ  $-2, BX
+
+pairs:
+	CMPQ   AX, BX
+	JGE    tail
+	MOVUPD (SI)(AX*8), X1
+	ADDPD  X0, X1
+	MOVUPD (DI)(AX*8), X2
+
+	// MINPD returns its source operand, the
+	// current value, if either value is NaN
+	// or the values are equal.
+	MINPD  X2, X1
+	MOVUPD X1, (DI)(AX*8)
+	ADDQ   $2, AX
+	JMP    pairs
+
+tail:
+	CMPQ  AX, CX
+	JGE   done
+	MOVSD (SI)(AX*8), X1
+	ADDSD X0, X1
+	MOVSD (DI)(AX*8), X2
+	MINSD X2, X1
+	MOVSD X1, (DI)(AX*8)
+
+done:
+	RET
@@ -0,0 +1,311 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package iso provides graph isomorphism functions.
+package iso
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// CanonicalLabel returns a canonical labeling of the nodes of g. The
+// returned map holds the canonical position, in [0, |V|), of each node
+// keyed by node ID. Two graphs are isomorphic if and only if relabeling
+// each graph's nodes by its canonical labeling gives identical graphs.
+// Node IDs and edge weights are not considered, and if g is a Directed
+// graph edge direction is taken into account.
+//
+// CanonicalLabel uses Weisfeiler-Lehman color refinement with
+// individualization of nodes in non-discrete cells and pruning of the
+// search tree by discovered automorphisms. The search is fast for most
+// graphs, but may take exponential time for some highly regular graphs.
+func CanonicalLabel(g graph.Graph) map[int]int {
+	c := newCanonizer(g)
+	c.search(c.initial(), nil)
+	label := make(map[int]int, len(c.nodes))
+	for pos, i := range c.best {
+		label[c.nodes[i].ID()] = pos
+	}
+	return label
+}
+
+// canonizer holds the state of a canonical labeling search.
+type canonizer struct {
+	nodes []graph.Node
+	adj   [][]bool
+
+	from, to [][]int
+
+	// best and bestCert hold the node ordering
+	// and certificate of the best leaf found.
+	best     []int
+	bestCert []bool
+
+	// automorphisms holds the automorphisms
+	// of the graph found during the search.
+	automorphisms [][]int
+}
+
+func newCanonizer(g graph.Graph) *canonizer {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	c := &canonizer{
+		nodes: nodes,
+		adj:   make([][]bool, len(nodes)),
+		from:  make([][]int, len(nodes)),
+		to:    make([][]int, len(nodes)),
+	}
+	for i := range nodes {
+		c.adj[i] = make([]bool, len(nodes))
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			j := indexOf[v.ID()]
+			c.adj[i][j] = true
+			c.from[i] = append(c.from[i], j)
+			c.to[j] = append(c.to[j], i)
+		}
+	}
+	return c
+}
+
+// initial returns the refined unit partition of the graph.
+func (c *canonizer) initial() []int {
+	return c.refine(make([]int, len(c.nodes)))
+}
+
+// signature is the refinement signature of a node.
+type signature struct {
+	node int
+	key  []int
+}
+
+type bySignature []signature
+
+func (s bySignature) Len() int           { return len(s) }
+func (s bySignature) Less(i, j int) bool { return lessInts(s[i].key, s[j].key) }
+func (s bySignature) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func lessInts(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if v != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// refine performs color refinement on the given coloring until the number
+// of colors is stable, returning the refined coloring. Colors are the rank
+// of each node's signature, so the order of existing colors is retained.
+func (c *canonizer) refine(color []int) []int {
+	n := countColors(color)
+	sigs := make([]signature, len(color))
+	for {
+		for i := range color {
+			key := []int{color[i]}
+			key = append(key, sortedColors(color, c.from[i])...)
+			key = append(key, -1)
+			key = append(key, sortedColors(color, c.to[i])...)
+			sigs[i] = signature{node: i, key: key}
+		}
+		sort.Sort(bySignature(sigs))
+		next := make([]int, len(color))
+		rank := 0
+		for k, s := range sigs {
+			if k != 0 && !equalInts(s.key, sigs[k-1].key) {
+				rank++
+			}
+			next[s.node] = rank
+		}
+		color = next
+		m := countColors(color)
+		if m == n {
+			return color
+		}
+		n = m
+	}
+}
+
+func sortedColors(color, nodes []int) []int {
+	c := make([]int, len(nodes))
+	for k, i := range nodes {
+		c[k] = color[i]
+	}
+	sort.Ints(c)
+	return c
+}
+
+func countColors(color []int) int {
+	seen := make(map[int]struct{}, len(color))
+	for _, c := range color {
+		seen[c] = struct{}{}
+	}
+	return len(seen)
+}
+
+// targetCell returns the members of the first non-singleton color cell
+// of the given coloring, or nil if the coloring is discrete.
+func targetCell(color []int) []int {
+	count := make(map[int]int)
+	for _, c := range color {
+		count[c]++
+	}
+	target := -1
+	for c, n := range count {
+		if n > 1 && (target < 0 || c < target) {
+			target = c
+		}
+	}
+	if target < 0 {
+		return nil
+	}
+	var cell []int
+	for i, c := range color {
+		if c == target {
+			cell = append(cell, i)
+		}
+	}
+	return cell
+}
+
+// individualize returns a coloring with v distinguished from the other
+// members of its color cell.
+func individualize(color []int, v int) []int {
+	next := make([]int, len(color))
+	for i, c := range color {
+		next[i] = 2 * c
+		if c == color[v] && i != v {
+			next[i]++
+		}
+	}
+	return next
+}
+
+// search explores the individualization-refinement tree rooted at the
+// given coloring. The prefix holds the nodes individualized to reach
+// this point in the tree.
+func (c *canonizer) search(color []int, prefix []int) {
+	cell := targetCell(color)
+	if cell == nil {
+		c.leaf(color)
+		return
+	}
+
+	var tried []int
+	for _, v := range cell {
+		if c.sameOrbit(v, tried, prefix) {
+			continue
+		}
+		tried = append(tried, v)
+		c.search(c.refine(individualize(color, v)), append(prefix[:len(prefix):len(prefix)], v))
+	}
+}
+
+// leaf compares the discrete coloring with the best leaf found so far.
+func (c *canonizer) leaf(color []int) {
+	order := make([]int, len(color))
+	for i, pos := range color {
+		order[pos] = i
+	}
+	cert := make([]bool, 0, len(order)*len(order))
+	for _, i := range order {
+		for _, j := range order {
+			cert = append(cert, c.adj[i][j])
+		}
+	}
+
+	if c.best == nil {
+		c.best, c.bestCert = order, cert
+		return
+	}
+	switch compareCert(cert, c.bestCert) {
+	case -1:
+		c.best, c.bestCert = order, cert
+	case 0:
+		// The mapping between the two orderings is an
+		// automorphism of the graph.
+		perm := make([]int, len(order))
+		for pos, i := range c.best {
+			perm[i] = order[pos]
+		}
+		c.automorphisms = append(c.automorphisms, perm)
+	}
+}
+
+func compareCert(a, b []bool) int {
+	for k, v := range a {
+		if v != b[k] {
+			if !v {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// sameOrbit returns whether v is in the same orbit as any of the tried
+// nodes under the group generated by the known automorphisms that fix
+// every node in prefix.
+func (c *canonizer) sameOrbit(v int, tried, prefix []int) bool {
+	if len(tried) == 0 || len(c.automorphisms) == 0 {
+		return false
+	}
+
+	parent := make([]int, len(c.nodes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, perm := range c.automorphisms {
+		fixes := true
+		for _, p := range prefix {
+			if perm[p] != p {
+				fixes = false
+				break
+			}
+		}
+		if !fixes {
+			continue
+		}
+		for i, j := range perm {
+			parent[find(i)] = find(j)
+		}
+	}
+
+	root := find(v)
+	for _, u := range tried {
+		if find(u) == root {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iso
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+// canonicalEdges returns the edges of g relabeled by the canonical
+// labeling of g as a set of node pairs.
+func canonicalEdges(g graph.Graph) map[[2]int]bool {
+	label := CanonicalLabel(g)
+	edges := make(map[[2]int]bool)
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			edges[[2]int{label[u.ID()], label[v.ID()]}] = true
+		}
+	}
+	return edges
+}
+
+// permuted returns a copy of the edges of g with node IDs permuted.
+func permuted(dst graph.Builder, g graph.Graph, rnd *rand.Rand) {
+	nodes := g.Nodes()
+	perm := rnd.Perm(len(nodes))
+	idOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		idOf[n.ID()] = perm[i] + 100
+		dst.AddNode(simple.Node(perm[i] + 100))
+	}
+	for _, u := range nodes {
+		for _, v := range g.From(u) {
+			dst.SetEdge(simple.Edge{F: simple.Node(idOf[u.ID()]), T: simple.Node(idOf[v.ID()])})
+		}
+	}
+}
+
+func TestCanonicalLabelUndirected(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 1; n <= 20; n++ {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		err := gen.Gnp(g, n, 0.3, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error generating graph: %v", err)
+		}
+		for i := 0; i < 5; i++ {
+			h := simple.NewUndirectedGraph(0, math.Inf(1))
+			permuted(h, g, rnd)
+			if !reflect.DeepEqual(canonicalEdges(g), canonicalEdges(h)) {
+				t.Errorf("canonical forms differ for isomorphic graphs of order %d", n)
+			}
+		}
+	}
+}
+
+func TestCanonicalLabelDirected(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 1; n <= 20; n++ {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		err := gen.Gnp(g, n, 0.2, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error generating graph: %v", err)
+		}
+		for i := 0; i < 5; i++ {
+			h := simple.NewDirectedGraph(0, math.Inf(1))
+			permuted(h, g, rnd)
+			if !reflect.DeepEqual(canonicalEdges(g), canonicalEdges(h)) {
+				t.Errorf("canonical forms differ for isomorphic graphs of order %d", n)
+			}
+		}
+	}
+}
+
+func TestCanonicalLabelRegular(t *testing.T) {
+	// A 6-cycle and two disjoint triangles are both 2-regular
+	// and are not distinguished by color refinement alone.
+	cycle := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 6; i++ {
+		cycle.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 6)})
+	}
+	triangles := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 3; i++ {
+		triangles.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 3)})
+		triangles.SetEdge(simple.Edge{F: simple.Node(i + 3), T: simple.Node((i+1)%3 + 3)})
+	}
+	if reflect.DeepEqual(canonicalEdges(cycle), canonicalEdges(triangles)) {
+		t.Error("unexpected equal canonical forms for non-isomorphic graphs")
+	}
+
+	complete := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 12; i++ {
+		for j := i + 1; j < 12; j++ {
+			complete.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+		}
+	}
+	label := CanonicalLabel(complete)
+	seen := make(map[int]bool)
+	for _, l := range label {
+		seen[l] = true
+	}
+	if len(seen) != 12 {
+		t.Errorf("unexpected number of canonical labels: got:%d want:12", len(seen))
+	}
+}
@@ -0,0 +1,419 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iso
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// EditCosts holds the cost functions for graph edit operations. All costs
+// must be non-negative. A nil substitution function is treated as a zero
+// cost and a nil insertion or deletion function is treated as a unit cost.
+type EditCosts struct {
+	// NodeSubstitute returns the cost of
+	// substituting node a with node b.
+	NodeSubstitute func(a, b graph.Node) float64
+	// NodeInsert and NodeDelete return
+	// the cost of inserting or deleting
+	// the node n.
+	NodeInsert, NodeDelete func(n graph.Node) float64
+
+	// EdgeSubstitute returns the cost of
+	// substituting edge a with edge b.
+	EdgeSubstitute func(a, b graph.Edge) float64
+	// EdgeInsert and EdgeDelete return
+	// the cost of inserting or deleting
+	// the edge e.
+	EdgeInsert, EdgeDelete func(e graph.Edge) float64
+}
+
+func (c EditCosts) nodeSub(a, b graph.Node) float64 {
+	if c.NodeSubstitute == nil {
+		return 0
+	}
+	return c.NodeSubstitute(a, b)
+}
+
+func (c EditCosts) nodeIns(n graph.Node) float64 {
+	if c.NodeInsert == nil {
+		return 1
+	}
+	return c.NodeInsert(n)
+}
+
+func (c EditCosts) nodeDel(n graph.Node) float64 {
+	if c.NodeDelete == nil {
+		return 1
+	}
+	return c.NodeDelete(n)
+}
+
+func (c EditCosts) edgeSub(a, b graph.Edge) float64 {
+	if c.EdgeSubstitute == nil {
+		return 0
+	}
+	return c.EdgeSubstitute(a, b)
+}
+
+func (c EditCosts) edgeIns(e graph.Edge) float64 {
+	if c.EdgeInsert == nil {
+		return 1
+	}
+	return c.EdgeInsert(e)
+}
+
+func (c EditCosts) edgeDel(e graph.Edge) float64 {
+	if c.EdgeDelete == nil {
+		return 1
+	}
+	return c.EdgeDelete(e)
+}
+
+const (
+	// maxExactEditOrder is the largest graph order
+	// for which EditDistance uses the exact solver.
+	maxExactEditOrder = 10
+
+	// defaultBeamWidth is the beam width used by
+	// EditDistance for larger graphs.
+	defaultBeamWidth = 100
+)
+
+// EditDistance returns the graph edit distance between a and b using the
+// provided edit costs. If both graphs have no more than 10 nodes, the
+// distance is computed exactly by A* search and exact is returned true.
+// Otherwise the distance is an upper bound estimated by BeamEditDistance
+// with a beam width of 100 and exact is returned false.
+//
+// If a is a Directed graph, edge direction is taken into account and b
+// must also be Directed.
+func EditDistance(a, b graph.Graph, costs EditCosts) (dist float64, exact bool) {
+	if len(a.Nodes()) <= maxExactEditOrder && len(b.Nodes()) <= maxExactEditOrder {
+		return newEditor(a, b, costs).astar(), true
+	}
+	return BeamEditDistance(a, b, costs, defaultBeamWidth), false
+}
+
+// BeamEditDistance returns an upper bound on the graph edit distance between
+// a and b using the provided edit costs. The search retains the width best
+// partial node mappings at each step, so larger widths give tighter bounds at
+// the expense of time. BeamEditDistance will panic if width is less than one.
+//
+// If a is a Directed graph, edge direction is taken into account and b
+// must also be Directed.
+func BeamEditDistance(a, b graph.Graph, costs EditCosts, width int) float64 {
+	if width < 1 {
+		panic("iso: beam width less than one")
+	}
+	return newEditor(a, b, costs).beam(width)
+}
+
+// editor holds the state of a graph edit distance search.
+type editor struct {
+	costs EditCosts
+
+	directed bool
+
+	a, b   []graph.Node
+	ea, eb [][]graph.Edge
+}
+
+func newEditor(a, b graph.Graph, costs EditCosts) *editor {
+	_, directed := a.(graph.Directed)
+	e := &editor{
+		costs:    costs,
+		directed: directed,
+		a:        a.Nodes(),
+		b:        b.Nodes(),
+	}
+
+	// Process the nodes of a in order of decreasing
+	// degree so that edge costs are accrued early.
+	sort.Sort(ordered.ByID(e.a))
+	sort.Stable(byDegree{nodes: e.a, g: a})
+	sort.Sort(ordered.ByID(e.b))
+
+	e.ea = edgeMatrix(a, e.a)
+	e.eb = edgeMatrix(b, e.b)
+	return e
+}
+
+// byDegree sorts nodes by decreasing degree.
+type byDegree struct {
+	nodes []graph.Node
+	g     graph.Graph
+}
+
+func (n byDegree) Len() int { return len(n.nodes) }
+func (n byDegree) Less(i, j int) bool {
+	return len(n.g.From(n.nodes[i])) > len(n.g.From(n.nodes[j]))
+}
+func (n byDegree) Swap(i, j int) { n.nodes[i], n.nodes[j] = n.nodes[j], n.nodes[i] }
+
+func edgeMatrix(g graph.Graph, nodes []graph.Node) [][]graph.Edge {
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	m := make([][]graph.Edge, len(nodes))
+	for i := range m {
+		m[i] = make([]graph.Edge, len(nodes))
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			m[i][indexOf[v.ID()]] = g.Edge(u, v)
+		}
+	}
+	return m
+}
+
+// editState is a partial mapping of the nodes of a to the nodes of b.
+type editState struct {
+	// mapping holds the index into b of
+	// each mapped node of a, or -1 if the
+	// node of a is deleted.
+	mapping []int
+	used    []bool
+
+	// cost is the cost of the partial mapping
+	// and bound is the cost plus an admissible
+	// estimate of the cost of completion.
+	cost, bound float64
+
+	complete bool
+}
+
+// edgeCost returns the cost of the edge operation between the edge x in a
+// and the edge y in b.
+func (e *editor) edgeCost(x, y graph.Edge) float64 {
+	switch {
+	case x != nil && y != nil:
+		return e.costs.edgeSub(x, y)
+	case x != nil:
+		return e.costs.edgeDel(x)
+	case y != nil:
+		return e.costs.edgeIns(y)
+	default:
+		return 0
+	}
+}
+
+// bEdge returns the edge in b between the indices i and j or nil if either
+// is a deletion.
+func (e *editor) bEdge(i, j int) graph.Edge {
+	if i < 0 || j < 0 {
+		return nil
+	}
+	return e.eb[i][j]
+}
+
+// extend returns the state s with the next node of a mapped to the node of
+// b with index v, or deleted if v is -1.
+func (e *editor) extend(s editState, v int) editState {
+	k := len(s.mapping)
+	next := editState{
+		mapping: append(s.mapping[:k:k], v),
+		used:    append([]bool(nil), s.used...),
+		cost:    s.cost,
+	}
+	if v < 0 {
+		next.cost += e.costs.nodeDel(e.a[k])
+	} else {
+		next.used[v] = true
+		next.cost += e.costs.nodeSub(e.a[k], e.b[v])
+	}
+	for i, w := range s.mapping {
+		next.cost += e.edgeCost(e.ea[k][i], e.bEdge(v, w))
+		if e.directed {
+			next.cost += e.edgeCost(e.ea[i][k], e.bEdge(w, v))
+		}
+	}
+	next.cost += e.edgeCost(e.ea[k][k], e.bEdge(v, v))
+	next.bound = next.cost + e.heuristic(next)
+	return next
+}
+
+// finish returns the state s completed by inserting all unused nodes of b
+// and the edges incident to them.
+func (e *editor) finish(s editState) editState {
+	next := editState{
+		mapping:  s.mapping,
+		used:     s.used,
+		cost:     s.cost,
+		complete: true,
+	}
+	for i, used := range s.used {
+		if used {
+			continue
+		}
+		next.cost += e.costs.nodeIns(e.b[i])
+		for j, y := range e.eb[i] {
+			if y == nil {
+				continue
+			}
+			if !e.directed && j < i && !s.used[j] {
+				// Count undirected edges between
+				// inserted nodes once.
+				continue
+			}
+			next.cost += e.costs.edgeIns(y)
+		}
+		if e.directed {
+			for j := range e.eb {
+				if y := e.eb[j][i]; y != nil && s.used[j] {
+					next.cost += e.costs.edgeIns(y)
+				}
+			}
+		}
+	}
+	next.bound = next.cost
+	return next
+}
+
+// heuristic returns an admissible estimate of the cost of completing s
+// based on the cheapest available operation for each remaining node and
+// the cheapest deletions or insertions of the remaining edges.
+func (e *editor) heuristic(s editState) float64 {
+	var fromA float64
+	for _, u := range e.a[len(s.mapping):] {
+		min := e.costs.nodeDel(u)
+		for j, v := range e.b {
+			if !s.used[j] {
+				min = math.Min(min, e.costs.nodeSub(u, v))
+			}
+		}
+		fromA += min
+	}
+	var fromB float64
+	for j, v := range e.b {
+		if s.used[j] {
+			continue
+		}
+		min := e.costs.nodeIns(v)
+		for _, u := range e.a[len(s.mapping):] {
+			min = math.Min(min, e.costs.nodeSub(u, v))
+		}
+		fromB += min
+	}
+	return math.Max(fromA, fromB) + e.edgeHeuristic(s)
+}
+
+// edgeHeuristic returns a lower bound on the cost of the edge operations
+// needed to complete s. Each edge not yet costed is either substituted,
+// pairing edges of a and b one to one, or deleted or inserted, so at least
+// the difference between the numbers of remaining edges in a and in b must
+// be deleted or inserted.
+func (e *editor) edgeHeuristic(s editState) float64 {
+	k := len(s.mapping)
+	na, minDel := 0, math.Inf(1)
+	for i, row := range e.ea {
+		for j, x := range row {
+			if x == nil || (i < k && j < k) || (!e.directed && j < i) {
+				continue
+			}
+			na++
+			minDel = math.Min(minDel, e.costs.edgeDel(x))
+		}
+	}
+	nb, minIns := 0, math.Inf(1)
+	for i, row := range e.eb {
+		for j, y := range row {
+			if y == nil || (s.used[i] && s.used[j]) || (!e.directed && j < i) {
+				continue
+			}
+			nb++
+			minIns = math.Min(minIns, e.costs.edgeIns(y))
+		}
+	}
+	switch {
+	case na > nb:
+		return float64(na-nb) * minDel
+	case nb > na:
+		return float64(nb-na) * minIns
+	}
+	return 0
+}
+
+// successors returns the states reachable from s in one step.
+func (e *editor) successors(s editState) []editState {
+	if len(s.mapping) == len(e.a) {
+		return []editState{e.finish(s)}
+	}
+	next := make([]editState, 0, len(e.b)+1)
+	for v, used := range s.used {
+		if !used {
+			next = append(next, e.extend(s, v))
+		}
+	}
+	return append(next, e.extend(s, -1))
+}
+
+func (e *editor) root() editState {
+	s := editState{used: make([]bool, len(e.b))}
+	s.bound = e.heuristic(s)
+	return s
+}
+
+// astar returns the exact edit distance by A* search.
+func (e *editor) astar() float64 {
+	q := editQueue{e.root()}
+	for q.Len() != 0 {
+		s := heap.Pop(&q).(editState)
+		if s.complete {
+			return s.cost
+		}
+		for _, n := range e.successors(s) {
+			heap.Push(&q, n)
+		}
+	}
+	panic("iso: no complete edit path")
+}
+
+// beam returns an upper bound for the edit distance by beam search.
+func (e *editor) beam(width int) float64 {
+	level := []editState{e.root()}
+	for !level[0].complete {
+		var next editQueue
+		for _, s := range level {
+			next = append(next, e.successors(s)...)
+		}
+		sort.Sort(next)
+		if len(next) > width {
+			next = next[:width]
+		}
+		level = next
+	}
+	return level[0].cost
+}
+
+// editQueue is a priority queue of edit states ordered by bound.
+type editQueue []editState
+
+func (q editQueue) Len() int { return len(q) }
+func (q editQueue) Less(i, j int) bool {
+	if q[i].bound != q[j].bound {
+		return q[i].bound < q[j].bound
+	}
+	// Prefer complete and deeper states among
+	// equal bounds to avoid expanding the many
+	// equivalent partial mappings.
+	if q[i].complete != q[j].complete {
+		return q[i].complete
+	}
+	return len(q[i].mapping) > len(q[j].mapping)
+}
+func (q editQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *editQueue) Push(n interface{}) { *q = append(*q, n.(editState)) }
+func (q *editQueue) Pop() interface{} {
+	t := *q
+	var n interface{}
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
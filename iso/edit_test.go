@@ -0,0 +1,199 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iso
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func undirected(edges ...[2]int) graph.Graph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+func directed(edges ...[2]int) graph.Graph {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+var editDistanceTests = []struct {
+	name string
+	a, b graph.Graph
+	want float64
+}{
+	{
+		name: "empty",
+		a:    undirected(),
+		b:    undirected(),
+		want: 0,
+	},
+	{
+		name: "empty to triangle",
+		a:    undirected(),
+		b:    undirected([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 0}),
+		want: 6,
+	},
+	{
+		name: "path to triangle",
+		a:    undirected([2]int{0, 1}, [2]int{1, 2}),
+		b:    undirected([2]int{3, 4}, [2]int{4, 5}, [2]int{5, 3}),
+		want: 1,
+	},
+	{
+		name: "path to star",
+		a:    undirected([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}),
+		b:    undirected([2]int{0, 1}, [2]int{0, 2}, [2]int{0, 3}),
+		want: 2,
+	},
+	{
+		name: "isomorphic directed",
+		a:    directed([2]int{0, 1}, [2]int{1, 2}),
+		b:    directed([2]int{2, 1}, [2]int{1, 0}),
+		want: 0,
+	},
+	{
+		name: "directed reversal",
+		a:    directed([2]int{0, 1}, [2]int{1, 2}),
+		b:    directed([2]int{0, 1}, [2]int{2, 1}),
+		want: 2,
+	},
+	{
+		name: "triangle to square",
+		a:    undirected([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 0}),
+		b:    undirected([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 0}),
+		want: 4,
+	},
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, test := range editDistanceTests {
+		got, exact := EditDistance(test.a, test.b, EditCosts{})
+		if !exact {
+			t.Errorf("%q: expected exact edit distance", test.name)
+		}
+		if got != test.want {
+			t.Errorf("%q: unexpected edit distance: got:%v want:%v", test.name, got, test.want)
+		}
+		got = BeamEditDistance(test.a, test.b, EditCosts{}, 1000)
+		if got != test.want {
+			t.Errorf("%q: unexpected wide beam edit distance: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBeamEditDistanceBound(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		a := simple.NewUndirectedGraph(0, math.Inf(1))
+		b := simple.NewUndirectedGraph(0, math.Inf(1))
+		gen.Gnp(a, 7, 0.4, rnd)
+		gen.Gnp(b, 6, 0.4, rnd)
+		exact, _ := EditDistance(a, b, EditCosts{})
+		for _, width := range []int{1, 5, 50} {
+			approx := BeamEditDistance(a, b, EditCosts{}, width)
+			if approx < exact {
+				t.Errorf("beam width %d edit distance below exact: got:%v exact:%v", width, approx, exact)
+			}
+		}
+		if back, _ := EditDistance(b, a, EditCosts{}); back != exact {
+			t.Errorf("unexpected asymmetric unit cost edit distance: %v != %v", exact, back)
+		}
+	}
+}
+
+func TestEditDistanceDense(t *testing.T) {
+	// The edge heuristic lets the exact search reach
+	// the substitution of all nodes directly rather
+	// than exploring every partial mapping.
+	k10 := simple.NewUndirectedGraph(0, math.Inf(1))
+	gen.Gnp(k10, 10, 1, nil)
+	empty := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 10; i++ {
+		empty.AddNode(simple.Node(i))
+	}
+	for _, test := range []struct{ a, b graph.Graph }{{k10, empty}, {empty, k10}} {
+		got, exact := EditDistance(test.a, test.b, EditCosts{})
+		if !exact {
+			t.Error("expected exact edit distance")
+		}
+		if got != 45 {
+			t.Errorf("unexpected edit distance: got:%v want:45", got)
+		}
+	}
+}
+
+// loopGraph is an undirected graph that may hold self loops.
+type loopGraph struct {
+	nodes []graph.Node
+	edges map[[2]int]graph.Edge
+}
+
+func newLoopGraph(n int, edges ...[2]int) loopGraph {
+	g := loopGraph{edges: make(map[[2]int]graph.Edge)}
+	for i := 0; i < n; i++ {
+		g.nodes = append(g.nodes, simple.Node(i))
+	}
+	for _, e := range edges {
+		edge := simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1}
+		g.edges[e] = edge
+		g.edges[[2]int{e[1], e[0]}] = edge
+	}
+	return g
+}
+
+func (g loopGraph) Has(n graph.Node) bool { return 0 <= n.ID() && n.ID() < len(g.nodes) }
+func (g loopGraph) Nodes() []graph.Node   { return append([]graph.Node(nil), g.nodes...) }
+func (g loopGraph) From(n graph.Node) []graph.Node {
+	var to []graph.Node
+	for _, v := range g.nodes {
+		if _, ok := g.edges[[2]int{n.ID(), v.ID()}]; ok {
+			to = append(to, v)
+		}
+	}
+	return to
+}
+func (g loopGraph) HasEdgeBetween(x, y graph.Node) bool { return g.Edge(x, y) != nil }
+func (g loopGraph) Edge(u, v graph.Node) graph.Edge {
+	e, ok := g.edges[[2]int{u.ID(), v.ID()}]
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+func TestEditDistanceSelfLoops(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		a, b graph.Graph
+		want float64
+	}{
+		{name: "loop to none", a: newLoopGraph(1, [2]int{0, 0}), b: newLoopGraph(1), want: 1},
+		{name: "none to loop", a: newLoopGraph(1), b: newLoopGraph(1, [2]int{0, 0}), want: 1},
+		{name: "loop to loop", a: newLoopGraph(2, [2]int{0, 0}), b: newLoopGraph(2, [2]int{1, 1}), want: 0},
+		{
+			name: "loops on path",
+			a:    newLoopGraph(2, [2]int{0, 1}, [2]int{0, 0}, [2]int{1, 1}),
+			b:    newLoopGraph(2, [2]int{0, 1}, [2]int{1, 1}),
+			want: 1,
+		},
+	} {
+		got, _ := EditDistance(test.a, test.b, EditCosts{})
+		if got != test.want {
+			t.Errorf("%q: unexpected edit distance: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
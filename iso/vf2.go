@@ -0,0 +1,248 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iso
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Compatibility holds the functions used to decide whether pattern nodes
+// and edges may be matched to graph nodes and edges. A nil function allows
+// any match.
+type Compatibility struct {
+	// Node returns whether the pattern
+	// node p may be matched to the node n.
+	Node func(p, n graph.Node) bool
+
+	// Edge returns whether the pattern
+	// edge p may be matched to the edge e.
+	Edge func(p, e graph.Edge) bool
+}
+
+func (c Compatibility) node(p, n graph.Node) bool {
+	return c.Node == nil || c.Node(p, n)
+}
+
+func (c Compatibility) edge(p, e graph.Edge) bool {
+	return c.Edge == nil || c.Edge(p, e)
+}
+
+// SubgraphMatches calls fn with each injective mapping of the nodes of pattern
+// to the nodes of g under which every edge of pattern maps to an edge of g,
+// until fn returns false. The mapping passed to fn is keyed by pattern node ID
+// and may be retained. Edges of g between matched nodes that are not in the
+// pattern are permitted. Candidate node and edge matches are filtered by compat.
+//
+// If pattern is a Directed graph, edge direction is taken into account and g
+// must also be Directed.
+//
+// SubgraphMatches uses a VF2-style depth first search, extending the partial
+// mapping along the edges of the pattern and pruning candidates by degree.
+func SubgraphMatches(pattern, g graph.Graph, compat Compatibility, fn func(map[int]graph.Node) bool) {
+	m := newMatcher(pattern, g, compat)
+	if len(m.order) == 0 {
+		fn(make(map[int]graph.Node))
+		return
+	}
+	m.match(0, fn)
+}
+
+// matcher holds the state of a subgraph match search.
+type matcher struct {
+	pattern, g graph.Graph
+	compat     Compatibility
+
+	directed bool
+
+	// order is the order in which pattern
+	// nodes are matched and links holds,
+	// for each, the edges to pattern nodes
+	// earlier in the order.
+	order []graph.Node
+	links [][]link
+
+	hosts  []graph.Node
+	mapped []graph.Node
+	used   map[int]bool
+}
+
+// link is an edge between the pattern node at a position in the
+// match order and the pattern node at an earlier position, at.
+type link struct {
+	at int
+
+	// out and in indicate pattern edges
+	// to and from the earlier node.
+	out, in bool
+}
+
+func newMatcher(pattern, g graph.Graph, compat Compatibility) *matcher {
+	_, directed := pattern.(graph.Directed)
+	m := &matcher{
+		pattern:  pattern,
+		g:        g,
+		compat:   compat,
+		directed: directed,
+		hosts:    g.Nodes(),
+		used:     make(map[int]bool),
+	}
+	sort.Sort(ordered.ByID(m.hosts))
+
+	// Order the pattern nodes so that each node is
+	// connected to as many earlier nodes as possible,
+	// preferring high degree nodes, so that the search
+	// is constrained early.
+	nodes := pattern.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	pos := make(map[int]int, len(nodes))
+	for len(m.order) < len(nodes) {
+		best, bestLinks, bestDeg := -1, -1, -1
+		for i, n := range nodes {
+			if _, ok := pos[n.ID()]; ok {
+				continue
+			}
+			var l int
+			for _, v := range m.neighbors(pattern, n) {
+				if _, ok := pos[v.ID()]; ok {
+					l++
+				}
+			}
+			d := m.degree(pattern, n)
+			if l > bestLinks || (l == bestLinks && d > bestDeg) {
+				best, bestLinks, bestDeg = i, l, d
+			}
+		}
+		n := nodes[best]
+		var links []link
+		for _, v := range m.neighbors(pattern, n) {
+			at, ok := pos[v.ID()]
+			if !ok {
+				continue
+			}
+			l := link{at: at}
+			if m.directed {
+				d := pattern.(graph.Directed)
+				l.out = d.HasEdgeFromTo(n, v)
+				l.in = d.HasEdgeFromTo(v, n)
+			} else {
+				l.out = true
+			}
+			links = append(links, l)
+		}
+		pos[n.ID()] = len(m.order)
+		m.order = append(m.order, n)
+		m.links = append(m.links, links)
+	}
+	m.mapped = make([]graph.Node, len(m.order))
+	return m
+}
+
+// neighbors returns the nodes adjacent to n in g ignoring direction.
+func (m *matcher) neighbors(g graph.Graph, n graph.Node) []graph.Node {
+	if !m.directed {
+		return g.From(n)
+	}
+	seen := make(map[int]bool)
+	var adj []graph.Node
+	for _, v := range g.From(n) {
+		seen[v.ID()] = true
+		adj = append(adj, v)
+	}
+	for _, v := range g.(graph.Directed).To(n) {
+		if !seen[v.ID()] {
+			adj = append(adj, v)
+		}
+	}
+	return adj
+}
+
+func (m *matcher) degree(g graph.Graph, n graph.Node) int {
+	d := len(g.From(n))
+	if m.directed {
+		d += len(g.(graph.Directed).To(n))
+	}
+	return d
+}
+
+// match extends the partial mapping of the first i pattern nodes.
+func (m *matcher) match(i int, fn func(map[int]graph.Node) bool) bool {
+	if i == len(m.order) {
+		mapping := make(map[int]graph.Node, len(m.order))
+		for k, p := range m.order {
+			mapping[p.ID()] = m.mapped[k]
+		}
+		return fn(mapping)
+	}
+
+	p := m.order[i]
+	candidates := m.hosts
+	if len(m.links[i]) != 0 {
+		// Only neighbors of the image of a linked
+		// pattern node can be matched to p.
+		l := m.links[i][0]
+		h := m.mapped[l.at]
+		switch {
+		case !m.directed:
+			candidates = m.g.From(h)
+		case l.out:
+			candidates = m.g.(graph.Directed).To(h)
+		default:
+			candidates = m.g.From(h)
+		}
+	}
+	for _, n := range candidates {
+		if !m.feasible(i, p, n) {
+			continue
+		}
+		m.mapped[i] = n
+		m.used[n.ID()] = true
+		ok := m.match(i+1, fn)
+		m.used[n.ID()] = false
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// feasible returns whether the pattern node p at position i may be
+// mapped to n given the current partial mapping.
+func (m *matcher) feasible(i int, p, n graph.Node) bool {
+	if m.used[n.ID()] || !m.compat.node(p, n) {
+		return false
+	}
+	if len(m.g.From(n)) < len(m.pattern.From(p)) {
+		return false
+	}
+	if m.directed && len(m.g.(graph.Directed).To(n)) < len(m.pattern.(graph.Directed).To(p)) {
+		return false
+	}
+	for _, l := range m.links[i] {
+		q, h := m.order[l.at], m.mapped[l.at]
+		if l.out && !m.edge(p, q, n, h) {
+			return false
+		}
+		if l.in && !m.edge(q, p, h, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// edge returns whether the pattern edge from p to q can be matched by
+// an edge from n to h.
+func (m *matcher) edge(p, q, n, h graph.Node) bool {
+	if m.directed {
+		if !m.g.(graph.Directed).HasEdgeFromTo(n, h) {
+			return false
+		}
+	} else if !m.g.HasEdgeBetween(n, h) {
+		return false
+	}
+	return m.compat.edge(m.pattern.Edge(p, q), m.g.Edge(n, h))
+}
@@ -0,0 +1,152 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iso
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestSubgraphMatchesTriangles(t *testing.T) {
+	k4 := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			k4.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: 1})
+		}
+	}
+	tri := simple.NewUndirectedGraph(0, math.Inf(1))
+	tri.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	tri.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	tri.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0), W: 1})
+
+	var n int
+	SubgraphMatches(tri, k4, Compatibility{}, func(map[int]graph.Node) bool {
+		n++
+		return true
+	})
+	// Four triangles each with six automorphisms.
+	if n != 24 {
+		t.Errorf("unexpected number of triangle matches: got:%d want:24", n)
+	}
+
+	n = 0
+	SubgraphMatches(tri, k4, Compatibility{}, func(map[int]graph.Node) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("search did not stop: got:%d calls want:1", n)
+	}
+
+	n = 0
+	compat := Compatibility{Node: func(p, g graph.Node) bool { return p.ID() != 0 || g.ID() == 3 }}
+	SubgraphMatches(tri, k4, compat, func(m map[int]graph.Node) bool {
+		if m[0].ID() != 3 {
+			t.Errorf("incompatible node match: %v", m)
+		}
+		n++
+		return true
+	})
+	if n != 6 {
+		t.Errorf("unexpected number of compatible matches: got:%d want:6", n)
+	}
+}
+
+func TestSubgraphMatchesRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		for _, directed := range []bool{false, true} {
+			p := randomGraph(rnd, directed, 4, 0.5)
+			g := randomGraph(rnd, directed, 7, 0.5)
+
+			got := make(map[[4]int]bool)
+			SubgraphMatches(p, g, Compatibility{}, func(m map[int]graph.Node) bool {
+				var k [4]int
+				for i := range k {
+					k[i] = m[i].ID()
+				}
+				if got[k] {
+					t.Errorf("trial %d: duplicate match %v", trial, k)
+				}
+				got[k] = true
+				return true
+			})
+
+			var want int
+			for a := 0; a < 7; a++ {
+				for b := 0; b < 7; b++ {
+					for c := 0; c < 7; c++ {
+						for d := 0; d < 7; d++ {
+							k := [4]int{a, b, c, d}
+							if isEmbedding(p, g, k, directed) {
+								want++
+								if !got[k] {
+									t.Errorf("trial %d directed=%t: missing match %v", trial, directed, k)
+								}
+							}
+						}
+					}
+				}
+			}
+			if len(got) != want {
+				t.Errorf("trial %d directed=%t: unexpected number of matches: got:%d want:%d", trial, directed, len(got), want)
+			}
+		}
+	}
+}
+
+func randomGraph(rnd *rand.Rand, directed bool, n int, p float64) graph.Graph {
+	var g graph.Builder
+	if directed {
+		g = simple.NewDirectedGraph(0, math.Inf(1))
+	} else {
+		g = simple.NewUndirectedGraph(0, math.Inf(1))
+	}
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j || (!directed && j < i) {
+				continue
+			}
+			if rnd.Float64() < p {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: 1})
+			}
+		}
+	}
+	return g.(graph.Graph)
+}
+
+func isEmbedding(p, g graph.Graph, k [4]int, directed bool) bool {
+	for i := range k {
+		for j := i + 1; j < len(k); j++ {
+			if k[i] == k[j] {
+				return false
+			}
+		}
+	}
+	for i := range k {
+		for j := range k {
+			if i == j {
+				continue
+			}
+			if directed {
+				if p.(graph.Directed).HasEdgeFromTo(simple.Node(i), simple.Node(j)) &&
+					!g.(graph.Directed).HasEdgeFromTo(simple.Node(k[i]), simple.Node(k[j])) {
+					return false
+				}
+			} else if p.HasEdgeBetween(simple.Node(i), simple.Node(j)) &&
+				!g.HasEdgeBetween(simple.Node(k[i]), simple.Node(k[j])) {
+				return false
+			}
+		}
+	}
+	return true
+}
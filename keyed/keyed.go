@@ -0,0 +1,203 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package keyed provides graphs whose nodes are identified by arbitrary
+// comparable keys, such as strings, UUIDs or structs, that are mapped to
+// the integer node IDs used by the graph packages.
+package keyed
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Node is a graph node with an external key.
+type Node struct {
+	id  int
+	key interface{}
+}
+
+// ID returns the ID number of the node.
+func (n Node) ID() int { return n.id }
+
+// Key returns the external key of the node.
+func (n Node) Key() interface{} { return n.key }
+
+// Registry maps comparable keys to the nodes of a graph and back.
+type Registry struct {
+	g graph.NodeAdder
+
+	nodes map[interface{}]graph.Node
+	keys  map[int]interface{}
+}
+
+// NewRegistry returns a Registry that adds the nodes it creates to g.
+func NewRegistry(g graph.NodeAdder) *Registry {
+	return &Registry{
+		g:     g,
+		nodes: make(map[interface{}]graph.Node),
+		keys:  make(map[int]interface{}),
+	}
+}
+
+// checkKey panics if key is not a valid key.
+func checkKey(key interface{}) {
+	if key == nil {
+		panic("keyed: nil key")
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		panic(fmt.Sprintf("keyed: key type %T is not comparable", key))
+	}
+}
+
+// GetOrCreate returns the node registered with the given key. If no
+// node is registered with the key, a new Node is created with an ID
+// from the registry's graph, added to the graph and registered.
+// GetOrCreate panics if key is nil or not comparable.
+func (r *Registry) GetOrCreate(key interface{}) graph.Node {
+	checkKey(key)
+	if n, ok := r.nodes[key]; ok {
+		return n
+	}
+	n := Node{id: r.g.NewNodeID(), key: key}
+	r.g.AddNode(n)
+	r.nodes[key] = n
+	r.keys[n.id] = key
+	return n
+}
+
+// Register registers the node n with the given key. The node is not
+// added to the registry's graph. Register panics if key is nil or not
+// comparable, or if the key or the node ID is already registered.
+func (r *Registry) Register(key interface{}, n graph.Node) {
+	checkKey(key)
+	if _, exists := r.nodes[key]; exists {
+		panic(fmt.Sprintf("keyed: key %v already registered", key))
+	}
+	if k, exists := r.keys[n.ID()]; exists {
+		panic(fmt.Sprintf("keyed: node ID %d already registered with key %v", n.ID(), k))
+	}
+	r.nodes[key] = n
+	r.keys[n.ID()] = key
+}
+
+// Remove removes the registration of the given key, returning whether
+// the key was registered. The node is not removed from the registry's
+// graph.
+func (r *Registry) Remove(key interface{}) bool {
+	n, ok := r.nodes[key]
+	if !ok {
+		return false
+	}
+	delete(r.nodes, key)
+	delete(r.keys, n.ID())
+	return true
+}
+
+// Node returns the node registered with the given key and whether
+// it exists.
+func (r *Registry) Node(key interface{}) (graph.Node, bool) {
+	n, ok := r.nodes[key]
+	return n, ok
+}
+
+// Key returns the key registered for the node ID and whether it exists.
+func (r *Registry) Key(id int) (interface{}, bool) {
+	key, ok := r.keys[id]
+	return key, ok
+}
+
+// Len returns the number of registered keys.
+func (r *Registry) Len() int { return len(r.nodes) }
+
+// Builder is a graph that can have nodes and edges added.
+type Builder interface {
+	graph.Graph
+	graph.Builder
+}
+
+// Graph is a facade over a graph that allows nodes and edges to be
+// added and queried by key. The underlying graph is available through
+// the embedded Builder for use with the graph algorithm packages.
+type Graph struct {
+	Builder
+
+	reg *Registry
+}
+
+// NewGraph returns a Graph that adds keyed nodes and edges to g.
+func NewGraph(g Builder) *Graph {
+	return &Graph{Builder: g, reg: NewRegistry(g)}
+}
+
+// Registry returns the registry holding the keys of g.
+func (g *Graph) Registry() *Registry { return g.reg }
+
+// AddKey returns the node for the given key, adding a new node to the
+// graph if the key is not already present.
+func (g *Graph) AddKey(key interface{}) graph.Node {
+	return g.reg.GetOrCreate(key)
+}
+
+// NodeFor returns the node for the given key and whether it exists.
+func (g *Graph) NodeFor(key interface{}) (graph.Node, bool) {
+	return g.reg.Node(key)
+}
+
+// KeyOf returns the key of the node n and whether it has one.
+func (g *Graph) KeyOf(n graph.Node) (interface{}, bool) {
+	return g.reg.Key(n.ID())
+}
+
+// HasKey returns whether the key is present in the graph.
+func (g *Graph) HasKey(key interface{}) bool {
+	_, ok := g.reg.Node(key)
+	return ok
+}
+
+// SetEdgeBetweenKeys sets an edge with weight w from the node for k1 to
+// the node for k2, adding nodes for keys that are not present. In an
+// undirected graph the edge is between the two nodes.
+func (g *Graph) SetEdgeBetweenKeys(k1, k2 interface{}, w float64) {
+	u := g.reg.GetOrCreate(k1)
+	v := g.reg.GetOrCreate(k2)
+	g.SetEdge(simple.Edge{F: u, T: v, W: w})
+}
+
+// EdgeBetweenKeys returns the edge from the node for k1 to the node for
+// k2, or nil if either key is not present or there is no such edge.
+func (g *Graph) EdgeBetweenKeys(k1, k2 interface{}) graph.Edge {
+	u, ok := g.reg.Node(k1)
+	if !ok {
+		return nil
+	}
+	v, ok := g.reg.Node(k2)
+	if !ok {
+		return nil
+	}
+	if !g.Has(u) || !g.Has(v) {
+		return nil
+	}
+	return g.Edge(u, v)
+}
+
+// RemoveKey removes the node for the given key and its edges from the
+// graph, returning whether the key was present. RemoveKey panics if the
+// underlying graph is not a graph.NodeRemover.
+func (g *Graph) RemoveKey(key interface{}) bool {
+	r, ok := g.Builder.(graph.NodeRemover)
+	if !ok {
+		panic("keyed: graph cannot remove nodes")
+	}
+	n, ok := g.reg.Node(key)
+	if !ok {
+		return false
+	}
+	r.RemoveNode(n)
+	g.reg.Remove(key)
+	return true
+}
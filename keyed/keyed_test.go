@@ -0,0 +1,84 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package keyed
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+type uuid [16]byte
+
+type place struct {
+	city    string
+	country string
+}
+
+func TestGraph(t *testing.T) {
+	g := NewGraph(simple.NewDirectedGraph(0, math.Inf(1)))
+	a := uuid{1}
+	b := uuid{2}
+	c := place{city: "Adelaide", country: "Australia"}
+
+	g.SetEdgeBetweenKeys(a, b, 1)
+	g.SetEdgeBetweenKeys(b, c, 2)
+	g.SetEdgeBetweenKeys(a, c, 5)
+	g.SetEdgeBetweenKeys("d", a, 1)
+
+	if n := len(g.Nodes()); n != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", n)
+	}
+	if e := g.EdgeBetweenKeys(b, c); e == nil || e.Weight() != 2 {
+		t.Errorf("unexpected edge between keys: got:%v", e)
+	}
+	if e := g.EdgeBetweenKeys(c, b); e != nil {
+		t.Errorf("unexpected reverse edge: got:%v", e)
+	}
+	if e := g.EdgeBetweenKeys(a, "missing"); e != nil {
+		t.Errorf("unexpected edge to missing key: got:%v", e)
+	}
+
+	u, _ := g.NodeFor(a)
+	v, _ := g.NodeFor(c)
+	if key, ok := g.KeyOf(v); !ok || key != c {
+		t.Errorf("unexpected key for node: got:%v want:%v", key, c)
+	}
+	if k := u.(Node).Key(); k != a {
+		t.Errorf("unexpected node key: got:%v want:%v", k, a)
+	}
+
+	pt := path.DijkstraFrom(u, g.Builder)
+	p, w := pt.To(v)
+	if w != 3 || len(p) != 3 {
+		t.Errorf("unexpected shortest path: got:%v weight:%v", p, w)
+	}
+
+	if !g.RemoveKey(b) {
+		t.Errorf("expected key to be removed")
+	}
+	if g.HasKey(b) {
+		t.Errorf("removed key still present")
+	}
+	if n := len(g.Nodes()); n != 3 {
+		t.Errorf("unexpected number of nodes after removal: got:%d want:3", n)
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	r := NewRegistry(simple.NewUndirectedGraph(0, math.Inf(1)))
+	for _, key := range []interface{}{nil, []int{1}, map[string]int{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for key %#v", key)
+				}
+			}()
+			r.GetOrCreate(key)
+		}()
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package layout provides graph layout functions.
+package layout
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Point is a position in the plane.
+type Point struct {
+	X, Y float64
+}
+
+// Cooling is a cooling schedule for force-directed layout. It returns the
+// maximum displacement of a node during iteration i of n given the initial
+// temperature t0.
+type Cooling func(i, n int, t0 float64) float64
+
+// LinearCooling is a Cooling schedule that decreases the temperature linearly
+// to zero over the iterations of a layout.
+func LinearCooling(i, n int, t0 float64) float64 {
+	return t0 * (1 - float64(i)/float64(n))
+}
+
+// ExponentialCooling returns a Cooling schedule that multiplies the
+// temperature by factor at each iteration.
+func ExponentialCooling(factor float64) Cooling {
+	return func(i, _ int, t0 float64) float64 {
+		return t0 * math.Pow(factor, float64(i))
+	}
+}
+
+// ForceDirected is a force-directed layout using the spring-electrical model
+// of Fruchterman and Reingold, doi:10.1002/spe.4380211102. Nodes repel each
+// other and edges attract their terminal nodes, and the positions of the nodes
+// are updated iteratively with displacements limited by a decreasing
+// temperature. Edge direction is ignored.
+//
+// Repulsive forces are approximated by the Barnes-Hut method,
+// doi:10.1038/324446a0, which reduces the cost of each iteration from
+// O(n^2) to O(n log n) for n nodes, allowing layout of large graphs.
+type ForceDirected struct {
+	// Iterations is the number of iterations
+	// of the layout. If Iterations is zero,
+	// 100 iterations are performed.
+	Iterations int
+
+	// Theta is the Barnes-Hut opening
+	// criterion. Groups of nodes in a cell
+	// of width w at a distance d are treated
+	// as a single body if w/d < Theta. If
+	// Theta is zero, repulsive forces are
+	// calculated exactly.
+	Theta float64
+
+	// Temperature is the initial maximum
+	// displacement of a node in a single
+	// iteration. If Temperature is zero,
+	// one tenth of the initial layout
+	// width is used.
+	Temperature float64
+
+	// Cooling is the cooling schedule. If
+	// Cooling is nil, LinearCooling is used.
+	Cooling Cooling
+
+	// Step, if not nil, is called after each
+	// iteration with the iteration number
+	// and the current node positions keyed
+	// by node ID. The positions must not be
+	// retained or modified. If Step returns
+	// false the layout is terminated.
+	Step func(iter int, pos map[int]Point) bool
+
+	// Src is the random source used for
+	// initial node positions. If Src is nil,
+	// rand.Float64 is used.
+	Src *rand.Rand
+}
+
+// Layout returns node positions for g keyed by node ID. Nodes are initially
+// placed at random in a square with an area proportional to the number of
+// nodes and the natural spring length is one.
+func (l ForceDirected) Layout(g graph.Graph) map[int]Point {
+	pos, _ := l.layout(g, time.Time{})
+	return pos
+}
+
+// LayoutWithin returns node positions for g as calculated by Layout, ending
+// the layout early if the time budget elapses. At least one iteration is
+// always performed. The returned residual is the largest displacement of a
+// node demanded by the forces in the final iteration before it was limited
+// by the temperature. The residual is zero for a layout in equilibrium and
+// so bounds the distance of the returned layout from a stable layout.
+func (l ForceDirected) LayoutWithin(g graph.Graph, budget time.Duration) (pos map[int]Point, residual float64) {
+	var deadline time.Time
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+	return l.layout(g, deadline)
+}
+
+// layout performs the layout of g, ending after the deadline if it is not
+// zero, and returns the positions and residual force.
+func (l ForceDirected) layout(g graph.Graph, deadline time.Time) (map[int]Point, float64) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	var edges [][2]int
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if j := indexOf[v.ID()]; i < j || (i > j && !g.HasEdgeBetween(v, u)) {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	}
+
+	rnd := rand.Float64
+	if l.Src != nil {
+		rnd = l.Src.Float64
+	}
+	width := math.Sqrt(float64(len(nodes)))
+	pos := make([]Point, len(nodes))
+	for i := range pos {
+		pos[i] = Point{X: (rnd() - 0.5) * width, Y: (rnd() - 0.5) * width}
+	}
+
+	iters := l.Iterations
+	if iters == 0 {
+		iters = 100
+	}
+	t0 := l.Temperature
+	if t0 == 0 {
+		t0 = width / 10
+	}
+	cool := l.Cooling
+	if cool == nil {
+		cool = LinearCooling
+	}
+
+	var step map[int]Point
+	if l.Step != nil {
+		step = make(map[int]Point, len(nodes))
+	}
+	disp := make([]Point, len(nodes))
+	var residual float64
+	for iter := 0; iter < iters; iter++ {
+		if iter != 0 && !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		for i := range disp {
+			disp[i] = Point{}
+		}
+		repulsion(disp, pos, l.Theta)
+		for _, e := range edges {
+			d := Point{X: pos[e[0]].X - pos[e[1]].X, Y: pos[e[0]].Y - pos[e[1]].Y}
+			dist := math.Hypot(d.X, d.Y)
+			if dist == 0 {
+				continue
+			}
+			// f_a = d^2/k with k = 1.
+			f := dist
+			disp[e[0]].X -= d.X * f
+			disp[e[0]].Y -= d.Y * f
+			disp[e[1]].X += d.X * f
+			disp[e[1]].Y += d.Y * f
+		}
+
+		t := cool(iter, iters, t0)
+		residual = 0
+		for i, d := range disp {
+			mag := math.Hypot(d.X, d.Y)
+			residual = math.Max(residual, mag)
+			if mag == 0 {
+				continue
+			}
+			s := math.Min(mag, t) / mag
+			pos[i].X += d.X * s
+			pos[i].Y += d.Y * s
+		}
+
+		if l.Step != nil {
+			for i, n := range nodes {
+				step[n.ID()] = pos[i]
+			}
+			if !l.Step(iter, step) {
+				break
+			}
+		}
+	}
+
+	layout := make(map[int]Point, len(nodes))
+	for i, n := range nodes {
+		layout[n.ID()] = pos[i]
+	}
+	return layout, residual
+}
+
+// repulsion adds the repulsive forces between the bodies at pos to disp,
+// using a Barnes-Hut approximation if theta is positive.
+func repulsion(disp, pos []Point, theta float64) {
+	if theta <= 0 {
+		for i := range pos {
+			for j := i + 1; j < len(pos); j++ {
+				f := repulse(pos[i], pos[j], 1)
+				disp[i].X += f.X
+				disp[i].Y += f.Y
+				disp[j].X -= f.X
+				disp[j].Y -= f.Y
+			}
+		}
+		return
+	}
+	t := newQuadTree(pos)
+	for i, p := range pos {
+		f := t.force(i, p, theta)
+		disp[i].X += f.X
+		disp[i].Y += f.Y
+	}
+}
+
+// repulse returns the repulsive force on a body at p from a body of the
+// given mass at q. Coincident bodies are separated along a fixed direction.
+func repulse(p, q Point, mass float64) Point {
+	d := Point{X: p.X - q.X, Y: p.Y - q.Y}
+	dist2 := d.X*d.X + d.Y*d.Y
+	if dist2 == 0 {
+		return Point{X: mass * 1e-3}
+	}
+	// f_r = k^2/d with k = 1, along the unit vector d/|d|.
+	f := mass / dist2
+	return Point{X: d.X * f, Y: d.Y * f}
+}
+
+// quadTree is a Barnes-Hut quadtree.
+type quadTree struct {
+	root *cell
+	pos  []Point
+}
+
+// cell is a square region of a quadtree.
+type cell struct {
+	// center and width describe
+	// the region of the cell.
+	center Point
+	width  float64
+
+	// mass is the number of bodies in
+	// the cell and centroid is their
+	// center of mass.
+	mass     float64
+	centroid Point
+
+	// bodies holds the bodies in a leaf.
+	bodies []int
+
+	children *[4]*cell
+}
+
+// maxDepth limits the depth of the tree for coincident bodies.
+const maxDepth = 50
+
+func newQuadTree(pos []Point) *quadTree {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	width := math.Max(maxX-minX, maxY-minY)
+	if width == 0 {
+		width = 1
+	}
+	t := &quadTree{
+		root: &cell{center: Point{X: (minX + maxX) / 2, Y: (minY + maxY) / 2}, width: width * (1 + 1e-9)},
+		pos:  pos,
+	}
+	for i := range pos {
+		t.root.insert(i, pos, 0)
+	}
+	return t
+}
+
+func (c *cell) insert(i int, pos []Point, depth int) {
+	p := pos[i]
+	c.centroid.X = (c.centroid.X*c.mass + p.X) / (c.mass + 1)
+	c.centroid.Y = (c.centroid.Y*c.mass + p.Y) / (c.mass + 1)
+	c.mass++
+
+	if c.children == nil {
+		if len(c.bodies) == 0 || depth == maxDepth {
+			c.bodies = append(c.bodies, i)
+			return
+		}
+		// Split the leaf.
+		c.children = &[4]*cell{}
+		for _, b := range c.bodies {
+			c.child(pos[b]).insert(b, pos, depth+1)
+		}
+		c.bodies = nil
+	}
+	c.child(p).insert(i, pos, depth+1)
+}
+
+// child returns the child cell containing p, creating it if necessary.
+func (c *cell) child(p Point) *cell {
+	var q int
+	off := Point{X: -c.width / 4, Y: -c.width / 4}
+	if p.X >= c.center.X {
+		q |= 1
+		off.X = -off.X
+	}
+	if p.Y >= c.center.Y {
+		q |= 2
+		off.Y = -off.Y
+	}
+	if c.children[q] == nil {
+		c.children[q] = &cell{
+			center: Point{X: c.center.X + off.X, Y: c.center.Y + off.Y},
+			width:  c.width / 2,
+		}
+	}
+	return c.children[q]
+}
+
+// force returns the approximate repulsive force on body i at p.
+func (t *quadTree) force(i int, p Point, theta float64) Point {
+	var f Point
+	stack := []*cell{t.root}
+	for len(stack) != 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if c.children == nil {
+			for _, b := range c.bodies {
+				if b == i {
+					continue
+				}
+				r := repulse(p, t.pos[b], 1)
+				f.X += r.X
+				f.Y += r.Y
+			}
+			continue
+		}
+		d := math.Hypot(p.X-c.centroid.X, p.Y-c.centroid.Y)
+		if d > 0 && c.width/d < theta && !c.contains(p) {
+			r := repulse(p, c.centroid, c.mass)
+			f.X += r.X
+			f.Y += r.Y
+			continue
+		}
+		for _, ch := range c.children {
+			if ch != nil {
+				stack = append(stack, ch)
+			}
+		}
+	}
+	return f
+}
+
+// contains returns whether p is within the region of c.
+func (c *cell) contains(p Point) bool {
+	h := c.width / 2
+	return c.center.X-h <= p.X && p.X < c.center.X+h && c.center.Y-h <= p.Y && p.Y < c.center.Y+h
+}
@@ -0,0 +1,156 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/gonum/graph/simple"
+)
+
+// barbell returns two n-cliques joined by a single edge.
+func barbell(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, off := range []int{0, n} {
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				g.SetEdge(simple.Edge{F: simple.Node(off + i), T: simple.Node(off + j), W: 1})
+			}
+		}
+	}
+	g.SetEdge(simple.Edge{F: simple.Node(n - 1), T: simple.Node(n), W: 1})
+	return g
+}
+
+func dist(a, b Point) float64 { return math.Hypot(a.X-b.X, a.Y-b.Y) }
+
+func TestForceDirectedBarbell(t *testing.T) {
+	const n = 8
+	for _, theta := range []float64{0, 0.5, 1} {
+		l := ForceDirected{Theta: theta, Iterations: 200, Src: rand.New(rand.NewSource(1))}
+		pos := l.Layout(barbell(n))
+		if len(pos) != 2*n {
+			t.Fatalf("unexpected number of positions: got:%d want:%d", len(pos), 2*n)
+		}
+		var within, between float64
+		var nw, nb int
+		for u := 0; u < 2*n; u++ {
+			for v := u + 1; v < 2*n; v++ {
+				d := dist(pos[u], pos[v])
+				if math.IsNaN(d) || math.IsInf(d, 0) {
+					t.Fatalf("theta=%v: invalid position: %v %v", theta, pos[u], pos[v])
+				}
+				if (u < n) == (v < n) {
+					within += d
+					nw++
+				} else {
+					between += d
+					nb++
+				}
+			}
+		}
+		within /= float64(nw)
+		between /= float64(nb)
+		if within >= between/2 {
+			t.Errorf("theta=%v: cliques not separated: mean within=%v mean between=%v", theta, within, between)
+		}
+	}
+}
+
+func TestForceDirectedStep(t *testing.T) {
+	var calls int
+	l := ForceDirected{
+		Iterations: 50,
+		Src:        rand.New(rand.NewSource(1)),
+		Step: func(iter int, pos map[int]Point) bool {
+			if iter != calls {
+				t.Errorf("unexpected iteration number: got:%d want:%d", iter, calls)
+			}
+			if len(pos) != 10 {
+				t.Errorf("unexpected number of positions: got:%d want:10", len(pos))
+			}
+			calls++
+			return calls < 10
+		},
+	}
+	l.Layout(barbell(5))
+	if calls != 10 {
+		t.Errorf("unexpected number of step calls: got:%d want:10", calls)
+	}
+}
+
+func TestForceDirectedDeterministic(t *testing.T) {
+	layout := func() map[int]Point {
+		l := ForceDirected{Theta: 0.8, Cooling: ExponentialCooling(0.95), Src: rand.New(rand.NewSource(1))}
+		return l.Layout(barbell(6))
+	}
+	a, b := layout(), layout()
+	for id, p := range a {
+		if b[id] != p {
+			t.Errorf("layout not deterministic for node %d: %v != %v", id, p, b[id])
+		}
+	}
+}
+
+func TestForceDirectedLayoutWithin(t *testing.T) {
+	g := barbell(6)
+	l := ForceDirected{Iterations: 200, Src: rand.New(rand.NewSource(1))}
+	want := l.Layout(g)
+	l.Src = rand.New(rand.NewSource(1))
+	got, final := l.LayoutWithin(g, 0)
+	for id, p := range want {
+		if got[id] != p {
+			t.Errorf("unexpected position for node %d with unlimited budget: got:%v want:%v", id, got[id], p)
+		}
+	}
+
+	var iters int
+	l = ForceDirected{
+		Iterations: 1e6,
+		Src:        rand.New(rand.NewSource(1)),
+		Step:       func(int, map[int]Point) bool { iters++; return true },
+	}
+	pos, residual := l.LayoutWithin(g, 20*time.Millisecond)
+	if len(pos) != 12 || iters == 0 || iters == 1e6 {
+		t.Errorf("unexpected budgeted layout: %d positions after %d iterations", len(pos), iters)
+	}
+	if math.IsNaN(residual) || residual < 0 {
+		t.Errorf("invalid residual: %v", residual)
+	}
+
+	l = ForceDirected{Iterations: 1, Src: rand.New(rand.NewSource(1))}
+	_, initial := l.LayoutWithin(g, 0)
+	if final >= initial {
+		t.Errorf("residual not reduced by layout: initial:%v final:%v", initial, final)
+	}
+}
+
+func TestBarnesHut(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	pos := make([]Point, 500)
+	for i := range pos {
+		pos[i] = Point{X: rnd.NormFloat64() * 10, Y: rnd.NormFloat64() * 10}
+	}
+	// Include coincident points.
+	pos[1] = pos[0]
+
+	exact := make([]Point, len(pos))
+	repulsion(exact, pos, 0)
+	for _, theta := range []float64{0.3, 0.7} {
+		approx := make([]Point, len(pos))
+		repulsion(approx, pos, theta)
+		var errSum, magSum float64
+		for i := range pos {
+			errSum += dist(exact[i], approx[i])
+			magSum += math.Hypot(exact[i].X, exact[i].Y)
+		}
+		if rel := errSum / magSum; rel > theta/5 {
+			t.Errorf("theta=%v: large Barnes-Hut error: relative error=%v", theta, rel)
+		}
+	}
+}
@@ -0,0 +1,596 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Layered is a layered drawing of a directed graph.
+type Layered struct {
+	// Layers holds the vertices of each
+	// layer in order of increasing X,
+	// including the dummy vertices that
+	// route edges spanning more than one
+	// layer.
+	Layers [][]Vertex
+
+	// Positions holds the positions of
+	// the graph nodes keyed by node ID.
+	Positions map[int]Point
+
+	// Routes holds the polyline route of
+	// each edge keyed by the IDs of its
+	// from and to nodes, starting at the
+	// from node, passing through the
+	// positions of the edge's dummy
+	// vertices and ending at the to node.
+	// Self loops are not routed.
+	Routes map[[2]int][]Point
+
+	// Crossings is the number of edge
+	// crossings in the drawing.
+	Crossings int
+}
+
+// Vertex is a vertex of a layered drawing.
+type Vertex struct {
+	// Node is the graph node of the
+	// vertex. Node is nil for dummy
+	// vertices.
+	Node graph.Node
+
+	// Edge holds the IDs of the from
+	// and to nodes of the edge routed
+	// through a dummy vertex.
+	Edge [2]int
+
+	// Pos is the position of the vertex.
+	Pos Point
+}
+
+// IsDummy returns whether v is a dummy vertex.
+func (v Vertex) IsDummy() bool { return v.Node == nil }
+
+// maxSweeps is the maximum number of crossing minimization sweeps.
+const maxSweeps = 24
+
+// Sugiyama returns a layered drawing of g using the framework of Sugiyama,
+// Tagawa and Toda, doi:10.1109/TSMC.1981.4308636. The drawing is constructed
+// in four phases:
+//
+// Cycles are removed by reversing the back edges of a depth-first search, so
+// g need not be acyclic, although the drawing is most readable when it is.
+//
+// Nodes are assigned to layers by longest path from the sources so that every
+// edge points to a later layer. Edges spanning more than one layer are split
+// by dummy vertices in each intermediate layer.
+//
+// Edge crossings are reduced by alternating downward and upward sweeps of the
+// barycenter heuristic, keeping the ordering with the fewest crossings.
+//
+// Each vertex is placed as close as possible to the mean X position of its
+// neighbors in the adjacent layers, preserving the order within each layer
+// and a minimum separation of one between vertices.
+//
+// Layer i is placed at Y = -i, so edges point down in a Y-up coordinate
+// system, and the leftmost vertex is placed at X = 0.
+func Sugiyama(g graph.Directed) Layered {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	var edges [][2]int
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if j := indexOf[v.ID()]; i != j {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	}
+
+	reversed := backEdges(len(nodes), edges)
+	oriented := make([][2]int, len(edges))
+	for k, e := range edges {
+		if reversed[k] {
+			e[0], e[1] = e[1], e[0]
+		}
+		oriented[k] = e
+	}
+
+	s := newSugiyama(nodes, oriented)
+	s.minimizeCrossings()
+	s.assignCoordinates()
+
+	l := Layered{
+		Layers:    make([][]Vertex, len(s.layers)),
+		Positions: make(map[int]Point, len(nodes)),
+		Routes:    make(map[[2]int][]Point, len(edges)),
+		Crossings: s.crossings(),
+	}
+	for i, layer := range s.layers {
+		l.Layers[i] = make([]Vertex, len(layer))
+		for j, v := range layer {
+			vert := Vertex{Pos: s.point(v)}
+			if v < len(nodes) {
+				vert.Node = nodes[v]
+			} else {
+				e := edges[s.verts[v].edge]
+				vert.Edge = [2]int{nodes[e[0]].ID(), nodes[e[1]].ID()}
+			}
+			l.Layers[i][j] = vert
+		}
+	}
+	for i, n := range nodes {
+		l.Positions[n.ID()] = s.point(i)
+	}
+	for k, e := range edges {
+		route := make([]Point, 0, len(s.chains[k])+2)
+		route = append(route, s.point(oriented[k][0]))
+		for _, d := range s.chains[k] {
+			route = append(route, s.point(d))
+		}
+		route = append(route, s.point(oriented[k][1]))
+		if reversed[k] {
+			for i, j := 0, len(route)-1; i < j; i, j = i+1, j-1 {
+				route[i], route[j] = route[j], route[i]
+			}
+		}
+		l.Routes[[2]int{nodes[e[0]].ID(), nodes[e[1]].ID()}] = route
+	}
+	return l
+}
+
+// backEdges returns which of the edges between n vertices are back edges of a
+// depth-first search. Reversing the back edges makes the graph acyclic.
+func backEdges(n int, edges [][2]int) []bool {
+	out := make([][]int, n)
+	for k, e := range edges {
+		out[e[0]] = append(out[e[0]], k)
+	}
+
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make([]int, n)
+	back := make([]bool, len(edges))
+	type frame struct {
+		v, next int
+	}
+	for root := range out {
+		if color[root] != white {
+			continue
+		}
+		color[root] = grey
+		stack := []frame{{v: root}}
+		for len(stack) != 0 {
+			f := &stack[len(stack)-1]
+			if f.next == len(out[f.v]) {
+				color[f.v] = black
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			k := out[f.v][f.next]
+			f.next++
+			switch w := edges[k][1]; color[w] {
+			case white:
+				color[w] = grey
+				stack = append(stack, frame{v: w})
+			case grey:
+				back[k] = true
+			}
+		}
+	}
+	return back
+}
+
+// sugiyama holds the state of a layered drawing.
+type sugiyama struct {
+	// verts holds the graph vertices
+	// followed by the dummy vertices.
+	verts []vertex
+
+	// chains holds the dummy vertices
+	// of each edge in layer order.
+	chains [][]int
+
+	// layers holds the vertices of
+	// each layer in order.
+	layers [][]int
+
+	// x holds the X coordinate of
+	// each vertex.
+	x []float64
+}
+
+type vertex struct {
+	layer int
+
+	// pos is the index of the
+	// vertex in its layer.
+	pos int
+
+	// edge is the index of the edge
+	// routed through a dummy vertex.
+	edge int
+
+	// up and down hold the neighbors
+	// in the previous and next layers.
+	up, down []int
+}
+
+// newSugiyama returns the layering of the acyclic graph with the given nodes
+// and edges between node indices.
+func newSugiyama(nodes []graph.Node, edges [][2]int) *sugiyama {
+	s := &sugiyama{
+		verts:  make([]vertex, len(nodes)),
+		chains: make([][]int, len(edges)),
+	}
+
+	// Assign layers by longest path in
+	// topological order.
+	out := make([][]int, len(nodes))
+	indeg := make([]int, len(nodes))
+	for _, e := range edges {
+		out[e[0]] = append(out[e[0]], e[1])
+		indeg[e[1]]++
+	}
+	var queue []int
+	for i, d := range indeg {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+	var height int
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		l := s.verts[u].layer
+		if l+1 > height {
+			height = l + 1
+		}
+		for _, v := range out[u] {
+			if s.verts[v].layer < l+1 {
+				s.verts[v].layer = l + 1
+			}
+			indeg[v]--
+			if indeg[v] == 0 {
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	// Split long edges with dummy vertices.
+	link := func(u, v int) {
+		s.verts[u].down = append(s.verts[u].down, v)
+		s.verts[v].up = append(s.verts[v].up, u)
+	}
+	for k, e := range edges {
+		prev := e[0]
+		for l := s.verts[e[0]].layer + 1; l < s.verts[e[1]].layer; l++ {
+			d := len(s.verts)
+			s.verts = append(s.verts, vertex{layer: l, edge: k})
+			s.chains[k] = append(s.chains[k], d)
+			link(prev, d)
+			prev = d
+		}
+		link(prev, e[1])
+	}
+
+	s.layers = make([][]int, height)
+	for v := range s.verts {
+		l := s.verts[v].layer
+		s.verts[v].pos = len(s.layers[l])
+		s.layers[l] = append(s.layers[l], v)
+	}
+	return s
+}
+
+// minimizeCrossings reorders the vertices within each layer to reduce the
+// number of edge crossings.
+func (s *sugiyama) minimizeCrossings() {
+	best := s.copyLayers()
+	min := s.crossings()
+	var stale int
+	for sweep := 0; sweep < maxSweeps && min != 0; sweep++ {
+		if sweep%2 == 0 {
+			for l := 1; l < len(s.layers); l++ {
+				s.order(s.layers[l], func(v int) []int { return s.verts[v].up })
+			}
+		} else {
+			for l := len(s.layers) - 2; l >= 0; l-- {
+				s.order(s.layers[l], func(v int) []int { return s.verts[v].down })
+			}
+		}
+		c := s.crossings()
+		if c < min {
+			best = s.copyLayers()
+			min = c
+			stale = 0
+			continue
+		}
+		stale++
+		if stale == 4 {
+			break
+		}
+	}
+	s.layers = best
+	for _, layer := range s.layers {
+		for i, v := range layer {
+			s.verts[v].pos = i
+		}
+	}
+}
+
+func (s *sugiyama) copyLayers() [][]int {
+	c := make([][]int, len(s.layers))
+	for i, l := range s.layers {
+		c[i] = append([]int(nil), l...)
+	}
+	return c
+}
+
+// order sorts layer by the barycenter of the positions of the neighbors of
+// each vertex returned by adj. Vertices with no neighbors keep their position.
+func (s *sugiyama) order(layer []int, adj func(int) []int) {
+	bary := make(map[int]float64, len(layer))
+	for _, v := range layer {
+		nbrs := adj(v)
+		if len(nbrs) == 0 {
+			bary[v] = float64(s.verts[v].pos)
+			continue
+		}
+		var sum float64
+		for _, u := range nbrs {
+			sum += float64(s.verts[u].pos)
+		}
+		bary[v] = sum / float64(len(nbrs))
+	}
+	sort.Stable(byValue{order: layer, val: bary})
+	for i, v := range layer {
+		s.verts[v].pos = i
+	}
+}
+
+// byValue sorts vertices by increasing value.
+type byValue struct {
+	order []int
+	val   map[int]float64
+}
+
+func (s byValue) Len() int           { return len(s.order) }
+func (s byValue) Less(i, j int) bool { return s.val[s.order[i]] < s.val[s.order[j]] }
+func (s byValue) Swap(i, j int)      { s.order[i], s.order[j] = s.order[j], s.order[i] }
+
+// crossings returns the number of edge crossings in the current ordering.
+func (s *sugiyama) crossings() int {
+	var n int
+	for l := 0; l+1 < len(s.layers); l++ {
+		var pairs [][2]int
+		for _, u := range s.layers[l] {
+			for _, v := range s.verts[u].down {
+				pairs = append(pairs, [2]int{s.verts[u].pos, s.verts[v].pos})
+			}
+		}
+		n += bilayerCrossings(pairs, len(s.layers[l+1]))
+	}
+	return n
+}
+
+// bilayerCrossings returns the number of crossings between the edges joining
+// positions in two layers, where the second layer has the given width, using
+// the accumulator tree of Barth, Jünger and Mutzel, doi:10.1007/3-540-36151-0_13.
+func bilayerCrossings(edges [][2]int, width int) int {
+	sort.Sort(byEnds(edges))
+	first := 1
+	for first < width {
+		first *= 2
+	}
+	tree := make([]int, 2*first-1)
+	first--
+	var n int
+	for _, e := range edges {
+		i := e[1] + first
+		tree[i]++
+		for i > 0 {
+			if i%2 == 1 {
+				n += tree[i+1]
+			}
+			i = (i - 1) / 2
+			tree[i]++
+		}
+	}
+	return n
+}
+
+// byEnds sorts edges by their from and then their to positions.
+type byEnds [][2]int
+
+func (e byEnds) Len() int { return len(e) }
+func (e byEnds) Less(i, j int) bool {
+	if e[i][0] == e[j][0] {
+		return e[i][1] < e[j][1]
+	}
+	return e[i][0] < e[j][0]
+}
+func (e byEnds) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// assignCoordinates assigns X coordinates to the vertices, placing each
+// vertex close to the mean position of its neighbors.
+func (s *sugiyama) assignCoordinates() {
+	s.x = make([]float64, len(s.verts))
+	for v := range s.verts {
+		s.x[v] = float64(s.verts[v].pos)
+	}
+	up := func(v int) []int { return s.verts[v].up }
+	down := func(v int) []int { return s.verts[v].down }
+	for pass := 0; pass < 8; pass++ {
+		for l := 1; l < len(s.layers); l++ {
+			s.place(s.layers[l], up)
+		}
+		for l := len(s.layers) - 2; l >= 0; l-- {
+			s.place(s.layers[l], down)
+		}
+	}
+
+	if len(s.x) == 0 {
+		return
+	}
+	min := s.x[0]
+	for _, x := range s.x {
+		if x < min {
+			min = x
+		}
+	}
+	for v := range s.x {
+		s.x[v] -= min
+	}
+}
+
+// place sets the X coordinates of the vertices in layer to minimize the
+// squared distances from the mean positions of the neighbors of each vertex
+// returned by adj, subject to the order of the layer and a minimum separation
+// of one. The optimal placement is found by isotonic regression using the
+// pool adjacent violators algorithm.
+func (s *sugiyama) place(layer []int, adj func(int) []int) {
+	type block struct {
+		sum float64
+		n   int
+	}
+	var blocks []block
+	for i, v := range layer {
+		want := s.x[v]
+		if nbrs := adj(v); len(nbrs) != 0 {
+			want = 0
+			for _, u := range nbrs {
+				want += s.x[u]
+			}
+			want /= float64(len(nbrs))
+		}
+		blocks = append(blocks, block{sum: want - float64(i), n: 1})
+		for len(blocks) > 1 {
+			a, b := blocks[len(blocks)-2], blocks[len(blocks)-1]
+			if a.sum/float64(a.n) <= b.sum/float64(b.n) {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{sum: a.sum + b.sum, n: a.n + b.n}
+		}
+	}
+	var i int
+	for _, b := range blocks {
+		mean := b.sum / float64(b.n)
+		for j := 0; j < b.n; j++ {
+			s.x[layer[i]] = mean + float64(i)
+			i++
+		}
+	}
+}
+
+func (s *sugiyama) point(v int) Point {
+	return Point{X: s.x[v], Y: -float64(s.verts[v].layer)}
+}
+
+// Build adds the nodes and edges of g to dst as Node and Edge values holding
+// the positions and routes of the drawing scaled by scale. The Edge values
+// hold the weights of the corresponding edges in g. The drawing must have
+// been constructed from g.
+func (l Layered) Build(dst graph.DirectedBuilder, g graph.Directed, scale float64) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	placed := make(map[int]Node, len(nodes))
+	for _, n := range nodes {
+		p := l.Positions[n.ID()]
+		placed[n.ID()] = Node{Node: n, Pos: Point{X: p.X * scale, Y: p.Y * scale}}
+		dst.AddNode(placed[n.ID()])
+	}
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			var route []Point
+			for _, p := range l.Routes[[2]int{u.ID(), v.ID()}] {
+				route = append(route, Point{X: p.X * scale, Y: p.Y * scale})
+			}
+			dst.SetEdge(Edge{
+				F:     placed[u.ID()],
+				T:     placed[v.ID()],
+				W:     g.Edge(u, v).Weight(),
+				Route: route,
+			})
+		}
+	}
+}
+
+// Node is a graph node with a position. Node implements dot.Attributer
+// so positioned nodes can be rendered by Graphviz using neato -n.
+type Node struct {
+	graph.Node
+	Pos Point
+}
+
+// DOTAttributes returns the pinned position of the node as a DOT attribute.
+func (n Node) DOTAttributes() []dot.Attribute {
+	return []dot.Attribute{{Key: "pos", Value: `"` + formatPoint(n.Pos) + `!"`}}
+}
+
+// Edge is a graph edge with a polyline route. Edge implements dot.Attributer
+// so routed edges can be rendered by Graphviz using neato -n.
+type Edge struct {
+	F, T  graph.Node
+	W     float64
+	Route []Point
+}
+
+// From returns the from node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// Weight returns the weight of the edge.
+func (e Edge) Weight() float64 { return e.W }
+
+// DOTAttributes returns the route of the edge as a DOT spline position
+// attribute, with each segment of the polyline represented by a straight
+// cubic Bézier curve. Edges without a route have no attributes.
+func (e Edge) DOTAttributes() []dot.Attribute {
+	if len(e.Route) < 2 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	buf.WriteString(formatPoint(e.Route[0]))
+	for i, q := range e.Route[1:] {
+		p := e.Route[i]
+		d := Point{X: (q.X - p.X) / 3, Y: (q.Y - p.Y) / 3}
+		for _, c := range []Point{
+			{X: p.X + d.X, Y: p.Y + d.Y},
+			{X: p.X + 2*d.X, Y: p.Y + 2*d.Y},
+			q,
+		} {
+			buf.WriteByte(' ')
+			buf.WriteString(formatPoint(c))
+		}
+	}
+	buf.WriteByte('"')
+	return []dot.Attribute{{Key: "pos", Value: buf.String()}}
+}
+
+func formatPoint(p Point) string {
+	return strconv.FormatFloat(p.X, 'g', -1, 64) + "," + strconv.FormatFloat(p.Y, 'g', -1, 64)
+}
@@ -0,0 +1,162 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/simple"
+)
+
+func directed(edges [][2]int) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	return g
+}
+
+var sugiyamaTests = []struct {
+	name      string
+	edges     [][2]int
+	layers    int
+	crossings int
+}{
+	{
+		name:   "chain",
+		edges:  [][2]int{{0, 1}, {1, 2}, {2, 3}},
+		layers: 4,
+	},
+	{
+		name:   "diamond",
+		edges:  [][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}},
+		layers: 3,
+	},
+	{
+		name:   "long edge",
+		edges:  [][2]int{{0, 1}, {1, 2}, {2, 3}, {0, 3}},
+		layers: 4,
+	},
+	{
+		// The crossing in the initial
+		// ordering can be removed.
+		name:   "crossed",
+		edges:  [][2]int{{0, 3}, {1, 2}},
+		layers: 2,
+	},
+	{
+		name:   "cycle",
+		edges:  [][2]int{{0, 1}, {1, 2}, {2, 0}},
+		layers: 3,
+	},
+	{
+		name:      "K3,3",
+		edges:     [][2]int{{0, 3}, {0, 4}, {0, 5}, {1, 3}, {1, 4}, {1, 5}, {2, 3}, {2, 4}, {2, 5}},
+		layers:    2,
+		crossings: 9,
+	},
+}
+
+func TestSugiyama(t *testing.T) {
+	for _, test := range sugiyamaTests {
+		g := directed(test.edges)
+		l := Sugiyama(g)
+		if len(l.Layers) != test.layers {
+			t.Errorf("unexpected number of layers for %s: got:%d want:%d", test.name, len(l.Layers), test.layers)
+		}
+		if l.Crossings != test.crossings {
+			t.Errorf("unexpected number of crossings for %s: got:%d want:%d", test.name, l.Crossings, test.crossings)
+		}
+		if len(l.Positions) != len(g.Nodes()) {
+			t.Errorf("unexpected number of positions for %s: got:%d want:%d", test.name, len(l.Positions), len(g.Nodes()))
+		}
+
+		for i, layer := range l.Layers {
+			for j, v := range layer {
+				if v.Pos.Y != -float64(i) {
+					t.Errorf("unexpected Y for vertex in layer %d for %s: got:%v", i, test.name, v.Pos.Y)
+				}
+				if j != 0 && v.Pos.X-layer[j-1].Pos.X < 1-1e-9 {
+					t.Errorf("vertices too close in layer %d for %s: %v %v", i, test.name, layer[j-1].Pos, v.Pos)
+				}
+				if !v.IsDummy() && l.Positions[v.Node.ID()] != v.Pos {
+					t.Errorf("inconsistent position for node %d for %s", v.Node.ID(), test.name)
+				}
+			}
+		}
+
+		var backward int
+		for _, e := range test.edges {
+			route, ok := l.Routes[e]
+			if !ok {
+				t.Errorf("missing route for edge %v for %s", e, test.name)
+				continue
+			}
+			if route[0] != l.Positions[e[0]] || route[len(route)-1] != l.Positions[e[1]] {
+				t.Errorf("route for edge %v does not join its nodes for %s", e, test.name)
+			}
+			dy := route[len(route)-1].Y - route[0].Y
+			if dy > 0 {
+				backward++
+			}
+			for i := 1; i < len(route); i++ {
+				if step := route[i].Y - route[i-1].Y; math.Abs(step) != 1 || step*dy < 0 {
+					t.Errorf("route for edge %v does not pass through adjacent layers for %s: %v", e, test.name, route)
+					break
+				}
+			}
+		}
+		if backward != 0 && test.name != "cycle" {
+			t.Errorf("unexpected backward edges for %s: %d", test.name, backward)
+		}
+	}
+}
+
+func TestSugiyamaStraightChain(t *testing.T) {
+	l := Sugiyama(directed([][2]int{{0, 1}, {1, 2}, {2, 3}}))
+	for id, p := range l.Positions {
+		if p.X != 0 {
+			t.Errorf("unexpected X for node %d in chain: got:%v want:0", id, p.X)
+		}
+	}
+}
+
+func TestSugiyamaDOT(t *testing.T) {
+	g := directed([][2]int{{0, 1}, {0, 2}, {1, 2}})
+	l := Sugiyama(g)
+	dst := simple.NewDirectedGraph(0, math.Inf(1))
+	l.Build(dst, g, 72)
+	b, err := dot.Marshal(dst, "", "", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		`0 [pos="`,
+		`2 [pos="`,
+		`0 -> 2 [pos="`,
+		`!"]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in DOT output:\n%s", want, got)
+		}
+	}
+	// The edge from 0 to 2 spans two layers and
+	// so has two cubic segments.
+	for _, e := range dst.Edges() {
+		a := e.(Edge).DOTAttributes()
+		if len(a) != 1 {
+			t.Fatalf("unexpected number of edge attributes: %d", len(a))
+		}
+		points := len(strings.Fields(strings.Trim(a[0].Value, `"`)))
+		want := 3*(len(e.(Edge).Route)-1) + 1
+		if points != want {
+			t.Errorf("unexpected number of spline points for %d->%d: got:%d want:%d", e.From().ID(), e.To().ID(), points, want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package locate provides facility location heuristics on graphs.
+//
+// Distances are shortest path distances from the facility nodes, the
+// centers, to the nodes they serve. If a graph does not implement
+// graph.Weighter, path.UniformCost is used.
+package locate
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+)
+
+// Solution is a placement of centers in a graph.
+type Solution struct {
+	// Centers holds the chosen
+	// centers ordered by ID.
+	Centers []graph.Node
+
+	// Assignment holds the nearest
+	// center to each node, keyed on
+	// the node ID. Nodes that cannot
+	// be reached from any center are
+	// not included.
+	Assignment map[int]graph.Node
+
+	// Cost is the cost of the solution
+	// under the objective used to find
+	// it. It is infinite if any node
+	// cannot be reached from a center.
+	Cost float64
+}
+
+// KCenter returns k centers for g chosen by the greedy algorithm of Gonzalez,
+// doi:10.1016/0304-3975(85)90224-5, each center after the first being the node
+// farthest from the centers already chosen. The Cost of the returned Solution
+// is the greatest distance from a node to its nearest center, which for an
+// undirected graph is at most twice the least possible. If g has no more than
+// k nodes, every node is a center. If src is not nil it is used as the random
+// source for choosing the first center, otherwise rand.Intn is used. KCenter
+// will panic if k is less than one.
+func KCenter(g graph.Graph, k int, src *rand.Rand) Solution {
+	if k < 1 {
+		panic("locate: k must be positive")
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	if len(nodes) == 0 {
+		return Solution{Assignment: make(map[int]graph.Node)}
+	}
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = src.Intn
+	}
+
+	centers := []graph.Node{nodes[rnd(len(nodes))]}
+	v := path.VoronoiFrom(g, centers)
+	for len(centers) < k && len(centers) < len(nodes) {
+		far, d := farthest(v, nodes)
+		if d == 0 {
+			// Every node is a center.
+			break
+		}
+		centers = append(centers, far)
+		v = path.VoronoiFrom(g, centers)
+	}
+	return solution(v, nodes, centers, maxCost)
+}
+
+// KMedian returns k centers for g chosen by single swap local search starting
+// from the centers found by KCenter, as described by Arya et al.,
+// doi:10.1137/S0097539702416402. The Cost of the returned Solution is the sum
+// of the distances from the nodes to their nearest centers, which for an
+// undirected graph is at most five times the least possible. Each round of
+// the search evaluates every swap of a center for a non-center, so a round
+// takes O(k.|V|.|E|.log|V|) time. If g has no more than k nodes, every node is
+// a center. If src is not nil it is used as the random source for choosing the
+// initial centers, otherwise rand.Intn is used. KMedian will panic if k is less
+// than one.
+func KMedian(g graph.Graph, k int, src *rand.Rand) Solution {
+	best := KCenter(g, k, src)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	centers := best.Centers
+	best = solution(path.VoronoiFrom(g, centers), nodes, centers, sumCost)
+
+	for improved := true; improved; {
+		improved = false
+		isCenter := make(map[int]bool, len(centers))
+		for _, c := range centers {
+			isCenter[c.ID()] = true
+		}
+	search:
+		for i := range centers {
+			for _, n := range nodes {
+				if isCenter[n.ID()] {
+					continue
+				}
+				swapped := make([]graph.Node, len(centers))
+				copy(swapped, centers)
+				swapped[i] = n
+				s := solution(path.VoronoiFrom(g, swapped), nodes, swapped, sumCost)
+				if s.Cost < best.Cost {
+					best, centers = s, swapped
+					improved = true
+					break search
+				}
+			}
+		}
+	}
+	return best
+}
+
+// farthest returns the node farthest from its nearest seed in v and its
+// distance. Ties are broken by lowest ID.
+func farthest(v path.Voronoi, nodes []graph.Node) (graph.Node, float64) {
+	var (
+		far  graph.Node
+		dist = -1.0
+	)
+	for _, n := range nodes {
+		if d := v.WeightTo(n); d > dist {
+			far, dist = n, d
+		}
+	}
+	return far, dist
+}
+
+// maxCost and sumCost are solution objectives.
+func maxCost(c, d float64) float64 { return math.Max(c, d) }
+func sumCost(c, d float64) float64 { return c + d }
+
+// solution returns the Solution described by the Voronoi partition v of nodes
+// from centers, costed using the given objective.
+func solution(v path.Voronoi, nodes, centers []graph.Node, cost func(c, d float64) float64) Solution {
+	s := Solution{
+		Centers:    make([]graph.Node, len(centers)),
+		Assignment: make(map[int]graph.Node, len(nodes)),
+	}
+	copy(s.Centers, centers)
+	sort.Sort(ordered.ByID(s.Centers))
+	for _, n := range nodes {
+		s.Cost = cost(s.Cost, v.WeightTo(n))
+		if c := v.Seed(n); c != nil {
+			s.Assignment[n.ID()] = c
+		}
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package locate
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+func randomGraph(n, m int, rnd *rand.Rand) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	// A spanning path keeps the graph connected.
+	for i := 1; i < n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i - 1), T: simple.Node(i), W: 1 + 9*rnd.Float64()})
+	}
+	for i := 0; i < m; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u != v {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1 + 9*rnd.Float64()})
+		}
+	}
+	return g
+}
+
+// optimal returns the least cost of any choice of k centers in g.
+func optimal(g graph.Graph, k int, cost func(c, d float64) float64) float64 {
+	nodes := g.Nodes()
+	best := math.Inf(1)
+	var choose func(start int, centers []graph.Node)
+	choose = func(start int, centers []graph.Node) {
+		if len(centers) == k {
+			if s := solution(path.VoronoiFrom(g, centers), nodes, centers, cost); s.Cost < best {
+				best = s.Cost
+			}
+			return
+		}
+		for i := start; i < len(nodes); i++ {
+			choose(i+1, append(centers, nodes[i]))
+		}
+	}
+	choose(0, nil)
+	return best
+}
+
+func TestKCenter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		g := randomGraph(14, 10, rnd)
+		for k := 1; k <= 3; k++ {
+			s := KCenter(g, k, rnd)
+			if len(s.Centers) != k {
+				t.Errorf("unexpected number of centers: got:%d want:%d", len(s.Centers), k)
+			}
+			checkAssignment(t, g, s)
+			if opt := optimal(g, k, maxCost); s.Cost > 2*opt+1e-9 {
+				t.Errorf("unexpected k-center cost for k=%d: got:%v want<=%v", k, s.Cost, 2*opt)
+			}
+		}
+	}
+
+	g := randomGraph(5, 0, rnd)
+	if s := KCenter(g, 10, rnd); len(s.Centers) != 5 || s.Cost != 0 {
+		t.Errorf("unexpected solution for k greater than order: got:%d centers cost %v", len(s.Centers), s.Cost)
+	}
+}
+
+func TestKMedian(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		g := randomGraph(14, 10, rnd)
+		for k := 1; k <= 3; k++ {
+			s := KMedian(g, k, rnd)
+			if len(s.Centers) != k {
+				t.Errorf("unexpected number of centers: got:%d want:%d", len(s.Centers), k)
+			}
+			checkAssignment(t, g, s)
+			opt := optimal(g, k, sumCost)
+			if s.Cost > 5*opt+1e-9 {
+				t.Errorf("unexpected k-median cost for k=%d: got:%v want<=%v", k, s.Cost, 5*opt)
+			}
+			if k == 1 && math.Abs(s.Cost-opt) > 1e-9 {
+				t.Errorf("unexpected 1-median cost: got:%v want:%v", s.Cost, opt)
+			}
+		}
+	}
+}
+
+func checkAssignment(t *testing.T, g graph.Graph, s Solution) {
+	v := path.VoronoiFrom(g, s.Centers)
+	for _, n := range g.Nodes() {
+		c, ok := s.Assignment[n.ID()]
+		if !ok {
+			t.Errorf("node %d not assigned", n.ID())
+			continue
+		}
+		if c.ID() != v.Seed(n).ID() {
+			t.Errorf("unexpected assignment for node %d: got:%d want:%d", n.ID(), c.ID(), v.Seed(n).ID())
+		}
+	}
+}
@@ -0,0 +1,562 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mine provides frequent subgraph mining functions.
+package mine
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// Labeling holds functions returning the labels of nodes and edges. A nil
+// function labels all nodes or edges with zero.
+type Labeling struct {
+	Node func(graph.Node) int
+	Edge func(graph.Edge) int
+}
+
+func (l Labeling) node(n graph.Node) int {
+	if l.Node == nil {
+		return 0
+	}
+	return l.Node(n)
+}
+
+func (l Labeling) edge(e graph.Edge) int {
+	if l.Edge == nil {
+		return 0
+	}
+	return l.Edge(e)
+}
+
+// DFSEdge is an edge in a DFS code. From and To are the discovery indices
+// of the edge's terminal nodes in the depth-first traversal that generated
+// the code. An edge with From less than To is a forward edge.
+type DFSEdge struct {
+	From, To int
+
+	FromLabel, EdgeLabel, ToLabel int
+}
+
+// Pattern is a frequent subgraph found by GSpan.
+type Pattern struct {
+	// Code is the minimum DFS code of
+	// the pattern.
+	Code []DFSEdge
+
+	// Support is the number of graphs
+	// containing the pattern and Graphs
+	// holds the indices of those graphs
+	// in the mined collection.
+	Support int
+	Graphs  []int
+}
+
+// Nodes returns the number of nodes in the pattern.
+func (p Pattern) Nodes() int {
+	var n int
+	for _, e := range p.Code {
+		if e.From >= n {
+			n = e.From + 1
+		}
+		if e.To >= n {
+			n = e.To + 1
+		}
+	}
+	return n
+}
+
+// Graph adds the pattern's nodes and edges to dst. Node IDs are the DFS
+// discovery indices of the nodes. Labels are not retained.
+func (p Pattern) Graph(dst graph.UndirectedBuilder) {
+	for _, e := range p.Code {
+		for _, id := range []int{e.From, e.To} {
+			if !dst.Has(simple.Node(id)) {
+				dst.AddNode(simple.Node(id))
+			}
+		}
+		dst.SetEdge(simple.Edge{F: simple.Node(e.From), T: simple.Node(e.To), W: 1})
+	}
+}
+
+// GSpan mines the undirected graphs for connected subgraphs present in at
+// least minSupport of the graphs, calling fn with each pattern found. If
+// maxEdges is positive, only patterns with no more than maxEdges edges are
+// mined. Mining stops if fn returns false. Node and edge labels are
+// provided by labels.
+//
+// GSpan implements the algorithm described in Yan and Han "gSpan:
+// Graph-Based Substructure Pattern Mining" ICDM 2002. Each pattern is
+// reported exactly once and patterns are reported in DFS code order, so
+// every pattern is preceded by its minimum DFS code prefixes.
+func GSpan(graphs []graph.Undirected, minSupport, maxEdges int, labels Labeling, fn func(Pattern) bool) {
+	m := miner{
+		minSupport: minSupport,
+		maxEdges:   maxEdges,
+		fn:         fn,
+	}
+	for i, g := range graphs {
+		m.graphs = append(m.graphs, newLabeledGraph(g, labels, i))
+	}
+
+	root := make(map[DFSEdge]projected)
+	for _, g := range m.graphs {
+		for _, v := range g.vertices {
+			for k := range v.edges {
+				e := &v.edges[k]
+				if g.vertices[e.from].label <= g.vertices[e.to].label {
+					key := DFSEdge{
+						From: 0, To: 1,
+						FromLabel: g.vertices[e.from].label,
+						EdgeLabel: e.label,
+						ToLabel:   g.vertices[e.to].label,
+					}
+					root[key] = append(root[key], &embedding{graph: g, edge: e})
+				}
+			}
+		}
+	}
+	keys := make([]DFSEdge, 0, len(root))
+	for k := range root {
+		keys = append(keys, k)
+	}
+	sort.Sort(byLabels(keys))
+	for _, k := range keys {
+		m.code = append(m.code[:0], k)
+		if !m.mine(root[k]) {
+			return
+		}
+	}
+}
+
+// labeledGraph is the internal representation of a labeled graph.
+type labeledGraph struct {
+	index    int
+	vertices []vertex
+	edges    int
+}
+
+type vertex struct {
+	label int
+	edges []labeledEdge
+}
+
+type labeledEdge struct {
+	from, to int
+	label    int
+	id       int
+}
+
+func newLabeledGraph(g graph.Undirected, labels Labeling, index int) *labeledGraph {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	lg := &labeledGraph{index: index, vertices: make([]vertex, len(nodes))}
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+		lg.vertices[i].label = labels.node(n)
+	}
+	ids := make(map[[2]int]int)
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			key := [2]int{i, j}
+			if j < i {
+				key = [2]int{j, i}
+			}
+			id, ok := ids[key]
+			if !ok {
+				id = lg.edges
+				ids[key] = id
+				lg.edges++
+			}
+			lg.vertices[i].edges = append(lg.vertices[i].edges, labeledEdge{
+				from:  i,
+				to:    j,
+				label: labels.edge(g.EdgeBetween(u, v)),
+				id:    id,
+			})
+		}
+	}
+	return lg
+}
+
+// embedding is a link in the chain of graph edges that embed a DFS code
+// into a graph.
+type embedding struct {
+	graph *labeledGraph
+	edge  *labeledEdge
+	prev  *embedding
+}
+
+// projected is the set of embeddings of a DFS code.
+type projected []*embedding
+
+// history is the expanded form of an embedding.
+type history struct {
+	edges     []*labeledEdge
+	hasEdge   map[int]bool
+	hasVertex map[int]bool
+}
+
+func newHistory(p *embedding) history {
+	h := history{hasEdge: make(map[int]bool), hasVertex: make(map[int]bool)}
+	for ; p != nil; p = p.prev {
+		h.edges = append(h.edges, p.edge)
+		h.hasEdge[p.edge.id] = true
+		h.hasVertex[p.edge.from] = true
+		h.hasVertex[p.edge.to] = true
+	}
+	for i, j := 0, len(h.edges)-1; i < j; i, j = i+1, j-1 {
+		h.edges[i], h.edges[j] = h.edges[j], h.edges[i]
+	}
+	return h
+}
+
+// support returns the number of distinct graphs in p and their indices.
+func (p projected) support() (int, []int) {
+	seen := make(map[int]bool)
+	var graphs []int
+	for _, e := range p {
+		if !seen[e.graph.index] {
+			seen[e.graph.index] = true
+			graphs = append(graphs, e.graph.index)
+		}
+	}
+	sort.Ints(graphs)
+	return len(graphs), graphs
+}
+
+// miner holds the state of a gSpan search.
+type miner struct {
+	graphs []*labeledGraph
+
+	minSupport int
+	maxEdges   int
+	fn         func(Pattern) bool
+
+	code []DFSEdge
+}
+
+// mine recursively extends the current DFS code, returning false if the
+// search has been halted.
+func (m *miner) mine(p projected) bool {
+	support, graphs := p.support()
+	if support < m.minSupport {
+		return true
+	}
+	if !isMin(m.code) {
+		return true
+	}
+	if !m.fn(Pattern{Code: append([]DFSEdge(nil), m.code...), Support: support, Graphs: graphs}) {
+		return false
+	}
+	if m.maxEdges > 0 && len(m.code) >= m.maxEdges {
+		return true
+	}
+
+	rmpath := rightmostPath(m.code)
+	maxToc := m.code[rmpath[0]].To
+	minLabel := m.code[0].FromLabel
+	labelOf := vertexLabels(m.code)
+
+	backward := make(map[DFSEdge]projected)
+	forward := make(map[DFSEdge]projected)
+	for _, e := range p {
+		g := e.graph
+		h := newHistory(e)
+		for i := len(rmpath) - 1; i >= 0; i-- {
+			b := backwardEdge(g, h.edges[rmpath[i]], h.edges[rmpath[0]], h)
+			if b != nil {
+				to := m.code[rmpath[i]].From
+				key := DFSEdge{From: maxToc, To: to, FromLabel: labelOf[maxToc], EdgeLabel: b.label, ToLabel: labelOf[to]}
+				backward[key] = append(backward[key], &embedding{graph: g, edge: b, prev: e})
+			}
+		}
+		for _, f := range forwardPureEdges(g, h.edges[rmpath[0]], minLabel, h) {
+			key := DFSEdge{From: maxToc, To: maxToc + 1, FromLabel: labelOf[maxToc], EdgeLabel: f.label, ToLabel: g.vertices[f.to].label}
+			forward[key] = append(forward[key], &embedding{graph: g, edge: f, prev: e})
+		}
+		for _, i := range rmpath {
+			for _, f := range forwardRightmostEdges(g, h.edges[i], minLabel, h) {
+				from := m.code[i].From
+				key := DFSEdge{From: from, To: maxToc + 1, FromLabel: labelOf[from], EdgeLabel: f.label, ToLabel: g.vertices[f.to].label}
+				forward[key] = append(forward[key], &embedding{graph: g, edge: f, prev: e})
+			}
+		}
+	}
+
+	for _, k := range sortedKeys(backward) {
+		m.code = append(m.code, k)
+		if !m.mine(backward[k]) {
+			return false
+		}
+		m.code = m.code[:len(m.code)-1]
+	}
+	for _, k := range sortedKeys(forward) {
+		m.code = append(m.code, k)
+		if !m.mine(forward[k]) {
+			return false
+		}
+		m.code = m.code[:len(m.code)-1]
+	}
+	return true
+}
+
+// rightmostPath returns the indices of the forward edges of code on its
+// rightmost path, starting from the edge discovering the rightmost vertex.
+func rightmostPath(code []DFSEdge) []int {
+	var rmpath []int
+	oldFrom := -1
+	for i := len(code) - 1; i >= 0; i-- {
+		e := code[i]
+		if e.From < e.To && (len(rmpath) == 0 || oldFrom == e.To) {
+			rmpath = append(rmpath, i)
+			oldFrom = e.From
+		}
+	}
+	return rmpath
+}
+
+// vertexLabels returns the labels of the vertices of code indexed by DFS
+// discovery index.
+func vertexLabels(code []DFSEdge) []int {
+	labels := []int{code[0].FromLabel}
+	for _, e := range code {
+		if e.From < e.To {
+			labels = append(labels, e.ToLabel)
+		}
+	}
+	return labels
+}
+
+// backwardEdge returns an edge from the rightmost vertex, the to vertex of
+// e2, back to the from vertex of e1 that is valid in a minimum DFS code, or
+// nil if no such edge exists.
+func backwardEdge(g *labeledGraph, e1, e2 *labeledEdge, h history) *labeledEdge {
+	if e1 == e2 {
+		return nil
+	}
+	for k := range g.vertices[e2.to].edges {
+		e := &g.vertices[e2.to].edges[k]
+		if h.hasEdge[e.id] || e.to != e1.from {
+			continue
+		}
+		if e1.label < e.label || (e1.label == e.label && g.vertices[e1.to].label <= g.vertices[e2.to].label) {
+			return e
+		}
+	}
+	return nil
+}
+
+// forwardPureEdges returns the edges from the rightmost vertex to vertices
+// not yet in the embedding.
+func forwardPureEdges(g *labeledGraph, rm *labeledEdge, minLabel int, h history) []*labeledEdge {
+	var edges []*labeledEdge
+	for k := range g.vertices[rm.to].edges {
+		e := &g.vertices[rm.to].edges[k]
+		if minLabel <= g.vertices[e.to].label && !h.hasVertex[e.to] {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// forwardRightmostEdges returns the edges from the from vertex of the
+// rightmost path edge rm to vertices not yet in the embedding that are
+// valid in a minimum DFS code.
+func forwardRightmostEdges(g *labeledGraph, rm *labeledEdge, minLabel int, h history) []*labeledEdge {
+	var edges []*labeledEdge
+	toLabel := g.vertices[rm.to].label
+	for k := range g.vertices[rm.from].edges {
+		e := &g.vertices[rm.from].edges[k]
+		newLabel := g.vertices[e.to].label
+		if rm.to == e.to || minLabel > newLabel || h.hasVertex[e.to] {
+			continue
+		}
+		if rm.label < e.label || (rm.label == e.label && toLabel <= newLabel) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// sortedKeys returns the keys of the extension map in DFS code order.
+func sortedKeys(ext map[DFSEdge]projected) []DFSEdge {
+	keys := make([]DFSEdge, 0, len(ext))
+	for k := range ext {
+		keys = append(keys, k)
+	}
+	sort.Sort(byExtension(keys))
+	return keys
+}
+
+// byExtension sorts backward extensions by increasing to index and
+// edge label and forward extensions by decreasing from index and
+// increasing edge and to labels. Backward extensions precede forward
+// extensions.
+type byExtension []DFSEdge
+
+func (e byExtension) Len() int { return len(e) }
+func (e byExtension) Less(i, j int) bool {
+	a, b := e[i], e[j]
+	aBack, bBack := a.From > a.To, b.From > b.To
+	if aBack != bBack {
+		return aBack
+	}
+	if aBack {
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.EdgeLabel < b.EdgeLabel
+	}
+	if a.From != b.From {
+		return a.From > b.From
+	}
+	if a.EdgeLabel != b.EdgeLabel {
+		return a.EdgeLabel < b.EdgeLabel
+	}
+	return a.ToLabel < b.ToLabel
+}
+func (e byExtension) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// byLabels sorts single edge DFS codes by their labels.
+type byLabels []DFSEdge
+
+func (e byLabels) Len() int { return len(e) }
+func (e byLabels) Less(i, j int) bool {
+	a, b := e[i], e[j]
+	if a.FromLabel != b.FromLabel {
+		return a.FromLabel < b.FromLabel
+	}
+	if a.EdgeLabel != b.EdgeLabel {
+		return a.EdgeLabel < b.EdgeLabel
+	}
+	return a.ToLabel < b.ToLabel
+}
+func (e byLabels) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// isMin returns whether code is the minimum DFS code of the graph it
+// describes.
+func isMin(code []DFSEdge) bool {
+	if len(code) == 1 {
+		return true
+	}
+
+	labels := vertexLabels(code)
+	g := &labeledGraph{vertices: make([]vertex, len(labels))}
+	for i, l := range labels {
+		g.vertices[i].label = l
+	}
+	for id, e := range code {
+		g.vertices[e.From].edges = append(g.vertices[e.From].edges, labeledEdge{from: e.From, to: e.To, label: e.EdgeLabel, id: id})
+		g.vertices[e.To].edges = append(g.vertices[e.To].edges, labeledEdge{from: e.To, to: e.From, label: e.EdgeLabel, id: id})
+	}
+
+	root := make(map[DFSEdge]projected)
+	for _, v := range g.vertices {
+		for k := range v.edges {
+			e := &v.edges[k]
+			if g.vertices[e.from].label <= g.vertices[e.to].label {
+				key := DFSEdge{From: 0, To: 1, FromLabel: g.vertices[e.from].label, EdgeLabel: e.label, ToLabel: g.vertices[e.to].label}
+				root[key] = append(root[key], &embedding{graph: g, edge: e})
+			}
+		}
+	}
+	keys := make([]DFSEdge, 0, len(root))
+	for k := range root {
+		keys = append(keys, k)
+	}
+	sort.Sort(byLabels(keys))
+	if keys[0] != code[0] {
+		return false
+	}
+
+	min := []DFSEdge{keys[0]}
+	p := root[keys[0]]
+	for len(min) < len(code) {
+		rmpath := rightmostPath(min)
+		maxToc := min[rmpath[0]].To
+		minLabel := min[0].FromLabel
+		labelOf := vertexLabels(min)
+
+		// Find the smallest backward extension.
+		backward := make(map[int]projected)
+		newTo := -1
+		for i := len(rmpath) - 1; i > 0 && newTo < 0; i-- {
+			for _, e := range p {
+				h := newHistory(e)
+				b := backwardEdge(g, h.edges[rmpath[i]], h.edges[rmpath[0]], h)
+				if b != nil {
+					backward[b.label] = append(backward[b.label], &embedding{graph: g, edge: b, prev: e})
+					newTo = min[rmpath[i]].From
+				}
+			}
+		}
+		if newTo >= 0 {
+			minEdge := -1
+			for l := range backward {
+				if minEdge < 0 || l < minEdge {
+					minEdge = l
+				}
+			}
+			next := DFSEdge{From: maxToc, To: newTo, FromLabel: labelOf[maxToc], EdgeLabel: minEdge, ToLabel: labelOf[newTo]}
+			if next != code[len(min)] {
+				return false
+			}
+			min = append(min, next)
+			p = backward[minEdge]
+			continue
+		}
+
+		// Otherwise find the smallest forward extension.
+		forward := make(map[[2]int]projected)
+		newFrom := -1
+		for _, e := range p {
+			h := newHistory(e)
+			for _, f := range forwardPureEdges(g, h.edges[rmpath[0]], minLabel, h) {
+				newFrom = maxToc
+				key := [2]int{f.label, g.vertices[f.to].label}
+				forward[key] = append(forward[key], &embedding{graph: g, edge: f, prev: e})
+			}
+		}
+		for _, i := range rmpath {
+			if newFrom >= 0 {
+				break
+			}
+			for _, e := range p {
+				h := newHistory(e)
+				for _, f := range forwardRightmostEdges(g, h.edges[i], minLabel, h) {
+					newFrom = min[i].From
+					key := [2]int{f.label, g.vertices[f.to].label}
+					forward[key] = append(forward[key], &embedding{graph: g, edge: f, prev: e})
+				}
+			}
+		}
+		if newFrom < 0 {
+			return true
+		}
+		var minKey [2]int
+		first := true
+		for k := range forward {
+			if first || k[0] < minKey[0] || (k[0] == minKey[0] && k[1] < minKey[1]) {
+				minKey = k
+				first = false
+			}
+		}
+		next := DFSEdge{From: newFrom, To: maxToc + 1, FromLabel: labelOf[newFrom], EdgeLabel: minKey[0], ToLabel: minKey[1]}
+		if next != code[len(min)] {
+			return false
+		}
+		min = append(min, next)
+		p = forward[minKey]
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// labeledEdges is a pattern represented as a list of labeled edges.
+type labeledEdges [][3]int
+
+// canonical returns a canonical string for a small labeled graph by
+// minimising over all vertex permutations.
+func canonical(vlabels map[int]int, edges labeledEdges) string {
+	var ids []int
+	for id := range vlabels {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	best := ""
+	permute(ids, 0, func(p []int) {
+		pos := make(map[int]int)
+		var vl []int
+		for i, id := range p {
+			pos[id] = i
+			vl = append(vl, vlabels[id])
+		}
+		var es [][3]int
+		for _, e := range edges {
+			u, v := pos[e[0]], pos[e[1]]
+			if u > v {
+				u, v = v, u
+			}
+			es = append(es, [3]int{u, v, e[2]})
+		}
+		sort.Sort(byTriple(es))
+		s := fmt.Sprint(vl, es)
+		if best == "" || s < best {
+			best = s
+		}
+	})
+	return best
+}
+
+type byTriple [][3]int
+
+func (t byTriple) Len() int { return len(t) }
+func (t byTriple) Less(i, j int) bool {
+	for k := range t[i] {
+		if t[i][k] != t[j][k] {
+			return t[i][k] < t[j][k]
+		}
+	}
+	return false
+}
+func (t byTriple) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+
+func permute(a []int, k int, fn func([]int)) {
+	if k == len(a) {
+		fn(a)
+		return
+	}
+	for i := k; i < len(a); i++ {
+		a[k], a[i] = a[i], a[k]
+		permute(a, k+1, fn)
+		a[k], a[i] = a[i], a[k]
+	}
+}
+
+// bruteForce returns the support of all connected subgraphs with up to
+// maxEdges edges keyed by canonical string.
+func bruteForce(graphs []*simple.UndirectedGraph, label Labeling, maxEdges int) map[string]int {
+	support := make(map[string]int)
+	for _, g := range graphs {
+		var all []graph.Edge
+		for _, e := range g.Edges() {
+			all = append(all, e)
+		}
+		seen := make(map[string]bool)
+		var subsets func(start int, chosen []graph.Edge)
+		subsets = func(start int, chosen []graph.Edge) {
+			if len(chosen) > 0 && connected(chosen) {
+				vl := make(map[int]int)
+				var es labeledEdges
+				for _, e := range chosen {
+					vl[e.From().ID()] = label.node(e.From())
+					vl[e.To().ID()] = label.node(e.To())
+					es = append(es, [3]int{e.From().ID(), e.To().ID(), label.edge(e)})
+				}
+				seen[canonical(vl, es)] = true
+			}
+			if len(chosen) == maxEdges {
+				return
+			}
+			for i := start; i < len(all); i++ {
+				subsets(i+1, append(chosen, all[i]))
+			}
+		}
+		subsets(0, nil)
+		for k := range seen {
+			support[k]++
+		}
+	}
+	return support
+}
+
+func connected(edges []graph.Edge) bool {
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(i int) int {
+		if p, ok := parent[i]; ok && p != i {
+			parent[i] = find(p)
+			return parent[i]
+		}
+		parent[i] = i
+		return i
+	}
+	for _, e := range edges {
+		parent[find(e.From().ID())] = find(e.To().ID())
+	}
+	root := find(edges[0].From().ID())
+	for _, e := range edges {
+		if find(e.From().ID()) != root || find(e.To().ID()) != root {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGSpan(t *testing.T) {
+	const (
+		maxEdges   = 4
+		minSupport = 2
+	)
+	rnd := rand.New(rand.NewSource(1))
+	nodeLabels := make(map[int]int)
+	var graphs []*simple.UndirectedGraph
+	var gs []graph.Undirected
+	for i := 0; i < 5; i++ {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for j := 0; j < 6; j++ {
+			id := i*10 + j
+			g.AddNode(simple.Node(id))
+			nodeLabels[id] = rnd.Intn(2)
+		}
+		for j := 0; j < 6; j++ {
+			for k := j + 1; k < 6; k++ {
+				if rnd.Float64() < 0.4 {
+					g.SetEdge(simple.Edge{F: simple.Node(i*10 + j), T: simple.Node(i*10 + k), W: float64(rnd.Intn(2))})
+				}
+			}
+		}
+		graphs = append(graphs, g)
+		gs = append(gs, g)
+	}
+	label := Labeling{
+		Node: func(n graph.Node) int { return nodeLabels[n.ID()] },
+		Edge: func(e graph.Edge) int { return int(e.Weight()) },
+	}
+
+	want := make(map[string]int)
+	for k, s := range bruteForce(graphs, label, maxEdges) {
+		if s >= minSupport {
+			want[k] = s
+		}
+	}
+
+	got := make(map[string]int)
+	GSpan(gs, minSupport, maxEdges, label, func(p Pattern) bool {
+		vl := make(map[int]int)
+		var es labeledEdges
+		for _, e := range p.Code {
+			vl[e.From] = e.FromLabel
+			vl[e.To] = e.ToLabel
+			es = append(es, [3]int{e.From, e.To, e.EdgeLabel})
+		}
+		k := canonical(vl, es)
+		if _, dup := got[k]; dup {
+			t.Errorf("pattern reported more than once: %v", p.Code)
+		}
+		if p.Support != len(p.Graphs) {
+			t.Errorf("support does not match graph count: %d != %d", p.Support, len(p.Graphs))
+		}
+		got[k] = p.Support
+		return true
+	})
+
+	if len(got) != len(want) {
+		t.Errorf("unexpected number of patterns: got:%d want:%d", len(got), len(want))
+	}
+	for k, s := range want {
+		if got[k] != s {
+			t.Errorf("unexpected support for %s: got:%d want:%d", k, got[k], s)
+		}
+	}
+}
+
+func TestGSpanHalt(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 5; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+	}
+	var n int
+	GSpan([]graph.Undirected{g, g}, 2, 0, Labeling{}, func(p Pattern) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Errorf("unexpected number of patterns before halt: got:%d want:3", n)
+	}
+
+	var paths []int
+	GSpan([]graph.Undirected{g}, 1, 0, Labeling{}, func(p Pattern) bool {
+		paths = append(paths, len(p.Code))
+		h := simple.NewUndirectedGraph(0, math.Inf(1))
+		p.Graph(h)
+		if len(h.Nodes()) != p.Nodes() {
+			t.Errorf("unexpected pattern graph order: got:%d want:%d", len(h.Nodes()), p.Nodes())
+		}
+		return true
+	})
+	if want := []int{1, 2, 3, 4, 5}; fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("unexpected path patterns: got:%v want:%v", paths, want)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+)
+
+// maxCorePeripheryIter is the maximum number of iterations performed by
+// CorePeriphery.
+const maxCorePeripheryIter = 1000
+
+// CorePeriphery returns the coreness scores of the nodes of the undirected
+// graph g under the continuous core-periphery model of Borgatti and Everett
+// described in doi:10.1016/S0378-8733(99)00019-2, and the fit of the model.
+//
+// The coreness vector c minimizes the residual
+//  \sum_{i≠j} (a_{ij} - c_i c_j)^2,
+// where a_{ij} is the adjacency matrix of g, so that the pattern of ties
+// between core nodes, with high scores, and periphery nodes, with low scores,
+// best matches the ideal core-periphery structure. The fit is the Pearson
+// correlation between the off-diagonal elements of the adjacency matrix and
+// of c c^T, and is NaN if either has no variance. CorePeriphery terminates
+// when the 2-norm of the vector difference between iterations is below tol.
+// The returned map is keyed on the graph node IDs.
+func CorePeriphery(g graph.Undirected, tol float64) (core map[int]float64, fit float64) {
+	nodes := g.Nodes()
+
+	// Make a topological copy of g with dense node IDs.
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	adj := make([][]int, len(nodes))
+	for i, n := range nodes {
+		for _, v := range g.From(n) {
+			if j := indexOf[v.ID()]; j != i {
+				adj[i] = append(adj[i], j)
+			}
+		}
+	}
+	indexOf = nil
+
+	// Start from the normalized degree.
+	c := make([]float64, len(nodes))
+	next := make([]float64, len(nodes))
+	var sum float64
+	for i := range c {
+		c[i] = float64(len(adj[i]))
+		sum += c[i] * c[i]
+	}
+	if sum != 0 {
+		scale := 1 / math.Sqrt(math.Sqrt(sum))
+		for i := range c {
+			c[i] *= scale
+		}
+	}
+
+	for iter := 0; iter < maxCorePeripheryIter; iter++ {
+		var sq float64
+		for _, v := range c {
+			sq += v * v
+		}
+		var norm float64
+		for i := range c {
+			// Minimizing the residual with respect
+			// to c_i with the other scores fixed.
+			var num float64
+			for _, j := range adj[i] {
+				num += c[j]
+			}
+			den := sq - c[i]*c[i]
+			if den == 0 {
+				next[i] = 0
+			} else {
+				next[i] = num / den
+			}
+			d := next[i] - c[i]
+			norm += d * d
+		}
+		c, next = next, c
+		if math.Sqrt(norm) < tol {
+			break
+		}
+	}
+
+	core = make(map[int]float64, len(nodes))
+	for i, n := range nodes {
+		core[n.ID()] = c[i]
+	}
+
+	// Calculate the correlation between a_ij and c_i c_j
+	// over the off-diagonal elements.
+	var sumA, sumP, sumAA, sumPP, sumAP float64
+	for i := range nodes {
+		isAdj := make(map[int]bool, len(adj[i]))
+		for _, j := range adj[i] {
+			isAdj[j] = true
+		}
+		for j := range nodes {
+			if i == j {
+				continue
+			}
+			var a float64
+			if isAdj[j] {
+				a = 1
+			}
+			p := c[i] * c[j]
+			sumA += a
+			sumP += p
+			sumAA += a * a
+			sumPP += p * p
+			sumAP += a * p
+		}
+	}
+	n := float64(len(nodes) * (len(nodes) - 1))
+	cov := sumAP/n - (sumA/n)*(sumP/n)
+	varA := sumAA/n - (sumA/n)*(sumA/n)
+	varP := sumPP/n - (sumP/n)*(sumP/n)
+	if varA <= 0 || varP <= 0 {
+		return core, math.NaN()
+	}
+	return core, cov / math.Sqrt(varA*varP)
+}
@@ -0,0 +1,81 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+)
+
+var corePeripheryTests = []struct {
+	g []set
+
+	core       set
+	minFit     float64
+	wantNaNFit bool
+}{
+	{
+		// A star has a single core node.
+		g: []set{
+			A: linksTo(B, C, D, E, F),
+			B: nil,
+			C: nil,
+			D: nil,
+			E: nil,
+			F: nil,
+		},
+		core:   linksTo(A),
+		minFit: 0.5,
+	},
+	{
+		// A clique of A-D with pendants
+		// attached to the clique nodes.
+		g: []set{
+			A: linksTo(B, C, D, E, F),
+			B: linksTo(C, D, G, H),
+			C: linksTo(D, I, J),
+			D: linksTo(K),
+			E: nil, F: nil, G: nil, H: nil,
+			I: nil, J: nil, K: nil,
+		},
+		core:   linksTo(A, B, C, D),
+		minFit: 0.6,
+	},
+	{
+		// A graph with no edges has no structure.
+		g:          []set{A: nil, B: nil, C: nil},
+		wantNaNFit: true,
+	},
+}
+
+func TestCorePeriphery(t *testing.T) {
+	for i, test := range corePeripheryTests {
+		core, fit := CorePeriphery(undirectedFrom(test.g), 1e-10)
+		if len(core) != len(test.g) {
+			t.Errorf("unexpected number of scores for test %d: got:%d want:%d", i, len(core), len(test.g))
+		}
+		if test.wantNaNFit {
+			if !math.IsNaN(fit) {
+				t.Errorf("unexpected fit for test %d: got:%v want:NaN", i, fit)
+			}
+			continue
+		}
+		if fit < test.minFit {
+			t.Errorf("unexpected fit for test %d: got:%v want:>=%v", i, fit, test.minFit)
+		}
+		minCore := math.Inf(1)
+		maxPeriphery := math.Inf(-1)
+		for id, c := range core {
+			if _, ok := test.core[id]; ok {
+				minCore = math.Min(minCore, c)
+			} else {
+				maxPeriphery = math.Max(maxPeriphery, c)
+			}
+		}
+		if minCore <= maxPeriphery {
+			t.Errorf("core and periphery not separated for test %d: %v", i, core)
+		}
+	}
+}
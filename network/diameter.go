@@ -0,0 +1,149 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/topo"
+)
+
+// Diameter returns the hop diameter of g, the greatest hop distance between
+// any pair of nodes joined by a path. Directed graphs may be handled by
+// wrapping them in a graph.Undirect.
+//
+// The diameter of each connected component is found using the iFUB algorithm
+// of Crescenzi et al., doi:10.1016/j.tcs.2012.09.018, starting from a node
+// chosen by a pair of double breadth-first sweeps. While the worst case time
+// complexity is O(|V|.|E|), for sparse real-world graphs typically only a small
+// number of breadth-first searches are needed.
+func Diameter(g graph.Undirected) int {
+	cc := topo.ConnectedComponents(g)
+	sort.Sort(bySizeDesc(cc))
+	var diam int
+	for _, c := range cc {
+		if len(c)-1 <= diam {
+			// The component cannot
+			// have a larger diameter.
+			break
+		}
+		sort.Sort(ordered.ByID(c))
+		if d := ifub(g, c); d > diam {
+			diam = d
+		}
+	}
+	return diam
+}
+
+// ifub returns the hop diameter of the connected component c of g.
+func ifub(g graph.Undirected, c []graph.Node) int {
+	// Find a central node and a lower bound for
+	// the diameter with a 4-sweep: two double
+	// sweeps, each starting from the middle of
+	// the path found by the previous.
+	start := c[0]
+	for _, n := range c[1:] {
+		if len(g.From(n)) > len(g.From(start)) {
+			start = n
+		}
+	}
+	var lb int
+	for i := 0; i < 2; i++ {
+		a := sweep(g, start)
+		b := sweep(g, a.farthest())
+		if b.ecc > lb {
+			lb = b.ecc
+		}
+		start = b.middle()
+	}
+
+	// Examine the fringes of the breadth-first
+	// tree from the central node, from furthest
+	// inwards, until the eccentricities of the
+	// nodes in the remaining inner levels cannot
+	// exceed the lower bound.
+	u := sweep(g, start)
+	if u.ecc > lb {
+		lb = u.ecc
+	}
+	for i := u.ecc; 2*i > lb; i-- {
+		for _, n := range u.fringe(i) {
+			if e := sweep(g, n).ecc; e > lb {
+				lb = e
+			}
+		}
+		if lb > 2*(i-1) {
+			break
+		}
+	}
+	return lb
+}
+
+// bfs is the result of a breadth-first search.
+type bfs struct {
+	// order holds the nodes in order of
+	// their visit, depth holds the depth
+	// of each node in order and parent
+	// holds the index of the parent.
+	order  []graph.Node
+	depth  []int
+	parent []int
+
+	ecc int
+}
+
+// sweep performs a breadth-first search of g from n.
+func sweep(g graph.Undirected, n graph.Node) bfs {
+	b := bfs{
+		order:  []graph.Node{n},
+		depth:  []int{0},
+		parent: []int{-1},
+	}
+	seen := map[int]bool{n.ID(): true}
+	for i := 0; i < len(b.order); i++ {
+		for _, v := range g.From(b.order[i]) {
+			if seen[v.ID()] {
+				continue
+			}
+			seen[v.ID()] = true
+			b.order = append(b.order, v)
+			b.depth = append(b.depth, b.depth[i]+1)
+			b.parent = append(b.parent, i)
+		}
+	}
+	b.ecc = b.depth[len(b.depth)-1]
+	return b
+}
+
+// farthest returns the last visited node of the search.
+func (b bfs) farthest() graph.Node {
+	return b.order[len(b.order)-1]
+}
+
+// middle returns the node half way along the path from the root to the
+// last visited node.
+func (b bfs) middle() graph.Node {
+	i := len(b.order) - 1
+	for b.depth[i] > b.ecc/2 {
+		i = b.parent[i]
+	}
+	return b.order[i]
+}
+
+// fringe returns the nodes at the given depth.
+func (b bfs) fringe(depth int) []graph.Node {
+	lo := sort.SearchInts(b.depth, depth)
+	hi := sort.SearchInts(b.depth, depth+1)
+	return b.order[lo:hi]
+}
+
+// bySizeDesc sorts node sets by decreasing size.
+type bySizeDesc [][]graph.Node
+
+func (s bySizeDesc) Len() int           { return len(s) }
+func (s bySizeDesc) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
+func (s bySizeDesc) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
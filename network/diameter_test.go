@@ -0,0 +1,103 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+var diameterTests = []struct {
+	name  string
+	graph func() graph.Undirected
+	want  int
+}{
+	{
+		name:  "empty",
+		graph: func() graph.Undirected { return simple.NewUndirectedGraph(0, math.Inf(1)) },
+		want:  0,
+	},
+	{
+		name: "path",
+		graph: func() graph.Undirected {
+			g := simple.NewUndirectedGraph(0, math.Inf(1))
+			for i := 0; i < 9; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1), W: 1})
+			}
+			return g
+		},
+		want: 9,
+	},
+	{
+		name: "cycle",
+		graph: func() graph.Undirected {
+			g := simple.NewUndirectedGraph(0, math.Inf(1))
+			for i := 0; i < 11; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 11), W: 1})
+			}
+			return g
+		},
+		want: 5,
+	},
+	{
+		name: "disconnected",
+		graph: func() graph.Undirected {
+			g := simple.NewUndirectedGraph(0, math.Inf(1))
+			for i := 0; i < 4; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1), W: 1})
+			}
+			for i := 10; i < 20; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(10), T: simple.Node(i + 1), W: 1})
+			}
+			return g
+		},
+		want: 4,
+	},
+}
+
+func TestDiameter(t *testing.T) {
+	for _, test := range diameterTests {
+		if got := Diameter(test.graph()); got != test.want {
+			t.Errorf("unexpected diameter for %s: got:%d want:%d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDiameterRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ n, m int }{
+		{n: 50, m: 49},
+		{n: 100, m: 120},
+		{n: 200, m: 400},
+		{n: 300, m: 250},
+	} {
+		for i := 0; i < 10; i++ {
+			g := simple.NewUndirectedGraph(0, math.Inf(1))
+			for j := 0; j < test.n; j++ {
+				g.AddNode(simple.Node(j))
+			}
+			for j := 0; j < test.m; j++ {
+				u, v := rnd.Intn(test.n), rnd.Intn(test.n)
+				if u != v {
+					g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+				}
+			}
+
+			var want int
+			for _, n := range g.Nodes() {
+				if e := sweep(g, n).ecc; e > want {
+					want = e
+				}
+			}
+			if got := Diameter(g); got != want {
+				t.Errorf("unexpected diameter for n=%d m=%d: got:%d want:%d", test.n, test.m, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/versioned"
+)
+
+// changes is implemented by measures that are updated with the
+// elementary changes to a graph.
+type changes interface {
+	addNode(id int)
+	removeNode(id int)
+	addArc(u, v int)
+	removeArc(u, v int)
+}
+
+// adjacency is a copy of the structure of a graph. Undirected edges
+// are held as a pair of arcs.
+type adjacency struct {
+	directed bool
+	from, to map[int]map[int]struct{}
+
+	// nodes holds the node IDs in an order
+	// allowing random selection and index
+	// holds the position of each ID.
+	nodes []int
+	index map[int]int
+}
+
+func newAdjacency(g graph.Graph) *adjacency {
+	// The versioned.Graph wrapper hides
+	// the directedness of the graph.
+	if v, ok := g.(*versioned.Graph); ok {
+		g = v.Mutable
+	}
+	_, directed := g.(graph.Directed)
+	a := &adjacency{
+		directed: directed,
+		from:     make(map[int]map[int]struct{}),
+		to:       make(map[int]map[int]struct{}),
+		index:    make(map[int]int),
+	}
+	nodes := g.Nodes()
+	ids := make([]int, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		a.addNode(id)
+	}
+	for _, u := range ids {
+		for _, v := range g.From(simpleNode(u)) {
+			a.addArc(u, v.ID())
+		}
+	}
+	return a
+}
+
+// simpleNode is a graph.Node used to query graphs by ID.
+type simpleNode int
+
+func (n simpleNode) ID() int { return int(n) }
+
+func (a *adjacency) has(id int) bool {
+	_, ok := a.index[id]
+	return ok
+}
+
+func (a *adjacency) addNode(id int) {
+	a.index[id] = len(a.nodes)
+	a.nodes = append(a.nodes, id)
+	a.from[id] = make(map[int]struct{})
+	a.to[id] = make(map[int]struct{})
+}
+
+func (a *adjacency) removeNode(id int) {
+	i := a.index[id]
+	last := a.nodes[len(a.nodes)-1]
+	a.nodes[i] = last
+	a.index[last] = i
+	a.nodes = a.nodes[:len(a.nodes)-1]
+	delete(a.index, id)
+	delete(a.from, id)
+	delete(a.to, id)
+}
+
+func (a *adjacency) hasArc(u, v int) bool {
+	_, ok := a.from[u][v]
+	return ok
+}
+
+func (a *adjacency) addArc(u, v int) {
+	a.from[u][v] = struct{}{}
+	a.to[v][u] = struct{}{}
+}
+
+func (a *adjacency) removeArc(u, v int) {
+	delete(a.from[u], v)
+	delete(a.to[v], u)
+}
+
+// sorted returns the IDs in the set in ascending order.
+func sorted(set map[int]struct{}) []int {
+	ids := make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// apply applies the event to the adjacency, passing the elementary changes
+// it makes to c. Arcs are added after being added to the adjacency and are
+// removed after being removed from the adjacency. Nodes are likewise added and
+// removed after the adjacency, with their arcs removed first.
+func (a *adjacency) apply(e versioned.Event, c changes) {
+	switch e.Op {
+	case versioned.AddNode:
+		if !a.has(e.Node.ID()) {
+			a.addNode(e.Node.ID())
+			c.addNode(e.Node.ID())
+		}
+	case versioned.RemoveNode:
+		id := e.Node.ID()
+		if !a.has(id) {
+			return
+		}
+		for _, v := range sorted(a.from[id]) {
+			a.removeArc(id, v)
+			c.removeArc(id, v)
+		}
+		for _, u := range sorted(a.to[id]) {
+			a.removeArc(u, id)
+			c.removeArc(u, id)
+		}
+		a.removeNode(id)
+		c.removeNode(id)
+	case versioned.SetEdge:
+		u, v := e.Edge.From().ID(), e.Edge.To().ID()
+		for _, id := range []int{u, v} {
+			if !a.has(id) {
+				a.addNode(id)
+				c.addNode(id)
+			}
+		}
+		a.arc(u, v, c)
+		if !a.directed && u != v {
+			a.arc(v, u, c)
+		}
+	case versioned.RemoveEdge:
+		u, v := e.Edge.From().ID(), e.Edge.To().ID()
+		if a.hasArc(u, v) {
+			a.removeArc(u, v)
+			c.removeArc(u, v)
+		}
+		if !a.directed && a.hasArc(v, u) {
+			a.removeArc(v, u)
+			c.removeArc(v, u)
+		}
+	}
+}
+
+// arc adds the arc from u to v if it is not already held.
+func (a *adjacency) arc(u, v int, c changes) {
+	if !a.hasArc(u, v) {
+		a.addArc(u, v)
+		c.addArc(u, v)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"math/rand"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/versioned"
+)
+
+// Degree is an incrementally updated degree centrality for the nodes
+// of a graph.
+type Degree struct {
+	adj *adjacency
+}
+
+// NewDegree returns a Degree for g.
+func NewDegree(g graph.Graph) *Degree {
+	return &Degree{adj: newAdjacency(g)}
+}
+
+// Update updates the degrees with the change to the graph described by e.
+func (d *Degree) Update(e versioned.Event) {
+	d.adj.apply(e, nop{})
+}
+
+// In returns the number of nodes with an edge to n.
+func (d *Degree) In(n graph.Node) int {
+	return len(d.adj.to[n.ID()])
+}
+
+// Out returns the number of nodes with an edge from n.
+func (d *Degree) Out(n graph.Node) int {
+	return len(d.adj.from[n.ID()])
+}
+
+// Degree returns the degree of n. For directed graphs this is the sum
+// of the in and out degrees.
+func (d *Degree) Degree(n graph.Node) int {
+	if d.adj.directed {
+		return d.In(n) + d.Out(n)
+	}
+	return d.Out(n)
+}
+
+// Centrality returns the degree centrality of n, the degree of n divided
+// by the number of other nodes in the graph.
+func (d *Degree) Centrality(n graph.Node) float64 {
+	if len(d.adj.nodes) < 2 {
+		return 0
+	}
+	return float64(d.Degree(n)) / float64(len(d.adj.nodes)-1)
+}
+
+// nop is a changes that does nothing.
+type nop struct{}
+
+func (nop) addNode(int)        {}
+func (nop) removeNode(int)     {}
+func (nop) addArc(_, _ int)    {}
+func (nop) removeArc(_, _ int) {}
+
+// Closeness is an incrementally updated approximation of the closeness
+// centrality of the nodes of a graph, as defined by network.Closeness
+// with unit edge weights.
+//
+// The approximation is made by the pivot sampling method of Eppstein and
+// Wang, doi:10.7155/jgaa.00081. The hop distances from a uniform sample of
+// k pivot nodes are held and the sum of distances to a node from all nodes
+// is estimated by scaling the sum of its distances from the pivots by n/k,
+// where n is the number of nodes. When the graph changes, distances from a
+// pivot are updated by searching only from the nodes whose distance may have
+// been shortened, or recomputed when a removed edge lay on a shortest path.
+// When k is not less than the number of nodes the values are exact.
+type Closeness struct {
+	adj *adjacency
+
+	k   int
+	rnd *rand.Rand
+
+	// pivots holds the pivot IDs and
+	// dist holds the hop distances from
+	// each pivot to reachable nodes.
+	pivots []int
+	dist   map[int]map[int]int
+}
+
+// NewCloseness returns a Closeness approximation for g using k pivots. If src
+// is not nil it is used as the random source for choosing pivots, otherwise a
+// source seeded with 1 is used.
+func NewCloseness(g graph.Graph, k int, src *rand.Rand) *Closeness {
+	if k < 1 {
+		panic("dynamic: number of pivots must be positive")
+	}
+	if src == nil {
+		src = rand.New(rand.NewSource(1))
+	}
+	c := &Closeness{
+		adj:  newAdjacency(g),
+		k:    k,
+		rnd:  src,
+		dist: make(map[int]map[int]int),
+	}
+	nodes := c.adj.nodes
+	if len(nodes) <= k {
+		c.pivots = append(c.pivots, nodes...)
+	} else {
+		for _, i := range c.rnd.Perm(len(nodes))[:k] {
+			c.pivots = append(c.pivots, nodes[i])
+		}
+	}
+	for _, p := range c.pivots {
+		c.search(p)
+	}
+	return c
+}
+
+// Update updates the approximation with the change to the graph described
+// by e.
+func (c *Closeness) Update(e versioned.Event) {
+	c.adj.apply(e, c)
+}
+
+// Closeness returns the approximate closeness centrality of n.
+func (c *Closeness) Closeness(n graph.Node) float64 {
+	var sum int
+	for _, p := range c.pivots {
+		sum += c.dist[p][n.ID()]
+	}
+	return float64(len(c.pivots)) / (float64(len(c.adj.nodes)) * float64(sum))
+}
+
+// Centralities returns the approximate closeness centrality of each node
+// keyed on the node IDs.
+func (c *Closeness) Centralities() map[int]float64 {
+	cc := make(map[int]float64, len(c.adj.nodes))
+	for _, id := range c.adj.nodes {
+		cc[id] = c.Closeness(simpleNode(id))
+	}
+	return cc
+}
+
+func (c *Closeness) addNode(id int) {
+	// Keep the pivots a uniform sample of the nodes.
+	switch {
+	case len(c.pivots) < c.k:
+		c.pivots = append(c.pivots, id)
+	case c.rnd.Intn(len(c.adj.nodes)) < c.k:
+		i := c.rnd.Intn(len(c.pivots))
+		delete(c.dist, c.pivots[i])
+		c.pivots[i] = id
+	default:
+		return
+	}
+	c.dist[id] = map[int]int{id: 0}
+}
+
+func (c *Closeness) removeNode(id int) {
+	for _, d := range c.dist {
+		delete(d, id)
+	}
+	i := -1
+	for j, p := range c.pivots {
+		if p == id {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return
+	}
+	delete(c.dist, id)
+
+	// Replace the pivot with a node that is not a pivot
+	// if there is one.
+	isPivot := make(map[int]bool, len(c.pivots))
+	for _, p := range c.pivots {
+		isPivot[p] = true
+	}
+	var candidates []int
+	for _, n := range c.adj.nodes {
+		if !isPivot[n] {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 0 {
+		c.pivots = append(c.pivots[:i], c.pivots[i+1:]...)
+		return
+	}
+	c.pivots[i] = candidates[c.rnd.Intn(len(candidates))]
+	c.search(c.pivots[i])
+}
+
+func (c *Closeness) addArc(u, v int) {
+	for _, p := range c.pivots {
+		d := c.dist[p]
+		du, ok := d[u]
+		if !ok {
+			continue
+		}
+		if dv, ok := d[v]; ok && dv <= du+1 {
+			continue
+		}
+		// Propagate the shortened distances.
+		d[v] = du + 1
+		queue := []int{v}
+		for len(queue) != 0 {
+			w := queue[0]
+			queue = queue[1:]
+			for x := range c.adj.from[w] {
+				if dx, ok := d[x]; ok && dx <= d[w]+1 {
+					continue
+				}
+				d[x] = d[w] + 1
+				queue = append(queue, x)
+			}
+		}
+	}
+}
+
+func (c *Closeness) removeArc(u, v int) {
+	for _, p := range c.pivots {
+		d := c.dist[p]
+		du, ok := d[u]
+		if !ok || d[v] != du+1 {
+			continue
+		}
+		// The distance to v is unchanged if
+		// another shortest path remains.
+		supported := false
+		for w := range c.adj.to[v] {
+			if dw, ok := d[w]; ok && dw+1 == d[v] {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			c.search(p)
+		}
+	}
+}
+
+// search computes the hop distances from the pivot p by breadth first search.
+func (c *Closeness) search(p int) {
+	d := map[int]int{p: 0}
+	queue := []int{p}
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for v := range c.adj.from[u] {
+			if _, ok := d[v]; ok {
+				continue
+			}
+			d[v] = d[u] + 1
+			queue = append(queue, v)
+		}
+	}
+	c.dist[p] = d
+}
@@ -0,0 +1,12 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dynamic provides incrementally updated network centrality measures.
+//
+// The measures hold their own copy of the structure of the graph they are
+// constructed from, and are updated with the changes made to the graph by
+// passing them the events delivered by a versioned.Graph subscription, so
+// they may be updated in a different goroutine from the one mutating the
+// graph. The measures are not safe for concurrent use.
+package dynamic
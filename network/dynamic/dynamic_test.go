@@ -0,0 +1,193 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/network"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/versioned"
+)
+
+// measure is an incrementally updated measure.
+type measure interface {
+	Update(versioned.Event)
+}
+
+// mutate makes a random sequence of mutations to g, updating the
+// measures with the events delivered for each.
+func mutate(g *versioned.Graph, rnd *rand.Rand, n, steps int, m ...measure) {
+	events, cancel := g.Subscribe(versioned.SubscribeOptions{Buffer: 16})
+	defer cancel()
+	for i := 0; i < steps; i++ {
+		u := simple.Node(rnd.Intn(n))
+		v := simple.Node(rnd.Intn(n))
+		switch r := rnd.Intn(10); {
+		case r < 6:
+			if u != v {
+				g.SetEdge(simple.Edge{F: u, T: v, W: 1})
+			}
+		case r < 9:
+			g.RemoveEdge(simple.Edge{F: u, T: v})
+		default:
+			g.RemoveNode(u)
+		}
+		for {
+			select {
+			case e := <-events:
+				for _, m := range m {
+					m.Update(e)
+				}
+				continue
+			default:
+			}
+			break
+		}
+	}
+}
+
+func randomGraph(g graph.Builder, n, m int, rnd *rand.Rand) {
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < m; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u != v {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+		}
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n    = 30
+		damp = 0.85
+	)
+	d := simple.NewDirectedGraph(0, math.Inf(1))
+	randomGraph(d, n, 60, rnd)
+	g := versioned.New(d)
+
+	pr := NewPageRank(g, damp, 1000, rand.New(rand.NewSource(1)))
+	check := func(when string) {
+		want := network.PageRankSparse(d, damp, 1e-8)
+		got := pr.Ranks()
+		if len(got) != len(want) {
+			t.Fatalf("unexpected number of ranks %s: got:%d want:%d", when, len(got), len(want))
+		}
+		for id, w := range want {
+			if math.Abs(got[id]-w) > 0.2*w+0.002 {
+				t.Errorf("unexpected rank for node %d %s: got:%v want:%v", id, when, got[id], w)
+			}
+		}
+	}
+	check("initially")
+	mutate(g, rnd, n, 100, pr)
+	check("after mutation")
+}
+
+func TestDegree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 20
+
+	dg := simple.NewDirectedGraph(0, math.Inf(1))
+	randomGraph(dg, n, 40, rnd)
+	d := versioned.New(dg)
+	dd := NewDegree(d)
+
+	ug := simple.NewUndirectedGraph(0, math.Inf(1))
+	randomGraph(ug, n, 40, rnd)
+	u := versioned.New(ug)
+	ud := NewDegree(u)
+
+	mutate(d, rnd, n, 200, dd)
+	mutate(u, rnd, n, 200, ud)
+
+	for _, v := range dg.Nodes() {
+		in, out := len(dg.To(v)), len(dg.From(v))
+		if dd.In(v) != in || dd.Out(v) != out {
+			t.Errorf("unexpected degree for node %d: got:%d/%d want:%d/%d", v.ID(), dd.In(v), dd.Out(v), in, out)
+		}
+		want := float64(in+out) / float64(len(dg.Nodes())-1)
+		if dd.Centrality(v) != want {
+			t.Errorf("unexpected centrality for node %d: got:%v want:%v", v.ID(), dd.Centrality(v), want)
+		}
+	}
+	for _, v := range ug.Nodes() {
+		if got, want := ud.Degree(v), len(ug.From(v)); got != want {
+			t.Errorf("unexpected undirected degree for node %d: got:%d want:%d", v.ID(), got, want)
+		}
+	}
+}
+
+func TestClosenessExact(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 25
+
+	for _, g := range []versioned.Mutable{
+		simple.NewDirectedGraph(0, math.Inf(1)),
+		simple.NewUndirectedGraph(0, math.Inf(1)),
+	} {
+		randomGraph(g, n, 50, rnd)
+		v := versioned.New(g)
+
+		// With as many pivots as nodes, the values are exact.
+		c := NewCloseness(v, n, rand.New(rand.NewSource(1)))
+		for i := 0; i < 10; i++ {
+			mutate(v, rnd, n, 20, c)
+			p, _ := path.FloydWarshall(g)
+			want := network.Closeness(g, p)
+			got := c.Centralities()
+			if len(got) != len(want) {
+				t.Fatalf("unexpected number of values: got:%d want:%d", len(got), len(want))
+			}
+			for id, w := range want {
+				if got[id] != w && math.Abs(got[id]-w) > 1e-12 {
+					t.Errorf("unexpected closeness for node %d: got:%v want:%v", id, got[id], w)
+				}
+			}
+		}
+	}
+}
+
+func TestClosenessPivots(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n = 100
+		k = 40
+	)
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % n), W: 1})
+	}
+	v := versioned.New(g)
+	c := NewCloseness(v, k, rand.New(rand.NewSource(1)))
+
+	// Shortcuts keep the graph connected.
+	events, cancel := v.Subscribe(versioned.SubscribeOptions{Buffer: 16})
+	for i := 0; i < 20; i++ {
+		a, b := rnd.Intn(n), rnd.Intn(n)
+		if a != b {
+			v.SetEdge(simple.Edge{F: simple.Node(a), T: simple.Node(b), W: 1})
+		}
+		for len(events) != 0 {
+			c.Update(<-events)
+		}
+	}
+	cancel()
+
+	p, _ := path.FloydWarshall(g)
+	want := network.Closeness(g, p)
+	for id, w := range want {
+		if got := c.Closeness(simple.Node(id)); math.Abs(got-w) > 0.3*w {
+			t.Errorf("unexpected closeness for node %d: got:%v want:%v", id, got, w)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/versioned"
+)
+
+// PageRank is an incrementally updated estimate of the PageRank of the nodes
+// of a graph, as defined by network.PageRank.
+//
+// The estimate is made by the Monte Carlo method of Bahmani, Chowdhury and
+// Goel, doi:10.14778/1929861.1929864. A number of random walks start at each
+// node, and at each step a walk ends with probability 1-damp, or otherwise
+// follows a random out edge or, from a node without out edges, jumps to a
+// random node. The PageRank of a node is estimated from the number of visits
+// made to it by the walks. When the graph changes, only the walks whose
+// distribution is altered by the change are rerouted, so updates take time
+// proportional to the number of visits to the nodes involved rather than to
+// the size of the graph. Each estimate has a relative standard error of
+// about 1/sqrt(walks*n*rank), where n is the number of nodes.
+type PageRank struct {
+	adj *adjacency
+
+	damp  float64
+	walks int
+	rnd   *rand.Rand
+
+	// starts holds the walks starting
+	// at each node, visits holds the
+	// number of visits made by each
+	// walk to each node and total the
+	// total visits to each node.
+	starts map[int][]*walk
+	visits map[int]map[*walk]int
+	total  map[int]int
+
+	// jumpers holds the walks that
+	// include a random jump.
+	jumpers map[*walk]struct{}
+
+	nextWalk int
+}
+
+// walk is a random walk. The step from nodes[i] to nodes[i+1] is
+// a random jump if jump[i] is true.
+type walk struct {
+	id    int
+	nodes []int
+	jump  []bool
+}
+
+// NewPageRank returns a PageRank estimate for g using the given damping
+// factor and number of walks per node. If src is not nil it is used as the
+// random source, otherwise a source seeded with 1 is used.
+func NewPageRank(g graph.Graph, damp float64, walks int, src *rand.Rand) *PageRank {
+	if src == nil {
+		src = rand.New(rand.NewSource(1))
+	}
+	p := &PageRank{
+		adj:     newAdjacency(g),
+		damp:    damp,
+		walks:   walks,
+		rnd:     src,
+		starts:  make(map[int][]*walk),
+		visits:  make(map[int]map[*walk]int),
+		total:   make(map[int]int),
+		jumpers: make(map[*walk]struct{}),
+	}
+	for _, id := range p.adj.nodes {
+		p.startWalks(id)
+	}
+	return p
+}
+
+// Update updates the estimate with the change to the graph described by e.
+func (p *PageRank) Update(e versioned.Event) {
+	p.adj.apply(e, p)
+}
+
+// Rank returns the estimated PageRank of n.
+func (p *PageRank) Rank(n graph.Node) float64 {
+	if len(p.adj.nodes) == 0 {
+		return 0
+	}
+	return (1 - p.damp) * float64(p.total[n.ID()]) / float64(len(p.adj.nodes)*p.walks)
+}
+
+// Ranks returns the estimated PageRank of each node keyed on the node IDs.
+func (p *PageRank) Ranks() map[int]float64 {
+	ranks := make(map[int]float64, len(p.adj.nodes))
+	for _, id := range p.adj.nodes {
+		ranks[id] = p.Rank(simpleNode(id))
+	}
+	return ranks
+}
+
+func (p *PageRank) addNode(id int) {
+	// A jump should land on the new node with
+	// probability 1/n, so reroute each walk's
+	// first jump with that probability.
+	n := len(p.adj.nodes)
+	for _, w := range p.walksIn(p.jumpers) {
+		for i := range w.jump {
+			if w.jump[i] && p.rnd.Intn(n) == 0 {
+				p.reroute(w, i, id, true)
+				break
+			}
+		}
+	}
+
+	p.startWalks(id)
+}
+
+// startWalks starts the walks from the node id.
+func (p *PageRank) startWalks(id int) {
+	for i := 0; i < p.walks; i++ {
+		w := &walk{id: p.nextWalk, nodes: []int{id}}
+		p.nextWalk++
+		p.visit(w, id, 1)
+		p.extend(w)
+		p.starts[id] = append(p.starts[id], w)
+	}
+}
+
+func (p *PageRank) removeNode(id int) {
+	for _, w := range p.starts[id] {
+		p.discard(w, 0)
+		delete(p.jumpers, w)
+	}
+	delete(p.starts, id)
+
+	// The node's arcs have been removed, so remaining
+	// visits are by jumps, which are rerouted to the
+	// remaining nodes.
+	for _, w := range p.walksIn(p.visits[id]) {
+		for i := range w.jump {
+			if w.jump[i] && w.nodes[i+1] == id {
+				p.reroute(w, i, p.adj.nodes[p.rnd.Intn(len(p.adj.nodes))], true)
+				break
+			}
+		}
+	}
+	delete(p.visits, id)
+	delete(p.total, id)
+}
+
+func (p *PageRank) addArc(u, v int) {
+	// Each continuing step from u should take the
+	// new arc with probability 1/k where k is the
+	// new out degree of u. Steps from u that were
+	// jumps must all take the new arc.
+	k := len(p.adj.from[u])
+	for _, w := range p.walksIn(p.visits[u]) {
+		for i := 0; i < len(w.nodes)-1; i++ {
+			if w.nodes[i] != u {
+				continue
+			}
+			if w.jump[i] || p.rnd.Intn(k) == 0 {
+				p.reroute(w, i, v, false)
+				break
+			}
+		}
+	}
+}
+
+func (p *PageRank) removeArc(u, v int) {
+	// Steps that took the removed arc are rerouted to
+	// another arc or, if there is none, a random jump.
+	for _, w := range p.walksIn(p.visits[u]) {
+		for i := 0; i < len(w.nodes)-1; i++ {
+			if w.nodes[i] == u && w.nodes[i+1] == v && !w.jump[i] {
+				next, jump := p.step(u)
+				p.reroute(w, i, next, jump)
+				break
+			}
+		}
+	}
+}
+
+// walksIn returns the walks in the set ordered by their creation so that
+// updates are deterministic for a given random source.
+func (p *PageRank) walksIn(set interface{}) []*walk {
+	var walks []*walk
+	switch set := set.(type) {
+	case map[*walk]int:
+		for w := range set {
+			walks = append(walks, w)
+		}
+	case map[*walk]struct{}:
+		for w := range set {
+			walks = append(walks, w)
+		}
+	}
+	sort.Sort(byWalkID(walks))
+	return walks
+}
+
+// reroute replaces the steps of w after its ith step with a step to
+// next followed by a random continuation.
+func (p *PageRank) reroute(w *walk, i, next int, jump bool) {
+	p.discard(w, i+1)
+	w.nodes = append(w.nodes, next)
+	w.jump = append(w.jump, jump)
+	if jump {
+		p.jumpers[w] = struct{}{}
+	}
+	p.visit(w, next, 1)
+	p.extend(w)
+}
+
+// extend continues w from its last node until it ends.
+func (p *PageRank) extend(w *walk) {
+	for p.rnd.Float64() < p.damp {
+		u := w.nodes[len(w.nodes)-1]
+		next, jump := p.step(u)
+		w.nodes = append(w.nodes, next)
+		w.jump = append(w.jump, jump)
+		if jump {
+			p.jumpers[w] = struct{}{}
+		}
+		p.visit(w, next, 1)
+	}
+}
+
+// step returns a random next node for a walk at u and whether the step
+// is a random jump.
+func (p *PageRank) step(u int) (next int, jump bool) {
+	to := p.adj.from[u]
+	if len(to) == 0 {
+		return p.adj.nodes[p.rnd.Intn(len(p.adj.nodes))], true
+	}
+	ids := sorted(to)
+	return ids[p.rnd.Intn(len(ids))], false
+}
+
+// discard removes the nodes of w from position i.
+func (p *PageRank) discard(w *walk, i int) {
+	for _, id := range w.nodes[i:] {
+		p.visit(w, id, -1)
+	}
+	w.nodes = w.nodes[:i]
+	if i == 0 {
+		w.jump = w.jump[:0]
+	} else {
+		w.jump = w.jump[:i-1]
+	}
+	jumps := false
+	for _, j := range w.jump {
+		jumps = jumps || j
+	}
+	if !jumps {
+		delete(p.jumpers, w)
+	}
+}
+
+// visit adjusts the visit count of w to the node id by d.
+func (p *PageRank) visit(w *walk, id, d int) {
+	v := p.visits[id]
+	if v == nil {
+		v = make(map[*walk]int)
+		p.visits[id] = v
+	}
+	v[w] += d
+	if v[w] == 0 {
+		delete(v, w)
+	}
+	p.total[id] += d
+}
+
+// byWalkID sorts walks by their ID.
+type byWalkID []*walk
+
+func (w byWalkID) Len() int           { return len(w) }
+func (w byWalkID) Less(i, j int) bool { return w[i].id < w[j].id }
+func (w byWalkID) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
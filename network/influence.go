@@ -0,0 +1,211 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Influence is a set of seed nodes chosen to maximize the spread of an
+// independent cascade.
+type Influence struct {
+	// Seeds holds the chosen seed nodes
+	// in the order they were chosen.
+	Seeds []graph.Node
+
+	// Spread is the estimated expected
+	// number of nodes activated by Seeds.
+	Spread float64
+
+	// Ratio is a lower bound on the ratio
+	// of the estimated spread of Seeds to
+	// the largest estimated spread of any
+	// set of the same size. It is at least
+	// 1-1/e.
+	Ratio float64
+
+	// Samples is the number of reverse
+	// reachable sets sampled.
+	Samples int
+}
+
+// InfluenceMaximization returns a set of at most k seed nodes of g that
+// approximately maximizes the expected number of nodes activated by an
+// independent cascade from the seeds. An active node u activates each
+// inactive node v with an edge from u to v with probability p(u, v). If p
+// is nil, the weighted cascade probability 1/in-degree(v) is used.
+//
+// The spread is estimated by the reverse influence sampling method of Borgs,
+// Brautbar, Chayes and Lucier, doi:10.1137/1.9781611973402.70. Reverse
+// reachable sets are sampled until samples sets have been drawn or the time
+// budget has elapsed, and seeds are chosen greedily to cover the sampled
+// sets. A negative samples or non-positive budget places no limit, but at
+// least one limit must be given. At least one set is always sampled. The
+// returned Ratio bounds the quality of the seeds with respect to the sampled
+// sets, which improves as more sets are sampled.
+//
+// If src is nil, rand.Float64 and rand.Intn are used.
+func InfluenceMaximization(g graph.Directed, k int, p func(u, v graph.Node) float64, samples int, budget time.Duration, src *rand.Rand) Influence {
+	if samples < 0 && budget <= 0 {
+		panic("network: unbounded influence sampling")
+	}
+	nodes := g.Nodes()
+	if len(nodes) == 0 || k <= 0 || samples == 0 {
+		return Influence{Ratio: 1}
+	}
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// in holds the in-neighbors of each node
+	// with the probability of activation.
+	type arc struct {
+		from int
+		p    float64
+	}
+	in := make([][]arc, len(nodes))
+	for i, v := range nodes {
+		to := g.To(v)
+		for _, u := range to {
+			prob := 1 / float64(len(to))
+			if p != nil {
+				prob = p(u, v)
+			}
+			in[i] = append(in[i], arc{from: indexOf[u.ID()], p: prob})
+		}
+	}
+
+	var (
+		rnd  = rand.Float64
+		intn = rand.Intn
+	)
+	if src != nil {
+		rnd = src.Float64
+		intn = src.Intn
+	}
+	var deadline time.Time
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+
+	// sets holds the reverse reachable sets and
+	// covers holds the sets containing each node.
+	var sets [][]int
+	covers := make([][]int, len(nodes))
+	seen := make([]int, len(nodes))
+	for i := range seen {
+		seen[i] = -1
+	}
+	for len(sets) != samples {
+		if len(sets)%64 == 63 && !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		id := len(sets)
+		root := intn(len(nodes))
+		seen[root] = id
+		set := []int{root}
+		for q := 0; q < len(set); q++ {
+			for _, a := range in[set[q]] {
+				if seen[a.from] != id && rnd() < a.p {
+					seen[a.from] = id
+					set = append(set, a.from)
+				}
+			}
+		}
+		for _, n := range set {
+			covers[n] = append(covers[n], id)
+		}
+		sets = append(sets, set)
+	}
+
+	if k > len(nodes) {
+		k = len(nodes)
+	}
+	seeds, covered := greedyCover(covers, len(sets), k)
+
+	// The greedy cover is at least 1-1/e of the
+	// optimal cover, which is also no more than
+	// the total of the k largest single covers
+	// and no more than the number of sets.
+	sizes := make([]int, len(covers))
+	for i, c := range covers {
+		sizes[i] = len(c)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	upper := 0
+	for _, s := range sizes[:k] {
+		upper += s
+	}
+	if upper > len(sets) {
+		upper = len(sets)
+	}
+	ratio := 1 - 1/math.E
+	if upper > 0 {
+		ratio = math.Max(ratio, float64(covered)/float64(upper))
+	} else {
+		ratio = 1
+	}
+
+	inf := Influence{
+		Spread:  float64(covered) / float64(len(sets)) * float64(len(nodes)),
+		Ratio:   ratio,
+		Samples: len(sets),
+	}
+	for _, i := range seeds {
+		inf.Seeds = append(inf.Seeds, nodes[i])
+	}
+	return inf
+}
+
+// greedyCover returns up to k node indices chosen greedily to cover the
+// most of the n sets listed for each node by covers, and the number of
+// sets covered. Ties are broken by lowest index.
+func greedyCover(covers [][]int, n, k int) (seeds []int, covered int) {
+	gain := make([]int, len(covers))
+	for i, c := range covers {
+		gain[i] = len(c)
+	}
+	done := make([]bool, n)
+
+	// containing holds the nodes in each set so
+	// gains can be reduced as sets are covered.
+	containing := make([][]int, n)
+	for i, c := range covers {
+		for _, s := range c {
+			containing[s] = append(containing[s], i)
+		}
+	}
+	for len(seeds) < k {
+		best := -1
+		for i, g := range gain {
+			if g > 0 && (best < 0 || g > gain[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		seeds = append(seeds, best)
+		for _, s := range covers[best] {
+			if done[s] {
+				continue
+			}
+			done[s] = true
+			covered++
+			for _, i := range containing[s] {
+				gain[i]--
+			}
+		}
+	}
+	return seeds, covered
+}
@@ -0,0 +1,96 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// stars returns a directed graph of stars with the given number of leaves,
+// directed from hub to leaves. The hub of each star is the lowest ID node
+// of the star.
+func stars(leaves ...int) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	id := 0
+	for _, n := range leaves {
+		hub := simple.Node(id)
+		g.AddNode(hub)
+		for i := 1; i <= n; i++ {
+			g.SetEdge(simple.Edge{F: hub, T: simple.Node(id + i), W: 1})
+		}
+		id += n + 1
+	}
+	return g
+}
+
+func certain(_, _ graph.Node) float64 { return 1 }
+
+func TestInfluenceMaximization(t *testing.T) {
+	for _, test := range []struct {
+		leaves []int
+		k      int
+		want   []int
+		spread float64
+	}{
+		{leaves: []int{9}, k: 1, want: []int{0}, spread: 10},
+		{leaves: []int{3, 9}, k: 2, want: []int{0, 4}, spread: 14},
+		{leaves: []int{3, 9, 2}, k: 2, want: []int{0, 4}, spread: 14},
+	} {
+		g := stars(test.leaves...)
+		inf := InfluenceMaximization(g, test.k, certain, 20000, 0, rand.New(rand.NewSource(1)))
+		sort.Sort(ordered.ByID(inf.Seeds))
+		var got []int
+		for _, n := range inf.Seeds {
+			got = append(got, n.ID())
+		}
+		if !equalInts(got, test.want) {
+			t.Errorf("unexpected seeds for stars %v: got:%v want:%v", test.leaves, got, test.want)
+		}
+		if inf.Samples != 20000 {
+			t.Errorf("unexpected number of samples: got:%d want:20000", inf.Samples)
+		}
+		if math.Abs(inf.Spread-test.spread) > 0.05*test.spread {
+			t.Errorf("unexpected spread for stars %v: got:%v want:%v", test.leaves, inf.Spread, test.spread)
+		}
+		if inf.Ratio < 1-1/math.E || inf.Ratio > 1 {
+			t.Errorf("unexpected ratio for stars %v: got:%v", test.leaves, inf.Ratio)
+		}
+	}
+}
+
+func TestInfluenceMaximizationBudget(t *testing.T) {
+	g := stars(50, 50)
+	inf := InfluenceMaximization(g, 2, nil, -1, 10*time.Millisecond, rand.New(rand.NewSource(1)))
+	if inf.Samples == 0 || len(inf.Seeds) != 2 {
+		t.Errorf("unexpected result: %+v", inf)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unbounded sampling")
+		}
+	}()
+	InfluenceMaximization(g, 2, nil, -1, 0, nil)
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if b[i] != v {
+			return false
+		}
+	}
+	return true
+}
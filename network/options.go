@@ -0,0 +1,80 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import "github.com/gonum/graph/instrument"
+
+// Option is a functional option for the network analysis functions that
+// accept options. Each function documents the options it uses and ignores
+// the others.
+type Option func(*options)
+
+type options struct {
+	damp  float64
+	tol   float64
+	ctx   Context
+	stats *instrument.Stats
+}
+
+func newOptions(opts []Option) options {
+	o := options{damp: 0.85, tol: 1e-8}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Context is the part of context.Context used to cancel network analyses.
+// It is satisfied by any context.Context.
+type Context interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
+// WithDamping returns an Option specifying the damping factor of a random
+// walk based analysis.
+func WithDamping(damp float64) Option {
+	return func(o *options) { o.damp = damp }
+}
+
+// WithTolerance returns an Option specifying the convergence tolerance of
+// an iterative analysis.
+func WithTolerance(tol float64) Option {
+	return func(o *options) { o.tol = tol }
+}
+
+// WithContext returns an Option specifying a context that cancels the
+// analysis when it is done.
+func WithContext(ctx Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithStats returns an Option specifying a Stats that the counts of work
+// performed by the analysis are added to.
+func WithStats(s *instrument.Stats) Option {
+	return func(o *options) { o.stats = s }
+}
+
+// report adds st to the options' Stats if there is one. It is safe to
+// call on a nil receiver.
+func (o *options) report(st instrument.Stats) {
+	if o != nil && o.stats != nil {
+		o.stats.Add(st)
+	}
+}
+
+// canceled returns the error of the options' context if it is done. It
+// is safe to call on a nil receiver.
+func (o *options) canceled() error {
+	if o == nil || o.ctx == nil {
+		return nil
+	}
+	select {
+	case <-o.ctx.Done():
+		return o.ctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/gonum/graph/instrument"
+	"github.com/gonum/graph/simple"
+)
+
+// doneContext is a Context that is already done.
+type doneContext struct{}
+
+var errDone = errors.New("done")
+
+func (doneContext) Done() <-chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}
+func (doneContext) Err() error { return errDone }
+
+func TestPageRankWith(t *testing.T) {
+	for i, test := range pageRankTests {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for u, e := range test.g {
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+
+		want := PageRankSparse(g, test.damp, test.tol)
+		got, err := PageRankWith(g, WithDamping(test.damp), WithTolerance(test.tol))
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+		}
+		for id, w := range want {
+			if math.Abs(got[id]-w) > test.tol {
+				t.Errorf("unexpected rank for node %d in test %d: got:%v want:%v", id, i, got[id], w)
+			}
+		}
+
+		got, err = PageRankWith(g, WithContext(doneContext{}), WithTolerance(0))
+		if err != errDone || got != nil {
+			t.Errorf("unexpected result for test %d with done context: got:%v %v want:nil %v", i, got, err, errDone)
+		}
+	}
+}
+
+func TestPageRankWithStats(t *testing.T) {
+	for i, test := range pageRankTests {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for u, e := range test.g {
+			if !g.Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+
+		var loose, tight instrument.Stats
+		PageRankWith(g, WithDamping(test.damp), WithTolerance(1e-2), WithStats(&loose))
+		PageRankWith(g, WithDamping(test.damp), WithTolerance(1e-10), WithStats(&tight))
+		if loose.Iterations < 1 {
+			t.Errorf("unexpected number of iterations for test %d: got:%d", i, loose.Iterations)
+		}
+		if tight.Iterations < loose.Iterations {
+			t.Errorf("unexpected number of iterations for test %d: tight tolerance:%d loose tolerance:%d",
+				i, tight.Iterations, loose.Iterations)
+		}
+	}
+}
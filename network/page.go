@@ -6,10 +6,10 @@ package network
 
 import (
 	"math"
-	"math/rand"
 
 	"github.com/gonum/floats"
 	"github.com/gonum/graph"
+	"github.com/gonum/graph/instrument"
 	"github.com/gonum/matrix/mat64"
 )
 
@@ -57,16 +57,11 @@ func PageRank(g graph.Directed, damp, tol float64) map[int]float64 {
 	}
 	lastV := mat64.NewVector(len(nodes), last)
 
+	// Start from the uniform distribution so
+	// the result is reproducible.
 	vec := make([]float64, len(nodes))
-	var sum float64
-	for i := range vec {
-		r := rand.NormFloat64()
-		sum += r
-		vec[i] = r
-	}
-	f := 1 / sum
 	for i := range vec {
-		vec[i] *= f
+		vec[i] = 1 / float64(len(nodes))
 	}
 	v := mat64.NewVector(len(nodes), vec)
 
@@ -91,6 +86,22 @@ func PageRank(g graph.Directed, damp, tol float64) map[int]float64 {
 // vector difference between iterations is below tol. The returned map is
 // keyed on the graph node IDs.
 func PageRankSparse(g graph.Directed, damp, tol float64) map[int]float64 {
+	ranks, _ := pageRankSparse(g, damp, tol, nil)
+	return ranks
+}
+
+// PageRankWith returns the PageRank weights for nodes of the directed graph g
+// as calculated by PageRankSparse, using the WithDamping, WithTolerance,
+// WithContext and WithStats options. The default damping factor is 0.85 and
+// the default tolerance is 1e-8. If the context is done before the calculation
+// converges, PageRankWith returns nil and the context's error. The Iterations
+// field of the Stats is filled.
+func PageRankWith(g graph.Directed, opts ...Option) (map[int]float64, error) {
+	o := newOptions(opts)
+	return pageRankSparse(g, o.damp, o.tol, &o)
+}
+
+func pageRankSparse(g graph.Directed, damp, tol float64, o *options) (map[int]float64, error) {
 	// PageRankSparse is implemented according to "How Google Finds Your Needle
 	// in the Web's Haystack".
 	//
@@ -124,22 +135,21 @@ func PageRankSparse(g graph.Directed, damp, tol float64) map[int]float64 {
 	}
 	lastV := mat64.NewVector(len(nodes), last)
 
+	// Start from the uniform distribution so
+	// the result is reproducible.
 	vec := make([]float64, len(nodes))
-	var sum float64
-	for i := range vec {
-		r := rand.NormFloat64()
-		sum += r
-		vec[i] = r
-	}
-	f := 1 / sum
 	for i := range vec {
-		vec[i] *= f
+		vec[i] = 1 / float64(len(nodes))
 	}
 	v := mat64.NewVector(len(nodes), vec)
 
+	var st instrument.Stats
+	defer func() { o.report(st) }()
+
 	dt := (1 - damp) / float64(len(nodes))
 	for {
 		lastV, v = v, lastV
+		st.Iterations++
 
 		m.mulVecUnitary(v, lastV)          // First term of the G matrix equation;
 		with := dangling.dotUnitary(lastV) // Second term;
@@ -149,6 +159,9 @@ func PageRankSparse(g graph.Directed, damp, tol float64) map[int]float64 {
 		if normDiff(vec, last) < tol {
 			break
 		}
+		if err := o.canceled(); err != nil {
+			return nil, err
+		}
 	}
 
 	ranks := make(map[int]float64, len(nodes))
@@ -156,7 +169,7 @@ func PageRankSparse(g graph.Directed, damp, tol float64) map[int]float64 {
 		ranks[nodes[i].ID()] = r
 	}
 
-	return ranks
+	return ranks, nil
 }
 
 // rowCompressedMatrix implements row-compressed
@@ -0,0 +1,172 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// richClubSwaps is the number of double edge swaps per edge used to
+// randomize a graph for rich-club normalization.
+const richClubSwaps = 10
+
+// RichClub returns the rich-club coefficients of the undirected graph g. The
+// rich-club coefficient for degree k is the density of the subgraph induced by
+// the nodes with degree greater than k,
+//  φ(k) = 2 E_k / (N_k (N_k - 1)),
+// as defined by Zhou and Mondragón in doi:10.1109/LCOMM.2004.823426. The
+// returned slice is indexed by k and holds the coefficients for each k with
+// at least two nodes of degree greater than k.
+//
+// If normalized is true, each coefficient is divided by the coefficient of a
+// degree-preserving randomization of g obtained by double edge swaps as
+// described by Colizza et al. in doi:10.1038/nphys209, and coefficients with a
+// zero valued denominator are returned as NaN. If src is not nil it is used as
+// the random source for the randomization, otherwise rand.Intn is used.
+func RichClub(g graph.Undirected, normalized bool, src *rand.Rand) []float64 {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	var edges [][2]int
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			if j := indexOf[v.ID()]; i < j {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	}
+	sort.Sort(byEnds(edges))
+
+	rc := richClub(len(nodes), edges)
+	if !normalized {
+		return rc
+	}
+
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = src.Intn
+	}
+	random := richClub(len(nodes), doubleEdgeSwap(edges, richClubSwaps*len(edges), rnd))
+	for k := range rc {
+		if random[k] == 0 {
+			rc[k] = math.NaN()
+			continue
+		}
+		rc[k] /= random[k]
+	}
+	return rc
+}
+
+// richClub returns the rich-club coefficients of the graph of n nodes with
+// the given edges.
+func richClub(n int, edges [][2]int) []float64 {
+	deg := make([]int, n)
+	for _, e := range edges {
+		deg[e[0]]++
+		deg[e[1]]++
+	}
+
+	// nodes[k] is the number of nodes with degree k
+	// and links[k] is the number of edges with a
+	// lower terminal degree of k.
+	var max int
+	for _, d := range deg {
+		if d > max {
+			max = d
+		}
+	}
+	nodes := make([]int, max+1)
+	for _, d := range deg {
+		nodes[d]++
+	}
+	links := make([]int, max+1)
+	for _, e := range edges {
+		d := deg[e[0]]
+		if deg[e[1]] < d {
+			d = deg[e[1]]
+		}
+		links[d]++
+	}
+
+	var rc []float64
+	nk, ek := n, len(edges)
+	for k := 0; k <= max; k++ {
+		// Remove the nodes and edges with degree k.
+		nk -= nodes[k]
+		ek -= links[k]
+		if nk < 2 {
+			break
+		}
+		rc = append(rc, 2*float64(ek)/(float64(nk)*float64(nk-1)))
+	}
+	return rc
+}
+
+// doubleEdgeSwap returns a copy of edges with swaps attempted random double
+// edge swaps. Swaps that would create a self edge or a multiple edge are
+// rejected.
+func doubleEdgeSwap(edges [][2]int, swaps int, rnd func(int) int) [][2]int {
+	edges = append([][2]int(nil), edges...)
+	if len(edges) < 2 {
+		return edges
+	}
+	has := make(map[[2]int]bool, len(edges))
+	key := func(u, v int) [2]int {
+		if u > v {
+			u, v = v, u
+		}
+		return [2]int{u, v}
+	}
+	for _, e := range edges {
+		has[e] = true
+	}
+	for i := 0; i < swaps; i++ {
+		a, b := rnd(len(edges)), rnd(len(edges))
+		if a == b {
+			continue
+		}
+		u, v := edges[a][0], edges[a][1]
+		x, y := edges[b][0], edges[b][1]
+		if rnd(2) == 0 {
+			x, y = y, x
+		}
+		// Swap u-v, x-y to u-y, x-v.
+		if u == y || x == v {
+			continue
+		}
+		uy, xv := key(u, y), key(x, v)
+		if uy == xv || has[uy] || has[xv] {
+			continue
+		}
+		delete(has, edges[a])
+		delete(has, edges[b])
+		edges[a], edges[b] = uy, xv
+		has[uy] = true
+		has[xv] = true
+	}
+	return edges
+}
+
+// byEnds sorts edges lexically by their terminal indices.
+type byEnds [][2]int
+
+func (e byEnds) Len() int { return len(e) }
+func (e byEnds) Less(i, j int) bool {
+	if e[i][0] == e[j][0] {
+		return e[i][1] < e[j][1]
+	}
+	return e[i][0] < e[j][0]
+}
+func (e byEnds) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
@@ -0,0 +1,141 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func undirectedFrom(g []set) *simple.UndirectedGraph {
+	ug := simple.NewUndirectedGraph(0, math.Inf(1))
+	for u, e := range g {
+		// Add nodes that are not defined by an edge.
+		if !ug.Has(simple.Node(u)) {
+			ug.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			ug.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+		}
+	}
+	return ug
+}
+
+var richClubTests = []struct {
+	g    []set
+	want []float64
+}{
+	{
+		// Degrees are A:3, B:3, C:2, D:3, E:1.
+		g: []set{
+			A: linksTo(B, C, D),
+			B: linksTo(C, D),
+			C: nil,
+			D: linksTo(E),
+			E: nil,
+		},
+		want: []float64{
+			0: 2 * 6.0 / (5 * 4),
+			1: 2 * 5.0 / (4 * 3),
+			2: 2 * 3.0 / (3 * 2),
+		},
+	},
+	{
+		g: []set{
+			A: linksTo(B),
+			B: nil,
+			C: nil,
+		},
+		want: []float64{
+			0: 2 * 1.0 / (2 * 1),
+		},
+	},
+	{
+		g:    []set{A: nil},
+		want: nil,
+	},
+}
+
+func TestRichClub(t *testing.T) {
+	const tol = 1e-12
+	for i, test := range richClubTests {
+		got := RichClub(undirectedFrom(test.g), false, nil)
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected rich-club coefficients for test %d: got:%v want:%v", i, got, test.want)
+			continue
+		}
+		for k := range got {
+			if math.Abs(got[k]-test.want[k]) > tol {
+				t.Errorf("unexpected rich-club coefficients for test %d: got:%v want:%v", i, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRichClubNormalized(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := gen.Gnp(g, 100, 0.1, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("failed to generate graph: %v", err)
+	}
+	raw := RichClub(g, false, nil)
+	norm := RichClub(g, true, rand.New(rand.NewSource(1)))
+	if len(norm) != len(raw) {
+		t.Fatalf("unexpected number of normalized coefficients: got:%d want:%d", len(norm), len(raw))
+	}
+	// The low degree coefficients depend only on the degree
+	// sequence, which is preserved by the randomization.
+	if math.Abs(norm[0]-1) > 1e-12 {
+		t.Errorf("unexpected normalized coefficient for k=0: got:%v want:1", norm[0])
+	}
+	again := RichClub(g, true, rand.New(rand.NewSource(1)))
+	for k := range norm {
+		if norm[k] != again[k] && !(math.IsNaN(norm[k]) && math.IsNaN(again[k])) {
+			t.Errorf("normalization not deterministic for k=%d: %v != %v", k, norm[k], again[k])
+		}
+	}
+}
+
+func TestDoubleEdgeSwap(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := gen.Gnp(g, 50, 0.1, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("failed to generate graph: %v", err)
+	}
+	var edges [][2]int
+	deg := make(map[int]int)
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if u.ID() < v.ID() {
+				edges = append(edges, [2]int{u.ID(), v.ID()})
+				deg[u.ID()]++
+				deg[v.ID()]++
+			}
+		}
+	}
+	swapped := doubleEdgeSwap(edges, 10*len(edges), rand.New(rand.NewSource(1)).Intn)
+	seen := make(map[[2]int]bool)
+	for _, e := range swapped {
+		if e[0] == e[1] {
+			t.Errorf("unexpected self edge: %v", e)
+		}
+		if seen[e] {
+			t.Errorf("unexpected multiple edge: %v", e)
+		}
+		seen[e] = true
+		deg[e[0]]--
+		deg[e[1]]--
+	}
+	for id, d := range deg {
+		if d != 0 {
+			t.Errorf("degree of node %d not preserved: difference %d", id, d)
+		}
+	}
+}
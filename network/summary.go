@@ -0,0 +1,188 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/topo"
+	"github.com/gonum/graph/traverse"
+)
+
+// Statistics holds a summary of the structure of a graph.
+type Statistics struct {
+	// Directed indicates whether
+	// the graph is directed.
+	Directed bool
+
+	// Nodes and Edges hold the number
+	// of nodes and edges in the graph.
+	Nodes, Edges int
+
+	// Density is the ratio of the number
+	// of edges to the number of possible
+	// edges in a simple graph of the same
+	// order and directedness.
+	Density float64
+
+	// MinDegree, MeanDegree and MaxDegree
+	// are the minimum, mean and maximum
+	// node degrees. For directed graphs
+	// the degree of a node is the sum of
+	// its in- and out-degrees.
+	MinDegree  int
+	MeanDegree float64
+	MaxDegree  int
+
+	// Components is the number of connected
+	// components, ignoring edge direction.
+	Components int
+
+	// Diameter is an estimate of the hop
+	// diameter of the largest component,
+	// ignoring edge direction. It is a lower
+	// bound found by a double breadth-first
+	// sweep and is exact for trees.
+	Diameter int
+
+	// Clustering is the global clustering
+	// coefficient, the ratio of closed to
+	// connected triples, ignoring edge
+	// direction. It is NaN if the graph
+	// has no connected triples.
+	Clustering float64
+
+	// Reciprocity is the fraction of edges
+	// of a directed graph for which the
+	// reverse edge also exists. It is NaN
+	// for undirected graphs.
+	Reciprocity float64
+}
+
+// Summary returns summary statistics of the structure of g.
+func Summary(g graph.Graph) Statistics {
+	d, isDirected := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+
+	s := Statistics{
+		Directed:    isDirected,
+		Nodes:       len(nodes),
+		Clustering:  math.NaN(),
+		Reciprocity: math.NaN(),
+	}
+
+	var u graph.Undirected
+	if isDirected {
+		u = graph.Undirect{G: d}
+	} else {
+		u = g.(graph.Undirected)
+	}
+
+	var degrees int
+	for i, n := range nodes {
+		out := len(g.From(n))
+		s.Edges += out
+		deg := out
+		if isDirected {
+			deg += len(d.To(n))
+		}
+		degrees += deg
+		if i == 0 || deg < s.MinDegree {
+			s.MinDegree = deg
+		}
+		if deg > s.MaxDegree {
+			s.MaxDegree = deg
+		}
+	}
+	if !isDirected {
+		s.Edges /= 2
+	}
+	if s.Nodes != 0 {
+		s.MeanDegree = float64(degrees) / float64(s.Nodes)
+	}
+	if s.Nodes > 1 {
+		possible := float64(s.Nodes) * float64(s.Nodes-1)
+		if !isDirected {
+			possible /= 2
+		}
+		s.Density = float64(s.Edges) / possible
+	}
+	if isDirected {
+		s.Reciprocity = Reciprocity(d)
+	}
+
+	cc := topo.ConnectedComponents(u)
+	s.Components = len(cc)
+	var largest []graph.Node
+	for _, c := range cc {
+		if len(c) > len(largest) {
+			largest = c
+		}
+	}
+	if len(largest) != 0 {
+		sort.Sort(ordered.ByID(largest))
+		far, _ := farthest(u, largest[0])
+		_, s.Diameter = farthest(u, far)
+	}
+
+	var closed, triples float64
+	for _, n := range nodes {
+		adj := u.From(n)
+		k := float64(len(adj))
+		triples += k * (k - 1) / 2
+		for i, a := range adj {
+			for _, b := range adj[i+1:] {
+				if u.HasEdgeBetween(a, b) {
+					closed++
+				}
+			}
+		}
+	}
+	if triples != 0 {
+		s.Clustering = closed / triples
+	}
+
+	return s
+}
+
+// farthest returns a node at the greatest hop distance from n in g and that
+// distance.
+func farthest(g graph.Graph, n graph.Node) (graph.Node, int) {
+	var (
+		bf    traverse.BreadthFirst
+		far   = n
+		depth int
+	)
+	bf.Walk(g, n, func(n graph.Node, d int) bool {
+		if d > depth {
+			far, depth = n, d
+		}
+		return false
+	})
+	return far, depth
+}
+
+// String returns a multi-line human readable summary of the statistics.
+func (s Statistics) String() string {
+	var buf bytes.Buffer
+	kind := "undirected"
+	if s.Directed {
+		kind = "directed"
+	}
+	fmt.Fprintf(&buf, "%s graph: %d nodes, %d edges, density %.4g\n", kind, s.Nodes, s.Edges, s.Density)
+	fmt.Fprintf(&buf, "degree: min %d, mean %.4g, max %d\n", s.MinDegree, s.MeanDegree, s.MaxDegree)
+	fmt.Fprintf(&buf, "components: %d, diameter estimate: %d\n", s.Components, s.Diameter)
+	fmt.Fprintf(&buf, "clustering: %.4g", s.Clustering)
+	if s.Directed {
+		fmt.Fprintf(&buf, ", reciprocity: %.4g", s.Reciprocity)
+	}
+	return buf.String()
+}
@@ -0,0 +1,138 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+var summaryTests = []struct {
+	name     string
+	g        []set
+	directed bool
+
+	want Statistics
+}{
+	{
+		name: "path",
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(C),
+			C: linksTo(D),
+			D: nil,
+		},
+		want: Statistics{
+			Nodes: 4, Edges: 3, Density: 0.5,
+			MinDegree: 1, MeanDegree: 1.5, MaxDegree: 2,
+			Components: 1, Diameter: 3,
+			Clustering: 0, Reciprocity: math.NaN(),
+		},
+	},
+	{
+		name: "triangle and isolate",
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+			C: nil,
+			D: nil,
+		},
+		want: Statistics{
+			Nodes: 4, Edges: 3, Density: 0.5,
+			MinDegree: 0, MeanDegree: 1.5, MaxDegree: 2,
+			Components: 2, Diameter: 1,
+			Clustering: 1, Reciprocity: math.NaN(),
+		},
+	},
+	{
+		name: "star",
+		g: []set{
+			A: linksTo(B, C, D, E),
+			B: nil,
+			C: nil,
+			D: nil,
+			E: nil,
+		},
+		want: Statistics{
+			Nodes: 5, Edges: 4, Density: 0.4,
+			MinDegree: 1, MeanDegree: 1.6, MaxDegree: 4,
+			Components: 1, Diameter: 2,
+			Clustering: 0, Reciprocity: math.NaN(),
+		},
+	},
+	{
+		name: "directed",
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(A, C),
+			C: linksTo(A),
+			D: nil,
+		},
+		directed: true,
+		want: Statistics{
+			Directed: true,
+			Nodes:    4, Edges: 4, Density: 4.0 / 12,
+			MinDegree: 0, MeanDegree: 2, MaxDegree: 3,
+			Components: 2, Diameter: 1,
+			Clustering: 1, Reciprocity: 0.5,
+		},
+	},
+}
+
+func TestSummary(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range summaryTests {
+		var g graph.Builder
+		if test.directed {
+			g = simple.NewDirectedGraph(0, math.Inf(1))
+		} else {
+			g = simple.NewUndirectedGraph(0, math.Inf(1))
+		}
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if !g.(graph.Graph).Has(simple.Node(u)) {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+			}
+		}
+		got := Summary(g.(graph.Graph))
+		want := test.want
+		if got.Directed != want.Directed || got.Nodes != want.Nodes || got.Edges != want.Edges ||
+			got.MinDegree != want.MinDegree || got.MaxDegree != want.MaxDegree ||
+			got.Components != want.Components || got.Diameter != want.Diameter {
+			t.Errorf("%q: unexpected summary:\ngot: %+v\nwant:%+v", test.name, got, want)
+		}
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{name: "density", got: got.Density, want: want.Density},
+			{name: "mean degree", got: got.MeanDegree, want: want.MeanDegree},
+			{name: "clustering", got: got.Clustering, want: want.Clustering},
+			{name: "reciprocity", got: got.Reciprocity, want: want.Reciprocity},
+		} {
+			if math.IsNaN(v.want) {
+				if !math.IsNaN(v.got) {
+					t.Errorf("%q: unexpected %s: got:%v want:NaN", test.name, v.name, v.got)
+				}
+				continue
+			}
+			if math.Abs(v.got-v.want) > tol {
+				t.Errorf("%q: unexpected %s: got:%v want:%v", test.name, v.name, v.got, v.want)
+			}
+		}
+
+		s := got.String()
+		if strings.Contains(s, "reciprocity") != test.directed {
+			t.Errorf("%q: unexpected reciprocity reporting:\n%s", test.name, s)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Reciprocity returns the fraction of edges in g for which the reverse edge
+// also exists. If g has no edges, Reciprocity returns NaN.
+func Reciprocity(g graph.Directed) float64 {
+	var edges, reciprocal int
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			edges++
+			if g.HasEdgeFromTo(v, u) {
+				reciprocal++
+			}
+		}
+	}
+	if edges == 0 {
+		return math.NaN()
+	}
+	return float64(reciprocal) / float64(edges)
+}
+
+// DyadCensus returns the number of mutual, asymmetric and null dyads in g.
+// A dyad is an unordered pair of distinct nodes, and is mutual if the nodes
+// are joined by edges in both directions, asymmetric if they are joined by an
+// edge in one direction and null if they are not joined.
+func DyadCensus(g graph.Directed) (mutual, asymmetric, null int) {
+	nodes := g.Nodes()
+	for _, u := range nodes {
+		for _, v := range g.From(u) {
+			if u.ID() == v.ID() {
+				continue
+			}
+			if !g.HasEdgeFromTo(v, u) {
+				asymmetric++
+			} else if u.ID() < v.ID() {
+				mutual++
+			}
+		}
+	}
+	n := len(nodes)
+	null = n*(n-1)/2 - mutual - asymmetric
+	return mutual, asymmetric, null
+}
+
+// Triad is a triad isomorphism class in the MAN labeling of Holland and
+// Leinhardt. The digits of the label give the number of mutual, asymmetric
+// and null dyads in the triad, and the letters distinguish the orientation
+// of the triad's edges: D (down), U (up), C (cyclic) and T (transitive).
+type Triad int
+
+const (
+	Triad003 Triad = iota
+	Triad012
+	Triad102
+	Triad021D
+	Triad021U
+	Triad021C
+	Triad111D
+	Triad111U
+	Triad030T
+	Triad030C
+	Triad201
+	Triad120D
+	Triad120U
+	Triad120C
+	Triad210
+	Triad300
+)
+
+var triadNames = [...]string{
+	"003", "012", "102", "021D", "021U", "021C", "111D", "111U",
+	"030T", "030C", "201", "120D", "120U", "120C", "210", "300",
+}
+
+// String returns the MAN label of the triad class.
+func (t Triad) String() string {
+	if t < 0 || int(t) >= len(triadNames) {
+		return "invalid"
+	}
+	return triadNames[t]
+}
+
+// triadCodes maps the adjacency code of an ordered triple of nodes to its
+// triad class.
+var triadCodes = [64]Triad{
+	0, 1, 1, 2, 1, 3, 5, 7, 1, 5, 4, 6, 2, 7, 6, 10,
+	1, 5, 3, 7, 4, 8, 8, 12, 5, 9, 8, 13, 6, 13, 11, 14,
+	1, 4, 5, 6, 5, 8, 9, 13, 3, 8, 8, 11, 7, 12, 13, 14,
+	2, 6, 7, 10, 6, 11, 13, 14, 7, 13, 12, 14, 10, 14, 14, 15,
+}
+
+// TriadCensus returns the number of triads of each class in g, indexed by
+// Triad. Self edges are ignored.
+//
+// TriadCensus uses the subquadratic algorithm described by Batagelj and Mrvar
+// in doi:10.1016/S0378-8733(01)00035-1, which only enumerates connected triads.
+func TriadCensus(g graph.Directed) [16]int {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// neighbors holds the indices of the nodes
+	// adjacent to each node ignoring direction.
+	neighbors := make([][]int, len(nodes))
+	for i, u := range nodes {
+		seen := make(map[int]bool)
+		for _, ns := range [][]graph.Node{g.From(u), g.To(u)} {
+			for _, v := range ns {
+				j := indexOf[v.ID()]
+				if j != i && !seen[j] {
+					seen[j] = true
+					neighbors[i] = append(neighbors[i], j)
+				}
+			}
+		}
+		sort.Ints(neighbors[i])
+	}
+	adjacent := func(i, j int) bool {
+		return g.HasEdgeBetween(nodes[i], nodes[j])
+	}
+	code := func(v, u, w int) int {
+		var c int
+		for k, p := range [][2]int{{v, u}, {u, v}, {v, w}, {w, v}, {u, w}, {w, u}} {
+			if g.HasEdgeFromTo(nodes[p[0]], nodes[p[1]]) {
+				c |= 1 << uint(k)
+			}
+		}
+		return c
+	}
+
+	var census [16]int
+	n := len(nodes)
+	for v := range nodes {
+		for _, u := range neighbors[v] {
+			if u <= v {
+				continue
+			}
+			// s holds the nodes adjacent to
+			// either u or v, excluding u and v.
+			s := make(map[int]bool)
+			for _, w := range neighbors[u] {
+				s[w] = true
+			}
+			for _, w := range neighbors[v] {
+				s[w] = true
+			}
+			delete(s, u)
+			delete(s, v)
+
+			if g.HasEdgeFromTo(nodes[v], nodes[u]) && g.HasEdgeFromTo(nodes[u], nodes[v]) {
+				census[Triad102] += n - len(s) - 2
+			} else {
+				census[Triad012] += n - len(s) - 2
+			}
+			for w := range s {
+				if u < w || (v < w && w < u && !adjacent(v, w)) {
+					census[triadCodes[code(v, u, w)]]++
+				}
+			}
+		}
+	}
+
+	var connected int
+	for _, c := range census {
+		connected += c
+	}
+	census[Triad003] = n*(n-1)*(n-2)/6 - connected
+	return census
+}
@@ -0,0 +1,160 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func directedFrom(g []set) *simple.DirectedGraph {
+	dg := simple.NewDirectedGraph(0, math.Inf(1))
+	for u, e := range g {
+		// Add nodes that are not defined by an edge.
+		if !dg.Has(simple.Node(u)) {
+			dg.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			dg.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1})
+		}
+	}
+	return dg
+}
+
+var triadClassTests = []struct {
+	g    []set
+	want Triad
+}{
+	{g: []set{A: nil, B: nil, C: nil}, want: Triad003},
+	{g: []set{A: linksTo(B), B: nil, C: nil}, want: Triad012},
+	{g: []set{A: linksTo(B), B: linksTo(A), C: nil}, want: Triad102},
+	{g: []set{A: nil, B: linksTo(A, C), C: nil}, want: Triad021D},
+	{g: []set{A: linksTo(B), B: nil, C: linksTo(B)}, want: Triad021U},
+	{g: []set{A: linksTo(B), B: linksTo(C), C: nil}, want: Triad021C},
+	{g: []set{A: linksTo(B), B: linksTo(A), C: linksTo(B)}, want: Triad111D},
+	{g: []set{A: linksTo(B), B: linksTo(A, C), C: nil}, want: Triad111U},
+	{g: []set{A: linksTo(B, C), B: linksTo(C), C: nil}, want: Triad030T},
+	{g: []set{A: linksTo(B), B: linksTo(C), C: linksTo(A)}, want: Triad030C},
+	{g: []set{A: linksTo(B, C), B: linksTo(A), C: linksTo(A)}, want: Triad201},
+	{g: []set{A: linksTo(C), B: linksTo(A, C), C: linksTo(A)}, want: Triad120D},
+	{g: []set{A: linksTo(B, C), B: nil, C: linksTo(A, B)}, want: Triad120U},
+	{g: []set{A: linksTo(B, C), B: linksTo(C), C: linksTo(A)}, want: Triad120C},
+	{g: []set{A: linksTo(B, C), B: linksTo(A, C), C: linksTo(A)}, want: Triad210},
+	{g: []set{A: linksTo(B, C), B: linksTo(A, C), C: linksTo(A, B)}, want: Triad300},
+}
+
+func TestTriadCensusClasses(t *testing.T) {
+	for _, test := range triadClassTests {
+		census := TriadCensus(directedFrom(test.g))
+		for c, n := range census {
+			want := 0
+			if Triad(c) == test.want {
+				want = 1
+			}
+			if n != want {
+				t.Errorf("unexpected census for %v triad: got:%v", test.want, census)
+				break
+			}
+		}
+	}
+}
+
+func TestTriadCensus(t *testing.T) {
+	for _, p := range []float64{0, 0.05, 0.2, 0.5} {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for i := 0; i < 30; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		err := gen.Gnp(g, 30, p, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatalf("failed to generate graph: %v", err)
+		}
+		got := TriadCensus(g)
+		want := bruteTriadCensus(g)
+		if got != want {
+			t.Errorf("unexpected triad census for p=%v:\ngot: %v\nwant:%v", p, got, want)
+		}
+	}
+}
+
+func bruteTriadCensus(g graph.Directed) [16]int {
+	nodes := g.Nodes()
+	var census [16]int
+	for i, v := range nodes {
+		for j := i + 1; j < len(nodes); j++ {
+			for k := j + 1; k < len(nodes); k++ {
+				u, w := nodes[j], nodes[k]
+				var c uint
+				for b, p := range [][2]graph.Node{{v, u}, {u, v}, {v, w}, {w, v}, {u, w}, {w, u}} {
+					if g.HasEdgeFromTo(p[0], p[1]) {
+						c |= 1 << uint(b)
+					}
+				}
+				census[triadCodes[c]]++
+			}
+		}
+	}
+	return census
+}
+
+var reciprocityTests = []struct {
+	g []set
+
+	reciprocity              float64
+	mutual, asymmetric, null int
+}{
+	{
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(A, C),
+			C: nil,
+			D: nil,
+		},
+		reciprocity: 2.0 / 3,
+		mutual:      1, asymmetric: 1, null: 4,
+	},
+	{
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+			C: nil,
+		},
+		reciprocity: 0,
+		mutual:      0, asymmetric: 3, null: 0,
+	},
+	{
+		g: []set{
+			A: nil,
+			B: nil,
+		},
+		reciprocity: math.NaN(),
+		mutual:      0, asymmetric: 0, null: 1,
+	},
+}
+
+func TestReciprocity(t *testing.T) {
+	const tol = 1e-12
+	for i, test := range reciprocityTests {
+		g := directedFrom(test.g)
+		r := Reciprocity(g)
+		if math.IsNaN(test.reciprocity) {
+			if !math.IsNaN(r) {
+				t.Errorf("unexpected reciprocity for test %d: got:%v want:NaN", i, r)
+			}
+		} else if math.Abs(r-test.reciprocity) > tol {
+			t.Errorf("unexpected reciprocity for test %d: got:%v want:%v", i, r, test.reciprocity)
+		}
+		m, a, n := DyadCensus(g)
+		if m != test.mutual || a != test.asymmetric || n != test.null {
+			t.Errorf("unexpected dyad census for test %d: got:%d/%d/%d want:%d/%d/%d",
+				i, m, a, n, test.mutual, test.asymmetric, test.null)
+		}
+	}
+}
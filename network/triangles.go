@@ -0,0 +1,71 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"github.com/gonum/graph"
+	"github.com/gonum/matrix/mat64"
+)
+
+// Triangles returns the number of triangles in the undirected graph g. Self
+// edges are ignored.
+//
+// If g is backed by an adjacency matrix, such as simple.UndirectedMatrix,
+// the count is obtained from the square of the adjacency matrix, which is
+// calculated by BLAS and so benefits from an optimized BLAS implementation
+// registered with blas64.Use. Otherwise triangles are counted by
+// intersecting the neighborhoods of adjacent nodes.
+func Triangles(g graph.Undirected) int {
+	if _, ok := g.(interface {
+		Matrix() mat64.Matrix
+	}); ok {
+		return denseTriangles(g)
+	}
+
+	// Count each triangle once, at its lowest
+	// ID node, by following edges to higher IDs.
+	var n int
+	for _, u := range g.Nodes() {
+		higher := make(map[int]graph.Node)
+		for _, v := range g.From(u) {
+			if v.ID() > u.ID() {
+				higher[v.ID()] = v
+			}
+		}
+		for _, v := range higher {
+			for _, w := range g.From(v) {
+				if _, ok := higher[w.ID()]; ok && w.ID() > v.ID() {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+// denseTriangles returns the number of triangles in g as one sixth of the
+// trace of the cube of the adjacency matrix of g.
+func denseTriangles(g graph.Undirected) int {
+	nodes := g.Nodes()
+	a := mat64.NewDense(len(nodes), len(nodes), nil)
+	for i, u := range nodes {
+		row := a.RawRowView(i)
+		for j, v := range nodes {
+			if i != j && g.HasEdgeBetween(u, v) {
+				row[j] = 1
+			}
+		}
+	}
+	var a2 mat64.Dense
+	a2.Mul(a, a)
+	var trace float64
+	for i := range nodes {
+		ai, a2i := a.RawRowView(i), a2.RawRowView(i)
+		for j, v := range ai {
+			trace += v * a2i[j]
+		}
+	}
+	return int(trace+0.5) / 6
+}
@@ -0,0 +1,55 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func naiveTriangles(g graph.Undirected) int {
+	nodes := g.Nodes()
+	var n int
+	for i, u := range nodes {
+		for j := i + 1; j < len(nodes); j++ {
+			for k := j + 1; k < len(nodes); k++ {
+				v, w := nodes[j], nodes[k]
+				if g.HasEdgeBetween(u, v) && g.HasEdgeBetween(v, w) && g.HasEdgeBetween(u, w) {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+func TestTriangles(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n int
+		p float64
+	}{
+		{n: 1, p: 1}, {n: 3, p: 1}, {n: 10, p: 0.5}, {n: 30, p: 0.2}, {n: 30, p: 0.9},
+	} {
+		g := simple.NewUndirectedGraph(0, 0)
+		gen.Gnp(g, test.n, test.p, src)
+		want := naiveTriangles(g)
+		if got := Triangles(g); got != want {
+			t.Errorf("unexpected number of triangles for n=%d p=%v: got:%d want:%d", test.n, test.p, got, want)
+		}
+
+		m := simple.NewUndirectedMatrix(test.n, 0, 0, 0)
+		for _, e := range g.Edges() {
+			m.SetEdge(e)
+		}
+		if got := Triangles(m); got != want {
+			t.Errorf("unexpected number of triangles for matrix n=%d p=%v: got:%d want:%d", test.n, test.p, got, want)
+		}
+	}
+}
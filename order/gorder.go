@@ -0,0 +1,111 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package order
+
+import (
+	"container/heap"
+
+	"github.com/gonum/graph"
+)
+
+// Gorder returns the nodes of g in the greedy order described by Wei, Yu,
+// Lu and Lin in doi:10.1145/2882903.2915220. Nodes are placed one at a time,
+// each time choosing the unplaced node with the greatest score against the
+// last window placed nodes. The score of a pair of nodes is the number of
+// edges between them plus the number of their common in-neighbors. If
+// window is less than one, a window of 5 is used.
+//
+// When no unplaced node has a positive score, the highest degree unplaced
+// node is chosen. Ties are broken by node ID.
+func Gorder(g graph.Graph, window int) []graph.Node {
+	if window < 1 {
+		window = 5
+	}
+	byDeg := ByDegree(g)
+	in := func(n graph.Node) []graph.Node { return g.From(n) }
+	out := in
+	if d, ok := g.(graph.Directed); ok {
+		in = d.To
+		out = d.From
+	}
+
+	placed := make(map[int]bool, len(byDeg))
+	score := make(map[int]int)
+	var q gorderQueue
+
+	// update adds delta to the score of each unplaced
+	// node sharing an edge or an in-neighbor with v.
+	update := func(v graph.Node, delta int) {
+		add := func(u graph.Node) {
+			if u.ID() == v.ID() || placed[u.ID()] {
+				return
+			}
+			score[u.ID()] += delta
+			heap.Push(&q, gorderEntry{node: u, score: score[u.ID()]})
+		}
+		for _, u := range neighbors(g, v) {
+			add(u)
+		}
+		for _, x := range in(v) {
+			for _, u := range out(x) {
+				add(u)
+			}
+		}
+	}
+
+	perm := make([]graph.Node, 0, len(byDeg))
+	next := 0
+	for len(perm) < len(byDeg) {
+		var v graph.Node
+		for q.Len() != 0 {
+			e := heap.Pop(&q).(gorderEntry)
+			if e.score > 0 && !placed[e.node.ID()] && score[e.node.ID()] == e.score {
+				v = e.node
+				break
+			}
+		}
+		if v == nil {
+			for placed[byDeg[next].ID()] {
+				next++
+			}
+			v = byDeg[next]
+		}
+		placed[v.ID()] = true
+		delete(score, v.ID())
+		perm = append(perm, v)
+
+		update(v, 1)
+		if len(perm) > window {
+			update(perm[len(perm)-window-1], -1)
+		}
+	}
+	return perm
+}
+
+// gorderEntry is a score of a node at the time it was queued.
+type gorderEntry struct {
+	node  graph.Node
+	score int
+}
+
+// gorderQueue is a max-heap of scored nodes. Ties are broken by node ID.
+type gorderQueue []gorderEntry
+
+func (q gorderQueue) Len() int { return len(q) }
+func (q gorderQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+	return q[i].node.ID() < q[j].node.ID()
+}
+func (q gorderQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *gorderQueue) Push(x interface{}) { *q = append(*q, x.(gorderEntry)) }
+func (q *gorderQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	e := old[n]
+	*q = old[:n]
+	return e
+}
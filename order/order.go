@@ -0,0 +1,218 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package order provides node reordering functions that improve the memory
+// locality of graph algorithms.
+//
+// Each ordering function returns the nodes of a graph in a new order, and
+// Permute copies a graph into a destination with node IDs assigned by
+// position in such an order. Graph representations that store nodes and
+// edges in arrays indexed by ID then hold neighboring nodes close together.
+package order
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// Permute copies the nodes and edges of src to dst, replacing each node in
+// src with a simple.Node whose ID is the position of the node in perm. Edge
+// weights are retained. Permute will panic if perm does not hold each node
+// of src exactly once.
+func Permute(dst graph.Builder, src graph.Graph, perm []graph.Node) {
+	nodes := src.Nodes()
+	if len(perm) != len(nodes) {
+		panic("order: permutation length mismatch")
+	}
+	id := make(map[int]int, len(perm))
+	for i, n := range perm {
+		if !src.Has(n) {
+			panic("order: permutation node not in graph")
+		}
+		if _, dup := id[n.ID()]; dup {
+			panic("order: duplicate node in permutation")
+		}
+		id[n.ID()] = i
+	}
+	graph.CopyWith(dst, src, func(n graph.Node) graph.Node {
+		return simple.Node(id[n.ID()])
+	}, nil)
+}
+
+// Bandwidth returns the maximum difference in position in perm between the
+// terminal nodes of an edge of g.
+func Bandwidth(g graph.Graph, perm []graph.Node) int {
+	pos := make(map[int]int, len(perm))
+	for i, n := range perm {
+		pos[n.ID()] = i
+	}
+	var b int
+	for _, u := range perm {
+		for _, v := range g.From(u) {
+			d := pos[u.ID()] - pos[v.ID()]
+			if d < 0 {
+				d = -d
+			}
+			if d > b {
+				b = d
+			}
+		}
+	}
+	return b
+}
+
+// ByDegree returns the nodes of g ordered by decreasing degree, so that
+// high degree hub nodes are placed together. Ties are broken by node ID.
+// The degree of a node is its number of neighbors, ignoring edge direction.
+func ByDegree(g graph.Graph) []graph.Node {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	deg := degrees(g, nodes)
+	sort.Stable(byDegree{nodes: nodes, deg: deg, desc: true})
+	return nodes
+}
+
+// RCM returns the nodes of g in reverse Cuthill-McKee order. The order
+// tends to reduce the bandwidth of the adjacency matrix of g. Edge
+// direction is ignored.
+//
+// Each connected component is ordered by a breadth first search from a
+// pseudo-peripheral node, visiting the neighbors of each node in order of
+// increasing degree.
+func RCM(g graph.Graph) []graph.Node {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	deg := degrees(g, nodes)
+	byMinDegree := append([]graph.Node(nil), nodes...)
+	sort.Stable(byDegree{nodes: byMinDegree, deg: deg})
+
+	perm := make([]graph.Node, 0, len(nodes))
+	placed := make(map[int]bool, len(nodes))
+	for _, n := range byMinDegree {
+		if placed[n.ID()] {
+			continue
+		}
+		start := peripheral(g, n)
+		placed[start.ID()] = true
+		perm = append(perm, start)
+		for i := len(perm) - 1; i < len(perm); i++ {
+			next := neighbors(g, perm[i])
+			sort.Sort(ordered.ByID(next))
+			sort.Stable(byDegree{nodes: next, deg: deg})
+			for _, v := range next {
+				if !placed[v.ID()] {
+					placed[v.ID()] = true
+					perm = append(perm, v)
+				}
+			}
+		}
+	}
+	for i, j := 0, len(perm)-1; i < j; i, j = i+1, j-1 {
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
+
+// peripheral returns a pseudo-peripheral node in the connected component
+// of n found by repeated breadth first searches from the lowest degree
+// node of the last level, as described by George and Liu.
+func peripheral(g graph.Graph, n graph.Node) graph.Node {
+	ecc := -1
+	for {
+		levels := levelStructure(g, n)
+		if len(levels) <= ecc+1 {
+			return n
+		}
+		ecc = len(levels) - 1
+		last := levels[ecc]
+		best := last[0]
+		bestDeg := len(neighbors(g, best))
+		for _, v := range last[1:] {
+			d := len(neighbors(g, v))
+			if d < bestDeg || (d == bestDeg && v.ID() < best.ID()) {
+				best, bestDeg = v, d
+			}
+		}
+		if best.ID() == n.ID() {
+			return n
+		}
+		n = best
+	}
+}
+
+// levelStructure returns the breadth first levels of the connected
+// component of n, ignoring edge direction.
+func levelStructure(g graph.Graph, n graph.Node) [][]graph.Node {
+	seen := map[int]bool{n.ID(): true}
+	levels := [][]graph.Node{{n}}
+	for {
+		var next []graph.Node
+		for _, u := range levels[len(levels)-1] {
+			for _, v := range neighbors(g, u) {
+				if !seen[v.ID()] {
+					seen[v.ID()] = true
+					next = append(next, v)
+				}
+			}
+		}
+		if next == nil {
+			return levels
+		}
+		levels = append(levels, next)
+	}
+}
+
+// neighbors returns the nodes adjacent to n in g, ignoring edge direction.
+func neighbors(g graph.Graph, n graph.Node) []graph.Node {
+	d, ok := g.(graph.Directed)
+	if !ok {
+		return g.From(n)
+	}
+	from := d.From(n)
+	seen := make(map[int]bool, len(from))
+	nodes := make([]graph.Node, 0, len(from))
+	for _, v := range from {
+		if v.ID() != n.ID() && !seen[v.ID()] {
+			seen[v.ID()] = true
+			nodes = append(nodes, v)
+		}
+	}
+	for _, v := range d.To(n) {
+		if v.ID() != n.ID() && !seen[v.ID()] {
+			seen[v.ID()] = true
+			nodes = append(nodes, v)
+		}
+	}
+	return nodes
+}
+
+// degrees returns the number of neighbors of each node in g, ignoring
+// edge direction.
+func degrees(g graph.Graph, nodes []graph.Node) map[int]int {
+	deg := make(map[int]int, len(nodes))
+	for _, n := range nodes {
+		deg[n.ID()] = len(neighbors(g, n))
+	}
+	return deg
+}
+
+// byDegree sorts nodes by degree, increasing unless desc is true.
+type byDegree struct {
+	nodes []graph.Node
+	deg   map[int]int
+	desc  bool
+}
+
+func (b byDegree) Len() int { return len(b.nodes) }
+func (b byDegree) Less(i, j int) bool {
+	di, dj := b.deg[b.nodes[i].ID()], b.deg[b.nodes[j].ID()]
+	if b.desc {
+		return di > dj
+	}
+	return di < dj
+}
+func (b byDegree) Swap(i, j int) { b.nodes[i], b.nodes[j] = b.nodes[j], b.nodes[i] }
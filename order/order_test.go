@@ -0,0 +1,185 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package order
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+// shuffledGrid returns an r×c grid graph with randomly assigned node IDs.
+func shuffledGrid(r, c int, src *rand.Rand) graph.Undirected {
+	ids := src.Perm(r * c)
+	g := simple.NewUndirectedGraph(0, 0)
+	for _, id := range ids {
+		g.AddNode(simple.Node(id))
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			u := simple.Node(ids[i*c+j])
+			if j+1 < c {
+				g.SetEdge(simple.Edge{F: u, T: simple.Node(ids[i*c+j+1]), W: 1})
+			}
+			if i+1 < r {
+				g.SetEdge(simple.Edge{F: u, T: simple.Node(ids[(i+1)*c+j]), W: 1})
+			}
+		}
+	}
+	return g
+}
+
+func checkPermutation(t *testing.T, name string, g graph.Graph, perm []graph.Node) {
+	nodes := g.Nodes()
+	if len(perm) != len(nodes) {
+		t.Errorf("%s: unexpected permutation length: got:%d want:%d", name, len(perm), len(nodes))
+		return
+	}
+	seen := make(map[int]bool)
+	for _, n := range perm {
+		if !g.Has(n) || seen[n.ID()] {
+			t.Errorf("%s: invalid permutation: %v", name, perm)
+			return
+		}
+		seen[n.ID()] = true
+	}
+}
+
+func TestRCM(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, size := range [][2]int{{1, 1}, {1, 20}, {4, 25}, {10, 10}} {
+		r, c := size[0], size[1]
+		g := shuffledGrid(r, c, src)
+		perm := RCM(g)
+		checkPermutation(t, "RCM", g, perm)
+
+		// The bandwidth of a grid in row major
+		// order of its shorter side is optimal.
+		want := r
+		if c < r {
+			want = c
+		}
+		if got := Bandwidth(g, perm); got > want {
+			t.Errorf("unexpected RCM bandwidth of %d×%d grid: got:%d want:<=%d", r, c, got, want)
+		}
+	}
+}
+
+func TestRCMDisconnected(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, 0)
+	for _, e := range [][2]int{{0, 5}, {5, 2}, {1, 4}, {4, 3}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	g.AddNode(simple.Node(6))
+	perm := RCM(g)
+	checkPermutation(t, "RCM", g, perm)
+	if got := Bandwidth(g, perm); got != 1 {
+		t.Errorf("unexpected bandwidth: got:%d want:1", got)
+	}
+}
+
+func TestByDegree(t *testing.T) {
+	g := simple.NewDirectedGraph(0, 0)
+	for _, e := range [][2]int{{0, 1}, {2, 1}, {3, 1}, {1, 4}, {2, 4}, {5, 6}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	want := []int{1, 2, 4, 0, 3, 5, 6}
+	got := ByDegree(g)
+	for i, n := range got {
+		if n.ID() != want[i] {
+			t.Fatalf("unexpected order: got:%v want:%v", got, want)
+		}
+	}
+}
+
+func TestGorder(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+
+	// Two dense communities joined by a single edge
+	// with interleaved IDs.
+	g := simple.NewUndirectedGraph(0, 0)
+	for i := 0; i < 20; i++ {
+		for j := i + 2; j < 20; j += 2 {
+			if src.Float64() < 0.7 {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: 1})
+			}
+		}
+	}
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	for _, window := range []int{0, 1, 3} {
+		perm := Gorder(g, window)
+		checkPermutation(t, "Gorder", g, perm)
+		var switches int
+		for i := 1; i < len(perm); i++ {
+			if perm[i].ID()%2 != perm[i-1].ID()%2 {
+				switches++
+			}
+		}
+		if switches != 1 {
+			t.Errorf("unexpected Gorder with window %d mixing communities: %v", window, perm)
+		}
+	}
+
+	d := simple.NewDirectedGraph(0, 0)
+	gen.Gnp(d, 200, 0.02, src)
+	checkPermutation(t, "Gorder", d, Gorder(d, 5))
+}
+
+func TestPermute(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	g := simple.NewDirectedGraph(0, 0)
+	gen.Gnp(g, 50, 0.1, src)
+	perm := RCM(g)
+
+	dst := simple.NewDirectedGraph(0, 0)
+	Permute(dst, g, perm)
+	if len(dst.Nodes()) != len(perm) {
+		t.Fatalf("unexpected number of nodes: got:%d want:%d", len(dst.Nodes()), len(perm))
+	}
+	var edges int
+	for i, u := range perm {
+		for _, v := range g.From(u) {
+			edges++
+			var j int
+			for j = range perm {
+				if perm[j].ID() == v.ID() {
+					break
+				}
+			}
+			if !dst.HasEdgeFromTo(simple.Node(i), simple.Node(j)) {
+				t.Errorf("missing edge %d->%d mapped from %d->%d", i, j, u.ID(), v.ID())
+			}
+		}
+	}
+	var got int
+	for _, u := range dst.Nodes() {
+		got += len(dst.From(u))
+	}
+	if got != edges {
+		t.Errorf("unexpected number of edges: got:%d want:%d", got, edges)
+	}
+}
+
+func TestPermutePanics(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, 0)
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	for _, perm := range [][]graph.Node{
+		{simple.Node(0)},
+		{simple.Node(0), simple.Node(0)},
+		{simple.Node(0), simple.Node(2)},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for permutation %v", perm)
+				}
+			}()
+			Permute(simple.NewUndirectedGraph(0, 0), g, perm)
+		}()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"container/heap"
 
 	"github.com/gonum/graph"
+	"github.com/gonum/graph/instrument"
 	"github.com/gonum/graph/internal/set"
 )
 
@@ -24,9 +25,24 @@ import (
 // falling back to NullHeuristic otherwise. If the graph does not implement graph.Weighter,
 // UniformCost is used. AStar will panic if g has an A*-reachable negative edge weight.
 func AStar(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded int) {
+	path, expanded, _ = aStar(s, t, g, &options{heuristic: h})
+	return path, expanded
+}
+
+// AStarWith finds the A*-shortest path from s to t in g with the same semantics as AStar,
+// using the WithHeuristic, WithContext and WithStats options. If the context is done before
+// the search completes, the partial result of the search is returned with the context's
+// error. The Expanded, Relaxed and HeapOps fields of the Stats are filled.
+func AStarWith(s, t graph.Node, g graph.Graph, opts ...Option) (path Shortest, expanded int, err error) {
+	o := newOptions(opts)
+	return aStar(s, t, g, &o)
+}
+
+func aStar(s, t graph.Node, g graph.Graph, o *options) (path Shortest, expanded int, err error) {
 	if !g.Has(s) || !g.Has(t) {
-		return Shortest{from: s}, 0
+		return Shortest{from: s}, 0, nil
 	}
+	h := o.heuristic
 	var weight Weighting
 	if wg, ok := g.(graph.Weighter); ok {
 		weight = wg.Weight
@@ -41,18 +57,27 @@ func AStar(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded
 		}
 	}
 
-	path = newShortestFrom(s, g.Nodes())
+	path = newShortestFrom(s, g.Nodes(), weight)
 	tid := t.ID()
 
+	var st instrument.Stats
+	defer func() { o.report(st) }()
+
 	visited := make(set.Ints)
 	open := &aStarQueue{indexOf: make(map[int]int)}
 	heap.Push(open, aStarNode{node: s, gscore: 0, fscore: h(s, t)})
+	st.HeapOps++
 
 	for open.Len() != 0 {
+		if err := o.canceled(); err != nil {
+			return path, expanded, err
+		}
 		u := heap.Pop(open).(aStarNode)
+		st.HeapOps++
 		uid := u.node.ID()
 		i := path.indexOf[uid]
 		expanded++
+		st.Expanded++
 
 		if uid == tid {
 			break
@@ -65,6 +90,7 @@ func AStar(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded
 				continue
 			}
 			j := path.indexOf[vid]
+			st.Relaxed++
 
 			w, ok := weight(u.node, v)
 			if !ok {
@@ -77,14 +103,16 @@ func AStar(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded
 			if n, ok := open.node(vid); !ok {
 				path.set(j, g, i)
 				heap.Push(open, aStarNode{node: v, gscore: g, fscore: g + h(v, t)})
+				st.HeapOps++
 			} else if g < n.gscore {
 				path.set(j, g, i)
 				open.update(vid, g, g+h(v, t))
+				st.HeapOps++
 			}
 		}
 	}
 
-	return path, expanded
+	return path, expanded, nil
 }
 
 // NullHeuristic is an admissible, consistent heuristic that will not speed up computation.
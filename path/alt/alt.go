@@ -0,0 +1,339 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package alt provides landmark-based A* (ALT) shortest path queries.
+package alt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+// Strategy is a landmark selection strategy.
+type Strategy int
+
+const (
+	// Farthest selects each landmark as the node
+	// farthest from the landmarks already selected.
+	Farthest Strategy = iota
+
+	// Avoid selects each landmark as a leaf of a
+	// shortest-path tree from a random root in the
+	// region of the tree least well covered by the
+	// landmarks already selected, as described by
+	// Goldberg and Harrelson.
+	Avoid
+)
+
+// SelectLandmarks returns k landmarks for g chosen using the given strategy. If
+// g has fewer than k nodes, all the nodes of g are returned. If src is not nil
+// it is used as the random source, otherwise rand.Intn is used.
+func SelectLandmarks(g graph.Graph, k int, strategy Strategy, src *rand.Rand) []graph.Node {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	if k >= len(nodes) {
+		return nodes
+	}
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = src.Intn
+	}
+
+	var landmarks []graph.Node
+	isLandmark := make(map[int]bool)
+	switch strategy {
+	case Farthest:
+		// minDist holds the distance from each
+		// node to its closest landmark.
+		minDist := make(map[int]float64, len(nodes))
+		for _, n := range nodes {
+			minDist[n.ID()] = math.Inf(1)
+		}
+		next := nodes[rnd(len(nodes))]
+		for len(landmarks) < k {
+			landmarks = append(landmarks, next)
+			isLandmark[next.ID()] = true
+			pt := path.DijkstraFrom(next, undirected(g))
+			for _, n := range nodes {
+				minDist[n.ID()] = math.Min(minDist[n.ID()], pt.WeightTo(n))
+			}
+			max := -1.0
+			for _, n := range nodes {
+				if d := minDist[n.ID()]; d > max && !isLandmark[n.ID()] {
+					next, max = n, d
+				}
+			}
+		}
+	case Avoid:
+		var t *Table
+		for len(landmarks) < k {
+			var l graph.Node
+			for attempt := 0; attempt < len(nodes); attempt++ {
+				l = avoid(g, nodes[rnd(len(nodes))], t)
+				if !isLandmark[l.ID()] {
+					break
+				}
+			}
+			for isLandmark[l.ID()] {
+				// Fall back to a random node if the
+				// search repeatedly finds landmarks.
+				l = nodes[rnd(len(nodes))]
+			}
+			landmarks = append(landmarks, l)
+			isLandmark[l.ID()] = true
+			t = NewTable(g, landmarks)
+		}
+	default:
+		panic("alt: unknown landmark selection strategy")
+	}
+	return landmarks
+}
+
+// avoid returns a landmark chosen by the avoid strategy from a shortest-path
+// tree rooted at root given the existing landmark table t.
+func avoid(g graph.Graph, root graph.Node, t *Table) graph.Node {
+	pt := path.DijkstraFrom(root, g)
+	tree := simple.NewDirectedGraph(0, math.Inf(1))
+	pt.Tree(tree)
+
+	isLandmark := make(map[int]bool)
+	if t != nil {
+		for _, id := range t.landmarks {
+			isLandmark[id] = true
+		}
+	}
+
+	// size holds the sum of the heuristic error of nodes
+	// in each subtree, or zero if the subtree contains
+	// a landmark.
+	size := make(map[int]float64)
+	var sum func(n graph.Node) (float64, bool)
+	sum = func(n graph.Node) (float64, bool) {
+		s := pt.WeightTo(n)
+		if t != nil {
+			s -= t.HeuristicCost(root, n)
+		}
+		hasLandmark := isLandmark[n.ID()]
+		for _, c := range tree.From(n) {
+			cs, cl := sum(c)
+			s += cs
+			hasLandmark = hasLandmark || cl
+		}
+		if hasLandmark {
+			s = 0
+		}
+		size[n.ID()] = s
+		return s, hasLandmark
+	}
+	sum(root)
+
+	n := root
+	for {
+		children := tree.From(n)
+		if len(children) == 0 {
+			return n
+		}
+		sort.Sort(ordered.ByID(children))
+		next := children[0]
+		for _, c := range children[1:] {
+			if size[c.ID()] > size[next.ID()] {
+				next = c
+			}
+		}
+		n = next
+	}
+}
+
+// Table holds the distances between a set of landmarks and the nodes of a
+// graph. The distances provide lower bounds on the distance between any pair
+// of nodes by the triangle inequality. The bounds remain valid if edge weights
+// in the graph are increased or edges are removed, so a Table may be reused
+// while weights change dynamically as long as they do not fall below the
+// weights used to construct the table.
+type Table struct {
+	landmarks []int
+	indexOf   map[int]int
+
+	// from[l][i] holds the distance from
+	// landmark l to node i and to[l][i]
+	// holds the distance from node i to
+	// landmark l.
+	from, to [][]float64
+}
+
+// NewTable returns a landmark distance table for g using the provided
+// landmarks. If the graph does not implement graph.Weighter, path.UniformCost
+// is used. NewTable will panic if g has a negative edge weight.
+func NewTable(g graph.Graph, landmarks []graph.Node) *Table {
+	nodes := g.Nodes()
+	t := &Table{
+		indexOf: make(map[int]int, len(nodes)),
+		from:    make([][]float64, len(landmarks)),
+		to:      make([][]float64, len(landmarks)),
+	}
+	for i, n := range nodes {
+		t.indexOf[n.ID()] = i
+	}
+	_, isDirected := g.(graph.Directed)
+	for l, lm := range landmarks {
+		t.landmarks = append(t.landmarks, lm.ID())
+		fwd := path.DijkstraFrom(lm, g)
+		t.from[l] = make([]float64, len(nodes))
+		for i, n := range nodes {
+			t.from[l][i] = fwd.WeightTo(n)
+		}
+		if !isDirected {
+			t.to[l] = t.from[l]
+			continue
+		}
+		bwd := path.DijkstraFrom(lm, reversed{g.(graph.Directed)})
+		t.to[l] = make([]float64, len(nodes))
+		for i, n := range nodes {
+			t.to[l][i] = bwd.WeightTo(n)
+		}
+	}
+	return t
+}
+
+// Landmarks returns the IDs of the landmarks in the table.
+func (t *Table) Landmarks() []int {
+	return append([]int(nil), t.landmarks...)
+}
+
+// HeuristicCost returns a lower bound on the distance from x to y. It
+// satisfies the path.Heuristic type and is admissible and consistent for
+// use with path.AStar on the graph used to construct the table.
+func (t *Table) HeuristicCost(x, y graph.Node) float64 {
+	i, iok := t.indexOf[x.ID()]
+	j, jok := t.indexOf[y.ID()]
+	if !iok || !jok {
+		return 0
+	}
+	var h float64
+	for l := range t.landmarks {
+		// d(x,y) >= d(l,y) - d(l,x)
+		if a, b := t.from[l][j], t.from[l][i]; !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+			h = math.Max(h, a-b)
+		}
+		// d(x,y) >= d(x,l) - d(y,l)
+		if a, b := t.to[l][i], t.to[l][j]; !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+			h = math.Max(h, a-b)
+		}
+	}
+	return h
+}
+
+// Query returns a shortest path from s to u in g and the weight of the path,
+// using A* search guided by the landmark table. The graph g may differ from
+// the graph used to construct the table by having increased edge weights.
+// The number of nodes expanded by the search is also returned.
+func (t *Table) Query(g graph.Graph, s, u graph.Node) (p []graph.Node, weight float64, expanded int) {
+	pt, expanded := path.AStar(s, u, g, t.HeuristicCost)
+	p, weight = pt.To(u)
+	return p, weight, expanded
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t *Table) MarshalBinary() ([]byte, error) {
+	ids := make([]int, len(t.indexOf))
+	for id, i := range t.indexOf {
+		ids[i] = id
+	}
+
+	var buf bytes.Buffer
+	w := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+	w(int64(len(t.landmarks)))
+	w(int64(len(ids)))
+	for _, id := range t.landmarks {
+		w(int64(id))
+	}
+	for _, id := range ids {
+		w(int64(id))
+	}
+	for l := range t.landmarks {
+		w(t.from[l])
+		w(t.to[l])
+	}
+	return buf.Bytes(), nil
+}
+
+// errBadTable is returned when unmarshaling an invalid table encoding.
+var errBadTable = errors.New("alt: invalid table encoding")
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *Table) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	var nl, nn int64
+	if binary.Read(r, binary.LittleEndian, &nl) != nil || binary.Read(r, binary.LittleEndian, &nn) != nil {
+		return errBadTable
+	}
+	if nl < 0 || nn < 0 || (nl+nn)*8+2*nl*nn*8 != int64(r.Len()) {
+		return errBadTable
+	}
+	ids := make([]int64, nl+nn)
+	if binary.Read(r, binary.LittleEndian, ids) != nil {
+		return errBadTable
+	}
+	*t = Table{
+		landmarks: make([]int, nl),
+		indexOf:   make(map[int]int, nn),
+		from:      make([][]float64, nl),
+		to:        make([][]float64, nl),
+	}
+	for l := range t.landmarks {
+		t.landmarks[l] = int(ids[l])
+	}
+	for i, id := range ids[nl:] {
+		t.indexOf[int(id)] = i
+	}
+	for l := range t.landmarks {
+		t.from[l] = make([]float64, nn)
+		t.to[l] = make([]float64, nn)
+		if binary.Read(r, binary.LittleEndian, t.from[l]) != nil || binary.Read(r, binary.LittleEndian, t.to[l]) != nil {
+			return errBadTable
+		}
+	}
+	return nil
+}
+
+// reversed is a directed graph with the direction of edges reversed.
+type reversed struct {
+	graph.Directed
+}
+
+func (g reversed) From(n graph.Node) []graph.Node { return g.Directed.To(n) }
+func (g reversed) To(n graph.Node) []graph.Node   { return g.Directed.From(n) }
+func (g reversed) Edge(u, v graph.Node) graph.Edge {
+	e := g.Directed.Edge(v, u)
+	if e == nil {
+		return nil
+	}
+	return simple.Edge{F: u, T: v, W: e.Weight()}
+}
+func (g reversed) HasEdgeFromTo(u, v graph.Node) bool { return g.Directed.HasEdgeFromTo(v, u) }
+func (g reversed) Weight(x, y graph.Node) (w float64, ok bool) {
+	if wg, ok := g.Directed.(graph.Weighter); ok {
+		return wg.Weight(y, x)
+	}
+	return path.UniformCost(g.Directed)(y, x)
+}
+
+// undirected returns g as an undirected graph if it is directed.
+func undirected(g graph.Graph) graph.Graph {
+	if d, ok := g.(graph.Directed); ok {
+		return graph.Undirect{G: d}
+	}
+	return g
+}
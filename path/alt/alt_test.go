@@ -0,0 +1,113 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package alt
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+func randomDirected(n int, p float64, rnd *rand.Rand) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && rnd.Float64() < p {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: float64(rnd.Intn(10) + 1)})
+			}
+		}
+	}
+	return g
+}
+
+func grid(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			id := i*n + j
+			if j+1 < n {
+				g.SetEdge(simple.Edge{F: simple.Node(id), T: simple.Node(id + 1), W: float64(1 + (i+j)%3)})
+			}
+			if i+1 < n {
+				g.SetEdge(simple.Edge{F: simple.Node(id), T: simple.Node(id + n), W: float64(1 + (i*j)%4)})
+			}
+		}
+	}
+	return g
+}
+
+func TestQuery(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		g    graph.Graph
+	}{
+		{name: "directed", g: randomDirected(40, 0.08, rnd)},
+		{name: "grid", g: grid(10)},
+	} {
+		for _, strategy := range []Strategy{Farthest, Avoid} {
+			landmarks := SelectLandmarks(test.g, 4, strategy, rnd)
+			if len(landmarks) != 4 {
+				t.Fatalf("%s: unexpected number of landmarks: got:%d want:4", test.name, len(landmarks))
+			}
+			seen := make(map[int]bool)
+			for _, l := range landmarks {
+				if seen[l.ID()] {
+					t.Errorf("%s: duplicate landmark: %d", test.name, l.ID())
+				}
+				seen[l.ID()] = true
+			}
+			tab := NewTable(test.g, landmarks)
+			for _, s := range test.g.Nodes() {
+				pt := path.DijkstraFrom(s, test.g)
+				for _, u := range test.g.Nodes() {
+					want := pt.WeightTo(u)
+					if h := tab.HeuristicCost(s, u); h > want {
+						t.Errorf("%s: inadmissible heuristic from %d to %d: %v > %v", test.name, s.ID(), u.ID(), h, want)
+					}
+					_, got, _ := tab.Query(test.g, s, u)
+					if got != want {
+						t.Errorf("%s: unexpected weight from %d to %d: got:%v want:%v", test.name, s.ID(), u.ID(), got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTableMarshal(t *testing.T) {
+	g := randomDirected(20, 0.2, rand.New(rand.NewSource(1)))
+	tab := NewTable(g, SelectLandmarks(g, 3, Farthest, nil))
+	b, err := tab.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling table: %v", err)
+	}
+	var got Table
+	err = got.UnmarshalBinary(b)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling table: %v", err)
+	}
+	if !reflect.DeepEqual(got.Landmarks(), tab.Landmarks()) {
+		t.Errorf("unexpected landmarks: got:%v want:%v", got.Landmarks(), tab.Landmarks())
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.Nodes() {
+			if got.HeuristicCost(u, v) != tab.HeuristicCost(u, v) {
+				t.Fatalf("unexpected heuristic after round trip for %d-%d", u.ID(), v.ID())
+			}
+		}
+	}
+	if err := got.UnmarshalBinary(b[:len(b)-1]); err == nil {
+		t.Error("expected error for truncated table")
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+	"time"
+
+	"github.com/gonum/graph"
+)
+
+// ARAStar finds a path from s to t in g using the anytime repairing A* algorithm
+// of Likhachev, Gordon and Thrun, "ARA*: Anytime A* with Provable Bounds on
+// Sub-Optimality", NIPS 2003. A first path is found quickly by a search with the
+// heuristic h inflated by the given factor, which must not be less than one. The
+// inflation is then reduced in steps and the path improved, reusing the work of
+// earlier searches, until the path is optimal or the time budget has elapsed. A
+// budget that is not positive places no limit on the search. The first search
+// is always run to completion.
+//
+// ARAStar returns the best path found and a bound on its sub-optimality: the
+// weight of the returned path is at most bound times the weight of a shortest
+// path. The bound is one if the path is optimal and +Inf if t is not reachable
+// from s. The bound holds only if h is admissible.
+//
+// If h is nil, ARAStar will use the g.HeuristicCost method if g implements
+// HeuristicCoster, falling back to NullHeuristic otherwise. If the graph does
+// not implement graph.Weighter, UniformCost is used. ARAStar will panic if g has
+// an ARA*-reachable negative edge weight.
+func ARAStar(s, t graph.Node, g graph.Graph, h Heuristic, inflation float64, budget time.Duration) (path Shortest, bound float64) {
+	if inflation < 1 {
+		panic("ARA*: inflation less than one")
+	}
+	if !g.Has(s) || !g.Has(t) {
+		return Shortest{from: s}, math.Inf(1)
+	}
+	var deadline time.Time
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	if h == nil {
+		if g, ok := g.(HeuristicCoster); ok {
+			h = g.HeuristicCost
+		} else {
+			h = NullHeuristic
+		}
+	}
+
+	eps := inflation
+	a := newARAStar(s, t, g, weight, h, eps)
+
+	// Reduce the inflation in at most
+	// ten steps.
+	step := math.Max((inflation-1)/10, 0.1)
+
+	a.improve(eps, time.Time{})
+	path, bound = a.result(eps)
+	if math.IsInf(bound, 1) {
+		// The first search exhausted the
+		// graph without reaching t.
+		return path, bound
+	}
+	for eps > 1 && bound > 1 && (deadline.IsZero() || time.Now().Before(deadline)) {
+		eps = math.Max(1, eps-step)
+		a.reopen(eps)
+		if !a.improve(eps, deadline) {
+			break
+		}
+		path, bound = a.result(eps)
+	}
+	return path, bound
+}
+
+// araStar holds the state of an ARA* search.
+type araStar struct {
+	g      graph.Graph
+	weight Weighting
+	h      Heuristic
+
+	nodes   []graph.Node
+	indexOf map[int]int
+	s, t    int
+
+	gval   []float64
+	parent []int
+	hval   []float64
+
+	open     araQueue
+	inOpen   []bool
+	closed   []bool
+	incons   []int
+	inIncons []bool
+}
+
+func newARAStar(s, t graph.Node, g graph.Graph, weight Weighting, h Heuristic, eps float64) *araStar {
+	nodes := g.Nodes()
+	a := &araStar{
+		g:        g,
+		weight:   weight,
+		h:        h,
+		nodes:    nodes,
+		indexOf:  make(map[int]int, len(nodes)),
+		gval:     make([]float64, len(nodes)),
+		parent:   make([]int, len(nodes)),
+		hval:     make([]float64, len(nodes)),
+		inOpen:   make([]bool, len(nodes)),
+		closed:   make([]bool, len(nodes)),
+		inIncons: make([]bool, len(nodes)),
+	}
+	for i, n := range nodes {
+		a.indexOf[n.ID()] = i
+		a.gval[i] = math.Inf(1)
+		a.parent[i] = -1
+		a.hval[i] = math.NaN()
+	}
+	a.s = a.indexOf[s.ID()]
+	a.t = a.indexOf[t.ID()]
+	a.gval[a.s] = 0
+	a.inOpen[a.s] = true
+	heap.Push(&a.open, araNode{i: a.s, key: a.key(a.s, eps)})
+	return a
+}
+
+// heuristic returns the heuristic cost from the ith node to the target.
+func (a *araStar) heuristic(i int) float64 {
+	if math.IsNaN(a.hval[i]) {
+		a.hval[i] = a.h(a.nodes[i], a.nodes[a.t])
+	}
+	return a.hval[i]
+}
+
+func (a *araStar) key(i int, eps float64) float64 {
+	return a.gval[i] + eps*a.heuristic(i)
+}
+
+// minOpen returns the open node with the smallest key, or -1 if there is
+// no open node.
+func (a *araStar) minOpen(eps float64) int {
+	for a.open.Len() != 0 {
+		n := a.open[0]
+		if a.inOpen[n.i] && n.key == a.key(n.i, eps) {
+			return n.i
+		}
+		heap.Pop(&a.open)
+	}
+	return -1
+}
+
+// improve expands nodes until the path to the target is within eps of the
+// best possible path through the open nodes. It returns false if the
+// deadline passed first.
+func (a *araStar) improve(eps float64, deadline time.Time) bool {
+	for expanded := 0; ; expanded++ {
+		u := a.minOpen(eps)
+		if u < 0 || a.gval[a.t] <= a.key(u, eps) {
+			return true
+		}
+		if expanded%64 == 63 && !deadline.IsZero() && time.Now().After(deadline) {
+			return false
+		}
+		heap.Pop(&a.open)
+		a.inOpen[u] = false
+		a.closed[u] = true
+
+		un := a.nodes[u]
+		for _, v := range a.g.From(un) {
+			j := a.indexOf[v.ID()]
+			w, ok := a.weight(un, v)
+			if !ok {
+				panic("ARA*: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("ARA*: negative edge weight")
+			}
+			joint := a.gval[u] + w
+			if joint >= a.gval[j] {
+				continue
+			}
+			a.gval[j] = joint
+			a.parent[j] = u
+			if a.closed[j] {
+				if !a.inIncons[j] {
+					a.inIncons[j] = true
+					a.incons = append(a.incons, j)
+				}
+				continue
+			}
+			a.inOpen[j] = true
+			heap.Push(&a.open, araNode{i: j, key: a.key(j, eps)})
+		}
+	}
+}
+
+// reopen moves the inconsistent nodes to the open set, rebuilds the open
+// set with keys for eps and clears the closed set.
+func (a *araStar) reopen(eps float64) {
+	open := a.open[:0]
+	seen := make(map[int]bool)
+	for _, n := range a.open {
+		if a.inOpen[n.i] && !seen[n.i] {
+			seen[n.i] = true
+			open = append(open, araNode{i: n.i, key: a.key(n.i, eps)})
+		}
+	}
+	for _, i := range a.incons {
+		a.inIncons[i] = false
+		if !seen[i] {
+			seen[i] = true
+			a.inOpen[i] = true
+			open = append(open, araNode{i: i, key: a.key(i, eps)})
+		}
+	}
+	a.open = open
+	heap.Init(&a.open)
+	a.incons = a.incons[:0]
+	for i := range a.closed {
+		a.closed[i] = false
+	}
+}
+
+// result returns the current path to the target and its sub-optimality
+// bound after a search with inflation eps.
+func (a *araStar) result(eps float64) (Shortest, float64) {
+	path := newShortestFrom(a.nodes[a.s], a.nodes, a.weight)
+	for i, p := range a.parent {
+		if p >= 0 {
+			path.set(i, a.gval[i], p)
+		}
+	}
+	gt := a.gval[a.t]
+	if math.IsInf(gt, 1) {
+		return path, math.Inf(1)
+	}
+
+	// The weight of a shortest path is at least
+	// the smallest unweighted f-value of a node
+	// in the open or inconsistent sets.
+	lower := gt
+	for _, n := range a.open {
+		if a.inOpen[n.i] {
+			lower = math.Min(lower, a.gval[n.i]+a.heuristic(n.i))
+		}
+	}
+	for _, i := range a.incons {
+		lower = math.Min(lower, a.gval[i]+a.heuristic(i))
+	}
+	bound := eps
+	if lower > 0 {
+		bound = math.Min(eps, gt/lower)
+	} else if gt == 0 {
+		bound = 1
+	}
+	return path, math.Max(bound, 1)
+}
+
+// araNode is a node index with its key at the time it was queued.
+type araNode struct {
+	i   int
+	key float64
+}
+
+// araQueue is a min-heap of keyed node indices.
+type araQueue []araNode
+
+func (q araQueue) Len() int            { return len(q) }
+func (q araQueue) Less(i, j int) bool  { return q[i].key < q[j].key }
+func (q araQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *araQueue) Push(x interface{}) { *q = append(*q, x.(araNode)) }
+func (q *araQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,67 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+// maxARAStarSweep is the largest graph order for which
+// TestARAStar checks every inflation and budget.
+const maxARAStarSweep = 1000
+
+func TestARAStar(t *testing.T) {
+	for _, test := range aStarTests {
+		inflations := []float64{1, 2.5}
+		budgets := []time.Duration{0, time.Nanosecond}
+		if len(test.g.Nodes()) > maxARAStarSweep {
+			// Each search of a large graph takes seconds,
+			// so only the cheapest search is checked.
+			if testing.Short() {
+				continue
+			}
+			inflations = inflations[1:]
+			budgets = budgets[1:]
+		}
+
+		s, u := simple.Node(test.s), simple.Node(test.t)
+		want := DijkstraFrom(s, test.g).WeightTo(u)
+
+		for _, inflation := range inflations {
+			for _, budget := range budgets {
+				pt, bound := ARAStar(s, u, test.g, test.heuristic, inflation, budget)
+				p, cost := pt.To(u)
+				if math.IsInf(want, 1) {
+					if !math.IsInf(bound, 1) || p != nil {
+						t.Errorf("unexpected path for %q: got:%v with bound %v", test.name, p, bound)
+					}
+					continue
+				}
+				if !topo.IsPathIn(test.g, p) {
+					t.Errorf("got path that is not path in input graph for %q", test.name)
+				}
+				if p[0].ID() != test.s || p[len(p)-1].ID() != test.t {
+					t.Errorf("unexpected path terminals for %q: %v", test.name, p)
+				}
+				if bound < 1 || bound > inflation {
+					t.Errorf("unexpected bound for %q with inflation %v: got:%v", test.name, inflation, bound)
+				}
+				if cost < want || cost > bound*want+1e-9 {
+					t.Errorf("unexpected cost for %q with inflation %v and budget %v: got:%v want:%v with bound %v",
+						test.name, inflation, budget, cost, want, bound)
+				}
+				if budget == 0 && (bound != 1 || cost != want) {
+					t.Errorf("expected optimal path for %q with unlimited budget: got:%v want:%v with bound %v",
+						test.name, cost, want, bound)
+				}
+			}
+		}
+	}
+}
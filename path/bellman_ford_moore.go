@@ -4,11 +4,17 @@
 
 package path
 
-import "github.com/gonum/graph"
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gonum/graph"
+)
 
 // BellmanFordFrom returns a shortest-path tree for a shortest path from u to all nodes in
-// the graph g, or false indicating that a negative cycle exists in the graph. If the graph
-// does not implement graph.Weighter, UniformCost is used.
+// the graph g, or false indicating that a negative cycle exists in the graph. When a negative
+// cycle exists, it is described by the NegativeCycle method of the returned Shortest. If the
+// graph does not implement graph.Weighter, UniformCost is used.
 //
 // The time complexity of BellmanFordFrom is O(|V|.|E|).
 func BellmanFordFrom(u graph.Node, g graph.Graph) (path Shortest, ok bool) {
@@ -24,7 +30,7 @@ func BellmanFordFrom(u graph.Node, g graph.Graph) (path Shortest, ok bool) {
 
 	nodes := g.Nodes()
 
-	path = newShortestFrom(u, nodes)
+	path = newShortestFrom(u, nodes, weight)
 	path.dist[path.indexOf[u.ID()]] = 0
 
 	// TODO(kortschak): Consider adding further optimisations
@@ -58,6 +64,8 @@ func BellmanFordFrom(u graph.Node, g graph.Graph) (path Shortest, ok bool) {
 				panic("bellman-ford: unexpected invalid weight")
 			}
 			if path.dist[j]+w < path.dist[k] {
+				path.set(k, path.dist[j]+w, j)
+				path.negCycle = negativeCycle(path, k, weight)
 				return path, false
 			}
 		}
@@ -65,3 +73,55 @@ func BellmanFordFrom(u graph.Node, g graph.Graph) (path Shortest, ok bool) {
 
 	return path, true
 }
+
+// NegativeCycleError describes a negative cycle found in a graph.
+type NegativeCycleError struct {
+	// Cycle holds the nodes of the cycle
+	// in path order, with the first node
+	// repeated at the end.
+	Cycle []graph.Node
+
+	// Weight is the total weight of the
+	// edges of the cycle.
+	Weight float64
+}
+
+// Error returns a description of the cycle.
+func (e *NegativeCycleError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString("path: negative cycle: ")
+	for i, n := range e.Cycle {
+		if i != 0 {
+			buf.WriteString("->")
+		}
+		fmt.Fprint(&buf, n.ID())
+	}
+	fmt.Fprintf(&buf, " with weight %v", e.Weight)
+	return buf.String()
+}
+
+// negativeCycle returns the negative cycle in the shortest-path tree p that
+// includes or leads to the node at index k, whose distance has just been
+// reduced.
+func negativeCycle(p Shortest, k int, weight Weighting) *NegativeCycleError {
+	// Following the tree back |V| steps from k
+	// must end in the cycle.
+	for i := 0; i < len(p.nodes); i++ {
+		k = p.next[k]
+	}
+	idx := []int{k}
+	for i := p.next[k]; i != k; i = p.next[i] {
+		idx = append(idx, i)
+	}
+	idx = append(idx, k)
+
+	e := &NegativeCycleError{Cycle: make([]graph.Node, len(idx))}
+	for i, j := range idx {
+		e.Cycle[len(idx)-1-i] = p.nodes[j]
+	}
+	for i, u := range e.Cycle[:len(e.Cycle)-1] {
+		w, _ := weight(u, e.Cycle[i+1])
+		e.Weight += w
+	}
+	return e
+}
@@ -24,12 +24,17 @@ func TestBellmanFordFrom(t *testing.T) {
 		if test.HasNegativeCycle {
 			if ok {
 				t.Errorf("%q: expected negative cycle", test.Name)
+				continue
 			}
+			checkNegativeCycle(t, test.Name, pt.NegativeCycle(), g.(graph.Graph))
 			continue
 		}
 		if !ok {
 			t.Fatalf("%q: unexpected negative cycle", test.Name)
 		}
+		if err := pt.NegativeCycle(); err != nil {
+			t.Errorf("%q: unexpected negative cycle error: %v", test.Name, err)
+		}
 
 		if pt.From().ID() != test.Query.From().ID() {
 			t.Fatalf("%q: unexpected from node ID: got:%d want:%d", pt.From().ID(), test.Query.From().ID())
@@ -68,3 +73,35 @@ func TestBellmanFordFrom(t *testing.T) {
 		}
 	}
 }
+
+func checkNegativeCycle(t *testing.T, name string, err error, g graph.Graph) {
+	nc, ok := err.(*NegativeCycleError)
+	if !ok {
+		t.Errorf("%q: unexpected negative cycle error type: got:%T want:*NegativeCycleError", name, err)
+		return
+	}
+	c := nc.Cycle
+	if len(c) < 2 || c[0].ID() != c[len(c)-1].ID() {
+		t.Errorf("%q: negative cycle is not closed: %v", name, c)
+		return
+	}
+	weight := UniformCost(g)
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	}
+	var sum float64
+	for i, u := range c[:len(c)-1] {
+		w, ok := weight(u, c[i+1])
+		if !ok {
+			t.Errorf("%q: negative cycle contains missing edge %d->%d", name, u.ID(), c[i+1].ID())
+			return
+		}
+		sum += w
+	}
+	if sum != nc.Weight {
+		t.Errorf("%q: unexpected negative cycle weight: got:%v want:%v", name, nc.Weight, sum)
+	}
+	if sum >= 0 {
+		t.Errorf("%q: cycle weight is not negative: %v", name, sum)
+	}
+}
@@ -140,3 +140,14 @@ func BenchmarkAStarUndirectedmallWorld_100_5_20_2_Heur(b *testing.B) {
 	}
 	benchmarkAStarHeuristic(b, nswUndirected_100_5_20_2, h)
 }
+
+func BenchmarkFloydWarshallGnp_100_half(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FloydWarshall(gnpUndirected_100_half)
+	}
+}
+func BenchmarkFloydWarshallDistancesGnp_100_half(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FloydWarshallDistances(gnpUndirected_100_half)
+	}
+}
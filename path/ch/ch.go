@@ -0,0 +1,398 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ch provides contraction hierarchy point-to-point shortest path queries.
+package ch
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+)
+
+// witnessLimit is the maximum number of nodes settled during a witness
+// search. Reaching the limit may add unnecessary shortcuts but does not
+// affect the correctness of queries.
+const witnessLimit = 500
+
+// Hierarchy is a contraction hierarchy of a graph. A Hierarchy answers
+// point-to-point shortest path queries on the static graph it was built
+// from. The zero value is an empty hierarchy.
+type Hierarchy struct {
+	nodes   []graph.Node
+	indexOf map[int]int
+
+	// rank holds the contraction order
+	// position of each node.
+	rank []int
+
+	// up holds the edges from each node to
+	// higher ranked nodes and down holds the
+	// reversed edges to each node from higher
+	// ranked nodes.
+	up, down [][]arc
+
+	// mid holds the contracted node of each
+	// shortcut keyed by terminal indices.
+	mid map[[2]int]int
+}
+
+// arc is a weighted edge in a contraction hierarchy.
+type arc struct {
+	to int
+	w  float64
+}
+
+// Preprocess returns a contraction hierarchy for g. Nodes are contracted in
+// the given order, which must hold every node of g exactly once. If order is
+// nil, the order is chosen by a greedy edge difference heuristic. If the graph
+// does not implement graph.Weighter, path.UniformCost is used. Preprocess will
+// panic if g has a negative edge weight.
+//
+// Query times depend strongly on the contraction order; good orders contract
+// unimportant nodes, such as those on minor roads, first.
+func Preprocess(g graph.Graph, order []graph.Node) *Hierarchy {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	h := &Hierarchy{
+		nodes:   nodes,
+		indexOf: make(map[int]int, len(nodes)),
+		rank:    make([]int, len(nodes)),
+		up:      make([][]arc, len(nodes)),
+		down:    make([][]arc, len(nodes)),
+		mid:     make(map[[2]int]int),
+	}
+	for i, n := range nodes {
+		h.indexOf[n.ID()] = i
+		h.rank[i] = -1
+	}
+
+	c := contractor{
+		out:        make([]map[int]float64, len(nodes)),
+		in:         make([]map[int]float64, len(nodes)),
+		contracted: make([]bool, len(nodes)),
+		neighbors:  make([]int, len(nodes)),
+		dist:       make(map[int]float64),
+	}
+	for i := range nodes {
+		c.out[i] = make(map[int]float64)
+		c.in[i] = make(map[int]float64)
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			w, ok := weight(u, v)
+			if !ok {
+				panic("ch: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("ch: negative edge weight")
+			}
+			j := h.indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			c.setArc(i, j, w)
+		}
+	}
+
+	if order != nil {
+		if len(order) != len(nodes) {
+			panic("ch: order length mismatch")
+		}
+		for r, n := range order {
+			i, ok := h.indexOf[n.ID()]
+			if !ok || h.rank[i] >= 0 {
+				panic("ch: invalid contraction order")
+			}
+			h.contract(&c, i, r)
+		}
+		return h
+	}
+
+	q := make(priorityQueue, 0, len(nodes))
+	for i := range nodes {
+		q = append(q, prioritized{node: i, priority: c.priority(i)})
+	}
+	heap.Init(&q)
+	for r := 0; q.Len() != 0; {
+		n := heap.Pop(&q).(prioritized)
+		// Lazily update the priority of the node
+		// and requeue it if it is no longer the
+		// cheapest to contract.
+		p := c.priority(n.node)
+		if q.Len() != 0 && p > q[0].priority {
+			heap.Push(&q, prioritized{node: n.node, priority: p})
+			continue
+		}
+		h.contract(&c, n.node, r)
+		r++
+	}
+	return h
+}
+
+// contract contracts the node v, giving it the rank r, and records its
+// remaining edges in the hierarchy.
+func (h *Hierarchy) contract(c *contractor, v, r int) {
+	h.rank[v] = r
+	for _, s := range c.shortcuts(v) {
+		if c.setArc(s.from, s.to, s.w) {
+			h.mid[[2]int{s.from, s.to}] = v
+		}
+	}
+	for w, wt := range c.out[v] {
+		h.up[v] = append(h.up[v], arc{to: w, w: wt})
+		delete(c.in[w], v)
+		c.neighbors[w]++
+	}
+	for u, wt := range c.in[v] {
+		h.down[v] = append(h.down[v], arc{to: u, w: wt})
+		delete(c.out[u], v)
+		c.neighbors[u]++
+	}
+	c.out[v] = nil
+	c.in[v] = nil
+	c.contracted[v] = true
+}
+
+// Query returns a shortest path from s to t and the weight of the path. If
+// no path exists, Query returns a nil path and an infinite weight.
+func (h *Hierarchy) Query(s, t graph.Node) (p []graph.Node, weight float64) {
+	si, sok := h.indexOf[s.ID()]
+	ti, tok := h.indexOf[t.ID()]
+	if !sok || !tok {
+		return nil, math.Inf(1)
+	}
+	if si == ti {
+		return []graph.Node{h.nodes[si]}, 0
+	}
+
+	fwd := newSearch(si)
+	bwd := newSearch(ti)
+	best := math.Inf(1)
+	meet := -1
+	for fwd.q.Len() != 0 || bwd.q.Len() != 0 {
+		if fwd.min() >= best && bwd.min() >= best {
+			break
+		}
+		for _, dir := range []struct {
+			s, other *search
+			arcs     [][]arc
+		}{
+			{s: fwd, other: bwd, arcs: h.up},
+			{s: bwd, other: fwd, arcs: h.down},
+		} {
+			if dir.s.min() >= best {
+				continue
+			}
+			u, ok := dir.s.next()
+			if !ok {
+				continue
+			}
+			if d, ok := dir.other.dist[u]; ok && dir.s.dist[u]+d < best {
+				best = dir.s.dist[u] + d
+				meet = u
+			}
+			dir.s.relax(u, dir.arcs[u])
+		}
+	}
+	if meet < 0 {
+		return nil, math.Inf(1)
+	}
+
+	var idx []int
+	for u := meet; u != si; u = fwd.prev[u] {
+		idx = append(idx, u)
+	}
+	idx = append(idx, si)
+	for i, j := 0, len(idx)-1; i < j; i, j = i+1, j-1 {
+		idx[i], idx[j] = idx[j], idx[i]
+	}
+	for u := meet; u != ti; {
+		u = bwd.prev[u]
+		idx = append(idx, u)
+	}
+
+	p = []graph.Node{h.nodes[idx[0]]}
+	for k := 1; k < len(idx); k++ {
+		p = h.unpack(p, idx[k-1], idx[k])
+	}
+	return p, best
+}
+
+// unpack appends the nodes of the original path represented by the edge
+// from u to v, excluding u, to p.
+func (h *Hierarchy) unpack(p []graph.Node, u, v int) []graph.Node {
+	m, ok := h.mid[[2]int{u, v}]
+	if !ok {
+		return append(p, h.nodes[v])
+	}
+	p = h.unpack(p, u, m)
+	return h.unpack(p, m, v)
+}
+
+// search is one direction of a bidirectional Dijkstra search.
+type search struct {
+	dist    map[int]float64
+	prev    map[int]int
+	settled map[int]bool
+	q       priorityQueue
+}
+
+func newSearch(from int) *search {
+	return &search{
+		dist:    map[int]float64{from: 0},
+		prev:    make(map[int]int),
+		settled: make(map[int]bool),
+		q:       priorityQueue{{node: from}},
+	}
+}
+
+// min returns the smallest key in the queue.
+func (s *search) min() float64 {
+	for s.q.Len() != 0 && s.settled[s.q[0].node] {
+		heap.Pop(&s.q)
+	}
+	if s.q.Len() == 0 {
+		return math.Inf(1)
+	}
+	return s.q[0].priority
+}
+
+// next settles and returns the next node in the search.
+func (s *search) next() (int, bool) {
+	if s.min() == math.Inf(1) {
+		return -1, false
+	}
+	n := heap.Pop(&s.q).(prioritized)
+	s.settled[n.node] = true
+	return n.node, true
+}
+
+func (s *search) relax(u int, arcs []arc) {
+	for _, a := range arcs {
+		d := s.dist[u] + a.w
+		if old, ok := s.dist[a.to]; !ok || d < old {
+			s.dist[a.to] = d
+			s.prev[a.to] = u
+			heap.Push(&s.q, prioritized{node: a.to, priority: d})
+		}
+	}
+}
+
+// contractor holds the remaining graph during contraction.
+type contractor struct {
+	out, in    []map[int]float64
+	contracted []bool
+
+	// neighbors holds the number of
+	// contracted neighbors of each node.
+	neighbors []int
+
+	// dist is the working distance
+	// map for witness searches.
+	dist map[int]float64
+}
+
+// setArc sets the weight of the arc from u to v to w if it is lighter than
+// any existing arc, returning whether the arc was set.
+func (c *contractor) setArc(u, v int, w float64) bool {
+	if old, ok := c.out[u][v]; ok && old <= w {
+		return false
+	}
+	c.out[u][v] = w
+	c.in[v][u] = w
+	return true
+}
+
+type shortcut struct {
+	from, to int
+	w        float64
+}
+
+// shortcuts returns the shortcuts required to contract v.
+func (c *contractor) shortcuts(v int) []shortcut {
+	var s []shortcut
+	for u, wu := range c.in[v] {
+		var max float64
+		for w, ww := range c.out[v] {
+			if w != u && wu+ww > max {
+				max = wu + ww
+			}
+		}
+		c.witness(u, v, max)
+		for w, ww := range c.out[v] {
+			if w == u {
+				continue
+			}
+			if d, ok := c.dist[w]; !ok || d > wu+ww {
+				s = append(s, shortcut{from: u, to: w, w: wu + ww})
+			}
+		}
+	}
+	return s
+}
+
+// witness performs a bounded Dijkstra search from u avoiding v, leaving the
+// distances found in c.dist.
+func (c *contractor) witness(u, v int, max float64) {
+	for k := range c.dist {
+		delete(c.dist, k)
+	}
+	c.dist[u] = 0
+	settled := make(map[int]bool)
+	q := priorityQueue{{node: u}}
+	for q.Len() != 0 && len(settled) < witnessLimit {
+		n := heap.Pop(&q).(prioritized)
+		if settled[n.node] {
+			continue
+		}
+		if n.priority > max {
+			break
+		}
+		settled[n.node] = true
+		for w, wt := range c.out[n.node] {
+			if w == v {
+				continue
+			}
+			d := n.priority + wt
+			if old, ok := c.dist[w]; !ok || d < old {
+				c.dist[w] = d
+				heap.Push(&q, prioritized{node: w, priority: d})
+			}
+		}
+	}
+}
+
+// priority returns the contraction priority of v, the edge difference
+// plus the number of contracted neighbors.
+func (c *contractor) priority(v int) float64 {
+	return float64(len(c.shortcuts(v)) - len(c.in[v]) - len(c.out[v]) + c.neighbors[v])
+}
+
+type prioritized struct {
+	node     int
+	priority float64
+}
+
+// priorityQueue implements a no-dec priority queue.
+type priorityQueue []prioritized
+
+func (q priorityQueue) Len() int            { return len(q) }
+func (q priorityQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q priorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(n interface{}) { *q = append(*q, n.(prioritized)) }
+func (q *priorityQueue) Pop() interface{} {
+	t := *q
+	var n interface{}
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
@@ -0,0 +1,114 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+func randomGraph(g graph.Builder, n int, p float64, rnd *rand.Rand) {
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && rnd.Float64() < p {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: float64(rnd.Intn(10) + 1)})
+			}
+		}
+	}
+}
+
+func grid(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			id := i*n + j
+			if j+1 < n {
+				g.SetEdge(simple.Edge{F: simple.Node(id), T: simple.Node(id + 1), W: float64(1 + (i+j)%3)})
+			}
+			if i+1 < n {
+				g.SetEdge(simple.Edge{F: simple.Node(id), T: simple.Node(id + n), W: float64(1 + (i*j)%4)})
+			}
+		}
+	}
+	return g
+}
+
+type weightedGraph interface {
+	graph.Graph
+	graph.Weighter
+}
+
+func checkQueries(t *testing.T, name string, g weightedGraph, h *Hierarchy) {
+	for _, s := range g.Nodes() {
+		pt := path.DijkstraFrom(s, g)
+		for _, u := range g.Nodes() {
+			want := pt.WeightTo(u)
+			p, got := h.Query(s, u)
+			if got != want {
+				t.Errorf("%s: unexpected weight from %d to %d: got:%v want:%v", name, s.ID(), u.ID(), got, want)
+				continue
+			}
+			if math.IsInf(want, 1) {
+				if p != nil {
+					t.Errorf("%s: unexpected path for unreachable node: %v", name, p)
+				}
+				continue
+			}
+			if p[0].ID() != s.ID() || p[len(p)-1].ID() != u.ID() {
+				t.Errorf("%s: unexpected path terminals: %v", name, p)
+				continue
+			}
+			var sum float64
+			for i := 1; i < len(p); i++ {
+				w, ok := g.Weight(p[i-1], p[i])
+				if !ok {
+					t.Errorf("%s: path uses missing edge %d-%d", name, p[i-1].ID(), p[i].ID())
+				}
+				sum += w
+			}
+			if sum != want {
+				t.Errorf("%s: unexpected path weight from %d to %d: got:%v want:%v", name, s.ID(), u.ID(), sum, want)
+			}
+		}
+	}
+}
+
+func TestHierarchyDirected(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		randomGraph(g, 30, 0.1, rnd)
+		checkQueries(t, "directed", g, Preprocess(g, nil))
+	}
+}
+
+func TestHierarchyUndirected(t *testing.T) {
+	g := grid(8)
+	checkQueries(t, "grid", g, Preprocess(g, nil))
+
+	// An explicit order by ID is valid, if slow.
+	var order []graph.Node
+	for i := 0; i < 64; i++ {
+		order = append(order, simple.Node(i))
+	}
+	checkQueries(t, "grid ordered", g, Preprocess(g, order))
+}
+
+func TestHierarchyMissing(t *testing.T) {
+	g := grid(2)
+	h := Preprocess(g, nil)
+	p, w := h.Query(simple.Node(0), simple.Node(10))
+	if p != nil || !math.IsInf(w, 1) {
+		t.Errorf("unexpected result for missing node: path=%v weight=%v", p, w)
+	}
+}
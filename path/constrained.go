@@ -0,0 +1,133 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/gonum/graph"
+)
+
+// ConstrainedShortest returns a minimum weight path from s to t in g whose total
+// resource consumption does not exceed budget, along with the weight and resource
+// consumption of the path. If weight is nil, the graph's Weight method is used if
+// g implements graph.Weighter, otherwise UniformCost is used. If no path within
+// the budget exists, ConstrainedShortest returns a nil path and infinite weight
+// and resource values.
+//
+// ConstrainedShortest uses label-setting with dominance pruning; a partial path
+// to a node is discarded if another path to the same node has no greater weight
+// and no greater resource consumption. ConstrainedShortest will panic if g has
+// an s-reachable negative edge weight or negative resource value.
+//
+// The problem is NP-hard in general and the number of non-dominated labels may
+// grow exponentially, but is usually small in practice.
+func ConstrainedShortest(g graph.Graph, s, t graph.Node, weight, resource Weighting, budget float64) (path []graph.Node, w, r float64) {
+	if !g.Has(s) || !g.Has(t) {
+		return nil, math.Inf(1), math.Inf(1)
+	}
+	if weight == nil {
+		if wg, ok := g.(graph.Weighter); ok {
+			weight = wg.Weight
+		} else {
+			weight = UniformCost(g)
+		}
+	}
+
+	// labels holds the non-dominated labels
+	// settled or queued at each node.
+	labels := make(map[int][]*resourceLabel)
+	root := &resourceLabel{node: s}
+	labels[s.ID()] = []*resourceLabel{root}
+	q := resourceQueue{root}
+	for q.Len() != 0 {
+		l := heap.Pop(&q).(*resourceLabel)
+		if l.dominated {
+			continue
+		}
+		if l.node.ID() == t.ID() {
+			for p := l; p != nil; p = p.prev {
+				path = append(path, p.node)
+			}
+			reverse(path)
+			return path, l.weight, l.resource
+		}
+		for _, v := range g.From(l.node) {
+			dw, ok := weight(l.node, v)
+			if !ok {
+				panic("constrained shortest: unexpected invalid weight")
+			}
+			if dw < 0 {
+				panic("constrained shortest: negative edge weight")
+			}
+			dr, ok := resource(l.node, v)
+			if !ok {
+				panic("constrained shortest: unexpected invalid resource")
+			}
+			if dr < 0 {
+				panic("constrained shortest: negative edge resource")
+			}
+			next := &resourceLabel{node: v, weight: l.weight + dw, resource: l.resource + dr, prev: l}
+			if next.resource > budget {
+				continue
+			}
+
+			existing := labels[v.ID()]
+			dominated := false
+			for _, e := range existing {
+				if e.weight <= next.weight && e.resource <= next.resource {
+					dominated = true
+					break
+				}
+			}
+			if dominated {
+				continue
+			}
+			kept := existing[:0]
+			for _, e := range existing {
+				if next.weight <= e.weight && next.resource <= e.resource {
+					e.dominated = true
+					continue
+				}
+				kept = append(kept, e)
+			}
+			labels[v.ID()] = append(kept, next)
+			heap.Push(&q, next)
+		}
+	}
+	return nil, math.Inf(1), math.Inf(1)
+}
+
+// resourceLabel is a partial path in a resource constrained search.
+type resourceLabel struct {
+	node             graph.Node
+	weight, resource float64
+	prev             *resourceLabel
+
+	// dominated indicates the label has been
+	// dominated by a label found later.
+	dominated bool
+}
+
+// resourceQueue is a priority queue of labels ordered by weight and then
+// by resource consumption.
+type resourceQueue []*resourceLabel
+
+func (q resourceQueue) Len() int { return len(q) }
+func (q resourceQueue) Less(i, j int) bool {
+	if q[i].weight == q[j].weight {
+		return q[i].resource < q[j].resource
+	}
+	return q[i].weight < q[j].weight
+}
+func (q resourceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *resourceQueue) Push(n interface{}) { *q = append(*q, n.(*resourceLabel)) }
+func (q *resourceQueue) Pop() interface{} {
+	t := *q
+	var n interface{}
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
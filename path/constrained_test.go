@@ -0,0 +1,90 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// bruteConstrained returns the minimum weight of simple paths from s to t
+// within the resource budget.
+func bruteConstrained(g graph.Graph, s, t graph.Node, weight, resource Weighting, budget float64) float64 {
+	best := math.Inf(1)
+	visited := make(map[int]bool)
+	var walk func(u graph.Node, w, r float64)
+	walk = func(u graph.Node, w, r float64) {
+		if r > budget {
+			return
+		}
+		if u.ID() == t.ID() {
+			best = math.Min(best, w)
+			return
+		}
+		visited[u.ID()] = true
+		for _, v := range g.From(u) {
+			if visited[v.ID()] {
+				continue
+			}
+			dw, _ := weight(u, v)
+			dr, _ := resource(u, v)
+			walk(v, w+dw, r+dr)
+		}
+		visited[u.ID()] = false
+	}
+	walk(s, 0, 0)
+	return best
+}
+
+func TestConstrainedShortest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		res := make(map[[2]int]float64)
+		for u := 0; u < 9; u++ {
+			g.AddNode(simple.Node(u))
+		}
+		for u := 0; u < 9; u++ {
+			for v := 0; v < 9; v++ {
+				if u != v && rnd.Float64() < 0.3 {
+					g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: float64(rnd.Intn(10))})
+					res[[2]int{u, v}] = float64(rnd.Intn(10))
+				}
+			}
+		}
+		resource := func(x, y graph.Node) (float64, bool) {
+			r, ok := res[[2]int{x.ID(), y.ID()}]
+			return r, ok
+		}
+		for _, budget := range []float64{0, 5, 10, 20, math.Inf(1)} {
+			s, u := simple.Node(0), simple.Node(8)
+			want := bruteConstrained(g, s, u, g.Weight, resource, budget)
+			p, w, r := ConstrainedShortest(g, s, u, nil, resource, budget)
+			if w != want {
+				t.Errorf("test %d budget %v: unexpected weight: got:%v want:%v", i, budget, w, want)
+			}
+			if math.IsInf(want, 1) {
+				if p != nil {
+					t.Errorf("test %d budget %v: unexpected path: %v", i, budget, p)
+				}
+				continue
+			}
+			var sumW, sumR float64
+			for k := 1; k < len(p); k++ {
+				dw, _ := g.Weight(p[k-1], p[k])
+				dr, _ := resource(p[k-1], p[k])
+				sumW += dw
+				sumR += dr
+			}
+			if sumW != w || sumR != r || r > budget {
+				t.Errorf("test %d budget %v: inconsistent path: weight=%v/%v resource=%v/%v", i, budget, sumW, w, sumR, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/topo"
+)
+
+// DAGLongest returns a longest path in the directed acyclic graph g and its
+// weight. The path may start at any node. If g is not acyclic, the error
+// returned by topo.Sort is returned. If the graph does not implement
+// graph.Weighter, UniformCost is used.
+//
+// The time complexity of DAGLongest is O(|V|+|E|).
+func DAGLongest(g graph.Directed) (path []graph.Node, weight float64, err error) {
+	s, err := CriticalPath(g)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.Critical, s.Length, nil
+}
+
+// Schedule is the result of a critical path analysis of a project network.
+// Nodes of the network are events and edges are activities whose durations
+// are the edge weights.
+type Schedule struct {
+	// Length is the least time in
+	// which the project can be
+	// completed.
+	Length float64
+
+	// Critical is a critical path
+	// through the project network.
+	Critical []graph.Node
+
+	weight   Weighting
+	earliest map[int]float64
+	latest   map[int]float64
+}
+
+// Earliest returns the earliest time the event n can occur.
+func (s Schedule) Earliest(n graph.Node) float64 {
+	t, ok := s.earliest[n.ID()]
+	if !ok {
+		return math.NaN()
+	}
+	return t
+}
+
+// Latest returns the latest time the event n can occur without delaying
+// completion of the project.
+func (s Schedule) Latest(n graph.Node) float64 {
+	t, ok := s.latest[n.ID()]
+	if !ok {
+		return math.NaN()
+	}
+	return t
+}
+
+// Slack returns the amount of time the event n may be delayed without
+// delaying completion of the project.
+func (s Schedule) Slack(n graph.Node) float64 {
+	return s.Latest(n) - s.Earliest(n)
+}
+
+// EdgeSlack returns the amount of time the activity from u to v may be
+// delayed without delaying completion of the project. Activities with
+// zero slack are critical.
+func (s Schedule) EdgeSlack(u, v graph.Node) float64 {
+	w, ok := s.weight(u, v)
+	if !ok {
+		return math.NaN()
+	}
+	return s.Latest(v) - s.Earliest(u) - w
+}
+
+// CriticalPath performs a critical path analysis of the project network g,
+// returning the earliest and latest times of each event and a critical path
+// through the network. If g is not acyclic, the error returned by topo.Sort
+// is returned. If the graph does not implement graph.Weighter, UniformCost
+// is used.
+//
+// The time complexity of CriticalPath is O(|V|+|E|).
+func CriticalPath(g graph.Directed) (Schedule, error) {
+	order, err := topo.Sort(g)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	s := Schedule{
+		earliest: make(map[int]float64, len(order)),
+		latest:   make(map[int]float64, len(order)),
+	}
+	if wg, ok := g.(graph.Weighter); ok {
+		s.weight = wg.Weight
+	} else {
+		s.weight = UniformCost(g)
+	}
+	if len(order) == 0 {
+		return s, nil
+	}
+
+	// Forward pass: earliest event times.
+	for _, u := range order {
+		s.earliest[u.ID()] = 0
+	}
+	pred := make(map[int]graph.Node)
+	for _, u := range order {
+		for _, v := range g.From(u) {
+			w, ok := s.weight(u, v)
+			if !ok {
+				panic("path: unexpected invalid weight")
+			}
+			d := s.earliest[u.ID()] + w
+			e := s.earliest[v.ID()]
+			p, hasPred := pred[v.ID()]
+			if d > e || (d == e && hasPred && u.ID() < p.ID()) {
+				s.earliest[v.ID()] = d
+				pred[v.ID()] = u
+			}
+		}
+	}
+	end := order[0]
+	for _, n := range order {
+		if s.earliest[n.ID()] > s.earliest[end.ID()] {
+			end = n
+		}
+	}
+	s.Length = s.earliest[end.ID()]
+	for n := end; n != nil; n = pred[n.ID()] {
+		s.Critical = append(s.Critical, n)
+	}
+	reverse(s.Critical)
+
+	// Backward pass: latest event times.
+	for i := len(order) - 1; i >= 0; i-- {
+		u := order[i]
+		l := s.Length
+		for _, v := range g.From(u) {
+			w, _ := s.weight(u, v)
+			l = math.Min(l, s.latest[v.ID()]-w)
+		}
+		s.latest[u.ID()] = l
+	}
+
+	return s, nil
+}
@@ -0,0 +1,109 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+func TestDAGLongest(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(3), T: simple.Node(2), W: 1},
+		{F: simple.Node(2), T: simple.Node(4), W: 1},
+	} {
+		g.SetEdge(e)
+	}
+	p, w, err := DAGLongest(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := nodeIDs(p), []int{0, 1, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected path: got:%v want:%v", got, want)
+	}
+	if w != 3 {
+		t.Errorf("unexpected weight: got:%v want:3", w)
+	}
+
+	g.SetEdge(simple.Edge{F: simple.Node(4), T: simple.Node(0), W: 1})
+	_, _, err = DAGLongest(g)
+	if _, ok := err.(topo.Unorderable); !ok {
+		t.Errorf("expected topo.Unorderable error for cyclic graph: got:%v", err)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 3},
+		{F: simple.Node(0), T: simple.Node(2), W: 2},
+		{F: simple.Node(1), T: simple.Node(3), W: 4},
+		{F: simple.Node(2), T: simple.Node(3), W: 2},
+		{F: simple.Node(2), T: simple.Node(4), W: 3},
+		{F: simple.Node(3), T: simple.Node(5), W: 2},
+		{F: simple.Node(4), T: simple.Node(5), W: 1},
+	} {
+		g.SetEdge(e)
+	}
+
+	s, err := CriticalPath(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Length != 9 {
+		t.Errorf("unexpected length: got:%v want:9", s.Length)
+	}
+	if got, want := nodeIDs(s.Critical), []int{0, 1, 3, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected critical path: got:%v want:%v", got, want)
+	}
+
+	earliest := []float64{0, 3, 2, 7, 5, 9}
+	latest := []float64{0, 3, 5, 7, 8, 9}
+	for i := range earliest {
+		n := simple.Node(i)
+		if got := s.Earliest(n); got != earliest[i] {
+			t.Errorf("unexpected earliest time for %d: got:%v want:%v", i, got, earliest[i])
+		}
+		if got := s.Latest(n); got != latest[i] {
+			t.Errorf("unexpected latest time for %d: got:%v want:%v", i, got, latest[i])
+		}
+		if got, want := s.Slack(n), latest[i]-earliest[i]; got != want {
+			t.Errorf("unexpected slack for %d: got:%v want:%v", i, got, want)
+		}
+	}
+	for _, test := range []struct {
+		u, v  int
+		slack float64
+	}{
+		{u: 0, v: 1, slack: 0},
+		{u: 0, v: 2, slack: 3},
+		{u: 2, v: 3, slack: 3},
+		{u: 2, v: 4, slack: 3},
+		{u: 3, v: 5, slack: 0},
+	} {
+		if got := s.EdgeSlack(simple.Node(test.u), simple.Node(test.v)); got != test.slack {
+			t.Errorf("unexpected slack for %d->%d: got:%v want:%v", test.u, test.v, got, test.slack)
+		}
+	}
+	if !math.IsNaN(s.Earliest(simple.Node(-1))) {
+		t.Errorf("expected NaN earliest time for absent node")
+	}
+}
+
+func nodeIDs(p []graph.Node) []int {
+	ids := make([]int, len(p))
+	for i, n := range p {
+		ids[i] = n.ID()
+	}
+	return ids
+}
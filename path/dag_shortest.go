@@ -0,0 +1,88 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import "github.com/gonum/graph"
+
+// DAGShortestFrom returns a shortest-path tree for a shortest path from u to all nodes in
+// the graph g. If the subgraph of g reachable from u is acyclic, the paths are found by
+// relaxing edges in topological order and negative edge weights are permitted. Otherwise
+// DAGShortestFrom falls back to BellmanFordFrom and returns false if a negative cycle
+// exists in the graph. If the graph does not implement graph.Weighter, UniformCost is
+// used.
+//
+// The time complexity of DAGShortestFrom is O(|V|+|E|) when the reachable subgraph is
+// acyclic.
+func DAGShortestFrom(u graph.Node, g graph.Graph) (path Shortest, ok bool) {
+	if !g.Has(u) {
+		return Shortest{from: u}, true
+	}
+	order, acyclic := reachableOrder(u, g)
+	if !acyclic {
+		return BellmanFordFrom(u, g)
+	}
+
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	path = newShortestFrom(u, g.Nodes(), weight)
+	path.dist[path.indexOf[u.ID()]] = 0
+	for _, n := range order {
+		k := path.indexOf[n.ID()]
+		for _, v := range g.From(n) {
+			j := path.indexOf[v.ID()]
+			w, ok := weight(n, v)
+			if !ok {
+				panic("path: unexpected invalid weight")
+			}
+			joint := path.dist[k] + w
+			if joint < path.dist[j] {
+				path.set(j, joint, k)
+			}
+		}
+	}
+	return path, true
+}
+
+// reachableOrder returns the nodes of g reachable from u in topological order
+// and whether the reachable subgraph is acyclic.
+func reachableOrder(u graph.Node, g graph.Graph) (order []graph.Node, acyclic bool) {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	type frame struct {
+		node graph.Node
+		next []graph.Node
+	}
+
+	state := map[int]int{u.ID(): active}
+	stack := []frame{{node: u, next: g.From(u)}}
+	for len(stack) != 0 {
+		top := &stack[len(stack)-1]
+		if len(top.next) == 0 {
+			state[top.node.ID()] = done
+			order = append(order, top.node)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		v := top.next[0]
+		top.next = top.next[1:]
+		switch state[v.ID()] {
+		case active:
+			return nil, false
+		case unvisited:
+			state[v.ID()] = active
+			stack = append(stack, frame{node: v, next: g.From(v)})
+		}
+	}
+	reverse(order)
+	return order, true
+}
@@ -0,0 +1,88 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path/internal/testgraphs"
+	"github.com/gonum/graph/simple"
+)
+
+func TestDAGShortestFrom(t *testing.T) {
+	for _, test := range testgraphs.ShortestPathTests {
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetEdge(e)
+		}
+
+		pt, ok := DAGShortestFrom(test.Query.From(), g.(graph.Graph))
+		want, wantOK := BellmanFordFrom(test.Query.From(), g.(graph.Graph))
+		if ok != wantOK {
+			t.Errorf("%q: unexpected ok: got:%t want:%t", test.Name, ok, wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, n := range g.(graph.Graph).Nodes() {
+			if got, want := pt.WeightTo(n), want.WeightTo(n); got != want {
+				t.Errorf("%q: unexpected weight to %d: got:%v want:%v", test.Name, n.ID(), got, want)
+			}
+		}
+
+		var got []int
+		p, _ := pt.To(test.Query.To())
+		for _, n := range p {
+			got = append(got, n.ID())
+		}
+		ok = len(got) == 0 && len(test.WantPaths) == 0
+		for _, sp := range test.WantPaths {
+			if reflect.DeepEqual(got, sp) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			t.Errorf("%q: unexpected shortest path:\ngot: %v\nwant from:%v",
+				test.Name, p, test.WantPaths)
+		}
+	}
+}
+
+func TestDAGShortestFromNegative(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 5},
+		{F: simple.Node(0), T: simple.Node(2), W: 3},
+		{F: simple.Node(1), T: simple.Node(3), W: -6},
+		{F: simple.Node(2), T: simple.Node(3), W: 1},
+		{F: simple.Node(3), T: simple.Node(4), W: 2},
+		// A cycle not reachable from 0.
+		{F: simple.Node(5), T: simple.Node(6), W: 1},
+		{F: simple.Node(6), T: simple.Node(5), W: 1},
+		{F: simple.Node(5), T: simple.Node(4), W: 1},
+	} {
+		g.SetEdge(e)
+	}
+
+	pt, ok := DAGShortestFrom(simple.Node(0), g)
+	if !ok {
+		t.Fatal("unexpected negative cycle")
+	}
+	p, w := pt.To(simple.Node(4))
+	if got, want := nodeIDs(p), []int{0, 1, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected path: got:%v want:%v", got, want)
+	}
+	if w != 1 {
+		t.Errorf("unexpected weight: got:%v want:1", w)
+	}
+	if w := pt.WeightTo(simple.Node(5)); !math.IsInf(w, 1) {
+		t.Errorf("unexpected weight to unreachable node: got:%v want:+Inf", w)
+	}
+}
@@ -6,8 +6,10 @@ package path
 
 import (
 	"container/heap"
+	"sync"
 
 	"github.com/gonum/graph"
+	"github.com/gonum/graph/instrument"
 )
 
 // DijkstraFrom returns a shortest-path tree for a shortest path from u to all nodes in
@@ -27,7 +29,7 @@ func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
 	}
 
 	nodes := g.Nodes()
-	path := newShortestFrom(u, nodes)
+	path := newShortestFrom(u, nodes, weight)
 
 	// Dijkstra's algorithm here is implemented essentially as
 	// described in Function B.2 in figure 6 of UTCS Technical
@@ -73,15 +75,29 @@ func DijkstraFrom(u graph.Node, g graph.Graph) Shortest {
 // The time complexity of DijkstrAllPaths is O(|V|.|E|+|V|^2.log|V|).
 func DijkstraAllPaths(g graph.Graph) (paths AllShortest) {
 	paths = newAllShortest(g.Nodes(), false)
-	dijkstraAllPaths(g, paths)
+	dijkstraAllPaths(g, paths, &options{workers: 1})
 	return paths
 }
 
+// DijkstraAllPathsWith returns a shortest-path tree for shortest paths in the graph g
+// with the same semantics as DijkstraAllPaths, using the WithWorkers, WithContext and
+// WithStats options. When more than one worker is used, g must be safe for concurrent
+// reads. If the context is done before the search completes, the partial result of the
+// search is returned with the context's error. The Expanded, Relaxed and HeapOps fields
+// of the Stats are filled.
+func DijkstraAllPathsWith(g graph.Graph, opts ...Option) (paths AllShortest, err error) {
+	o := newOptions(opts)
+	paths = newAllShortest(g.Nodes(), false)
+	err = dijkstraAllPaths(g, paths, &o)
+	return paths, err
+}
+
 // dijkstraAllPaths is the all-paths implementation of Dijkstra. It is shared
 // between DijkstraAllPaths and JohnsonAllPaths to avoid repeated allocation
-// of the nodes slice and the indexOf map. It returns nothing, but stores the
-// result of the work in the paths parameter which is a reference type.
-func dijkstraAllPaths(g graph.Graph, paths AllShortest) {
+// of the nodes slice and the indexOf map. It stores the result of the work in
+// the paths parameter which is a reference type, and returns the error of the
+// options' context if the work is cancelled.
+func dijkstraAllPaths(g graph.Graph, paths AllShortest, o *options) error {
 	var weight Weighting
 	if wg, ok := g.(graph.Weighter); ok {
 		weight = wg.Weight
@@ -89,39 +105,113 @@ func dijkstraAllPaths(g graph.Graph, paths AllShortest) {
 		weight = UniformCost(g)
 	}
 
-	var Q priorityQueue
-	for i, u := range paths.nodes {
-		// Dijkstra's algorithm here is implemented essentially as
-		// described in Function B.2 in figure 6 of UTCS Technical
-		// Report TR-07-54 with the addition of handling multiple
-		// co-equal paths.
-		//
-		// http://www.cs.utexas.edu/ftp/techreports/tr07-54.pdf
-
-		// Q must be empty at this point.
-		heap.Push(&Q, distanceNode{node: u, dist: 0})
-		for Q.Len() != 0 {
-			mid := heap.Pop(&Q).(distanceNode)
-			k := paths.indexOf[mid.node.ID()]
-			if mid.dist < paths.dist.At(i, k) {
-				paths.dist.Set(i, k, mid.dist)
+	if o.workers <= 1 {
+		var (
+			Q  priorityQueue
+			st instrument.Stats
+		)
+		defer func() { o.report(st) }()
+		for i := range paths.nodes {
+			if err := o.canceled(); err != nil {
+				return err
 			}
-			for _, v := range g.From(mid.node) {
-				j := paths.indexOf[v.ID()]
-				w, ok := weight(mid.node, v)
-				if !ok {
-					panic("dijkstra: unexpected invalid weight")
-				}
-				if w < 0 {
-					panic("dijkstra: negative edge weight")
-				}
-				joint := paths.dist.At(i, k) + w
-				if joint < paths.dist.At(i, j) {
-					heap.Push(&Q, distanceNode{node: v, dist: joint})
-					paths.set(i, j, joint, k)
-				} else if joint == paths.dist.At(i, j) {
-					paths.add(i, j, k)
+			dijkstraAllPathsFrom(g, weight, paths, i, &Q, &st)
+		}
+		return nil
+	}
+
+	// Each source is searched by one of the workers.
+	// The rows of paths written by each search are
+	// distinct, so no locking is needed. A panic in
+	// a worker is passed back to the caller.
+	var (
+		wg        sync.WaitGroup
+		once      sync.Once
+		recovered interface{}
+		work      = make(chan int)
+
+		mu    sync.Mutex
+		total instrument.Stats
+	)
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					once.Do(func() { recovered = r })
+					for range work {
+					}
 				}
+			}()
+			var (
+				Q  priorityQueue
+				st instrument.Stats
+			)
+			defer func() {
+				mu.Lock()
+				total.Add(st)
+				mu.Unlock()
+			}()
+			for i := range work {
+				dijkstraAllPathsFrom(g, weight, paths, i, &Q, &st)
+			}
+		}()
+	}
+	var err error
+	for i := range paths.nodes {
+		if err = o.canceled(); err != nil {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	o.report(total)
+	if recovered != nil {
+		panic(recovered)
+	}
+	return err
+}
+
+// dijkstraAllPathsFrom finds the shortest paths from the ith node of paths
+// using the empty priority queue Q, adding the work done to st.
+func dijkstraAllPathsFrom(g graph.Graph, weight Weighting, paths AllShortest, i int, Q *priorityQueue, st *instrument.Stats) {
+	// Dijkstra's algorithm here is implemented essentially as
+	// described in Function B.2 in figure 6 of UTCS Technical
+	// Report TR-07-54 with the addition of handling multiple
+	// co-equal paths.
+	//
+	// http://www.cs.utexas.edu/ftp/techreports/tr07-54.pdf
+
+	// Q must be empty at this point.
+	heap.Push(Q, distanceNode{node: paths.nodes[i], dist: 0})
+	st.HeapOps++
+	for Q.Len() != 0 {
+		mid := heap.Pop(Q).(distanceNode)
+		st.HeapOps++
+		st.Expanded++
+		k := paths.indexOf[mid.node.ID()]
+		if mid.dist < paths.dist.At(i, k) {
+			paths.dist.Set(i, k, mid.dist)
+		}
+		for _, v := range g.From(mid.node) {
+			st.Relaxed++
+			j := paths.indexOf[v.ID()]
+			w, ok := weight(mid.node, v)
+			if !ok {
+				panic("dijkstra: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("dijkstra: negative edge weight")
+			}
+			joint := paths.dist.At(i, k) + w
+			if joint < paths.dist.At(i, j) {
+				heap.Push(Q, distanceNode{node: v, dist: joint})
+				st.HeapOps++
+				paths.set(i, j, joint, k)
+			} else if joint == paths.dist.At(i, j) {
+				paths.add(i, j, k)
 			}
 		}
 	}
@@ -6,6 +6,7 @@ package path
 
 import (
 	"math"
+	"math/rand"
 	"reflect"
 	"sort"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/gonum/graph"
 	"github.com/gonum/graph/internal/ordered"
 	"github.com/gonum/graph/path/internal/testgraphs"
+	"github.com/gonum/graph/simple"
 )
 
 func TestDijkstraFrom(t *testing.T) {
@@ -176,3 +178,161 @@ func TestDijkstraAllPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestShortestTree(t *testing.T) {
+	for _, test := range testgraphs.ShortestPathTests {
+		if test.HasNegativeWeight {
+			continue
+		}
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetEdge(e)
+		}
+
+		pt := DijkstraFrom(test.Query.From(), g.(graph.Graph))
+		tree := simple.NewDirectedGraph(0, math.Inf(1))
+		pt.Tree(tree)
+
+		for _, n := range g.(graph.Graph).Nodes() {
+			want, weight := pt.To(n)
+			if want == nil {
+				if tree.Has(n) {
+					t.Errorf("%q: unexpected unreachable node %d in tree", test.Name, n.ID())
+				}
+				continue
+			}
+			got := []graph.Node{n}
+			var gotWeight float64
+			for u := n; u.ID() != pt.From().ID(); {
+				parents := tree.To(u)
+				if len(parents) != 1 {
+					t.Fatalf("%q: unexpected number of parents for node %d: %d", test.Name, u.ID(), len(parents))
+				}
+				w, _ := tree.Weight(parents[0], u)
+				gotWeight += w
+				u = parents[0]
+				got = append(got, u)
+			}
+			reverse(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%q: unexpected tree path to %d:\ngot: %v\nwant:%v", test.Name, n.ID(), got, want)
+			}
+			if math.Abs(gotWeight-weight) > 1e-12 {
+				t.Errorf("%q: unexpected tree path weight to %d: got:%f want:%f", test.Name, n.ID(), gotWeight, weight)
+			}
+		}
+	}
+}
+
+func TestShortestTreeWeights(t *testing.T) {
+	// The path weight to 2 is not exactly representable,
+	// so its difference from the path weight to 1 is not
+	// the weight of the edge from 1 to 2.
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 0.1})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 0.2})
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(3), W: 0.2})
+	g.SetEdge(simple.Edge{F: simple.Node(3), T: simple.Node(2), W: 0.2})
+
+	for _, pt := range []Shortest{
+		DijkstraFrom(simple.Node(0), g),
+		func() Shortest { pt, _ := BellmanFordFrom(simple.Node(0), g); return pt }(),
+	} {
+		tree := simple.NewDirectedGraph(0, math.Inf(1))
+		pt.Tree(tree)
+		for _, v := range tree.Nodes() {
+			for _, u := range tree.To(v) {
+				got, _ := tree.Weight(u, v)
+				want, _ := g.Weight(u, v)
+				if got != want {
+					t.Errorf("unexpected weight for tree edge %d->%d: got:%v want:%v", u.ID(), v.ID(), got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestBetweenRand(t *testing.T) {
+	// A ladder of diamonds has many shortest
+	// paths from end to end.
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for i := 0; i < 10; i += 3 {
+		for _, e := range []simple.Edge{
+			{F: simple.Node(i), T: simple.Node(i + 1), W: 1},
+			{F: simple.Node(i), T: simple.Node(i + 2), W: 1},
+			{F: simple.Node(i + 1), T: simple.Node(i + 3), W: 1},
+			{F: simple.Node(i + 2), T: simple.Node(i + 3), W: 1},
+		} {
+			g.SetEdge(e)
+		}
+	}
+	pt := DijkstraAllPaths(g)
+
+	choose := func(seed int64) [][]graph.Node {
+		src := rand.New(rand.NewSource(seed))
+		var paths [][]graph.Node
+		for i := 0; i < 20; i++ {
+			p, weight, unique := pt.BetweenRand(simple.Node(0), simple.Node(12), src)
+			if weight != 8 || unique {
+				t.Fatalf("unexpected path: got:%v weight:%v unique:%t", p, weight, unique)
+			}
+			paths = append(paths, p)
+		}
+		return paths
+	}
+	first := choose(1)
+	if second := choose(1); !reflect.DeepEqual(first, second) {
+		t.Errorf("paths not reproducible:\nfirst: %v\nsecond:%v", first, second)
+	}
+
+	// PathBetweenRand makes the same choices
+	// as BetweenRand given the same source.
+	src := rand.New(rand.NewSource(1))
+	for i, want := range first {
+		p, _, _ := pt.PathBetweenRand(simple.Node(0), simple.Node(12), src)
+		for j, id := range p {
+			if id != want[j].ID() {
+				t.Fatalf("unexpected path %d: got:%v want:%v", i, p, want)
+			}
+		}
+	}
+}
+
+func TestReachable(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.AddNode(simple.Node(2))
+
+	pt := DijkstraFrom(simple.Node(0), g)
+	all := DijkstraAllPaths(g)
+	for _, test := range []struct {
+		to   graph.Node
+		want error
+	}{
+		{to: simple.Node(0), want: nil},
+		{to: simple.Node(1), want: nil},
+		{to: simple.Node(2), want: ErrUnreachable},
+		{to: simple.Node(3), want: ErrAbsentNode},
+	} {
+		if err := pt.Check(test.to); err != test.want {
+			t.Errorf("unexpected Shortest check for %d: got:%v want:%v", test.to.ID(), err, test.want)
+		}
+		if got := pt.Reachable(test.to); got != (test.want == nil) {
+			t.Errorf("unexpected Shortest reachability for %d: got:%t", test.to.ID(), got)
+		}
+		if err := all.Check(simple.Node(0), test.to); err != test.want {
+			t.Errorf("unexpected AllShortest check for %d: got:%v want:%v", test.to.ID(), err, test.want)
+		}
+		if got := all.Reachable(simple.Node(0), test.to); got != (test.want == nil) {
+			t.Errorf("unexpected AllShortest reachability for %d: got:%t", test.to.ID(), got)
+		}
+	}
+
+	absent := DijkstraFrom(simple.Node(5), g)
+	if err := absent.Check(simple.Node(0)); err != ErrAbsentNode {
+		t.Errorf("unexpected check from absent node: got:%v want:%v", err, ErrAbsentNode)
+	}
+	if err := all.Check(simple.Node(5), simple.Node(0)); err != ErrAbsentNode {
+		t.Errorf("unexpected AllShortest check from absent node: got:%v want:%v", err, ErrAbsentNode)
+	}
+}
@@ -4,7 +4,13 @@
 
 package path
 
-import "github.com/gonum/graph"
+import (
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/kernel"
+	"github.com/gonum/matrix/mat64"
+)
 
 // FloydWarshall returns a shortest-path tree for the graph g or false indicating
 // that a negative cycle exists in the graph. If the graph does not implement
@@ -57,3 +63,62 @@ func FloydWarshall(g graph.Graph) (paths AllShortest, ok bool) {
 
 	return paths, ok
 }
+
+// FloydWarshallDistances returns the shortest path distances between all
+// pairs of nodes in g using the Floyd-Warshall algorithm, without the path
+// information held by FloydWarshall. Row and column i of dist correspond to
+// nodes[i]. If the graph does not implement graph.Weighter, UniformCost is
+// used. If a negative cycle exists in g, ok will be returned false and the
+// distances are not valid.
+//
+// The relaxation of each row of dist is performed by a vectorized kernel,
+// so FloydWarshallDistances is substantially faster than FloydWarshall for
+// dense graphs such as simple.DirectedMatrix.
+func FloydWarshallDistances(g graph.Graph) (nodes []graph.Node, dist *mat64.Dense, ok bool) {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	nodes = g.Nodes()
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	dist = mat64.NewDense(len(nodes), len(nodes), nil)
+	inf := math.Inf(1)
+	for i, u := range nodes {
+		row := dist.RawRowView(i)
+		for j := range row {
+			row[j] = inf
+		}
+		row[i] = 0
+		for _, v := range g.From(u) {
+			w, ok := weight(u, v)
+			if !ok {
+				panic("floyd-warshall: unexpected invalid weight")
+			}
+			row[indexOf[v.ID()]] = w
+		}
+	}
+
+	for k := range nodes {
+		rk := dist.RawRowView(k)
+		for i := range nodes {
+			ri := dist.RawRowView(i)
+			if math.IsInf(ri[k], 1) {
+				continue
+			}
+			kernel.MinPlus(ri, rk, ri[k])
+		}
+	}
+
+	for i := range nodes {
+		if dist.At(i, i) < 0 {
+			return nodes, dist, false
+		}
+	}
+	return nodes, dist, true
+}
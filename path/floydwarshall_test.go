@@ -100,3 +100,33 @@ func TestFloydWarshall(t *testing.T) {
 		}
 	}
 }
+
+func TestFloydWarshallDistances(t *testing.T) {
+	for _, test := range testgraphs.ShortestPathTests {
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetEdge(e)
+		}
+
+		nodes, dist, ok := FloydWarshallDistances(g.(graph.Graph))
+		if test.HasNegativeCycle {
+			if ok {
+				t.Errorf("%q: expected negative cycle", test.Name)
+			}
+			continue
+		}
+		if !ok {
+			t.Fatalf("%q: unexpected negative cycle", test.Name)
+		}
+
+		pt, _ := FloydWarshall(g.(graph.Graph))
+		for i, u := range nodes {
+			for j, v := range nodes {
+				if got, want := dist.At(i, j), pt.Weight(u, v); got != want {
+					t.Errorf("%q: unexpected distance from %d to %d: got:%v want:%v",
+						test.Name, u.ID(), v.ID(), got, want)
+				}
+			}
+		}
+	}
+}
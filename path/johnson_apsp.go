@@ -6,14 +6,15 @@ package path
 
 import (
 	"math"
-	"math/rand"
 
 	"github.com/gonum/graph"
 	"github.com/gonum/graph/simple"
 )
 
-// JohnsonAllPaths returns a shortest-path tree for shortest paths in the graph g.
-// If the graph does not implement graph.Weighter, UniformCost is used.
+// JohnsonAllPaths returns a shortest-path tree for shortest paths in the graph g,
+// or false indicating that a negative cycle exists in the graph. When a negative
+// cycle exists, it is described by the NegativeCycle method of the returned
+// AllShortest. If the graph does not implement graph.Weighter, UniformCost is used.
 //
 // The time complexity of JohnsonAllPaths is O(|V|.|E|+|V|^2.log|V|).
 func JohnsonAllPaths(g graph.Graph) (paths AllShortest, ok bool) {
@@ -30,25 +31,24 @@ func JohnsonAllPaths(g graph.Graph) (paths AllShortest, ok bool) {
 
 	paths = newAllShortest(g.Nodes(), false)
 
-	sign := -1
-	for {
-		// Choose a random node ID until we find
-		// one that is not in g.
-		jg.q = sign * rand.Int()
+	// Choose the first negative node ID that
+	// is not in g, so the result does not
+	// depend on the global random source.
+	for jg.q = -1; ; jg.q-- {
 		if _, exists := paths.indexOf[jg.q]; !exists {
 			break
 		}
-		sign *= -1
 	}
 
 	jg.bellmanFord = true
 	jg.adjustBy, ok = BellmanFordFrom(johnsonGraphNode(jg.q), jg)
 	if !ok {
+		paths.negCycle = jg.adjustBy.negCycle
 		return paths, false
 	}
 
 	jg.bellmanFord = false
-	dijkstraAllPaths(jg, paths)
+	dijkstraAllPaths(jg, paths, &options{workers: 1})
 
 	for i, u := range paths.nodes {
 		hu := jg.adjustBy.WeightTo(u)
@@ -26,12 +26,17 @@ func TestJohnsonAllPaths(t *testing.T) {
 		if test.HasNegativeCycle {
 			if ok {
 				t.Errorf("%q: expected negative cycle", test.Name)
+				continue
 			}
+			checkNegativeCycle(t, test.Name, pt.NegativeCycle(), g.(graph.Graph))
 			continue
 		}
 		if !ok {
 			t.Fatalf("%q: unexpected negative cycle", test.Name)
 		}
+		if err := pt.NegativeCycle(); err != nil {
+			t.Errorf("%q: unexpected negative cycle error: %v", test.Name, err)
+		}
 
 		// Check all random paths returned are OK.
 		for i := 0; i < 10; i++ {
@@ -0,0 +1,178 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+)
+
+// Flow is a flow through a directed graph.
+type Flow struct {
+	// Value is the amount of flow
+	// leaving the source.
+	Value float64
+
+	// Cost is the sum over all edges
+	// of the flow along the edge times
+	// the edge's cost.
+	Cost float64
+
+	flow map[int]map[int]float64
+}
+
+// Of returns the flow along the edge from u to v.
+func (f Flow) Of(u, v graph.Node) float64 {
+	return f.flow[u.ID()][v.ID()]
+}
+
+// MinCostFlow returns a minimum cost flow of the given value from s to t in g,
+// and whether a flow of that value exists. If no such flow exists, the returned
+// Flow is a minimum cost maximum flow. A value of +Inf requests a minimum cost
+// maximum flow. If s and t are the same node, MinCostFlow returns a minimum
+// cost circulation.
+//
+// The capacity of each edge is given by capacity, which must return finite,
+// non-negative values, and the cost per unit of flow by the graph's Weight
+// method. If the graph does not implement graph.Weighter, UniformCost is used.
+//
+// MinCostFlow finds a feasible flow using shortest augmenting paths and then
+// cancels negative cost cycles in the residual graph, choosing the minimum mean
+// cycle at each step, giving a strongly polynomial running time. Unlike
+// successive shortest path methods, MinCostFlow accepts negative cost edges and
+// negative cost cycles.
+func MinCostFlow(g graph.Directed, s, t graph.Node, value float64, capacity func(u, v graph.Node) float64) (flow Flow, ok bool) {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	indexOf := make(map[int]int, len(nodes))
+	for i, u := range nodes {
+		indexOf[u.ID()] = i
+	}
+	si, sok := indexOf[s.ID()]
+	ti, tok := indexOf[t.ID()]
+	if !sok || !tok {
+		return Flow{flow: make(map[int]map[int]float64)}, value <= 0
+	}
+
+	// The residual graph holds each edge of g at an
+	// even index followed by its reverse, so the
+	// partner of arc i is arc i^1.
+	var (
+		arcs     []meanArc
+		residual []float64
+	)
+	adj := make([][]int, len(nodes))
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			c := capacity(u, v)
+			if c < 0 || math.IsInf(c, 0) || math.IsNaN(c) {
+				panic("path: invalid capacity")
+			}
+			w, ok := weight(u, v)
+			if !ok {
+				panic("path: unexpected invalid weight")
+			}
+			j := indexOf[v.ID()]
+			adj[i] = append(adj[i], len(arcs))
+			arcs = append(arcs, meanArc{from: i, to: j, cost: w})
+			residual = append(residual, c)
+			adj[j] = append(adj[j], len(arcs))
+			arcs = append(arcs, meanArc{from: j, to: i, cost: -w})
+			residual = append(residual, 0)
+		}
+	}
+
+	// Find a feasible flow by augmenting along
+	// shortest paths in the residual graph.
+	if si != ti {
+		via := make([]int, len(nodes))
+		for flow.Value < value {
+			for i := range via {
+				via[i] = -1
+			}
+			queue := []int{si}
+			for len(queue) != 0 && via[ti] < 0 {
+				u := queue[0]
+				queue = queue[1:]
+				for _, a := range adj[u] {
+					v := arcs[a].to
+					if residual[a] > 0 && v != si && via[v] < 0 {
+						via[v] = a
+						queue = append(queue, v)
+					}
+				}
+			}
+			if via[ti] < 0 {
+				break
+			}
+
+			delta := value - flow.Value
+			for v := ti; v != si; v = arcs[via[v]].from {
+				delta = math.Min(delta, residual[via[v]])
+			}
+			for v := ti; v != si; v = arcs[via[v]].from {
+				residual[via[v]] -= delta
+				residual[via[v]^1] += delta
+			}
+			flow.Value += delta
+		}
+	}
+	ok = flow.Value >= value
+
+	// Cancel negative cost cycles, most negative
+	// mean first, until none remain.
+	var (
+		live  []meanArc
+		index []int
+	)
+	for {
+		live, index = live[:0], index[:0]
+		for i, a := range arcs {
+			if residual[i] > 0 {
+				live = append(live, a)
+				index = append(index, i)
+			}
+		}
+		cycle, mean := karp(len(nodes), live)
+		if cycle == nil || mean >= -cancelTolerance {
+			break
+		}
+		delta := math.Inf(1)
+		for _, a := range cycle {
+			delta = math.Min(delta, residual[index[a]])
+		}
+		for _, a := range cycle {
+			residual[index[a]] -= delta
+			residual[index[a]^1] += delta
+		}
+	}
+
+	flow.flow = make(map[int]map[int]float64)
+	for i := 0; i < len(arcs); i += 2 {
+		f := residual[i^1]
+		if f == 0 {
+			continue
+		}
+		u, v := nodes[arcs[i].from].ID(), nodes[arcs[i].to].ID()
+		if flow.flow[u] == nil {
+			flow.flow[u] = make(map[int]float64)
+		}
+		flow.flow[u][v] += f
+		flow.Cost += f * arcs[i].cost
+	}
+	return flow, ok
+}
+
+// cancelTolerance is the smallest magnitude of negative cycle mean
+// cost considered by MinCostFlow, preventing cycling on cycles with
+// zero cost that appear negative due to rounding error.
+const cancelTolerance = 1e-12
@@ -0,0 +1,138 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+type flowEdge struct {
+	from, to  int
+	cap, cost float64
+}
+
+var minCostFlowTests = []struct {
+	name  string
+	edges []flowEdge
+	s, t  int
+	value float64
+
+	ok        bool
+	wantValue float64
+	wantCost  float64
+}{
+	{
+		name: "partial",
+		edges: []flowEdge{
+			{0, 1, 2, 1}, {0, 2, 2, 2}, {1, 2, 1, 1}, {1, 3, 1, 3}, {2, 3, 3, 1},
+		},
+		s: 0, t: 3, value: 3,
+		ok: true, wantValue: 3, wantCost: 9,
+	},
+	{
+		name: "saturated",
+		edges: []flowEdge{
+			{0, 1, 2, 1}, {0, 2, 2, 2}, {1, 2, 1, 1}, {1, 3, 1, 3}, {2, 3, 3, 1},
+		},
+		s: 0, t: 3, value: 4,
+		ok: true, wantValue: 4, wantCost: 13,
+	},
+	{
+		name: "infeasible",
+		edges: []flowEdge{
+			{0, 1, 2, 1}, {0, 2, 2, 2}, {1, 2, 1, 1}, {1, 3, 1, 3}, {2, 3, 3, 1},
+		},
+		s: 0, t: 3, value: 5,
+		ok: false, wantValue: 4, wantCost: 13,
+	},
+	{
+		name: "max flow",
+		edges: []flowEdge{
+			{0, 1, 2, 1}, {0, 2, 2, 2}, {1, 2, 1, 1}, {1, 3, 1, 3}, {2, 3, 3, 1},
+		},
+		s: 0, t: 3, value: math.Inf(1),
+		ok: false, wantValue: 4, wantCost: 13,
+	},
+	{
+		name: "short path expensive",
+		edges: []flowEdge{
+			{0, 3, 2, 10}, {0, 1, 2, 1}, {1, 2, 2, 1}, {2, 3, 2, 1},
+		},
+		s: 0, t: 3, value: 2,
+		ok: true, wantValue: 2, wantCost: 6,
+	},
+	{
+		name: "negative cycle",
+		edges: []flowEdge{
+			{0, 1, 1, 1}, {1, 2, 3, -4}, {2, 1, 2, 1}, {2, 3, 1, 1},
+		},
+		s: 0, t: 3, value: 1,
+		ok: true, wantValue: 1, wantCost: -8,
+	},
+	{
+		name: "circulation",
+		edges: []flowEdge{
+			{0, 1, 1, -2}, {1, 0, 5, 1}, {1, 2, 4, -1}, {2, 0, 2, 0},
+		},
+		s: 0, t: 0, value: 0,
+		ok: true, wantValue: 0, wantCost: -3,
+	},
+}
+
+func TestMinCostFlow(t *testing.T) {
+	for _, test := range minCostFlowTests {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		capacity := make(map[[2]int]float64)
+		for _, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e.from), T: simple.Node(e.to), W: e.cost})
+			capacity[[2]int{e.from, e.to}] = e.cap
+		}
+		capOf := func(u, v graph.Node) float64 { return capacity[[2]int{u.ID(), v.ID()}] }
+
+		f, ok := MinCostFlow(g, simple.Node(test.s), simple.Node(test.t), test.value, capOf)
+		if ok != test.ok {
+			t.Errorf("%q: unexpected ok: got:%t want:%t", test.name, ok, test.ok)
+		}
+		if f.Value != test.wantValue {
+			t.Errorf("%q: unexpected value: got:%v want:%v", test.name, f.Value, test.wantValue)
+		}
+		if math.Abs(f.Cost-test.wantCost) > 1e-12 {
+			t.Errorf("%q: unexpected cost: got:%v want:%v", test.name, f.Cost, test.wantCost)
+		}
+
+		// Check capacity and conservation constraints.
+		var cost float64
+		net := make(map[int]float64)
+		for _, e := range test.edges {
+			x := f.Of(simple.Node(e.from), simple.Node(e.to))
+			if x < 0 || x > e.cap {
+				t.Errorf("%q: flow %v along %d->%d violates capacity %v", test.name, x, e.from, e.to, e.cap)
+			}
+			net[e.from] -= x
+			net[e.to] += x
+			cost += x * e.cost
+		}
+		for _, n := range g.Nodes() {
+			want := 0.0
+			switch {
+			case test.s == test.t:
+			case n.ID() == test.s:
+				want = -f.Value
+			case n.ID() == test.t:
+				want = f.Value
+			}
+			if net[n.ID()] != want {
+				t.Errorf("%q: unexpected net flow at %d: got:%v want:%v", test.name, n.ID(), net[n.ID()], want)
+			}
+		}
+		if math.Abs(cost-f.Cost) > 1e-12 {
+			t.Errorf("%q: cost does not match flow: got:%v want:%v", test.name, f.Cost, cost)
+		}
+	}
+}
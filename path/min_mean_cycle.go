@@ -0,0 +1,151 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+)
+
+// MinMeanCycle returns the cycle in g with the least mean edge weight, and
+// that mean. The returned cycle holds the nodes of the cycle in path order,
+// with the first node repeated at the end. If g has no cycle, MinMeanCycle
+// returns nil and +Inf. If the graph does not implement graph.Weighter,
+// UniformCost is used.
+//
+// MinMeanCycle uses Karp's algorithm. The time complexity of MinMeanCycle
+// is O(|V|.|E|) and it uses O(|V|^2) space.
+func MinMeanCycle(g graph.Directed) (cycle []graph.Node, mean float64) {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	return minMeanCycleOf(g, func(u, v graph.Node) float64 {
+		w, ok := weight(u, v)
+		if !ok {
+			panic("path: unexpected invalid weight")
+		}
+		return w
+	})
+}
+
+// minMeanCycleOf returns the minimum mean cycle of g under the given cost
+// function, and its mean cost.
+func minMeanCycleOf(g graph.Directed, cost func(u, v graph.Node) float64) (cycle []graph.Node, mean float64) {
+	nodes := g.Nodes()
+	indexOf := make(map[int]int, len(nodes))
+	for i, u := range nodes {
+		indexOf[u.ID()] = i
+	}
+	var arcs []meanArc
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			arcs = append(arcs, meanArc{from: i, to: indexOf[v.ID()], cost: cost(u, v)})
+		}
+	}
+
+	c, mean := karp(len(nodes), arcs)
+	if c == nil {
+		return nil, mean
+	}
+	cycle = make([]graph.Node, len(c)+1)
+	for i, a := range c {
+		cycle[i] = nodes[arcs[a].from]
+	}
+	cycle[len(c)] = cycle[0]
+	return cycle, mean
+}
+
+// meanArc is an arc between dense node indices.
+type meanArc struct {
+	from, to int
+	cost     float64
+}
+
+// karp returns the indices into arcs of a minimum mean cycle, in path order,
+// of the graph with n nodes and the given arcs, and the mean cost of the
+// cycle. If there is no cycle, karp returns nil and +Inf.
+func karp(n int, arcs []meanArc) (cycle []int, mean float64) {
+	if n == 0 {
+		return nil, math.Inf(1)
+	}
+
+	// dist[k][v] is the least cost of a walk of
+	// exactly k arcs ending at v, starting from
+	// any node, and pred[k][v] is the index of
+	// the last arc on that walk.
+	dist := make([][]float64, n+1)
+	pred := make([][]int, n+1)
+	dist[0] = make([]float64, n)
+	for k := 1; k <= n; k++ {
+		dist[k] = make([]float64, n)
+		pred[k] = make([]int, n)
+		for v := range dist[k] {
+			dist[k][v] = math.Inf(1)
+			pred[k][v] = -1
+		}
+		for i, a := range arcs {
+			if d := dist[k-1][a.from] + a.cost; d < dist[k][a.to] {
+				dist[k][a.to] = d
+				pred[k][a.to] = i
+			}
+		}
+	}
+
+	best := -1
+	mean = math.Inf(1)
+	for v := 0; v < n; v++ {
+		if math.IsInf(dist[n][v], 1) {
+			continue
+		}
+		max := math.Inf(-1)
+		for k := 0; k < n; k++ {
+			if math.IsInf(dist[k][v], 1) {
+				continue
+			}
+			max = math.Max(max, (dist[n][v]-dist[k][v])/float64(n-k))
+		}
+		if max < mean {
+			best = v
+			mean = max
+		}
+	}
+	if best < 0 {
+		return nil, mean
+	}
+
+	// Any cycle on the n-arc walk ending at
+	// best is a minimum mean cycle.
+	walk := make([]int, n+1)
+	walk[n] = best
+	for k := n; k > 0; k-- {
+		walk[k-1] = arcs[pred[k][walk[k]]].from
+	}
+	seen := make(map[int]int, n)
+	for j := n; j >= 0; j-- {
+		i, ok := seen[walk[j]]
+		if !ok {
+			seen[walk[j]] = j
+			continue
+		}
+		cycle = make([]int, i-j)
+		for k := range cycle {
+			cycle[k] = pred[j+k+1][walk[j+k+1]]
+		}
+		break
+	}
+
+	// Report the mean of the cycle itself
+	// rather than Karp's bound to avoid
+	// accumulated rounding error.
+	var sum float64
+	for _, a := range cycle {
+		sum += arcs[a].cost
+	}
+	return cycle, sum / float64(len(cycle))
+}
@@ -0,0 +1,95 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+var minMeanCycleTests = []struct {
+	name  string
+	edges []simple.Edge
+
+	want []int // Sorted IDs of the cycle nodes.
+	mean float64
+}{
+	{
+		name: "acyclic",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: -1},
+			{F: simple.Node(1), T: simple.Node(2), W: -1},
+		},
+		mean: math.Inf(1),
+	},
+	{
+		name: "positive",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+			{F: simple.Node(1), T: simple.Node(0), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: -1},
+			{F: simple.Node(3), T: simple.Node(1), W: 2},
+		},
+		want: []int{1, 2, 3},
+		mean: 2.0 / 3,
+	},
+	{
+		name: "negative",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: -4},
+			{F: simple.Node(2), T: simple.Node(0), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 2},
+			{F: simple.Node(3), T: simple.Node(4), W: -5},
+			{F: simple.Node(4), T: simple.Node(3), W: 2},
+		},
+		want: []int{3, 4},
+		mean: -1.5,
+	},
+}
+
+func TestMinMeanCycle(t *testing.T) {
+	for _, test := range minMeanCycleTests {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetEdge(e)
+		}
+
+		cycle, mean := MinMeanCycle(g)
+		if math.Abs(mean-test.mean) > 1e-12 && mean != test.mean {
+			t.Errorf("%q: unexpected mean: got:%v want:%v", test.name, mean, test.mean)
+		}
+		if test.want == nil {
+			if cycle != nil {
+				t.Errorf("%q: unexpected cycle: %v", test.name, cycle)
+			}
+			continue
+		}
+		if len(cycle) < 2 || cycle[0].ID() != cycle[len(cycle)-1].ID() {
+			t.Errorf("%q: cycle is not closed: %v", test.name, cycle)
+			continue
+		}
+		for i, u := range cycle[:len(cycle)-1] {
+			if !g.HasEdgeFromTo(u, cycle[i+1]) {
+				t.Errorf("%q: cycle contains missing edge %d->%d", test.name, u.ID(), cycle[i+1].ID())
+			}
+		}
+		nodes := append(cycle[:0:0], cycle[:len(cycle)-1]...)
+		sort.Sort(ordered.ByID(nodes))
+		got := make([]int, len(nodes))
+		for i, n := range nodes {
+			got[i] = n.ID()
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected cycle nodes: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
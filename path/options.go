@@ -0,0 +1,88 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"runtime"
+
+	"github.com/gonum/graph/instrument"
+)
+
+// Option is a functional option for the path finding functions that
+// accept options. Each function documents the options it uses and
+// ignores the others.
+type Option func(*options)
+
+type options struct {
+	heuristic Heuristic
+	workers   int
+	ctx       Context
+	stats     *instrument.Stats
+}
+
+func newOptions(opts []Option) options {
+	o := options{workers: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Context is the part of context.Context used to cancel path finding.
+// It is satisfied by any context.Context.
+type Context interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
+// WithHeuristic returns an Option specifying the heuristic used by an
+// informed search.
+func WithHeuristic(h Heuristic) Option {
+	return func(o *options) { o.heuristic = h }
+}
+
+// WithWorkers returns an Option specifying the number of goroutines used
+// by a parallel computation. If n is less than one, runtime.GOMAXPROCS(0)
+// goroutines are used. The default is a single goroutine.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		o.workers = n
+	}
+}
+
+// WithContext returns an Option specifying a context that cancels the
+// computation when it is done.
+func WithContext(ctx Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithStats returns an Option specifying a Stats that the counts of work
+// performed by the computation are added to.
+func WithStats(s *instrument.Stats) Option {
+	return func(o *options) { o.stats = s }
+}
+
+// report adds st to the options' Stats if there is one.
+func (o *options) report(st instrument.Stats) {
+	if o.stats != nil {
+		o.stats.Add(st)
+	}
+}
+
+// canceled returns the error of the options' context if it is done.
+func (o *options) canceled() error {
+	if o.ctx == nil {
+		return nil
+	}
+	select {
+	case <-o.ctx.Done():
+		return o.ctx.Err()
+	default:
+		return nil
+	}
+}
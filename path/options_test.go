@@ -0,0 +1,130 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/instrument"
+	"github.com/gonum/graph/simple"
+)
+
+// doneContext is a Context that is already done.
+type doneContext struct{}
+
+var errDone = errors.New("done")
+
+func (doneContext) Done() <-chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}
+func (doneContext) Err() error { return errDone }
+
+func optionsTestGraph() *simple.DirectedGraph {
+	rnd := rand.New(rand.NewSource(1))
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	const n = 100
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < 5*n; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u != v {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: float64(1 + rnd.Intn(3))})
+		}
+	}
+	return g
+}
+
+func TestAStarWith(t *testing.T) {
+	g := optionsTestGraph()
+	h := func(x, y graph.Node) float64 { return 0 }
+	s, u := simple.Node(0), simple.Node(50)
+
+	want, _ := AStar(s, u, g, h)
+	got, _, err := AStarWith(s, u, g, WithHeuristic(h))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got.WeightTo(u) != want.WeightTo(u) {
+		t.Errorf("unexpected path weight: got:%v want:%v", got.WeightTo(u), want.WeightTo(u))
+	}
+
+	_, expanded, err := AStarWith(s, u, g, WithContext(doneContext{}))
+	if err != errDone || expanded != 0 {
+		t.Errorf("unexpected result with done context: got:%d %v want:0 %v", expanded, err, errDone)
+	}
+}
+
+func TestDijkstraAllPathsWith(t *testing.T) {
+	g := optionsTestGraph()
+	want := DijkstraAllPaths(g)
+	for _, workers := range []int{0, 1, 4} {
+		got, err := DijkstraAllPathsWith(g, WithWorkers(workers))
+		if err != nil {
+			t.Errorf("unexpected error for %d workers: %v", workers, err)
+		}
+		for _, u := range g.Nodes() {
+			for _, v := range g.Nodes() {
+				if got.Weight(u, v) != want.Weight(u, v) {
+					t.Errorf("unexpected weight for %d workers from %d to %d: got:%v want:%v",
+						workers, u.ID(), v.ID(), got.Weight(u, v), want.Weight(u, v))
+				}
+				gotPaths, _ := got.AllBetween(u, v)
+				wantPaths, _ := want.AllBetween(u, v)
+				if len(gotPaths) != len(wantPaths) {
+					t.Errorf("unexpected number of paths for %d workers from %d to %d: got:%d want:%d",
+						workers, u.ID(), v.ID(), len(gotPaths), len(wantPaths))
+				}
+			}
+		}
+	}
+
+	if _, err := DijkstraAllPathsWith(g, WithWorkers(4), WithContext(doneContext{})); err != errDone {
+		t.Errorf("unexpected error with done context: got:%v want:%v", err, errDone)
+	}
+
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: -1})
+	var panicked interface{}
+	func() {
+		defer func() { panicked = recover() }()
+		DijkstraAllPathsWith(g, WithWorkers(4))
+	}()
+	if !reflect.DeepEqual(panicked, "dijkstra: negative edge weight") {
+		t.Errorf("unexpected panic for negative edge weight: got:%v", panicked)
+	}
+}
+
+func TestWithStats(t *testing.T) {
+	g := optionsTestGraph()
+	var edges int
+	for _, u := range g.Nodes() {
+		edges += len(g.From(u))
+	}
+
+	var st instrument.Stats
+	_, expanded, _ := AStarWith(simple.Node(0), simple.Node(50), g, WithStats(&st))
+	if st.Expanded != expanded {
+		t.Errorf("unexpected A* expanded count: got:%d want:%d", st.Expanded, expanded)
+	}
+	if st.Relaxed == 0 || st.Relaxed > edges || st.HeapOps < 2*st.Expanded-1 {
+		t.Errorf("unexpected A* stats: %v", st)
+	}
+
+	// Every pushed node is popped and expanded.
+	for _, workers := range []int{1, 2, 4} {
+		var got instrument.Stats
+		DijkstraAllPathsWith(g, WithWorkers(workers), WithStats(&got))
+		if got.Expanded < len(g.Nodes()) || got.Relaxed == 0 || got.HeapOps != 2*got.Expanded {
+			t.Errorf("unexpected Dijkstra stats for %d workers: %v", workers, got)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// DistanceOracle is an approximate distance oracle for an undirected graph.
+type DistanceOracle struct {
+	k int
+
+	// pivots holds the nearest member
+	// of each sample level to each node.
+	pivots []map[int]pivot
+
+	// bunch holds the distances from
+	// each node to the members of its
+	// bunch.
+	bunch map[int]map[int]float64
+}
+
+// pivot is the nearest sample member to a node.
+type pivot struct {
+	id   int
+	dist float64
+}
+
+// Oracle returns a distance oracle for the undirected graph g as described by
+// Thorup and Zwick, doi:10.1145/1044731.1044732. The distances returned by
+// the oracle are at least the shortest path distance and at most 2k-1 times
+// that distance, and each query takes O(k) time. The oracle has an expected
+// size of O(k.|V|^{1+1/k}) and is constructed in O(k.|E|.|V|^{1/k}.log|V|)
+// expected time. If k is one the oracle holds all shortest path distances.
+//
+// If the graph does not implement graph.Weighter, UniformCost is used. If src
+// is not nil it is used as the random source for sampling nodes, otherwise
+// rand.Float64 is used. Oracle will panic if k is less than one or g has a
+// negative edge weight.
+func Oracle(g graph.Undirected, k int, src *rand.Rand) *DistanceOracle {
+	if k < 1 {
+		panic("oracle: k must be positive")
+	}
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = src.Float64
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+
+	// Sample the nested levels A_0 ⊇ A_1 ⊇ ... ⊇ A_{k-1},
+	// ensuring that the last level is not empty.
+	levels := make([][]graph.Node, k+1)
+	levels[0] = nodes
+	p := math.Pow(float64(len(nodes)), -1/float64(k))
+	for i := 1; i < k; i++ {
+		for _, n := range levels[i-1] {
+			if rnd() < p {
+				levels[i] = append(levels[i], n)
+			}
+		}
+		if len(levels[i]) == 0 && len(levels[i-1]) != 0 {
+			levels[i] = []graph.Node{levels[i-1][int(rnd()*float64(len(levels[i-1])))]}
+		}
+	}
+
+	o := &DistanceOracle{
+		k:      k,
+		pivots: make([]map[int]pivot, k+1),
+		bunch:  make(map[int]map[int]float64, len(nodes)),
+	}
+	for _, n := range nodes {
+		o.bunch[n.ID()] = make(map[int]float64)
+	}
+	for i := k; i >= 0; i-- {
+		o.pivots[i] = nearest(g, weight, levels[i])
+	}
+
+	// The cluster of w in A_i \ A_{i+1} is the set of nodes
+	// closer to w than to any member of A_{i+1}, and v is in
+	// the cluster of w exactly when w is in the bunch of v.
+	for i := 0; i < k; i++ {
+		sampled := make(map[int]bool, len(levels[i+1]))
+		for _, n := range levels[i+1] {
+			sampled[n.ID()] = true
+		}
+		for _, w := range levels[i] {
+			if !sampled[w.ID()] {
+				o.cluster(g, weight, w, o.pivots[i+1])
+			}
+		}
+	}
+
+	return o
+}
+
+// nearest returns the nearest member of sources to each node reachable
+// from sources in g.
+func nearest(g graph.Graph, weight Weighting, sources []graph.Node) map[int]pivot {
+	piv := make(map[int]pivot)
+	var Q priorityQueue
+	for _, s := range sources {
+		piv[s.ID()] = pivot{id: s.ID()}
+		Q = append(Q, distanceNode{node: s})
+	}
+	heap.Init(&Q)
+	for Q.Len() != 0 {
+		mid := heap.Pop(&Q).(distanceNode)
+		p := piv[mid.node.ID()]
+		if mid.dist > p.dist {
+			continue
+		}
+		for _, v := range g.From(mid.node) {
+			w, ok := weight(mid.node, v)
+			if !ok {
+				panic("oracle: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("oracle: negative edge weight")
+			}
+			joint := p.dist + w
+			if q, ok := piv[v.ID()]; !ok || joint < q.dist {
+				piv[v.ID()] = pivot{id: p.id, dist: joint}
+				heap.Push(&Q, distanceNode{node: v, dist: joint})
+			}
+		}
+	}
+	return piv
+}
+
+// cluster adds w to the bunches of the nodes in its cluster, the nodes that
+// are closer to w than to their pivot in the next level.
+func (o *DistanceOracle) cluster(g graph.Graph, weight Weighting, w graph.Node, next map[int]pivot) {
+	closer := func(v int, d float64) bool {
+		p, ok := next[v]
+		return !ok || d < p.dist
+	}
+	dist := map[int]float64{w.ID(): 0}
+	Q := priorityQueue{{node: w, dist: 0}}
+	for Q.Len() != 0 {
+		mid := heap.Pop(&Q).(distanceNode)
+		if mid.dist > dist[mid.node.ID()] {
+			continue
+		}
+		o.bunch[mid.node.ID()][w.ID()] = mid.dist
+		for _, v := range g.From(mid.node) {
+			e, _ := weight(mid.node, v)
+			joint := mid.dist + e
+			if !closer(v.ID(), joint) {
+				continue
+			}
+			if d, ok := dist[v.ID()]; !ok || joint < d {
+				dist[v.ID()] = joint
+				heap.Push(&Q, distanceNode{node: v, dist: joint})
+			}
+		}
+	}
+}
+
+// Weight returns the approximate distance between u and v. If there is no
+// path between u and v, Weight returns positive infinity.
+func (o *DistanceOracle) Weight(u, v graph.Node) float64 {
+	uid, vid := u.ID(), v.ID()
+	if _, ok := o.bunch[uid]; !ok {
+		return math.Inf(1)
+	}
+	if _, ok := o.bunch[vid]; !ok {
+		return math.Inf(1)
+	}
+	if uid == vid {
+		return 0
+	}
+
+	w := pivot{id: uid}
+	for i := 0; ; {
+		if d, ok := o.bunch[vid][w.id]; ok {
+			return w.dist + d
+		}
+		i++
+		if i >= o.k {
+			return math.Inf(1)
+		}
+		uid, vid = vid, uid
+		var ok bool
+		w, ok = o.pivots[i][uid]
+		if !ok {
+			return math.Inf(1)
+		}
+	}
+}
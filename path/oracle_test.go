@@ -0,0 +1,67 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+func TestOracle(t *testing.T) {
+	for _, test := range []struct {
+		n, m int
+		k    int
+	}{
+		{n: 50, m: 100, k: 1},
+		{n: 50, m: 100, k: 2},
+		{n: 100, m: 150, k: 3},
+		{n: 200, m: 600, k: 4},
+		{n: 40, m: 30, k: 2}, // Disconnected.
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for i := 0; i < test.n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 0; i < test.m; i++ {
+			u, v := rnd.Intn(test.n), rnd.Intn(test.n)
+			if u != v {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: 1 + rnd.Float64()})
+			}
+		}
+
+		o := Oracle(g, test.k, rnd)
+		paths := DijkstraAllPaths(g)
+		stretch := float64(2*test.k - 1)
+		for _, u := range g.Nodes() {
+			for _, v := range g.Nodes() {
+				want := paths.Weight(u, v)
+				got := o.Weight(u, v)
+				if math.IsInf(want, 1) {
+					if !math.IsInf(got, 1) {
+						t.Errorf("unexpected finite distance for n=%d k=%d between %d and %d: got:%v",
+							test.n, test.k, u.ID(), v.ID(), got)
+					}
+					continue
+				}
+				const tol = 1e-12
+				if got < want-tol || got > stretch*want+tol {
+					t.Errorf("unexpected distance for n=%d k=%d between %d and %d: got:%v want in [%v,%v]",
+						test.n, test.k, u.ID(), v.ID(), got, want, stretch*want)
+				}
+				if test.k == 1 && math.Abs(got-want) > tol {
+					t.Errorf("unexpected inexact distance for n=%d k=1 between %d and %d: got:%v want:%v",
+						test.n, u.ID(), v.ID(), got, want)
+				}
+			}
+		}
+		if got := o.Weight(simple.Node(-1), simple.Node(0)); !math.IsInf(got, 1) {
+			t.Errorf("unexpected distance from absent node: got:%v", got)
+		}
+	}
+}
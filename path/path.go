@@ -0,0 +1,128 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/topo"
+)
+
+// Path is a path through a graph represented by the IDs of its nodes
+// in order. Representing paths by ID avoids holding node values for
+// long paths through large graphs; the nodes and edges of a path can
+// be obtained from the graph when they are needed.
+type Path []int
+
+// nodeLookup is implemented by graphs that can return a node by its ID.
+type nodeLookup interface {
+	Node(id int) graph.Node
+}
+
+// Nodes returns the nodes of the path in g. If g has a Node(id int) graph.Node
+// method it is used to obtain the nodes, otherwise the nodes of g are indexed.
+// Nodes that are not in g are returned as nil.
+func (p Path) Nodes(g graph.Graph) []graph.Node {
+	if len(p) == 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, len(p))
+	if l, ok := g.(nodeLookup); ok {
+		for i, id := range p {
+			nodes[i] = l.Node(id)
+		}
+		return nodes
+	}
+	byID := make(map[int]graph.Node)
+	for _, n := range g.Nodes() {
+		byID[n.ID()] = n
+	}
+	for i, id := range p {
+		nodes[i] = byID[id]
+	}
+	return nodes
+}
+
+// Edges returns the edges between consecutive nodes of the path in g.
+// An edge that is not in g is returned as nil.
+func (p Path) Edges(g graph.Graph) []graph.Edge {
+	if len(p) < 2 {
+		return nil
+	}
+	nodes := p.Nodes(g)
+	edges := make([]graph.Edge, len(p)-1)
+	for i := range edges {
+		u, v := nodes[i], nodes[i+1]
+		if u == nil || v == nil {
+			continue
+		}
+		edges[i] = g.Edge(u, v)
+	}
+	return edges
+}
+
+// Weight returns the total weight of the path in g. Edge weights are
+// obtained from g if it is a graph.Weighter, otherwise UniformCost is
+// used. The weight of a path with an absent edge is +Inf.
+func (p Path) Weight(g graph.Graph) float64 {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	nodes := p.Nodes(g)
+	var w float64
+	for i := 1; i < len(nodes); i++ {
+		u, v := nodes[i-1], nodes[i]
+		if u == nil || v == nil {
+			return math.Inf(1)
+		}
+		ew, ok := weight(u, v)
+		if !ok {
+			return math.Inf(1)
+		}
+		w += ew
+	}
+	return w
+}
+
+// IsPath returns whether nodes is a walk in g: every node is in g and
+// each consecutive pair of nodes is joined by an edge, following edge
+// direction if g is directed. An empty sequence is a path.
+func IsPath(g graph.Graph, nodes []graph.Node) bool {
+	return topo.IsPathIn(g, nodes)
+}
+
+// Weight returns the total weight of the walk through g given by nodes.
+// Edge weights are obtained from g if it is a graph.Weighter, otherwise
+// UniformCost is used. Weight returns an error if a node is not in g or
+// if consecutive nodes are not joined by an edge.
+func Weight(g graph.Graph, nodes []graph.Node) (float64, error) {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	var w float64
+	for i, n := range nodes {
+		if !g.Has(n) {
+			return math.Inf(1), fmt.Errorf("path: node %d not in graph", n.ID())
+		}
+		if i == 0 {
+			continue
+		}
+		u := nodes[i-1]
+		if g.Edge(u, n) == nil {
+			return math.Inf(1), fmt.Errorf("path: no edge from node %d to node %d", u.ID(), n.ID())
+		}
+		ew, _ := weight(u, n)
+		w += ew
+	}
+	return w, nil
+}
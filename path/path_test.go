@@ -0,0 +1,137 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path/internal/testgraphs"
+	"github.com/gonum/graph/simple"
+)
+
+func TestPath(t *testing.T) {
+	for _, test := range testgraphs.ShortestPathTests {
+		if test.HasNegativeWeight {
+			continue
+		}
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetEdge(e)
+		}
+
+		pt := DijkstraFrom(test.Query.From(), g.(graph.Graph))
+		nodes, weight := pt.To(test.Query.To())
+		p, pw := pt.PathTo(test.Query.To())
+		if pw != weight && !(math.IsInf(pw, 1) && math.IsInf(weight, 1)) {
+			t.Errorf("%q: unexpected path weight: got:%v want:%v", test.Name, pw, weight)
+		}
+		if len(p) != len(nodes) {
+			t.Errorf("%q: unexpected path length: got:%d want:%d", test.Name, len(p), len(nodes))
+			continue
+		}
+		for i, n := range nodes {
+			if p[i] != n.ID() {
+				t.Errorf("%q: unexpected path: got:%v want:%v", test.Name, p, nodes)
+				break
+			}
+		}
+		if p == nil {
+			continue
+		}
+
+		for _, gr := range []graph.Graph{g.(graph.Graph), noLookup{g.(graph.Graph)}} {
+			if got := p.Nodes(gr); !reflect.DeepEqual(got, nodes) {
+				t.Errorf("%q: unexpected path nodes: got:%v want:%v", test.Name, got, nodes)
+			}
+		}
+		if got := p.Weight(g.(graph.Graph)); got != weight {
+			t.Errorf("%q: unexpected weight from graph: got:%v want:%v", test.Name, got, weight)
+		}
+		var sum float64
+		for _, e := range p.Edges(g.(graph.Graph)) {
+			sum += e.Weight()
+		}
+		if sum != weight {
+			t.Errorf("%q: unexpected sum of edge weights: got:%v want:%v", test.Name, sum, weight)
+		}
+
+		if !test.HasUniquePath {
+			continue
+		}
+		all := DijkstraAllPaths(g.(graph.Graph))
+		p, pw, unique := all.PathBetween(test.Query.From(), test.Query.To())
+		if pw != test.Weight || !unique {
+			t.Errorf("%q: unexpected all paths result: got weight:%v unique:%t want weight:%v unique:true",
+				test.Name, pw, unique, test.Weight)
+		}
+		if !reflect.DeepEqual([]int(p), test.WantPaths[0]) {
+			t.Errorf("%q: unexpected all paths path: got:%v want:%v", test.Name, p, test.WantPaths[0])
+		}
+	}
+}
+
+func TestPathAbsentEdge(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 2})
+	g.AddNode(simple.Node(2))
+	p := Path{0, 1, 2}
+	if w := p.Weight(g); !math.IsInf(w, 1) {
+		t.Errorf("unexpected weight for path with absent edge: got:%v want:+Inf", w)
+	}
+	edges := p.Edges(g)
+	if len(edges) != 2 || edges[0] == nil || edges[1] != nil {
+		t.Errorf("unexpected edges for path with absent edge: got:%v", edges)
+	}
+	if w := (Path{0, 1}).Weight(g); w != 2 {
+		t.Errorf("unexpected weight: got:%v want:2", w)
+	}
+	if w := (Path{0}).Weight(g); w != 0 {
+		t.Errorf("unexpected weight for single node path: got:%v want:0", w)
+	}
+}
+
+// noLookup hides the Node method of a graph.
+type noLookup struct {
+	graph.Graph
+}
+
+func TestWeight(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 2})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 0.5})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0), W: 1})
+
+	for _, test := range []struct {
+		nodes  []int
+		isPath bool
+		weight float64
+	}{
+		{nodes: nil, isPath: true, weight: 0},
+		{nodes: []int{1}, isPath: true, weight: 0},
+		{nodes: []int{0, 1, 2}, isPath: true, weight: 2.5},
+		{nodes: []int{0, 1, 2, 0, 1}, isPath: true, weight: 5.5},
+		{nodes: []int{1, 0}, isPath: false},
+		{nodes: []int{0, 3}, isPath: false},
+		{nodes: []int{3}, isPath: false},
+	} {
+		nodes := make([]graph.Node, len(test.nodes))
+		for i, id := range test.nodes {
+			nodes[i] = simple.Node(id)
+		}
+		if got := IsPath(g, nodes); got != test.isPath {
+			t.Errorf("unexpected IsPath result for %v: got:%t want:%t", test.nodes, got, test.isPath)
+		}
+		w, err := Weight(g, nodes)
+		if (err == nil) != test.isPath {
+			t.Errorf("unexpected error for %v: %v", test.nodes, err)
+		}
+		if err == nil && w != test.weight {
+			t.Errorf("unexpected weight for %v: got:%v want:%v", test.nodes, w, test.weight)
+		}
+	}
+}
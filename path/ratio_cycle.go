@@ -0,0 +1,55 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+)
+
+// BestRatioCycle returns the cycle in g with the greatest geometric mean of
+// its multiplicative edge weights and the product of the weights along that
+// cycle. The returned cycle holds the nodes of the cycle in path order, with
+// the first node repeated at the end. If g has no cycle, BestRatioCycle
+// returns nil and zero.
+//
+// When edge weights are exchange rates, the returned cycle is an arbitrage
+// opportunity if and only if ratio is greater than one. If the graph does not
+// implement graph.Weighter, UniformCost is used. BestRatioCycle will panic if
+// g has a non-positive edge weight.
+//
+// The cycle is found as a minimum mean cycle of the negated logarithm of the
+// edge weights using Karp's algorithm. The time complexity of BestRatioCycle
+// is O(|V|.|E|) and it uses O(|V|^2) space.
+func BestRatioCycle(g graph.Directed) (cycle []graph.Node, ratio float64) {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	cycle, _ = minMeanCycleOf(g, func(u, v graph.Node) float64 {
+		w, ok := weight(u, v)
+		if !ok {
+			panic("path: unexpected invalid weight")
+		}
+		if w <= 0 {
+			panic("path: non-positive edge weight")
+		}
+		return -math.Log(w)
+	})
+	if cycle == nil {
+		return nil, 0
+	}
+
+	ratio = 1
+	for i, u := range cycle[:len(cycle)-1] {
+		w, _ := weight(u, cycle[i+1])
+		ratio *= w
+	}
+	return cycle, ratio
+}
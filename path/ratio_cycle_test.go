@@ -0,0 +1,106 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+var bestRatioCycleTests = []struct {
+	name  string
+	edges []simple.Edge
+
+	want  []int // Sorted IDs of the cycle nodes.
+	ratio float64
+}{
+	{
+		name: "acyclic",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(2), W: 2},
+			{F: simple.Node(0), T: simple.Node(2), W: 2},
+		},
+	},
+	{
+		name: "arbitrage",
+		edges: []simple.Edge{
+			// USD, EUR, GBP, JPY.
+			{F: simple.Node(0), T: simple.Node(1), W: 0.9},
+			{F: simple.Node(1), T: simple.Node(2), W: 0.9},
+			{F: simple.Node(2), T: simple.Node(0), W: 1.3},
+			{F: simple.Node(0), T: simple.Node(3), W: 100},
+			{F: simple.Node(3), T: simple.Node(0), W: 0.0099},
+			{F: simple.Node(1), T: simple.Node(3), W: 110},
+		},
+		want:  []int{0, 1, 2},
+		ratio: 0.9 * 0.9 * 1.3,
+	},
+	{
+		name: "no arbitrage",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 0.5},
+			{F: simple.Node(1), T: simple.Node(0), W: 1.5},
+			{F: simple.Node(1), T: simple.Node(2), W: 0.25},
+			{F: simple.Node(2), T: simple.Node(1), W: 3.9},
+		},
+		want:  []int{1, 2},
+		ratio: 0.25 * 3.9,
+	},
+	{
+		name: "disconnected",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(0), W: 0.4},
+			{F: simple.Node(2), T: simple.Node(3), W: 0.95},
+			{F: simple.Node(3), T: simple.Node(2), W: 0.95},
+		},
+		want:  []int{2, 3},
+		ratio: 0.95 * 0.95,
+	},
+}
+
+func TestBestRatioCycle(t *testing.T) {
+	for _, test := range bestRatioCycleTests {
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetEdge(e)
+		}
+
+		cycle, ratio := BestRatioCycle(g)
+		if test.want == nil {
+			if cycle != nil || ratio != 0 {
+				t.Errorf("%q: unexpected cycle: got:%v ratio:%v", test.name, cycle, ratio)
+			}
+			continue
+		}
+		if len(cycle) < 2 || cycle[0].ID() != cycle[len(cycle)-1].ID() {
+			t.Errorf("%q: cycle is not closed: %v", test.name, cycle)
+			continue
+		}
+		for i, u := range cycle[:len(cycle)-1] {
+			if !g.HasEdgeFromTo(u, cycle[i+1]) {
+				t.Errorf("%q: cycle contains missing edge %d->%d", test.name, u.ID(), cycle[i+1].ID())
+			}
+		}
+		nodes := append(cycle[:0:0], cycle[:len(cycle)-1]...)
+		sort.Sort(ordered.ByID(nodes))
+		got := make([]int, len(nodes))
+		for i, n := range nodes {
+			got[i] = n.ID()
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected cycle nodes: got:%v want:%v", test.name, got, test.want)
+		}
+		if math.Abs(ratio-test.ratio) > 1e-12 {
+			t.Errorf("%q: unexpected ratio: got:%v want:%v", test.name, ratio, test.ratio)
+		}
+	}
+}
@@ -5,13 +5,26 @@
 package path
 
 import (
+	"errors"
 	"math"
 	"math/rand"
 
 	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
 	"github.com/gonum/matrix/mat64"
 )
 
+var (
+	// ErrAbsentNode is returned when a queried
+	// node is not in the analysed graph.
+	ErrAbsentNode = errors.New("path: node not in graph")
+
+	// ErrUnreachable is returned when a queried
+	// node is in the analysed graph but there is
+	// no path to it.
+	ErrUnreachable = errors.New("path: node not reachable")
+)
+
 // Shortest is a shortest-path tree created by the BellmanFordFrom or DijkstraFrom
 // single-source shortest path functions.
 type Shortest struct {
@@ -42,9 +55,18 @@ type Shortest struct {
 	// tree of the graph. The index is a
 	// linear mapping of to-dense-id.
 	next []int
+
+	// weight returns the weights of
+	// the edges of the analysed graph
+	// for the edges placed by Tree.
+	weight Weighting
+
+	// negCycle describes the negative
+	// cycle found by BellmanFordFrom.
+	negCycle *NegativeCycleError
 }
 
-func newShortestFrom(u graph.Node, nodes []graph.Node) Shortest {
+func newShortestFrom(u graph.Node, nodes []graph.Node, weight Weighting) Shortest {
 	indexOf := make(map[int]int, len(nodes))
 	uid := u.ID()
 	for i, n := range nodes {
@@ -62,6 +84,8 @@ func newShortestFrom(u graph.Node, nodes []graph.Node) Shortest {
 
 		dist: make([]float64, len(nodes)),
 		next: make([]int, len(nodes)),
+
+		weight: weight,
 	}
 	for i := range nodes {
 		p.dist[i] = math.Inf(1)
@@ -77,10 +101,42 @@ func (p Shortest) set(to int, weight float64, mid int) {
 	p.next[to] = mid
 }
 
+// NegativeCycle returns a *NegativeCycleError describing the negative cycle
+// found during construction of the Shortest, or nil if no negative cycle was
+// found.
+func (p Shortest) NegativeCycle() error {
+	if p.negCycle == nil {
+		return nil
+	}
+	return p.negCycle
+}
+
 // From returns the starting node of the paths held by the Shortest.
 func (p Shortest) From() graph.Node { return p.from }
 
-// WeightTo returns the weight of the minimum path to v.
+// Reachable returns whether v is in the analysed graph and there is a path
+// to v from the starting node.
+func (p Shortest) Reachable(v graph.Node) bool {
+	return p.Check(v) == nil
+}
+
+// Check returns ErrAbsentNode if either v or the starting node is not in
+// the analysed graph, ErrUnreachable if there is no path to v from the
+// starting node, and nil otherwise.
+func (p Shortest) Check(v graph.Node) error {
+	to, toOK := p.indexOf[v.ID()]
+	if _, fromOK := p.indexOf[p.from.ID()]; !fromOK || !toOK {
+		return ErrAbsentNode
+	}
+	if math.IsInf(p.dist[to], 1) {
+		return ErrUnreachable
+	}
+	return nil
+}
+
+// WeightTo returns the weight of the minimum path to v. If v is not
+// reachable, WeightTo returns positive infinity; Check distinguishes
+// the reasons for this.
 func (p Shortest) WeightTo(v graph.Node) float64 {
 	to, toOK := p.indexOf[v.ID()]
 	if !toOK {
@@ -89,7 +145,8 @@ func (p Shortest) WeightTo(v graph.Node) float64 {
 	return p.dist[to]
 }
 
-// To returns a shortest path to v and the weight of the path.
+// To returns a shortest path to v and the weight of the path. If v is not
+// reachable, To returns a nil path and positive infinity.
 func (p Shortest) To(v graph.Node) (path []graph.Node, weight float64) {
 	to, toOK := p.indexOf[v.ID()]
 	if !toOK || math.IsInf(p.dist[to], 1) {
@@ -105,6 +162,54 @@ func (p Shortest) To(v graph.Node) (path []graph.Node, weight float64) {
 	return path, p.dist[p.indexOf[v.ID()]]
 }
 
+// PathTo returns a shortest path to v as a Path of node IDs and the weight
+// of the path.
+func (p Shortest) PathTo(v graph.Node) (path Path, weight float64) {
+	to, toOK := p.indexOf[v.ID()]
+	if !toOK || math.IsInf(p.dist[to], 1) {
+		return nil, math.Inf(1)
+	}
+	from := p.indexOf[p.from.ID()]
+	n := 1
+	for i := to; i != from; i = p.next[i] {
+		n++
+	}
+	path = make(Path, n)
+	for i := to; ; i = p.next[i] {
+		n--
+		path[n] = p.nodes[i].ID()
+		if i == from {
+			break
+		}
+	}
+	return path, p.dist[to]
+}
+
+// Tree places the shortest-path tree held by the Shortest in the destination,
+// dst. The destination is not cleared first. All nodes reachable from the
+// starting node are added to dst if they are not already present, and each
+// tree edge is added from its parent in the tree with the weight of the edge
+// in the analysed graph.
+func (p Shortest) Tree(dst graph.DirectedBuilder) {
+	for i, n := range p.nodes {
+		if math.IsInf(p.dist[i], 1) || dst.Has(n) {
+			continue
+		}
+		dst.AddNode(n)
+	}
+	for i, n := range p.nodes {
+		mid := p.next[i]
+		if mid < 0 || math.IsInf(p.dist[i], 1) {
+			continue
+		}
+		w, ok := p.weight(p.nodes[mid], n)
+		if !ok {
+			panic("path: unexpected invalid weight")
+		}
+		dst.SetEdge(simple.Edge{F: p.nodes[mid], T: n, W: w})
+	}
+}
+
 // AllShortest is a shortest-path tree created by the DijkstraAllPaths, FloydWarshall
 // or JohnsonAllPaths all-pairs shortest paths functions.
 type AllShortest struct {
@@ -144,6 +249,10 @@ type AllShortest struct {
 	// Warshall and reverse is used for
 	// Dijkstra.
 	forward bool
+
+	// negCycle describes the negative
+	// cycle found by JohnsonAllPaths.
+	negCycle *NegativeCycleError
 }
 
 func newAllShortest(nodes []graph.Node, forward bool) AllShortest {
@@ -165,6 +274,16 @@ func newAllShortest(nodes []graph.Node, forward bool) AllShortest {
 	}
 }
 
+// NegativeCycle returns a *NegativeCycleError describing the negative cycle
+// found during construction of the AllShortest, or nil if no negative cycle
+// was found.
+func (p AllShortest) NegativeCycle() error {
+	if p.negCycle == nil {
+		return nil
+	}
+	return p.negCycle
+}
+
 func (p AllShortest) at(from, to int) (mid []int) {
 	return p.next[from+to*len(p.nodes)]
 }
@@ -186,7 +305,29 @@ loop: // These are likely to be rare, so just loop over collisions.
 	}
 }
 
-// Weight returns the weight of the minimum path between u and v.
+// Reachable returns whether u and v are in the analysed graph and there is
+// a path from u to v.
+func (p AllShortest) Reachable(u, v graph.Node) bool {
+	return p.Check(u, v) == nil
+}
+
+// Check returns ErrAbsentNode if either u or v is not in the analysed graph,
+// ErrUnreachable if there is no path from u to v, and nil otherwise.
+func (p AllShortest) Check(u, v graph.Node) error {
+	from, fromOK := p.indexOf[u.ID()]
+	to, toOK := p.indexOf[v.ID()]
+	if !fromOK || !toOK {
+		return ErrAbsentNode
+	}
+	if math.IsInf(p.dist.At(from, to), 1) {
+		return ErrUnreachable
+	}
+	return nil
+}
+
+// Weight returns the weight of the minimum path between u and v. If there
+// is no such path, Weight returns positive infinity; Check distinguishes
+// the reasons for this.
 func (p AllShortest) Weight(u, v graph.Node) float64 {
 	from, fromOK := p.indexOf[u.ID()]
 	to, toOK := p.indexOf[v.ID()]
@@ -199,13 +340,56 @@ func (p AllShortest) Weight(u, v graph.Node) float64 {
 // Between returns a shortest path from u to v and the weight of the path. If more than
 // one shortest path exists between u and v, a randomly chosen path will be returned and
 // unique is returned false. If a cycle with zero weight exists in the path, it will not
-// be included, but unique will be returned false.
+// be included, but unique will be returned false. The path is chosen using rand.Intn;
+// BetweenRand may be used to choose it reproducibly.
 func (p AllShortest) Between(u, v graph.Node) (path []graph.Node, weight float64, unique bool) {
+	return p.BetweenRand(u, v, nil)
+}
+
+// BetweenRand returns a shortest path from u to v and the weight of the path, with the
+// same semantics as Between. If src is not nil it is used as the random source for
+// choosing between shortest paths, otherwise rand.Intn is used.
+func (p AllShortest) BetweenRand(u, v graph.Node, src *rand.Rand) (path []graph.Node, weight float64, unique bool) {
+	idx, weight, unique := p.between(u, v, intnFor(src))
+	if idx == nil {
+		return nil, weight, unique
+	}
+	path = make([]graph.Node, len(idx))
+	for i, j := range idx {
+		path[i] = p.nodes[j]
+	}
+	return path, weight, unique
+}
+
+// PathBetween returns a shortest path from u to v as a Path of node IDs and
+// the weight of the path, with the same semantics as Between.
+func (p AllShortest) PathBetween(u, v graph.Node) (path Path, weight float64, unique bool) {
+	return p.PathBetweenRand(u, v, nil)
+}
+
+// PathBetweenRand returns a shortest path from u to v as a Path of node IDs and
+// the weight of the path, with the same semantics as BetweenRand.
+func (p AllShortest) PathBetweenRand(u, v graph.Node, src *rand.Rand) (path Path, weight float64, unique bool) {
+	idx, weight, unique := p.between(u, v, intnFor(src))
+	if idx == nil {
+		return nil, weight, unique
+	}
+	path = make(Path, len(idx))
+	for i, j := range idx {
+		path[i] = p.nodes[j].ID()
+	}
+	return path, weight, unique
+}
+
+// between returns the indices of the nodes of a shortest path from u to v
+// and the weight and uniqueness of the path as described for Between, choosing
+// between shortest paths using rnd.
+func (p AllShortest) between(u, v graph.Node, rnd func(int) int) (path []int, weight float64, unique bool) {
 	from, fromOK := p.indexOf[u.ID()]
 	to, toOK := p.indexOf[v.ID()]
 	if !fromOK || !toOK || len(p.at(from, to)) == 0 {
 		if u.ID() == v.ID() {
-			return []graph.Node{p.nodes[from]}, 0, true
+			return []int{from}, 0, true
 		}
 		return nil, math.Inf(1), false
 	}
@@ -214,16 +398,16 @@ func (p AllShortest) Between(u, v graph.Node) (path []graph.Node, weight float64
 	for i := range seen {
 		seen[i] = -1
 	}
-	var n graph.Node
+	var n int
 	if p.forward {
-		n = p.nodes[from]
+		n = from
 		seen[from] = 0
 	} else {
-		n = p.nodes[to]
+		n = to
 		seen[to] = 0
 	}
 
-	path = []graph.Node{n}
+	path = []int{n}
 	weight = p.dist.At(from, to)
 	unique = true
 
@@ -232,7 +416,7 @@ func (p AllShortest) Between(u, v graph.Node) (path []graph.Node, weight float64
 		c := p.at(from, to)
 		if len(c) != 1 {
 			unique = false
-			next = c[rand.Intn(len(c))]
+			next = c[rnd(len(c))]
 		} else {
 			next = c[0]
 		}
@@ -240,7 +424,7 @@ func (p AllShortest) Between(u, v graph.Node) (path []graph.Node, weight float64
 			path = path[:seen[next]]
 		}
 		seen[next] = len(path)
-		path = append(path, p.nodes[next])
+		path = append(path, next)
 		if p.forward {
 			from = next
 		} else {
@@ -248,7 +432,9 @@ func (p AllShortest) Between(u, v graph.Node) (path []graph.Node, weight float64
 		}
 	}
 	if !p.forward {
-		reverse(path)
+		for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+			path[i], path[j] = path[j], path[i]
+		}
 	}
 
 	return path, weight, unique
@@ -312,6 +498,14 @@ func (p AllShortest) allBetween(from, to int, seen []bool, path []graph.Node, pa
 	return paths
 }
 
+// intnFor returns src.Intn, or rand.Intn if src is nil.
+func intnFor(src *rand.Rand) func(int) int {
+	if src == nil {
+		return rand.Intn
+	}
+	return src.Intn
+}
+
 func reverse(p []graph.Node) {
 	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
 		p[i], p[j] = p[j], p[i]
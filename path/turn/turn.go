@@ -0,0 +1,268 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package turn provides an edge-based graph expansion for routing with turn
+// costs and turn restrictions.
+package turn
+
+import (
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+// Cost returns the cost of turning at v when travelling along the edge from u
+// to v and then along the edge from v to w. If the turn is prohibited, ok is
+// returned false.
+type Cost func(u, v, w graph.Node) (cost float64, ok bool)
+
+// Graph is an edge-based expansion of a graph. Each node of the expansion
+// represents a directed edge of the original graph, and an edge in the
+// expansion from the node representing (u, v) to the node representing
+// (v, w) exists if the turn at v is permitted. The weight of that edge is
+// the weight of (v, w) in the original graph plus the cost of the turn.
+//
+// In addition, the expansion holds a start node for each node of the
+// original graph with edges to each of the expanded nodes representing the
+// original node's outgoing edges.
+//
+// Graph implements graph.Directed and graph.Weighter, so the expansion can
+// be used with the shortest path functions in the path package.
+type Graph struct {
+	orig  []graph.Node
+	arcs  [][2]int
+	start map[int]int
+
+	from, to [][]int
+	weight   map[[2]int]float64
+}
+
+var (
+	_ graph.Directed = (*Graph)(nil)
+	_ graph.Weighter = (*Graph)(nil)
+)
+
+// New returns the edge-based expansion of g using the provided turn cost
+// function. If turn is nil, all turns are permitted with zero cost. If g does
+// not implement graph.Weighter, path.UniformCost is used. For undirected
+// graphs, each edge is expanded into two arcs, and turns back along the
+// same edge are subject to the turn cost function like any other turn.
+func New(g graph.Graph, turn Cost) *Graph {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	t := &Graph{
+		orig:   nodes,
+		start:  make(map[int]int, len(nodes)),
+		weight: make(map[[2]int]float64),
+	}
+
+	// out holds the arc IDs leaving each original node and
+	// in holds the arc IDs entering each original node.
+	out := make([][]int, len(nodes))
+	in := make([][]int, len(nodes))
+	arcWeight := make([]float64, 0)
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			w, ok := weight(u, v)
+			if !ok {
+				panic("turn: unexpected invalid weight")
+			}
+			j := indexOf[v.ID()]
+			id := len(t.arcs)
+			t.arcs = append(t.arcs, [2]int{i, j})
+			arcWeight = append(arcWeight, w)
+			out[i] = append(out[i], id)
+			in[j] = append(in[j], id)
+		}
+	}
+
+	n := len(t.arcs) + len(nodes)
+	t.from = make([][]int, n)
+	t.to = make([][]int, n)
+	link := func(a, b int, w float64) {
+		t.from[a] = append(t.from[a], b)
+		t.to[b] = append(t.to[b], a)
+		t.weight[[2]int{a, b}] = w
+	}
+	for i, u := range nodes {
+		s := len(t.arcs) + i
+		t.start[u.ID()] = s
+		for _, b := range out[i] {
+			link(s, b, arcWeight[b])
+		}
+	}
+	for a, arc := range t.arcs {
+		v := arc[1]
+		for _, b := range out[v] {
+			c := 0.0
+			if turn != nil {
+				var ok bool
+				c, ok = turn(nodes[arc[0]], nodes[v], nodes[t.arcs[b][1]])
+				if !ok {
+					continue
+				}
+			}
+			link(a, b, arcWeight[b]+c)
+		}
+	}
+	return t
+}
+
+// Start returns the start node in the expansion for the original node n.
+func (g *Graph) Start(n graph.Node) (graph.Node, bool) {
+	id, ok := g.start[n.ID()]
+	if !ok {
+		return nil, false
+	}
+	return simple.Node(id), true
+}
+
+// Arc returns the original edge terminals represented by the expanded node
+// n. If n is a start node, from is returned nil and to is the original node.
+// If n is not a node of the expansion, ok is returned false.
+func (g *Graph) Arc(n graph.Node) (from, to graph.Node, ok bool) {
+	id := n.ID()
+	switch {
+	case id < 0 || id >= len(g.from):
+		return nil, nil, false
+	case id < len(g.arcs):
+		a := g.arcs[id]
+		return g.orig[a[0]], g.orig[a[1]], true
+	default:
+		return nil, g.orig[id-len(g.arcs)], true
+	}
+}
+
+// Has returns whether the node exists within the graph.
+func (g *Graph) Has(n graph.Node) bool {
+	id := n.ID()
+	return 0 <= id && id < len(g.from)
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *Graph) Nodes() []graph.Node {
+	nodes := make([]graph.Node, len(g.from))
+	for i := range nodes {
+		nodes[i] = simple.Node(i)
+	}
+	return nodes
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *Graph) From(n graph.Node) []graph.Node {
+	if !g.Has(n) {
+		return nil
+	}
+	return toNodes(g.from[n.ID()])
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (g *Graph) To(n graph.Node) []graph.Node {
+	if !g.Has(n) {
+		return nil
+	}
+	return toNodes(g.to[n.ID()])
+}
+
+func toNodes(ids []int) []graph.Node {
+	nodes := make([]graph.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = simple.Node(id)
+	}
+	return nodes
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *Graph) HasEdgeBetween(x, y graph.Node) bool {
+	return g.HasEdgeFromTo(x, y) || g.HasEdgeFromTo(y, x)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *Graph) HasEdgeFromTo(u, v graph.Node) bool {
+	_, ok := g.weight[[2]int{u.ID(), v.ID()}]
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *Graph) Edge(u, v graph.Node) graph.Edge {
+	w, ok := g.weight[[2]int{u.ID(), v.ID()}]
+	if !ok {
+		return nil
+	}
+	return simple.Edge{F: simple.Node(u.ID()), T: simple.Node(v.ID()), W: w}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node the weight returned is zero, and if there is no joining edge
+// between the two nodes the weight value returned is +Inf. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (g *Graph) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return 0, true
+	}
+	w, ok = g.weight[[2]int{x.ID(), y.ID()}]
+	if !ok {
+		return math.Inf(1), false
+	}
+	return w, true
+}
+
+// Shortest returns a shortest path from s to t in the original graph that
+// respects the turn restrictions of the expansion, and the weight of the path
+// including turn costs. If no such path exists, Shortest returns a nil path
+// and an infinite weight.
+func (g *Graph) Shortest(s, t graph.Node) (p []graph.Node, weight float64) {
+	start, ok := g.Start(s)
+	if !ok {
+		return nil, math.Inf(1)
+	}
+	if _, ok := g.start[t.ID()]; !ok {
+		return nil, math.Inf(1)
+	}
+	if s.ID() == t.ID() {
+		return []graph.Node{g.orig[start.ID()-len(g.arcs)]}, 0
+	}
+
+	pt := path.DijkstraFrom(start, g)
+	best := -1
+	weight = math.Inf(1)
+	for id, a := range g.arcs {
+		if g.orig[a[1]].ID() != t.ID() {
+			continue
+		}
+		if w := pt.WeightTo(simple.Node(id)); w < weight {
+			best, weight = id, w
+		}
+	}
+	if best < 0 {
+		return nil, math.Inf(1)
+	}
+
+	ep, _ := pt.To(simple.Node(best))
+	p = make([]graph.Node, len(ep))
+	for i, n := range ep {
+		_, p[i], _ = g.Arc(n)
+	}
+	return p, weight
+}
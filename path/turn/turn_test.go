@@ -0,0 +1,139 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package turn
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func turnAt(u, v, w int, cost float64, ok bool) Cost {
+	return func(a, b, c graph.Node) (float64, bool) {
+		if a.ID() == u && b.ID() == v && c.ID() == w {
+			return cost, ok
+		}
+		return 0, true
+	}
+}
+
+var shortestTests = []struct {
+	name string
+	turn Cost
+	s, t int
+
+	want   []int
+	weight float64
+}{
+	{
+		name:   "unrestricted",
+		s:      0,
+		t:      2,
+		want:   []int{0, 1, 2},
+		weight: 2,
+	},
+	{
+		name:   "prohibited",
+		turn:   turnAt(0, 1, 2, 0, false),
+		s:      0,
+		t:      2,
+		want:   []int{0, 3, 2},
+		weight: 4,
+	},
+	{
+		name:   "cheap turn",
+		turn:   turnAt(0, 1, 2, 1, true),
+		s:      0,
+		t:      2,
+		want:   []int{0, 1, 2},
+		weight: 3,
+	},
+	{
+		name:   "expensive turn",
+		turn:   turnAt(0, 1, 2, 5, true),
+		s:      0,
+		t:      2,
+		want:   []int{0, 3, 2},
+		weight: 4,
+	},
+	{
+		name:   "via restricted node",
+		turn:   turnAt(0, 1, 2, 0, false),
+		s:      0,
+		t:      4,
+		want:   []int{0, 1, 4},
+		weight: 2,
+	},
+	{
+		name:   "unreachable",
+		s:      2,
+		t:      0,
+		want:   nil,
+		weight: math.Inf(1),
+	},
+	{
+		name:   "same",
+		s:      1,
+		t:      1,
+		want:   []int{1},
+		weight: 0,
+	},
+}
+
+func TestShortest(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(0), T: simple.Node(3), W: 2},
+		{F: simple.Node(3), T: simple.Node(2), W: 2},
+		{F: simple.Node(1), T: simple.Node(4), W: 1},
+	} {
+		g.SetEdge(e)
+	}
+
+	for _, test := range shortestTests {
+		p, w := New(g, test.turn).Shortest(simple.Node(test.s), simple.Node(test.t))
+		var got []int
+		for _, n := range p {
+			got = append(got, n.ID())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected path: got:%v want:%v", test.name, got, test.want)
+		}
+		if w != test.weight {
+			t.Errorf("%q: unexpected weight: got:%v want:%v", test.name, w, test.weight)
+		}
+	}
+}
+
+func TestUTurn(t *testing.T) {
+	// A two-way road with U-turns prohibited.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	noUTurn := func(u, v, w graph.Node) (float64, bool) { return 0, u.ID() != w.ID() }
+
+	p, w := New(g, noUTurn).Shortest(simple.Node(0), simple.Node(2))
+	if len(p) != 3 || w != 2 {
+		t.Errorf("unexpected path: got:%v weight:%v", p, w)
+	}
+	p, w = New(g, noUTurn).Shortest(simple.Node(0), simple.Node(0))
+	if len(p) != 1 || w != 0 {
+		t.Errorf("unexpected path to self: got:%v weight:%v", p, w)
+	}
+
+	exp := New(g, noUTurn)
+	s, _ := exp.Start(simple.Node(1))
+	for _, n := range exp.From(s) {
+		from, _, ok := exp.Arc(n)
+		if !ok || from.ID() != 1 {
+			t.Errorf("unexpected arc from start node: %v", n)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Voronoi is a partition of the nodes of a graph into cells, each holding
+// the nodes closer to one seed node than to any other, and the shortest-path
+// forest from the seeds. It is created by VoronoiFrom.
+type Voronoi struct {
+	// nodes hold the nodes of the analysed
+	// graph.
+	nodes []graph.Node
+	// indexOf contains a mapping between
+	// the id-dense representation of the
+	// graph and the potentially id-sparse
+	// nodes held in nodes.
+	indexOf map[int]int
+
+	// dist, next and seed represent the
+	// shortest paths from the seeds.
+	//
+	// Indices into dist, next and seed
+	// are mapped through indexOf.
+	//
+	// dist contains the distance to the
+	// nearest seed for each node, next
+	// contains the shortest-path forest
+	// and seed contains the index of the
+	// nearest seed, or -1 if no seed is
+	// reachable.
+	dist []float64
+	next []int
+	seed []int
+}
+
+// VoronoiFrom returns the Voronoi partition of the nodes of g by their
+// shortest-path distance from the given seed nodes, computed with a single
+// multi-source Dijkstra search. Distances are measured along paths from the
+// seeds. When a node is equally close to more than one seed it is assigned
+// to the seed with the lowest ID. Seeds that are not in g are ignored. If
+// the graph does not implement graph.Weighter, UniformCost is used.
+// VoronoiFrom will panic if g has a seed-reachable negative edge weight.
+//
+// The time complexity of VoronoiFrom is O(|E|.log|V|).
+func VoronoiFrom(g graph.Graph, seeds []graph.Node) Voronoi {
+	var weight Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	v := Voronoi{
+		nodes:   nodes,
+		indexOf: indexOf,
+
+		dist: make([]float64, len(nodes)),
+		next: make([]int, len(nodes)),
+		seed: make([]int, len(nodes)),
+	}
+	for i := range nodes {
+		v.dist[i] = math.Inf(1)
+		v.next[i] = -1
+		v.seed[i] = -1
+	}
+
+	var Q priorityQueue
+	for _, s := range seeds {
+		i, ok := indexOf[s.ID()]
+		if !ok || v.seed[i] == i {
+			continue
+		}
+		v.dist[i] = 0
+		v.seed[i] = i
+		Q = append(Q, distanceNode{node: nodes[i], dist: 0})
+	}
+	heap.Init(&Q)
+	for Q.Len() != 0 {
+		mid := heap.Pop(&Q).(distanceNode)
+		k := indexOf[mid.node.ID()]
+		if mid.dist > v.dist[k] {
+			continue
+		}
+		for _, u := range g.From(mid.node) {
+			j := indexOf[u.ID()]
+			w, ok := weight(mid.node, u)
+			if !ok {
+				panic("voronoi: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("voronoi: negative edge weight")
+			}
+			joint := v.dist[k] + w
+			// Ties are broken in favor of the seed
+			// with the lower ID. A tied node may be
+			// re-expanded to propagate its new seed.
+			if joint < v.dist[j] || (joint == v.dist[j] && v.lowerSeed(v.seed[k], v.seed[j]) && j != v.seed[j]) {
+				v.dist[j] = joint
+				v.next[j] = k
+				v.seed[j] = v.seed[k]
+				heap.Push(&Q, distanceNode{node: u, dist: joint})
+			}
+		}
+	}
+
+	return v
+}
+
+// lowerSeed returns whether the seed at index a has a lower ID than the
+// seed at index b.
+func (v Voronoi) lowerSeed(a, b int) bool {
+	return b < 0 || v.nodes[a].ID() < v.nodes[b].ID()
+}
+
+// Seed returns the seed nearest to n. If no seed is reachable, Seed
+// returns nil.
+func (v Voronoi) Seed(n graph.Node) graph.Node {
+	i, ok := v.indexOf[n.ID()]
+	if !ok || v.seed[i] < 0 {
+		return nil
+	}
+	return v.nodes[v.seed[i]]
+}
+
+// Reachable returns whether n is in the analysed graph and there is a path
+// to n from a seed.
+func (v Voronoi) Reachable(n graph.Node) bool {
+	return v.Check(n) == nil
+}
+
+// Check returns ErrAbsentNode if n is not in the analysed graph,
+// ErrUnreachable if there is no path to n from a seed, and nil otherwise.
+func (v Voronoi) Check(n graph.Node) error {
+	i, ok := v.indexOf[n.ID()]
+	if !ok {
+		return ErrAbsentNode
+	}
+	if v.seed[i] < 0 {
+		return ErrUnreachable
+	}
+	return nil
+}
+
+// WeightTo returns the weight of the minimum path to n from its nearest seed.
+func (v Voronoi) WeightTo(n graph.Node) float64 {
+	i, ok := v.indexOf[n.ID()]
+	if !ok {
+		return math.Inf(1)
+	}
+	return v.dist[i]
+}
+
+// To returns a shortest path to n from its nearest seed and the weight of
+// the path.
+func (v Voronoi) To(n graph.Node) (path []graph.Node, weight float64) {
+	to, ok := v.indexOf[n.ID()]
+	if !ok || v.seed[to] < 0 {
+		return nil, math.Inf(1)
+	}
+	for i := to; i >= 0; i = v.next[i] {
+		path = append(path, v.nodes[i])
+	}
+	reverse(path)
+	return path, v.dist[to]
+}
+
+// Cells returns the nodes in the cell of each seed, keyed on the seed ID.
+// The nodes of each cell are ordered by ID. Nodes not reachable from any
+// seed are not included.
+func (v Voronoi) Cells() map[int][]graph.Node {
+	cells := make(map[int][]graph.Node)
+	for i, n := range v.nodes {
+		if v.seed[i] < 0 {
+			continue
+		}
+		id := v.nodes[v.seed[i]].ID()
+		cells[id] = append(cells[id], n)
+	}
+	for _, c := range cells {
+		sort.Sort(ordered.ByID(c))
+	}
+	return cells
+}
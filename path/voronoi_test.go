@@ -0,0 +1,96 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestVoronoiFrom(t *testing.T) {
+	// A path 0-1-2-3-4-5-6 with seeds at either end
+	// and an unreachable node 7.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < 6; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1), W: 1})
+	}
+	g.AddNode(simple.Node(7))
+
+	v := VoronoiFrom(g, []graph.Node{simple.Node(6), simple.Node(0)})
+	want := map[int][]graph.Node{
+		0: {simple.Node(0), simple.Node(1), simple.Node(2), simple.Node(3)},
+		6: {simple.Node(4), simple.Node(5), simple.Node(6)},
+	}
+	if got := v.Cells(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected cells: got:%v want:%v", got, want)
+	}
+	if s := v.Seed(simple.Node(7)); s != nil {
+		t.Errorf("unexpected seed for unreachable node: got:%v", s)
+	}
+	if err := v.Check(simple.Node(7)); err != ErrUnreachable {
+		t.Errorf("unexpected check for unreachable node: got:%v want:%v", err, ErrUnreachable)
+	}
+	if err := v.Check(simple.Node(8)); err != ErrAbsentNode {
+		t.Errorf("unexpected check for absent node: got:%v want:%v", err, ErrAbsentNode)
+	}
+	if !v.Reachable(simple.Node(3)) {
+		t.Errorf("expected node 3 to be reachable")
+	}
+	p, w := v.To(simple.Node(4))
+	if wantPath := []graph.Node{simple.Node(6), simple.Node(5), simple.Node(4)}; !reflect.DeepEqual(p, wantPath) || w != 2 {
+		t.Errorf("unexpected path to 4: got:%v %v want:%v 2", p, w, wantPath)
+	}
+}
+
+func TestVoronoiFromRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 200
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < 4*n; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u != v {
+			// Integer weights make ties likely.
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: float64(1 + rnd.Intn(3))})
+		}
+	}
+	seeds := []graph.Node{simple.Node(17), simple.Node(3), simple.Node(150), simple.Node(99)}
+	trees := make([]Shortest, len(seeds))
+	for i, s := range seeds {
+		trees[i] = DijkstraFrom(s, g)
+	}
+
+	v := VoronoiFrom(g, seeds)
+	for _, n := range g.Nodes() {
+		wantDist := math.Inf(1)
+		var wantSeed graph.Node
+		for i, s := range seeds {
+			d := trees[i].WeightTo(n)
+			if d < wantDist || (d == wantDist && wantSeed != nil && s.ID() < wantSeed.ID()) {
+				wantDist, wantSeed = d, s
+			}
+		}
+		if math.IsInf(wantDist, 1) {
+			wantSeed = nil
+		}
+		if got := v.WeightTo(n); got != wantDist {
+			t.Errorf("unexpected distance for node %d: got:%v want:%v", n.ID(), got, wantDist)
+		}
+		got := v.Seed(n)
+		if (got == nil) != (wantSeed == nil) || (got != nil && got.ID() != wantSeed.ID()) {
+			t.Errorf("unexpected seed for node %d: got:%v want:%v", n.ID(), got, wantSeed)
+		}
+		if p, w := v.To(n); wantSeed != nil && (p[0].ID() != wantSeed.ID() || p[len(p)-1].ID() != n.ID() || w != wantDist) {
+			t.Errorf("unexpected path to node %d: got:%v %v", n.ID(), p, w)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package propagate provides graph-based semi-supervised label propagation
+// and graph signal smoothing.
+package propagate
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+)
+
+// Harmonic returns class scores for the nodes of the undirected graph g given
+// the known class labels of a subset of the nodes. The labels map is keyed on
+// node IDs and holds non-negative class indices. The returned map is keyed on
+// the graph node IDs and holds for each node a slice of scores indexed by
+// class.
+//
+// The scores are the harmonic solution of Zhu, Ghahramani and Lafferty
+// described in http://mlg.eng.cam.ac.uk/zoubin/papers/zgl.pdf. Labeled nodes
+// keep their labels and the score of each unlabeled node is the weighted mean
+// of the scores of its neighbors. Nodes in components with no labeled nodes
+// have zero scores. Harmonic terminates when the 2-norm of the vector
+// difference between iterations is below tol.
+//
+// Edge weights are treated as similarities. If g does not implement
+// graph.Weighter, path.UniformCost is used. Harmonic will panic if g has a
+// negative edge weight or a label is negative.
+func Harmonic(g graph.Undirected, labels map[int]int, tol float64) map[int][]float64 {
+	a := newAdjacency(g)
+	y, classes := a.labelMatrix(labels)
+
+	f := make([]float64, len(y))
+	copy(f, y)
+	next := make([]float64, len(y))
+	for {
+		var norm float64
+		for i := range a.nodes {
+			if _, ok := labels[a.nodes[i].ID()]; ok {
+				copy(next[i*classes:(i+1)*classes], y[i*classes:(i+1)*classes])
+				continue
+			}
+			for c := 0; c < classes; c++ {
+				var sum float64
+				for _, e := range a.edges[i] {
+					sum += e.w * f[e.to*classes+c]
+				}
+				if a.degree[i] != 0 {
+					sum /= a.degree[i]
+				}
+				next[i*classes+c] = sum
+				d := sum - f[i*classes+c]
+				norm += d * d
+			}
+		}
+		f, next = next, f
+		if math.Sqrt(norm) < tol {
+			break
+		}
+	}
+	return a.scores(f, classes)
+}
+
+// LocalGlobal returns class scores for the nodes of the undirected graph g
+// given the known class labels of a subset of the nodes using the local and
+// global consistency method of Zhou et al. described in
+// http://papers.nips.cc/paper/2506-learning-with-local-and-global-consistency.pdf.
+// The labels map is keyed on node IDs and holds non-negative class indices.
+// The returned map is keyed on the graph node IDs and holds for each node a
+// slice of scores indexed by class.
+//
+// The scores are the limit of the iteration
+//  F ← α S F + (1-α) Y,
+// where S = D^{-1/2} W D^{-1/2} is the normalized weight matrix of g and Y
+// holds the initial labels. Unlike Harmonic, labeled nodes may change their
+// scores. The parameter alpha in (0, 1) controls the relative weighting of
+// neighbor information and the initial labels. LocalGlobal terminates when the
+// 2-norm of the vector difference between iterations is below tol.
+//
+// Edge weights are treated as similarities. If g does not implement
+// graph.Weighter, path.UniformCost is used. LocalGlobal will panic if g has a
+// negative edge weight, a label is negative or alpha is not in (0, 1).
+func LocalGlobal(g graph.Undirected, labels map[int]int, alpha, tol float64) map[int][]float64 {
+	if alpha <= 0 || alpha >= 1 {
+		panic("propagate: alpha out of range")
+	}
+	a := newAdjacency(g)
+	y, classes := a.labelMatrix(labels)
+
+	invSqrt := make([]float64, len(a.nodes))
+	for i, d := range a.degree {
+		if d != 0 {
+			invSqrt[i] = 1 / math.Sqrt(d)
+		}
+	}
+
+	f := make([]float64, len(y))
+	copy(f, y)
+	next := make([]float64, len(y))
+	for {
+		var norm float64
+		for i := range a.nodes {
+			for c := 0; c < classes; c++ {
+				var sum float64
+				for _, e := range a.edges[i] {
+					sum += e.w * invSqrt[e.to] * f[e.to*classes+c]
+				}
+				v := alpha*invSqrt[i]*sum + (1-alpha)*y[i*classes+c]
+				next[i*classes+c] = v
+				d := v - f[i*classes+c]
+				norm += d * d
+			}
+		}
+		f, next = next, f
+		if math.Sqrt(norm) < tol {
+			break
+		}
+	}
+	return a.scores(f, classes)
+}
+
+// Classify returns the class with the highest score for each node in scores.
+// Ties are broken by the lower class index. Nodes with no positive score are
+// given the class -1.
+func Classify(scores map[int][]float64) map[int]int {
+	classes := make(map[int]int, len(scores))
+	for id, s := range scores {
+		best := -1
+		max := 0.0
+		for c, v := range s {
+			if v > max {
+				best, max = c, v
+			}
+		}
+		classes[id] = best
+	}
+	return classes
+}
+
+// Smooth returns a smoothed version of the signal x over the nodes of the
+// undirected graph g. The smoothed signal f minimizes
+//  ||f - x||^2 + λ f^T L f,
+// where L is the weighted graph Laplacian of g, by solving (I + λL) f = x.
+// Larger values of lambda give smoother signals. Nodes absent from x are
+// treated as having a zero signal. The returned map is keyed on the graph
+// node IDs. Smooth terminates when the 2-norm of the vector difference
+// between iterations is below tol.
+//
+// If g does not implement graph.Weighter, path.UniformCost is used. Smooth
+// will panic if g has a negative edge weight or lambda is negative.
+func Smooth(g graph.Undirected, x map[int]float64, lambda, tol float64) map[int]float64 {
+	if lambda < 0 {
+		panic("propagate: negative lambda")
+	}
+	a := newAdjacency(g)
+	signal := make([]float64, len(a.nodes))
+	for i, n := range a.nodes {
+		signal[i] = x[n.ID()]
+	}
+
+	// Jacobi iteration converges since
+	// I + λL is diagonally dominant.
+	f := make([]float64, len(a.nodes))
+	copy(f, signal)
+	next := make([]float64, len(a.nodes))
+	for {
+		var norm float64
+		for i := range a.nodes {
+			var sum float64
+			for _, e := range a.edges[i] {
+				sum += e.w * f[e.to]
+			}
+			v := (signal[i] + lambda*sum) / (1 + lambda*a.degree[i])
+			next[i] = v
+			d := v - f[i]
+			norm += d * d
+		}
+		f, next = next, f
+		if math.Sqrt(norm) < tol {
+			break
+		}
+	}
+
+	smooth := make(map[int]float64, len(a.nodes))
+	for i, n := range a.nodes {
+		smooth[n.ID()] = f[i]
+	}
+	return smooth
+}
+
+// adjacency is a weighted adjacency list with dense node indices.
+type adjacency struct {
+	nodes   []graph.Node
+	indexOf map[int]int
+	edges   [][]edge
+
+	// degree holds the weighted
+	// degree of each node.
+	degree []float64
+}
+
+type edge struct {
+	to int
+	w  float64
+}
+
+func newAdjacency(g graph.Undirected) adjacency {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	a := adjacency{
+		nodes:   nodes,
+		indexOf: make(map[int]int, len(nodes)),
+		edges:   make([][]edge, len(nodes)),
+		degree:  make([]float64, len(nodes)),
+	}
+	for i, n := range nodes {
+		a.indexOf[n.ID()] = i
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			j := a.indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			w, ok := weight(u, v)
+			if !ok {
+				panic("propagate: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("propagate: negative edge weight")
+			}
+			a.edges[i] = append(a.edges[i], edge{to: j, w: w})
+			a.degree[i] += w
+		}
+	}
+	return a
+}
+
+// labelMatrix returns the row major initial label matrix for the labels and
+// the number of classes.
+func (a adjacency) labelMatrix(labels map[int]int) (y []float64, classes int) {
+	for _, c := range labels {
+		if c < 0 {
+			panic("propagate: negative class label")
+		}
+		if c >= classes {
+			classes = c + 1
+		}
+	}
+	y = make([]float64, len(a.nodes)*classes)
+	for id, c := range labels {
+		if i, ok := a.indexOf[id]; ok {
+			y[i*classes+c] = 1
+		}
+	}
+	return y, classes
+}
+
+// scores returns the row major score matrix f keyed by node ID.
+func (a adjacency) scores(f []float64, classes int) map[int][]float64 {
+	s := make(map[int][]float64, len(a.nodes))
+	for i, n := range a.nodes {
+		s[n.ID()] = f[i*classes : (i+1)*classes : (i+1)*classes]
+	}
+	return s
+}
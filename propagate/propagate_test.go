@@ -0,0 +1,133 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package propagate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+func pathGraph(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 1; i < n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i - 1), T: simple.Node(i), W: 1})
+	}
+	return g
+}
+
+// barbell returns two 5-cliques, {0..4} and {5..9}, joined by the edge 4-5.
+func barbell() *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, off := range []int{0, 5} {
+		for i := 0; i < 5; i++ {
+			for j := i + 1; j < 5; j++ {
+				g.SetEdge(simple.Edge{F: simple.Node(off + i), T: simple.Node(off + j), W: 1})
+			}
+		}
+	}
+	g.SetEdge(simple.Edge{F: simple.Node(4), T: simple.Node(5), W: 1})
+	return g
+}
+
+func TestHarmonicPath(t *testing.T) {
+	const tol = 1e-6
+	scores := Harmonic(pathGraph(5), map[int]int{0: 0, 4: 1}, 1e-12)
+	for i := 0; i < 5; i++ {
+		want := []float64{1 - float64(i)/4, float64(i) / 4}
+		got := scores[i]
+		if len(got) != 2 || math.Abs(got[0]-want[0]) > tol || math.Abs(got[1]-want[1]) > tol {
+			t.Errorf("unexpected scores for node %d: got:%v want:%v", i, got, want)
+		}
+	}
+}
+
+func TestHarmonicUnlabeledComponent(t *testing.T) {
+	g := pathGraph(3)
+	g.SetEdge(simple.Edge{F: simple.Node(10), T: simple.Node(11), W: 1})
+	classes := Classify(Harmonic(g, map[int]int{0: 1}, 1e-12))
+	for id, want := range map[int]int{0: 1, 1: 1, 2: 1, 10: -1, 11: -1} {
+		if classes[id] != want {
+			t.Errorf("unexpected class for node %d: got:%d want:%d", id, classes[id], want)
+		}
+	}
+}
+
+func TestPropagateBarbell(t *testing.T) {
+	labels := map[int]int{0: 0, 9: 1}
+	for _, test := range []struct {
+		name   string
+		scores map[int][]float64
+	}{
+		{name: "harmonic", scores: Harmonic(barbell(), labels, 1e-12)},
+		{name: "local-global", scores: LocalGlobal(barbell(), labels, 0.9, 1e-12)},
+	} {
+		classes := Classify(test.scores)
+		for id := 0; id < 10; id++ {
+			want := 0
+			if id >= 5 {
+				want = 1
+			}
+			if classes[id] != want {
+				t.Errorf("%s: unexpected class for node %d: got:%d want:%d", test.name, id, classes[id], want)
+			}
+		}
+	}
+}
+
+func TestLocalGlobalPanics(t *testing.T) {
+	for _, alpha := range []float64{0, 1, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for alpha=%v", alpha)
+				}
+			}()
+			LocalGlobal(pathGraph(3), map[int]int{0: 0}, alpha, 1e-6)
+		}()
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	const tol = 1e-8
+	g := pathGraph(5)
+	x := map[int]float64{0: 1, 1: -1, 2: 1, 3: -1, 4: 1}
+
+	got := Smooth(g, x, 0, 1e-12)
+	for id, v := range x {
+		if math.Abs(got[id]-v) > tol {
+			t.Errorf("unexpected smoothed value with lambda=0 for node %d: got:%v want:%v", id, got[id], v)
+		}
+	}
+
+	constant := map[int]float64{0: 2, 1: 2, 2: 2, 3: 2, 4: 2}
+	got = Smooth(g, constant, 10, 1e-12)
+	for id, v := range constant {
+		if math.Abs(got[id]-v) > tol {
+			t.Errorf("unexpected smoothed constant for node %d: got:%v want:%v", id, got[id], v)
+		}
+	}
+
+	// Smoothing reduces the total variation and preserves the mean.
+	variation := func(f map[int]float64) float64 {
+		var v float64
+		for i := 1; i < 5; i++ {
+			v += math.Abs(f[i] - f[i-1])
+		}
+		return v
+	}
+	got = Smooth(g, x, 1, 1e-12)
+	if variation(got) >= variation(x) {
+		t.Errorf("smoothing did not reduce variation: got:%v want:<%v", variation(got), variation(x))
+	}
+	var sum float64
+	for _, v := range got {
+		sum += v
+	}
+	if math.Abs(sum-1) > tol {
+		t.Errorf("smoothing did not preserve signal sum: got:%v want:1", sum)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recognize
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Cactus is a decomposition of a graph in which every edge lies on at most
+// one cycle.
+type Cactus struct {
+	// Cycles holds the cycles of the graph.
+	// Each cycle starts at its node nearest
+	// the root of a depth first search and
+	// lists the remaining nodes in order.
+	Cycles [][]graph.Node
+
+	// Bridges holds the edges of the graph
+	// that lie on no cycle.
+	Bridges []graph.Edge
+
+	// order is the depth first preorder
+	// and parent and cycle hold for each
+	// node its parent index and the index
+	// of the cycle containing the edge to
+	// its parent, or -1.
+	order  []int
+	parent []int
+	cycle  []int
+}
+
+// IsCactus returns whether every edge of g lies on at most one cycle, that
+// is, whether each connected component of g is a cactus.
+func IsCactus(g graph.Undirected) bool {
+	_, ok := CactusOf(g)
+	return ok
+}
+
+// CactusOf returns the cactus decomposition of g and whether every edge of g
+// lies on at most one cycle.
+//
+// The time complexity of CactusOf is O(|V|+|E|).
+func CactusOf(g graph.Undirected) (*Cactus, bool) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	c := &Cactus{
+		parent: make([]int, len(nodes)),
+		cycle:  make([]int, len(nodes)),
+	}
+	depth := make([]int, len(nodes))
+	for i := range nodes {
+		c.parent[i] = -1
+		c.cycle[i] = -1
+		depth[i] = -1
+	}
+
+	type frame struct {
+		u    int
+		next []graph.Node
+	}
+	for root := range nodes {
+		if depth[root] >= 0 {
+			continue
+		}
+		depth[root] = 0
+		c.order = append(c.order, root)
+		stack := []frame{{u: root, next: g.From(nodes[root])}}
+		for len(stack) != 0 {
+			top := &stack[len(stack)-1]
+			if len(top.next) == 0 {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			u := top.u
+			v := indexOf[top.next[0].ID()]
+			top.next = top.next[1:]
+			switch {
+			case depth[v] < 0:
+				depth[v] = depth[u] + 1
+				c.parent[v] = u
+				c.order = append(c.order, v)
+				stack = append(stack, frame{u: v, next: g.From(nodes[v])})
+			case v != c.parent[u] && depth[v] < depth[u]:
+				// A back edge closes the cycle formed
+				// with the tree path from v to u.
+				id := len(c.Cycles)
+				var cyc []graph.Node
+				for x := u; x != v; x = c.parent[x] {
+					if c.cycle[x] >= 0 {
+						return nil, false
+					}
+					c.cycle[x] = id
+					cyc = append(cyc, nodes[x])
+				}
+				cyc = append(cyc, nodes[v])
+				reverseNodes(cyc)
+				c.Cycles = append(c.Cycles, cyc)
+			}
+		}
+	}
+
+	for _, v := range c.order {
+		if u := c.parent[v]; u >= 0 && c.cycle[v] < 0 {
+			c.Bridges = append(c.Bridges, g.Edge(nodes[u], nodes[v]))
+		}
+	}
+	return c, true
+}
+
+// MatchingNumber returns the number of edges in a maximum matching of the
+// graph described by c.
+//
+// The time complexity of MatchingNumber is O(|V|).
+func (c *Cactus) MatchingNumber() int {
+	n := len(c.parent)
+
+	// Each cycle hangs from its top node, the
+	// parent of its first member in preorder.
+	members := make([][]int, len(c.Cycles))
+	for _, v := range c.order {
+		if id := c.cycle[v]; id >= 0 {
+			members[id] = append(members[id], v)
+		}
+	}
+
+	// free[v] is the size of a maximum matching
+	// of the part of the graph hanging below v
+	// leaving v unmatched, and best[v] is the
+	// size of a maximum matching of that part.
+	free := make([]int, n)
+	best := make([]int, n)
+	gain := make([]int, n)
+	for i := len(c.order) - 1; i >= 0; i-- {
+		v := c.order[i]
+		best[v] = free[v] + gain[v]
+
+		u := c.parent[v]
+		if u < 0 {
+			continue
+		}
+		id := c.cycle[v]
+		switch {
+		case id < 0:
+			free[u] += best[v]
+			gain[u] = max(gain[u], free[v]+1-best[v])
+		case v == members[id][0]:
+			// The cycle is complete below its
+			// top node, u.
+			m := members[id]
+			k := len(m)
+			g0 := chainMatching(m, free, best)
+			g1 := max(
+				free[m[0]]+1+chainMatching(m[1:], free, best),
+				free[m[k-1]]+1+chainMatching(m[:k-1], free, best),
+			)
+			free[u] += g0
+			gain[u] = max(gain[u], g1-g0)
+		}
+	}
+
+	var size int
+	for _, v := range c.order {
+		if c.parent[v] < 0 {
+			size += best[v]
+		}
+	}
+	return size
+}
+
+// chainMatching returns the size of a maximum matching of the path of
+// nodes in chain and the parts of the graph hanging below them.
+func chainMatching(chain []int, free, best []int) int {
+	const none = -1
+	d, e := 0, none
+	for _, v := range chain {
+		nd := d + best[v]
+		if e != none {
+			nd = max(nd, e+free[v]+1)
+		}
+		d, e = nd, d+free[v]
+	}
+	return d
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func reverseNodes(p []graph.Node) {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+}
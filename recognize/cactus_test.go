@@ -0,0 +1,157 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recognize
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+var cactusTests = []struct {
+	name  string
+	edges [][2]int
+
+	want     bool
+	cycles   int
+	matching int
+}{
+	{name: "triangle", edges: [][2]int{{0, 1}, {1, 2}, {2, 0}}, want: true, cycles: 1, matching: 1},
+	{name: "bowtie", edges: [][2]int{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 2}}, want: true, cycles: 2, matching: 2},
+	{name: "tree", edges: [][2]int{{0, 1}, {1, 2}, {1, 3}, {3, 4}}, want: true, cycles: 0, matching: 2},
+	{name: "diamond", edges: [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {0, 2}}, want: false},
+	{name: "K4", edges: [][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}, want: false},
+	{
+		name:  "two components",
+		edges: [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {4, 5}, {5, 6}, {6, 4}, {6, 7}},
+		want:  true, cycles: 2, matching: 4,
+	},
+}
+
+func TestCactus(t *testing.T) {
+	for _, test := range cactusTests {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+		}
+
+		c, ok := CactusOf(g)
+		if ok != test.want {
+			t.Errorf("%q: unexpected result: got:%t want:%t", test.name, ok, test.want)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if len(c.Cycles) != test.cycles {
+			t.Errorf("%q: unexpected number of cycles: got:%d want:%d", test.name, len(c.Cycles), test.cycles)
+		}
+		checkCactus(t, test.name, g, c)
+		if m := c.MatchingNumber(); m != test.matching {
+			t.Errorf("%q: unexpected matching number: got:%d want:%d", test.name, m, test.matching)
+		}
+	}
+}
+
+func TestCactusMatchingRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		g.AddNode(simple.Node(0))
+		n := 1
+		for n < 12 {
+			at := simple.Node(rnd.Intn(n))
+			if rnd.Intn(2) == 0 {
+				g.SetEdge(simple.Edge{F: at, T: simple.Node(n), W: 1})
+				n++
+				continue
+			}
+			k := 2 + rnd.Intn(3)
+			prev := at
+			for i := 0; i < k; i++ {
+				g.SetEdge(simple.Edge{F: prev, T: simple.Node(n), W: 1})
+				prev = simple.Node(n)
+				n++
+			}
+			g.SetEdge(simple.Edge{F: prev, T: at, W: 1})
+		}
+
+		c, ok := CactusOf(g)
+		if !ok {
+			t.Errorf("trial %d: cactus not recognized", trial)
+			continue
+		}
+		checkCactus(t, "random", g, c)
+		if got, want := c.MatchingNumber(), bruteMatching(g); got != want {
+			t.Errorf("trial %d: unexpected matching number: got:%d want:%d", trial, got, want)
+		}
+	}
+}
+
+// checkCactus checks that the cycles and bridges of c partition the edges of g.
+func checkCactus(t *testing.T, name string, g graph.Undirected, c *Cactus) {
+	seen := make(map[[2]int]bool)
+	mark := func(u, v graph.Node) {
+		if !g.HasEdgeBetween(u, v) {
+			t.Errorf("%q: decomposition contains missing edge %d--%d", name, u.ID(), v.ID())
+		}
+		k := [2]int{u.ID(), v.ID()}
+		if k[0] > k[1] {
+			k[0], k[1] = k[1], k[0]
+		}
+		if seen[k] {
+			t.Errorf("%q: edge %d--%d appears twice in decomposition", name, u.ID(), v.ID())
+		}
+		seen[k] = true
+	}
+	for _, cyc := range c.Cycles {
+		for i, u := range cyc {
+			mark(u, cyc[(i+1)%len(cyc)])
+		}
+	}
+	for _, e := range c.Bridges {
+		mark(e.From(), e.To())
+	}
+	var m int
+	for _, u := range g.Nodes() {
+		m += len(g.From(u))
+	}
+	if len(seen) != m/2 {
+		t.Errorf("%q: decomposition does not cover graph: got:%d edges want:%d", name, len(seen), m/2)
+	}
+}
+
+func bruteMatching(g graph.Undirected) int {
+	matched := make(map[int]bool)
+	nodes := g.Nodes()
+	var match func(i int) int
+	match = func(i int) int {
+		for i < len(nodes) && matched[nodes[i].ID()] {
+			i++
+		}
+		if i == len(nodes) {
+			return 0
+		}
+		u := nodes[i]
+		matched[u.ID()] = true
+		best := match(i + 1)
+		for _, v := range g.From(u) {
+			if matched[v.ID()] {
+				continue
+			}
+			matched[v.ID()] = true
+			if m := 1 + match(i+1); m > best {
+				best = m
+			}
+			matched[v.ID()] = false
+		}
+		matched[u.ID()] = false
+		return best
+	}
+	return match(0)
+}
@@ -0,0 +1,187 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package recognize provides recognition of structured graph classes and
+// algorithms that exploit that structure.
+//
+// Problems that are hard or expensive on general graphs often have simple
+// linear-time solutions on restricted classes. The decompositions returned
+// by the recognition functions in this package give access to those
+// algorithms, so callers may dispatch to them when the structure is present.
+package recognize
+
+import "github.com/gonum/graph"
+
+// Composition is the kind of a node in a series-parallel decomposition.
+type Composition int
+
+const (
+	// Single is a single edge between
+	// the terminals.
+	Single Composition = iota
+
+	// Series is the series composition
+	// of two graphs, identifying a
+	// terminal of each.
+	Series
+
+	// Parallel is the parallel
+	// composition of two graphs,
+	// identifying both terminals.
+	Parallel
+)
+
+// Decomposition is a node of a series-parallel decomposition tree.
+type Decomposition struct {
+	Kind Composition
+
+	// S and T are the terminals of the
+	// subgraph described by the node.
+	S, T graph.Node
+
+	// Edge is the edge of a Single node.
+	Edge graph.Edge
+
+	// Children holds the two operands of
+	// a Series or Parallel node. The
+	// operands of a Series node are
+	// ordered from S to T.
+	Children []*Decomposition
+}
+
+// Reliability returns the probability that the terminals of d are connected
+// when each edge e is independently present with probability p(e).
+//
+// The time complexity of Reliability is O(|E|).
+func (d *Decomposition) Reliability(p func(graph.Edge) float64) float64 {
+	switch d.Kind {
+	case Single:
+		return p(d.Edge)
+	case Series:
+		return d.Children[0].Reliability(p) * d.Children[1].Reliability(p)
+	case Parallel:
+		return 1 - (1-d.Children[0].Reliability(p))*(1-d.Children[1].Reliability(p))
+	default:
+		panic("recognize: invalid composition")
+	}
+}
+
+// IsSeriesParallel returns whether g is a two-terminal series-parallel graph
+// with terminals s and t.
+func IsSeriesParallel(g graph.Undirected, s, t graph.Node) bool {
+	_, ok := SeriesParallel(g, s, t)
+	return ok
+}
+
+// SeriesParallel returns a series-parallel decomposition of g with terminals
+// s and t, and whether g is a two-terminal series-parallel graph with those
+// terminals.
+//
+// SeriesParallel repeatedly replaces parallel edges with a single edge and
+// non-terminal nodes of degree two and their edges with a single edge. The
+// time complexity of SeriesParallel is O(|V|+|E|).
+func SeriesParallel(g graph.Undirected, s, t graph.Node) (*Decomposition, bool) {
+	if s.ID() == t.ID() || !g.Has(s) || !g.Has(t) {
+		return nil, false
+	}
+
+	nodes := g.Nodes()
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	r := spReducer{
+		nodes: nodes,
+		s:     indexOf[s.ID()],
+		t:     indexOf[t.ID()],
+		edges: make([]map[int]*Decomposition, len(nodes)),
+	}
+	for i := range r.edges {
+		r.edges[i] = make(map[int]*Decomposition)
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			j := indexOf[v.ID()]
+			if i < j {
+				r.add(i, j, &Decomposition{Kind: Single, S: u, T: v, Edge: g.Edge(u, v)})
+			}
+		}
+	}
+
+	for len(r.work) != 0 {
+		v := r.work[len(r.work)-1]
+		r.work = r.work[:len(r.work)-1]
+		if len(r.edges[v]) != 2 || v == r.s || v == r.t {
+			continue
+		}
+		var (
+			ends [2]int
+			subs [2]*Decomposition
+			k    int
+		)
+		for u, d := range r.edges[v] {
+			ends[k], subs[k] = u, d
+			k++
+		}
+		if ends[0] > ends[1] {
+			ends[0], ends[1] = ends[1], ends[0]
+			subs[0], subs[1] = subs[1], subs[0]
+		}
+		for _, u := range ends {
+			delete(r.edges[u], v)
+		}
+		r.edges[v] = nil
+		r.removed++
+		r.add(ends[0], ends[1], &Decomposition{
+			Kind:     Series,
+			S:        nodes[ends[0]],
+			T:        nodes[ends[1]],
+			Children: subs[:],
+		})
+	}
+
+	d, ok := r.edges[r.s][r.t]
+	if !ok || len(r.edges[r.s]) != 1 || len(r.edges[r.t]) != 1 || r.removed != len(nodes)-2 {
+		return nil, false
+	}
+	if d.S.ID() != s.ID() {
+		d.S, d.T = d.T, d.S
+		if d.Kind == Series {
+			d.Children[0], d.Children[1] = d.Children[1], d.Children[0]
+		}
+	}
+	return d, true
+}
+
+// spReducer holds the state of a series-parallel reduction.
+type spReducer struct {
+	nodes []graph.Node
+	s, t  int
+
+	// edges holds the remaining edges,
+	// each labelled with the subgraph
+	// it replaces.
+	edges []map[int]*Decomposition
+
+	// work holds nodes that may have
+	// become reducible.
+	work    []int
+	removed int
+}
+
+// add adds an edge between u and v labelled with d, composing it in
+// parallel with any existing edge between u and v.
+func (r *spReducer) add(u, v int, d *Decomposition) {
+	if e, ok := r.edges[u][v]; ok {
+		d = &Decomposition{
+			Kind:     Parallel,
+			S:        r.nodes[u],
+			T:        r.nodes[v],
+			Children: []*Decomposition{e, d},
+		}
+	}
+	r.edges[u][v] = d
+	r.edges[v][u] = d
+	r.work = append(r.work, u, v)
+}
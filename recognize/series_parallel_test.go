@@ -0,0 +1,133 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recognize
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+var seriesParallelTests = []struct {
+	name  string
+	edges [][2]int
+	s, t  int
+
+	want bool
+}{
+	{name: "edge", edges: [][2]int{{0, 1}}, s: 0, t: 1, want: true},
+	{name: "path", edges: [][2]int{{0, 1}, {1, 2}, {2, 3}}, s: 0, t: 3, want: true},
+	{name: "path inner terminal", edges: [][2]int{{0, 1}, {1, 2}, {2, 3}}, s: 0, t: 2, want: false},
+	{name: "diamond", edges: [][2]int{{0, 1}, {1, 3}, {0, 2}, {2, 3}}, s: 0, t: 3, want: true},
+	{name: "bridge", edges: [][2]int{{0, 1}, {1, 3}, {0, 2}, {2, 3}, {1, 2}}, s: 0, t: 3, want: false},
+	{name: "bridge across", edges: [][2]int{{0, 1}, {1, 3}, {0, 2}, {2, 3}, {1, 2}}, s: 1, t: 2, want: true},
+	{
+		name:  "nested",
+		edges: [][2]int{{0, 1}, {1, 2}, {2, 5}, {1, 3}, {3, 2}, {0, 4}, {4, 5}, {0, 5}},
+		s:     0, t: 5,
+		want: true,
+	},
+	{name: "K4", edges: [][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}, s: 0, t: 1, want: false},
+	{name: "dangling", edges: [][2]int{{0, 1}, {1, 2}, {1, 3}}, s: 0, t: 2, want: false},
+	{name: "disconnected", edges: [][2]int{{0, 1}, {2, 3}}, s: 0, t: 1, want: false},
+}
+
+func TestSeriesParallel(t *testing.T) {
+	for _, test := range seriesParallelTests {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+		}
+		s, tn := simple.Node(test.s), simple.Node(test.t)
+
+		d, ok := SeriesParallel(g, s, tn)
+		if ok != test.want {
+			t.Errorf("%q: unexpected result: got:%t want:%t", test.name, ok, test.want)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if d.S.ID() != test.s || d.T.ID() != test.t {
+			t.Errorf("%q: unexpected terminals: got:%d,%d want:%d,%d", test.name, d.S.ID(), d.T.ID(), test.s, test.t)
+		}
+		if n := checkDecomposition(t, test.name, d); n != len(test.edges) {
+			t.Errorf("%q: unexpected number of edges in decomposition: got:%d want:%d", test.name, n, len(test.edges))
+		}
+
+		for _, q := range []float64{0, 0.3, 0.5, 0.9, 1} {
+			p := func(graph.Edge) float64 { return q }
+			got := d.Reliability(p)
+			want := bruteReliability(g, s, tn, test.edges, q)
+			if math.Abs(got-want) > 1e-12 {
+				t.Errorf("%q: unexpected reliability for p=%v: got:%v want:%v", test.name, q, got, want)
+			}
+		}
+	}
+}
+
+// checkDecomposition checks the terminals of d and its children
+// and returns the number of edges it describes.
+func checkDecomposition(t *testing.T, name string, d *Decomposition) int {
+	switch d.Kind {
+	case Single:
+		if !sameEnds(d.S, d.T, d.Edge.From(), d.Edge.To()) {
+			t.Errorf("%q: edge does not join terminals", name)
+		}
+		return 1
+	case Series:
+		a, b := d.Children[0], d.Children[1]
+		var mid graph.Node
+		switch {
+		case a.S.ID() == d.S.ID():
+			mid = a.T
+		case a.T.ID() == d.S.ID():
+			mid = a.S
+		default:
+			t.Errorf("%q: first series operand does not start at S", name)
+			return 0
+		}
+		if !sameEnds(mid, d.T, b.S, b.T) {
+			t.Errorf("%q: series operands do not join", name)
+		}
+	case Parallel:
+		for _, c := range d.Children {
+			if !sameEnds(d.S, d.T, c.S, c.T) {
+				t.Errorf("%q: parallel operand has different terminals", name)
+			}
+		}
+	}
+	return checkDecomposition(t, name, d.Children[0]) + checkDecomposition(t, name, d.Children[1])
+}
+
+func sameEnds(a, b, c, d graph.Node) bool {
+	return (a.ID() == c.ID() && b.ID() == d.ID()) || (a.ID() == d.ID() && b.ID() == c.ID())
+}
+
+func bruteReliability(g graph.Undirected, s, t graph.Node, edges [][2]int, p float64) float64 {
+	var r float64
+	for set := 0; set < 1<<uint(len(edges)); set++ {
+		h := simple.NewUndirectedGraph(0, math.Inf(1))
+		for _, n := range g.Nodes() {
+			h.AddNode(n)
+		}
+		prob := 1.0
+		for i, e := range edges {
+			if set&(1<<uint(i)) != 0 {
+				h.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+				prob *= p
+			} else {
+				prob *= 1 - p
+			}
+		}
+		if topo.PathExistsIn(h, s, t) {
+			r += prob
+		}
+	}
+	return r
+}
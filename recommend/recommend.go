@@ -0,0 +1,176 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package recommend provides neighborhood-based collaborative filtering over
+// bipartite graphs.
+//
+// The graphs hold users and the items they have rated, with each edge joining
+// a user to an item weighted by the rating. Implicit feedback, such as a
+// purchase, may be given a rating of one. The functions do not need to know
+// which nodes are users and which are items since the neighbors of a user are
+// items and the neighbors of an item are users, so a simple.BipartiteMatrix or
+// any undirected graph without edges between two users or two items may be
+// used.
+package recommend
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/gonum/graph"
+)
+
+// Recommendation is a scored node.
+type Recommendation struct {
+	Node  graph.Node
+	Score float64
+}
+
+// Similar returns the k nodes most similar to n, which are the nodes sharing
+// at least one neighbor with n, in decreasing order of similarity. Nodes with
+// a similarity that is not positive are not returned. If k is negative all
+// the similar nodes are returned.
+func Similar(g graph.Undirected, n graph.Node, k int, sim Similarity) []Recommendation {
+	x := ratings(g, n)
+	scores := make(map[int]float64)
+	nodes := make(map[int]graph.Node)
+	for _, item := range g.From(n) {
+		for _, v := range g.From(item) {
+			if v.ID() == n.ID() {
+				continue
+			}
+			if _, ok := nodes[v.ID()]; ok {
+				continue
+			}
+			nodes[v.ID()] = v
+			if s := sim(x, ratings(g, v)); s > 0 {
+				scores[v.ID()] = s
+			}
+		}
+	}
+	return top(scores, nodes, k)
+}
+
+// ItemBased returns the k items not rated by user with the highest scores in
+// decreasing order of score. The score of an item is the sum over the items
+// rated by user of their similarity to the item multiplied by the rating.
+// Only items sharing a user with an item rated by user are scored, and items
+// with a score that is not positive are not returned. If k is negative all
+// the scored items are returned.
+func ItemBased(g graph.Undirected, user graph.Node, k int, sim Similarity) []Recommendation {
+	rated := ratings(g, user)
+	scores := make(map[int]float64)
+	nodes := make(map[int]graph.Node)
+	for _, i := range g.From(user) {
+		for _, j := range Similar(g, i, -1, sim) {
+			id := j.Node.ID()
+			if _, ok := rated[id]; ok {
+				continue
+			}
+			nodes[id] = j.Node
+			scores[id] += j.Score * rated[i.ID()]
+		}
+	}
+	return positive(scores, nodes, k)
+}
+
+// UserBased returns the k items not rated by user with the highest scores in
+// decreasing order of score. The score of an item is the sum over the given
+// number of users most similar to user of their similarity multiplied by
+// their rating of the item. Items with a score that is not positive are not
+// returned. If neighbors is negative all similar users are used and if k is
+// negative all the scored items are returned.
+func UserBased(g graph.Undirected, user graph.Node, neighbors, k int, sim Similarity) []Recommendation {
+	rated := ratings(g, user)
+	scores := make(map[int]float64)
+	nodes := make(map[int]graph.Node)
+	for _, v := range Similar(g, user, neighbors, sim) {
+		for _, j := range g.From(v.Node) {
+			id := j.ID()
+			if _, ok := rated[id]; ok {
+				continue
+			}
+			nodes[id] = j
+			scores[id] += v.Score * g.EdgeBetween(v.Node, j).Weight()
+		}
+	}
+	return positive(scores, nodes, k)
+}
+
+// ratings returns the weights of the edges of n keyed by neighbor ID.
+func ratings(g graph.Undirected, n graph.Node) map[int]float64 {
+	to := g.From(n)
+	r := make(map[int]float64, len(to))
+	for _, v := range to {
+		r[v.ID()] = g.EdgeBetween(n, v).Weight()
+	}
+	return r
+}
+
+// positive returns the top k of the positive scores.
+func positive(scores map[int]float64, nodes map[int]graph.Node, k int) []Recommendation {
+	for id, s := range scores {
+		if s <= 0 {
+			delete(scores, id)
+		}
+	}
+	return top(scores, nodes, k)
+}
+
+// top returns the k highest scoring nodes in decreasing order of score
+// and then increasing order of ID. If k is negative all the nodes are
+// returned.
+func top(scores map[int]float64, nodes map[int]graph.Node, k int) []Recommendation {
+	if k < 0 || k > len(scores) {
+		k = len(scores)
+	}
+	if k == 0 {
+		return nil
+	}
+	h := make(recHeap, 0, k)
+	for id, s := range scores {
+		r := Recommendation{Node: nodes[id], Score: s}
+		if len(h) < k {
+			heap.Push(&h, r)
+			continue
+		}
+		if better(r, h[0]) {
+			h[0] = r
+			heap.Fix(&h, 0)
+		}
+	}
+	recs := []Recommendation(h)
+	sort.Sort(byScore(recs))
+	return recs
+}
+
+// better returns whether a ranks above b.
+func better(a, b Recommendation) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.Node.ID() < b.Node.ID()
+}
+
+// byScore sorts recommendations in decreasing rank.
+type byScore []Recommendation
+
+func (r byScore) Len() int           { return len(r) }
+func (r byScore) Less(i, j int) bool { return better(r[i], r[j]) }
+func (r byScore) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// recHeap is a min-heap of recommendations with the lowest
+// ranked recommendation at the root.
+type recHeap []Recommendation
+
+func (h recHeap) Len() int            { return len(h) }
+func (h recHeap) Less(i, j int) bool  { return better(h[j], h[i]) }
+func (h recHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *recHeap) Push(x interface{}) { *h = append(*h, x.(Recommendation)) }
+func (h *recHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
@@ -0,0 +1,115 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recommend
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// ratingsGraph returns users 0-2 and their ratings of items 3-6.
+func ratingsGraph() *simple.BipartiteMatrix {
+	g := simple.NewBipartiteMatrix(3, 4, 0, 0, 0)
+	for _, r := range []struct {
+		user, item int
+		rating     float64
+	}{
+		{user: 0, item: 3, rating: 5},
+		{user: 0, item: 4, rating: 3},
+		{user: 1, item: 3, rating: 4},
+		{user: 1, item: 4, rating: 3},
+		{user: 1, item: 5, rating: 5},
+		{user: 2, item: 4, rating: 1},
+		{user: 2, item: 6, rating: 4},
+	} {
+		g.SetEdge(simple.Edge{F: simple.Node(r.user), T: simple.Node(r.item), W: r.rating})
+	}
+	return g
+}
+
+type want struct {
+	id    int
+	score float64
+}
+
+func check(t *testing.T, name string, got []Recommendation, want []want) {
+	if len(got) != len(want) {
+		t.Errorf("%s: unexpected number of recommendations: got:%v want:%v", name, got, want)
+		return
+	}
+	for i, w := range want {
+		if got[i].Node.ID() != w.id || math.Abs(got[i].Score-w.score) > 1e-12 {
+			t.Errorf("%s: unexpected recommendation %d: got:%d:%v want:%d:%v",
+				name, i, got[i].Node.ID(), got[i].Score, w.id, w.score)
+		}
+	}
+}
+
+func TestUserBased(t *testing.T) {
+	g := ratingsGraph()
+	check(t, "all neighbors", UserBased(g, simple.Node(0), -1, -1, Jaccard),
+		[]want{{id: 5, score: 2.0 / 3 * 5}, {id: 6, score: 1.0 / 3 * 4}})
+	check(t, "one neighbor", UserBased(g, simple.Node(0), 1, -1, Jaccard),
+		[]want{{id: 5, score: 2.0 / 3 * 5}})
+	check(t, "top one", UserBased(g, simple.Node(0), -1, 1, Jaccard),
+		[]want{{id: 5, score: 2.0 / 3 * 5}})
+}
+
+func TestItemBased(t *testing.T) {
+	g := ratingsGraph()
+	check(t, "jaccard", ItemBased(g, simple.Node(0), -1, Jaccard),
+		[]want{{id: 5, score: 1.0/2*5 + 1.0/3*3}, {id: 6, score: 1.0 / 3 * 3}})
+
+	// Items already rated by the
+	// user are not recommended.
+	for _, r := range ItemBased(g, simple.Node(2), -1, Cosine) {
+		if g.HasEdgeBetween(simple.Node(2), r.Node) {
+			t.Errorf("unexpected recommendation of rated item %d", r.Node.ID())
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	g := ratingsGraph()
+	check(t, "similar users", Similar(g, simple.Node(0), -1, Jaccard),
+		[]want{{id: 1, score: 2.0 / 3}, {id: 2, score: 1.0 / 3}})
+	if got := Similar(g, simple.Node(5), 0, Cosine); len(got) != 0 {
+		t.Errorf("unexpected similar nodes for k=0: got:%v", got)
+	}
+}
+
+func TestTop(t *testing.T) {
+	scores := map[int]float64{0: 1, 1: 3, 2: 3, 3: 2, 4: 0.5}
+	nodes := make(map[int]graph.Node)
+	for id := range scores {
+		nodes[id] = simple.Node(id)
+	}
+	check(t, "top three", top(scores, nodes, 3),
+		[]want{{id: 1, score: 3}, {id: 2, score: 3}, {id: 3, score: 2}})
+	check(t, "top all", top(scores, nodes, -1),
+		[]want{{id: 1, score: 3}, {id: 2, score: 3}, {id: 3, score: 2}, {id: 0, score: 1}, {id: 4, score: 0.5}})
+}
+
+func TestSimilarity(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		sim  Similarity
+		x, y map[int]float64
+		want float64
+	}{
+		{name: "cosine parallel", sim: Cosine, x: map[int]float64{1: 1, 2: 2}, y: map[int]float64{1: 2, 2: 4}, want: 1},
+		{name: "cosine orthogonal", sim: Cosine, x: map[int]float64{1: 1}, y: map[int]float64{2: 1}, want: 0},
+		{name: "cosine empty", sim: Cosine, x: nil, y: map[int]float64{2: 1}, want: 0},
+		{name: "jaccard", sim: Jaccard, x: map[int]float64{1: 5, 2: 1}, y: map[int]float64{2: 3, 3: 1}, want: 1.0 / 3},
+		{name: "jaccard empty", sim: Jaccard, want: 0},
+	} {
+		if got := test.sim(test.x, test.y); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("%s: unexpected similarity: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
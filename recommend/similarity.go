@@ -0,0 +1,52 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package recommend
+
+import "math"
+
+// Similarity returns the similarity of two nodes given the ratings of their
+// neighbors keyed by neighbor ID.
+type Similarity func(x, y map[int]float64) float64
+
+// Cosine returns the cosine similarity of the rating vectors x and y. Cosine
+// returns zero if either vector is zero.
+func Cosine(x, y map[int]float64) float64 {
+	if len(y) < len(x) {
+		x, y = y, x
+	}
+	var dot, xx, yy float64
+	for id, xr := range x {
+		xx += xr * xr
+		if yr, ok := y[id]; ok {
+			dot += xr * yr
+		}
+	}
+	for _, yr := range y {
+		yy += yr * yr
+	}
+	if xx == 0 || yy == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(xx*yy)
+}
+
+// Jaccard returns the Jaccard similarity of the sets of neighbors of x and y,
+// ignoring the ratings. Jaccard returns zero if both sets are empty.
+func Jaccard(x, y map[int]float64) float64 {
+	if len(y) < len(x) {
+		x, y = y, x
+	}
+	var common int
+	for id := range x {
+		if _, ok := y[id]; ok {
+			common++
+		}
+	}
+	union := len(x) + len(y) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
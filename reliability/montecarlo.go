@@ -0,0 +1,103 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reliability
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/gonum/graph"
+)
+
+// Estimate is a Monte Carlo estimate of a two-terminal reliability.
+type Estimate struct {
+	// Connected is the number of samples
+	// in which the terminals were connected
+	// out of a total of Samples.
+	Connected, Samples int
+}
+
+// Reliability returns the estimated reliability.
+func (e Estimate) Reliability() float64 {
+	return float64(e.Connected) / float64(e.Samples)
+}
+
+// Interval returns the Wilson score interval for the reliability, where z
+// is the standard normal quantile of the required confidence level, for
+// example 1.96 for a 95% confidence interval.
+func (e Estimate) Interval(z float64) (lo, hi float64) {
+	n := float64(e.Samples)
+	p := e.Reliability()
+	z2 := z * z
+	mid := (p + z2/(2*n)) / (1 + z2/n)
+	half := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return math.Max(0, mid-half), math.Min(1, mid+half)
+}
+
+// MonteCarlo returns an estimate of the probability that s and t are connected
+// in g when each edge e fails independently with probability fail(e), using the
+// given number of samples. If src is nil, the global random source is used.
+//
+// The time complexity of MonteCarlo is O(samples.(|V|+|E|)).
+func MonteCarlo(g graph.Undirected, s, t graph.Node, fail Failure, samples int, src *rand.Rand) Estimate {
+	if samples < 1 {
+		panic("reliability: non-positive sample count")
+	}
+	est := Estimate{Samples: samples}
+	if !g.Has(s) || !g.Has(t) {
+		return est
+	}
+	if s.ID() == t.ID() {
+		est.Connected = samples
+		return est
+	}
+
+	rnd := rand.Float64
+	if src != nil {
+		rnd = src.Float64
+	}
+
+	n := newNetwork(g, fail)
+	si, ti := n.indexOf[s.ID()], n.indexOf[t.ID()]
+	adj := make([][]int, len(n.nodes))
+	for k, e := range n.ends {
+		adj[e[0]] = append(adj[e[0]], k)
+		adj[e[1]] = append(adj[e[1]], k)
+	}
+	up := make([]bool, len(n.ends))
+	seen := make([]bool, len(n.nodes))
+	var stack []int
+	for i := 0; i < samples; i++ {
+		for k, p := range n.pass {
+			up[k] = rnd() < p
+		}
+		for j := range seen {
+			seen[j] = false
+		}
+		seen[si] = true
+		stack = append(stack[:0], si)
+		for len(stack) != 0 && !seen[ti] {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, k := range adj[u] {
+				if !up[k] {
+					continue
+				}
+				v := n.ends[k][0]
+				if v == u {
+					v = n.ends[k][1]
+				}
+				if !seen[v] {
+					seen[v] = true
+					stack = append(stack, v)
+				}
+			}
+		}
+		if seen[ti] {
+			est.Connected++
+		}
+	}
+	return est
+}
@@ -0,0 +1,175 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package reliability provides two-terminal network reliability calculations.
+//
+// The two-terminal reliability of a graph is the probability that two nodes
+// remain connected when each edge fails independently with a given
+// probability.
+package reliability
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/recognize"
+)
+
+// Failure returns the probability that the edge e fails.
+type Failure func(e graph.Edge) float64
+
+// WeightFailure is a Failure that returns the weight of e.
+func WeightFailure(e graph.Edge) float64 { return e.Weight() }
+
+// AttributeFailure returns a Failure that reads the failure probability of
+// an edge from its graph.Attributer attribute with the given key. Edges
+// without the attribute fail with probability def. The returned Failure
+// will panic if the attribute value is not a valid float.
+func AttributeFailure(key string, def float64) Failure {
+	return func(e graph.Edge) float64 {
+		a, ok := e.(graph.Attributer)
+		if !ok {
+			return def
+		}
+		for _, attr := range a.Attributes() {
+			if attr.Key != key {
+				continue
+			}
+			p, err := strconv.ParseFloat(attr.Value, 64)
+			if err != nil {
+				panic("reliability: invalid failure probability: " + err.Error())
+			}
+			return p
+		}
+		return def
+	}
+}
+
+// Exact returns the probability that s and t are connected in g when each
+// edge e fails independently with probability fail(e).
+//
+// If g is a two-terminal series-parallel graph with terminals s and t, Exact
+// uses the series-parallel decomposition of g and takes O(|V|+|E|) time.
+// Otherwise Exact uses the factoring algorithm, conditioning on the state of
+// each edge in turn, which takes O(2^|E|) time in the worst case and is only
+// suitable for small graphs.
+func Exact(g graph.Undirected, s, t graph.Node, fail Failure) float64 {
+	if !g.Has(s) || !g.Has(t) {
+		return 0
+	}
+	if s.ID() == t.ID() {
+		return 1
+	}
+	if d, ok := recognize.SeriesParallel(g, s, t); ok {
+		return d.Reliability(func(e graph.Edge) float64 { return 1 - fail(e) })
+	}
+
+	n := newNetwork(g, fail)
+	f := factoring{network: n, s: n.indexOf[s.ID()], t: n.indexOf[t.ID()]}
+	comp := make([]int, len(n.nodes))
+	for i := range comp {
+		comp[i] = i
+	}
+	return f.reliability(0, comp)
+}
+
+// network is an indexed edge list of an undirected graph.
+type network struct {
+	nodes   []graph.Node
+	indexOf map[int]int
+
+	// ends and pass hold the end node
+	// indices and the probability of
+	// survival of each edge.
+	ends [][2]int
+	pass []float64
+}
+
+func newNetwork(g graph.Undirected, fail Failure) network {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	n := network{nodes: nodes, indexOf: make(map[int]int, len(nodes))}
+	for i, u := range nodes {
+		n.indexOf[u.ID()] = i
+	}
+	for i, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := n.indexOf[v.ID()]
+			if i < j {
+				n.ends = append(n.ends, [2]int{i, j})
+				n.pass = append(n.pass, 1-fail(g.EdgeBetween(u, v)))
+			}
+		}
+	}
+	return n
+}
+
+// factoring holds the state of a factoring reliability calculation.
+type factoring struct {
+	network
+	s, t int
+}
+
+// reliability returns the probability that s and t are connected given
+// that the edges before k have been contracted where they survive, as
+// described by the component labelling comp, and deleted otherwise.
+func (f factoring) reliability(k int, comp []int) float64 {
+	for ; k < len(f.ends); k++ {
+		if comp[f.ends[k][0]] != comp[f.ends[k][1]] {
+			break
+		}
+	}
+	if k == len(f.ends) || !f.connected(k, comp) {
+		return 0
+	}
+
+	a, b := comp[f.ends[k][0]], comp[f.ends[k][1]]
+	merged := make([]int, len(comp))
+	for i, c := range comp {
+		if c == b {
+			c = a
+		}
+		merged[i] = c
+	}
+	var r float64
+	if merged[f.s] == merged[f.t] {
+		r = f.pass[k]
+	} else {
+		r = f.pass[k] * f.reliability(k+1, merged)
+	}
+	return r + (1-f.pass[k])*f.reliability(k+1, comp)
+}
+
+// connected returns whether the components of s and t are joined by
+// the edges from k onwards.
+func (f factoring) connected(k int, comp []int) bool {
+	adj := make(map[int][]int)
+	for _, e := range f.ends[k:] {
+		a, b := comp[e[0]], comp[e[1]]
+		if a != b {
+			adj[a] = append(adj[a], b)
+			adj[b] = append(adj[b], a)
+		}
+	}
+	seen := map[int]bool{comp[f.s]: true}
+	stack := []int{comp[f.s]}
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if u == comp[f.t] {
+			return true
+		}
+		for _, v := range adj[u] {
+			if !seen[v] {
+				seen[v] = true
+				stack = append(stack, v)
+			}
+		}
+	}
+	return false
+}
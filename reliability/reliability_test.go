@@ -0,0 +1,154 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reliability
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+var reliabilityTests = []struct {
+	name  string
+	edges [][2]int
+	s, t  int
+}{
+	{name: "path", edges: [][2]int{{0, 1}, {1, 2}}, s: 0, t: 2},
+	{name: "diamond", edges: [][2]int{{0, 1}, {1, 3}, {0, 2}, {2, 3}}, s: 0, t: 3},
+	{name: "bridge", edges: [][2]int{{0, 1}, {1, 3}, {0, 2}, {2, 3}, {1, 2}}, s: 0, t: 3},
+	{name: "K4", edges: [][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}, s: 0, t: 3},
+	{
+		name:  "K4 with tail",
+		edges: [][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}, {3, 4}, {4, 5}, {5, 3}},
+		s:     0, t: 5,
+	},
+	{name: "disconnected", edges: [][2]int{{0, 1}, {2, 3}}, s: 0, t: 3},
+}
+
+func TestExact(t *testing.T) {
+	for _, test := range reliabilityTests {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for i, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 0.05 * float64(i+1)})
+		}
+		s, tn := simple.Node(test.s), simple.Node(test.t)
+
+		got := Exact(g, s, tn, WeightFailure)
+		want := bruteReliability(g, s, tn, WeightFailure)
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("%q: unexpected reliability: got:%v want:%v", test.name, got, want)
+		}
+	}
+}
+
+func TestExactBridge(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range reliabilityTests[2].edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	for _, p := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		got := Exact(g, simple.Node(0), simple.Node(3), func(graph.Edge) float64 { return 1 - p })
+		want := 2*math.Pow(p, 2) + 2*math.Pow(p, 3) - 5*math.Pow(p, 4) + 2*math.Pow(p, 5)
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected bridge reliability for p=%v: got:%v want:%v", p, got, want)
+		}
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	const (
+		samples = 20000
+		z       = 4
+	)
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range reliabilityTests {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for i, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 0.05 * float64(i+1)})
+		}
+		s, tn := simple.Node(test.s), simple.Node(test.t)
+
+		est := MonteCarlo(g, s, tn, WeightFailure, samples, rnd)
+		if est.Samples != samples {
+			t.Errorf("%q: unexpected sample count: got:%d want:%d", test.name, est.Samples, samples)
+		}
+		want := Exact(g, s, tn, WeightFailure)
+		lo, hi := est.Interval(z)
+		if want < lo || hi < want {
+			t.Errorf("%q: exact reliability %v outside interval [%v, %v]", test.name, want, lo, hi)
+		}
+		if r := est.Reliability(); r < lo || hi < r {
+			t.Errorf("%q: estimate %v outside interval [%v, %v]", test.name, r, lo, hi)
+		}
+	}
+}
+
+type attrEdge struct {
+	simple.Edge
+	attrs []graph.Attribute
+}
+
+func (e attrEdge) Attributes() []graph.Attribute { return e.attrs }
+
+func TestAttributeFailure(t *testing.T) {
+	fail := AttributeFailure("fail", 0.5)
+	e := simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 0.1}
+	for _, test := range []struct {
+		e    graph.Edge
+		want float64
+	}{
+		{e: e, want: 0.5},
+		{e: attrEdge{Edge: e}, want: 0.5},
+		{e: attrEdge{Edge: e, attrs: []graph.Attribute{{Key: "color", Value: "red"}, {Key: "fail", Value: "0.25"}}}, want: 0.25},
+	} {
+		if got := fail(test.e); got != test.want {
+			t.Errorf("unexpected failure probability: got:%v want:%v", got, test.want)
+		}
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		fail(attrEdge{Edge: e, attrs: []graph.Attribute{{Key: "fail", Value: "high"}}})
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for invalid failure attribute")
+	}
+}
+
+func bruteReliability(g graph.Undirected, s, t graph.Node, fail Failure) float64 {
+	var edges []graph.Edge
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if u.ID() < v.ID() {
+				edges = append(edges, g.EdgeBetween(u, v))
+			}
+		}
+	}
+	var r float64
+	for set := 0; set < 1<<uint(len(edges)); set++ {
+		h := simple.NewUndirectedGraph(0, math.Inf(1))
+		for _, n := range g.Nodes() {
+			h.AddNode(n)
+		}
+		prob := 1.0
+		for i, e := range edges {
+			if set&(1<<uint(i)) != 0 {
+				h.SetEdge(simple.Edge{F: e.From(), T: e.To(), W: 1})
+				prob *= 1 - fail(e)
+			} else {
+				prob *= fail(e)
+			}
+		}
+		if topo.PathExistsIn(h, s, t) {
+			r += prob
+		}
+	}
+	return r
+}
@@ -0,0 +1,158 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package html implements rendering of graphs as self-contained interactive
+// HTML documents.
+//
+// The rendered document embeds the graph as JSON and a small force-directed
+// viewer that requires no external resources, so documents can be written
+// by tests and tools as visual debugging artifacts and opened directly in a
+// web browser. Nodes can be dragged, the view can be panned and zoomed, and
+// clicking a node or edge shows its attributes.
+package html
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Graph is the JSON representation of a graph embedded in a rendered
+// document.
+type Graph struct {
+	Directed bool   `json:"directed"`
+	Nodes    []Node `json:"nodes"`
+	Links    []Link `json:"links"`
+}
+
+// Node is the JSON representation of a graph node.
+type Node struct {
+	ID         int               `json:"id"`
+	Label      string            `json:"label"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Link is the JSON representation of a graph edge. Weight is nil if the
+// edge weight is not finite.
+type Link struct {
+	Source     int               `json:"source"`
+	Target     int               `json:"target"`
+	Weight     *float64          `json:"weight,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// NewGraph returns the JSON representation of g. Node labels are taken from
+// the DOTID method of nodes implementing dot.Node or the Key method of
+// nodes with a Key() string method, such as simple.KeyedNode, and node and edge
+// attributes from nodes and edges implementing dot.Attributer or
+// graph.Attributer. Nodes and edges are ordered by ID.
+func NewGraph(g graph.Graph) Graph {
+	_, isDirected := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+
+	j := Graph{
+		Directed: isDirected,
+		Nodes:    make([]Node, 0, len(nodes)),
+		Links:    []Link{},
+	}
+	for _, n := range nodes {
+		label := ""
+		switch n := n.(type) {
+		case dot.Node:
+			label = n.DOTID()
+		case keyer:
+			label = n.Key()
+		}
+		if label == "" {
+			label = strconv.Itoa(n.ID())
+		}
+		j.Nodes = append(j.Nodes, Node{ID: n.ID(), Label: label, Attributes: attributes(n)})
+	}
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected && v.ID() < u.ID() {
+				continue
+			}
+			e := g.Edge(u, v)
+			l := Link{Source: u.ID(), Target: v.ID(), Attributes: attributes(e)}
+			if w := e.Weight(); !math.IsInf(w, 0) && !math.IsNaN(w) {
+				l.Weight = &w
+			}
+			j.Links = append(j.Links, l)
+		}
+	}
+	return j
+}
+
+// keyer is implemented by nodes with an external string key,
+// such as simple.KeyedNode.
+type keyer interface {
+	Key() string
+}
+
+func attributes(v interface{}) map[string]string {
+	var m map[string]string
+	switch a := v.(type) {
+	case dot.Attributer:
+		for _, attr := range a.DOTAttributes() {
+			if m == nil {
+				m = make(map[string]string)
+			}
+			m[attr.Key] = attr.Value
+		}
+	case graph.Attributer:
+		for _, attr := range a.Attributes() {
+			if m == nil {
+				m = make(map[string]string)
+			}
+			m[attr.Key] = attr.Value
+		}
+	}
+	return m
+}
+
+// Marshal returns a self-contained HTML document rendering g with the given
+// title.
+func Marshal(g graph.Graph, title string) ([]byte, error) {
+	data, err := json.Marshal(NewGraph(g))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = page.Execute(&buf, struct {
+		Title string
+		Graph template.JS
+	}{
+		Title: title,
+		// The output of json.Marshal escapes <, > and &,
+		// so it is safe to embed in a script element.
+		Graph: template.JS(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteFile writes a self-contained HTML document rendering g with the given
+// title to the named file. WriteFile is intended for writing debugging
+// artifacts, for example when a test fails.
+func WriteFile(filename string, g graph.Graph, title string) error {
+	b, err := Marshal(g, title)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0644)
+}
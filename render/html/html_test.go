@@ -0,0 +1,122 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package html
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/simple"
+)
+
+type namedNode struct {
+	id   int
+	name string
+}
+
+func (n namedNode) ID() int       { return n.id }
+func (n namedNode) DOTID() string { return n.name }
+func (n namedNode) DOTAttributes() []dot.Attribute {
+	return []dot.Attribute{{Key: "shape", Value: "box"}}
+}
+
+func float(f float64) *float64 { return &f }
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: namedNode{id: 0, name: "</script>"}, T: simple.Node(1), W: 2})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 0.5})
+	g.AddNode(simple.Node(3))
+
+	b, err := Marshal(g, "a <b> & c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(b, []byte("<title>a &lt;b&gt; &amp; c</title>")) {
+		t.Errorf("title not escaped in output")
+	}
+	if n := bytes.Count(b, []byte("</script>")); n != 1 {
+		t.Errorf("unexpected number of script end tags: got:%d want:1", n)
+	}
+
+	const start = "var graph = "
+	i := bytes.Index(b, []byte(start))
+	if i < 0 {
+		t.Fatalf("missing graph data in output")
+	}
+	data := b[i+len(start):]
+	data = data[:bytes.IndexByte(data, '\n')]
+	data = bytes.TrimSuffix(data, []byte(";"))
+	var got Graph
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatalf("failed to parse embedded graph: %v", err)
+	}
+	want := Graph{
+		Nodes: []Node{
+			{ID: 0, Label: "</script>", Attributes: map[string]string{"shape": "box"}},
+			{ID: 1, Label: "1"},
+			{ID: 2, Label: "2"},
+			{ID: 3, Label: "3"},
+		},
+		Links: []Link{
+			{Source: 0, Target: 1, Weight: float(2)},
+			{Source: 1, Target: 2, Weight: float(0.5)},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected embedded graph:\ngot: %+v\nwant:%+v", got, want)
+	}
+}
+
+func TestNewGraphDirected(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0), W: math.Inf(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+
+	got := NewGraph(g)
+	want := Graph{
+		Directed: true,
+		Nodes:    []Node{{ID: 0, Label: "0"}, {ID: 1, Label: "1"}},
+		Links: []Link{
+			{Source: 0, Target: 1, Weight: float(1)},
+			{Source: 1, Target: 0},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected graph:\ngot: %+v\nwant:%+v", got, want)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "html")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	name := filepath.Join(dir, "graph.html")
+	err = WriteFile(name, g, "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	want, _ := Marshal(g, "test")
+	if !bytes.Equal(b, want) {
+		t.Errorf("unexpected file contents")
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package html
+
+import "html/template"
+
+// page is the document template. The viewer lays out the graph with a
+// simple force simulation and renders it as SVG.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+html, body { margin: 0; height: 100%; font: 13px sans-serif; }
+svg { width: 100%; height: 100%; display: block; cursor: move; }
+.link { stroke: #999; stroke-width: 1.5; }
+.link.selected { stroke: #d62728; stroke-width: 3; }
+.node circle { fill: #1f77b4; stroke: #fff; stroke-width: 1.5; cursor: pointer; }
+.node.selected circle { fill: #d62728; }
+.node text { pointer-events: none; fill: #333; }
+#info { position: absolute; top: 8px; left: 8px; max-width: 40%; background: rgba(255,255,255,0.9);
+	border: 1px solid #ccc; padding: 6px 10px; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<div id="info">{{.Title}}</div>
+<svg id="view"><defs><marker id="arrow" viewBox="0 -5 10 10" refX="17" markerWidth="6" markerHeight="6" orient="auto">
+<path d="M0,-5L10,0L0,5" fill="#999"></path></marker></defs><g id="scene"></g></svg>
+<script>
+(function() {
+"use strict";
+var graph = {{.Graph}};
+var ns = "http://www.w3.org/2000/svg";
+var svg = document.getElementById("view");
+var scene = document.getElementById("scene");
+var info = document.getElementById("info");
+var title = info.textContent;
+
+var index = {};
+graph.nodes.forEach(function(n, i) {
+	index[n.id] = n;
+	var a = 2 * Math.PI * i / graph.nodes.length;
+	var r = 10 * Math.sqrt(graph.nodes.length);
+	n.x = r * Math.cos(a);
+	n.y = r * Math.sin(a);
+	n.vx = 0;
+	n.vy = 0;
+});
+
+function el(name, attrs, parent) {
+	var e = document.createElementNS(ns, name);
+	for (var k in attrs) {
+		e.setAttribute(k, attrs[k]);
+	}
+	parent.appendChild(e);
+	return e;
+}
+
+var selected = null;
+function select(e, desc) {
+	if (selected) {
+		selected.classList.remove("selected");
+	}
+	selected = e;
+	if (!e) {
+		info.textContent = title;
+		return;
+	}
+	e.classList.add("selected");
+	info.textContent = desc;
+}
+function describe(kind, obj, keys) {
+	var s = kind;
+	keys.forEach(function(k) {
+		if (obj[k] !== undefined) {
+			s += "\n" + k + ": " + obj[k];
+		}
+	});
+	for (var k in obj.attributes || {}) {
+		s += "\n" + k + " = " + obj.attributes[k];
+	}
+	return s;
+}
+
+graph.links.forEach(function(l) {
+	l.s = index[l.source];
+	l.t = index[l.target];
+	l.el = el("line", {"class": "link"}, scene);
+	if (graph.directed) {
+		l.el.setAttribute("marker-end", "url(#arrow)");
+	}
+	l.el.addEventListener("click", function(ev) {
+		ev.stopPropagation();
+		select(l.el, describe("edge", l, ["source", "target", "weight"]));
+	});
+});
+graph.nodes.forEach(function(n) {
+	n.el = el("g", {"class": "node"}, scene);
+	el("circle", {r: 6}, n.el);
+	el("text", {x: 8, y: 4}, n.el).textContent = n.label;
+	n.el.addEventListener("mousedown", function(ev) {
+		ev.stopPropagation();
+		drag = n;
+		n.fixed = true;
+		select(n.el, describe("node", n, ["id", "label"]));
+		alpha = Math.max(alpha, 0.3);
+	});
+});
+
+// Pan and zoom.
+var view = {x: 0, y: 0, k: 1};
+var drag = null, pan = null;
+function toScene(ev) {
+	var r = svg.getBoundingClientRect();
+	return {x: (ev.clientX - r.left - view.x) / view.k, y: (ev.clientY - r.top - view.y) / view.k};
+}
+function transform() {
+	scene.setAttribute("transform", "translate(" + view.x + "," + view.y + ") scale(" + view.k + ")");
+}
+svg.addEventListener("mousedown", function(ev) {
+	pan = {x: ev.clientX - view.x, y: ev.clientY - view.y};
+	select(null);
+});
+window.addEventListener("mousemove", function(ev) {
+	if (drag) {
+		var p = toScene(ev);
+		drag.x = p.x;
+		drag.y = p.y;
+	} else if (pan) {
+		view.x = ev.clientX - pan.x;
+		view.y = ev.clientY - pan.y;
+		transform();
+	}
+});
+window.addEventListener("mouseup", function() {
+	if (drag) {
+		drag.fixed = false;
+	}
+	drag = null;
+	pan = null;
+});
+svg.addEventListener("wheel", function(ev) {
+	ev.preventDefault();
+	var r = svg.getBoundingClientRect();
+	var mx = ev.clientX - r.left, my = ev.clientY - r.top;
+	var f = ev.deltaY < 0 ? 1.1 : 1 / 1.1;
+	view.x = mx - (mx - view.x) * f;
+	view.y = my - (my - view.y) * f;
+	view.k *= f;
+	transform();
+});
+function center() {
+	var r = svg.getBoundingClientRect();
+	view.x = r.width / 2;
+	view.y = r.height / 2;
+	transform();
+}
+window.addEventListener("resize", center);
+center();
+
+// Force simulation with spring-electrical forces.
+var alpha = 1;
+function tick() {
+	var nodes = graph.nodes, i, j;
+	for (i = 0; i < nodes.length; i++) {
+		for (j = i + 1; j < nodes.length; j++) {
+			var a = nodes[i], b = nodes[j];
+			var dx = a.x - b.x, dy = a.y - b.y;
+			var d2 = dx * dx + dy * dy || 0.01;
+			var f = 900 / d2 * alpha;
+			a.vx += dx * f; a.vy += dy * f;
+			b.vx -= dx * f; b.vy -= dy * f;
+		}
+	}
+	graph.links.forEach(function(l) {
+		var dx = l.t.x - l.s.x, dy = l.t.y - l.s.y;
+		var d = Math.sqrt(dx * dx + dy * dy) || 0.01;
+		var f = (d - 40) / d * 0.1 * alpha;
+		l.s.vx += dx * f; l.s.vy += dy * f;
+		l.t.vx -= dx * f; l.t.vy -= dy * f;
+	});
+	nodes.forEach(function(n) {
+		n.vx -= n.x * 0.01 * alpha;
+		n.vy -= n.y * 0.01 * alpha;
+		if (!n.fixed) {
+			n.x += n.vx;
+			n.y += n.vy;
+		}
+		n.vx *= 0.5;
+		n.vy *= 0.5;
+	});
+	alpha *= 0.99;
+}
+function draw() {
+	graph.links.forEach(function(l) {
+		l.el.setAttribute("x1", l.s.x);
+		l.el.setAttribute("y1", l.s.y);
+		l.el.setAttribute("x2", l.t.x);
+		l.el.setAttribute("y2", l.t.y);
+	});
+	graph.nodes.forEach(function(n) {
+		n.el.setAttribute("transform", "translate(" + n.x + "," + n.y + ")");
+	});
+}
+function frame() {
+	if (alpha > 0.005) {
+		tick();
+		draw();
+	}
+	window.requestAnimationFrame(frame);
+}
+draw();
+window.requestAnimationFrame(frame);
+})();
+</script>
+</body>
+</html>
+`))
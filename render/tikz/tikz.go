@@ -0,0 +1,179 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tikz implements rendering of positioned graphs as LaTeX TikZ
+// pictures.
+//
+// TikZ is described in the PGF/TikZ manual: http://www.ctan.org/pkg/pgf
+package tikz
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/layout"
+)
+
+// Picture describes how a graph is rendered as a tikzpicture environment.
+// The zero value renders nodes as circles labeled by their IDs, or their
+// DOT IDs if they implement dot.Node.
+type Picture struct {
+	// Scale is the length in centimeters
+	// of a unit in layout coordinates. If
+	// Scale is zero, a scale of 1 is used.
+	Scale float64
+
+	// NodeStyle and EdgeStyle are the TikZ
+	// options used for all nodes and edges.
+	// They are defined as the vertex and arc
+	// styles of the picture. If NodeStyle is
+	// empty, "draw, circle" is used. Edges of
+	// directed graphs are drawn with arrows
+	// in addition to EdgeStyle.
+	NodeStyle string
+	EdgeStyle string
+
+	// Node, if not nil, returns the label and
+	// additional TikZ options for a node. The
+	// label is LaTeX source and is not escaped.
+	Node func(graph.Node) (label, style string)
+
+	// Edge, if not nil, returns the label and
+	// additional TikZ options for an edge. The
+	// label is LaTeX source and is not escaped.
+	// Empty labels are not drawn.
+	Edge func(graph.Edge) (label, style string)
+
+	// Routes, if not nil, holds polyline
+	// routes for edges keyed by the IDs of
+	// their from and to nodes, such as those
+	// returned by layout.Sugiyama. The first
+	// and last points of a route are the
+	// positions of the edge's nodes.
+	Routes map[[2]int][]layout.Point
+}
+
+// Marshal returns a tikzpicture environment drawing the graph g with the
+// nodes placed at the given positions keyed by node ID. Nodes are named
+// n<ID> in the picture.
+func (p Picture) Marshal(g graph.Graph, pos map[int]layout.Point) ([]byte, error) {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	_, isDirected := g.(graph.Directed)
+
+	scale := p.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	nodeStyle := p.NodeStyle
+	if nodeStyle == "" {
+		nodeStyle = "draw, circle"
+	}
+	edgeStyle := p.EdgeStyle
+	if isDirected {
+		edgeStyle = join("->", edgeStyle)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\\begin{tikzpicture}[x=%scm, y=%scm, vertex/.style={%s}, arc/.style={%s}]\n",
+		formatFloat(scale), formatFloat(scale), nodeStyle, edgeStyle)
+
+	for _, n := range nodes {
+		q, ok := pos[n.ID()]
+		if !ok {
+			return nil, fmt.Errorf("tikz: missing position for node %d", n.ID())
+		}
+		var label, style string
+		if p.Node != nil {
+			label, style = p.Node(n)
+		} else {
+			label = defaultLabel(n)
+		}
+		fmt.Fprintf(&buf, "\t\\node[%s] (%s) at %s {%s};\n", join("vertex", style), name(n), point(q), label)
+	}
+
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected && v.ID() < u.ID() {
+				continue
+			}
+			e := g.Edge(u, v)
+			var label, style string
+			if p.Edge != nil {
+				label, style = p.Edge(e)
+			}
+			fmt.Fprintf(&buf, "\t\\draw[%s] (%s)", join("arc", style), name(u))
+			route := p.Routes[[2]int{u.ID(), v.ID()}]
+			if len(route) > 2 {
+				for _, q := range route[1 : len(route)-1] {
+					fmt.Fprintf(&buf, " -- %s", point(q))
+				}
+			}
+			buf.WriteString(" --")
+			if label != "" {
+				fmt.Fprintf(&buf, " node[midway, auto] {%s}", label)
+			}
+			fmt.Fprintf(&buf, " (%s);\n", name(v))
+		}
+	}
+	buf.WriteString("\\end{tikzpicture}\n")
+	return buf.Bytes(), nil
+}
+
+// join returns the TikZ options a and b joined by a comma.
+func join(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + ", " + b
+}
+
+func name(n graph.Node) string {
+	return "n" + strconv.Itoa(n.ID())
+}
+
+func point(p layout.Point) string {
+	return "(" + formatFloat(p.X) + ", " + formatFloat(p.Y) + ")"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 6, 64)
+}
+
+func defaultLabel(n graph.Node) string {
+	if dn, ok := n.(dot.Node); ok {
+		return Escape(dn.DOTID())
+	}
+	return strconv.Itoa(n.ID())
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\textasciicircum{}`,
+	`~`, `\textasciitilde{}`,
+)
+
+// Escape returns s with the LaTeX special characters escaped so that it
+// can be used as a node or edge label.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,99 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tikz
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/build"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/simple"
+)
+
+type namedNode struct {
+	id   int
+	name string
+}
+
+func (n namedNode) ID() int       { return n.id }
+func (n namedNode) DOTID() string { return n.name }
+
+func TestMarshalUndirected(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: namedNode{id: 0, name: "a_1"}, T: simple.Node(1), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	pos := map[int]layout.Point{0: {X: 0, Y: 0}, 1: {X: 1.5, Y: 0}, 2: {X: 1.5, Y: -1}}
+
+	got, err := Picture{}.Marshal(g, pos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `\begin{tikzpicture}[x=1cm, y=1cm, vertex/.style={draw, circle}, arc/.style={}]
+	\node[vertex] (n0) at (0, 0) {a\_1};
+	\node[vertex] (n1) at (1.5, 0) {1};
+	\node[vertex] (n2) at (1.5, -1) {2};
+	\draw[arc] (n0) -- (n1);
+	\draw[arc] (n1) -- (n2);
+\end{tikzpicture}
+`
+	if string(got) != want {
+		t.Errorf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarshalDirectedStyled(t *testing.T) {
+	g := build.Directed("0->1:2, 1->0:3")
+	pos := map[int]layout.Point{0: {X: 0, Y: 0}, 1: {X: 0, Y: -2}}
+
+	p := Picture{
+		Scale:     2,
+		NodeStyle: "fill=gray",
+		EdgeStyle: "thick",
+		Node: func(n graph.Node) (label, style string) {
+			if n.ID() == 0 {
+				return "$v_0$", "red"
+			}
+			return "$v_1$", ""
+		},
+		Edge: func(e graph.Edge) (label, style string) {
+			return strconv.FormatFloat(e.Weight(), 'g', -1, 64), "bend left"
+		},
+		Routes: map[[2]int][]layout.Point{{1, 0}: {{X: 0, Y: -2}, {X: 1, Y: -1}, {X: 0, Y: 0}}},
+	}
+	got, err := p.Marshal(g, pos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `\begin{tikzpicture}[x=2cm, y=2cm, vertex/.style={fill=gray}, arc/.style={->, thick}]
+	\node[vertex, red] (n0) at (0, 0) {$v_0$};
+	\node[vertex] (n1) at (0, -2) {$v_1$};
+	\draw[arc, bend left] (n0) -- node[midway, auto] {2} (n1);
+	\draw[arc, bend left] (n1) -- (1, -1) -- node[midway, auto] {3} (n0);
+\end{tikzpicture}
+`
+	if string(got) != want {
+		t.Errorf("unexpected output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarshalMissingPosition(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	g.AddNode(simple.Node(0))
+	_, err := Picture{}.Marshal(g, nil)
+	if err == nil {
+		t.Error("expected error for missing position")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := Escape(`50% {a}_b & $c$ #1 ^~\`)
+	want := `50\% \{a\}\_b \& \$c\$ \#1 \textasciicircum{}\textasciitilde{}\textbackslash{}`
+	if got != want {
+		t.Errorf("unexpected escape: got:%q want:%q", got, want)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rewrite provides double-pushout graph rewriting.
+//
+// A Rule is a pair of graphs, a left hand side pattern and a right hand side
+// replacement. Nodes and edges present in both, identified by node ID, are
+// preserved by a rewrite. Those only in the left hand side are deleted and
+// those only in the right hand side are created.
+package rewrite
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/iso"
+	"github.com/gonum/graph/simple"
+)
+
+// Graph is a graph that can be rewritten.
+type Graph interface {
+	graph.Graph
+	graph.Builder
+	graph.NodeRemover
+	graph.EdgeRemover
+}
+
+// Match is an occurrence of a rule's left hand side in a graph, mapping
+// each left hand side node ID to a node of the graph.
+type Match map[int]graph.Node
+
+// Rule is a double-pushout rewrite rule.
+type Rule struct {
+	// LHS is the pattern to be replaced
+	// and RHS is its replacement. If LHS
+	// is a Directed graph, RHS and the
+	// graphs rewritten must also be.
+	LHS, RHS graph.Graph
+
+	// Compat restricts the graph nodes
+	// and edges matched by LHS.
+	Compat iso.Compatibility
+
+	// NewNode returns the node to add to
+	// the graph for the RHS node n, given
+	// an unused node ID. If NewNode is nil,
+	// a simple.Node is added.
+	NewNode func(n graph.Node, id int) graph.Node
+
+	// NewEdge returns the edge to add to
+	// the graph for the RHS edge e between
+	// the given graph nodes. If NewEdge is
+	// nil, a simple.Edge with the weight
+	// of e is added.
+	NewEdge func(e graph.Edge, from, to graph.Node) graph.Edge
+}
+
+var (
+	// ErrNoMatch is returned when a match
+	// is not an occurrence of the rule's
+	// left hand side in the graph.
+	ErrNoMatch = errors.New("rewrite: match not found in graph")
+
+	// ErrDangling is returned when applying
+	// a rule would delete a node with edges
+	// not deleted by the rule.
+	ErrDangling = errors.New("rewrite: deleted node has dangling edges")
+)
+
+// Matches calls fn with each match of r in g at which r may be applied, until
+// fn returns false. A match may be applied if it satisfies the dangling
+// condition: every graph node deleted by r has no edges other than those
+// matched by r.LHS.
+func (r Rule) Matches(g graph.Graph, fn func(Match) bool) {
+	iso.SubgraphMatches(r.LHS, g, r.Compat, func(m map[int]graph.Node) bool {
+		if r.dangling(g, Match(m)) {
+			return true
+		}
+		return fn(Match(m))
+	})
+}
+
+// Apply applies r to g at the match m and returns the graph nodes
+// corresponding to the nodes of r.RHS, keyed by RHS node ID. Apply checks
+// that m is an applicable match before modifying g, so if an error is
+// returned g is unchanged.
+func (r Rule) Apply(g Graph, m Match) (map[int]graph.Node, error) {
+	if !r.valid(g, m) {
+		return nil, ErrNoMatch
+	}
+	if r.dangling(g, m) {
+		return nil, ErrDangling
+	}
+
+	// Delete LHS edges and nodes not preserved by RHS.
+	lhs := sortedNodes(r.LHS)
+	for _, u := range lhs {
+		for _, v := range r.LHS.From(u) {
+			if !r.isDirected() && u.ID() > v.ID() {
+				continue
+			}
+			if !r.hasEdge(r.RHS, u, v) {
+				g.RemoveEdge(g.Edge(m[u.ID()], m[v.ID()]))
+			}
+		}
+	}
+	for _, u := range lhs {
+		if !r.RHS.Has(u) {
+			g.RemoveNode(m[u.ID()])
+		}
+	}
+
+	// Create RHS nodes and edges not in LHS.
+	image := make(map[int]graph.Node)
+	rhs := sortedNodes(r.RHS)
+	for _, u := range rhs {
+		if r.LHS.Has(u) {
+			image[u.ID()] = m[u.ID()]
+			continue
+		}
+		id := g.NewNodeID()
+		var n graph.Node = simple.Node(id)
+		if r.NewNode != nil {
+			n = r.NewNode(u, id)
+		}
+		g.AddNode(n)
+		image[u.ID()] = n
+	}
+	for _, u := range rhs {
+		for _, v := range r.RHS.From(u) {
+			if !r.isDirected() && u.ID() > v.ID() {
+				continue
+			}
+			if r.LHS.Has(u) && r.LHS.Has(v) && r.hasEdge(r.LHS, u, v) {
+				continue
+			}
+			e := r.RHS.Edge(u, v)
+			from, to := image[u.ID()], image[v.ID()]
+			var ge graph.Edge = simple.Edge{F: from, T: to, W: e.Weight()}
+			if r.NewEdge != nil {
+				ge = r.NewEdge(e, from, to)
+			}
+			g.SetEdge(ge)
+		}
+	}
+	return image, nil
+}
+
+// Rewrite repeatedly applies the first applicable rule in rules at its first
+// match in g until no rule applies or max rewrites have been made, and returns
+// the number of rewrites made. Each rewrite is made as by Rule.Apply. If max
+// is negative, no limit is placed on the number of rewrites, so the rules
+// must not allow an infinite sequence of rewrites.
+func Rewrite(g Graph, rules []Rule, max int) int {
+	var n int
+	for max < 0 || n < max {
+		applied := false
+		for _, r := range rules {
+			var match Match
+			r.Matches(g, func(m Match) bool {
+				match = m
+				return false
+			})
+			if match == nil {
+				continue
+			}
+			if _, err := r.Apply(g, match); err != nil {
+				// Matches only returns applicable matches.
+				panic(err)
+			}
+			applied = true
+			break
+		}
+		if !applied {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// valid returns whether m is an injective mapping of the nodes of r.LHS
+// to nodes of g that maps every edge of r.LHS to a compatible edge of g.
+func (r Rule) valid(g graph.Graph, m Match) bool {
+	lhs := r.LHS.Nodes()
+	if len(m) != len(lhs) {
+		return false
+	}
+	used := make(map[int]bool, len(m))
+	for _, u := range lhs {
+		n, ok := m[u.ID()]
+		if !ok || n == nil || !g.Has(n) || used[n.ID()] {
+			return false
+		}
+		used[n.ID()] = true
+		if r.Compat.Node != nil && !r.Compat.Node(u, n) {
+			return false
+		}
+	}
+	for _, u := range lhs {
+		for _, v := range r.LHS.From(u) {
+			x, y := m[u.ID()], m[v.ID()]
+			if !r.hasEdge(g, x, y) {
+				return false
+			}
+			if r.Compat.Edge != nil && !r.Compat.Edge(r.LHS.Edge(u, v), g.Edge(x, y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// dangling returns whether applying r at m would leave edges of g
+// attached to deleted nodes.
+func (r Rule) dangling(g graph.Graph, m Match) bool {
+	inverse := make(map[int]graph.Node, len(m))
+	for id, n := range m {
+		inverse[n.ID()] = simple.Node(id)
+	}
+	for id, n := range m {
+		u := simple.Node(id)
+		if r.RHS.Has(u) {
+			continue
+		}
+		for _, w := range g.From(n) {
+			v, ok := inverse[w.ID()]
+			if !ok || !r.hasEdge(r.LHS, u, v) {
+				return true
+			}
+		}
+		if d, ok := g.(graph.Directed); ok && r.isDirected() {
+			for _, w := range d.To(n) {
+				v, ok := inverse[w.ID()]
+				if !ok || !r.hasEdge(r.LHS, v, u) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (r Rule) isDirected() bool {
+	_, ok := r.LHS.(graph.Directed)
+	return ok
+}
+
+// hasEdge returns whether g has an edge from u to v, respecting
+// direction if r is directed.
+func (r Rule) hasEdge(g graph.Graph, u, v graph.Node) bool {
+	if r.isDirected() {
+		return g.(graph.Directed).HasEdgeFromTo(u, v)
+	}
+	return g.HasEdgeBetween(u, v)
+}
+
+func sortedNodes(g graph.Graph) []graph.Node {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	return nodes
+}
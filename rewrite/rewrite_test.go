@@ -0,0 +1,140 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rewrite
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/iso"
+	"github.com/gonum/graph/simple"
+)
+
+func undirected(edges ...[2]int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	return g
+}
+
+func edgeCount(g graph.Graph) int {
+	var n int
+	for _, u := range g.Nodes() {
+		n += len(g.From(u))
+	}
+	return n
+}
+
+// smooth removes a node of degree two, joining its neighbors.
+var smooth = Rule{
+	LHS: undirected([2]int{0, 1}, [2]int{1, 2}),
+	RHS: undirected([2]int{0, 2}),
+}
+
+func TestRewriteSmooth(t *testing.T) {
+	g := undirected([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 4})
+	n := Rewrite(g, []Rule{smooth}, -1)
+	if n != 3 {
+		t.Errorf("unexpected number of rewrites: got:%d want:3", n)
+	}
+	if len(g.Nodes()) != 2 || !g.HasEdgeBetween(simple.Node(0), simple.Node(4)) {
+		t.Errorf("unexpected result: nodes:%v edges:%d", g.Nodes(), edgeCount(g)/2)
+	}
+
+	g = undirected([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 4})
+	if n := Rewrite(g, []Rule{smooth}, 1); n != 1 {
+		t.Errorf("unexpected number of limited rewrites: got:%d want:1", n)
+	}
+	if len(g.Nodes()) != 4 {
+		t.Errorf("unexpected number of nodes after one rewrite: got:%d want:4", len(g.Nodes()))
+	}
+}
+
+func TestApplyDangling(t *testing.T) {
+	g := undirected([2]int{0, 1}, [2]int{0, 2}, [2]int{0, 3})
+	smooth.Matches(g, func(m Match) bool {
+		t.Errorf("unexpected applicable match: %v", m)
+		return true
+	})
+
+	m := Match{0: simple.Node(1), 1: simple.Node(0), 2: simple.Node(2)}
+	if _, err := smooth.Apply(g, m); err != ErrDangling {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrDangling)
+	}
+	if len(g.Nodes()) != 4 || edgeCount(g) != 6 {
+		t.Errorf("graph modified by failed rewrite")
+	}
+
+	m = Match{0: simple.Node(1), 1: simple.Node(2), 2: simple.Node(3)}
+	if _, err := smooth.Apply(g, m); err != ErrNoMatch {
+		t.Errorf("unexpected error: got:%v want:%v", err, ErrNoMatch)
+	}
+	m = Match{0: simple.Node(1), 1: simple.Node(0), 2: simple.Node(1)}
+	if _, err := smooth.Apply(g, m); err != ErrNoMatch {
+		t.Errorf("unexpected error for non-injective match: got:%v want:%v", err, ErrNoMatch)
+	}
+}
+
+func TestApplySubdivide(t *testing.T) {
+	sub := Rule{
+		LHS: undirected([2]int{0, 1}),
+		RHS: undirected([2]int{0, 10}, [2]int{10, 1}),
+		Compat: iso.Compatibility{
+			Edge: func(_, e graph.Edge) bool { return e.Weight() == 1 },
+		},
+		NewEdge: func(e graph.Edge, from, to graph.Node) graph.Edge {
+			return simple.Edge{F: from, T: to, W: 2}
+		},
+	}
+	g := undirected([2]int{0, 1}, [2]int{1, 2})
+	n := Rewrite(g, []Rule{sub}, -1)
+	if n != 2 {
+		t.Errorf("unexpected number of rewrites: got:%d want:2", n)
+	}
+	if len(g.Nodes()) != 5 || edgeCount(g)/2 != 4 {
+		t.Errorf("unexpected result size: nodes:%d edges:%d", len(g.Nodes()), edgeCount(g)/2)
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.From(u) {
+			if w := g.Edge(u, v).Weight(); w != 2 {
+				t.Errorf("unexpected weight for edge %d--%d: got:%v want:2", u.ID(), v.ID(), w)
+			}
+		}
+	}
+}
+
+func TestApplyDirected(t *testing.T) {
+	path := simple.NewDirectedGraph(0, math.Inf(1))
+	path.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	path.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	short := simple.NewDirectedGraph(0, math.Inf(1))
+	short.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2), W: 1})
+	fold := Rule{LHS: path, RHS: short}
+
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(5), T: simple.Node(6), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(6), T: simple.Node(7), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(8), T: simple.Node(6), W: 1})
+
+	var n int
+	fold.Matches(g, func(Match) bool { n++; return true })
+	if n != 0 {
+		t.Errorf("unexpected applicable matches with dangling in-edge: got:%d want:0", n)
+	}
+
+	g.RemoveNode(simple.Node(8))
+	image, err := fold.Apply(g, Match{0: simple.Node(5), 1: simple.Node(6), 2: simple.Node(7)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image[0].ID() != 5 || image[2].ID() != 7 {
+		t.Errorf("unexpected image: %v", image)
+	}
+	if g.Has(simple.Node(6)) || !g.HasEdgeFromTo(simple.Node(5), simple.Node(7)) || g.HasEdgeFromTo(simple.Node(7), simple.Node(5)) {
+		t.Errorf("unexpected result graph")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package robustness provides network robustness simulation under random
+// failure and targeted attack.
+package robustness
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/network"
+	"github.com/gonum/graph/simple"
+)
+
+// Attack is a node removal strategy.
+type Attack interface {
+	// Next returns the next node to remove from g,
+	// which holds the nodes that have not yet been
+	// removed. If Next returns nil, the simulation
+	// is stopped.
+	Next(g graph.Undirected) graph.Node
+}
+
+// Result holds the outcome of a robustness simulation.
+type Result struct {
+	// Nodes is the number of nodes
+	// in the original graph.
+	Nodes int
+
+	// Removed holds the removed
+	// nodes in order of removal.
+	Removed []graph.Node
+
+	// Giant holds the size of the largest
+	// connected component after each removal.
+	// Giant[0] is the size before any nodes
+	// are removed and Giant[i] is the size
+	// after the removal of Removed[i-1].
+	Giant []int
+}
+
+// Fractions returns the size of the largest connected component after each
+// removal as a fraction of the number of nodes in the original graph.
+func (r Result) Fractions() []float64 {
+	f := make([]float64, len(r.Giant))
+	for i, s := range r.Giant {
+		f[i] = float64(s) / float64(r.Nodes)
+	}
+	return f
+}
+
+// Threshold returns the fraction of nodes removed when the size of the
+// largest connected component first falls below frac of the number of nodes
+// in the original graph. If the largest connected component never falls below
+// frac, Threshold returns NaN.
+func (r Result) Threshold(frac float64) float64 {
+	for i, s := range r.Giant {
+		if float64(s) < frac*float64(r.Nodes) {
+			return float64(i) / float64(r.Nodes)
+		}
+	}
+	return math.NaN()
+}
+
+// R returns the robustness measure of Schneider et al. doi:10.1073/pnas.1009440108,
+//  R = 1/N \sum_{Q=1}^{N} s(Q),
+// where s(Q) is the fraction of nodes in the largest connected component after
+// the removal of Q nodes. R ranges from zero to one half when all nodes are
+// removed.
+func (r Result) R() float64 {
+	if r.Nodes == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range r.Giant[1:] {
+		sum += float64(s) / float64(r.Nodes)
+	}
+	return sum / float64(r.Nodes)
+}
+
+// Simulate removes nodes from a copy of g in the order chosen by attack until
+// all nodes are removed or attack returns nil, and returns the sizes of the
+// largest connected component during the removal. Edge direction is ignored,
+// so the connected components of directed graphs are weakly connected
+// components.
+func Simulate(g graph.Graph, attack Attack) Result {
+	h := simple.NewUndirectedGraph(0, math.Inf(1))
+	graph.Copy(h, g)
+
+	nodes := g.Nodes()
+	r := Result{Nodes: len(nodes)}
+	for len(h.Nodes()) != 0 {
+		n := attack.Next(h)
+		if n == nil {
+			break
+		}
+		if !h.Has(n) {
+			panic("robustness: attack returned absent node")
+		}
+		h.RemoveNode(n)
+		r.Removed = append(r.Removed, n)
+	}
+
+	// Calculate the giant component sizes by adding
+	// the removed nodes back in reverse order.
+	removed := make(map[int]bool, len(r.Removed))
+	for _, n := range r.Removed {
+		removed[n.ID()] = true
+	}
+	uf := newUnionFind()
+	var giant int
+	add := func(u graph.Node) {
+		uf.add(u.ID())
+		for _, v := range g.From(u) {
+			if !removed[v.ID()] && v.ID() != u.ID() {
+				uf.union(u.ID(), v.ID())
+			}
+		}
+		if d, ok := g.(graph.Directed); ok {
+			for _, v := range d.To(u) {
+				if !removed[v.ID()] && v.ID() != u.ID() {
+					uf.union(u.ID(), v.ID())
+				}
+			}
+		}
+		if s := uf.size[uf.find(u.ID())]; s > giant {
+			giant = s
+		}
+	}
+	for _, n := range nodes {
+		if !removed[n.ID()] {
+			add(n)
+		}
+	}
+	r.Giant = make([]int, len(r.Removed)+1)
+	r.Giant[len(r.Removed)] = giant
+	for i := len(r.Removed) - 1; i >= 0; i-- {
+		n := r.Removed[i]
+		delete(removed, n.ID())
+		add(n)
+		r.Giant[i] = giant
+	}
+	return r
+}
+
+// unionFind is a disjoint set forest with union by size.
+type unionFind struct {
+	parent map[int]int
+	size   map[int]int
+}
+
+func newUnionFind() unionFind {
+	return unionFind{parent: make(map[int]int), size: make(map[int]int)}
+}
+
+func (u unionFind) add(x int) {
+	u.parent[x] = x
+	u.size[x] = 1
+}
+
+func (u unionFind) find(x int) int {
+	for u.parent[x] != x {
+		u.parent[x] = u.parent[u.parent[x]]
+		x = u.parent[x]
+	}
+	return x
+}
+
+func (u unionFind) union(x, y int) {
+	x, y = u.find(x), u.find(y)
+	if x == y {
+		return
+	}
+	if u.size[x] < u.size[y] {
+		x, y = y, x
+	}
+	u.parent[y] = x
+	u.size[x] += u.size[y]
+}
+
+// Random returns an Attack that removes nodes uniformly at random, simulating
+// random failure or site percolation. If src is not nil it is used as the
+// random source, otherwise rand.Intn is used.
+func Random(src *rand.Rand) Attack {
+	if src == nil {
+		return random{rnd: rand.Intn}
+	}
+	return random{rnd: src.Intn}
+}
+
+type random struct {
+	rnd func(int) int
+}
+
+func (a random) Next(g graph.Undirected) graph.Node {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return nodes[a.rnd(len(nodes))]
+}
+
+// Degree returns an Attack that removes nodes in order of decreasing degree,
+// with ties broken by node ID. If adaptive is true, degrees are recalculated
+// after each removal, otherwise the degrees in the original graph are used.
+// A non-adaptive Attack holds its removal order and should only be used for a
+// single simulation.
+func Degree(adaptive bool) Attack {
+	return &ranked{adaptive: adaptive, score: func(g graph.Undirected) map[int]float64 {
+		deg := make(map[int]float64)
+		for _, n := range g.Nodes() {
+			deg[n.ID()] = float64(len(g.From(n)))
+		}
+		return deg
+	}}
+}
+
+// Betweenness returns an Attack that removes nodes in order of decreasing
+// betweenness centrality, with ties broken by node ID. If adaptive is true,
+// betweenness is recalculated after each removal, otherwise the betweenness
+// in the original graph is used. A non-adaptive Attack holds its removal
+// order and should only be used for a single simulation.
+func Betweenness(adaptive bool) Attack {
+	return &ranked{adaptive: adaptive, score: func(g graph.Undirected) map[int]float64 {
+		return network.Betweenness(g)
+	}}
+}
+
+// ranked is an Attack that removes nodes in order of decreasing score.
+type ranked struct {
+	adaptive bool
+	score    func(graph.Undirected) map[int]float64
+
+	// order holds the removal order
+	// for non-adaptive attacks.
+	order []graph.Node
+}
+
+func (a *ranked) Next(g graph.Undirected) graph.Node {
+	if !a.adaptive && a.order != nil {
+		for len(a.order) != 0 {
+			n := a.order[0]
+			a.order = a.order[1:]
+			if g.Has(n) {
+				return n
+			}
+		}
+		return nil
+	}
+
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	score := a.score(g)
+	sort.Sort(byScore{nodes: nodes, score: score})
+	if a.adaptive {
+		return nodes[0]
+	}
+	a.order = nodes[1:]
+	return nodes[0]
+}
+
+// byScore sorts nodes by decreasing score and then by increasing ID.
+type byScore struct {
+	nodes []graph.Node
+	score map[int]float64
+}
+
+func (s byScore) Len() int { return len(s.nodes) }
+func (s byScore) Less(i, j int) bool {
+	si, sj := s.score[s.nodes[i].ID()], s.score[s.nodes[j].ID()]
+	if si == sj {
+		return s.nodes[i].ID() < s.nodes[j].ID()
+	}
+	return si > sj
+}
+func (s byScore) Swap(i, j int) { s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i] }
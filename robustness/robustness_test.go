@@ -0,0 +1,130 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robustness
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+// star returns a star with n leaves and the hub node 0.
+func star(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 1; i <= n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(i), W: 1})
+	}
+	return g
+}
+
+func TestSimulateStar(t *testing.T) {
+	for _, attack := range []Attack{Degree(false), Degree(true), Betweenness(false), Betweenness(true)} {
+		r := Simulate(star(5), attack)
+		if len(r.Removed) != 6 {
+			t.Fatalf("unexpected number of removed nodes: got:%d want:6", len(r.Removed))
+		}
+		if r.Removed[0].ID() != 0 {
+			t.Errorf("expected hub to be removed first, got node %d", r.Removed[0].ID())
+		}
+		want := []int{6, 1, 1, 1, 1, 1, 0}
+		for i, s := range r.Giant {
+			if s != want[i] {
+				t.Errorf("unexpected giant component sizes: got:%v want:%v", r.Giant, want)
+				break
+			}
+		}
+		if th := r.Threshold(0.5); th != 1.0/6 {
+			t.Errorf("unexpected threshold: got:%v want:%v", th, 1.0/6)
+		}
+		if got, want := r.R(), 5.0/36; math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected R: got:%v want:%v", got, want)
+		}
+	}
+}
+
+func TestSimulateGiant(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := gen.Gnp(g, 60, 0.05, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("failed to generate graph: %v", err)
+	}
+	for _, test := range []struct {
+		name   string
+		attack Attack
+	}{
+		{name: "random", attack: Random(rand.New(rand.NewSource(1)))},
+		{name: "degree", attack: Degree(false)},
+		{name: "adaptive degree", attack: Degree(true)},
+	} {
+		r := Simulate(g, test.attack)
+		if len(r.Giant) != len(r.Removed)+1 {
+			t.Fatalf("%s: length mismatch between giant sizes and removals", test.name)
+		}
+
+		// Check against direct calculation.
+		h := simple.NewUndirectedGraph(0, math.Inf(1))
+		graph.Copy(h, g)
+		for i := 0; i <= len(r.Removed); i++ {
+			if i > 0 {
+				h.RemoveNode(r.Removed[i-1])
+			}
+			var want int
+			for _, c := range topo.ConnectedComponents(h) {
+				if len(c) > want {
+					want = len(c)
+				}
+			}
+			if r.Giant[i] != want {
+				t.Errorf("%s: unexpected giant component size after %d removals: got:%d want:%d", test.name, i, r.Giant[i], want)
+			}
+		}
+	}
+
+	random := Simulate(g, Random(rand.New(rand.NewSource(1)))).R()
+	targeted := Simulate(g, Degree(true)).R()
+	if targeted >= random {
+		t.Errorf("expected targeted attack to be more damaging: random R=%v targeted R=%v", random, targeted)
+	}
+}
+
+type stopAfter int
+
+func (s *stopAfter) Next(g graph.Undirected) graph.Node {
+	if *s == 0 {
+		return nil
+	}
+	*s--
+	nodes := g.Nodes()
+	min := nodes[0]
+	for _, n := range nodes {
+		if n.ID() < min.ID() {
+			min = n
+		}
+	}
+	return min
+}
+
+func TestSimulateStop(t *testing.T) {
+	s := stopAfter(2)
+	r := Simulate(star(3), &s)
+	if len(r.Removed) != 2 {
+		t.Errorf("unexpected number of removals: got:%d want:2", len(r.Removed))
+	}
+	want := []int{4, 1, 1}
+	for i, s := range r.Giant {
+		if s != want[i] {
+			t.Errorf("unexpected giant component sizes: got:%v want:%v", r.Giant, want)
+			break
+		}
+	}
+	if th := r.Threshold(0.1); !math.IsNaN(th) {
+		t.Errorf("unexpected threshold: got:%v want:NaN", th)
+	}
+}
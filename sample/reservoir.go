@@ -0,0 +1,145 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sample
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// ReservoirNodes returns k nodes of g sampled without replacement in a single
+// pass over the nodes, holding only k nodes at any time. If weight is nil the
+// nodes are sampled uniformly, otherwise each node is sampled with probability
+// proportional to its weight, and nodes with zero weight are not sampled. If g
+// has fewer than k nodes with positive weight, all of them are returned. The
+// nodes are returned in the order of a sequential weighted sample. If src is
+// not nil it is used as the random source, otherwise rand.Float64 is used.
+// ReservoirNodes will panic if weight returns a negative value.
+func ReservoirNodes(g graph.Graph, k int, weight func(graph.Node) float64, src *rand.Rand) []graph.Node {
+	r := newReservoir(k, src)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		w := 1.0
+		if weight != nil {
+			w = weight(n)
+		}
+		r.offer(n, w)
+	}
+	sampled := r.items()
+	s := make([]graph.Node, len(sampled))
+	for i, n := range sampled {
+		s[i] = n.(graph.Node)
+	}
+	return s
+}
+
+// ReservoirEdges returns k edges of g sampled without replacement in a single
+// pass over the edges, holding only k edges at any time. If weighted is false
+// the edges are sampled uniformly, otherwise each edge is sampled with
+// probability proportional to its weight, and edges with zero weight are not
+// sampled. Each edge of an undirected graph is considered once. If g has fewer
+// than k edges eligible for sampling, all of them are returned. The edges are
+// returned in the order of a sequential weighted sample. If src is not nil it
+// is used as the random source, otherwise rand.Float64 is used. ReservoirEdges
+// will panic if weighted is true and g has an edge with negative weight.
+func ReservoirEdges(g graph.Graph, k int, weighted bool, src *rand.Rand) []graph.Edge {
+	r := newReservoir(k, src)
+	_, isDirected := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected && v.ID() < u.ID() {
+				continue
+			}
+			e := g.Edge(u, v)
+			w := 1.0
+			if weighted {
+				w = e.Weight()
+			}
+			r.offer(e, w)
+		}
+	}
+	sampled := r.items()
+	s := make([]graph.Edge, len(sampled))
+	for i, e := range sampled {
+		s[i] = e.(graph.Edge)
+	}
+	return s
+}
+
+// reservoir is a weighted reservoir sample using the A-Res algorithm of
+// Efraimidis and Spirakis, doi:10.1016/j.ipl.2005.11.003. Each item is given
+// the key log(u)/w for u uniform in (0, 1) and the items with the k largest
+// keys are retained.
+type reservoir struct {
+	k    int
+	rnd  func() float64
+	heap reservoirHeap
+}
+
+func newReservoir(k int, src *rand.Rand) *reservoir {
+	return &reservoir{k: k, rnd: float64For(src)}
+}
+
+// offer considers x with weight w for inclusion in the sample.
+func (r *reservoir) offer(x interface{}, w float64) {
+	if w < 0 {
+		panic("sample: negative weight")
+	}
+	if w == 0 || r.k <= 0 {
+		return
+	}
+	u := r.rnd()
+	for u == 0 {
+		u = r.rnd()
+	}
+	key := math.Log(u) / w
+	if len(r.heap) < r.k {
+		heap.Push(&r.heap, keyed{item: x, key: key})
+		return
+	}
+	if key > r.heap[0].key {
+		r.heap[0] = keyed{item: x, key: key}
+		heap.Fix(&r.heap, 0)
+	}
+}
+
+// items returns the sampled items in decreasing order of key.
+func (r *reservoir) items() []interface{} {
+	s := make([]interface{}, len(r.heap))
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = heap.Pop(&r.heap).(keyed).item
+	}
+	return s
+}
+
+// keyed is a sampled item and its key.
+type keyed struct {
+	item interface{}
+	key  float64
+}
+
+// reservoirHeap is a min-heap of keyed items.
+type reservoirHeap []keyed
+
+func (h reservoirHeap) Len() int            { return len(h) }
+func (h reservoirHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h reservoirHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *reservoirHeap) Push(x interface{}) { *h = append(*h, x.(keyed)) }
+func (h *reservoirHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
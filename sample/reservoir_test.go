@@ -0,0 +1,139 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sample
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestReservoirNodes(t *testing.T) {
+	const n = 10
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	src := rand.New(rand.NewSource(1))
+
+	got := ReservoirNodes(g, 2*n, nil, src)
+	if len(got) != n {
+		t.Errorf("unexpected oversize sample length: got:%d want:%d", len(got), n)
+	}
+
+	const trials = 20000
+	counts := make([]int, n)
+	for i := 0; i < trials; i++ {
+		s := ReservoirNodes(g, 3, nil, src)
+		if len(s) != 3 {
+			t.Fatalf("unexpected sample length: got:%d want:3", len(s))
+		}
+		seen := make(map[int]bool)
+		for _, u := range s {
+			if seen[u.ID()] {
+				t.Fatalf("node %d sampled twice", u.ID())
+			}
+			seen[u.ID()] = true
+			counts[u.ID()]++
+		}
+	}
+	want := float64(trials) * 3 / n
+	for id, c := range counts {
+		if math.Abs(float64(c)-want) > 0.1*want {
+			t.Errorf("unexpected uniform count for node %d: got:%d want:%v", id, c, want)
+		}
+	}
+
+	// Node 0 has nine times the weight of
+	// node 1 and other nodes have none.
+	weight := func(u graph.Node) float64 {
+		switch u.ID() {
+		case 0:
+			return 9
+		case 1:
+			return 1
+		}
+		return 0
+	}
+	var zero int
+	for i := 0; i < trials; i++ {
+		s := ReservoirNodes(g, 1, weight, src)
+		if len(s) != 1 {
+			t.Fatalf("unexpected sample length: got:%d want:1", len(s))
+		}
+		if s[0].ID() > 1 {
+			t.Fatalf("sampled node %d with zero weight", s[0].ID())
+		}
+		if s[0].ID() == 0 {
+			zero++
+		}
+	}
+	if p := float64(zero) / trials; math.Abs(p-0.9) > 0.01 {
+		t.Errorf("unexpected weighted sample proportion: got:%v want:0.9", p)
+	}
+	if s := ReservoirNodes(g, n, weight, src); len(s) != 2 {
+		t.Errorf("unexpected number of positive weight nodes: got:%d want:2", len(s))
+	}
+}
+
+func TestReservoirEdges(t *testing.T) {
+	for _, test := range samplingTests {
+		g := gnp(t, test.n, test.p, test.directed, 1)
+		var m int
+		for _, u := range g.Nodes() {
+			m += len(g.From(u))
+		}
+		if !test.directed {
+			m /= 2
+		}
+		s := ReservoirEdges(g, test.size, false, rand.New(rand.NewSource(1)))
+		want := test.size
+		if m < want {
+			want = m
+		}
+		if len(s) != want {
+			t.Errorf("%s: unexpected sample length: got:%d want:%d", test.name, len(s), want)
+		}
+		seen := make(map[[2]int]bool)
+		for _, e := range s {
+			if g.Edge(e.From(), e.To()) == nil {
+				t.Errorf("%s: sampled edge %d-%d not in graph", test.name, e.From().ID(), e.To().ID())
+			}
+			k := [2]int{e.From().ID(), e.To().ID()}
+			if !test.directed && k[0] > k[1] {
+				k[0], k[1] = k[1], k[0]
+			}
+			if seen[k] {
+				t.Errorf("%s: edge %d-%d sampled twice", test.name, k[0], k[1])
+			}
+			seen[k] = true
+		}
+	}
+}
+
+func TestReservoirEdgesWeighted(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 9})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	src := rand.New(rand.NewSource(1))
+
+	const trials = 20000
+	var heavy int
+	for i := 0; i < trials; i++ {
+		s := ReservoirEdges(g, 1, true, src)
+		if len(s) != 1 {
+			t.Fatalf("unexpected sample length: got:%d want:1", len(s))
+		}
+		if s[0].From().ID() == 0 {
+			heavy++
+		}
+	}
+	if p := float64(heavy) / trials; math.Abs(p-0.9) > 0.01 {
+		t.Errorf("unexpected weighted sample proportion: got:%v want:0.9", p)
+	}
+}
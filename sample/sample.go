@@ -0,0 +1,272 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sample provides graph sampling functions for extracting
+// representative subgraphs from large graphs.
+package sample
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// GraphBuilder is a graph that can have nodes and edges added.
+type GraphBuilder interface {
+	Has(graph.Node) bool
+	graph.Builder
+}
+
+// Nodes places in dst the subgraph of g induced by n nodes chosen uniformly
+// at random. If g has fewer than n nodes, all nodes of g are used. If src is
+// not nil it is used as the random source, otherwise rand.Intn is used.
+func Nodes(dst GraphBuilder, g graph.Graph, n int, src *rand.Rand) {
+	rnd := intnFor(src)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	if n > len(nodes) {
+		n = len(nodes)
+	}
+	// Partial Fisher-Yates shuffle.
+	for i := 0; i < n; i++ {
+		j := i + rnd(len(nodes)-i)
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	induce(dst, g, nodes[:n])
+}
+
+// Edges places in dst m edges of g chosen uniformly at random and their
+// terminal nodes. If g has fewer than m edges, all edges of g are used. If src
+// is not nil it is used as the random source, otherwise rand.Intn is used.
+func Edges(dst GraphBuilder, g graph.Graph, m int, src *rand.Rand) {
+	rnd := intnFor(src)
+	_, isDirected := g.(graph.Directed)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	var edges []graph.Edge
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected && v.ID() < u.ID() {
+				continue
+			}
+			edges = append(edges, g.Edge(u, v))
+		}
+	}
+	if m > len(edges) {
+		m = len(edges)
+	}
+	for i := 0; i < m; i++ {
+		j := i + rnd(len(edges)-i)
+		edges[i], edges[j] = edges[j], edges[i]
+		e := edges[i]
+		for _, n := range []graph.Node{e.From(), e.To()} {
+			if !dst.Has(n) {
+				dst.AddNode(n)
+			}
+		}
+		dst.SetEdge(e)
+	}
+}
+
+// RandomWalk places in dst the subgraph of g induced by the first n distinct
+// nodes visited by a random walk starting from a node chosen uniformly at
+// random. At each step the walk follows an edge from the current node chosen
+// uniformly at random, or with probability escape, or if the current node has
+// no out-going edges, jumps to a node chosen uniformly at random. If the walk
+// has not visited a new node within len(g.Nodes()) steps, it jumps to a random
+// unvisited node, so sampling terminates for disconnected graphs even when
+// escape is zero. If g has fewer than n nodes, all nodes of g are used. If src
+// is not nil it is used as the random source, otherwise rand.Float64 and
+// rand.Intn are used.
+//
+// The nodes of the sample approximately preserve the degree distribution and
+// local clustering of g for small values of escape.
+func RandomWalk(dst GraphBuilder, g graph.Graph, n int, escape float64, src *rand.Rand) error {
+	if escape < 0 || escape > 1 {
+		return fmt.Errorf("sample: bad escape: escape=%v", escape)
+	}
+	rnd, rndN := float64For(src), intnFor(src)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	if n > len(nodes) {
+		n = len(nodes)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	s := newSampler(nodes, rndN)
+	u := s.jump()
+	s.visit(u)
+	for stale := 0; len(s.sample) < n; {
+		if stale >= len(nodes) {
+			u = s.jump()
+			s.visit(u)
+			stale = 0
+			continue
+		}
+		to := g.From(u)
+		if len(to) == 0 || rnd() < escape {
+			u = nodes[rndN(len(nodes))]
+		} else {
+			sort.Sort(ordered.ByID(to))
+			u = to[rndN(len(to))]
+		}
+		if s.visit(u) {
+			stale = 0
+		} else {
+			stale++
+		}
+	}
+	induce(dst, g, s.sample)
+	return nil
+}
+
+// ForestFire places in dst the subgraph of g induced by n nodes burned by the
+// forest fire sampling procedure described by Leskovec and Faloutsos in
+// doi:10.1145/1150402.1150479. A fire is started at a node chosen uniformly at
+// random and each burning node burns a geometrically distributed number of its
+// unburned out-neighbours with mean forward/(1-forward) and, if g is directed,
+// of its unburned in-neighbours with mean backward/(1-backward). When a fire
+// dies out before n nodes are burned, a new fire is started at a randomly
+// chosen unburned node. If g has fewer than n nodes, all nodes of g are used.
+// If src is not nil it is used as the random source, otherwise rand.Float64
+// and rand.Intn are used.
+//
+// Forest fire sampling with forward around 0.7 approximately preserves the
+// degree distributions, densification and shrinking diameter of g.
+func ForestFire(dst GraphBuilder, g graph.Graph, n int, forward, backward float64, src *rand.Rand) error {
+	if forward < 0 || forward >= 1 {
+		return fmt.Errorf("sample: bad forward burning probability: forward=%v", forward)
+	}
+	if backward < 0 || backward >= 1 {
+		return fmt.Errorf("sample: bad backward burning probability: backward=%v", backward)
+	}
+	rnd, rndN := float64For(src), intnFor(src)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	if n > len(nodes) {
+		n = len(nodes)
+	}
+
+	d, isDirected := g.(graph.Directed)
+	s := newSampler(nodes, rndN)
+	var queue []graph.Node
+	// burn burns up to a geometrically distributed
+	// number of the unburned nodes in ns with the
+	// burning probability p.
+	burn := func(ns []graph.Node, p float64) {
+		var unburned []graph.Node
+		for _, v := range ns {
+			if !s.visited[v.ID()] {
+				unburned = append(unburned, v)
+			}
+		}
+		sort.Sort(ordered.ByID(unburned))
+		k := 0
+		for rnd() < p {
+			k++
+		}
+		for i := 0; i < k && i < len(unburned) && len(s.sample) < n; i++ {
+			j := i + rndN(len(unburned)-i)
+			unburned[i], unburned[j] = unburned[j], unburned[i]
+			s.visit(unburned[i])
+			queue = append(queue, unburned[i])
+		}
+	}
+	for len(s.sample) < n {
+		if len(queue) == 0 {
+			u := s.jump()
+			s.visit(u)
+			queue = append(queue, u)
+			continue
+		}
+		u := queue[0]
+		queue = queue[1:]
+		burn(g.From(u), forward)
+		if isDirected {
+			burn(d.To(u), backward)
+		}
+	}
+	induce(dst, g, s.sample)
+	return nil
+}
+
+// sampler holds the nodes visited during sampling.
+type sampler struct {
+	nodes   []graph.Node
+	perm    []int
+	next    int
+	visited map[int]bool
+	sample  []graph.Node
+}
+
+func newSampler(nodes []graph.Node, rndN func(int) int) *sampler {
+	perm := make([]int, len(nodes))
+	for i := range perm {
+		j := rndN(i + 1)
+		perm[i] = perm[j]
+		perm[j] = i
+	}
+	return &sampler{nodes: nodes, perm: perm, visited: make(map[int]bool)}
+}
+
+// visit adds n to the sample, returning whether n was not already visited.
+func (s *sampler) visit(n graph.Node) bool {
+	if s.visited[n.ID()] {
+		return false
+	}
+	s.visited[n.ID()] = true
+	s.sample = append(s.sample, n)
+	return true
+}
+
+// jump returns an unvisited node chosen uniformly at random. It must not be
+// called when all nodes have been visited.
+func (s *sampler) jump() graph.Node {
+	for s.visited[s.nodes[s.perm[s.next]].ID()] {
+		s.next++
+	}
+	return s.nodes[s.perm[s.next]]
+}
+
+// induce places in dst the subgraph of g induced by nodes.
+func induce(dst GraphBuilder, g graph.Graph, nodes []graph.Node) {
+	in := make(map[int]bool, len(nodes))
+	for _, n := range nodes {
+		in[n.ID()] = true
+		if !dst.Has(n) {
+			dst.AddNode(n)
+		}
+	}
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if in[v.ID()] {
+				dst.SetEdge(g.Edge(u, v))
+			}
+		}
+	}
+}
+
+func intnFor(src *rand.Rand) func(int) int {
+	if src == nil {
+		return rand.Intn
+	}
+	return src.Intn
+}
+
+func float64For(src *rand.Rand) func() float64 {
+	if src == nil {
+		return rand.Float64
+	}
+	return src.Float64
+}
@@ -0,0 +1,201 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sample
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func gnp(t *testing.T, n int, p float64, directed bool, seed int64) graph.Graph {
+	g := newBuilder(directed)
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	err := gen.Gnp(g, n, p, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		t.Fatalf("failed to generate graph: %v", err)
+	}
+	return g
+}
+
+func newBuilder(directed bool) interface {
+	graph.Graph
+	GraphBuilder
+} {
+	if directed {
+		return simple.NewDirectedGraph(0, math.Inf(1))
+	}
+	return simple.NewUndirectedGraph(0, math.Inf(1))
+}
+
+// checkInduced checks that s is the subgraph of g induced by the nodes of s.
+func checkInduced(t *testing.T, name string, g, s graph.Graph) {
+	for _, u := range s.Nodes() {
+		if !g.Has(u) {
+			t.Errorf("%s: sampled node %d not in graph", name, u.ID())
+		}
+		for _, v := range s.Nodes() {
+			if u.ID() == v.ID() {
+				continue
+			}
+			if (g.Edge(u, v) != nil) != (s.Edge(u, v) != nil) {
+				t.Errorf("%s: sample is not induced at %d-%d", name, u.ID(), v.ID())
+			}
+		}
+	}
+}
+
+var samplingTests = []struct {
+	name     string
+	n        int
+	p        float64
+	directed bool
+	size     int
+}{
+	{name: "sparse undirected", n: 100, p: 0.02, size: 30},
+	{name: "dense undirected", n: 100, p: 0.2, size: 30},
+	{name: "sparse directed", n: 100, p: 0.02, directed: true, size: 30},
+	{name: "dense directed", n: 100, p: 0.2, directed: true, size: 30},
+	{name: "empty", n: 50, p: 0, size: 10},
+	{name: "oversize", n: 20, p: 0.1, size: 40},
+}
+
+func TestNodes(t *testing.T) {
+	for _, test := range samplingTests {
+		g := gnp(t, test.n, test.p, test.directed, 1)
+		dst := newBuilder(test.directed)
+		Nodes(dst, g, test.size, rand.New(rand.NewSource(1)))
+		want := test.size
+		if want > test.n {
+			want = test.n
+		}
+		if n := len(dst.Nodes()); n != want {
+			t.Errorf("%s: unexpected sample size: got:%d want:%d", test.name, n, want)
+		}
+		checkInduced(t, test.name, g, dst)
+	}
+}
+
+func TestEdges(t *testing.T) {
+	for _, test := range samplingTests {
+		g := gnp(t, test.n, test.p, test.directed, 1)
+		dst := newBuilder(test.directed)
+		Edges(dst, g, test.size, rand.New(rand.NewSource(1)))
+
+		var edges int
+		for _, u := range g.Nodes() {
+			edges += len(g.From(u))
+		}
+		if !test.directed {
+			edges /= 2
+		}
+		want := test.size
+		if want > edges {
+			want = edges
+		}
+		var got int
+		for _, u := range dst.Nodes() {
+			for _, v := range dst.From(u) {
+				got++
+				if g.Edge(u, v) == nil {
+					t.Errorf("%s: sampled edge %d-%d not in graph", test.name, u.ID(), v.ID())
+				}
+			}
+		}
+		if !test.directed {
+			got /= 2
+		}
+		if got != want {
+			t.Errorf("%s: unexpected number of sampled edges: got:%d want:%d", test.name, got, want)
+		}
+	}
+}
+
+func TestRandomWalk(t *testing.T) {
+	for _, test := range samplingTests {
+		for _, escape := range []float64{0, 0.15, 1} {
+			g := gnp(t, test.n, test.p, test.directed, 1)
+			dst := newBuilder(test.directed)
+			err := RandomWalk(dst, g, test.size, escape, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			want := test.size
+			if want > test.n {
+				want = test.n
+			}
+			if n := len(dst.Nodes()); n != want {
+				t.Errorf("%s escape=%v: unexpected sample size: got:%d want:%d", test.name, escape, n, want)
+			}
+			checkInduced(t, test.name, g, dst)
+		}
+	}
+
+	err := RandomWalk(newBuilder(false), gnp(t, 10, 0.1, false, 1), 5, 1.5, nil)
+	if err == nil {
+		t.Error("expected error for invalid escape probability")
+	}
+}
+
+func TestForestFire(t *testing.T) {
+	for _, test := range samplingTests {
+		for _, p := range []float64{0, 0.35, 0.7} {
+			g := gnp(t, test.n, test.p, test.directed, 1)
+			dst := newBuilder(test.directed)
+			err := ForestFire(dst, g, test.size, p, p/2, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			want := test.size
+			if want > test.n {
+				want = test.n
+			}
+			if n := len(dst.Nodes()); n != want {
+				t.Errorf("%s forward=%v: unexpected sample size: got:%d want:%d", test.name, p, n, want)
+			}
+			checkInduced(t, test.name, g, dst)
+		}
+	}
+
+	for _, p := range [][2]float64{{1, 0}, {0, 1}, {-0.1, 0}} {
+		err := ForestFire(newBuilder(true), gnp(t, 10, 0.1, true, 1), 5, p[0], p[1], nil)
+		if err == nil {
+			t.Errorf("expected error for invalid burning probabilities: %v", p)
+		}
+	}
+}
+
+func TestSamplingDeterministic(t *testing.T) {
+	g := gnp(t, 200, 0.05, false, 1)
+	sample := func() []int {
+		dst := newBuilder(false)
+		err := ForestFire(dst, g, 50, 0.7, 0, rand.New(rand.NewSource(7)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []int
+		for i := 0; i < 200; i++ {
+			if dst.Has(simple.Node(i)) {
+				ids = append(ids, i)
+			}
+		}
+		return ids
+	}
+	a, b := sample(), sample()
+	if len(a) != len(b) {
+		t.Fatalf("sampling not deterministic: %v != %v", a, b)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("sampling not deterministic: %v != %v", a, b)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schema provides declarative constraints on the structure of graphs
+// and validation of graphs against them.
+//
+// A Schema describes the node labels and edge types a graph may hold, which
+// edge types may join nodes of which labels, bounds on node degrees, the
+// attributes nodes and edges must have and whether the graph must be
+// acyclic. Validating a graph returns every violation of the schema, so that
+// data from untrusted sources can be reported on in full rather than failing
+// at the first error.
+package schema
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/topo"
+)
+
+// Any matches any node label or edge type in an EdgeRule or DegreeBound.
+const Any = "*"
+
+// NodeLabel returns the label of the node n.
+type NodeLabel func(n graph.Node) string
+
+// EdgeType returns the type of the edge e.
+type EdgeType func(e graph.Edge) string
+
+// NodeAttribute returns a NodeLabel that takes the label of a node from its
+// graph.Attributer attribute with the given key. Nodes without the attribute
+// have the empty label.
+func NodeAttribute(key string) NodeLabel {
+	return func(n graph.Node) string {
+		v, _ := attribute(n, key)
+		return v
+	}
+}
+
+// EdgeAttribute returns an EdgeType that takes the type of an edge from its
+// graph.Attributer attribute with the given key. Edges without the attribute
+// have the empty type.
+func EdgeAttribute(key string) EdgeType {
+	return func(e graph.Edge) string {
+		v, _ := attribute(e, key)
+		return v
+	}
+}
+
+// EdgeRule allows edges of type Type from nodes labeled From to nodes
+// labeled To. Any of the fields may be Any. In undirected graphs the rule
+// matches edges in either orientation.
+type EdgeRule struct {
+	From, Type, To string
+}
+
+// Direction specifies the edges counted by a DegreeBound.
+type Direction int
+
+const (
+	// Both counts all edges of a node.
+	Both Direction = iota
+	// Out counts the edges leaving a node.
+	Out
+	// In counts the edges entering a node.
+	In
+)
+
+// DegreeBound bounds the degree of nodes labeled Label, which may be Any.
+// A negative Max places no upper bound on the degree. In undirected graphs
+// the in, out and total degree of a node are all its degree.
+type DegreeBound struct {
+	Label     string
+	Direction Direction
+	Min, Max  int
+}
+
+func (b DegreeBound) contains(d int) bool {
+	return b.Min <= d && (b.Max < 0 || d <= b.Max)
+}
+
+// Schema is a set of constraints on a graph. The zero value places no
+// constraint on a graph.
+type Schema struct {
+	// NodeLabel and EdgeType return the label of
+	// a node and the type of an edge. If nil, all
+	// nodes and edges have the empty label and type.
+	NodeLabel NodeLabel
+	EdgeType  EdgeType
+
+	// Labels is the set of allowed node labels.
+	// If empty, any label is allowed.
+	Labels []string
+
+	// Edges is the set of allowed edges. If
+	// empty, any edge is allowed.
+	Edges []EdgeRule
+
+	// Degrees holds the bounds on node degrees.
+	// A node must satisfy every bound that
+	// applies to its label.
+	Degrees []DegreeBound
+
+	// Acyclic specifies that the graph must
+	// not hold a cycle.
+	Acyclic bool
+
+	// NodeAttributes and EdgeAttributes hold the
+	// graph.Attributer attribute keys required of
+	// nodes by label and of edges by type. Keys
+	// listed under Any are required of all nodes
+	// or edges.
+	NodeAttributes map[string][]string
+	EdgeAttributes map[string][]string
+}
+
+// Kind is the kind of a schema violation.
+type Kind int
+
+const (
+	// UnknownLabel is a node with a label not
+	// allowed by the schema.
+	UnknownLabel Kind = iota + 1
+	// InvalidEdge is an edge not allowed by
+	// the schema.
+	InvalidEdge
+	// DegreeOutOfBounds is a node with a degree
+	// outside a bound.
+	DegreeOutOfBounds
+	// MissingAttribute is a node or edge without
+	// a required attribute.
+	MissingAttribute
+	// Cycle is a set of nodes forming a cycle
+	// in a graph required to be acyclic.
+	Cycle
+)
+
+var kindNames = map[Kind]string{
+	UnknownLabel:      "unknown label",
+	InvalidEdge:       "invalid edge",
+	DegreeOutOfBounds: "degree out of bounds",
+	MissingAttribute:  "missing attribute",
+	Cycle:             "cycle",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Violation is a violation of a schema.
+type Violation struct {
+	Kind Kind
+
+	// Node is the node in violation for the
+	// UnknownLabel and DegreeOutOfBounds kinds
+	// and for missing node attributes.
+	Node graph.Node
+
+	// Edge is the edge in violation for the
+	// InvalidEdge kind and for missing edge
+	// attributes.
+	Edge graph.Edge
+
+	// Label is the label of Node or the type
+	// of Edge.
+	Label string
+
+	// Attribute is the key of a missing attribute.
+	Attribute string
+
+	// Degree and Bound are the degree of Node
+	// and the bound it violates.
+	Degree int
+	Bound  DegreeBound
+
+	// Cycle holds the nodes, sorted by ID, of a
+	// strongly connected component of a directed
+	// graph, or a connected component of an
+	// undirected graph, that holds a cycle.
+	Cycle []graph.Node
+}
+
+// Error satisfies the error interface.
+func (v Violation) Error() string {
+	switch v.Kind {
+	case UnknownLabel:
+		return fmt.Sprintf("schema: node %d: unknown label %q", v.Node.ID(), v.Label)
+	case InvalidEdge:
+		return fmt.Sprintf("schema: edge %d-%d: edge of type %q not allowed", v.Edge.From().ID(), v.Edge.To().ID(), v.Label)
+	case DegreeOutOfBounds:
+		max := "inf"
+		if v.Bound.Max >= 0 {
+			max = fmt.Sprint(v.Bound.Max)
+		}
+		return fmt.Sprintf("schema: node %d: degree %d outside [%d,%s]", v.Node.ID(), v.Degree, v.Bound.Min, max)
+	case MissingAttribute:
+		if v.Edge != nil {
+			return fmt.Sprintf("schema: edge %d-%d: missing attribute %q", v.Edge.From().ID(), v.Edge.To().ID(), v.Attribute)
+		}
+		return fmt.Sprintf("schema: node %d: missing attribute %q", v.Node.ID(), v.Attribute)
+	case Cycle:
+		return fmt.Sprintf("schema: cycle in %v", v.Cycle)
+	}
+	return "schema: " + v.Kind.String()
+}
+
+// Violations is a list of schema violations.
+type Violations []Violation
+
+// Error satisfies the error interface.
+func (v Violations) Error() string {
+	switch len(v) {
+	case 0:
+		return "schema: no violations"
+	case 1:
+		return v[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d other violations)", v[0].Error(), len(v)-1)
+}
+
+// Validate returns the violations of the schema s by g, or nil if g satisfies
+// s. Node violations are listed first in order of node ID, followed by edge
+// violations in order of their from and to node IDs and then cycles.
+func (s Schema) Validate(g graph.Graph) Violations {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	dg, directed := g.(graph.Directed)
+
+	labels := make(map[int]string, len(nodes))
+	for _, n := range nodes {
+		if s.NodeLabel != nil {
+			labels[n.ID()] = s.NodeLabel(n)
+		}
+	}
+
+	var vs Violations
+	for _, n := range nodes {
+		l := labels[n.ID()]
+		if len(s.Labels) != 0 && !contains(s.Labels, l) {
+			vs = append(vs, Violation{Kind: UnknownLabel, Node: n, Label: l})
+		}
+		for _, key := range required(s.NodeAttributes, l) {
+			if _, ok := attribute(n, key); !ok {
+				vs = append(vs, Violation{Kind: MissingAttribute, Node: n, Label: l, Attribute: key})
+			}
+		}
+		for _, b := range s.Degrees {
+			if b.Label != Any && b.Label != l {
+				continue
+			}
+			var d int
+			switch {
+			case !directed:
+				d = len(g.From(n))
+			case b.Direction == Out:
+				d = len(dg.From(n))
+			case b.Direction == In:
+				d = len(dg.To(n))
+			default:
+				d = len(dg.From(n)) + len(dg.To(n))
+			}
+			if !b.contains(d) {
+				vs = append(vs, Violation{Kind: DegreeOutOfBounds, Node: n, Label: l, Degree: d, Bound: b})
+			}
+		}
+	}
+
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !directed && v.ID() < u.ID() {
+				continue
+			}
+			e := g.Edge(u, v)
+			var t string
+			if s.EdgeType != nil {
+				t = s.EdgeType(e)
+			}
+			if len(s.Edges) != 0 && !s.allowed(labels[u.ID()], t, labels[v.ID()], directed) {
+				vs = append(vs, Violation{Kind: InvalidEdge, Edge: e, Label: t})
+			}
+			for _, key := range required(s.EdgeAttributes, t) {
+				if _, ok := attribute(e, key); !ok {
+					vs = append(vs, Violation{Kind: MissingAttribute, Edge: e, Label: t, Attribute: key})
+				}
+			}
+		}
+	}
+
+	if s.Acyclic {
+		vs = append(vs, cycles(g)...)
+	}
+	return vs
+}
+
+// allowed returns whether an edge of type t from a node labeled from to a
+// node labeled to is allowed by the edge rules of s.
+func (s Schema) allowed(from, t, to string, directed bool) bool {
+	for _, r := range s.Edges {
+		if !match(r.Type, t) {
+			continue
+		}
+		if match(r.From, from) && match(r.To, to) {
+			return true
+		}
+		if !directed && match(r.From, to) && match(r.To, from) {
+			return true
+		}
+	}
+	return false
+}
+
+// cycles returns the Cycle violations of g.
+func cycles(g graph.Graph) Violations {
+	var vs Violations
+	if dg, ok := g.(graph.Directed); ok {
+		for _, c := range topo.TarjanSCC(dg) {
+			if len(c) == 1 && !dg.HasEdgeFromTo(c[0], c[0]) {
+				continue
+			}
+			sort.Sort(ordered.ByID(c))
+			vs = append(vs, Violation{Kind: Cycle, Cycle: c})
+		}
+	} else if ug, ok := g.(graph.Undirected); ok {
+		for _, c := range topo.ConnectedComponents(ug) {
+			// A connected component is a tree
+			// if it has one fewer edges than nodes.
+			var edges int
+			for _, u := range c {
+				for _, v := range ug.From(u) {
+					if u.ID() <= v.ID() {
+						edges++
+					}
+				}
+			}
+			if edges < len(c) {
+				continue
+			}
+			sort.Sort(ordered.ByID(c))
+			vs = append(vs, Violation{Kind: Cycle, Cycle: c})
+		}
+	}
+	sort.Sort(byFirstID(vs))
+	return vs
+}
+
+// byFirstID sorts cycle violations by the ID of their first node.
+type byFirstID Violations
+
+func (v byFirstID) Len() int           { return len(v) }
+func (v byFirstID) Less(i, j int) bool { return v[i].Cycle[0].ID() < v[j].Cycle[0].ID() }
+func (v byFirstID) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+
+// required returns the attribute keys required for the label l.
+func required(attrs map[string][]string, l string) []string {
+	all := attrs[Any]
+	if l == Any {
+		return all
+	}
+	return append(all[:len(all):len(all)], attrs[l]...)
+}
+
+func match(pattern, s string) bool { return pattern == Any || pattern == s }
+
+func contains(set []string, s string) bool {
+	for _, e := range set {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// attribute returns the value of the graph.Attributer attribute of v with
+// the given key.
+func attribute(v interface{}, key string) (string, bool) {
+	a, ok := v.(graph.Attributer)
+	if !ok {
+		return "", false
+	}
+	for _, attr := range a.Attributes() {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
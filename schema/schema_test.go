@@ -0,0 +1,166 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+type node struct {
+	id    int
+	attrs []graph.Attribute
+}
+
+func (n node) ID() int                       { return n.id }
+func (n node) Attributes() []graph.Attribute { return n.attrs }
+
+type edge struct {
+	f, t  graph.Node
+	attrs []graph.Attribute
+}
+
+func (e edge) From() graph.Node              { return e.f }
+func (e edge) To() graph.Node                { return e.t }
+func (e edge) Weight() float64               { return 1 }
+func (e edge) Attributes() []graph.Attribute { return e.attrs }
+
+func attrs(kv ...string) []graph.Attribute {
+	var a []graph.Attribute
+	for i := 0; i < len(kv); i += 2 {
+		a = append(a, graph.Attribute{Key: kv[i], Value: kv[i+1]})
+	}
+	return a
+}
+
+// violation is a comparable summary of a Violation.
+type violation struct {
+	kind   Kind
+	id     int
+	from   int
+	to     int
+	attr   string
+	degree int
+	cycle  []int
+}
+
+func summarize(vs Violations) []violation {
+	var s []violation
+	for _, v := range vs {
+		c := violation{kind: v.Kind, id: -1, from: -1, to: -1, attr: v.Attribute, degree: v.Degree}
+		if v.Node != nil {
+			c.id = v.Node.ID()
+		}
+		if v.Edge != nil {
+			c.from, c.to = v.Edge.From().ID(), v.Edge.To().ID()
+		}
+		for _, n := range v.Cycle {
+			c.cycle = append(c.cycle, n.ID())
+		}
+		s = append(s, c)
+	}
+	return s
+}
+
+// social returns a directed graph of people and the companies they work for.
+func social() *simple.DirectedGraph {
+	g := simple.NewDirectedGraph(0, 0)
+	nodes := []node{
+		{id: 0, attrs: attrs("label", "person", "name", "ann")},
+		{id: 1, attrs: attrs("label", "person", "name", "bob")},
+		{id: 2, attrs: attrs("label", "company", "name", "acme")},
+		{id: 3, attrs: attrs("label", "person")},
+		{id: 4, attrs: attrs("label", "robot", "name", "r2")},
+	}
+	for _, n := range nodes {
+		g.AddNode(n)
+	}
+	for _, e := range []edge{
+		{f: nodes[0], t: nodes[1], attrs: attrs("type", "knows")},
+		{f: nodes[0], t: nodes[2], attrs: attrs("type", "works_at", "since", "2001")},
+		{f: nodes[1], t: nodes[2], attrs: attrs("type", "works_at")},
+		{f: nodes[2], t: nodes[0], attrs: attrs("type", "knows")},
+	} {
+		g.SetEdge(e)
+	}
+	return g
+}
+
+func TestValidate(t *testing.T) {
+	s := Schema{
+		NodeLabel: NodeAttribute("label"),
+		EdgeType:  EdgeAttribute("type"),
+		Labels:    []string{"person", "company"},
+		Edges: []EdgeRule{
+			{From: "person", Type: "knows", To: "person"},
+			{From: "person", Type: "works_at", To: "company"},
+		},
+		Degrees: []DegreeBound{
+			{Label: "person", Direction: Out, Min: 1, Max: -1},
+			{Label: "company", Direction: In, Min: 0, Max: 1},
+		},
+		Acyclic:        true,
+		NodeAttributes: map[string][]string{Any: {"name"}},
+		EdgeAttributes: map[string][]string{"works_at": {"since"}},
+	}
+	got := summarize(s.Validate(social()))
+	want := []violation{
+		{kind: DegreeOutOfBounds, id: 2, from: -1, to: -1, degree: 2},
+		{kind: MissingAttribute, id: 3, from: -1, to: -1, attr: "name"},
+		{kind: DegreeOutOfBounds, id: 3, from: -1, to: -1, degree: 0},
+		{kind: UnknownLabel, id: 4, from: -1, to: -1},
+		{kind: MissingAttribute, id: -1, from: 1, to: 2, attr: "since"},
+		{kind: InvalidEdge, id: -1, from: 2, to: 0},
+		{kind: Cycle, id: -1, from: -1, to: -1, cycle: []int{0, 1, 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected violations:\ngot: %+v\nwant:%+v", got, want)
+	}
+}
+
+func TestValidateUndirected(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, 0)
+	// A tree 0-1-2 and a triangle 3-4-5.
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {3, 4}, {4, 5}, {5, 3}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	s := Schema{
+		NodeLabel: func(n graph.Node) string {
+			if n.ID()%2 == 0 {
+				return "even"
+			}
+			return "odd"
+		},
+		// Edges must join even and odd nodes,
+		// which 5-3 does not.
+		Edges:   []EdgeRule{{From: "odd", Type: Any, To: "even"}},
+		Degrees: []DegreeBound{{Label: Any, Min: 0, Max: 1}},
+		Acyclic: true,
+	}
+	got := summarize(s.Validate(g))
+	want := []violation{
+		{kind: DegreeOutOfBounds, id: 1, from: -1, to: -1, degree: 2},
+		{kind: DegreeOutOfBounds, id: 3, from: -1, to: -1, degree: 2},
+		{kind: DegreeOutOfBounds, id: 4, from: -1, to: -1, degree: 2},
+		{kind: DegreeOutOfBounds, id: 5, from: -1, to: -1, degree: 2},
+		{kind: InvalidEdge, id: -1, from: 5, to: 3},
+		{kind: Cycle, id: -1, from: -1, to: -1, cycle: []int{3, 4, 5}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected violations:\ngot: %+v\nwant:%+v", got, want)
+	}
+}
+
+func TestValidateEmpty(t *testing.T) {
+	if vs := (Schema{}).Validate(social()); vs != nil {
+		t.Errorf("unexpected violations for empty schema: %v", vs)
+	}
+	if vs := (Schema{Acyclic: true}).Validate(simple.NewDirectedGraph(0, 0)); vs != nil {
+		t.Errorf("unexpected violations for empty graph: %v", vs)
+	}
+}
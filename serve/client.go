@@ -0,0 +1,177 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Client is a graph backed by a remote graph service. Client implements
+// graph.Directed and graph.Weighter. Nodes and edges returned by a Client are
+// simple.Node and simple.Edge values.
+//
+// The graph interface methods panic if the service cannot be reached or
+// returns an error. The To and HasEdgeFromTo methods panic if the remote
+// graph is not directed.
+type Client struct {
+	// URL is the base URL of the service.
+	URL string
+
+	// HTTP is the client used for requests.
+	// If HTTP is nil, http.DefaultClient
+	// is used.
+	HTTP *http.Client
+}
+
+var (
+	_ graph.Directed = Client{}
+	_ graph.Weighter = Client{}
+)
+
+// Info returns a description of the remote graph.
+func (c Client) Info() (Info, error) {
+	var info Info
+	err := c.get("/info", nil, &info)
+	return info, err
+}
+
+// Has returns whether the node exists within the graph.
+func (c Client) Has(n graph.Node) bool {
+	var has bool
+	c.mustGet("/has", url.Values{"id": {strconv.Itoa(n.ID())}}, &has)
+	return has
+}
+
+// Nodes returns all the nodes in the graph.
+func (c Client) Nodes() []graph.Node {
+	var id []int
+	c.mustGet("/nodes", nil, &id)
+	return nodes(id)
+}
+
+// From returns all nodes that can be reached directly from n.
+func (c Client) From(n graph.Node) []graph.Node {
+	var id []int
+	c.mustGet("/from", url.Values{"id": {strconv.Itoa(n.ID())}}, &id)
+	return nodes(id)
+}
+
+// To returns all nodes that can reach directly to n.
+func (c Client) To(n graph.Node) []graph.Node {
+	var id []int
+	c.mustGet("/to", url.Values{"id": {strconv.Itoa(n.ID())}}, &id)
+	return nodes(id)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (c Client) HasEdgeBetween(x, y graph.Node) bool {
+	var has bool
+	c.mustGet("/between", url.Values{"x": {strconv.Itoa(x.ID())}, "y": {strconv.Itoa(y.ID())}}, &has)
+	return has
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (c Client) HasEdgeFromTo(u, v graph.Node) bool {
+	return c.Edge(u, v) != nil
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+func (c Client) Edge(u, v graph.Node) graph.Edge {
+	var e *Edge
+	c.mustGet("/edge", url.Values{"u": {strconv.Itoa(u.ID())}, "v": {strconv.Itoa(v.ID())}}, &e)
+	if e == nil {
+		return nil
+	}
+	return simple.Edge{F: simple.Node(e.From), T: simple.Node(e.To), W: e.Weight}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a
+// non-nil Edge. If x and y are the same node the weight is zero and if there is
+// no joining edge the weight is +Inf. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (c Client) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return 0, true
+	}
+	if e := c.Edge(x, y); e != nil {
+		return e.Weight(), true
+	}
+	return math.Inf(1), false
+}
+
+// ShortestPath returns the shortest path from u to v computed by the service and
+// its weight. If no path exists, path is nil and the weight is +Inf.
+func (c Client) ShortestPath(u, v graph.Node) (path []graph.Node, weight float64, err error) {
+	var p Path
+	err = c.get("/path", url.Values{"from": {strconv.Itoa(u.ID())}, "to": {strconv.Itoa(v.ID())}}, &p)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(p.Path) == 0 {
+		return nil, math.Inf(1), nil
+	}
+	return nodes(p.Path), p.Weight, nil
+}
+
+// Components returns the connected components of the remote graph computed by the
+// service. Components of directed graphs are the weakly connected components.
+func (c Client) Components() ([][]graph.Node, error) {
+	var id [][]int
+	err := c.get("/components", nil, &id)
+	if err != nil {
+		return nil, err
+	}
+	cc := make([][]graph.Node, len(id))
+	for i, ids := range id {
+		cc[i] = nodes(ids)
+	}
+	return cc, nil
+}
+
+func (c Client) mustGet(path string, query url.Values, v interface{}) {
+	err := c.get(path, query, v)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (c Client) get(path string, query url.Values, v interface{}) error {
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	u := c.URL + path
+	if query != nil {
+		u += "?" + query.Encode()
+	}
+	resp, err := client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("serve: %s: %s", resp.Status, msg)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func nodes(id []int) []graph.Node {
+	n := make([]graph.Node, len(id))
+	for i, v := range id {
+		n[i] = simple.Node(v)
+	}
+	return n
+}
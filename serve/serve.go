@@ -0,0 +1,220 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package serve provides an HTTP/JSON service exposing a graph and a client
+// that implements the graph interfaces against a remote service.
+//
+// The service handles GET requests to the following paths, with node IDs
+// passed as query parameters:
+//
+//  /info                   {"directed": bool}
+//  /nodes                  [id, ...]
+//  /has?id=                bool
+//  /from?id=               [id, ...]
+//  /to?id=                 [id, ...] (directed graphs only)
+//  /edge?u=&v=             {"from": id, "to": id, "weight": w} or null
+//  /between?x=&y=          bool
+//  /path?from=&to=         {"path": [id, ...], "weight": w}
+//  /components             [[id, ...], ...]
+//
+// Components of directed graphs are the weakly connected components.
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+// Info describes a served graph.
+type Info struct {
+	Directed bool `json:"directed"`
+}
+
+// Edge is the JSON representation of an edge.
+type Edge struct {
+	From   int     `json:"from"`
+	To     int     `json:"to"`
+	Weight float64 `json:"weight"`
+}
+
+// Path is the JSON representation of a shortest path. An empty Path
+// indicates that no path exists.
+type Path struct {
+	Path   []int   `json:"path"`
+	Weight float64 `json:"weight"`
+}
+
+// NewHandler returns an http.Handler serving g. The graph must not be mutated
+// while the handler is in use unless access is synchronized by the caller.
+func NewHandler(g graph.Graph) http.Handler {
+	h := handler{g: g}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", h.info)
+	mux.HandleFunc("/nodes", h.nodes)
+	mux.HandleFunc("/has", h.has)
+	mux.HandleFunc("/from", h.from)
+	mux.HandleFunc("/to", h.to)
+	mux.HandleFunc("/edge", h.edge)
+	mux.HandleFunc("/between", h.between)
+	mux.HandleFunc("/path", h.path)
+	mux.HandleFunc("/components", h.components)
+	return mux
+}
+
+type handler struct {
+	g graph.Graph
+}
+
+func (h handler) info(w http.ResponseWriter, r *http.Request) {
+	_, directed := h.g.(graph.Directed)
+	reply(w, Info{Directed: directed})
+}
+
+func (h handler) nodes(w http.ResponseWriter, r *http.Request) {
+	reply(w, ids(h.g.Nodes()))
+}
+
+func (h handler) has(w http.ResponseWriter, r *http.Request) {
+	id, ok := param(w, r, "id")
+	if !ok {
+		return
+	}
+	reply(w, h.g.Has(simple.Node(id)))
+}
+
+func (h handler) from(w http.ResponseWriter, r *http.Request) {
+	id, ok := param(w, r, "id")
+	if !ok {
+		return
+	}
+	reply(w, ids(h.g.From(simple.Node(id))))
+}
+
+func (h handler) to(w http.ResponseWriter, r *http.Request) {
+	d, ok := h.g.(graph.Directed)
+	if !ok {
+		http.Error(w, "serve: graph is not directed", http.StatusNotFound)
+		return
+	}
+	id, ok := param(w, r, "id")
+	if !ok {
+		return
+	}
+	reply(w, ids(d.To(simple.Node(id))))
+}
+
+func (h handler) edge(w http.ResponseWriter, r *http.Request) {
+	u, ok := param(w, r, "u")
+	if !ok {
+		return
+	}
+	v, ok := param(w, r, "v")
+	if !ok {
+		return
+	}
+	e := h.g.Edge(simple.Node(u), simple.Node(v))
+	if e == nil {
+		reply(w, nil)
+		return
+	}
+	reply(w, Edge{From: e.From().ID(), To: e.To().ID(), Weight: e.Weight()})
+}
+
+func (h handler) between(w http.ResponseWriter, r *http.Request) {
+	x, ok := param(w, r, "x")
+	if !ok {
+		return
+	}
+	y, ok := param(w, r, "y")
+	if !ok {
+		return
+	}
+	reply(w, h.g.HasEdgeBetween(simple.Node(x), simple.Node(y)))
+}
+
+func (h handler) path(w http.ResponseWriter, r *http.Request) {
+	from, ok := param(w, r, "from")
+	if !ok {
+		return
+	}
+	to, ok := param(w, r, "to")
+	if !ok {
+		return
+	}
+	if !h.g.Has(simple.Node(from)) || !h.g.Has(simple.Node(to)) {
+		http.Error(w, "serve: node not in graph", http.StatusNotFound)
+		return
+	}
+	p, weight := path.DijkstraFrom(simple.Node(from), h.g).To(simple.Node(to))
+	if math.IsInf(weight, 1) {
+		reply(w, Path{})
+		return
+	}
+	reply(w, Path{Path: ids(p), Weight: weight})
+}
+
+func (h handler) components(w http.ResponseWriter, r *http.Request) {
+	var cc [][]graph.Node
+	switch g := h.g.(type) {
+	case graph.Directed:
+		cc = topo.ConnectedComponents(graph.Undirect{G: g})
+	case graph.Undirected:
+		cc = topo.ConnectedComponents(g)
+	default:
+		http.Error(w, "serve: components require a directed or undirected graph", http.StatusNotImplemented)
+		return
+	}
+	c := make([][]int, len(cc))
+	for i, nodes := range cc {
+		c[i] = ids(nodes)
+	}
+	sort.Sort(byFirst(c))
+	reply(w, c)
+}
+
+// ids returns the sorted IDs of nodes.
+func ids(nodes []graph.Node) []int {
+	sort.Sort(ordered.ByID(nodes))
+	id := make([]int, len(nodes))
+	for i, n := range nodes {
+		id[i] = n.ID()
+	}
+	return id
+}
+
+// byFirst sorts sorted ID lists by their first element.
+type byFirst [][]int
+
+func (c byFirst) Len() int           { return len(c) }
+func (c byFirst) Less(i, j int) bool { return c[i][0] < c[j][0] }
+func (c byFirst) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// param returns the integer query parameter with the given name. If the
+// parameter is missing or malformed an error is written to w.
+func param(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("serve: invalid %s parameter", name), http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func reply(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
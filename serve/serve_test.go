@@ -0,0 +1,147 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package serve
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+func directedServer() (*httptest.Server, *simple.DirectedGraph) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(0), T: simple.Node(2), W: 5},
+		{F: simple.Node(3), T: simple.Node(4), W: 1},
+	} {
+		g.SetEdge(e)
+	}
+	g.AddNode(simple.Node(5))
+	return httptest.NewServer(NewHandler(g)), g
+}
+
+func sortedIDs(nodes []graph.Node) []int {
+	sort.Sort(ordered.ByID(nodes))
+	var id []int
+	for _, n := range nodes {
+		id = append(id, n.ID())
+	}
+	return id
+}
+
+func TestClient(t *testing.T) {
+	srv, g := directedServer()
+	defer srv.Close()
+	c := Client{URL: srv.URL}
+
+	info, err := c.Info()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Directed {
+		t.Errorf("expected directed graph")
+	}
+
+	if got, want := sortedIDs(c.Nodes()), sortedIDs(g.Nodes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes: got:%v want:%v", got, want)
+	}
+	for _, u := range g.Nodes() {
+		if !c.Has(u) {
+			t.Errorf("missing node %d", u.ID())
+		}
+		if got, want := sortedIDs(c.From(u)), sortedIDs(g.From(u)); !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected nodes from %d: got:%v want:%v", u.ID(), got, want)
+		}
+		if got, want := sortedIDs(c.To(u)), sortedIDs(g.To(u)); !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected nodes to %d: got:%v want:%v", u.ID(), got, want)
+		}
+		for _, v := range g.Nodes() {
+			if got, want := c.HasEdgeBetween(u, v), g.HasEdgeBetween(u, v); got != want {
+				t.Errorf("unexpected HasEdgeBetween(%d, %d): got:%t want:%t", u.ID(), v.ID(), got, want)
+			}
+			gw, gok := c.Weight(u, v)
+			ww, wok := g.Weight(u, v)
+			if gw != ww || gok != wok {
+				t.Errorf("unexpected weight from %d to %d: got:%v %t want:%v %t", u.ID(), v.ID(), gw, gok, ww, wok)
+			}
+		}
+	}
+	if c.Has(simple.Node(10)) {
+		t.Errorf("unexpected node 10")
+	}
+
+	p, w, err := c.ShortestPath(simple.Node(0), simple.Node(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := sortedIDs(p); w != 3 || !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("unexpected shortest path: got:%v %v want:[0 1 2] 3", got, w)
+	}
+	p, w, err = c.ShortestPath(simple.Node(0), simple.Node(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p != nil || !math.IsInf(w, 1) {
+		t.Errorf("unexpected path between disconnected nodes: got:%v %v", p, w)
+	}
+	if _, _, err = c.ShortestPath(simple.Node(0), simple.Node(10)); err == nil {
+		t.Errorf("expected error for missing node")
+	}
+
+	cc, err := c.Components()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got [][]int
+	for _, nodes := range cc {
+		got = append(got, sortedIDs(nodes))
+	}
+	if want := [][]int{{0, 1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected components: got:%v want:%v", got, want)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	srv := httptest.NewServer(NewHandler(g))
+	defer srv.Close()
+
+	for _, test := range []struct {
+		path string
+		code int
+	}{
+		{path: "/from", code: http.StatusBadRequest},
+		{path: "/from?id=x", code: http.StatusBadRequest},
+		{path: "/to?id=0", code: http.StatusNotFound},
+		{path: "/nodes", code: http.StatusOK},
+	} {
+		resp, err := http.Get(srv.URL + test.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.code {
+			t.Errorf("unexpected status for %s: got:%d want:%d", test.path, resp.StatusCode, test.code)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic for To on undirected graph")
+			}
+		}()
+		Client{URL: srv.URL}.To(simple.Node(0))
+	}()
+}
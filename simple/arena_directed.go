@@ -0,0 +1,309 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+
+	"github.com/gonum/graph"
+)
+
+// ArenaDirectedGraph implements a generalized directed graph with compact
+// storage for large graphs.
+//
+// Unlike DirectedGraph, which holds edges in a map of maps, ArenaDirectedGraph
+// holds each node in a slot of a slice and the edges of each node in slices of
+// slot and weight pairs, so the garbage collector scans few pointers and each
+// edge occupies 32 bytes. Slots of removed nodes are reused by added nodes.
+//
+// Only the end points and weight of an edge are retained, so Edge returns an
+// Edge value rather than the graph.Edge passed to SetEdge. Edge lookup takes
+// time linear in the out-degree of the from node.
+type ArenaDirectedGraph struct {
+	// slot maps node IDs to slots.
+	slot  map[int]int
+	nodes []graph.Node
+	from  [][]halfEdge
+	to    [][]halfEdge
+
+	// free holds the unused slots.
+	free []int
+
+	self, absent float64
+
+	ids IDAllocator
+}
+
+// halfEdge is an edge incident to a node, holding the
+// slot of the node at the other end of the edge.
+type halfEdge struct {
+	slot int
+	w    float64
+}
+
+// NewArenaDirectedGraph returns an ArenaDirectedGraph with the specified self
+// and absent edge weight values.
+func NewArenaDirectedGraph(self, absent float64) *ArenaDirectedGraph {
+	return &ArenaDirectedGraph{
+		slot: make(map[int]int),
+
+		self:   self,
+		absent: absent,
+
+		ids: &ReuseIDs{},
+	}
+}
+
+// NewNodeID returns a new unique ID for a node to be added to g. The returned ID does
+// not become a valid ID in g until it is added to g. IDs are allocated by the
+// IDAllocator of g, which is a ReuseIDs unless set by SetIDAllocator.
+func (g *ArenaDirectedGraph) NewNodeID() int {
+	return g.ids.NewID()
+}
+
+// SetIDAllocator sets the allocator of the IDs returned by NewNodeID to a,
+// marking the IDs of the nodes in g as in use by a.
+func (g *ArenaDirectedGraph) SetIDAllocator(a IDAllocator) {
+	for id := range g.slot {
+		a.Use(id)
+	}
+	g.ids = a
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *ArenaDirectedGraph) AddNode(n graph.Node) {
+	if _, exists := g.slot[n.ID()]; exists {
+		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
+	}
+	var s int
+	if len(g.free) != 0 {
+		s = g.free[len(g.free)-1]
+		g.free = g.free[:len(g.free)-1]
+		g.nodes[s] = n
+	} else {
+		s = len(g.nodes)
+		g.nodes = append(g.nodes, n)
+		g.from = append(g.from, nil)
+		g.to = append(g.to, nil)
+	}
+	g.slot[n.ID()] = s
+
+	g.ids.Use(n.ID())
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it. If the node
+// is not in the graph it is a no-op.
+func (g *ArenaDirectedGraph) RemoveNode(n graph.Node) {
+	s, ok := g.slot[n.ID()]
+	if !ok {
+		return
+	}
+	delete(g.slot, n.ID())
+
+	for _, e := range g.from[s] {
+		g.to[e.slot] = removeHalfEdge(g.to[e.slot], s)
+	}
+	for _, e := range g.to[s] {
+		g.from[e.slot] = removeHalfEdge(g.from[e.slot], s)
+	}
+	g.nodes[s] = nil
+	g.from[s] = nil
+	g.to[s] = nil
+	g.free = append(g.free, s)
+
+	g.ids.Release(n.ID())
+}
+
+// find returns the index of the half edge to the given slot in edges, or -1
+// if it is not present.
+func find(edges []halfEdge, slot int) int {
+	for i, e := range edges {
+		if e.slot == slot {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeHalfEdge removes the half edge to the given slot from edges.
+func removeHalfEdge(edges []halfEdge, slot int) []halfEdge {
+	i := find(edges, slot)
+	if i < 0 {
+		return edges
+	}
+	last := len(edges) - 1
+	edges[i] = edges[last]
+	return edges[:last]
+}
+
+// SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *ArenaDirectedGraph) SetEdge(e graph.Edge) {
+	var (
+		from = e.From()
+		fid  = from.ID()
+		to   = e.To()
+		tid  = to.ID()
+	)
+
+	if fid == tid {
+		panic("simple: adding self edge")
+	}
+
+	if !g.Has(from) {
+		g.AddNode(from)
+	}
+	if !g.Has(to) {
+		g.AddNode(to)
+	}
+
+	fs, ts := g.slot[fid], g.slot[tid]
+	w := e.Weight()
+	if i := find(g.from[fs], ts); i >= 0 {
+		g.from[fs][i].w = w
+		g.to[ts][find(g.to[ts], fs)].w = w
+		return
+	}
+	g.from[fs] = append(g.from[fs], halfEdge{slot: ts, w: w})
+	g.to[ts] = append(g.to[ts], halfEdge{slot: fs, w: w})
+}
+
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If the edge does not exist
+// it is a no-op.
+func (g *ArenaDirectedGraph) RemoveEdge(e graph.Edge) {
+	fs, ok := g.slot[e.From().ID()]
+	if !ok {
+		return
+	}
+	ts, ok := g.slot[e.To().ID()]
+	if !ok {
+		return
+	}
+	g.from[fs] = removeHalfEdge(g.from[fs], ts)
+	g.to[ts] = removeHalfEdge(g.to[ts], fs)
+}
+
+// Node returns the node in the graph with the given ID.
+func (g *ArenaDirectedGraph) Node(id int) graph.Node {
+	s, ok := g.slot[id]
+	if !ok {
+		return nil
+	}
+	return g.nodes[s]
+}
+
+// Has returns whether the node exists within the graph.
+func (g *ArenaDirectedGraph) Has(n graph.Node) bool {
+	_, ok := g.slot[n.ID()]
+	return ok
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *ArenaDirectedGraph) Nodes() []graph.Node {
+	nodes := make([]graph.Node, 0, len(g.slot))
+	for _, n := range g.nodes {
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Edges returns all the edges in the graph.
+func (g *ArenaDirectedGraph) Edges() []graph.Edge {
+	var edges []graph.Edge
+	for s, u := range g.nodes {
+		for _, e := range g.from[s] {
+			edges = append(edges, Edge{F: u, T: g.nodes[e.slot], W: e.w})
+		}
+	}
+	return edges
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *ArenaDirectedGraph) From(n graph.Node) []graph.Node {
+	s, ok := g.slot[n.ID()]
+	if !ok {
+		return nil
+	}
+	return g.nodesOf(g.from[s])
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (g *ArenaDirectedGraph) To(n graph.Node) []graph.Node {
+	s, ok := g.slot[n.ID()]
+	if !ok {
+		return nil
+	}
+	return g.nodesOf(g.to[s])
+}
+
+func (g *ArenaDirectedGraph) nodesOf(edges []halfEdge) []graph.Node {
+	nodes := make([]graph.Node, len(edges))
+	for i, e := range edges {
+		nodes[i] = g.nodes[e.slot]
+	}
+	return nodes
+}
+
+// edge returns the out-edges of the node with ID uid and the index of the
+// edge to the node with ID vid within them, or -1 if there is no such edge.
+func (g *ArenaDirectedGraph) edge(uid, vid int) ([]halfEdge, int) {
+	us, ok := g.slot[uid]
+	if !ok {
+		return nil, -1
+	}
+	vs, ok := g.slot[vid]
+	if !ok {
+		return nil, -1
+	}
+	return g.from[us], find(g.from[us], vs)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *ArenaDirectedGraph) HasEdgeBetween(x, y graph.Node) bool {
+	return g.HasEdgeFromTo(x, y) || g.HasEdgeFromTo(y, x)
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *ArenaDirectedGraph) Edge(u, v graph.Node) graph.Edge {
+	edges, i := g.edge(u.ID(), v.ID())
+	if i < 0 {
+		return nil
+	}
+	return Edge{F: g.nodes[g.slot[u.ID()]], T: g.nodes[edges[i].slot], W: edges[i].w}
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *ArenaDirectedGraph) HasEdgeFromTo(u, v graph.Node) bool {
+	_, i := g.edge(u.ID(), v.ID())
+	return i >= 0
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (g *ArenaDirectedGraph) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return g.self, true
+	}
+	edges, i := g.edge(x.ID(), y.ID())
+	if i < 0 {
+		return g.absent, false
+	}
+	return edges[i].w, true
+}
+
+// Degree returns the in+out degree of n in g.
+func (g *ArenaDirectedGraph) Degree(n graph.Node) int {
+	s, ok := g.slot[n.ID()]
+	if !ok {
+		return 0
+	}
+	return len(g.from[s]) + len(g.to[s])
+}
@@ -0,0 +1,129 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+var _ graph.Directed = &ArenaDirectedGraph{}
+var _ graph.DirectedBuilder = &ArenaDirectedGraph{}
+var _ graph.Weighter = &ArenaDirectedGraph{}
+
+// TestArenaDirectedGraphRandom checks that an ArenaDirectedGraph behaves
+// identically to a DirectedGraph under a random sequence of mutations.
+func TestArenaDirectedGraphRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 30
+	want := NewDirectedGraph(0, math.Inf(1))
+	got := NewArenaDirectedGraph(0, math.Inf(1))
+	for i := 0; i < 5000; i++ {
+		u, v := Node(rnd.Intn(n)), Node(rnd.Intn(n))
+		switch op := rnd.Intn(10); {
+		case op < 6:
+			if u == v {
+				continue
+			}
+			e := Edge{F: u, T: v, W: float64(rnd.Intn(5))}
+			want.SetEdge(e)
+			got.SetEdge(e)
+		case op < 8:
+			e := Edge{F: u, T: v}
+			want.RemoveEdge(e)
+			got.RemoveEdge(e)
+		case op < 9:
+			want.RemoveNode(u)
+			got.RemoveNode(u)
+		default:
+			if id := want.NewNodeID(); id != got.NewNodeID() {
+				t.Fatalf("unexpected new node ID at step %d: got:%d want:%d", i, got.NewNodeID(), id)
+			}
+			if !want.Has(u) {
+				want.AddNode(u)
+				got.AddNode(u)
+			}
+		}
+		if i%100 == 0 {
+			compareDirected(t, got, want)
+		}
+	}
+	compareDirected(t, got, want)
+}
+
+func compareDirected(t *testing.T, got *ArenaDirectedGraph, want *DirectedGraph) {
+	if !sameIDs(got.Nodes(), want.Nodes()) {
+		t.Fatalf("unexpected nodes: got:%v want:%v", got.Nodes(), want.Nodes())
+	}
+	if len(got.Edges()) != len(want.Edges()) {
+		t.Fatalf("unexpected number of edges: got:%d want:%d", len(got.Edges()), len(want.Edges()))
+	}
+	for _, u := range want.Nodes() {
+		if !sameIDs(got.From(u), want.From(u)) {
+			t.Fatalf("unexpected From(%d): got:%v want:%v", u.ID(), got.From(u), want.From(u))
+		}
+		if !sameIDs(got.To(u), want.To(u)) {
+			t.Fatalf("unexpected To(%d): got:%v want:%v", u.ID(), got.To(u), want.To(u))
+		}
+		if got.Degree(u) != want.Degree(u) {
+			t.Fatalf("unexpected degree of %d: got:%d want:%d", u.ID(), got.Degree(u), want.Degree(u))
+		}
+		for _, v := range want.Nodes() {
+			if got.HasEdgeFromTo(u, v) != want.HasEdgeFromTo(u, v) {
+				t.Fatalf("unexpected HasEdgeFromTo(%d, %d)", u.ID(), v.ID())
+			}
+			if got.HasEdgeBetween(u, v) != want.HasEdgeBetween(u, v) {
+				t.Fatalf("unexpected HasEdgeBetween(%d, %d)", u.ID(), v.ID())
+			}
+			gw, gok := got.Weight(u, v)
+			ww, wok := want.Weight(u, v)
+			if gw != ww || gok != wok {
+				t.Fatalf("unexpected Weight(%d, %d): got:%v,%t want:%v,%t", u.ID(), v.ID(), gw, gok, ww, wok)
+			}
+			if e := got.Edge(u, v); (e == nil) != (want.Edge(u, v) == nil) ||
+				e != nil && (e.From().ID() != u.ID() || e.To().ID() != v.ID()) {
+				t.Fatalf("unexpected Edge(%d, %d): got:%v", u.ID(), v.ID(), e)
+			}
+		}
+	}
+}
+
+func sameIDs(a, b []graph.Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sort.Sort(ordered.ByID(a))
+	sort.Sort(ordered.ByID(b))
+	for i := range a {
+		if a[i].ID() != b[i].ID() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestArenaDirectedGraphSlotReuse(t *testing.T) {
+	g := NewArenaDirectedGraph(0, math.Inf(1))
+	for i := 0; i < 4; i++ {
+		g.AddNode(Node(i))
+	}
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 1})
+	g.RemoveNode(Node(1))
+	g.AddNode(Node(10))
+	if len(g.nodes) != 4 {
+		t.Errorf("removed slot not reused: got %d slots, want 4", len(g.nodes))
+	}
+	if len(g.From(Node(0))) != 0 || len(g.To(Node(10))) != 0 {
+		t.Error("stale edge in reused slot")
+	}
+	if g.Node(1) != nil || g.Node(10) != Node(10) {
+		t.Error("unexpected node lookup after slot reuse")
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/matrix/mat64"
+)
+
+// BipartiteMatrix represents an undirected bipartite graph using a
+// rectangular biadjacency matrix. Each edge joins a row node to a column
+// node, and is stored implicitly as the weight in the row and column of the
+// matrix, so edges stored in the graph are not recoverable. A graph with r
+// row nodes and c column nodes uses r×c elements rather than the (r+c)²
+// elements of an UndirectedMatrix holding the same graph.
+//
+// A BipartiteMatrix may be converted to a general graph using graph.Copy.
+type BipartiteMatrix struct {
+	mat *mat64.Dense
+
+	rows, cols     []graph.Node
+	rowIdx, colIdx map[int]int
+
+	self   float64
+	absent float64
+}
+
+// NewBipartiteMatrix creates a bipartite dense graph with r row nodes with
+// IDs from 0 to r-1 and c column nodes with IDs from r to r+c-1. All edges
+// are initialized with the weight given by init. The self parameter specifies
+// the cost of self connection, and absent specifies the weight returned for
+// absent edges.
+func NewBipartiteMatrix(r, c int, init, self, absent float64) *BipartiteMatrix {
+	rows := make([]graph.Node, r)
+	for i := range rows {
+		rows[i] = Node(i)
+	}
+	cols := make([]graph.Node, c)
+	for j := range cols {
+		cols[j] = Node(r + j)
+	}
+	return newBipartiteMatrix(rows, cols, init, self, absent)
+}
+
+// NewBipartiteMatrixFrom creates a bipartite dense graph with the given row
+// and column nodes, which are held in ascending order of ID. All edges are
+// initialized with the weight given by init. The self parameter specifies
+// the cost of self connection, and absent specifies the weight returned for
+// absent edges. NewBipartiteMatrixFrom will panic if a node ID is repeated.
+func NewBipartiteMatrixFrom(rows, cols []graph.Node, init, self, absent float64) *BipartiteMatrix {
+	rows = append([]graph.Node(nil), rows...)
+	sort.Sort(ordered.ByID(rows))
+	cols = append([]graph.Node(nil), cols...)
+	sort.Sort(ordered.ByID(cols))
+	return newBipartiteMatrix(rows, cols, init, self, absent)
+}
+
+func newBipartiteMatrix(rows, cols []graph.Node, init, self, absent float64) *BipartiteMatrix {
+	mat := make([]float64, len(rows)*len(cols))
+	if init != 0 {
+		for i := range mat {
+			mat[i] = init
+		}
+	}
+	g := &BipartiteMatrix{
+		rows:   rows,
+		cols:   cols,
+		rowIdx: make(map[int]int, len(rows)),
+		colIdx: make(map[int]int, len(cols)),
+		self:   self,
+		absent: absent,
+	}
+	if len(mat) != 0 {
+		g.mat = mat64.NewDense(len(rows), len(cols), mat)
+	}
+	for i, n := range rows {
+		if _, ok := g.rowIdx[n.ID()]; ok {
+			panic("simple: duplicate node ID")
+		}
+		g.rowIdx[n.ID()] = i
+	}
+	for j, n := range cols {
+		_, inRows := g.rowIdx[n.ID()]
+		_, inCols := g.colIdx[n.ID()]
+		if inRows || inCols {
+			panic("simple: duplicate node ID")
+		}
+		g.colIdx[n.ID()] = j
+	}
+	return g
+}
+
+// BipartiteMatrixFrom returns a BipartiteMatrix holding the edges of g that
+// join a node in rows to a node in cols. The weights of the edges are taken
+// from g. The self parameter specifies the cost of self connection, and
+// absent specifies the weight returned for absent edges. BipartiteMatrixFrom will panic if g has an edge joining two nodes in rows
+// or two nodes in cols.
+func BipartiteMatrixFrom(g graph.Undirected, rows, cols []graph.Node, self, absent float64) *BipartiteMatrix {
+	b := NewBipartiteMatrixFrom(rows, cols, absent, self, absent)
+	for i, u := range b.rows {
+		for _, v := range g.From(u) {
+			if _, ok := b.rowIdx[v.ID()]; ok {
+				panic("simple: edge within bipartite part")
+			}
+			j, ok := b.colIdx[v.ID()]
+			if !ok {
+				continue
+			}
+			b.mat.Set(i, j, g.EdgeBetween(u, v).Weight())
+		}
+	}
+	for _, u := range b.cols {
+		for _, v := range g.From(u) {
+			if _, ok := b.colIdx[v.ID()]; ok {
+				panic("simple: edge within bipartite part")
+			}
+		}
+	}
+	return b
+}
+
+// Node returns the node in the graph with the given ID.
+func (g *BipartiteMatrix) Node(id int) graph.Node {
+	if i, ok := g.rowIdx[id]; ok {
+		return g.rows[i]
+	}
+	if j, ok := g.colIdx[id]; ok {
+		return g.cols[j]
+	}
+	return nil
+}
+
+// Has returns whether the node exists within the graph.
+func (g *BipartiteMatrix) Has(n graph.Node) bool {
+	return g.InRows(n) || g.InColumns(n)
+}
+
+// InRows returns whether n is a row node of the graph.
+func (g *BipartiteMatrix) InRows(n graph.Node) bool {
+	_, ok := g.rowIdx[n.ID()]
+	return ok
+}
+
+// InColumns returns whether n is a column node of the graph.
+func (g *BipartiteMatrix) InColumns(n graph.Node) bool {
+	_, ok := g.colIdx[n.ID()]
+	return ok
+}
+
+// Rows returns the row nodes of the graph in ascending order of ID.
+func (g *BipartiteMatrix) Rows() []graph.Node {
+	return append([]graph.Node(nil), g.rows...)
+}
+
+// Columns returns the column nodes of the graph in ascending order of ID.
+func (g *BipartiteMatrix) Columns() []graph.Node {
+	return append([]graph.Node(nil), g.cols...)
+}
+
+// Nodes returns all the nodes in the graph, the row nodes followed by the
+// column nodes.
+func (g *BipartiteMatrix) Nodes() []graph.Node {
+	nodes := make([]graph.Node, 0, len(g.rows)+len(g.cols))
+	nodes = append(nodes, g.rows...)
+	return append(nodes, g.cols...)
+}
+
+// Edges returns all the edges in the graph. Each edge is from a row node to
+// a column node.
+func (g *BipartiteMatrix) Edges() []graph.Edge {
+	var edges []graph.Edge
+	for i, u := range g.rows {
+		for j, v := range g.cols {
+			if w := g.mat.At(i, j); !isSame(w, g.absent) {
+				edges = append(edges, Edge{F: u, T: v, W: w})
+			}
+		}
+	}
+	return edges
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *BipartiteMatrix) From(n graph.Node) []graph.Node {
+	var neighbors []graph.Node
+	if i, ok := g.rowIdx[n.ID()]; ok {
+		for j, v := range g.cols {
+			if !isSame(g.mat.At(i, j), g.absent) {
+				neighbors = append(neighbors, v)
+			}
+		}
+		return neighbors
+	}
+	if j, ok := g.colIdx[n.ID()]; ok {
+		for i, u := range g.rows {
+			if !isSame(g.mat.At(i, j), g.absent) {
+				neighbors = append(neighbors, u)
+			}
+		}
+	}
+	return neighbors
+}
+
+// index returns the row and column of the matrix element holding the edge
+// between x and y, and whether x and y are in different parts of the graph.
+func (g *BipartiteMatrix) index(x, y graph.Node) (i, j int, ok bool) {
+	if i, ok = g.rowIdx[x.ID()]; ok {
+		j, ok = g.colIdx[y.ID()]
+		return i, j, ok
+	}
+	if i, ok = g.rowIdx[y.ID()]; ok {
+		j, ok = g.colIdx[x.ID()]
+		return i, j, ok
+	}
+	return 0, 0, false
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (g *BipartiteMatrix) HasEdgeBetween(x, y graph.Node) bool {
+	i, j, ok := g.index(x, y)
+	return ok && !isSame(g.mat.At(i, j), g.absent)
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *BipartiteMatrix) Edge(u, v graph.Node) graph.Edge {
+	return g.EdgeBetween(u, v)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (g *BipartiteMatrix) EdgeBetween(x, y graph.Node) graph.Edge {
+	i, j, ok := g.index(x, y)
+	if !ok {
+		return nil
+	}
+	w := g.mat.At(i, j)
+	if isSame(w, g.absent) {
+		return nil
+	}
+	return Edge{F: g.Node(x.ID()), T: g.Node(y.ID()), W: w}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (g *BipartiteMatrix) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return g.self, true
+	}
+	i, j, ok := g.index(x, y)
+	if !ok {
+		return g.absent, false
+	}
+	w = g.mat.At(i, j)
+	return w, !isSame(w, g.absent)
+}
+
+// SetEdge sets e, an edge from one node to another. If the ends of the edge are not
+// a row node and a column node of g, SetEdge panics.
+func (g *BipartiteMatrix) SetEdge(e graph.Edge) {
+	i, j, ok := g.index(e.From(), e.To())
+	if !ok {
+		panic("simple: set illegal edge")
+	}
+	g.mat.Set(i, j, e.Weight())
+}
+
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If the edge does not exist
+// it is a no-op.
+func (g *BipartiteMatrix) RemoveEdge(e graph.Edge) {
+	i, j, ok := g.index(e.From(), e.To())
+	if !ok {
+		return
+	}
+	g.mat.Set(i, j, g.absent)
+}
+
+// Degree returns the degree of n in g.
+func (g *BipartiteMatrix) Degree(n graph.Node) int {
+	return len(g.From(n))
+}
+
+// Matrix returns the mat64.Matrix biadjacency representation of the graph.
+// The rows and columns of the matrix correspond to the nodes returned by
+// Rows and Columns. Matrix returns nil if the graph has no row or no
+// column nodes.
+func (g *BipartiteMatrix) Matrix() mat64.Matrix {
+	if g.mat == nil {
+		return nil
+	}
+	// Prevent alteration of dimensions of the returned matrix.
+	m := *g.mat
+	return &m
+}
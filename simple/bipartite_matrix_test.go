@@ -0,0 +1,123 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+var _ graph.Undirected = (*BipartiteMatrix)(nil)
+
+func TestBipartiteMatrix(t *testing.T) {
+	g := NewBipartiteMatrix(2, 3, math.Inf(1), 0, math.Inf(1))
+	if n := len(g.Nodes()); n != 5 {
+		t.Fatalf("unexpected number of nodes: got:%d want:5", n)
+	}
+	if !g.InRows(Node(1)) || g.InRows(Node(2)) || !g.InColumns(Node(4)) || g.Has(Node(5)) {
+		t.Error("unexpected node parts")
+	}
+
+	g.SetEdge(Edge{F: Node(0), T: Node(2), W: 1})
+	g.SetEdge(Edge{F: Node(4), T: Node(1), W: 2})
+	g.SetEdge(Edge{F: Node(0), T: Node(4), W: 3})
+	for _, e := range []Edge{
+		{F: Node(0), T: Node(1)},
+		{F: Node(2), T: Node(3)},
+		{F: Node(0), T: Node(5)},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic setting edge %d-%d", e.F.ID(), e.T.ID())
+				}
+			}()
+			g.SetEdge(e)
+		}()
+	}
+
+	if !g.HasEdgeBetween(Node(2), Node(0)) || g.HasEdgeBetween(Node(1), Node(2)) {
+		t.Error("unexpected edge existence")
+	}
+	if e := g.EdgeBetween(Node(1), Node(4)); e == nil || e.Weight() != 2 || e.From().ID() != 1 {
+		t.Errorf("unexpected edge: got:%v", e)
+	}
+	if w, ok := g.Weight(Node(4), Node(0)); w != 3 || !ok {
+		t.Errorf("unexpected weight: got:%v,%t want:3,true", w, ok)
+	}
+	if w, ok := g.Weight(Node(0), Node(1)); !math.IsInf(w, 1) || ok {
+		t.Errorf("unexpected weight between rows: got:%v,%t", w, ok)
+	}
+	if d := g.Degree(Node(4)); d != 2 {
+		t.Errorf("unexpected degree: got:%d want:2", d)
+	}
+	if r, c := g.Matrix().Dims(); r != 2 || c != 3 {
+		t.Errorf("unexpected matrix dimensions: got:%d×%d want:2×3", r, c)
+	}
+	if len(g.Edges()) != 3 {
+		t.Errorf("unexpected number of edges: got:%d want:3", len(g.Edges()))
+	}
+	g.RemoveEdge(Edge{F: Node(4), T: Node(0)})
+	if g.HasEdgeBetween(Node(0), Node(4)) {
+		t.Error("edge not removed")
+	}
+}
+
+func TestBipartiteMatrixConversion(t *testing.T) {
+	u := NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range []Edge{
+		{F: Node(10), T: Node(1), W: 1},
+		{F: Node(10), T: Node(2), W: 2},
+		{F: Node(30), T: Node(2), W: 3},
+		{F: Node(20), T: Node(3), W: 4},
+	} {
+		u.SetEdge(e)
+	}
+	rows := []graph.Node{Node(30), Node(10), Node(20)}
+	cols := []graph.Node{Node(3), Node(2), Node(1)}
+	b := BipartiteMatrixFrom(u, rows, cols, 0, math.Inf(1))
+	if got, want := ids(b.Rows()), []int{10, 20, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected rows: got:%v want:%v", got, want)
+	}
+	if got, want := b.Matrix().At(2, 1), 3.0; got != want {
+		t.Errorf("unexpected matrix element for 30-2: got:%v want:%v", got, want)
+	}
+
+	back := NewUndirectedGraph(0, math.Inf(1))
+	graph.Copy(back, b)
+	if len(back.Edges()) != len(u.Edges()) {
+		t.Errorf("unexpected number of edges after round trip: got:%d want:%d", len(back.Edges()), len(u.Edges()))
+	}
+	for _, e := range u.Edges() {
+		got := back.EdgeBetween(e.From(), e.To())
+		if got == nil || got.Weight() != e.Weight() {
+			t.Errorf("unexpected edge %d-%d after round trip: got:%v want:%v", e.From().ID(), e.To().ID(), got, e)
+		}
+	}
+
+	u.SetEdge(Edge{F: Node(1), T: Node(2), W: 1})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for edge within part")
+			}
+		}()
+		BipartiteMatrixFrom(u, rows, cols, 0, math.Inf(1))
+	}()
+}
+
+func ids(nodes []graph.Node) []int {
+	sort.Sort(ordered.ByID(nodes))
+	ids := make([]int, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
@@ -0,0 +1,142 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"github.com/gonum/graph"
+	"github.com/gonum/matrix/mat64"
+)
+
+// Clone returns a copy of g. The structure of the copy is independent of g,
+// but the node and edge values are shared, so any attributes they hold are
+// retained.
+func (g *DirectedGraph) Clone() *DirectedGraph {
+	c := &DirectedGraph{
+		nodes: make(map[int]graph.Node, len(g.nodes)),
+		from:  cloneEdgeMaps(g.from),
+		to:    cloneEdgeMaps(g.to),
+
+		self:         g.self,
+		absent:       g.absent,
+		edgesPerNode: g.edgesPerNode,
+
+		ids:     g.ids.Clone(),
+		deleted: g.deleted.clone(),
+	}
+	for id, n := range g.nodes {
+		c.nodes[id] = n
+	}
+	return c
+}
+
+// Clone returns a copy of g. The structure of the copy is independent of g,
+// but the node and edge values are shared, so any attributes they hold are
+// retained.
+func (g *UndirectedGraph) Clone() *UndirectedGraph {
+	c := &UndirectedGraph{
+		nodes: make(map[int]graph.Node, len(g.nodes)),
+		edges: cloneEdgeMaps(g.edges),
+
+		self:         g.self,
+		absent:       g.absent,
+		edgesPerNode: g.edgesPerNode,
+
+		ids:     g.ids.Clone(),
+		deleted: g.deleted.clone(),
+	}
+	for id, n := range g.nodes {
+		c.nodes[id] = n
+	}
+	return c
+}
+
+func cloneEdgeMaps(m map[int]map[int]graph.Edge) map[int]map[int]graph.Edge {
+	c := make(map[int]map[int]graph.Edge, len(m))
+	for id, edges := range m {
+		ce := make(map[int]graph.Edge, len(edges))
+		for to, e := range edges {
+			ce[to] = e
+		}
+		c[id] = ce
+	}
+	return c
+}
+
+// Clone returns a copy of g. The structure of the copy is independent of g,
+// but the node values are shared, so any attributes they hold are retained.
+func (g *ArenaDirectedGraph) Clone() *ArenaDirectedGraph {
+	c := &ArenaDirectedGraph{
+		slot:  make(map[int]int, len(g.slot)),
+		nodes: append([]graph.Node(nil), g.nodes...),
+		from:  cloneHalfEdges(g.from),
+		to:    cloneHalfEdges(g.to),
+		free:  append([]int(nil), g.free...),
+
+		self:   g.self,
+		absent: g.absent,
+
+		ids: g.ids.Clone(),
+	}
+	for id, s := range g.slot {
+		c.slot[id] = s
+	}
+	return c
+}
+
+func cloneHalfEdges(edges [][]halfEdge) [][]halfEdge {
+	c := make([][]halfEdge, len(edges))
+	for i, e := range edges {
+		if e != nil {
+			c[i] = append([]halfEdge(nil), e...)
+		}
+	}
+	return c
+}
+
+// Clone returns a copy of g. The adjacency matrix of the copy is independent
+// of g, but the node values are shared, so any attributes they hold are
+// retained.
+func (g *DirectedMatrix) Clone() *DirectedMatrix {
+	r, c := g.mat.Dims()
+	mat := mat64.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			mat.Set(i, j, g.mat.At(i, j))
+		}
+	}
+	var nodes []graph.Node
+	if g.nodes != nil {
+		nodes = append(nodes, g.nodes...)
+	}
+	return &DirectedMatrix{
+		mat:    mat,
+		nodes:  nodes,
+		self:   g.self,
+		absent: g.absent,
+	}
+}
+
+// Clone returns a copy of g. The adjacency matrix of the copy is independent
+// of g, but the node values are shared, so any attributes they hold are
+// retained.
+func (g *UndirectedMatrix) Clone() *UndirectedMatrix {
+	n := g.mat.Symmetric()
+	mat := mat64.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			mat.SetSym(i, j, g.mat.At(i, j))
+		}
+	}
+	var nodes []graph.Node
+	if g.nodes != nil {
+		nodes = append(nodes, g.nodes...)
+	}
+	return &UndirectedMatrix{
+		mat:    mat,
+		nodes:  nodes,
+		self:   g.self,
+		absent: g.absent,
+	}
+}
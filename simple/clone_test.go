@@ -0,0 +1,138 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+)
+
+// attrNode is a node with an attribute to check that
+// Clone retains node values.
+type attrNode struct {
+	Node
+	label string
+}
+
+func TestDirectedGraphClone(t *testing.T) {
+	g := NewDirectedGraph(0, math.Inf(1))
+	g.AddNode(attrNode{Node: 0, label: "zero"})
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 2})
+	g.SetEdge(Edge{F: Node(1), T: Node(2), W: 3})
+	g.RemoveNode(Node(2))
+
+	c := g.Clone()
+	checkClone(t, "DirectedGraph", g, c)
+	if id := c.NewNodeID(); id != g.NewNodeID() {
+		t.Errorf("unexpected new node ID for clone: got:%d want:%d", id, g.NewNodeID())
+	}
+
+	c.RemoveEdge(Edge{F: Node(0), T: Node(1)})
+	c.AddNode(Node(5))
+	if !g.HasEdgeFromTo(Node(0), Node(1)) {
+		t.Errorf("removing edge from clone altered original")
+	}
+	if g.Has(Node(5)) {
+		t.Errorf("adding node to clone altered original")
+	}
+}
+
+func TestUndirectedGraphClone(t *testing.T) {
+	g := NewUndirectedGraph(0, math.Inf(1))
+	g.AddNode(attrNode{Node: 0, label: "zero"})
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 2})
+	g.SetEdge(Edge{F: Node(1), T: Node(2), W: 3})
+
+	c := g.Clone()
+	checkClone(t, "UndirectedGraph", g, c)
+
+	c.RemoveEdge(Edge{F: Node(1), T: Node(0)})
+	if !g.HasEdgeBetween(Node(0), Node(1)) {
+		t.Errorf("removing edge from clone altered original")
+	}
+}
+
+func TestArenaDirectedGraphClone(t *testing.T) {
+	g := NewArenaDirectedGraph(0, math.Inf(1))
+	g.AddNode(attrNode{Node: 0, label: "zero"})
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 2})
+	g.SetEdge(Edge{F: Node(1), T: Node(2), W: 3})
+	g.RemoveNode(Node(2))
+
+	c := g.Clone()
+	checkClone(t, "ArenaDirectedGraph", g, c)
+
+	c.SetEdge(Edge{F: Node(0), T: Node(1), W: 7})
+	c.AddNode(Node(6))
+	if w := g.Edge(Node(0), Node(1)).Weight(); w != 2 {
+		t.Errorf("setting edge in clone altered original weight: got:%v want:2", w)
+	}
+	if g.Has(Node(6)) {
+		t.Errorf("adding node to clone altered original")
+	}
+}
+
+func TestDirectedMatrixClone(t *testing.T) {
+	g := NewDirectedMatrix(3, 0, 0, math.Inf(1))
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 2})
+	g.SetEdge(Edge{F: Node(1), T: Node(2), W: 3})
+
+	c := g.Clone()
+	checkClone(t, "DirectedMatrix", g, c)
+
+	c.SetEdge(Edge{F: Node(0), T: Node(1), W: 7})
+	if w := g.Edge(Node(0), Node(1)).Weight(); w != 2 {
+		t.Errorf("setting edge in clone altered original weight: got:%v want:2", w)
+	}
+}
+
+func TestUndirectedMatrixClone(t *testing.T) {
+	g := NewUndirectedMatrix(3, 0, 0, math.Inf(1))
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 2})
+	g.SetEdge(Edge{F: Node(1), T: Node(2), W: 3})
+
+	c := g.Clone()
+	checkClone(t, "UndirectedMatrix", g, c)
+
+	c.RemoveEdge(Edge{F: Node(0), T: Node(1)})
+	if !g.HasEdgeBetween(Node(0), Node(1)) {
+		t.Errorf("removing edge from clone altered original")
+	}
+}
+
+// checkClone checks that c holds the same nodes and edges as g.
+func checkClone(t *testing.T, name string, g, c graph.Graph) {
+	if len(c.Nodes()) != len(g.Nodes()) {
+		t.Errorf("unexpected number of nodes in %s clone: got:%d want:%d", name, len(c.Nodes()), len(g.Nodes()))
+	}
+	for _, n := range g.Nodes() {
+		if !c.Has(n) {
+			t.Errorf("missing node %d in %s clone", n.ID(), name)
+		}
+		if a, ok := n.(attrNode); ok {
+			var found bool
+			for _, cn := range c.Nodes() {
+				if cn == graph.Node(a) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("node attributes not retained in %s clone", name)
+			}
+		}
+		for _, v := range g.From(n) {
+			ge, ce := g.Edge(n, v), c.Edge(n, v)
+			if ce == nil {
+				t.Errorf("missing edge %d->%d in %s clone", n.ID(), v.ID(), name)
+				continue
+			}
+			if ce.Weight() != ge.Weight() {
+				t.Errorf("unexpected weight for edge %d->%d in %s clone: got:%v want:%v", n.ID(), v.ID(), name, ce.Weight(), ge.Weight())
+			}
+		}
+	}
+}
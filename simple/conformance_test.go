@@ -0,0 +1,30 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+	graphtesting "github.com/gonum/graph/testing"
+)
+
+func TestDirectedGraphConformance(t *testing.T) {
+	graphtesting.RunGraphSuite(t, func() graph.Builder { return simple.NewDirectedGraph(0, math.Inf(1)) })
+}
+
+func TestUndirectedGraphConformance(t *testing.T) {
+	graphtesting.RunGraphSuite(t, func() graph.Builder { return simple.NewUndirectedGraph(0, math.Inf(1)) })
+}
+
+func TestArenaDirectedGraphConformance(t *testing.T) {
+	graphtesting.RunGraphSuite(t, func() graph.Builder { return simple.NewArenaDirectedGraph(0, math.Inf(1)) })
+}
+
+func TestMixedGraphConformance(t *testing.T) {
+	graphtesting.RunGraphSuite(t, func() graph.Builder { return simple.NewMixedGraph(0, math.Inf(1)) })
+}
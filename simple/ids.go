@@ -0,0 +1,248 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"golang.org/x/tools/container/intsets"
+)
+
+// IDAllocator allocates the IDs returned by the NewNodeID methods of
+// graphs. Graphs call Use when a node is added and Release when a node is
+// removed.
+type IDAllocator interface {
+	// NewID returns an ID that is not in use.
+	// The ID is not in use until Use is called
+	// with it.
+	NewID() int
+
+	// Use marks id as in use.
+	Use(id int)
+
+	// Release marks id as no longer in use.
+	Release(id int)
+
+	// Clone returns an independent copy of
+	// the allocator.
+	Clone() IDAllocator
+}
+
+// ReuseIDs is an IDAllocator that reuses the IDs of removed nodes, returning
+// the lowest released ID if there is one and otherwise one more than the
+// highest ID in use. It is the allocator of graphs without an allocator set
+// by SetIDAllocator. The zero value is ready to use.
+type ReuseIDs struct {
+	free intsets.Sparse
+	used intsets.Sparse
+}
+
+// NewID satisfies the IDAllocator interface.
+func (a *ReuseIDs) NewID() int {
+	if a.used.Len() == 0 {
+		return 0
+	}
+	if a.used.Len() == maxInt {
+		panic(fmt.Sprintf("simple: cannot allocate node: no slot"))
+	}
+
+	var id int
+	if a.free.Len() != 0 && a.free.TakeMin(&id) {
+		return id
+	}
+	if id = a.used.Max(); id < maxInt {
+		return id + 1
+	}
+	for id = 0; id < maxInt; id++ {
+		if !a.used.Has(id) {
+			return id
+		}
+	}
+	panic("unreachable")
+}
+
+// Use satisfies the IDAllocator interface.
+func (a *ReuseIDs) Use(id int) {
+	a.free.Remove(id)
+	a.used.Insert(id)
+}
+
+// Release satisfies the IDAllocator interface.
+func (a *ReuseIDs) Release(id int) {
+	a.free.Insert(id)
+	a.used.Remove(id)
+}
+
+// Clone satisfies the IDAllocator interface.
+func (a *ReuseIDs) Clone() IDAllocator {
+	var c ReuseIDs
+	c.free.Copy(&a.free)
+	c.used.Copy(&a.used)
+	return &c
+}
+
+// SequentialIDs is an IDAllocator that never reuses IDs, returning one more
+// than the highest ID ever used. References to removed nodes held outside a
+// graph can not then refer to nodes added later. The zero value is ready to
+// use.
+type SequentialIDs struct {
+	next      int
+	exhausted bool
+}
+
+// NewID satisfies the IDAllocator interface. It panics if the highest
+// possible ID has been used.
+func (a *SequentialIDs) NewID() int {
+	if a.exhausted {
+		panic("simple: cannot allocate node: IDs exhausted")
+	}
+	return a.next
+}
+
+// Use satisfies the IDAllocator interface.
+func (a *SequentialIDs) Use(id int) {
+	if id < a.next || a.exhausted {
+		return
+	}
+	if id == maxInt {
+		a.exhausted = true
+		return
+	}
+	a.next = id + 1
+}
+
+// Release satisfies the IDAllocator interface.
+func (a *SequentialIDs) Release(int) {}
+
+// Clone satisfies the IDAllocator interface.
+func (a *SequentialIDs) Clone() IDAllocator {
+	c := *a
+	return &c
+}
+
+// RandomIDs is an IDAllocator that returns random non-negative IDs using
+// all the bits of int, 63 bits on 64-bit platforms. IDs in use are not
+// returned, and released IDs are only returned again by chance.
+type RandomIDs struct {
+	rnd  func() int64
+	used map[int]struct{}
+}
+
+// NewRandomIDs returns a RandomIDs using the random source src. If src is
+// nil, the global source of math/rand is used.
+func NewRandomIDs(src *rand.Rand) *RandomIDs {
+	rnd := rand.Int63
+	if src != nil {
+		rnd = src.Int63
+	}
+	return &RandomIDs{rnd: rnd, used: make(map[int]struct{})}
+}
+
+// NewID satisfies the IDAllocator interface.
+func (a *RandomIDs) NewID() int {
+	if len(a.used) == maxInt {
+		panic(fmt.Sprintf("simple: cannot allocate node: no slot"))
+	}
+	for {
+		id := int(a.rnd() & int64(maxInt))
+		if _, exists := a.used[id]; !exists {
+			return id
+		}
+	}
+}
+
+// Use satisfies the IDAllocator interface.
+func (a *RandomIDs) Use(id int) { a.used[id] = struct{}{} }
+
+// Release satisfies the IDAllocator interface.
+func (a *RandomIDs) Release(id int) { delete(a.used, id) }
+
+// Clone satisfies the IDAllocator interface. The clone shares the random
+// source of a.
+func (a *RandomIDs) Clone() IDAllocator {
+	c := &RandomIDs{rnd: a.rnd, used: make(map[int]struct{}, len(a.used))}
+	for id := range a.used {
+		c.used[id] = struct{}{}
+	}
+	return c
+}
+
+const (
+	timeWorkerBits   = 10
+	timeSequenceBits = 12
+	timeShift        = timeWorkerBits + timeSequenceBits
+	timeMaxWorker    = 1<<timeWorkerBits - 1
+	timeMaxSequence  = 1<<timeSequenceBits - 1
+)
+
+// TimeIDs is an IDAllocator that returns time-ordered IDs in the style of
+// Twitter's Snowflake. Each ID holds, from the most significant bit, the
+// number of milliseconds since an epoch in 41 bits, a worker number in 10
+// bits and a sequence number in 12 bits, so IDs allocated by one TimeIDs
+// increase with each call to NewID and IDs allocated by TimeIDs with
+// distinct worker numbers never collide.
+//
+// If more than 4096 IDs are allocated in a millisecond or the clock moves
+// backwards, the millisecond count is advanced ahead of the clock so that IDs
+// continue to increase. IDs are never reused. TimeIDs does not check that its
+// IDs are not in use by nodes added with IDs from another source.
+type TimeIDs struct {
+	worker int64
+	epoch  time.Time
+	now    func() time.Time
+
+	last int64 // last is the last millisecond count used.
+	seq  int64
+}
+
+// NewTimeIDs returns a TimeIDs for the given worker number counting time from
+// epoch. It panics if worker is outside [0, 1023], if epoch is in the future
+// or if int is narrower than 64 bits.
+func NewTimeIDs(worker int, epoch time.Time) *TimeIDs {
+	if maxInt>>timeShift>>31 == 0 {
+		panic("simple: time ordered IDs require 64-bit int")
+	}
+	if worker < 0 || timeMaxWorker < worker {
+		panic("simple: worker number out of range")
+	}
+	if epoch.After(time.Now()) {
+		panic("simple: epoch in the future")
+	}
+	return &TimeIDs{worker: int64(worker), epoch: epoch, now: time.Now, last: -1}
+}
+
+// NewID satisfies the IDAllocator interface. Each call returns a new ID.
+func (a *TimeIDs) NewID() int {
+	ms := int64(a.now().Sub(a.epoch) / time.Millisecond)
+	if ms > a.last {
+		a.last = ms
+		a.seq = 0
+	} else {
+		a.seq++
+		if a.seq > timeMaxSequence {
+			a.last++
+			a.seq = 0
+		}
+	}
+	if a.last>>(63-timeShift) != 0 {
+		panic("simple: cannot allocate node: time exhausted")
+	}
+	return int(a.last<<timeShift | a.worker<<timeSequenceBits | a.seq)
+}
+
+// Use satisfies the IDAllocator interface.
+func (a *TimeIDs) Use(int) {}
+
+// Release satisfies the IDAllocator interface.
+func (a *TimeIDs) Release(int) {}
+
+// Clone satisfies the IDAllocator interface. The clone has the worker number
+// of a, so IDs allocated by a and its clone may collide.
+func (a *TimeIDs) Clone() IDAllocator {
+	c := *a
+	return &c
+}
@@ -0,0 +1,143 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestReuseIDs(t *testing.T) {
+	g := NewUndirectedGraph(0, 0)
+	for i := 0; i < 4; i++ {
+		g.AddNode(Node(g.NewNodeID()))
+	}
+	g.RemoveNode(Node(1))
+	if id := g.NewNodeID(); id != 1 {
+		t.Errorf("unexpected new node ID: got:%d want:1", id)
+	}
+}
+
+func TestSequentialIDs(t *testing.T) {
+	g := NewDirectedGraph(0, 0)
+	g.AddNode(Node(5))
+	g.SetIDAllocator(&SequentialIDs{})
+	if id := g.NewNodeID(); id != 6 {
+		t.Errorf("unexpected new node ID after existing nodes: got:%d want:6", id)
+	}
+	for i := 0; i < 3; i++ {
+		g.AddNode(Node(g.NewNodeID()))
+	}
+	g.RemoveNode(Node(8))
+	g.RemoveNode(Node(2))
+	if id := g.NewNodeID(); id != 9 {
+		t.Errorf("unexpected new node ID after removal: got:%d want:9", id)
+	}
+
+	c := g.Clone()
+	c.AddNode(Node(c.NewNodeID()))
+	if id := g.NewNodeID(); id != 9 {
+		t.Errorf("clone allocation changed original: got:%d want:9", id)
+	}
+	if id := c.NewNodeID(); id != 10 {
+		t.Errorf("unexpected new node ID for clone: got:%d want:10", id)
+	}
+
+	var a SequentialIDs
+	a.Use(maxInt)
+	panicked := func() (p bool) {
+		defer func() { p = recover() != nil }()
+		a.NewID()
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for exhausted IDs")
+	}
+}
+
+func TestRandomIDs(t *testing.T) {
+	g := NewArenaDirectedGraph(0, 0)
+	g.SetIDAllocator(NewRandomIDs(rand.New(rand.NewSource(1))))
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		id := g.NewNodeID()
+		if id < 0 {
+			t.Fatalf("unexpected negative ID: %d", id)
+		}
+		if seen[id] {
+			t.Fatalf("unexpected repeated ID: %d", id)
+		}
+		seen[id] = true
+		g.AddNode(Node(id))
+	}
+
+	// An allocator with a source that repeats
+	// must skip IDs that are in use.
+	vals := []int64{3, 3, 3, 7}
+	a := &RandomIDs{
+		rnd: func() int64 {
+			v := vals[0]
+			vals = vals[1:]
+			return v
+		},
+		used: make(map[int]struct{}),
+	}
+	a.Use(a.NewID())
+	if id := a.NewID(); id != 7 {
+		t.Errorf("unexpected new ID: got:%d want:7", id)
+	}
+}
+
+func TestTimeIDs(t *testing.T) {
+	if maxInt>>62 == 0 {
+		t.Skip("time ordered IDs require 64-bit int")
+	}
+	epoch := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(time.Second)
+	a := NewTimeIDs(3, epoch)
+	a.now = func() time.Time { return now }
+
+	first := a.NewID()
+	if want := 1000<<timeShift | 3<<timeSequenceBits; first != want {
+		t.Errorf("unexpected first ID: got:%#x want:%#x", first, want)
+	}
+
+	// Exhaust the sequence numbers of the millisecond
+	// and then move the clock backwards.
+	last := first
+	for i := 0; i < 2*timeMaxSequence; i++ {
+		if i == timeMaxSequence {
+			now = now.Add(-time.Minute)
+		}
+		id := a.NewID()
+		if id <= last {
+			t.Fatalf("IDs not increasing at step %d: %#x after %#x", i, id, last)
+		}
+		if w := id >> timeSequenceBits & timeMaxWorker; w != 3 {
+			t.Fatalf("unexpected worker number at step %d: got:%d want:3", i, w)
+		}
+		last = id
+	}
+	if ms := last >> timeShift; ms != 1001 {
+		t.Errorf("unexpected millisecond count: got:%d want:1001", ms)
+	}
+
+	now = epoch.Add(time.Hour)
+	if ms := a.NewID() >> timeShift; ms != 3600000 {
+		t.Errorf("unexpected millisecond count after clock advance: got:%d want:3600000", ms)
+	}
+
+	for _, w := range []int{-1, timeMaxWorker + 1} {
+		panicked := func() (p bool) {
+			defer func() { p = recover() != nil }()
+			NewTimeIDs(w, epoch)
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic for worker %d", w)
+		}
+	}
+}
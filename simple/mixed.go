@@ -0,0 +1,335 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+
+	"github.com/gonum/graph"
+)
+
+// MixedGraph implements a generalized mixed graph holding both directed
+// and undirected edges. A pair of nodes may be joined either by an
+// undirected edge or by directed edges in one or both directions.
+type MixedGraph struct {
+	nodes map[int]graph.Node
+	from  map[int]map[int]graph.Edge
+	to    map[int]map[int]graph.Edge
+
+	// edges holds the undirected
+	// edges keyed in both orders.
+	edges map[int]map[int]graph.Edge
+
+	self, absent float64
+
+	ids IDAllocator
+}
+
+// NewMixedGraph returns a MixedGraph with the specified self and absent
+// edge weight values.
+func NewMixedGraph(self, absent float64) *MixedGraph {
+	return &MixedGraph{
+		nodes: make(map[int]graph.Node),
+		from:  make(map[int]map[int]graph.Edge),
+		to:    make(map[int]map[int]graph.Edge),
+		edges: make(map[int]map[int]graph.Edge),
+
+		self:   self,
+		absent: absent,
+
+		ids: &ReuseIDs{},
+	}
+}
+
+// NewNodeID returns a new unique ID for a node to be added to g. The returned ID does
+// not become a valid ID in g until it is added to g. IDs are allocated by the
+// IDAllocator of g, which is a ReuseIDs unless set by SetIDAllocator.
+func (g *MixedGraph) NewNodeID() int {
+	return g.ids.NewID()
+}
+
+// SetIDAllocator sets the allocator of the IDs returned by NewNodeID to a,
+// marking the IDs of the nodes in g as in use by a.
+func (g *MixedGraph) SetIDAllocator(a IDAllocator) {
+	for id := range g.nodes {
+		a.Use(id)
+	}
+	g.ids = a
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *MixedGraph) AddNode(n graph.Node) {
+	if _, exists := g.nodes[n.ID()]; exists {
+		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
+	}
+	g.nodes[n.ID()] = n
+	g.from[n.ID()] = make(map[int]graph.Edge)
+	g.to[n.ID()] = make(map[int]graph.Edge)
+	g.edges[n.ID()] = make(map[int]graph.Edge)
+
+	g.ids.Use(n.ID())
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it. If the node
+// is not in the graph it is a no-op.
+func (g *MixedGraph) RemoveNode(n graph.Node) {
+	if _, ok := g.nodes[n.ID()]; !ok {
+		return
+	}
+	delete(g.nodes, n.ID())
+
+	for from := range g.from[n.ID()] {
+		delete(g.to[from], n.ID())
+	}
+	delete(g.from, n.ID())
+
+	for to := range g.to[n.ID()] {
+		delete(g.from[to], n.ID())
+	}
+	delete(g.to, n.ID())
+
+	for neigh := range g.edges[n.ID()] {
+		delete(g.edges[neigh], n.ID())
+	}
+	delete(g.edges, n.ID())
+
+	g.ids.Release(n.ID())
+}
+
+// SetEdge adds e, a directed edge from one node to another. If the nodes do not exist,
+// they are added. Any undirected edge between the nodes is removed. It will panic if the
+// IDs of the e.From and e.To are equal.
+func (g *MixedGraph) SetEdge(e graph.Edge) {
+	fid, tid := g.addTerminals(e)
+
+	delete(g.edges[fid], tid)
+	delete(g.edges[tid], fid)
+
+	g.from[fid][tid] = e
+	g.to[tid][fid] = e
+}
+
+// SetUndirectedEdge adds e, an undirected edge between two nodes. If the nodes do not
+// exist, they are added. Any directed edges between the nodes are removed. It will panic
+// if the IDs of the e.From and e.To are equal.
+func (g *MixedGraph) SetUndirectedEdge(e graph.Edge) {
+	fid, tid := g.addTerminals(e)
+
+	delete(g.from[fid], tid)
+	delete(g.to[tid], fid)
+	delete(g.from[tid], fid)
+	delete(g.to[fid], tid)
+
+	g.edges[fid][tid] = e
+	g.edges[tid][fid] = e
+}
+
+// addTerminals adds the terminal nodes of e to g if they do not exist, and
+// returns their IDs.
+func (g *MixedGraph) addTerminals(e graph.Edge) (fid, tid int) {
+	var (
+		from = e.From()
+		to   = e.To()
+	)
+	fid, tid = from.ID(), to.ID()
+
+	if fid == tid {
+		panic("simple: adding self edge")
+	}
+
+	if !g.Has(from) {
+		g.AddNode(from)
+	}
+	if !g.Has(to) {
+		g.AddNode(to)
+	}
+	return fid, tid
+}
+
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If e is the
+// directed edge from e.From to e.To it is removed, otherwise any undirected edge
+// between the nodes is removed. If the edge does not exist it is a no-op.
+func (g *MixedGraph) RemoveEdge(e graph.Edge) {
+	from, to := e.From(), e.To()
+	if _, ok := g.nodes[from.ID()]; !ok {
+		return
+	}
+	if _, ok := g.nodes[to.ID()]; !ok {
+		return
+	}
+
+	if _, ok := g.from[from.ID()][to.ID()]; ok {
+		delete(g.from[from.ID()], to.ID())
+		delete(g.to[to.ID()], from.ID())
+		return
+	}
+	delete(g.edges[from.ID()], to.ID())
+	delete(g.edges[to.ID()], from.ID())
+}
+
+// Node returns the node in the graph with the given ID.
+func (g *MixedGraph) Node(id int) graph.Node {
+	return g.nodes[id]
+}
+
+// Has returns whether the node exists within the graph.
+func (g *MixedGraph) Has(n graph.Node) bool {
+	_, ok := g.nodes[n.ID()]
+
+	return ok
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *MixedGraph) Nodes() []graph.Node {
+	nodes := make([]graph.Node, len(g.nodes))
+	i := 0
+	for _, n := range g.nodes {
+		nodes[i] = n
+		i++
+	}
+
+	return nodes
+}
+
+// Edges returns all the edges in the graph. Each undirected edge is returned once.
+func (g *MixedGraph) Edges() []graph.Edge {
+	var edges []graph.Edge
+	for uid := range g.nodes {
+		for _, e := range g.from[uid] {
+			edges = append(edges, e)
+		}
+		for vid, e := range g.edges[uid] {
+			if uid < vid {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// DirectedEdges returns all the directed edges in the graph.
+func (g *MixedGraph) DirectedEdges() []graph.Edge {
+	var edges []graph.Edge
+	for uid := range g.nodes {
+		for _, e := range g.from[uid] {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// UndirectedEdges returns all the undirected edges in the graph.
+func (g *MixedGraph) UndirectedEdges() []graph.Edge {
+	var edges []graph.Edge
+	for uid := range g.nodes {
+		for vid, e := range g.edges[uid] {
+			if uid < vid {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// From returns all nodes in g that can be reached directly from n, either by
+// a directed edge from n or by an undirected edge.
+func (g *MixedGraph) From(n graph.Node) []graph.Node {
+	if _, ok := g.nodes[n.ID()]; !ok {
+		return nil
+	}
+
+	from := make([]graph.Node, 0, len(g.from[n.ID()])+len(g.edges[n.ID()]))
+	for id := range g.from[n.ID()] {
+		from = append(from, g.nodes[id])
+	}
+	for id := range g.edges[n.ID()] {
+		from = append(from, g.nodes[id])
+	}
+
+	return from
+}
+
+// To returns all nodes in g that can reach directly to n, either by a directed
+// edge to n or by an undirected edge.
+func (g *MixedGraph) To(n graph.Node) []graph.Node {
+	if _, ok := g.nodes[n.ID()]; !ok {
+		return nil
+	}
+
+	to := make([]graph.Node, 0, len(g.to[n.ID()])+len(g.edges[n.ID()]))
+	for id := range g.to[n.ID()] {
+		to = append(to, g.nodes[id])
+	}
+	for id := range g.edges[n.ID()] {
+		to = append(to, g.nodes[id])
+	}
+
+	return to
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *MixedGraph) HasEdgeBetween(x, y graph.Node) bool {
+	return g.Edge(x, y) != nil || g.Edge(y, x) != nil
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *MixedGraph) Edge(u, v graph.Node) graph.Edge {
+	if _, ok := g.nodes[u.ID()]; !ok {
+		return nil
+	}
+	if _, ok := g.nodes[v.ID()]; !ok {
+		return nil
+	}
+	if e, ok := g.from[u.ID()][v.ID()]; ok {
+		return e
+	}
+	if e, ok := g.edges[u.ID()][v.ID()]; ok {
+		return e
+	}
+	return nil
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v. An
+// undirected edge between u and v is an edge from u to v.
+func (g *MixedGraph) HasEdgeFromTo(u, v graph.Node) bool {
+	return g.Edge(u, v) != nil
+}
+
+// IsUndirected returns whether the edge from u to v is an undirected edge.
+func (g *MixedGraph) IsUndirected(u, v graph.Node) bool {
+	if _, ok := g.nodes[u.ID()]; !ok {
+		return false
+	}
+	_, ok := g.edges[u.ID()][v.ID()]
+	return ok
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (g *MixedGraph) Weight(x, y graph.Node) (w float64, ok bool) {
+	xid := x.ID()
+	yid := y.ID()
+	if xid == yid {
+		return g.self, true
+	}
+	if e := g.Edge(x, y); e != nil {
+		return e.Weight(), true
+	}
+	return g.absent, false
+}
+
+// Degree returns the number of edges incident to n in g, counting each
+// undirected edge once.
+func (g *MixedGraph) Degree(n graph.Node) int {
+	if _, ok := g.nodes[n.ID()]; !ok {
+		return 0
+	}
+
+	return len(g.from[n.ID()]) + len(g.to[n.ID()]) + len(g.edges[n.ID()])
+}
@@ -0,0 +1,92 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+)
+
+var _ graph.Mixed = &MixedGraph{}
+var _ graph.MixedBuilder = &MixedGraph{}
+var _ graph.Weighter = &MixedGraph{}
+
+func TestMixedGraph(t *testing.T) {
+	g := NewMixedGraph(0, math.Inf(1))
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 1})
+	g.SetUndirectedEdge(Edge{F: Node(1), T: Node(2), W: 2})
+
+	for _, test := range []struct {
+		u, v       int
+		from       bool
+		undirected bool
+	}{
+		{u: 0, v: 1, from: true},
+		{u: 1, v: 0, from: false},
+		{u: 1, v: 2, from: true, undirected: true},
+		{u: 2, v: 1, from: true, undirected: true},
+		{u: 0, v: 2, from: false},
+	} {
+		u, v := Node(test.u), Node(test.v)
+		if got := g.HasEdgeFromTo(u, v); got != test.from {
+			t.Errorf("unexpected HasEdgeFromTo(%d, %d): got:%t want:%t", test.u, test.v, got, test.from)
+		}
+		if got := g.IsUndirected(u, v); got != test.undirected {
+			t.Errorf("unexpected IsUndirected(%d, %d): got:%t want:%t", test.u, test.v, got, test.undirected)
+		}
+	}
+
+	if n := len(g.Edges()); n != 2 {
+		t.Errorf("unexpected number of edges: got:%d want:2", n)
+	}
+	if n := len(g.From(Node(1))); n != 1 {
+		t.Errorf("unexpected number of nodes from 1: got:%d want:1", n)
+	}
+	if n := len(g.To(Node(1))); n != 2 {
+		t.Errorf("unexpected number of nodes to 1: got:%d want:2", n)
+	}
+	if d := g.Degree(Node(1)); d != 2 {
+		t.Errorf("unexpected degree of 1: got:%d want:2", d)
+	}
+	if w, ok := g.Weight(Node(2), Node(1)); !ok || w != 2 {
+		t.Errorf("unexpected weight from 2 to 1: got:%v %t want:2 true", w, ok)
+	}
+}
+
+func TestMixedGraphReplaceKind(t *testing.T) {
+	g := NewMixedGraph(0, math.Inf(1))
+	g.SetEdge(Edge{F: Node(0), T: Node(1), W: 1})
+	g.SetEdge(Edge{F: Node(1), T: Node(0), W: 1})
+
+	g.SetUndirectedEdge(Edge{F: Node(0), T: Node(1), W: 3})
+	if n := len(g.DirectedEdges()); n != 0 {
+		t.Errorf("unexpected directed edges after setting undirected edge: got:%d want:0", n)
+	}
+	if n := len(g.UndirectedEdges()); n != 1 {
+		t.Errorf("unexpected undirected edges: got:%d want:1", n)
+	}
+
+	g.SetEdge(Edge{F: Node(1), T: Node(0), W: 4})
+	if g.IsUndirected(Node(0), Node(1)) {
+		t.Errorf("undirected edge not replaced by directed edge")
+	}
+	if g.HasEdgeFromTo(Node(0), Node(1)) {
+		t.Errorf("unexpected edge from 0 to 1")
+	}
+
+	g.SetUndirectedEdge(Edge{F: Node(0), T: Node(1), W: 3})
+	g.RemoveEdge(Edge{F: Node(1), T: Node(0)})
+	if g.HasEdgeBetween(Node(0), Node(1)) {
+		t.Errorf("undirected edge not removed")
+	}
+
+	g.SetUndirectedEdge(Edge{F: Node(0), T: Node(1), W: 3})
+	g.RemoveNode(Node(1))
+	if n := len(g.From(Node(0))); n != 0 {
+		t.Errorf("unexpected nodes from 0 after node removal: got:%d want:0", n)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gonum/graph"
+)
+
+// KeyedNode is a graph node with an external string key.
+type KeyedNode struct {
+	id  int
+	key string
+}
+
+// NewKeyedNode returns a KeyedNode with the given ID and key.
+func NewKeyedNode(id int, key string) KeyedNode {
+	return KeyedNode{id: id, key: key}
+}
+
+// ID returns the ID number of the node.
+func (n KeyedNode) ID() int { return n.id }
+
+// Key returns the external key of the node.
+func (n KeyedNode) Key() string { return n.key }
+
+// Registry maps external string keys to the nodes of a graph and back.
+type Registry struct {
+	g graph.NodeAdder
+
+	nodes map[string]graph.Node
+	keys  map[int]string
+}
+
+// NewRegistry returns a Registry that adds the nodes it creates to g.
+func NewRegistry(g graph.NodeAdder) *Registry {
+	return &Registry{
+		g:     g,
+		nodes: make(map[string]graph.Node),
+		keys:  make(map[int]string),
+	}
+}
+
+// GetOrCreate returns the node registered with the given key. If no
+// node is registered with the key, a new KeyedNode is created with an
+// ID from the registry's graph, added to the graph and registered.
+func (r *Registry) GetOrCreate(key string) graph.Node {
+	if n, ok := r.nodes[key]; ok {
+		return n
+	}
+	n := NewKeyedNode(r.g.NewNodeID(), key)
+	r.g.AddNode(n)
+	r.nodes[key] = n
+	r.keys[n.ID()] = key
+	return n
+}
+
+// Register registers the node n with the given key. The node is not
+// added to the registry's graph. Register panics if the key or the
+// node ID is already registered.
+func (r *Registry) Register(key string, n graph.Node) {
+	if _, exists := r.nodes[key]; exists {
+		panic(fmt.Sprintf("simple: key %q already registered", key))
+	}
+	if k, exists := r.keys[n.ID()]; exists {
+		panic(fmt.Sprintf("simple: node ID %d already registered with key %q", n.ID(), k))
+	}
+	r.nodes[key] = n
+	r.keys[n.ID()] = key
+}
+
+// Remove removes the registration of the given key, returning whether
+// the key was registered. The node is not removed from the registry's
+// graph.
+func (r *Registry) Remove(key string) bool {
+	n, ok := r.nodes[key]
+	if !ok {
+		return false
+	}
+	delete(r.nodes, key)
+	delete(r.keys, n.ID())
+	return true
+}
+
+// Node returns the node registered with the given key and whether
+// it exists.
+func (r *Registry) Node(key string) (graph.Node, bool) {
+	n, ok := r.nodes[key]
+	return n, ok
+}
+
+// ID returns the ID of the node registered with the given key and
+// whether it exists.
+func (r *Registry) ID(key string) (int, bool) {
+	n, ok := r.nodes[key]
+	if !ok {
+		return -1, false
+	}
+	return n.ID(), true
+}
+
+// Key returns the key registered for the node ID and whether it exists.
+func (r *Registry) Key(id int) (string, bool) {
+	key, ok := r.keys[id]
+	return key, ok
+}
+
+// Len returns the number of registered keys.
+func (r *Registry) Len() int { return len(r.nodes) }
+
+// Keys returns the registered keys in sorted order.
+func (r *Registry) Keys() []string {
+	keys := make([]string, 0, len(r.nodes))
+	for k := range r.nodes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
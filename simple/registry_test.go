@@ -0,0 +1,80 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	g := NewUndirectedGraph(0, math.Inf(1))
+	r := NewRegistry(g)
+
+	a := r.GetOrCreate("a")
+	b := r.GetOrCreate("b")
+	if again := r.GetOrCreate("a"); again != a {
+		t.Errorf("GetOrCreate returned a different node for existing key: got:%v want:%v", again, a)
+	}
+	if a.ID() == b.ID() {
+		t.Errorf("unexpected shared node ID %d", a.ID())
+	}
+	if !g.Has(a) || !g.Has(b) {
+		t.Errorf("created nodes not added to graph")
+	}
+	if k := a.(KeyedNode).Key(); k != "a" {
+		t.Errorf("unexpected node key: got:%q want:%q", k, "a")
+	}
+
+	c := Node(g.NewNodeID())
+	g.AddNode(c)
+	r.Register("c", c)
+	if n, ok := r.Node("c"); !ok || n != c {
+		t.Errorf("unexpected registered node: got:%v,%t want:%v,true", n, ok, c)
+	}
+	if id, ok := r.ID("b"); !ok || id != b.ID() {
+		t.Errorf("unexpected ID for key: got:%d,%t want:%d,true", id, ok, b.ID())
+	}
+	if key, ok := r.Key(c.ID()); !ok || key != "c" {
+		t.Errorf("unexpected key for ID: got:%q,%t want:%q,true", key, ok, "c")
+	}
+	if got, want := r.Keys(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected keys: got:%v want:%v", got, want)
+	}
+
+	for _, test := range []struct {
+		key string
+		id  int
+	}{
+		{key: "a", id: 100},
+		{key: "d", id: a.ID()},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic registering %q with ID %d", test.key, test.id)
+				}
+			}()
+			r.Register(test.key, Node(test.id))
+		}()
+	}
+
+	if !r.Remove("a") {
+		t.Errorf("expected key to be removed")
+	}
+	if r.Remove("a") {
+		t.Errorf("unexpected removal of absent key")
+	}
+	if _, ok := r.Key(a.ID()); ok {
+		t.Errorf("unexpected key for removed registration")
+	}
+	if !g.Has(a) {
+		t.Errorf("node removed from graph by registry")
+	}
+	if r.Len() != 2 {
+		t.Errorf("unexpected number of keys: got:%d want:2", r.Len())
+	}
+}
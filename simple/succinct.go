@@ -0,0 +1,308 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/bitvector"
+)
+
+// Succinct is an immutable directed graph held in space close to the
+// information theoretic minimum for its node and edge counts.
+//
+// The node IDs are held as a bit vector over the range of IDs, the degrees
+// of the nodes as unary codes in a second bit vector, and the neighbors of
+// each node as a sorted list of fixed width node indices, with rank and select
+// queries on the bit vectors mapping between IDs, indices and list positions.
+// For a graph of n nodes with a maximum ID of k and m edges, the structure
+// uses about k+2n+2m bits in addition to 2m⌈log₂ n⌉ bits for the neighbor
+// lists, and edge weights are held only if they differ between edges.
+//
+// Only the IDs of nodes and the end points and weights of edges are retained,
+// so nodes are returned as Node values and edges as Edge values. Edge lookup
+// takes time logarithmic in the out-degree of the from node.
+type Succinct struct {
+	ids *bitvector.Vector
+
+	from, to succinctAdjacency
+
+	// weights holds the weights of edges
+	// in the order of the from lists if
+	// they are not all equal to weight.
+	weight  float64
+	weights []float64
+
+	self, absent float64
+}
+
+// succinctAdjacency holds the adjacency lists of a Succinct graph.
+type succinctAdjacency struct {
+	// degrees holds a set bit for each
+	// node and a final set bit, each
+	// followed by an unset bit for each
+	// neighbor of the node.
+	degrees *bitvector.Vector
+
+	// neighbors holds the sorted
+	// neighbor indices of each node.
+	neighbors packedInts
+}
+
+// NewSuccinct returns a Succinct holding the nodes and weighted edges of g,
+// with the specified self and absent edge weight values. NewSuccinct panics
+// if a node of g has a negative ID.
+func NewSuccinct(g graph.Directed, self, absent float64) *Succinct {
+	nodes := g.Nodes()
+	maxID := -1
+	for _, n := range nodes {
+		if n.ID() < 0 {
+			panic(fmt.Sprintf("simple: negative node ID: %d", n.ID()))
+		}
+		if n.ID() > maxID {
+			maxID = n.ID()
+		}
+	}
+	present := make([]bool, maxID+1)
+	for _, n := range nodes {
+		present[n.ID()] = true
+	}
+	var b bitvector.Builder
+	for _, ok := range present {
+		b.Append(ok)
+	}
+	s := &Succinct{
+		ids:    b.Vector(),
+		self:   self,
+		absent: absent,
+	}
+
+	s.from = s.adjacency(g, g.From)
+	s.to = s.adjacency(g, g.To)
+
+	var weights []float64
+	for i := 0; i < s.ids.Ones(); i++ {
+		u := Node(s.ids.Select1(i))
+		lo, hi := s.from.span(i)
+		for k := lo; k < hi; k++ {
+			weights = append(weights, g.Edge(u, Node(s.ids.Select1(s.from.neighbors.at(k)))).Weight())
+		}
+	}
+	for i, w := range weights {
+		if w != weights[0] {
+			s.weights = weights
+			break
+		}
+		if i == len(weights)-1 {
+			s.weight = w
+		}
+	}
+	return s
+}
+
+// adjacency returns the adjacency lists of g given by the adj function.
+func (s *Succinct) adjacency(g graph.Graph, adj func(graph.Node) []graph.Node) succinctAdjacency {
+	n := s.ids.Ones()
+	var (
+		degrees   bitvector.Builder
+		neighbors []int
+	)
+	for i := 0; i < n; i++ {
+		to := adj(Node(s.ids.Select1(i)))
+		idx := make([]int, len(to))
+		for k, v := range to {
+			idx[k] = s.ids.Rank1(v.ID())
+		}
+		sort.Ints(idx)
+		neighbors = append(neighbors, idx...)
+		degrees.Append(true)
+		degrees.AppendN(false, len(idx))
+	}
+	degrees.Append(true)
+	return succinctAdjacency{
+		degrees:   degrees.Vector(),
+		neighbors: newPackedInts(neighbors, n-1),
+	}
+}
+
+// span returns the range of positions in a.neighbors holding the
+// neighbors of the node with index i.
+func (a *succinctAdjacency) span(i int) (lo, hi int) {
+	return a.degrees.Select1(i) - i, a.degrees.Select1(i+1) - i - 1
+}
+
+// find returns the position of the neighbor with index j in the list of
+// the node with index i, or -1 if j is not a neighbor of i.
+func (a *succinctAdjacency) find(i, j int) int {
+	lo, hi := a.span(i)
+	k := lo + sort.Search(hi-lo, func(k int) bool { return a.neighbors.at(lo+k) >= j })
+	if k == hi || a.neighbors.at(k) != j {
+		return -1
+	}
+	return k
+}
+
+// index returns the index of the node with the given ID, or -1 if there
+// is no such node.
+func (s *Succinct) index(id int) int {
+	if id < 0 || id >= s.ids.Len() || !s.ids.Get(id) {
+		return -1
+	}
+	return s.ids.Rank1(id)
+}
+
+// Node returns the node in the graph with the given ID, or nil if no
+// such node exists.
+func (s *Succinct) Node(id int) graph.Node {
+	if s.index(id) < 0 {
+		return nil
+	}
+	return Node(id)
+}
+
+// Has returns whether the node exists within the graph.
+func (s *Succinct) Has(n graph.Node) bool {
+	return s.index(n.ID()) >= 0
+}
+
+// Nodes returns all the nodes in the graph.
+func (s *Succinct) Nodes() []graph.Node {
+	nodes := make([]graph.Node, s.ids.Ones())
+	for i := range nodes {
+		nodes[i] = Node(s.ids.Select1(i))
+	}
+	return nodes
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (s *Succinct) From(n graph.Node) []graph.Node {
+	return s.nodesOf(&s.from, n)
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (s *Succinct) To(n graph.Node) []graph.Node {
+	return s.nodesOf(&s.to, n)
+}
+
+func (s *Succinct) nodesOf(a *succinctAdjacency, n graph.Node) []graph.Node {
+	i := s.index(n.ID())
+	if i < 0 {
+		return nil
+	}
+	lo, hi := a.span(i)
+	nodes := make([]graph.Node, hi-lo)
+	for k := range nodes {
+		nodes[k] = Node(s.ids.Select1(a.neighbors.at(lo + k)))
+	}
+	return nodes
+}
+
+// edge returns the position of the edge from u to v in the from lists,
+// or -1 if there is no such edge.
+func (s *Succinct) edge(u, v graph.Node) int {
+	i := s.index(u.ID())
+	if i < 0 {
+		return -1
+	}
+	j := s.index(v.ID())
+	if j < 0 {
+		return -1
+	}
+	return s.from.find(i, j)
+}
+
+func (s *Succinct) weightAt(k int) float64 {
+	if s.weights == nil {
+		return s.weight
+	}
+	return s.weights[k]
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (s *Succinct) HasEdgeBetween(x, y graph.Node) bool {
+	return s.edge(x, y) >= 0 || s.edge(y, x) >= 0
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (s *Succinct) HasEdgeFromTo(u, v graph.Node) bool {
+	return s.edge(u, v) >= 0
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (s *Succinct) Edge(u, v graph.Node) graph.Edge {
+	k := s.edge(u, v)
+	if k < 0 {
+		return nil
+	}
+	return Edge{F: Node(u.ID()), T: Node(v.ID()), W: s.weightAt(k)}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If x and y are the same node or there is no joining edge between the two nodes the weight
+// value returned is either the graph's absent or self value. Weight returns true if an edge
+// exists between x and y or if x and y have the same ID, false otherwise.
+func (s *Succinct) Weight(x, y graph.Node) (w float64, ok bool) {
+	if x.ID() == y.ID() {
+		return s.self, true
+	}
+	if k := s.edge(x, y); k >= 0 {
+		return s.weightAt(k), true
+	}
+	return s.absent, false
+}
+
+// Degree returns the in+out degree of n in g.
+func (s *Succinct) Degree(n graph.Node) int {
+	i := s.index(n.ID())
+	if i < 0 {
+		return 0
+	}
+	lo, hi := s.from.span(i)
+	d := hi - lo
+	lo, hi = s.to.span(i)
+	return d + hi - lo
+}
+
+// packedInts is an array of non-negative integers packed at a fixed
+// bit width.
+type packedInts struct {
+	width uint
+	words []uint64
+}
+
+// newPackedInts returns a packedInts holding the values of v, which must be
+// in [0, max].
+func newPackedInts(v []int, max int) packedInts {
+	var width uint = 1
+	for max>>width > 0 {
+		width++
+	}
+	p := packedInts{
+		width: width,
+		words: make([]uint64, (uint64(len(v))*uint64(width)+63)/64),
+	}
+	for k, x := range v {
+		off := uint64(k) * uint64(width)
+		p.words[off/64] |= uint64(x) << (off % 64)
+		if off%64+uint64(width) > 64 {
+			p.words[off/64+1] |= uint64(x) >> (64 - off%64)
+		}
+	}
+	return p
+}
+
+// at returns the value at position k.
+func (p packedInts) at(k int) int {
+	off := uint64(k) * uint64(p.width)
+	x := p.words[off/64] >> (off % 64)
+	if off%64+uint64(p.width) > 64 {
+		x |= p.words[off/64+1] << (64 - off%64)
+	}
+	return int(x & (1<<p.width - 1))
+}
@@ -0,0 +1,106 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+var _ graph.Directed = (*Succinct)(nil)
+
+func succinctIDs(nodes []graph.Node) []int {
+	sort.Sort(ordered.ByID(nodes))
+	ids := make([]int, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+func TestSuccinct(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 50, 200} {
+		for _, weighted := range []bool{false, true} {
+			g := NewDirectedGraph(0, math.Inf(1))
+			for i := 0; i < n; i++ {
+				// Leave gaps in the ID range.
+				g.AddNode(Node(2*i + rnd.Intn(2)))
+			}
+			nodes := g.Nodes()
+			for _, u := range nodes {
+				for _, v := range nodes {
+					if u.ID() == v.ID() || rnd.Float64() > 0.1 {
+						continue
+					}
+					w := 1.0
+					if weighted {
+						w = float64(rnd.Intn(10))
+					}
+					g.SetEdge(Edge{F: u, T: v, W: w})
+				}
+			}
+
+			s := NewSuccinct(g, 0, math.Inf(1))
+			if !reflect.DeepEqual(succinctIDs(s.Nodes()), succinctIDs(g.Nodes())) {
+				t.Fatalf("unexpected nodes for n=%d", n)
+			}
+			for id := -1; id <= 2*n+1; id++ {
+				if (s.Node(id) != nil) != (g.Node(id) != nil) {
+					t.Errorf("unexpected node existence for %d", id)
+				}
+			}
+			for _, u := range nodes {
+				if got, want := succinctIDs(s.From(u)), succinctIDs(g.From(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected from nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				if got, want := succinctIDs(s.To(u)), succinctIDs(g.To(u)); !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected to nodes for %d: got:%v want:%v", u.ID(), got, want)
+				}
+				if s.Degree(u) != g.Degree(u) {
+					t.Errorf("unexpected degree for %d: got:%d want:%d", u.ID(), s.Degree(u), g.Degree(u))
+				}
+				for _, v := range nodes {
+					if s.HasEdgeFromTo(u, v) != g.HasEdgeFromTo(u, v) {
+						t.Errorf("unexpected edge existence for %d->%d", u.ID(), v.ID())
+					}
+					if s.HasEdgeBetween(u, v) != g.HasEdgeBetween(u, v) {
+						t.Errorf("unexpected edge between existence for %d--%d", u.ID(), v.ID())
+					}
+					got, gotOK := s.Weight(u, v)
+					want, wantOK := g.Weight(u, v)
+					if got != want || gotOK != wantOK {
+						t.Errorf("unexpected weight for %d->%d: got:%v,%t want:%v,%t", u.ID(), v.ID(), got, gotOK, want, wantOK)
+					}
+				}
+			}
+			if !weighted && s.weights != nil {
+				t.Errorf("unexpected weight storage for uniformly weighted graph")
+			}
+		}
+	}
+}
+
+func TestPackedInts(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, max := range []int{0, 1, 2, 7, 1000, 1<<31 - 1} {
+		v := make([]int, 300)
+		for i := range v {
+			v[i] = rnd.Intn(max + 1)
+		}
+		p := newPackedInts(v, max)
+		for i, want := range v {
+			if got := p.at(i); got != want {
+				t.Errorf("unexpected value at %d for max=%d: got:%d want:%d", i, max, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import "github.com/gonum/graph"
+
+// tombstones holds soft deleted nodes keyed by ID.
+type tombstones map[int]*tombstone
+
+// tombstone is a soft deleted node and the edges that
+// joined it to the graph or to other soft deleted nodes.
+type tombstone struct {
+	node  graph.Node
+	edges []graph.Edge
+}
+
+func (t *tombstones) add(n graph.Node, edges []graph.Edge) {
+	if *t == nil {
+		*t = make(tombstones)
+	}
+	(*t)[n.ID()] = &tombstone{node: n, edges: edges}
+}
+
+// purge removes the tombstone of the node with the given ID and the edges
+// of other tombstones that join it, returning whether there was a tombstone.
+func (t tombstones) purge(id int) bool {
+	if _, ok := t[id]; !ok {
+		return false
+	}
+	delete(t, id)
+	t.dropEdges(id)
+	return true
+}
+
+// dropEdges removes the edges joining the node with the given ID from the
+// tombstones.
+func (t tombstones) dropEdges(id int) {
+	for _, ts := range t {
+		edges := ts.edges[:0]
+		for _, e := range ts.edges {
+			if e.From().ID() != id && e.To().ID() != id {
+				edges = append(edges, e)
+			}
+		}
+		ts.edges = edges
+	}
+}
+
+// removeEdge removes the edge from the node with ID u to the node with ID v
+// from the tombstones of its end points. If directed is false the edge is
+// removed in either orientation.
+func (t tombstones) removeEdge(u, v int, directed bool) {
+	for _, id := range [2]int{u, v} {
+		ts, ok := t[id]
+		if !ok {
+			continue
+		}
+		edges := ts.edges[:0]
+		for _, e := range ts.edges {
+			f, to := e.From().ID(), e.To().ID()
+			if (f == u && to == v) || (!directed && f == v && to == u) {
+				continue
+			}
+			edges = append(edges, e)
+		}
+		ts.edges = edges
+	}
+}
+
+// take removes the tombstone of the node with the given ID and returns it.
+// The edges of the tombstone joining other soft deleted nodes are moved to
+// their tombstones and the remaining edges are returned.
+func (t tombstones) take(id int) (n graph.Node, edges []graph.Edge, ok bool) {
+	ts, ok := t[id]
+	if !ok {
+		return nil, nil, false
+	}
+	delete(t, id)
+	for _, e := range ts.edges {
+		other := e.From().ID()
+		if other == id {
+			other = e.To().ID()
+		}
+		if o, ok := t[other]; ok {
+			o.edges = append(o.edges, e)
+			continue
+		}
+		edges = append(edges, e)
+	}
+	return ts.node, edges, true
+}
+
+func (t tombstones) nodes() []graph.Node {
+	if len(t) == 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, 0, len(t))
+	for _, ts := range t {
+		nodes = append(nodes, ts.node)
+	}
+	return nodes
+}
+
+func (t tombstones) clone() tombstones {
+	if t == nil {
+		return nil
+	}
+	c := make(tombstones, len(t))
+	for id, ts := range t {
+		c[id] = &tombstone{node: ts.node, edges: append([]graph.Edge(nil), ts.edges...)}
+	}
+	return c
+}
+
+// SoftDelete removes n and the edges attached to it from the graph, retaining
+// them so that they can be restored by Restore. The ID of n remains in use
+// until n is removed with RemoveNode, so NewNodeID does not return it and
+// AddNode panics if it is given a node with the ID. If the node is not in the
+// graph it is a no-op.
+func (g *DirectedGraph) SoftDelete(n graph.Node) {
+	u, ok := g.nodes[n.ID()]
+	if !ok {
+		return
+	}
+	var edges []graph.Edge
+	for _, e := range g.from[n.ID()] {
+		edges = append(edges, e)
+	}
+	for _, e := range g.to[n.ID()] {
+		edges = append(edges, e)
+	}
+	g.detach(n.ID())
+	g.deleted.add(u, edges)
+}
+
+// Restore returns the soft deleted node n to the graph with the edges
+// attached to it when it was deleted, and returns whether n was soft deleted.
+// Edges joining n to nodes that are still soft deleted are restored when
+// those nodes are restored.
+func (g *DirectedGraph) Restore(n graph.Node) bool {
+	u, edges, ok := g.deleted.take(n.ID())
+	if !ok {
+		return false
+	}
+	g.AddNode(u)
+	for _, e := range edges {
+		g.SetEdge(e)
+	}
+	return true
+}
+
+// Deleted returns the soft deleted nodes of the graph.
+func (g *DirectedGraph) Deleted() []graph.Node { return g.deleted.nodes() }
+
+// IsDeleted returns whether the node n is soft deleted.
+func (g *DirectedGraph) IsDeleted(n graph.Node) bool {
+	_, ok := g.deleted[n.ID()]
+	return ok
+}
+
+// SoftDelete removes n and the edges attached to it from the graph, retaining
+// them so that they can be restored by Restore. The ID of n remains in use
+// until n is removed with RemoveNode, so NewNodeID does not return it and
+// AddNode panics if it is given a node with the ID. If the node is not in the
+// graph it is a no-op.
+func (g *UndirectedGraph) SoftDelete(n graph.Node) {
+	u, ok := g.nodes[n.ID()]
+	if !ok {
+		return
+	}
+	var edges []graph.Edge
+	for _, e := range g.edges[n.ID()] {
+		edges = append(edges, e)
+	}
+	g.detach(n.ID())
+	g.deleted.add(u, edges)
+}
+
+// Restore returns the soft deleted node n to the graph with the edges
+// attached to it when it was deleted, and returns whether n was soft deleted.
+// Edges joining n to nodes that are still soft deleted are restored when
+// those nodes are restored.
+func (g *UndirectedGraph) Restore(n graph.Node) bool {
+	u, edges, ok := g.deleted.take(n.ID())
+	if !ok {
+		return false
+	}
+	g.AddNode(u)
+	for _, e := range edges {
+		g.SetEdge(e)
+	}
+	return true
+}
+
+// Deleted returns the soft deleted nodes of the graph.
+func (g *UndirectedGraph) Deleted() []graph.Node { return g.deleted.nodes() }
+
+// IsDeleted returns whether the node n is soft deleted.
+func (g *UndirectedGraph) IsDeleted(n graph.Node) bool {
+	_, ok := g.deleted[n.ID()]
+	return ok
+}
@@ -0,0 +1,140 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSoftDeleteDirected(t *testing.T) {
+	g := NewDirectedGraph(0, 0)
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 0}, {2, 3}} {
+		g.SetEdge(Edge{F: Node(e[0]), T: Node(e[1]), W: float64(e[0] + 1)})
+	}
+
+	g.SoftDelete(Node(2))
+	g.SoftDelete(Node(3))
+	if g.Has(Node(2)) || g.HasEdgeBetween(Node(1), Node(2)) || len(g.From(Node(1))) != 0 {
+		t.Fatal("soft deleted node still reachable")
+	}
+	if got := ids(g.Deleted()); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("unexpected deleted nodes: got:%v want:[2 3]", got)
+	}
+	if !g.IsDeleted(Node(3)) || g.IsDeleted(Node(0)) {
+		t.Error("unexpected IsDeleted result")
+	}
+	if id := g.NewNodeID(); id == 2 || id == 3 {
+		t.Errorf("new node ID reuses deleted ID: %d", id)
+	}
+	panicked := func() (p bool) {
+		defer func() { p = recover() != nil }()
+		g.AddNode(Node(2))
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic adding node with deleted ID")
+	}
+
+	c := g.Clone()
+
+	if !g.Restore(Node(2)) {
+		t.Fatal("failed to restore node")
+	}
+	if g.Restore(Node(2)) {
+		t.Error("unexpected second restore")
+	}
+	for _, e := range [][2]int{{1, 2}, {2, 0}} {
+		if w, ok := g.Weight(Node(e[0]), Node(e[1])); !ok || w != float64(e[0]+1) {
+			t.Errorf("edge %v not restored: weight=%v ok=%t", e, w, ok)
+		}
+	}
+	if g.HasEdgeBetween(Node(2), Node(3)) {
+		t.Error("edge to deleted node restored")
+	}
+
+	// The edge between the deleted nodes is
+	// restored with the second of them.
+	g.Restore(Node(3))
+	if !g.HasEdgeFromTo(Node(2), Node(3)) {
+		t.Error("edge between deleted nodes not restored")
+	}
+	if len(g.Deleted()) != 0 {
+		t.Errorf("unexpected deleted nodes after restore: %v", g.Deleted())
+	}
+
+	// The clone retains its own tombstones.
+	if got := ids(c.Deleted()); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("unexpected deleted nodes in clone: got:%v want:[2 3]", got)
+	}
+}
+
+func TestSoftDeleteUndirected(t *testing.T) {
+	g := NewUndirectedGraph(0, 0)
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 3}} {
+		g.SetEdge(Edge{F: Node(e[0]), T: Node(e[1]), W: 1})
+	}
+
+	g.SoftDelete(Node(1))
+	g.SoftDelete(Node(2))
+
+	// Removing a live node drops its
+	// edges from the tombstones.
+	g.RemoveNode(Node(0))
+	g.Restore(Node(1))
+	if len(g.From(Node(1))) != 0 {
+		t.Errorf("unexpected edges of restored node: %v", g.From(Node(1)))
+	}
+
+	// Removing a deleted node discards it
+	// and releases its ID.
+	g.RemoveNode(Node(2))
+	if g.IsDeleted(Node(2)) || g.Has(Node(2)) {
+		t.Error("removed node still held")
+	}
+	if g.Restore(Node(2)) {
+		t.Error("unexpected restore of removed node")
+	}
+	if id := g.NewNodeID(); id != 0 {
+		t.Errorf("unexpected new node ID: got:%d want:0", id)
+	}
+	g.AddNode(Node(2))
+	if g.HasEdgeBetween(Node(2), Node(3)) || g.HasEdgeBetween(Node(1), Node(2)) {
+		t.Error("edges of removed node resurrected")
+	}
+}
+
+func TestSoftDeleteRemoveEdge(t *testing.T) {
+	d := NewDirectedGraph(0, 0)
+	d.SetEdge(Edge{F: Node(0), T: Node(1), W: 1})
+	d.SetEdge(Edge{F: Node(1), T: Node(0), W: 1})
+	d.SetEdge(Edge{F: Node(1), T: Node(2), W: 1})
+	d.SoftDelete(Node(1))
+	d.RemoveEdge(Edge{F: Node(0), T: Node(1)})
+	d.Restore(Node(1))
+	if d.HasEdgeFromTo(Node(0), Node(1)) {
+		t.Error("removed edge restored in directed graph")
+	}
+	if !d.HasEdgeFromTo(Node(1), Node(0)) || !d.HasEdgeFromTo(Node(1), Node(2)) {
+		t.Error("retained edges not restored in directed graph")
+	}
+
+	u := NewUndirectedGraph(0, 0)
+	u.SetEdge(Edge{F: Node(0), T: Node(1), W: 1})
+	u.SetEdge(Edge{F: Node(1), T: Node(2), W: 1})
+	u.SoftDelete(Node(1))
+	u.SoftDelete(Node(2))
+	// The edge is held in the tombstone of 1 and
+	// is removed in the reverse orientation.
+	u.RemoveEdge(Edge{F: Node(2), T: Node(1)})
+	u.Restore(Node(2))
+	u.Restore(Node(1))
+	if u.HasEdgeBetween(Node(1), Node(2)) {
+		t.Error("removed edge restored in undirected graph")
+	}
+	if !u.HasEdgeBetween(Node(0), Node(1)) {
+		t.Error("retained edge not restored in undirected graph")
+	}
+}
@@ -7,8 +7,6 @@ package simple
 import (
 	"fmt"
 
-	"golang.org/x/tools/container/intsets"
-
 	"github.com/gonum/graph"
 )
 
@@ -19,8 +17,14 @@ type UndirectedGraph struct {
 
 	self, absent float64
 
-	freeIDs intsets.Sparse
-	usedIDs intsets.Sparse
+	// edgesPerNode is the initial capacity
+	// of the edge maps of added nodes.
+	edgesPerNode int
+
+	ids IDAllocator
+
+	// deleted holds the soft deleted nodes.
+	deleted tombstones
 }
 
 // NewUndirectedGraph returns an UndirectedGraph with the specified self and absent
@@ -32,32 +36,42 @@ func NewUndirectedGraph(self, absent float64) *UndirectedGraph {
 
 		self:   self,
 		absent: absent,
+
+		ids: &ReuseIDs{},
+	}
+}
+
+// NewUndirectedGraphWithCapacity returns a UndirectedGraph with the specified self and absent
+// edge weight values and with internal storage sized for the given number of
+// nodes and for edgesPerNode edges incident to each added node. Bulk loading a
+// graph of known size into a pre-sized graph avoids incremental map growth.
+func NewUndirectedGraphWithCapacity(nodes, edgesPerNode int, self, absent float64) *UndirectedGraph {
+	return &UndirectedGraph{
+		nodes: make(map[int]graph.Node, nodes),
+		edges: make(map[int]map[int]graph.Edge, nodes),
+
+		self:         self,
+		absent:       absent,
+		edgesPerNode: edgesPerNode,
+
+		ids: &ReuseIDs{},
 	}
 }
 
 // NewNodeID returns a new unique ID for a node to be added to g. The returned ID does
-// not become a valid ID in g until it is added to g.
+// not become a valid ID in g until it is added to g. IDs are allocated by the
+// IDAllocator of g, which is a ReuseIDs unless set by SetIDAllocator.
 func (g *UndirectedGraph) NewNodeID() int {
-	if len(g.nodes) == 0 {
-		return 0
-	}
-	if len(g.nodes) == maxInt {
-		panic(fmt.Sprintf("simple: cannot allocate node: no slot"))
-	}
+	return g.ids.NewID()
+}
 
-	var id int
-	if g.freeIDs.Len() != 0 && g.freeIDs.TakeMin(&id) {
-		return id
+// SetIDAllocator sets the allocator of the IDs returned by NewNodeID to a,
+// marking the IDs of the nodes in g as in use by a.
+func (g *UndirectedGraph) SetIDAllocator(a IDAllocator) {
+	for id := range g.nodes {
+		a.Use(id)
 	}
-	if id = g.usedIDs.Max(); id < maxInt {
-		return id + 1
-	}
-	for id = 0; id < maxInt; id++ {
-		if !g.usedIDs.Has(id) {
-			return id
-		}
-	}
-	panic("unreachable")
+	g.ids = a
 }
 
 // AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
@@ -65,29 +79,60 @@ func (g *UndirectedGraph) AddNode(n graph.Node) {
 	if _, exists := g.nodes[n.ID()]; exists {
 		panic(fmt.Sprintf("simple: node ID collision: %d", n.ID()))
 	}
+	if _, deleted := g.deleted[n.ID()]; deleted {
+		panic(fmt.Sprintf("simple: node ID collision with deleted node: %d", n.ID()))
+	}
 	g.nodes[n.ID()] = n
-	g.edges[n.ID()] = make(map[int]graph.Edge)
+	g.edges[n.ID()] = make(map[int]graph.Edge, g.edgesPerNode)
 
-	g.freeIDs.Remove(n.ID())
-	g.usedIDs.Insert(n.ID())
+	g.ids.Use(n.ID())
 }
 
-// RemoveNode removes n from the graph, as well as any edges attached to it. If the node
-// is not in the graph it is a no-op.
+// Reserve reallocates the internal storage of g to hold n more nodes without
+// incremental map growth. Reserve copies the existing storage, so it should be
+// called once before bulk loading rather than before each addition.
+func (g *UndirectedGraph) Reserve(n int) {
+	if n <= 0 {
+		return
+	}
+	nodes := make(map[int]graph.Node, len(g.nodes)+n)
+	for id, u := range g.nodes {
+		nodes[id] = u
+	}
+	g.nodes = nodes
+
+	edges := make(map[int]map[int]graph.Edge, len(g.nodes)+n)
+	for id, e := range g.edges {
+		edges[id] = e
+	}
+	g.edges = edges
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it. If n is
+// soft deleted, its retained edges are discarded. If the node is not in the graph it
+// is a no-op.
 func (g *UndirectedGraph) RemoveNode(n graph.Node) {
+	if g.deleted.purge(n.ID()) {
+		g.ids.Release(n.ID())
+		return
+	}
 	if _, ok := g.nodes[n.ID()]; !ok {
 		return
 	}
-	delete(g.nodes, n.ID())
+	g.detach(n.ID())
+	g.deleted.dropEdges(n.ID())
 
-	for from := range g.edges[n.ID()] {
-		delete(g.edges[from], n.ID())
-	}
-	delete(g.edges, n.ID())
+	g.ids.Release(n.ID())
+}
 
-	g.freeIDs.Insert(n.ID())
-	g.usedIDs.Remove(n.ID())
+// detach removes the node with the given ID and its edges from the graph.
+func (g *UndirectedGraph) detach(id int) {
+	delete(g.nodes, id)
 
+	for from := range g.edges[id] {
+		delete(g.edges[from], id)
+	}
+	delete(g.edges, id)
 }
 
 // SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added.
@@ -115,10 +160,11 @@ func (g *UndirectedGraph) SetEdge(e graph.Edge) {
 	g.edges[tid][fid] = e
 }
 
-// RemoveEdge removes e from the graph, leaving the terminal nodes. If the edge does not exist
-// it is a no-op.
+// RemoveEdge removes e from the graph, leaving the terminal nodes. If either terminal node is
+// soft deleted, the edge is not restored with it. If the edge does not exist it is a no-op.
 func (g *UndirectedGraph) RemoveEdge(e graph.Edge) {
 	from, to := e.From(), e.To()
+	g.deleted.removeEdge(from.ID(), to.ID(), false)
 	if _, ok := g.nodes[from.ID()]; !ok {
 		return
 	}
@@ -61,3 +61,29 @@ func TestIssue123UndirectedGraph(t *testing.T) {
 	n2 := Node(g.NewNodeID())
 	g.AddNode(n2)
 }
+
+func TestUndirectedGraphWithCapacity(t *testing.T) {
+	for _, g := range []*UndirectedGraph{
+		NewUndirectedGraphWithCapacity(10, 3, 0, math.Inf(1)),
+		NewUndirectedGraphWithCapacity(0, 0, 0, math.Inf(1)),
+	} {
+		g.SetEdge(Edge{F: Node(0), T: Node(1), W: 2})
+		g.Reserve(100)
+		for i := 2; i < 100; i++ {
+			g.SetEdge(Edge{F: Node(i - 1), T: Node(i), W: 1})
+		}
+		g.Reserve(0)
+		if n := len(g.Nodes()); n != 100 {
+			t.Errorf("unexpected number of nodes: got:%d want:100", n)
+		}
+		if n := len(g.Edges()); n != 99 {
+			t.Errorf("unexpected number of edges: got:%d want:99", n)
+		}
+		if w, ok := g.Weight(Node(1), Node(0)); !ok || w != 2 {
+			t.Errorf("edge lost after Reserve: got weight %v", w)
+		}
+		if len(g.From(Node(50))) != 2 {
+			t.Error("unexpected edges after bulk load")
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spatial
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/path"
+)
+
+// EarthRadius is the mean radius of the Earth in metres.
+const EarthRadius = 6371008.8
+
+// Haversine returns the great-circle distance in metres between a and b on
+// a sphere of radius EarthRadius, where the X and Y coordinates of each point
+// are its longitude and latitude in degrees.
+func Haversine(a, b layout.Point) float64 {
+	const rad = math.Pi / 180
+	lat1, lat2 := a.Y*rad, b.Y*rad
+	sinLat := math.Sin((lat2 - lat1) / 2)
+	sinLon := math.Sin((b.X - a.X) * rad / 2)
+	h := sinLat*sinLat + math.Cos(lat1)*math.Cos(lat2)*sinLon*sinLon
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// GreatCircleDistance returns the Haversine distance between the positions
+// of x and y. GreatCircleDistance panics if x or y is not Positioned.
+func GreatCircleDistance(x, y graph.Node) float64 {
+	return Haversine(pos(x), pos(y))
+}
+
+// GreatCircleHeuristic is a path.Heuristic returning the great-circle
+// distance between x and y. It is admissible and consistent for graphs
+// with edge weights no less than the great-circle distance between their
+// terminal nodes. It panics if x or y is not Positioned.
+func GreatCircleHeuristic(x, y graph.Node) float64 {
+	return GreatCircleDistance(x, y)
+}
+
+// GreatCircleWeighting returns a path.Weighting that returns the great-circle
+// distance between the terminal nodes of existing edges in g, zero for node
+// identity and Inf for otherwise absent edges. The returned Weighting panics
+// if an edge's terminal nodes are not Positioned.
+func GreatCircleWeighting(g graph.Graph) path.Weighting {
+	return func(x, y graph.Node) (w float64, ok bool) {
+		if x.ID() == y.ID() {
+			return 0, true
+		}
+		if e := g.Edge(x, y); e != nil {
+			return GreatCircleDistance(e.From(), e.To()), true
+		}
+		return math.Inf(1), false
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spatial
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/layout"
+)
+
+// KDTree is a 2-d tree index of Positioned nodes.
+type KDTree struct {
+	root *kdNode
+	len  int
+}
+
+// kdNode is a node of a KDTree. Nodes with a coordinate
+// on the node's axis less than the node's are held in
+// left and the remainder in right.
+type kdNode struct {
+	n           Positioned
+	axis        int
+	left, right *kdNode
+}
+
+// NewKDTree returns a balanced KDTree holding the given nodes.
+// The IDs of the nodes must be distinct.
+func NewKDTree(nodes []Positioned) *KDTree {
+	nodes = append([]Positioned(nil), nodes...)
+	return &KDTree{root: build(nodes, 0), len: len(nodes)}
+}
+
+// IndexNodes returns a balanced KDTree holding the nodes of g. IndexNodes
+// panics if a node of g is not Positioned.
+func IndexNodes(g graph.Graph) *KDTree {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	pn := make([]Positioned, len(nodes))
+	for i, n := range nodes {
+		p, ok := n.(Positioned)
+		if !ok {
+			panic(fmt.Sprintf("spatial: node %d is not positioned", n.ID()))
+		}
+		pn[i] = p
+	}
+	return &KDTree{root: build(pn, 0), len: len(pn)}
+}
+
+func build(nodes []Positioned, axis int) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Stable(byAxis{nodes: nodes, axis: axis})
+	m := len(nodes) / 2
+	// Move the median to the first of any equal
+	// coordinates so the left subtree holds only
+	// strictly lesser coordinates.
+	for m > 0 && coord(nodes[m-1].Pos(), axis) == coord(nodes[m].Pos(), axis) {
+		m--
+	}
+	return &kdNode{
+		n:     nodes[m],
+		axis:  axis,
+		left:  build(nodes[:m], 1-axis),
+		right: build(nodes[m+1:], 1-axis),
+	}
+}
+
+// Insert adds n to the tree. The ID of n must not be held by the tree.
+// Inserted nodes are not rebalanced.
+func (t *KDTree) Insert(n Positioned) {
+	t.len++
+	p := n.Pos()
+	next := &t.root
+	axis := 0
+	for *next != nil {
+		c := *next
+		if coord(p, c.axis) < coord(c.n.Pos(), c.axis) {
+			next = &c.left
+		} else {
+			next = &c.right
+		}
+		axis = 1 - c.axis
+	}
+	*next = &kdNode{n: n, axis: axis}
+}
+
+// Len returns the number of nodes held by the tree.
+func (t *KDTree) Len() int { return t.len }
+
+// NearestNode returns the node closest to p and the distance between them.
+// Ties are broken in favor of the node with the lowest ID. If the tree is
+// empty, NearestNode returns nil and +Inf.
+func (t *KDTree) NearestNode(p layout.Point) (n Positioned, d float64) {
+	d = math.Inf(1)
+	var search func(c *kdNode)
+	search = func(c *kdNode) {
+		if c == nil {
+			return
+		}
+		cd := dist(p, c.n.Pos())
+		if n == nil || cd < d || (cd == d && c.n.ID() < n.ID()) {
+			n, d = c.n, cd
+		}
+		delta := coord(p, c.axis) - coord(c.n.Pos(), c.axis)
+		near, far := c.right, c.left
+		if delta < 0 {
+			near, far = far, near
+		}
+		search(near)
+		if math.Abs(delta) <= d {
+			search(far)
+		}
+	}
+	search(t.root)
+	return n, d
+}
+
+// RangeQuery returns the nodes within r sorted by ID.
+func (t *KDTree) RangeQuery(r Rect) []Positioned {
+	var found []Positioned
+	var search func(c *kdNode)
+	search = func(c *kdNode) {
+		if c == nil {
+			return
+		}
+		p := c.n.Pos()
+		if r.Contains(p) {
+			found = append(found, c.n)
+		}
+		v := coord(p, c.axis)
+		if coord(r.Min, c.axis) < v {
+			search(c.left)
+		}
+		if v <= coord(r.Max, c.axis) {
+			search(c.right)
+		}
+	}
+	search(t.root)
+	sort.Sort(byID(found))
+	return found
+}
+
+func coord(p layout.Point, axis int) float64 {
+	if axis == 0 {
+		return p.X
+	}
+	return p.Y
+}
+
+// byAxis sorts nodes by their coordinate on an axis.
+type byAxis struct {
+	nodes []Positioned
+	axis  int
+}
+
+func (n byAxis) Len() int      { return len(n.nodes) }
+func (n byAxis) Swap(i, j int) { n.nodes[i], n.nodes[j] = n.nodes[j], n.nodes[i] }
+func (n byAxis) Less(i, j int) bool {
+	return coord(n.nodes[i].Pos(), n.axis) < coord(n.nodes[j].Pos(), n.axis)
+}
+
+// byID sorts Positioned nodes by ID.
+type byID []Positioned
+
+func (n byID) Len() int           { return len(n) }
+func (n byID) Less(i, j int) bool { return n[i].ID() < n[j].ID() }
+func (n byID) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
@@ -0,0 +1,102 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spatial provides spatial indexing and distance weighting
+// of graph nodes positioned in the plane.
+package spatial
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/path"
+)
+
+// Positioned is a graph node with a position in the plane.
+type Positioned interface {
+	graph.Node
+	Pos() layout.Point
+}
+
+// Node is a Positioned graph node. The position is reported
+// as a "pos" attribute in the form "x,y".
+type Node struct {
+	id  int
+	pos layout.Point
+}
+
+// NewNode returns a new Node with the given ID and position.
+func NewNode(id int, pos layout.Point) Node {
+	return Node{id: id, pos: pos}
+}
+
+// ID returns the ID number of the node.
+func (n Node) ID() int { return n.id }
+
+// Pos returns the position of the node.
+func (n Node) Pos() layout.Point { return n.pos }
+
+// Attributes returns the position of the node as a "pos" attribute.
+func (n Node) Attributes() []graph.Attribute {
+	return []graph.Attribute{{
+		Key:   "pos",
+		Value: strconv.FormatFloat(n.pos.X, 'g', -1, 64) + "," + strconv.FormatFloat(n.pos.Y, 'g', -1, 64),
+	}}
+}
+
+// Rect is an axis-aligned rectangle in the plane. Points
+// on the boundary of the rectangle are within it.
+type Rect struct {
+	Min, Max layout.Point
+}
+
+// Contains returns whether p is within the rectangle.
+func (r Rect) Contains(p layout.Point) bool {
+	return r.Min.X <= p.X && p.X <= r.Max.X && r.Min.Y <= p.Y && p.Y <= r.Max.Y
+}
+
+// Distance returns the Euclidean distance between x and y. Distance
+// panics if x or y is not Positioned.
+func Distance(x, y graph.Node) float64 {
+	return dist(pos(x), pos(y))
+}
+
+// EuclideanHeuristic is a path.Heuristic returning the Euclidean distance
+// between x and y. It is admissible and consistent for graphs with edge
+// weights no less than the distance between their terminal nodes. It
+// panics if x or y is not Positioned.
+func EuclideanHeuristic(x, y graph.Node) float64 {
+	return Distance(x, y)
+}
+
+// EuclideanWeighting returns a path.Weighting that returns the Euclidean
+// distance between the terminal nodes of existing edges in g, zero for node
+// identity and Inf for otherwise absent edges. The returned Weighting panics
+// if an edge's terminal nodes are not Positioned.
+func EuclideanWeighting(g graph.Graph) path.Weighting {
+	return func(x, y graph.Node) (w float64, ok bool) {
+		if x.ID() == y.ID() {
+			return 0, true
+		}
+		if e := g.Edge(x, y); e != nil {
+			return Distance(e.From(), e.To()), true
+		}
+		return math.Inf(1), false
+	}
+}
+
+func pos(n graph.Node) layout.Point {
+	p, ok := n.(Positioned)
+	if !ok {
+		panic(fmt.Sprintf("spatial: node %d is not positioned", n.ID()))
+	}
+	return p.Pos()
+}
+
+func dist(a, b layout.Point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
@@ -0,0 +1,182 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spatial
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/layout"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+func randomNodes(n int, src *rand.Rand) []Positioned {
+	nodes := make([]Positioned, n)
+	for i := range nodes {
+		// Use a coarse grid to ensure coincident
+		// coordinates are exercised.
+		nodes[i] = NewNode(i, layout.Point{X: float64(src.Intn(20)), Y: float64(src.Intn(20))})
+	}
+	return nodes
+}
+
+func TestKDTreeNearestNode(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 10, 100, 500} {
+		nodes := randomNodes(n, src)
+		trees := []*KDTree{NewKDTree(nodes), {}}
+		for _, nd := range nodes {
+			trees[1].Insert(nd)
+		}
+		for _, tree := range trees {
+			if tree.Len() != n {
+				t.Errorf("unexpected length: got:%d want:%d", tree.Len(), n)
+			}
+			for i := 0; i < 100; i++ {
+				p := layout.Point{X: src.Float64()*24 - 2, Y: src.Float64()*24 - 2}
+				var want Positioned
+				wantDist := math.Inf(1)
+				for _, nd := range nodes {
+					d := dist(p, nd.Pos())
+					if d < wantDist || (d == wantDist && nd.ID() < want.ID()) {
+						want, wantDist = nd, d
+					}
+				}
+				got, gotDist := tree.NearestNode(p)
+				if got != want || gotDist != wantDist {
+					t.Errorf("unexpected nearest node to %v for n=%d: got:%v at %v want:%v at %v",
+						p, n, got, gotDist, want, wantDist)
+				}
+			}
+		}
+	}
+}
+
+func TestKDTreeRangeQuery(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 10, 100, 500} {
+		nodes := randomNodes(n, src)
+		tree := NewKDTree(nodes)
+		for i := 0; i < 100; i++ {
+			x0, x1 := float64(src.Intn(22)-1), float64(src.Intn(22)-1)
+			y0, y1 := float64(src.Intn(22)-1), float64(src.Intn(22)-1)
+			r := Rect{
+				Min: layout.Point{X: math.Min(x0, x1), Y: math.Min(y0, y1)},
+				Max: layout.Point{X: math.Max(x0, x1), Y: math.Max(y0, y1)},
+			}
+			var want []Positioned
+			for _, nd := range nodes {
+				if r.Contains(nd.Pos()) {
+					want = append(want, nd)
+				}
+			}
+			got := tree.RangeQuery(r)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected range query result for %v with n=%d:\ngot: %v\nwant:%v", r, n, got, want)
+			}
+		}
+	}
+}
+
+func TestIndexNodes(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	err := gen.RandomGeometric(g, 50, 0.3, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tree := IndexNodes(g)
+	if tree.Len() != 50 {
+		t.Errorf("unexpected length: got:%d want:50", tree.Len())
+	}
+	for _, n := range g.Nodes() {
+		got, d := tree.NearestNode(n.(Positioned).Pos())
+		if got.ID() != n.ID() || d != 0 {
+			t.Errorf("unexpected nearest node to node %d: got:%d at %v", n.ID(), got.ID(), d)
+		}
+	}
+
+	g.AddNode(simple.Node(50))
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		IndexNodes(g)
+		return false
+	}()
+	if !panicked {
+		t.Errorf("expected panic for unpositioned node")
+	}
+}
+
+func TestEuclideanWeighting(t *testing.T) {
+	// Nodes on a unit grid joined to their orthogonal
+	// and diagonal neighbors, with a wall of missing
+	// nodes forcing the shortest path around it.
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	const size = 10
+	id := func(x, y int) int { return x*size + y }
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if x == 5 && y < 8 {
+				continue
+			}
+			g.AddNode(NewNode(id(x, y), layout.Point{X: float64(x), Y: float64(y)}))
+		}
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.Nodes() {
+			if u.ID() < v.ID() && Distance(u, v) < 1.5 {
+				g.SetEdge(simple.Edge{F: u, T: v, W: 1})
+			}
+		}
+	}
+
+	w := EuclideanWeighting(g)
+	s, tt := g.Node(id(0, 0)), g.Node(id(9, 0))
+	if got, ok := w(s, g.Node(id(1, 1))); !ok || got != math.Sqrt2 {
+		t.Errorf("unexpected edge weight: got:%v,%t want:%v,true", got, ok, math.Sqrt2)
+	}
+	if got, ok := w(s, tt); ok || !math.IsInf(got, 1) {
+		t.Errorf("unexpected absent edge weight: got:%v,%t want:+Inf,false", got, ok)
+	}
+
+	wg := weighted{Graph: g, w: w}
+	dijkstra := path.DijkstraFrom(s, wg)
+	aStar, _ := path.AStar(s, tt, wg, EuclideanHeuristic)
+	_, want := dijkstra.To(tt)
+	_, got := aStar.To(tt)
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected A* path weight: got:%v want:%v", got, want)
+	}
+}
+
+type weighted struct {
+	graph.Graph
+	w path.Weighting
+}
+
+func (g weighted) Weight(x, y graph.Node) (float64, bool) { return g.w(x, y) }
+
+func TestHaversine(t *testing.T) {
+	for _, test := range []struct {
+		a, b layout.Point
+		want float64
+	}{
+		{a: layout.Point{X: 0, Y: 0}, b: layout.Point{X: 0, Y: 0}, want: 0},
+		{a: layout.Point{X: 0, Y: 0}, b: layout.Point{X: 180, Y: 0}, want: math.Pi * EarthRadius},
+		{a: layout.Point{X: 0, Y: 90}, b: layout.Point{X: 0, Y: -90}, want: math.Pi * EarthRadius},
+		{a: layout.Point{X: 10, Y: 0}, b: layout.Point{X: 11, Y: 0}, want: math.Pi / 180 * EarthRadius},
+		// London to Paris.
+		{a: layout.Point{X: -0.1278, Y: 51.5074}, b: layout.Point{X: 2.3522, Y: 48.8566}, want: 343.56e3},
+	} {
+		got := Haversine(test.a, test.b)
+		if math.Abs(got-test.want) > 1e-4*test.want+1e-9 {
+			t.Errorf("unexpected distance between %v and %v: got:%v want:%v", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import "strconv"
+
+// Marking is a state of a Petri net holding the number of tokens in
+// each place.
+type Marking []int
+
+// Key returns the token counts of the marking as a string.
+func (m Marking) Key() string {
+	var b []byte
+	for i, n := range m {
+		if i != 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendInt(b, int64(n), 10)
+	}
+	return string(b)
+}
+
+// PetriTransition is a transition of a Petri net.
+type PetriTransition struct {
+	Label string
+
+	// In and Out hold the number of
+	// tokens consumed from and produced
+	// into each place, keyed by place
+	// index.
+	In, Out map[int]int
+}
+
+// PetriNet is a place/transition net. It is a System whose states are
+// Markings.
+type PetriNet struct {
+	// Transitions holds the transitions
+	// of the net.
+	Transitions []PetriTransition
+
+	// Initial marking of the net.
+	Marking Marking
+}
+
+// Initial returns the initial marking of the net.
+func (n PetriNet) Initial() []State {
+	return []State{n.Marking}
+}
+
+// Successors returns the markings reached by firing each enabled
+// transition in the marking s, which must be a Marking.
+func (n PetriNet) Successors(s State) []Transition {
+	m := s.(Marking)
+	var out []Transition
+	for _, t := range n.Transitions {
+		if !n.Enabled(m, t) {
+			continue
+		}
+		next := append(Marking(nil), m...)
+		for p, k := range t.In {
+			next[p] -= k
+		}
+		for p, k := range t.Out {
+			next[p] += k
+		}
+		out = append(out, Transition{Label: t.Label, To: next})
+	}
+	return out
+}
+
+// Enabled returns whether t may fire in the marking m.
+func (n PetriNet) Enabled(m Marking, t PetriTransition) bool {
+	for p, k := range t.In {
+		if m[p] < k {
+			return false
+		}
+	}
+	return true
+}
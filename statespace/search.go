@@ -0,0 +1,212 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import (
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Path is a path through a state space.
+type Path struct {
+	// States holds the states of the
+	// path, starting at an initial state.
+	States []State
+
+	// Labels holds the label of the
+	// transition into each state after
+	// the first.
+	Labels []string
+}
+
+// Result is the result of a reachability search.
+type Result struct {
+	// Found indicates whether a goal
+	// state was reached, and Path is
+	// a shortest path to it if so.
+	Found bool
+	Path  Path
+
+	// Visited is the number of distinct
+	// states visited.
+	Visited int
+
+	// Complete indicates whether the
+	// search was not cut short by its
+	// bounds, so that a goal that was
+	// not found is unreachable.
+	Complete bool
+}
+
+// Reach performs a breadth first search of the states of s for a state
+// satisfying goal. The search visits at most maxStates distinct states and
+// follows paths of at most maxDepth transitions. A negative bound places
+// no limit on the search, in which case the state space of s must be
+// finite if the goal is unreachable.
+func Reach(s System, goal func(State) bool, maxDepth, maxStates int) Result {
+	type visit struct {
+		state  State
+		from   string
+		label  string
+		depth  int
+		source bool
+	}
+	visited := make(map[string]visit)
+	var queue []string
+	res := Result{Complete: true}
+
+	path := func(key string) Path {
+		var p Path
+		for {
+			v := visited[key]
+			p.States = append(p.States, v.state)
+			if v.source {
+				break
+			}
+			p.Labels = append(p.Labels, v.label)
+			key = v.from
+		}
+		reverseStates(p.States)
+		for i, j := 0, len(p.Labels)-1; i < j; i, j = i+1, j-1 {
+			p.Labels[i], p.Labels[j] = p.Labels[j], p.Labels[i]
+		}
+		return p
+	}
+
+	// add records a newly discovered state and reports
+	// whether the search should stop.
+	add := func(key string, v visit) bool {
+		if maxStates >= 0 && len(visited) >= maxStates {
+			res.Complete = false
+			return true
+		}
+		visited[key] = v
+		res.Visited++
+		if goal(v.state) {
+			res.Found = true
+			res.Path = path(key)
+			return true
+		}
+		queue = append(queue, key)
+		return false
+	}
+
+	for _, init := range s.Initial() {
+		k := init.Key()
+		if _, ok := visited[k]; ok {
+			continue
+		}
+		if add(k, visit{state: init, source: true}) {
+			return res
+		}
+	}
+	for len(queue) != 0 {
+		k := queue[0]
+		queue = queue[1:]
+		v := visited[k]
+		if maxDepth >= 0 && v.depth >= maxDepth {
+			if len(s.Successors(v.state)) != 0 {
+				res.Complete = false
+			}
+			continue
+		}
+		for _, t := range s.Successors(v.state) {
+			nk := t.To.Key()
+			if _, ok := visited[nk]; ok {
+				continue
+			}
+			if add(nk, visit{state: t.To, from: k, label: t.Label, depth: v.depth + 1}) {
+				return res
+			}
+		}
+	}
+	return res
+}
+
+// Space is an explored state space held as a directed graph. Node IDs are
+// assigned to states in the order they are discovered.
+type Space struct {
+	*simple.DirectedGraph
+
+	states []State
+	id     map[string]int
+}
+
+// State returns the state with node ID id.
+func (s *Space) State(id int) State { return s.states[id] }
+
+// ID returns the node ID of the state st, and whether st is in the space.
+func (s *Space) ID(st State) (id int, ok bool) {
+	id, ok = s.id[st.Key()]
+	return id, ok
+}
+
+// Edge is an edge of a Space, holding the labels of all transitions
+// between its terminal states.
+type Edge struct {
+	F, T   graph.Node
+	Labels []string
+}
+
+// From returns the from-node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// Weight returns 1.
+func (Edge) Weight() float64 { return 1 }
+
+// Explore returns the state space of s reachable from its initial states,
+// visiting at most maxStates states, and whether the whole reachable space
+// was explored. If maxStates is negative, the state space of s must be
+// finite. Self transitions are not represented in the returned graph.
+func Explore(s System, maxStates int) (space *Space, complete bool) {
+	space = &Space{
+		DirectedGraph: simple.NewDirectedGraph(0, 0),
+		id:            make(map[string]int),
+	}
+	complete = true
+	add := func(st State) (int, bool) {
+		k := st.Key()
+		if id, ok := space.id[k]; ok {
+			return id, true
+		}
+		if maxStates >= 0 && len(space.states) >= maxStates {
+			complete = false
+			return -1, false
+		}
+		id := len(space.states)
+		space.id[k] = id
+		space.states = append(space.states, st)
+		space.AddNode(simple.Node(id))
+		return id, true
+	}
+
+	for _, init := range s.Initial() {
+		add(init)
+	}
+	for i := 0; i < len(space.states); i++ {
+		for _, t := range s.Successors(space.states[i]) {
+			j, ok := add(t.To)
+			if !ok || i == j {
+				continue
+			}
+			u, v := simple.Node(i), simple.Node(j)
+			var labels []string
+			if e, ok := space.Edge(u, v).(Edge); ok {
+				labels = e.Labels
+			}
+			space.SetEdge(Edge{F: u, T: v, Labels: append(labels, t.Label)})
+		}
+	}
+	return space, complete
+}
+
+func reverseStates(s []State) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
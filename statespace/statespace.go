@@ -0,0 +1,139 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package statespace provides exploration of implicitly defined state spaces
+// such as labelled transition systems and Petri nets.
+//
+// A System generates its states on demand, so state spaces are only expanded
+// as far as a search requires. States are identified by their keys, which
+// are used to detect previously visited states.
+package statespace
+
+import (
+	"sort"
+	"strconv"
+)
+
+// State is a state of a transition system.
+type State interface {
+	// Key returns a string that is equal
+	// for two states if and only if the
+	// states are equal.
+	Key() string
+}
+
+// Transition is a labelled transition to a state.
+type Transition struct {
+	Label string
+	To    State
+}
+
+// System is a labelled transition system.
+type System interface {
+	// Initial returns the initial states
+	// of the system.
+	Initial() []State
+
+	// Successors returns the transitions
+	// leaving the state s.
+	Successors(s State) []Transition
+}
+
+// Tuple is a state of a product system holding a state of each component.
+type Tuple []State
+
+// Key returns a key formed from the keys of the component states.
+func (t Tuple) Key() string {
+	var b []byte
+	for _, s := range t {
+		k := s.Key()
+		b = strconv.AppendInt(b, int64(len(k)), 10)
+		b = append(b, ':')
+		b = append(b, k...)
+	}
+	return string(b)
+}
+
+// Product returns the parallel composition of the given systems. The states
+// of the product are Tuples. Transitions with labels for which sync returns
+// true are taken jointly by all components, and are blocked unless every
+// component can take a transition with that label. Other transitions are
+// taken by one component alone. If sync is nil, no labels are synchronised.
+//
+// The product is constructed on the fly as its states are explored.
+func Product(sync func(label string) bool, systems ...System) System {
+	if sync == nil {
+		sync = func(string) bool { return false }
+	}
+	return product{sync: sync, systems: systems}
+}
+
+type product struct {
+	sync    func(string) bool
+	systems []System
+}
+
+func (p product) Initial() []State {
+	states := []State{Tuple(nil)}
+	for _, sys := range p.systems {
+		var next []State
+		for _, s := range states {
+			for _, c := range sys.Initial() {
+				next = append(next, appendTuple(s.(Tuple), c))
+			}
+		}
+		states = next
+	}
+	return states
+}
+
+func (p product) Successors(s State) []Transition {
+	t := s.(Tuple)
+	var out []Transition
+
+	// synced holds for each synchronised label
+	// the successor states of each component.
+	synced := make(map[string][][]State)
+	for i, sys := range p.systems {
+		for _, tr := range sys.Successors(t[i]) {
+			if !p.sync(tr.Label) {
+				next := append(Tuple(nil), t...)
+				next[i] = tr.To
+				out = append(out, Transition{Label: tr.Label, To: next})
+				continue
+			}
+			if synced[tr.Label] == nil {
+				synced[tr.Label] = make([][]State, len(p.systems))
+			}
+			synced[tr.Label][i] = append(synced[tr.Label][i], tr.To)
+		}
+	}
+
+	labels := make([]string, 0, len(synced))
+	for l := range synced {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		states := []Tuple{nil}
+		for _, choices := range synced[l] {
+			var next []Tuple
+			for _, s := range states {
+				for _, c := range choices {
+					next = append(next, appendTuple(s, c))
+				}
+			}
+			states = next
+		}
+		for _, s := range states {
+			out = append(out, Transition{Label: l, To: s})
+		}
+	}
+	return out
+}
+
+// appendTuple returns a copy of t with s appended.
+func appendTuple(t Tuple, s State) Tuple {
+	return append(append(make(Tuple, 0, len(t)+1), t...), s)
+}
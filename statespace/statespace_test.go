@@ -0,0 +1,155 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package statespace
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+// name is a named state.
+type name string
+
+func (n name) Key() string { return string(n) }
+
+// machine is a finite state machine starting in state "0".
+type machine map[name][]Transition
+
+func (m machine) Initial() []State { return []State{name("0")} }
+func (m machine) Successors(s State) []Transition {
+	return m[s.(name)]
+}
+
+// counter is an infinite state system.
+type counter int
+
+func (c counter) Key() string { return strconv.Itoa(int(c)) }
+
+type counting struct{}
+
+func (counting) Initial() []State { return []State{counter(0)} }
+func (counting) Successors(s State) []Transition {
+	return []Transition{{Label: "inc", To: s.(counter) + 1}}
+}
+
+// mutex is a Petri net of two processes sharing a lock.
+var mutex = PetriNet{
+	Transitions: []PetriTransition{
+		{Label: "enter1", In: map[int]int{0: 1, 4: 1}, Out: map[int]int{1: 1}},
+		{Label: "exit1", In: map[int]int{1: 1}, Out: map[int]int{0: 1, 4: 1}},
+		{Label: "enter2", In: map[int]int{2: 1, 4: 1}, Out: map[int]int{3: 1}},
+		{Label: "exit2", In: map[int]int{3: 1}, Out: map[int]int{2: 1, 4: 1}},
+	},
+	Marking: Marking{1, 0, 1, 0, 1},
+}
+
+func TestReachPetriNet(t *testing.T) {
+	res := Reach(mutex, func(s State) bool {
+		m := s.(Marking)
+		return m[1] > 0 && m[3] > 0
+	}, -1, -1)
+	if res.Found || !res.Complete || res.Visited != 3 {
+		t.Errorf("unexpected mutual exclusion result: %+v", res)
+	}
+
+	res = Reach(mutex, func(s State) bool { return s.(Marking)[3] > 0 }, -1, -1)
+	if !res.Found {
+		t.Fatal("expected to reach critical section")
+	}
+	if got, want := res.Path.Labels, []string{"enter2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected path labels: got:%v want:%v", got, want)
+	}
+	if got, want := res.Path.States[1].Key(), "1,0,0,1,0"; got != want {
+		t.Errorf("unexpected goal marking: got:%s want:%s", got, want)
+	}
+}
+
+func TestReachBounds(t *testing.T) {
+	five := func(s State) bool { return s.(counter) == 5 }
+
+	res := Reach(counting{}, five, -1, -1)
+	if !res.Found || len(res.Path.States) != 6 || len(res.Path.Labels) != 5 {
+		t.Errorf("unexpected unbounded result: %+v", res)
+	}
+	res = Reach(counting{}, five, 3, -1)
+	if res.Found || res.Complete || res.Visited != 4 {
+		t.Errorf("unexpected depth bounded result: %+v", res)
+	}
+	res = Reach(counting{}, five, -1, 3)
+	if res.Found || res.Complete || res.Visited != 3 {
+		t.Errorf("unexpected state bounded result: %+v", res)
+	}
+}
+
+func TestProduct(t *testing.T) {
+	a := machine{
+		"0": {{Label: "a", To: name("1")}},
+		"1": {{Label: "b", To: name("0")}},
+	}
+	b := machine{
+		"0": {{Label: "a", To: name("1")}},
+		"1": {{Label: "c", To: name("0")}},
+	}
+	p := Product(func(l string) bool { return l == "a" }, a, b)
+
+	space, complete := Explore(p, -1)
+	if !complete {
+		t.Error("expected complete exploration")
+	}
+	if n := len(space.Nodes()); n != 4 {
+		t.Errorf("unexpected number of product states: got:%d want:4", n)
+	}
+
+	id := func(x, y string) int {
+		i, ok := space.ID(Tuple{name(x), name(y)})
+		if !ok {
+			t.Fatalf("missing product state (%s,%s)", x, y)
+		}
+		return i
+	}
+	for _, e := range []struct {
+		from, to [2]string
+		label    string
+	}{
+		{from: [2]string{"0", "0"}, to: [2]string{"1", "1"}, label: "a"},
+		{from: [2]string{"1", "1"}, to: [2]string{"0", "1"}, label: "b"},
+		{from: [2]string{"1", "1"}, to: [2]string{"1", "0"}, label: "c"},
+		{from: [2]string{"0", "1"}, to: [2]string{"0", "0"}, label: "c"},
+		{from: [2]string{"1", "0"}, to: [2]string{"0", "0"}, label: "b"},
+	} {
+		u, v := simple.Node(id(e.from[0], e.from[1])), simple.Node(id(e.to[0], e.to[1]))
+		edge, ok := space.Edge(u, v).(Edge)
+		if !ok {
+			t.Errorf("missing transition %v -> %v", e.from, e.to)
+			continue
+		}
+		if !reflect.DeepEqual(edge.Labels, []string{e.label}) {
+			t.Errorf("unexpected labels for %v -> %v: got:%v want:[%s]", e.from, e.to, edge.Labels, e.label)
+		}
+	}
+	if s := space.State(id("0", "1")); s.Key() != (Tuple{name("0"), name("1")}).Key() {
+		t.Errorf("unexpected state for ID: %v", s)
+	}
+
+	// Without synchronisation the components interleave.
+	space, _ = Explore(Product(nil, a, b), -1)
+	if n := len(space.Nodes()); n != 4 {
+		t.Errorf("unexpected number of interleaved states: got:%d want:4", n)
+	}
+	if _, complete := Explore(counting{}, 10); complete {
+		t.Error("expected incomplete exploration of infinite system")
+	}
+}
+
+func TestTupleKey(t *testing.T) {
+	a := Tuple{name("a,"), name("b")}
+	b := Tuple{name("a"), name(",b")}
+	if a.Key() == b.Key() {
+		t.Errorf("distinct tuples share key %q", a.Key())
+	}
+}
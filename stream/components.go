@@ -0,0 +1,117 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+)
+
+// Components maintains the connected components of the nodes seen in an
+// edge stream using a disjoint set forest. It holds state for each node but
+// not for each edge.
+type Components struct {
+	parent map[int]int
+	size   map[int]int
+	count  int
+}
+
+// NewComponents returns an empty Components.
+func NewComponents() *Components {
+	return &Components{parent: make(map[int]int), size: make(map[int]int)}
+}
+
+// Add joins the components of the end points of e.
+func (c *Components) Add(e graph.Edge) {
+	c.union(c.add(e.From().ID()), c.add(e.To().ID()))
+}
+
+// AddNode adds n to the components as an isolated node if it has not
+// already been seen.
+func (c *Components) AddNode(n graph.Node) {
+	c.add(n.ID())
+}
+
+// Count returns the number of components.
+func (c *Components) Count() int { return c.count }
+
+// Nodes returns the number of nodes seen.
+func (c *Components) Nodes() int { return len(c.parent) }
+
+// Connected returns whether the nodes with IDs u and v are in the same
+// component. Nodes that have not been seen are not connected to any node.
+func (c *Components) Connected(u, v int) bool {
+	if _, ok := c.parent[u]; !ok {
+		return false
+	}
+	if _, ok := c.parent[v]; !ok {
+		return false
+	}
+	return c.find(u) == c.find(v)
+}
+
+// Size returns the number of nodes in the component holding the node with
+// ID id, or zero if the node has not been seen.
+func (c *Components) Size(id int) int {
+	if _, ok := c.parent[id]; !ok {
+		return 0
+	}
+	return c.size[c.find(id)]
+}
+
+// Sets returns the node IDs in each component. The IDs of each component are
+// sorted and the components are ordered by their lowest ID.
+func (c *Components) Sets() [][]int {
+	index := make(map[int]int)
+	var sets [][]int
+	ids := make([]int, 0, len(c.parent))
+	for id := range c.parent {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		r := c.find(id)
+		i, ok := index[r]
+		if !ok {
+			i = len(sets)
+			index[r] = i
+			sets = append(sets, nil)
+		}
+		sets[i] = append(sets[i], id)
+	}
+	return sets
+}
+
+func (c *Components) add(id int) int {
+	if _, ok := c.parent[id]; !ok {
+		c.parent[id] = id
+		c.size[id] = 1
+		c.count++
+	}
+	return id
+}
+
+func (c *Components) find(x int) int {
+	for c.parent[x] != x {
+		c.parent[x] = c.parent[c.parent[x]]
+		x = c.parent[x]
+	}
+	return x
+}
+
+func (c *Components) union(x, y int) {
+	x, y = c.find(x), c.find(y)
+	if x == y {
+		return
+	}
+	if c.size[x] < c.size[y] {
+		x, y = y, x
+	}
+	c.parent[y] = x
+	c.size[x] += c.size[y]
+	delete(c.size, y)
+	c.count--
+}
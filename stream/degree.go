@@ -0,0 +1,82 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+)
+
+// Degrees maintains the degree of each node seen in an edge stream and
+// running sums of the first and second powers of the degrees. It holds
+// state for each node but not for each edge. A self loop adds two to the
+// degree of its node.
+type Degrees struct {
+	deg    map[int]int
+	edges  int
+	s1, s2 float64
+}
+
+// NewDegrees returns an empty Degrees.
+func NewDegrees() *Degrees {
+	return &Degrees{deg: make(map[int]int)}
+}
+
+// Add increments the degrees of the end points of e.
+func (d *Degrees) Add(e graph.Edge) {
+	d.edges++
+	d.inc(e.From().ID())
+	d.inc(e.To().ID())
+}
+
+func (d *Degrees) inc(id int) {
+	k := float64(d.deg[id])
+	d.deg[id]++
+	d.s1++
+	d.s2 += 2*k + 1
+}
+
+// Degree returns the degree of the node with ID id.
+func (d *Degrees) Degree(id int) int { return d.deg[id] }
+
+// Nodes returns the number of nodes seen.
+func (d *Degrees) Nodes() int { return len(d.deg) }
+
+// Edges returns the number of edges seen.
+func (d *Degrees) Edges() int { return d.edges }
+
+// Mean returns the mean degree of the nodes seen, or NaN if no node has
+// been seen.
+func (d *Degrees) Mean() float64 {
+	return d.s1 / float64(len(d.deg))
+}
+
+// Variance returns the population variance of the degrees of the nodes
+// seen, or NaN if no node has been seen.
+func (d *Degrees) Variance() float64 {
+	n := float64(len(d.deg))
+	mean := d.s1 / n
+	return math.Max(d.s2/n-mean*mean, 0)
+}
+
+// Moment returns the kth raw moment of the degrees of the nodes seen, the
+// mean of the kth powers of the degrees, or NaN if no node has been seen.
+// The first and second moments are held as running sums and other moments
+// are computed from the held degrees.
+func (d *Degrees) Moment(k float64) float64 {
+	n := float64(len(d.deg))
+	switch k {
+	case 1:
+		return d.s1 / n
+	case 2:
+		return d.s2 / n
+	}
+	var s float64
+	for _, deg := range d.deg {
+		s += math.Pow(float64(deg), k)
+	}
+	return s / n
+}
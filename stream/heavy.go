@@ -0,0 +1,129 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/gonum/graph"
+)
+
+// HeavyHitters finds the nodes of highest degree in an edge stream using the
+// Space-Saving algorithm of Metwally, Agrawal and El Abbadi,
+// doi:10.1007/978-3-540-30570-5_27. At most k nodes are counted at any time.
+// Every node with a degree greater than the number of edge end points seen
+// divided by k is guaranteed to be counted.
+type HeavyHitters struct {
+	k       int
+	total   int
+	index   map[int]int
+	counted hitterHeap
+}
+
+// Hitter is a node counted by HeavyHitters.
+type Hitter struct {
+	// Node is the counted node.
+	Node graph.Node
+
+	// Count is an upper bound on the
+	// degree of Node. The degree is at
+	// least Count-Error.
+	Count int
+	Error int
+}
+
+// NewHeavyHitters returns a HeavyHitters counting at most k nodes.
+// NewHeavyHitters will panic if k is not positive.
+func NewHeavyHitters(k int) *HeavyHitters {
+	if k <= 0 {
+		panic("stream: non-positive heavy hitter capacity")
+	}
+	h := &HeavyHitters{k: k, index: make(map[int]int, k)}
+	h.counted.index = h.index
+	return h
+}
+
+// Add counts the end points of e.
+func (h *HeavyHitters) Add(e graph.Edge) {
+	h.offer(e.From())
+	h.offer(e.To())
+}
+
+func (h *HeavyHitters) offer(n graph.Node) {
+	h.total++
+	if i, ok := h.index[n.ID()]; ok {
+		h.counted.hitters[i].Count++
+		heap.Fix(&h.counted, i)
+		return
+	}
+	if len(h.counted.hitters) < h.k {
+		heap.Push(&h.counted, Hitter{Node: n, Count: 1})
+		return
+	}
+
+	// Replace the node with the
+	// lowest count.
+	min := h.counted.hitters[0]
+	delete(h.index, min.Node.ID())
+	h.counted.hitters[0] = Hitter{Node: n, Count: min.Count + 1, Error: min.Count}
+	h.index[n.ID()] = 0
+	heap.Fix(&h.counted, 0)
+}
+
+// Top returns the counted nodes whose degree may exceed the given fraction
+// of the number of edge end points seen, ordered by decreasing Count and
+// then by increasing ID.
+func (h *HeavyHitters) Top(fraction float64) []Hitter {
+	threshold := fraction * float64(h.total)
+	var top []Hitter
+	for _, c := range h.counted.hitters {
+		if float64(c.Count) > threshold {
+			top = append(top, c)
+		}
+	}
+	sort.Sort(byCount(top))
+	return top
+}
+
+type byCount []Hitter
+
+func (h byCount) Len() int { return len(h) }
+func (h byCount) Less(i, j int) bool {
+	if h[i].Count != h[j].Count {
+		return h[i].Count > h[j].Count
+	}
+	return h[i].Node.ID() < h[j].Node.ID()
+}
+func (h byCount) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+// hitterHeap is a min-heap of counted nodes that maintains
+// the position of each node in index.
+type hitterHeap struct {
+	hitters []Hitter
+	index   map[int]int
+}
+
+func (h *hitterHeap) Len() int { return len(h.hitters) }
+func (h *hitterHeap) Less(i, j int) bool {
+	return h.hitters[i].Count < h.hitters[j].Count
+}
+func (h *hitterHeap) Swap(i, j int) {
+	h.hitters[i], h.hitters[j] = h.hitters[j], h.hitters[i]
+	h.index[h.hitters[i].Node.ID()] = i
+	h.index[h.hitters[j].Node.ID()] = j
+}
+func (h *hitterHeap) Push(x interface{}) {
+	c := x.(Hitter)
+	h.index[c.Node.ID()] = len(h.hitters)
+	h.hitters = append(h.hitters, c)
+}
+func (h *hitterHeap) Pop() interface{} {
+	n := len(h.hitters) - 1
+	c := h.hitters[n]
+	delete(h.index, c.Node.ID())
+	h.hitters = h.hitters[:n]
+	return c
+}
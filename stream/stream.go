@@ -0,0 +1,110 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package stream provides one-pass algorithms over edge streams.
+//
+// The algorithms see each edge of a graph once, in the order it is delivered
+// by a Stream, and never hold the set of edges, so they may be used to
+// summarize graphs that are too large to materialize or that arrive over
+// time. Some of the algorithms hold state for each node and the others hold
+// a bounded amount of state chosen when they are created. A Window holds
+// only the most recent edges of a stream, as a graph. The edges of a
+// stream are treated as undirected: an edge contributes to the degree of both
+// its end points and components are weakly connected.
+package stream
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Stream is a source of edges.
+type Stream interface {
+	// Next returns the next edge of the
+	// stream, or false if the stream is
+	// exhausted.
+	Next() (graph.Edge, bool)
+}
+
+// Sink is a one-pass edge stream algorithm.
+type Sink interface {
+	// Add adds e to the summary of
+	// the stream held by the Sink.
+	Add(e graph.Edge)
+}
+
+// Run passes each edge of s to each of the sinks in turn until s is exhausted
+// and returns the number of edges read.
+func Run(s Stream, sinks ...Sink) int {
+	var n int
+	for {
+		e, ok := s.Next()
+		if !ok {
+			return n
+		}
+		n++
+		for _, k := range sinks {
+			k.Add(e)
+		}
+	}
+}
+
+// Edges returns a Stream of the edges in the slice.
+func Edges(edges []graph.Edge) Stream {
+	return &sliceStream{edges: edges}
+}
+
+type sliceStream struct {
+	edges []graph.Edge
+}
+
+func (s *sliceStream) Next() (graph.Edge, bool) {
+	if len(s.edges) == 0 {
+		return nil, false
+	}
+	e := s.edges[0]
+	s.edges = s.edges[1:]
+	return e, true
+}
+
+// FromGraph returns a Stream of the edges of g ordered by the IDs of their
+// end points. The edges of an undirected graph are delivered once. The edges
+// leaving each node are only retrieved from g when they are reached.
+func FromGraph(g graph.Graph) Stream {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	_, isDirected := g.(graph.Directed)
+	return &graphStream{g: g, directed: isDirected, nodes: nodes}
+}
+
+type graphStream struct {
+	g        graph.Graph
+	directed bool
+
+	nodes []graph.Node
+	u     graph.Node
+	to    []graph.Node
+}
+
+func (s *graphStream) Next() (graph.Edge, bool) {
+	for {
+		for len(s.to) != 0 {
+			v := s.to[0]
+			s.to = s.to[1:]
+			if !s.directed && v.ID() < s.u.ID() {
+				continue
+			}
+			return s.g.Edge(s.u, v), true
+		}
+		if len(s.nodes) == 0 {
+			return nil, false
+		}
+		s.u = s.nodes[0]
+		s.nodes = s.nodes[1:]
+		s.to = s.g.From(s.u)
+		sort.Sort(ordered.ByID(s.to))
+	}
+}
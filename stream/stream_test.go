@@ -0,0 +1,235 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func gnp(t *testing.T, n int, p float64, seed int64) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	err := gen.Gnp(g, n, p, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		t.Fatalf("failed to generate graph: %v", err)
+	}
+	return g
+}
+
+func TestFromGraph(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(2), T: simple.Node(0)},
+		{F: simple.Node(0), T: simple.Node(1)},
+		{F: simple.Node(1), T: simple.Node(2)},
+		{F: simple.Node(3), T: simple.Node(1)},
+	} {
+		g.SetEdge(e)
+	}
+	var got [][2]int
+	s := FromGraph(g)
+	for {
+		e, ok := s.Next()
+		if !ok {
+			break
+		}
+		u, v := e.From().ID(), e.To().ID()
+		if v < u {
+			u, v = v, u
+		}
+		got = append(got, [2]int{u, v})
+	}
+	want := [][2]int{{0, 1}, {0, 2}, {1, 2}, {1, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected undirected stream: got:%v want:%v", got, want)
+	}
+
+	d := simple.NewDirectedGraph(0, math.Inf(1))
+	d.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	d.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	if n := Run(FromGraph(d)); n != 2 {
+		t.Errorf("unexpected number of directed edges: got:%d want:2", n)
+	}
+}
+
+func TestRun(t *testing.T) {
+	g := gnp(t, 200, 0.05, 1)
+	c := NewComponents()
+	d := NewDegrees()
+	n := Run(FromGraph(g), c, d)
+	if n != len(g.Edges()) {
+		t.Errorf("unexpected number of edges read: got:%d want:%d", n, len(g.Edges()))
+	}
+	if d.Edges() != n {
+		t.Errorf("unexpected number of edges counted: got:%d want:%d", d.Edges(), n)
+	}
+	if n := Run(Edges(nil), c); n != 0 {
+		t.Errorf("unexpected number of edges read from empty stream: got:%d", n)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	c := NewComponents()
+	Run(Edges([]graph.Edge{
+		simple.Edge{F: simple.Node(0), T: simple.Node(1)},
+		simple.Edge{F: simple.Node(2), T: simple.Node(3)},
+		simple.Edge{F: simple.Node(4), T: simple.Node(3)},
+		simple.Edge{F: simple.Node(1), T: simple.Node(0)},
+	}), c)
+	c.AddNode(simple.Node(5))
+	c.AddNode(simple.Node(0))
+
+	if c.Count() != 3 {
+		t.Errorf("unexpected number of components: got:%d want:3", c.Count())
+	}
+	if c.Nodes() != 6 {
+		t.Errorf("unexpected number of nodes: got:%d want:6", c.Nodes())
+	}
+	want := [][]int{{0, 1}, {2, 3, 4}, {5}}
+	if got := c.Sets(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected components: got:%v want:%v", got, want)
+	}
+	if !c.Connected(2, 4) {
+		t.Error("expected nodes 2 and 4 to be connected")
+	}
+	if c.Connected(0, 2) || c.Connected(0, 6) {
+		t.Error("unexpected connection")
+	}
+	if c.Size(3) != 3 || c.Size(6) != 0 {
+		t.Errorf("unexpected component sizes: got:%d,%d want:3,0", c.Size(3), c.Size(6))
+	}
+}
+
+func TestDegrees(t *testing.T) {
+	g := gnp(t, 200, 0.05, 1)
+	d := NewDegrees()
+	Run(FromGraph(g), d)
+
+	var s1, s2, s3 float64
+	var n int
+	for _, u := range g.Nodes() {
+		k := float64(len(g.From(u)))
+		if k == 0 {
+			// Isolated nodes are not
+			// seen in the stream.
+			continue
+		}
+		if d.Degree(u.ID()) != int(k) {
+			t.Errorf("unexpected degree for node %d: got:%d want:%v", u.ID(), d.Degree(u.ID()), k)
+		}
+		n++
+		s1 += k
+		s2 += k * k
+		s3 += k * k * k
+	}
+	if d.Nodes() != n {
+		t.Errorf("unexpected number of nodes: got:%d want:%d", d.Nodes(), n)
+	}
+	mean := s1 / float64(n)
+	for _, test := range []struct {
+		name      string
+		got, want float64
+	}{
+		{name: "mean", got: d.Mean(), want: mean},
+		{name: "variance", got: d.Variance(), want: s2/float64(n) - mean*mean},
+		{name: "second moment", got: d.Moment(2), want: s2 / float64(n)},
+		{name: "third moment", got: d.Moment(3), want: s3 / float64(n)},
+	} {
+		if math.Abs(test.got-test.want) > 1e-9*math.Abs(test.want) {
+			t.Errorf("unexpected %s: got:%v want:%v", test.name, test.got, test.want)
+		}
+	}
+	if !math.IsNaN(NewDegrees().Mean()) {
+		t.Error("expected NaN mean for empty stream")
+	}
+}
+
+// triangles returns the number of triangles in g.
+func triangles(g *simple.UndirectedGraph) int {
+	var n int
+	for _, e := range g.Edges() {
+		u, v := e.From(), e.To()
+		for _, w := range g.From(u) {
+			if w.ID() > u.ID() && w.ID() > v.ID() && g.HasEdgeBetween(w, v) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestTriangles(t *testing.T) {
+	g := gnp(t, 100, 0.2, 1)
+	want := triangles(g)
+	m := len(g.Edges())
+
+	exact := NewTriangles(m, rand.New(rand.NewSource(1)))
+	Run(FromGraph(g), exact)
+	if got := exact.Estimate(); got != float64(want) {
+		t.Errorf("unexpected exact triangle count: got:%v want:%d", got, want)
+	}
+
+	// The estimate is unbiased, so the mean
+	// of independent estimates should be
+	// close to the true count.
+	const trials = 50
+	src := rand.New(rand.NewSource(1))
+	var sum float64
+	for i := 0; i < trials; i++ {
+		tri := NewTriangles(m/2, src)
+		Run(FromGraph(g), tri)
+		if tri.Edges() != m {
+			t.Fatalf("unexpected number of edges seen: got:%d want:%d", tri.Edges(), m)
+		}
+		sum += tri.Estimate()
+	}
+	if mean := sum / trials; math.Abs(mean-float64(want)) > 0.05*float64(want) {
+		t.Errorf("unexpected mean triangle estimate: got:%v want:%d", mean, want)
+	}
+}
+
+func TestHeavyHitters(t *testing.T) {
+	// Three hubs are each joined to many
+	// leaves in a stream with a long tail
+	// of low degree nodes.
+	var edges []graph.Edge
+	leaf := 100
+	for i := 0; i < 200; i++ {
+		for hub, deg := range []int{60, 40, 30} {
+			if i < deg {
+				edges = append(edges, simple.Edge{F: simple.Node(hub), T: simple.Node(leaf)})
+				leaf++
+			}
+		}
+		edges = append(edges, simple.Edge{F: simple.Node(leaf), T: simple.Node(leaf + 1)})
+		leaf += 2
+	}
+	h := NewHeavyHitters(50)
+	Run(Edges(edges), h)
+
+	top := h.Top(0.04)
+	if len(top) < 3 {
+		t.Fatalf("too few heavy hitters: got:%d want:>=3", len(top))
+	}
+	for i, want := range []struct{ id, deg int }{{0, 60}, {1, 40}, {2, 30}} {
+		got := top[i]
+		if got.Node.ID() != want.id {
+			t.Errorf("unexpected heavy hitter %d: got:%d want:%d", i, got.Node.ID(), want.id)
+		}
+		if got.Count-got.Error > want.deg || want.deg > got.Count {
+			t.Errorf("degree bounds for node %d do not hold: count:%d error:%d degree:%d",
+				want.id, got.Count, got.Error, want.deg)
+		}
+	}
+}
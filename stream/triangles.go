@@ -0,0 +1,149 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/gonum/graph"
+)
+
+// Triangles estimates the number of triangles in an edge stream using the
+// TRIÈST-BASE algorithm of De Stefani, Epasto, Riondato and Upfal,
+// doi:10.1145/2939672.2939771. A uniform reservoir sample of at most m edges
+// is held and the triangles closed by each sampled edge are counted. The
+// count is exact while no more than m edges have been seen. The stream must
+// not repeat an edge; self loops are ignored.
+type Triangles struct {
+	m   int
+	rnd func() float64
+	n   func(int) int
+
+	// seen is the number of edges seen and
+	// tau is the number of triangles in the
+	// sample.
+	seen int
+	tau  int
+
+	sample []pair
+	index  map[pair]int
+	adj    map[int]map[int]struct{}
+}
+
+// pair is an unordered pair of node IDs, held with the lower ID first.
+type pair struct{ u, v int }
+
+func newPair(u, v int) pair {
+	if v < u {
+		u, v = v, u
+	}
+	return pair{u: u, v: v}
+}
+
+// NewTriangles returns a Triangles holding a sample of at most m edges. If
+// src is not nil it is used as the random source, otherwise rand.Float64 and
+// rand.Intn are used. NewTriangles will panic if m is less than three.
+func NewTriangles(m int, src *rand.Rand) *Triangles {
+	if m < 3 {
+		panic("stream: triangle sample too small")
+	}
+	t := &Triangles{
+		m:     m,
+		rnd:   rand.Float64,
+		n:     rand.Intn,
+		index: make(map[pair]int),
+		adj:   make(map[int]map[int]struct{}),
+	}
+	if src != nil {
+		t.rnd = src.Float64
+		t.n = src.Intn
+	}
+	return t
+}
+
+// Add adds e to the stream.
+func (t *Triangles) Add(e graph.Edge) {
+	u, v := e.From().ID(), e.To().ID()
+	if u == v {
+		return
+	}
+	t.seen++
+	p := newPair(u, v)
+	if len(t.sample) < t.m {
+		t.insert(p)
+		return
+	}
+	if t.rnd() >= float64(t.m)/float64(t.seen) {
+		return
+	}
+	t.remove(t.sample[t.n(len(t.sample))])
+	t.insert(p)
+}
+
+// Estimate returns the estimated number of triangles in the stream.
+func (t *Triangles) Estimate() float64 {
+	if t.seen <= t.m {
+		return float64(t.tau)
+	}
+	s, m := float64(t.seen), float64(t.m)
+	xi := math.Max(1, (s/m)*((s-1)/(m-1))*((s-2)/(m-2)))
+	return xi * float64(t.tau)
+}
+
+// Edges returns the number of edges seen.
+func (t *Triangles) Edges() int { return t.seen }
+
+func (t *Triangles) insert(p pair) {
+	t.tau += t.common(p)
+	t.index[p] = len(t.sample)
+	t.sample = append(t.sample, p)
+	t.link(p.u, p.v)
+	t.link(p.v, p.u)
+}
+
+func (t *Triangles) remove(p pair) {
+	i := t.index[p]
+	last := len(t.sample) - 1
+	t.sample[i] = t.sample[last]
+	t.index[t.sample[i]] = i
+	t.sample = t.sample[:last]
+	delete(t.index, p)
+	t.unlink(p.u, p.v)
+	t.unlink(p.v, p.u)
+	t.tau -= t.common(p)
+}
+
+// common returns the number of sampled neighbors shared by the end
+// points of p.
+func (t *Triangles) common(p pair) int {
+	a, b := t.adj[p.u], t.adj[p.v]
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var n int
+	for w := range a {
+		if _, ok := b[w]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+func (t *Triangles) link(u, v int) {
+	a, ok := t.adj[u]
+	if !ok {
+		a = make(map[int]struct{})
+		t.adj[u] = a
+	}
+	a[v] = struct{}{}
+}
+
+func (t *Triangles) unlink(u, v int) {
+	delete(t.adj[u], v)
+	if len(t.adj[u]) == 0 {
+		delete(t.adj, u)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"time"
+
+	"github.com/gonum/graph"
+)
+
+// Window is an undirected graph of the most recent edges of an edge stream.
+// Edges expire from the window when they are older than the window duration
+// or when the window holds more than its maximum number of edges. The window
+// may hold more than one edge between a pair of nodes; an edge between the
+// pair remains until the last of them expires. Nodes are in the window while
+// they have an edge in the window.
+//
+// The degree of each node is maintained as edges arrive and expire. The
+// components of the window are maintained as edges arrive and are rebuilt
+// from the window, when next queried, after an expiry separates a pair of
+// nodes.
+type Window struct {
+	d   time.Duration
+	max int
+
+	// edges holds the edges of the window
+	// in order of arrival from head.
+	edges []timedEdge
+	head  int
+	now   time.Time
+
+	nodes map[int]graph.Node
+	adj   map[int]map[int]*multiEdge
+	deg   map[int]int
+
+	comp  *Components
+	stale bool
+}
+
+type timedEdge struct {
+	e graph.Edge
+	t time.Time
+}
+
+// multiEdge holds the edges between a pair of nodes in a Window.
+type multiEdge struct {
+	n      int
+	latest graph.Edge
+}
+
+// NewWindow returns an empty Window holding edges that are no older than d
+// and at most max edges. A non-positive d or max places no limit of that kind.
+// NewWindow will panic if neither limit is given.
+func NewWindow(d time.Duration, max int) *Window {
+	if d <= 0 && max <= 0 {
+		panic("stream: unbounded window")
+	}
+	return &Window{
+		d:     d,
+		max:   max,
+		nodes: make(map[int]graph.Node),
+		adj:   make(map[int]map[int]*multiEdge),
+		deg:   make(map[int]int),
+		comp:  NewComponents(),
+	}
+}
+
+// Add adds e to the window at the current time.
+func (w *Window) Add(e graph.Edge) {
+	w.AddAt(e, time.Now())
+}
+
+// AddAt adds e to the window at time t and expires the edges that are no
+// longer in the window. AddAt will panic if t is before the time of the last
+// addition or expiry.
+func (w *Window) AddAt(e graph.Edge, t time.Time) {
+	w.Expire(t)
+	w.edges = append(w.edges, timedEdge{e: e, t: t})
+
+	u, v := e.From(), e.To()
+	w.nodes[u.ID()] = u
+	w.nodes[v.ID()] = v
+	w.deg[u.ID()]++
+	w.deg[v.ID()]++
+	m, ok := w.adj[u.ID()][v.ID()]
+	if !ok {
+		m = &multiEdge{}
+		w.link(u.ID(), v.ID(), m)
+		w.link(v.ID(), u.ID(), m)
+	}
+	m.n++
+	m.latest = e
+	if !w.stale {
+		w.comp.Add(e)
+	}
+
+	if w.max > 0 && w.Len() > w.max {
+		w.expireOldest()
+	}
+}
+
+// Expire advances the time of the window to t and removes the edges that
+// are older than the window duration. Expire will panic if t is before the
+// time of the last addition or expiry.
+func (w *Window) Expire(t time.Time) {
+	if t.Before(w.now) {
+		panic("stream: window time moved backwards")
+	}
+	w.now = t
+	if w.d <= 0 {
+		return
+	}
+	cutoff := t.Add(-w.d)
+	for w.Len() != 0 && w.edges[w.head].t.Before(cutoff) {
+		w.expireOldest()
+	}
+}
+
+func (w *Window) expireOldest() {
+	e := w.edges[w.head].e
+	w.edges[w.head] = timedEdge{}
+	w.head++
+	if w.head == len(w.edges) || w.head > len(w.edges)/2 {
+		w.edges = append(w.edges[:0], w.edges[w.head:]...)
+		w.head = 0
+	}
+
+	u, v := e.From().ID(), e.To().ID()
+	m := w.adj[u][v]
+	m.n--
+	if m.n == 0 {
+		w.unlink(u, v)
+		w.unlink(v, u)
+		w.stale = true
+	}
+	w.decrement(u)
+	w.decrement(v)
+}
+
+func (w *Window) link(u, v int, m *multiEdge) {
+	a, ok := w.adj[u]
+	if !ok {
+		a = make(map[int]*multiEdge)
+		w.adj[u] = a
+	}
+	a[v] = m
+}
+
+func (w *Window) unlink(u, v int) {
+	delete(w.adj[u], v)
+}
+
+func (w *Window) decrement(id int) {
+	w.deg[id]--
+	if w.deg[id] == 0 {
+		delete(w.deg, id)
+		delete(w.adj, id)
+		delete(w.nodes, id)
+	}
+}
+
+// Len returns the number of edges in the window.
+func (w *Window) Len() int { return len(w.edges) - w.head }
+
+// Degree returns the number of edges in the window incident to the node
+// with ID id. A self loop adds two to the degree of its node.
+func (w *Window) Degree(id int) int { return w.deg[id] }
+
+// Has returns whether the node exists within the window.
+func (w *Window) Has(n graph.Node) bool {
+	_, ok := w.nodes[n.ID()]
+	return ok
+}
+
+// Nodes returns the nodes in the window.
+func (w *Window) Nodes() []graph.Node {
+	nodes := make([]graph.Node, 0, len(w.nodes))
+	for _, n := range w.nodes {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// From returns the nodes that share an edge in the window with n.
+func (w *Window) From(n graph.Node) []graph.Node {
+	a := w.adj[n.ID()]
+	if len(a) == 0 {
+		return nil
+	}
+	nodes := make([]graph.Node, 0, len(a))
+	for id := range a {
+		nodes = append(nodes, w.nodes[id])
+	}
+	return nodes
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y in
+// the window.
+func (w *Window) HasEdgeBetween(x, y graph.Node) bool {
+	_, ok := w.adj[x.ID()][y.ID()]
+	return ok
+}
+
+// Edge returns the most recent edge in the window between u and v, or nil
+// if there is no such edge.
+func (w *Window) Edge(u, v graph.Node) graph.Edge {
+	return w.EdgeBetween(u, v)
+}
+
+// EdgeBetween returns the most recent edge in the window between x and y,
+// or nil if there is no such edge.
+func (w *Window) EdgeBetween(x, y graph.Node) graph.Edge {
+	m, ok := w.adj[x.ID()][y.ID()]
+	if !ok {
+		return nil
+	}
+	return m.latest
+}
+
+// ComponentCount returns the number of connected components in the window.
+func (w *Window) ComponentCount() int {
+	return w.components().Count()
+}
+
+// Connected returns whether the nodes with IDs u and v are in the same
+// connected component of the window.
+func (w *Window) Connected(u, v int) bool {
+	return w.components().Connected(u, v)
+}
+
+// ComponentSize returns the number of nodes in the connected component of
+// the window holding the node with ID id, or zero if the node is not in the
+// window.
+func (w *Window) ComponentSize(id int) int {
+	return w.components().Size(id)
+}
+
+// Components returns the node IDs in each connected component of the window.
+// The IDs of each component are sorted and the components are ordered by
+// their lowest ID.
+func (w *Window) Components() [][]int {
+	return w.components().Sets()
+}
+
+// components returns the components of the window, rebuilding them
+// if an expiry may have separated a component.
+func (w *Window) components() *Components {
+	if !w.stale {
+		return w.comp
+	}
+	w.comp = NewComponents()
+	for u, a := range w.adj {
+		w.comp.add(u)
+		for v := range a {
+			w.comp.union(u, w.comp.add(v))
+		}
+	}
+	w.stale = false
+	return w.comp
+}
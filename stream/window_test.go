@@ -0,0 +1,131 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stream
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestWindowDuration(t *testing.T) {
+	start := time.Unix(0, 0)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	w := NewWindow(10*time.Second, 0)
+	w.AddAt(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1}, at(0))
+	w.AddAt(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 2}, at(5))
+	w.AddAt(simple.Edge{F: simple.Node(3), T: simple.Node(4), W: 3}, at(6))
+	w.AddAt(simple.Edge{F: simple.Node(2), T: simple.Node(1), W: 4}, at(8))
+
+	if w.Len() != 4 {
+		t.Errorf("unexpected window length: got:%d want:4", w.Len())
+	}
+	if got, want := w.Components(), [][]int{{0, 1, 2}, {3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected components: got:%v want:%v", got, want)
+	}
+	if w.Degree(1) != 3 {
+		t.Errorf("unexpected degree of node 1: got:%d want:3", w.Degree(1))
+	}
+	if e := w.EdgeBetween(simple.Node(1), simple.Node(2)); e == nil || e.Weight() != 4 {
+		t.Errorf("unexpected latest edge between 1 and 2: got:%v", e)
+	}
+
+	// The first edge expires, separating
+	// node 0 from the window.
+	w.Expire(at(11))
+	if w.Len() != 3 {
+		t.Errorf("unexpected window length: got:%d want:3", w.Len())
+	}
+	if w.Has(simple.Node(0)) {
+		t.Error("expected node 0 to have expired")
+	}
+	if w.Connected(0, 1) {
+		t.Error("unexpected connection to expired node")
+	}
+	if got, want := w.Components(), [][]int{{1, 2}, {3, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected components: got:%v want:%v", got, want)
+	}
+
+	// One of the two edges between 1 and 2
+	// expires, leaving them connected.
+	w.Expire(at(16))
+	if !w.HasEdgeBetween(simple.Node(1), simple.Node(2)) {
+		t.Error("expected edge between 1 and 2 to remain")
+	}
+	if w.Degree(1) != 1 || w.ComponentSize(2) != 2 {
+		t.Errorf("unexpected state: degree:%d size:%d want: degree:1 size:2", w.Degree(1), w.ComponentSize(2))
+	}
+
+	w.Expire(at(100))
+	if w.Len() != 0 || len(w.Nodes()) != 0 || w.ComponentCount() != 0 {
+		t.Errorf("expected empty window: len:%d nodes:%d components:%d", w.Len(), len(w.Nodes()), w.ComponentCount())
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		w.Expire(at(50))
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for time moving backwards")
+	}
+}
+
+func TestWindowCount(t *testing.T) {
+	const (
+		n   = 50
+		max = 40
+	)
+	src := rand.New(rand.NewSource(1))
+	var edges []graph.Edge
+	w := NewWindow(0, max)
+	for i := 0; i < 500; i++ {
+		e := simple.Edge{F: simple.Node(src.Intn(n)), T: simple.Node(src.Intn(n))}
+		edges = append(edges, e)
+		w.AddAt(e, time.Unix(int64(i), 0))
+		if w.Len() > max {
+			t.Fatalf("window too long: got:%d want:<=%d", w.Len(), max)
+		}
+		if i%25 != 0 {
+			continue
+		}
+
+		// Compare the window with a graph
+		// built from the most recent edges.
+		recent := edges
+		if len(recent) > max {
+			recent = recent[len(recent)-max:]
+		}
+		c := NewComponents()
+		d := NewDegrees()
+		Run(Edges(recent), c, d)
+		if got, want := w.Components(), c.Sets(); !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected components after %d edges: got:%v want:%v", i+1, got, want)
+		}
+		for id := 0; id < n; id++ {
+			if w.Degree(id) != d.Degree(id) {
+				t.Errorf("unexpected degree of node %d after %d edges: got:%d want:%d", id, i+1, w.Degree(id), d.Degree(id))
+			}
+		}
+		nodes := w.Nodes()
+		if len(nodes) != d.Nodes() {
+			t.Errorf("unexpected number of nodes after %d edges: got:%d want:%d", i+1, len(nodes), d.Nodes())
+		}
+		for _, u := range nodes {
+			for _, v := range w.From(u) {
+				if !w.HasEdgeBetween(v, u) {
+					t.Errorf("asymmetric edge %d-%d", u.ID(), v.ID())
+				}
+			}
+		}
+	}
+}
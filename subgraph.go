@@ -0,0 +1,60 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"golang.org/x/tools/container/intsets"
+)
+
+// Subgraph copies the subgraph of src defined by nodes into dst without first
+// clearing the destination. Nodes that are not in src are ignored. Subgraph will
+// panic if a node ID copied from src matches a node ID in the destination.
+//
+// If induced is true, the induced subgraph is copied; the nodes and all the edges
+// of src that join two of the nodes. Otherwise the edges of src with at least one
+// terminal node in nodes are copied, along with the terminal nodes of those edges,
+// so the copied subgraph spans the neighborhood of nodes.
+func Subgraph(dst Builder, src Graph, nodes []Node, induced bool) {
+	var in, added intsets.Sparse
+	for _, n := range nodes {
+		if !src.Has(n) || in.Has(n.ID()) {
+			continue
+		}
+		in.Insert(n.ID())
+		added.Insert(n.ID())
+		dst.AddNode(n)
+	}
+
+	d, isDirected := src.(Directed)
+	for _, u := range nodes {
+		if !in.Has(u.ID()) {
+			continue
+		}
+		for _, v := range src.From(u) {
+			if !in.Has(v.ID()) {
+				if induced {
+					continue
+				}
+				if added.Insert(v.ID()) {
+					dst.AddNode(v)
+				}
+			}
+			dst.SetEdge(src.Edge(u, v))
+		}
+		if induced || !isDirected {
+			continue
+		}
+		for _, v := range d.To(u) {
+			if in.Has(v.ID()) {
+				// Set when v was visited.
+				continue
+			}
+			if added.Insert(v.ID()) {
+				dst.AddNode(v)
+			}
+			dst.SetEdge(src.Edge(v, u))
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph_test
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+var subgraphTests = []struct {
+	directed bool
+	edges    []simple.Edge
+	nodes    []int
+	induced  bool
+
+	wantNodes []int
+	wantEdges [][2]int
+}{
+	{
+		directed: true,
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(0), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+			{F: simple.Node(4), T: simple.Node(5), W: 1},
+		},
+		nodes:   []int{0, 1, 2, 7},
+		induced: true,
+
+		wantNodes: []int{0, 1, 2},
+		wantEdges: [][2]int{{0, 1}, {1, 2}, {2, 0}},
+	},
+	{
+		directed: true,
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(3), T: simple.Node(1), W: 1},
+			{F: simple.Node(4), T: simple.Node(5), W: 1},
+		},
+		nodes:   []int{1},
+		induced: false,
+
+		wantNodes: []int{0, 1, 2, 3},
+		wantEdges: [][2]int{{0, 1}, {1, 2}, {3, 1}},
+	},
+	{
+		directed: false,
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+		},
+		nodes:   []int{1, 2},
+		induced: true,
+
+		wantNodes: []int{1, 2},
+		wantEdges: [][2]int{{1, 2}},
+	},
+	{
+		directed: false,
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+			{F: simple.Node(3), T: simple.Node(4), W: 1},
+		},
+		nodes:   []int{1, 2},
+		induced: false,
+
+		wantNodes: []int{0, 1, 2, 3},
+		wantEdges: [][2]int{{0, 1}, {1, 2}, {2, 3}},
+	},
+}
+
+func TestSubgraph(t *testing.T) {
+	for i, test := range subgraphTests {
+		var src, dst interface {
+			graph.Graph
+			graph.Builder
+		}
+		if test.directed {
+			src = simple.NewDirectedGraph(0, math.Inf(1))
+			dst = simple.NewDirectedGraph(0, math.Inf(1))
+		} else {
+			src = simple.NewUndirectedGraph(0, math.Inf(1))
+			dst = simple.NewUndirectedGraph(0, math.Inf(1))
+		}
+		for _, e := range test.edges {
+			src.SetEdge(e)
+		}
+		var nodes []graph.Node
+		for _, id := range test.nodes {
+			nodes = append(nodes, simple.Node(id))
+		}
+
+		graph.Subgraph(dst, src, nodes, test.induced)
+
+		var gotNodes []int
+		for _, n := range dst.Nodes() {
+			gotNodes = append(gotNodes, n.ID())
+		}
+		sort.Ints(gotNodes)
+		if !reflect.DeepEqual(gotNodes, test.wantNodes) {
+			t.Errorf("unexpected nodes for test %d: got:%v want:%v", i, gotNodes, test.wantNodes)
+		}
+
+		var edges int
+		for _, u := range dst.Nodes() {
+			edges += len(dst.From(u))
+		}
+		want := len(test.wantEdges)
+		if !test.directed {
+			want *= 2
+		}
+		if edges != want {
+			t.Errorf("unexpected number of edges for test %d: got:%d want:%d", i, edges, want)
+		}
+		for _, e := range test.wantEdges {
+			if !dst.HasEdgeBetween(simple.Node(e[0]), simple.Node(e[1])) {
+				t.Errorf("missing edge %v for test %d", e, i)
+			}
+		}
+	}
+}
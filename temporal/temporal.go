@@ -0,0 +1,306 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package temporal provides temporal networks and time-respecting path
+// algorithms.
+package temporal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// Contact is a time-respecting connection between two nodes, such as a
+// scheduled transit service. A contact leaves From at Departure and reaches
+// To at Arrival.
+type Contact struct {
+	From, To           graph.Node
+	Departure, Arrival float64
+}
+
+// Duration returns the traversal time of the contact.
+func (c Contact) Duration() float64 { return c.Arrival - c.Departure }
+
+// Network is a temporal network of contacts between nodes.
+type Network struct {
+	nodes    map[int]graph.Node
+	contacts []Contact
+}
+
+// NewNetwork returns an empty temporal network.
+func NewNetwork() *Network {
+	return &Network{nodes: make(map[int]graph.Node)}
+}
+
+// AddNode adds n to the network. It panics if the added node ID matches an
+// existing node ID.
+func (n *Network) AddNode(u graph.Node) {
+	if _, exists := n.nodes[u.ID()]; exists {
+		panic(fmt.Sprintf("temporal: node ID collision: %d", u.ID()))
+	}
+	n.nodes[u.ID()] = u
+}
+
+// AddContact adds c to the network. If the terminal nodes of c do not exist,
+// they are added. AddContact will panic if the IDs of c.From and c.To are equal
+// or if the duration of c is not positive.
+func (n *Network) AddContact(c Contact) {
+	if c.From.ID() == c.To.ID() {
+		panic("temporal: adding self contact")
+	}
+	if !(c.Arrival > c.Departure) {
+		panic("temporal: contact duration not positive")
+	}
+	if !n.Has(c.From) {
+		n.AddNode(c.From)
+	}
+	if !n.Has(c.To) {
+		n.AddNode(c.To)
+	}
+	n.contacts = append(n.contacts, c)
+}
+
+// Has returns whether the node exists within the network.
+func (n *Network) Has(u graph.Node) bool {
+	_, ok := n.nodes[u.ID()]
+	return ok
+}
+
+// Nodes returns all the nodes in the network.
+func (n *Network) Nodes() []graph.Node {
+	nodes := make([]graph.Node, 0, len(n.nodes))
+	for _, u := range n.nodes {
+		nodes = append(nodes, u)
+	}
+	return nodes
+}
+
+// Contacts returns all the contacts in the network in order of addition.
+func (n *Network) Contacts() []Contact {
+	return append([]Contact(nil), n.contacts...)
+}
+
+// Event is a node of a time-expanded graph; the presence of a network node
+// at a point in time.
+type Event struct {
+	Node graph.Node
+	Time float64
+}
+
+// TimeExpanded builds the time-expanded graph of n into dst and returns the
+// events represented by the added nodes, indexed by node ID. An event is
+// created for each departure and arrival of a contact. Each contact becomes
+// an edge from its departure event to its arrival event and consecutive
+// events at the same network node are joined by waiting edges. Edge weights
+// are the elapsed time, so shortest paths in dst are shortest duration
+// journeys. The nodes added to dst are simple.Node values with IDs from zero,
+// so dst should be empty.
+func TimeExpanded(dst graph.DirectedBuilder, n *Network) []Event {
+	type key struct {
+		id   int
+		time float64
+	}
+	index := make(map[key]int)
+	var events []Event
+	event := func(u graph.Node, t float64) int {
+		k := key{id: u.ID(), time: t}
+		i, ok := index[k]
+		if !ok {
+			i = len(events)
+			index[k] = i
+			events = append(events, Event{Node: u, Time: t})
+			dst.AddNode(simple.Node(i))
+		}
+		return i
+	}
+
+	nodes := n.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	contacts := n.Contacts()
+	sort.Stable(byDeparture(contacts))
+	for _, c := range contacts {
+		dep := event(c.From, c.Departure)
+		arr := event(c.To, c.Arrival)
+		dst.SetEdge(simple.Edge{F: simple.Node(dep), T: simple.Node(arr), W: c.Duration()})
+	}
+
+	atNode := make(map[int][]int)
+	for i, e := range events {
+		atNode[e.Node.ID()] = append(atNode[e.Node.ID()], i)
+	}
+	for _, u := range nodes {
+		at := atNode[u.ID()]
+		sort.Sort(byTime{at: at, events: events})
+		for i := 1; i < len(at); i++ {
+			w := events[at[i]].Time - events[at[i-1]].Time
+			dst.SetEdge(simple.Edge{F: simple.Node(at[i-1]), T: simple.Node(at[i]), W: w})
+		}
+	}
+	return events
+}
+
+// EarliestArrival returns the earliest arrival times at the nodes of n for
+// journeys leaving src no earlier than start and arriving no later than end,
+// keyed by node ID. The arrival time of src is start. Nodes that cannot be
+// reached are not included. The returned via map holds the final contact of
+// the earliest arriving journey to each reached node other than src, so the
+// journeys may be reconstructed by following contacts back to src.
+func EarliestArrival(n *Network, src graph.Node, start, end float64) (arrival map[int]float64, via map[int]Contact) {
+	if !n.Has(src) {
+		return nil, nil
+	}
+	arrival = map[int]float64{src.ID(): start}
+	via = make(map[int]Contact)
+
+	contacts := n.Contacts()
+	sort.Stable(byDeparture(contacts))
+	for _, c := range contacts {
+		if c.Departure < start || c.Arrival > end {
+			continue
+		}
+		t, ok := arrival[c.From.ID()]
+		if !ok || c.Departure < t {
+			continue
+		}
+		if a, ok := arrival[c.To.ID()]; !ok || c.Arrival < a {
+			arrival[c.To.ID()] = c.Arrival
+			via[c.To.ID()] = c
+		}
+	}
+	return arrival, via
+}
+
+// LatestDeparture returns the latest departure times from the nodes of n for
+// journeys leaving no earlier than start and reaching dst no later than end,
+// keyed by node ID. The departure time of dst is end. Nodes that cannot reach
+// dst are not included. The returned via map holds the first contact of the
+// latest departing journey from each node other than dst.
+func LatestDeparture(n *Network, dst graph.Node, start, end float64) (departure map[int]float64, via map[int]Contact) {
+	if !n.Has(dst) {
+		return nil, nil
+	}
+	departure = map[int]float64{dst.ID(): end}
+	via = make(map[int]Contact)
+
+	contacts := n.Contacts()
+	sort.Stable(sort.Reverse(byArrival(contacts)))
+	for _, c := range contacts {
+		if c.Departure < start || c.Arrival > end {
+			continue
+		}
+		t, ok := departure[c.To.ID()]
+		if !ok || c.Arrival > t {
+			continue
+		}
+		if d, ok := departure[c.From.ID()]; !ok || c.Departure > d {
+			departure[c.From.ID()] = c.Departure
+			via[c.From.ID()] = c
+		}
+	}
+	return departure, via
+}
+
+// ShortestDuration returns the minimum elapsed time of journeys from src to
+// the nodes of n leaving no earlier than start and arriving no later than
+// end, keyed by node ID. Waiting at src before departure is not counted. The
+// duration for src is zero and nodes that cannot be reached are not included.
+//
+// ShortestDuration uses the single pass algorithm of Wu et al.
+// doi:10.14778/2732939.2732945, maintaining for each node the non-dominated
+// pairs of journey start and arrival times.
+func ShortestDuration(n *Network, src graph.Node, start, end float64) map[int]float64 {
+	if !n.Has(src) {
+		return nil
+	}
+	duration := map[int]float64{src.ID(): 0}
+
+	// journeys holds the non-dominated
+	// journeys to each node ordered by
+	// increasing start and arrival.
+	journeys := make(map[int][]journey)
+
+	contacts := n.Contacts()
+	sort.Stable(byDeparture(contacts))
+	for _, c := range contacts {
+		if c.Departure < start || c.Arrival > end || c.To.ID() == src.ID() {
+			continue
+		}
+		var s float64
+		if c.From.ID() == src.ID() {
+			s = c.Departure
+		} else {
+			l := journeys[c.From.ID()]
+			// Find the latest starting journey
+			// arriving in time for the contact.
+			i := sort.Search(len(l), func(i int) bool { return l[i].arrival > c.Departure })
+			if i == 0 {
+				continue
+			}
+			s = l[i-1].start
+		}
+
+		journeys[c.To.ID()] = insert(journeys[c.To.ID()], journey{start: s, arrival: c.Arrival})
+		d := c.Arrival - s
+		if cur, ok := duration[c.To.ID()]; !ok || d < cur {
+			duration[c.To.ID()] = d
+		}
+	}
+	return duration
+}
+
+// journey is a journey summarised by its start and arrival times.
+type journey struct {
+	start, arrival float64
+}
+
+// insert inserts j into the non-dominated journeys in l, removing journeys
+// that j dominates, and returns the updated slice. A journey dominates
+// another if it starts no earlier and arrives no later.
+func insert(l []journey, j journey) []journey {
+	for _, o := range l {
+		if o.start >= j.start && o.arrival <= j.arrival {
+			return l
+		}
+	}
+	kept := l[:0]
+	for _, o := range l {
+		if j.start >= o.start && j.arrival <= o.arrival {
+			continue
+		}
+		kept = append(kept, o)
+	}
+	i := sort.Search(len(kept), func(i int) bool { return kept[i].start > j.start })
+	kept = append(kept, journey{})
+	copy(kept[i+1:], kept[i:])
+	kept[i] = j
+	return kept
+}
+
+// byDeparture sorts contacts by increasing departure time.
+type byDeparture []Contact
+
+func (c byDeparture) Len() int           { return len(c) }
+func (c byDeparture) Less(i, j int) bool { return c[i].Departure < c[j].Departure }
+func (c byDeparture) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// byArrival sorts contacts by increasing arrival time.
+type byArrival []Contact
+
+func (c byArrival) Len() int           { return len(c) }
+func (c byArrival) Less(i, j int) bool { return c[i].Arrival < c[j].Arrival }
+func (c byArrival) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// byTime sorts event indices by increasing event time.
+type byTime struct {
+	at     []int
+	events []Event
+}
+
+func (s byTime) Len() int           { return len(s.at) }
+func (s byTime) Less(i, j int) bool { return s.events[s.at[i]].Time < s.events[s.at[j]].Time }
+func (s byTime) Swap(i, j int)      { s.at[i], s.at[j] = s.at[j], s.at[i] }
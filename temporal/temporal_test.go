@@ -0,0 +1,138 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+// timetable returns a small transit network. Node 0 may reach
+// node 3 early by a slow direct service or later by a fast
+// connection through nodes 1 and 2.
+func timetable() *Network {
+	n := NewNetwork()
+	for _, c := range []struct {
+		f, t     int
+		dep, arr float64
+	}{
+		{f: 0, t: 3, dep: 1, arr: 10},
+		{f: 0, t: 1, dep: 2, arr: 4},
+		{f: 1, t: 2, dep: 5, arr: 6},
+		{f: 2, t: 3, dep: 7, arr: 8},
+		{f: 0, t: 1, dep: 6, arr: 7},
+		{f: 1, t: 2, dep: 7, arr: 8},
+		{f: 2, t: 3, dep: 8.5, arr: 9},
+
+		// The service from 1 leaves
+		// before any arrival at 1.
+		{f: 1, t: 4, dep: 3, arr: 4},
+		{f: 4, t: 2, dep: 8, arr: 9},
+	} {
+		n.AddContact(Contact{From: simple.Node(c.f), To: simple.Node(c.t), Departure: c.dep, Arrival: c.arr})
+	}
+	n.AddNode(simple.Node(5))
+	return n
+}
+
+func TestEarliestArrival(t *testing.T) {
+	arrival, via := EarliestArrival(timetable(), simple.Node(0), 0, 20)
+	want := map[int]float64{0: 0, 1: 4, 2: 6, 3: 8}
+	if !reflect.DeepEqual(arrival, want) {
+		t.Errorf("unexpected earliest arrival times: got:%v want:%v", arrival, want)
+	}
+	if c := via[3]; c.From.ID() != 2 || c.Departure != 7 {
+		t.Errorf("unexpected final contact to 3: got:%+v", c)
+	}
+
+	arrival, _ = EarliestArrival(timetable(), simple.Node(0), 3, 20)
+	want = map[int]float64{0: 3, 1: 7, 2: 8, 3: 9}
+	if !reflect.DeepEqual(arrival, want) {
+		t.Errorf("unexpected earliest arrival times from 3: got:%v want:%v", arrival, want)
+	}
+}
+
+func TestLatestDeparture(t *testing.T) {
+	departure, via := LatestDeparture(timetable(), simple.Node(3), 0, 9)
+	want := map[int]float64{3: 9, 2: 8.5, 1: 7, 0: 6}
+	if !reflect.DeepEqual(departure, want) {
+		t.Errorf("unexpected latest departure times: got:%v want:%v", departure, want)
+	}
+	if c := via[0]; c.To.ID() != 1 || c.Departure != 6 {
+		t.Errorf("unexpected first contact from 0: got:%+v", c)
+	}
+
+	departure, _ = LatestDeparture(timetable(), simple.Node(3), 0, 8)
+	want = map[int]float64{3: 8, 2: 7, 1: 5, 0: 2}
+	if !reflect.DeepEqual(departure, want) {
+		t.Errorf("unexpected latest departure times by 8: got:%v want:%v", departure, want)
+	}
+}
+
+func TestShortestDuration(t *testing.T) {
+	got := ShortestDuration(timetable(), simple.Node(0), 0, 20)
+	want := map[int]float64{0: 0, 1: 1, 2: 2, 3: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected shortest durations: got:%v want:%v", got, want)
+	}
+}
+
+func TestTimeExpanded(t *testing.T) {
+	n := timetable()
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	events := TimeExpanded(g, n)
+	if len(events) != len(g.Nodes()) {
+		t.Fatalf("unexpected number of events: got:%d want:%d", len(events), len(g.Nodes()))
+	}
+	for i := 1; i < len(events); i++ {
+		for j := 0; j < i; j++ {
+			if events[i].Node.ID() == events[j].Node.ID() && events[i].Time == events[j].Time {
+				t.Errorf("duplicate event: %v", events[i])
+			}
+		}
+	}
+
+	// The earliest arrival at node 3 found in the
+	// time-expanded graph matches EarliestArrival.
+	var from int
+	for i, e := range events {
+		if e.Node.ID() == 0 && e.Time == 2 {
+			from = i
+		}
+	}
+	pt := path.DijkstraFrom(simple.Node(from), g)
+	best := math.Inf(1)
+	for i, e := range events {
+		if e.Node.ID() != 3 {
+			continue
+		}
+		if !math.IsInf(pt.WeightTo(simple.Node(i)), 1) {
+			best = math.Min(best, e.Time)
+		}
+	}
+	if best != 8 {
+		t.Errorf("unexpected earliest arrival at 3 in time-expanded graph: got:%v want:8", best)
+	}
+}
+
+func TestAddContactPanics(t *testing.T) {
+	for _, c := range []Contact{
+		{From: simple.Node(0), To: simple.Node(0), Departure: 0, Arrival: 1},
+		{From: simple.Node(0), To: simple.Node(1), Departure: 1, Arrival: 1},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for contact %+v", c)
+				}
+			}()
+			NewNetwork().AddContact(c)
+		}()
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testgraphs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Check returns an error describing the first violation found of the
+// graph.Graph contract by g, and of the graph.Directed, graph.Undirected and
+// graph.Weighter contracts if g implements them. Check returns nil if no
+// violation is found. Check takes time quadratic in the order of g.
+func Check(g graph.Graph) error {
+	nodes := g.Nodes()
+	ids := make(map[int]bool, len(nodes))
+	absent := simple.Node(0)
+	for _, n := range nodes {
+		if ids[n.ID()] {
+			return fmt.Errorf("testgraphs: duplicate node ID %d in Nodes", n.ID())
+		}
+		ids[n.ID()] = true
+		if !g.Has(n) {
+			return fmt.Errorf("testgraphs: Has returns false for node %d in Nodes", n.ID())
+		}
+		if n.ID() >= int(absent) {
+			absent = simple.Node(n.ID() + 1)
+		}
+	}
+	if g.Has(absent) {
+		return fmt.Errorf("testgraphs: Has returns true for absent node %d", absent)
+	}
+	if len(g.From(absent)) != 0 {
+		return fmt.Errorf("testgraphs: From returns nodes for absent node %d", absent)
+	}
+
+	d, isDirected := g.(graph.Directed)
+	u, isUndirected := g.(graph.Undirected)
+	if isDirected && isUndirected {
+		return errors.New("testgraphs: graph is both directed and undirected")
+	}
+	w, isWeighter := g.(graph.Weighter)
+
+	edges := make(map[[2]int]bool)
+	for _, x := range nodes {
+		for _, y := range g.From(x) {
+			xid, yid := x.ID(), y.ID()
+			if !ids[yid] {
+				return fmt.Errorf("testgraphs: From(%d) returns node %d not in Nodes", xid, yid)
+			}
+			if edges[[2]int{xid, yid}] {
+				return fmt.Errorf("testgraphs: From(%d) returns node %d more than once", xid, yid)
+			}
+			edges[[2]int{xid, yid}] = true
+
+			if !g.HasEdgeBetween(x, y) || !g.HasEdgeBetween(y, x) {
+				return fmt.Errorf("testgraphs: HasEdgeBetween returns false for edge %d-%d", xid, yid)
+			}
+			e := g.Edge(x, y)
+			if e == nil {
+				return fmt.Errorf("testgraphs: Edge returns nil for edge %d-%d", xid, yid)
+			}
+			from, to := e.From().ID(), e.To().ID()
+			if isUndirected && from == yid && to == xid {
+				from, to = to, from
+			}
+			if from != xid || to != yid {
+				return fmt.Errorf("testgraphs: Edge(%d, %d) returns edge %d-%d", xid, yid, e.From().ID(), e.To().ID())
+			}
+			if isDirected && !d.HasEdgeFromTo(x, y) {
+				return fmt.Errorf("testgraphs: HasEdgeFromTo returns false for edge %d->%d", xid, yid)
+			}
+			if isUndirected && u.EdgeBetween(x, y) == nil {
+				return fmt.Errorf("testgraphs: EdgeBetween returns nil for edge %d-%d", xid, yid)
+			}
+			if isWeighter {
+				wt, ok := w.Weight(x, y)
+				if !ok {
+					return fmt.Errorf("testgraphs: Weight returns false for edge %d-%d", xid, yid)
+				}
+				if wt != e.Weight() {
+					return fmt.Errorf("testgraphs: Weight returns %v for edge %d-%d with weight %v", wt, xid, yid, e.Weight())
+				}
+			}
+		}
+		if g.HasEdgeBetween(x, absent) {
+			return fmt.Errorf("testgraphs: HasEdgeBetween returns true for absent node %d", absent)
+		}
+		if g.Edge(x, absent) != nil {
+			return fmt.Errorf("testgraphs: Edge returns non-nil for absent node %d", absent)
+		}
+	}
+
+	switch {
+	case isDirected:
+		var in int
+		for _, y := range nodes {
+			for _, x := range d.To(y) {
+				if !edges[[2]int{x.ID(), y.ID()}] {
+					return fmt.Errorf("testgraphs: To(%d) returns node %d without edge in From", y.ID(), x.ID())
+				}
+				in++
+			}
+			for _, x := range nodes {
+				if d.HasEdgeFromTo(x, y) != edges[[2]int{x.ID(), y.ID()}] {
+					return fmt.Errorf("testgraphs: HasEdgeFromTo(%d, %d) inconsistent with From", x.ID(), y.ID())
+				}
+			}
+		}
+		if in != len(edges) {
+			return fmt.Errorf("testgraphs: To returns %d edges, From returns %d", in, len(edges))
+		}
+	case isUndirected:
+		for e := range edges {
+			if !edges[[2]int{e[1], e[0]}] {
+				return fmt.Errorf("testgraphs: From(%d) returns %d but From(%d) does not return %d", e[0], e[1], e[1], e[0])
+			}
+		}
+	}
+	return nil
+}
+
+// CheckFixture returns an error if g does not satisfy the graph contracts as
+// checked by Check, or if g does not hold exactly the nodes and edges of the
+// fixture f with the same edge weights.
+func CheckFixture(g graph.Graph, f Fixture) error {
+	err := Check(g)
+	if err != nil {
+		return err
+	}
+	_, isDirected := g.(graph.Directed)
+	if isDirected != f.Directed {
+		return fmt.Errorf("testgraphs: %s: graph directedness does not match fixture", f.Name)
+	}
+
+	nodes := g.Nodes()
+	got := make([]int, len(nodes))
+	for i, n := range nodes {
+		got[i] = n.ID()
+	}
+	sort.Ints(got)
+	want := append([]int(nil), f.Nodes...)
+	sort.Ints(want)
+	if len(got) != len(want) {
+		return fmt.Errorf("testgraphs: %s: graph has %d nodes, want %d", f.Name, len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return fmt.Errorf("testgraphs: %s: graph has node %d, want %d", f.Name, got[i], want[i])
+		}
+	}
+
+	var n int
+	for _, x := range nodes {
+		n += len(g.From(x))
+	}
+	if !f.Directed {
+		n /= 2
+	}
+	if n != len(f.Edges) {
+		return fmt.Errorf("testgraphs: %s: graph has %d edges, want %d", f.Name, n, len(f.Edges))
+	}
+	for _, fe := range f.Edges {
+		e := g.Edge(fe.From(), fe.To())
+		if e == nil {
+			return fmt.Errorf("testgraphs: %s: missing edge %d-%d", f.Name, fe.From().ID(), fe.To().ID())
+		}
+		if e.Weight() != fe.Weight() {
+			return fmt.Errorf("testgraphs: %s: edge %d-%d has weight %v, want %v", f.Name, fe.From().ID(), fe.To().ID(), e.Weight(), fe.Weight())
+		}
+	}
+	return nil
+}
+
+// CheckBuilder builds each fixture with the same directedness as the graphs
+// returned by newBuilder into a new graph and checks it with CheckFixture.
+// The graphs returned by newBuilder must be empty and implement graph.Graph.
+// CheckBuilder returns the first error found.
+func CheckBuilder(newBuilder func() graph.Builder) error {
+	for _, f := range Fixtures {
+		b := newBuilder()
+		g, ok := b.(graph.Graph)
+		if !ok {
+			return errors.New("testgraphs: builder does not implement graph.Graph")
+		}
+		if _, isDirected := g.(graph.Directed); isDirected != f.Directed {
+			continue
+		}
+		f.Build(b)
+		err := CheckFixture(g, f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
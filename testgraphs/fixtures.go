@@ -0,0 +1,289 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package testgraphs provides canonical graph fixtures and helpers for
+// testing graph algorithms and graph implementations.
+//
+// The Check functions verify that a graph implementation satisfies the
+// contracts of the graph interfaces, and CheckFixture verifies that an
+// implementation faithfully stores a fixture, so authors of graph backends
+// can validate conformance. The helpers return errors rather than taking a
+// *testing.T so they can be used from any test framework.
+package testgraphs
+
+import (
+	"math"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// Fixture is a graph with known properties.
+type Fixture struct {
+	// Name is the name of the fixture.
+	Name string
+
+	// Directed indicates whether the
+	// fixture is a directed graph.
+	Directed bool
+
+	// Nodes holds the IDs of all the
+	// nodes of the graph, including
+	// those not in any edge.
+	Nodes []int
+
+	// Edges holds the edges of the
+	// graph. For undirected fixtures
+	// each edge appears once.
+	Edges []simple.Edge
+
+	// Acyclic indicates whether the graph
+	// is a directed acyclic graph or an
+	// undirected forest.
+	Acyclic bool
+
+	// Components is the number of
+	// connected components, ignoring
+	// edge direction.
+	Components int
+
+	// NegativeWeight and NegativeCycle
+	// indicate whether the graph has a
+	// negative edge weight and whether
+	// it has a negative weight cycle.
+	NegativeWeight bool
+	NegativeCycle  bool
+}
+
+// Build adds the nodes and edges of the fixture to dst, which should not
+// already hold any of the fixture's nodes.
+func (f Fixture) Build(dst graph.Builder) {
+	for _, id := range f.Nodes {
+		dst.AddNode(simple.Node(id))
+	}
+	for _, e := range f.Edges {
+		dst.SetEdge(e)
+	}
+}
+
+// Graph returns a new simple graph holding the fixture. The returned graph
+// is a *simple.DirectedGraph or a *simple.UndirectedGraph.
+func (f Fixture) Graph() graph.Graph {
+	var g graph.Builder
+	if f.Directed {
+		g = simple.NewDirectedGraph(0, math.Inf(1))
+	} else {
+		g = simple.NewUndirectedGraph(0, math.Inf(1))
+	}
+	f.Build(g)
+	return g.(graph.Graph)
+}
+
+// KarateClub returns Zachary's karate club network, an undirected social
+// network of 34 members of a university karate club with 78 edges.
+//
+// W. W. Zachary, An information flow model for conflict and fission in small
+// groups, Journal of Anthropological Research 33, 452-473 (1977).
+func KarateClub() *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	Fixtures[0].Build(g)
+	return g
+}
+
+// Fixtures is a collection of graph fixtures. The first fixture is
+// Zachary's karate club network.
+var Fixtures = []Fixture{
+	{
+		Name:       "karate club",
+		Nodes:      span(34),
+		Edges:      edges(karate, 1),
+		Components: 1,
+	},
+
+	// Degenerate graphs.
+	{
+		Name:    "empty undirected",
+		Acyclic: true,
+	},
+	{
+		Name:     "empty directed",
+		Directed: true,
+		Acyclic:  true,
+	},
+	{
+		Name:       "single node undirected",
+		Nodes:      []int{0},
+		Acyclic:    true,
+		Components: 1,
+	},
+	{
+		Name:       "single node directed",
+		Directed:   true,
+		Nodes:      []int{0},
+		Acyclic:    true,
+		Components: 1,
+	},
+
+	// Directed acyclic graphs.
+	{
+		Name:       "chain",
+		Directed:   true,
+		Nodes:      span(5),
+		Edges:      edges([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}}, 1),
+		Acyclic:    true,
+		Components: 1,
+	},
+	{
+		Name:       "diamond",
+		Directed:   true,
+		Nodes:      span(4),
+		Edges:      edges([][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}}, 1),
+		Acyclic:    true,
+		Components: 1,
+	},
+	{
+		Name:       "binary tree",
+		Directed:   true,
+		Nodes:      span(7),
+		Edges:      edges([][2]int{{0, 1}, {0, 2}, {1, 3}, {1, 4}, {2, 5}, {2, 6}}, 1),
+		Acyclic:    true,
+		Components: 1,
+	},
+	{
+		// A DAG with a transitive edge and
+		// a negative weight edge.
+		Name:     "weighted DAG",
+		Directed: true,
+		Nodes:    span(6),
+		Edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 5},
+			{F: simple.Node(0), T: simple.Node(2), W: 3},
+			{F: simple.Node(1), T: simple.Node(2), W: 2},
+			{F: simple.Node(1), T: simple.Node(3), W: 6},
+			{F: simple.Node(2), T: simple.Node(3), W: 7},
+			{F: simple.Node(2), T: simple.Node(4), W: 4},
+			{F: simple.Node(2), T: simple.Node(5), W: 2},
+			{F: simple.Node(3), T: simple.Node(4), W: -1},
+			{F: simple.Node(3), T: simple.Node(5), W: 1},
+			{F: simple.Node(4), T: simple.Node(5), W: -2},
+		},
+		Acyclic:        true,
+		Components:     1,
+		NegativeWeight: true,
+	},
+
+	// Cyclic graphs.
+	{
+		Name:       "directed cycle",
+		Directed:   true,
+		Nodes:      span(4),
+		Edges:      edges([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}}, 1),
+		Components: 1,
+	},
+	{
+		Name:       "undirected cycle",
+		Nodes:      span(4),
+		Edges:      edges([][2]int{{0, 1}, {1, 2}, {2, 3}, {0, 3}}, 1),
+		Components: 1,
+	},
+	{
+		// A negative cycle 1->2->3->1 with
+		// weight -1 reachable from 0 and an
+		// unreachable node 4.
+		Name:     "negative cycle",
+		Directed: true,
+		Nodes:    span(5),
+		Edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 2},
+			{F: simple.Node(2), T: simple.Node(3), W: -4},
+			{F: simple.Node(3), T: simple.Node(1), W: 1},
+			{F: simple.Node(3), T: simple.Node(4), W: 1},
+		},
+		Components:     1,
+		NegativeWeight: true,
+		NegativeCycle:  true,
+	},
+	{
+		// A cycle with a negative edge and
+		// positive total weight.
+		Name:     "negative edge positive cycle",
+		Directed: true,
+		Nodes:    span(3),
+		Edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(2), W: -1},
+			{F: simple.Node(2), T: simple.Node(0), W: 1},
+		},
+		Components:     1,
+		NegativeWeight: true,
+	},
+
+	// Disconnected graphs.
+	{
+		// Two triangles and an isolated node.
+		Name:       "disconnected undirected",
+		Nodes:      span(7),
+		Edges:      edges([][2]int{{0, 1}, {0, 2}, {1, 2}, {3, 4}, {3, 5}, {4, 5}}, 1),
+		Components: 3,
+	},
+	{
+		// Two chains and an isolated node
+		// with non-contiguous IDs.
+		Name:       "disconnected directed",
+		Directed:   true,
+		Nodes:      []int{1, 3, 5, 10, 20, 30, 100},
+		Edges:      edges([][2]int{{1, 3}, {3, 5}, {10, 20}, {20, 30}}, 1),
+		Acyclic:    true,
+		Components: 3,
+	},
+}
+
+// span returns the IDs 0 to n-1.
+func span(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+// edges returns the edges between the node ID pairs with weight w.
+func edges(pairs [][2]int, w float64) []simple.Edge {
+	e := make([]simple.Edge, len(pairs))
+	for i, p := range pairs {
+		e[i] = simple.Edge{F: simple.Node(p[0]), T: simple.Node(p[1]), W: w}
+	}
+	return e
+}
+
+// karate holds the edges of Zachary's karate club network.
+var karate = [][2]int{
+	{0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 5}, {0, 6}, {0, 7}, {0, 8}, {0, 10}, {0, 11}, {0, 12}, {0, 13}, {0, 17}, {0, 19}, {0, 21}, {0, 31},
+	{1, 2}, {1, 3}, {1, 7}, {1, 13}, {1, 17}, {1, 19}, {1, 21}, {1, 30},
+	{2, 3}, {2, 7}, {2, 8}, {2, 9}, {2, 13}, {2, 27}, {2, 28}, {2, 32},
+	{3, 7}, {3, 12}, {3, 13},
+	{4, 6}, {4, 10},
+	{5, 6}, {5, 10}, {5, 16},
+	{6, 16},
+	{8, 30}, {8, 32}, {8, 33},
+	{9, 33},
+	{13, 33},
+	{14, 32}, {14, 33},
+	{15, 32}, {15, 33},
+	{18, 32}, {18, 33},
+	{19, 33},
+	{20, 32}, {20, 33},
+	{22, 32}, {22, 33},
+	{23, 25}, {23, 27}, {23, 29}, {23, 32}, {23, 33},
+	{24, 25}, {24, 27}, {24, 31},
+	{25, 31},
+	{26, 29}, {26, 33},
+	{27, 33},
+	{28, 31}, {28, 33},
+	{29, 32}, {29, 33},
+	{30, 32}, {30, 33},
+	{31, 32}, {31, 33},
+	{32, 33},
+}
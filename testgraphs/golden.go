@@ -0,0 +1,51 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testgraphs
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+)
+
+// Golden compares got with the contents of the golden file at path and
+// returns an error describing the first differing line if they differ. If
+// update is true, the golden file is written with got instead. Golden is
+// intended for comparing serialized graphs, for example DOT encodings, in
+// tests with an update flag:
+//
+//  var update = flag.Bool("update", false, "update golden files")
+//
+//  func TestEncoding(t *testing.T) {
+//  	...
+//  	err := testgraphs.Golden("testdata/graph.dot", got, *update)
+//  	if err != nil {
+//  		t.Error(err)
+//  	}
+//  }
+func Golden(path string, got []byte, update bool) error {
+	if update {
+		return ioutil.WriteFile(path, got, 0644)
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(got, want) {
+		return nil
+	}
+	gotLines := bytes.Split(got, []byte("\n"))
+	wantLines := bytes.Split(want, []byte("\n"))
+	for i := 0; ; i++ {
+		switch {
+		case i == len(gotLines):
+			return fmt.Errorf("testgraphs: %s: output ends at line %d, want line %q", path, i+1, wantLines[i])
+		case i == len(wantLines):
+			return fmt.Errorf("testgraphs: %s: unexpected extra output at line %d: %q", path, i+1, gotLines[i])
+		case !bytes.Equal(gotLines[i], wantLines[i]):
+			return fmt.Errorf("testgraphs: %s: mismatch at line %d:\ngot: %q\nwant:%q", path, i+1, gotLines[i], wantLines[i])
+		}
+	}
+}
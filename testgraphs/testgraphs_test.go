@@ -0,0 +1,170 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testgraphs
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+func TestFixtures(t *testing.T) {
+	for _, f := range Fixtures {
+		g := f.Graph()
+		err := CheckFixture(g, f)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", f.Name, err)
+			continue
+		}
+
+		var u graph.Undirected
+		if d, ok := g.(graph.Directed); ok {
+			u = graph.Undirect{G: d}
+		} else {
+			u = g.(graph.Undirected)
+		}
+		cc := topo.ConnectedComponents(u)
+		if len(cc) != f.Components {
+			t.Errorf("unexpected number of components for %s: got:%d want:%d", f.Name, len(cc), f.Components)
+		}
+
+		var acyclic bool
+		if d, ok := g.(graph.Directed); ok {
+			_, err := topo.Sort(d)
+			acyclic = err == nil
+		} else {
+			acyclic = len(f.Edges) == len(f.Nodes)-len(cc)
+		}
+		if acyclic != f.Acyclic {
+			t.Errorf("unexpected acyclicity for %s: got:%t want:%t", f.Name, acyclic, f.Acyclic)
+		}
+
+		var negWeight bool
+		for _, e := range f.Edges {
+			if e.W < 0 {
+				negWeight = true
+			}
+		}
+		if negWeight != f.NegativeWeight {
+			t.Errorf("unexpected negative weight for %s: got:%t want:%t", f.Name, negWeight, f.NegativeWeight)
+		}
+		if !f.Directed {
+			continue
+		}
+		var negCycle bool
+		for _, n := range g.Nodes() {
+			if _, ok := path.BellmanFordFrom(n, g); !ok {
+				negCycle = true
+			}
+		}
+		if negCycle != f.NegativeCycle {
+			t.Errorf("unexpected negative cycle for %s: got:%t want:%t", f.Name, negCycle, f.NegativeCycle)
+		}
+	}
+}
+
+func TestKarateClub(t *testing.T) {
+	g := KarateClub()
+	if n := len(g.Nodes()); n != 34 {
+		t.Errorf("unexpected number of nodes: got:%d want:34", n)
+	}
+	if n := len(g.Edges()); n != 78 {
+		t.Errorf("unexpected number of edges: got:%d want:78", n)
+	}
+}
+
+func TestCheckBuilder(t *testing.T) {
+	for _, newBuilder := range []func() graph.Builder{
+		func() graph.Builder { return simple.NewDirectedGraph(0, math.Inf(1)) },
+		func() graph.Builder { return simple.NewUndirectedGraph(0, math.Inf(1)) },
+	} {
+		err := CheckBuilder(newBuilder)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+// noTo is a directed graph with a broken To method.
+type noTo struct {
+	*simple.DirectedGraph
+}
+
+func (g noTo) To(graph.Node) []graph.Node { return nil }
+
+// oneWay is an undirected graph that only reports
+// neighbors with greater IDs.
+type oneWay struct {
+	*simple.UndirectedGraph
+}
+
+func (g oneWay) From(n graph.Node) []graph.Node {
+	var to []graph.Node
+	for _, v := range g.UndirectedGraph.From(n) {
+		if v.ID() > n.ID() {
+			to = append(to, v)
+		}
+	}
+	return to
+}
+
+// phantom is a directed graph that reports a
+// neighbor that is not in the graph.
+type phantom struct {
+	*simple.DirectedGraph
+}
+
+func (g phantom) From(n graph.Node) []graph.Node {
+	return append(g.DirectedGraph.From(n), simple.Node(-1))
+}
+
+func TestCheckViolations(t *testing.T) {
+	d := Fixtures[5].Graph().(*simple.DirectedGraph)
+	u := Fixtures[0].Graph().(*simple.UndirectedGraph)
+	for _, g := range []graph.Graph{
+		noTo{d},
+		oneWay{u},
+		phantom{d},
+	} {
+		if err := Check(g); err == nil {
+			t.Errorf("expected error for %T", g)
+		}
+	}
+
+	if err := CheckFixture(d, Fixtures[6]); err == nil {
+		t.Error("expected error for mismatched fixture")
+	}
+}
+
+func TestGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testgraphs")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "golden")
+
+	err = Golden(name, []byte("a\nb\n"), true)
+	if err != nil {
+		t.Fatalf("unexpected error updating golden file: %v", err)
+	}
+	err = Golden(name, []byte("a\nb\n"), false)
+	if err != nil {
+		t.Errorf("unexpected error for matching output: %v", err)
+	}
+	for _, got := range []string{"a\nc\n", "a\n", "a\nb\nc\n"} {
+		err = Golden(name, []byte(got), false)
+		if err == nil {
+			t.Errorf("expected error for %q", got)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package testing provides a conformance test suite for implementations of
+// the graph interfaces.
+package testing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/testgraphs"
+)
+
+// RunGraphSuite runs a conformance test suite against the graphs returned by
+// newBuilder, reporting failures to t. Each call to newBuilder must return a
+// new empty graph that implements graph.Graph and either graph.Directed or
+// graph.Undirected. The suite exercises the semantics of Has, Nodes, From,
+// Edge and HasEdgeBetween, node ID allocation and reuse, edge replacement,
+// the documented panics of AddNode and SetEdge, and, if the graph implements
+// graph.NodeRemover or graph.EdgeRemover, the removal of nodes and edges.
+// Graph consistency is checked after each mutation with testgraphs.Check and
+// the graph is checked against the fixtures in testgraphs.Fixtures with
+// testgraphs.CheckBuilder.
+//
+// Node values added by the suite are simple.Node values and edges are
+// simple.Edge values.
+func RunGraphSuite(t *testing.T, newBuilder func() graph.Builder) {
+	b := newBuilder()
+	if _, ok := b.(graph.Graph); !ok {
+		t.Fatalf("builder %T does not implement graph.Graph", b)
+	}
+	_, isDirected := b.(graph.Directed)
+	_, isUndirected := b.(graph.Undirected)
+	if isDirected == isUndirected {
+		t.Fatalf("builder %T must implement exactly one of graph.Directed and graph.Undirected", b)
+	}
+
+	for _, test := range suite {
+		if test.remover && !canRemove(newBuilder()) {
+			t.Logf("%s: skipped: builder does not implement removal", test.name)
+			continue
+		}
+		run(t, test, newBuilder)
+	}
+	runFixtures(t, newBuilder)
+}
+
+func canRemove(b graph.Builder) bool {
+	_, isNodeRemover := b.(graph.NodeRemover)
+	_, isEdgeRemover := b.(graph.EdgeRemover)
+	return isNodeRemover && isEdgeRemover
+}
+
+// suiteTest is a conformance test. The fn function is called with a new
+// builder and its graph and directedness, and returns a description of the
+// first failure or the empty string.
+type suiteTest struct {
+	name    string
+	remover bool
+	fn      func(b graph.Builder, g graph.Graph, directed bool) string
+}
+
+// run runs a single suite test, reporting unexpected panics as failures.
+func run(t *testing.T, test suiteTest, newBuilder func() graph.Builder) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("%s: unexpected panic: %v", test.name, r)
+		}
+	}()
+	b := newBuilder()
+	g := b.(graph.Graph)
+	_, directed := g.(graph.Directed)
+	if msg := test.fn(b, g, directed); msg != "" {
+		t.Errorf("%s: %s", test.name, msg)
+	}
+}
+
+// runFixtures checks the builder against the testgraphs fixtures, reporting
+// unexpected panics as failures.
+func runFixtures(t *testing.T, newBuilder func() graph.Builder) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("fixtures: unexpected panic: %v", r)
+		}
+	}()
+	if err := testgraphs.CheckBuilder(newBuilder); err != nil {
+		t.Errorf("fixtures: %v", err)
+	}
+}
+
+// panics returns whether fn panics.
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func contains(nodes []graph.Node, id int) bool {
+	for _, n := range nodes {
+		if n.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+func edge(u, v int, w float64) simple.Edge {
+	return simple.Edge{F: simple.Node(u), T: simple.Node(v), W: w}
+}
+
+var suite = []suiteTest{
+	{
+		name: "empty graph",
+		fn: func(b graph.Builder, g graph.Graph, _ bool) string {
+			if n := len(g.Nodes()); n != 0 {
+				return fmt.Sprintf("new graph has %d nodes", n)
+			}
+			u, v := simple.Node(0), simple.Node(1)
+			switch {
+			case g.Has(u):
+				return "Has returns true for absent node"
+			case len(g.From(u)) != 0:
+				return "From returns nodes for absent node"
+			case g.Edge(u, v) != nil:
+				return "Edge returns non-nil for absent nodes"
+			case g.HasEdgeBetween(u, v):
+				return "HasEdgeBetween returns true for absent nodes"
+			}
+			return ""
+		},
+	},
+	{
+		name: "add nodes",
+		fn: func(b graph.Builder, g graph.Graph, _ bool) string {
+			ids := []int{0, 5, 100, -3}
+			for _, id := range ids {
+				b.AddNode(simple.Node(id))
+			}
+			nodes := g.Nodes()
+			if len(nodes) != len(ids) {
+				return fmt.Sprintf("graph has %d nodes after adding %d", len(nodes), len(ids))
+			}
+			for _, id := range ids {
+				if !g.Has(simple.Node(id)) {
+					return fmt.Sprintf("Has returns false for added node %d", id)
+				}
+				if !contains(nodes, id) {
+					return fmt.Sprintf("Nodes does not return added node %d", id)
+				}
+				if len(g.From(simple.Node(id))) != 0 {
+					return fmt.Sprintf("From returns nodes for isolated node %d", id)
+				}
+			}
+			if !panics(func() { b.AddNode(simple.Node(5)) }) {
+				return "AddNode does not panic on ID collision"
+			}
+			if err := testgraphs.Check(g); err != nil {
+				return err.Error()
+			}
+			return ""
+		},
+	},
+	{
+		name: "new node IDs",
+		fn: func(b graph.Builder, g graph.Graph, _ bool) string {
+			for _, id := range []int{0, 1, 7} {
+				b.AddNode(simple.Node(id))
+			}
+			for i := 0; i < 10; i++ {
+				id := b.NewNodeID()
+				if g.Has(simple.Node(id)) {
+					return fmt.Sprintf("NewNodeID returns ID %d already in the graph", id)
+				}
+				b.AddNode(simple.Node(id))
+			}
+			if n := len(g.Nodes()); n != 13 {
+				return fmt.Sprintf("graph has %d nodes, want 13", n)
+			}
+			return ""
+		},
+	},
+	{
+		name: "set edge",
+		fn: func(b graph.Builder, g graph.Graph, directed bool) string {
+			b.SetEdge(edge(0, 1, 2))
+			u, v := simple.Node(0), simple.Node(1)
+			if !g.Has(u) || !g.Has(v) {
+				return "SetEdge does not add absent nodes"
+			}
+			if !contains(g.From(u), 1) {
+				return "From does not return edge target"
+			}
+			if directed == contains(g.From(v), 0) {
+				return fmt.Sprintf("From of edge target returns source: %t for directed graph: %t", !directed, directed)
+			}
+			if !g.HasEdgeBetween(u, v) || !g.HasEdgeBetween(v, u) {
+				return "HasEdgeBetween returns false for edge"
+			}
+			e := g.Edge(u, v)
+			if e == nil {
+				return "Edge returns nil for edge"
+			}
+			if e.Weight() != 2 {
+				return fmt.Sprintf("edge has weight %v, want 2", e.Weight())
+			}
+			if directed {
+				d := g.(graph.Directed)
+				if !d.HasEdgeFromTo(u, v) || d.HasEdgeFromTo(v, u) {
+					return "HasEdgeFromTo inconsistent with edge direction"
+				}
+				if !contains(d.To(v), 0) || len(d.To(u)) != 0 {
+					return "To inconsistent with edge direction"
+				}
+				if g.Edge(v, u) != nil {
+					return "Edge returns non-nil for reverse of directed edge"
+				}
+			} else if g.Edge(v, u) == nil || g.(graph.Undirected).EdgeBetween(v, u) == nil {
+				return "Edge returns nil for reverse of undirected edge"
+			}
+			if !panics(func() { b.SetEdge(edge(2, 2, 1)) }) {
+				return "SetEdge does not panic for self edge"
+			}
+			if err := testgraphs.Check(g); err != nil {
+				return err.Error()
+			}
+			return ""
+		},
+	},
+	{
+		name: "replace edge",
+		fn: func(b graph.Builder, g graph.Graph, directed bool) string {
+			b.SetEdge(edge(0, 1, 1))
+			b.SetEdge(edge(0, 1, 3))
+			if !directed {
+				b.SetEdge(edge(1, 0, 4))
+			}
+			want := 3.0
+			if !directed {
+				want = 4
+			}
+			if n := len(g.From(simple.Node(0))); n != 1 {
+				return fmt.Sprintf("From returns %d nodes after replacing edge, want 1", n)
+			}
+			if w := g.Edge(simple.Node(0), simple.Node(1)).Weight(); w != want {
+				return fmt.Sprintf("replaced edge has weight %v, want %v", w, want)
+			}
+			if err := testgraphs.Check(g); err != nil {
+				return err.Error()
+			}
+			return ""
+		},
+	},
+	{
+		name:    "remove edge",
+		remover: true,
+		fn: func(b graph.Builder, g graph.Graph, directed bool) string {
+			b.SetEdge(edge(0, 1, 1))
+			b.SetEdge(edge(1, 2, 1))
+			r := b.(graph.EdgeRemover)
+			r.RemoveEdge(edge(0, 1, 1))
+			u, v := simple.Node(0), simple.Node(1)
+			if !g.Has(u) || !g.Has(v) {
+				return "RemoveEdge removes terminal nodes"
+			}
+			if g.HasEdgeBetween(u, v) || g.Edge(u, v) != nil || contains(g.From(u), 1) {
+				return "edge remains after RemoveEdge"
+			}
+			if !g.HasEdgeBetween(v, simple.Node(2)) {
+				return "RemoveEdge removes other edge"
+			}
+			if directed {
+				// Removing the reverse of a
+				// directed edge is a no-op.
+				r.RemoveEdge(edge(2, 1, 1))
+				if !g.HasEdgeBetween(v, simple.Node(2)) {
+					return "RemoveEdge of reverse edge removes directed edge"
+				}
+			}
+			r.RemoveEdge(edge(0, 2, 1))
+			r.RemoveEdge(edge(10, 11, 1))
+			if n := len(g.Nodes()); n != 3 {
+				return fmt.Sprintf("graph has %d nodes after removing absent edges, want 3", n)
+			}
+			if err := testgraphs.Check(g); err != nil {
+				return err.Error()
+			}
+			return ""
+		},
+	},
+	{
+		name:    "remove node",
+		remover: true,
+		fn: func(b graph.Builder, g graph.Graph, directed bool) string {
+			b.SetEdge(edge(0, 1, 1))
+			b.SetEdge(edge(1, 2, 1))
+			b.SetEdge(edge(2, 0, 1))
+			r := b.(graph.NodeRemover)
+			r.RemoveNode(simple.Node(1))
+			if g.Has(simple.Node(1)) || contains(g.Nodes(), 1) {
+				return "node remains after RemoveNode"
+			}
+			for _, id := range []int{0, 2} {
+				n := simple.Node(id)
+				if contains(g.From(n), 1) {
+					return fmt.Sprintf("From(%d) returns removed node", id)
+				}
+				if g.HasEdgeBetween(n, simple.Node(1)) || g.Edge(n, simple.Node(1)) != nil {
+					return fmt.Sprintf("edge between %d and removed node remains", id)
+				}
+				if directed && contains(g.(graph.Directed).To(n), 1) {
+					return fmt.Sprintf("To(%d) returns removed node", id)
+				}
+			}
+			if !g.HasEdgeBetween(simple.Node(2), simple.Node(0)) {
+				return "RemoveNode removes unrelated edge"
+			}
+			r.RemoveNode(simple.Node(1))
+			r.RemoveNode(simple.Node(10))
+			if n := len(g.Nodes()); n != 2 {
+				return fmt.Sprintf("graph has %d nodes after removing absent nodes, want 2", n)
+			}
+			if err := testgraphs.Check(g); err != nil {
+				return err.Error()
+			}
+			return ""
+		},
+	},
+	{
+		name:    "ID reuse",
+		remover: true,
+		fn: func(b graph.Builder, g graph.Graph, directed bool) string {
+			for i := 0; i < 5; i++ {
+				b.AddNode(simple.Node(i))
+			}
+			b.SetEdge(edge(0, 3, 1))
+			b.SetEdge(edge(3, 4, 1))
+			r := b.(graph.NodeRemover)
+			r.RemoveNode(simple.Node(3))
+			id := b.NewNodeID()
+			if g.Has(simple.Node(id)) {
+				return fmt.Sprintf("NewNodeID returns ID %d already in the graph after removal", id)
+			}
+
+			// A node added with a removed ID
+			// must not have stale edges.
+			b.AddNode(simple.Node(3))
+			if len(g.From(simple.Node(3))) != 0 {
+				return "re-added node has stale edges"
+			}
+			if g.HasEdgeBetween(simple.Node(0), simple.Node(3)) || g.HasEdgeBetween(simple.Node(3), simple.Node(4)) {
+				return "HasEdgeBetween reports stale edge of re-added node"
+			}
+			if directed && len(g.(graph.Directed).To(simple.Node(3))) != 0 {
+				return "re-added node has stale incoming edges"
+			}
+
+			// Removing all nodes leaves an
+			// empty but usable graph.
+			for _, n := range g.Nodes() {
+				r.RemoveNode(n)
+			}
+			if n := len(g.Nodes()); n != 0 {
+				return fmt.Sprintf("graph has %d nodes after removing all nodes", n)
+			}
+			b.AddNode(simple.Node(42))
+			b.SetEdge(edge(b.NewNodeID(), 42, 1))
+			if n := len(g.Nodes()); n != 2 {
+				return fmt.Sprintf("graph has %d nodes after reuse, want 2", n)
+			}
+			if err := testgraphs.Check(g); err != nil {
+				return err.Error()
+			}
+			return ""
+		},
+	},
+}
@@ -0,0 +1,21 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestSimpleDirected(t *testing.T) {
+	RunGraphSuite(t, func() graph.Builder { return simple.NewDirectedGraph(0, math.Inf(1)) })
+}
+
+func TestSimpleUndirected(t *testing.T) {
+	RunGraphSuite(t, func() graph.Builder { return simple.NewUndirectedGraph(0, math.Inf(1)) })
+}
@@ -0,0 +1,152 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+// Layers returns the nodes of the directed acyclic graph g grouped by the
+// length of the longest path reaching them, so that every edge of g leads
+// from an earlier layer to a later one. Nodes in the same layer have no
+// dependencies between them and may be processed in parallel. Each layer
+// is sorted by node ID. If g is not acyclic, the error returned by Sort is
+// returned.
+func Layers(g graph.Directed) ([][]graph.Node, error) {
+	sorted, err := Sort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := make(map[int]int, len(sorted))
+	var layers [][]graph.Node
+	for _, u := range sorted {
+		d := depth[u.ID()]
+		if d == len(layers) {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], u)
+		for _, v := range g.From(u) {
+			if depth[v.ID()] < d+1 {
+				depth[v.ID()] = d + 1
+			}
+		}
+	}
+	for _, l := range layers {
+		sort.Sort(ordered.ByID(l))
+	}
+	return layers, nil
+}
+
+// MaxAntichain returns a largest set of nodes of the directed acyclic graph g
+// such that no node in the set is reachable from another, sorted by node ID.
+// By Dilworth's theorem its size is the least number of chains needed to cover
+// g, and so the maximum parallelism available when executing g. If g is not
+// acyclic, the error returned by Sort is returned.
+//
+// The antichain is found from a maximum matching in the bipartite graph of the
+// transitive closure of g. The time complexity of MaxAntichain is O(|V|^3).
+func MaxAntichain(g graph.Directed) ([]graph.Node, error) {
+	_, err := Sort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// reach[i] holds the indices of the nodes
+	// reachable from node i, excluding i.
+	reach := make([][]int, len(nodes))
+	for i, u := range nodes {
+		seen := map[int]bool{i: true}
+		stack := []graph.Node{u}
+		for len(stack) != 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range g.From(n) {
+				j := indexOf[v.ID()]
+				if !seen[j] {
+					seen[j] = true
+					reach[i] = append(reach[i], j)
+					stack = append(stack, v)
+				}
+			}
+		}
+		sort.Ints(reach[i])
+	}
+
+	// Find a maximum matching from the left copy
+	// of each node to the right copy of the nodes
+	// it reaches using augmenting paths.
+	matchL := make([]int, len(nodes))
+	matchR := make([]int, len(nodes))
+	for i := range nodes {
+		matchL[i] = -1
+		matchR[i] = -1
+	}
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for _, j := range reach[i] {
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+			if matchR[j] < 0 || augment(matchR[j], seen) {
+				matchL[i] = j
+				matchR[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range nodes {
+		augment(i, make([]bool, len(nodes)))
+	}
+
+	// By König's theorem, the left copies reachable
+	// from unmatched left copies by alternating paths
+	// whose right copies are not so reachable form
+	// the complement of a minimum vertex cover, and
+	// so a maximum antichain.
+	inZL := make([]bool, len(nodes))
+	inZR := make([]bool, len(nodes))
+	var queue []int
+	for i, j := range matchL {
+		if j < 0 {
+			inZL[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) != 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range reach[i] {
+			if inZR[j] {
+				continue
+			}
+			inZR[j] = true
+			if k := matchR[j]; k >= 0 && !inZL[k] {
+				inZL[k] = true
+				queue = append(queue, k)
+			}
+		}
+	}
+
+	var antichain []graph.Node
+	for i, n := range nodes {
+		if inZL[i] && !inZR[i] {
+			antichain = append(antichain, n)
+		}
+	}
+	return antichain, nil
+}
@@ -0,0 +1,110 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestLayers(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range [][2]int{
+		{0, 1}, {0, 2}, {1, 3}, {2, 3}, {3, 4}, {0, 4}, {5, 4},
+	} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	g.AddNode(simple.Node(6))
+
+	layers, err := Layers(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got [][]int
+	for _, l := range layers {
+		var ids []int
+		for _, n := range l {
+			ids = append(ids, n.ID())
+		}
+		got = append(got, ids)
+	}
+	want := [][]int{{0, 5, 6}, {1, 2}, {3}, {4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected layers: got:%v want:%v", got, want)
+	}
+
+	g.SetEdge(simple.Edge{F: simple.Node(4), T: simple.Node(0), W: 1})
+	if _, err := Layers(g); err == nil {
+		t.Error("expected error for cyclic graph")
+	}
+}
+
+func TestMaxAntichain(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		n := 1 + rnd.Intn(9)
+		g := simple.NewDirectedGraph(0, math.Inf(1))
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if rnd.Float64() < 0.3 {
+					g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j), W: 1})
+				}
+			}
+		}
+
+		a, err := MaxAntichain(g)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !isAntichain(g, a) {
+			t.Errorf("trial %d: result is not an antichain: %v", trial, a)
+		}
+		if want := bruteMaxAntichain(g, n); len(a) != want {
+			t.Errorf("trial %d: unexpected antichain size: got:%d want:%d", trial, len(a), want)
+		}
+	}
+
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0), W: 1})
+	if _, err := MaxAntichain(g); err == nil {
+		t.Error("expected error for cyclic graph")
+	}
+}
+
+func isAntichain(g graph.Directed, a []graph.Node) bool {
+	for _, u := range a {
+		for _, v := range a {
+			if u.ID() != v.ID() && PathExistsIn(g, u, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func bruteMaxAntichain(g graph.Directed, n int) int {
+	var max int
+	for set := 0; set < 1<<uint(n); set++ {
+		var a []graph.Node
+		for i := 0; i < n; i++ {
+			if set&(1<<uint(i)) != 0 {
+				a = append(a, simple.Node(i))
+			}
+		}
+		if len(a) > max && isAntichain(g, a) {
+			max = len(a)
+		}
+	}
+	return max
+}
@@ -0,0 +1,210 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+// AggregateParallel places in dst an undirected summary of g where the edges
+// joining each pair of nodes are merged into a single edge. Since the graphs
+// in this package hold at most one edge in each direction between a pair of
+// nodes, the parallel edges of a directed graph are the edges u→v and v→u.
+// For undirected graphs, AggregateParallel copies g into dst.
+//
+// The merge function is called with the edges joining each pair of nodes in
+// ascending order of their from node IDs and must return an edge between the same pair
+// of nodes. If merge is nil, the edges are replaced by a simple.Edge holding
+// the sum of their weights.
+func AggregateParallel(dst graph.UndirectedBuilder, g graph.Graph, merge func([]graph.Edge) graph.Edge) {
+	if merge == nil {
+		merge = sumEdges
+	}
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		if !dst.Has(u) {
+			dst.AddNode(u)
+		}
+	}
+	d, isDirected := g.(graph.Directed)
+	for _, u := range nodes {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected {
+				if v.ID() < u.ID() {
+					continue
+				}
+				dst.SetEdge(merge([]graph.Edge{g.Edge(u, v)}))
+				continue
+			}
+			if !d.HasEdgeFromTo(v, u) {
+				dst.SetEdge(merge([]graph.Edge{d.Edge(u, v)}))
+				continue
+			}
+			if v.ID() < u.ID() {
+				// Already merged from v.
+				continue
+			}
+			dst.SetEdge(merge([]graph.Edge{d.Edge(u, v), d.Edge(v, u)}))
+		}
+	}
+}
+
+// sumEdges returns a simple.Edge joining the terminals of the first edge in
+// edges with the sum of the weights of all the edges.
+func sumEdges(edges []graph.Edge) graph.Edge {
+	var w float64
+	for _, e := range edges {
+		w += e.Weight()
+	}
+	return simple.Edge{F: edges[0].From(), T: edges[0].To(), W: w}
+}
+
+// MetaNode is a node in an aggregated graph representing a set of nodes of
+// the original graph.
+type MetaNode struct {
+	id int
+
+	// Members holds the nodes of the
+	// original graph in the set.
+	Members []graph.Node
+
+	// Edges and Weight hold the number
+	// and total weight of the edges of
+	// the original graph joining members
+	// of the set.
+	Edges  int
+	Weight float64
+}
+
+// ID returns the ID of the meta node.
+func (n *MetaNode) ID() int { return n.id }
+
+// DOTAttributes returns the size and internal edge statistics of the meta
+// node as DOT attributes.
+func (n *MetaNode) DOTAttributes() []dot.Attribute {
+	return []dot.Attribute{
+		{Key: "size", Value: strconv.Itoa(len(n.Members))},
+		{Key: "edges", Value: strconv.Itoa(n.Edges)},
+		{Key: "weight", Value: strconv.FormatFloat(n.Weight, 'g', -1, 64)},
+	}
+}
+
+// MetaEdge is an edge in an aggregated graph representing the edges of the
+// original graph joining members of two meta nodes.
+type MetaEdge struct {
+	F, T *MetaNode
+
+	// Count and W hold the number
+	// and total weight of the edges
+	// represented by the meta edge.
+	Count int
+	W     float64
+}
+
+// From returns the from-node of the edge.
+func (e *MetaEdge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e *MetaEdge) To() graph.Node { return e.T }
+
+// Weight returns the total weight of the edges represented by the meta edge.
+func (e *MetaEdge) Weight() float64 { return e.W }
+
+// DOTAttributes returns the count and total weight of the meta edge as DOT
+// attributes.
+func (e *MetaEdge) DOTAttributes() []dot.Attribute {
+	return []dot.Attribute{
+		{Key: "count", Value: strconv.Itoa(e.Count)},
+		{Key: "weight", Value: strconv.FormatFloat(e.W, 'g', -1, 64)},
+	}
+}
+
+// Aggregate places in dst a summary of g where each of the given communities
+// is represented by a *MetaNode with an ID equal to the index of the community,
+// and the edges joining members of different communities are represented by a
+// *MetaEdge holding the number and total weight of the edges. Edges within a
+// community are summarized in the community's MetaNode. If g does not
+// implement graph.Weighter, path.UniformCost is used.
+//
+// The communities returned by the Communities method of a
+// community.ReducedGraph are suitable for use with Aggregate. Meta edges are
+// directed only if both g and dst are directed. Aggregate will panic if a node
+// of g is not in exactly one community.
+func Aggregate(dst graph.Builder, g graph.Graph, communities [][]graph.Node) {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	meta := make([]*MetaNode, len(communities))
+	communityOf := make(map[int]int)
+	for i, c := range communities {
+		meta[i] = &MetaNode{id: i, Members: c}
+		for _, n := range c {
+			if _, ok := communityOf[n.ID()]; ok {
+				panic("transform: node in more than one community")
+			}
+			communityOf[n.ID()] = i
+		}
+		dst.AddNode(meta[i])
+	}
+
+	// Meta edges are only directed if both
+	// g and dst are directed.
+	_, isDirected := g.(graph.Directed)
+	_, directedDst := dst.(graph.DirectedBuilder)
+	edges := make(map[[2]int]*MetaEdge)
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		cu, ok := communityOf[u.ID()]
+		if !ok {
+			panic("transform: node not in community")
+		}
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if !isDirected && v.ID() < u.ID() {
+				continue
+			}
+			w, ok := weight(u, v)
+			if !ok {
+				panic("transform: unexpected invalid weight")
+			}
+			cv := communityOf[v.ID()]
+			if cu == cv {
+				meta[cu].Edges++
+				meta[cu].Weight += w
+				continue
+			}
+			k := [2]int{cu, cv}
+			if !(isDirected && directedDst) && cu > cv {
+				k[0], k[1] = cv, cu
+			}
+			e, ok := edges[k]
+			if !ok {
+				e = &MetaEdge{F: meta[k[0]], T: meta[k[1]]}
+				edges[k] = e
+			}
+			e.Count++
+			e.W += w
+		}
+	}
+	for _, e := range edges {
+		dst.SetEdge(e)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/encoding/dot"
+	"github.com/gonum/graph/simple"
+)
+
+func TestAggregateParallel(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(0), W: 2},
+		{F: simple.Node(1), T: simple.Node(2), W: 4},
+		{F: simple.Node(3), T: simple.Node(2), W: 8},
+		{F: simple.Node(2), T: simple.Node(3), W: 16},
+	} {
+		g.SetEdge(e)
+	}
+	g.AddNode(simple.Node(4))
+
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	AggregateParallel(dst, g, nil)
+	if n := len(dst.Nodes()); n != 5 {
+		t.Errorf("unexpected number of nodes: got:%d want:5", n)
+	}
+	want := map[[2]int]float64{{0, 1}: 3, {1, 2}: 4, {2, 3}: 24}
+	if n := len(dst.Edges()); n != len(want) {
+		t.Errorf("unexpected number of edges: got:%d want:%d", n, len(want))
+	}
+	for k, w := range want {
+		e := dst.EdgeBetween(simple.Node(k[0]), simple.Node(k[1]))
+		if e == nil {
+			t.Errorf("missing edge %v", k)
+			continue
+		}
+		if e.Weight() != w {
+			t.Errorf("unexpected weight for edge %v: got:%v want:%v", k, e.Weight(), w)
+		}
+	}
+
+	var calls [][]int
+	AggregateParallel(simple.NewUndirectedGraph(0, math.Inf(1)), g, func(edges []graph.Edge) graph.Edge {
+		var from []int
+		for _, e := range edges {
+			from = append(from, e.From().ID())
+		}
+		calls = append(calls, from)
+		return edges[0]
+	})
+	wantCalls := [][]int{{0, 1}, {1}, {2, 3}}
+	if len(calls) != len(wantCalls) {
+		t.Fatalf("unexpected merge calls: got:%v want:%v", calls, wantCalls)
+	}
+	for i, c := range calls {
+		if len(c) != len(wantCalls[i]) {
+			t.Errorf("unexpected merge call: got:%v want:%v", c, wantCalls[i])
+			continue
+		}
+		for j := range c {
+			if c[j] != wantCalls[i][j] {
+				t.Errorf("unexpected merge call: got:%v want:%v", c, wantCalls[i])
+				break
+			}
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		// Community 0.
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(2), T: simple.Node(0), W: 1},
+		// Community 1.
+		{F: simple.Node(3), T: simple.Node(4), W: 2},
+		// Between communities.
+		{F: simple.Node(0), T: simple.Node(3), W: 0.5},
+		{F: simple.Node(2), T: simple.Node(4), W: 0.25},
+	} {
+		g.SetEdge(e)
+	}
+	g.AddNode(simple.Node(5))
+	communities := [][]graph.Node{
+		{simple.Node(0), simple.Node(1), simple.Node(2)},
+		{simple.Node(3), simple.Node(4)},
+		{simple.Node(5)},
+	}
+
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	Aggregate(dst, g, communities)
+	if n := len(dst.Nodes()); n != 3 {
+		t.Errorf("unexpected number of meta nodes: got:%d want:3", n)
+	}
+	for _, test := range []struct {
+		id     int
+		edges  int
+		weight float64
+	}{
+		{id: 0, edges: 3, weight: 3},
+		{id: 1, edges: 1, weight: 2},
+		{id: 2, edges: 0, weight: 0},
+	} {
+		n := dst.Node(test.id).(*MetaNode)
+		if n.Edges != test.edges || n.Weight != test.weight {
+			t.Errorf("unexpected internal summary for meta node %d: got:%d/%v want:%d/%v",
+				test.id, n.Edges, n.Weight, test.edges, test.weight)
+		}
+	}
+	if n := len(dst.Edges()); n != 1 {
+		t.Fatalf("unexpected number of meta edges: got:%d want:1", n)
+	}
+	e := dst.EdgeBetween(simple.Node(0), simple.Node(1)).(*MetaEdge)
+	if e.Count != 2 || e.W != 0.75 {
+		t.Errorf("unexpected meta edge summary: got:%d/%v want:2/0.75", e.Count, e.W)
+	}
+
+	b, err := dot.Marshal(dst, "", "", "", false)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling aggregate: %v", err)
+	}
+	for _, want := range []string{"size=3", "count=2", "weight=0.75"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("DOT encoding missing %q:\n%s", want, b)
+		}
+	}
+}
+
+func TestAggregateDirected(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(2), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(2), T: simple.Node(0), W: 1},
+	} {
+		g.SetEdge(e)
+	}
+	communities := [][]graph.Node{
+		{simple.Node(0), simple.Node(1)},
+		{simple.Node(2)},
+	}
+
+	dst := simple.NewDirectedGraph(0, math.Inf(1))
+	Aggregate(dst, g, communities)
+	fwd := dst.Edge(simple.Node(0), simple.Node(1)).(*MetaEdge)
+	if fwd.Count != 2 {
+		t.Errorf("unexpected forward meta edge count: got:%d want:2", fwd.Count)
+	}
+	rev := dst.Edge(simple.Node(1), simple.Node(0)).(*MetaEdge)
+	if rev.Count != 1 {
+		t.Errorf("unexpected reverse meta edge count: got:%d want:1", rev.Count)
+	}
+
+	udst := simple.NewUndirectedGraph(0, math.Inf(1))
+	Aggregate(udst, g, communities)
+	e := udst.EdgeBetween(simple.Node(0), simple.Node(1)).(*MetaEdge)
+	if e.Count != 3 {
+		t.Errorf("unexpected undirected meta edge count: got:%d want:3", e.Count)
+	}
+}
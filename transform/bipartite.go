@@ -0,0 +1,95 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/simple"
+)
+
+// BipartiteProjection places in dst the one-mode projection of the bipartite
+// graph g onto the nodes in keep. For example, the projection of a graph of
+// users and the items they have rated onto the users is a graph of users.
+// Each pair of nodes in keep with at least one neighbor in common is joined in
+// dst by an edge with weight weighting(shared), where shared holds the common
+// neighbors in ascending order of ID. If weighting is nil, the weight is the
+// number of shared neighbors. All the nodes in keep are added to dst.
+//
+// Edges of g joining a pair of nodes in keep are ignored, as are the
+// neighbors of nodes in keep that are themselves in keep. BipartiteProjection
+// will panic if a node in keep is not in g.
+func BipartiteProjection(dst graph.UndirectedBuilder, g graph.Undirected, keep []graph.Node, weighting func(shared []graph.Node) float64) {
+	if weighting == nil {
+		weighting = countShared
+	}
+	kept := make(map[int]bool, len(keep))
+	var nodes []graph.Node
+	for _, n := range keep {
+		if !g.Has(n) {
+			panic("transform: projected node not in graph")
+		}
+		if kept[n.ID()] {
+			continue
+		}
+		kept[n.ID()] = true
+		nodes = append(nodes, n)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		if !dst.Has(u) {
+			dst.AddNode(u)
+		}
+	}
+
+	for _, u := range nodes {
+		// shared holds the neighbors u has in
+		// common with each later node in keep.
+		shared := make(map[int][]graph.Node)
+		var partners []graph.Node
+		items := g.From(u)
+		sort.Sort(ordered.ByID(items))
+		for _, w := range items {
+			if kept[w.ID()] {
+				continue
+			}
+			for _, v := range g.From(w) {
+				if !kept[v.ID()] || v.ID() <= u.ID() {
+					continue
+				}
+				if _, ok := shared[v.ID()]; !ok {
+					partners = append(partners, v)
+				}
+				shared[v.ID()] = append(shared[v.ID()], w)
+			}
+		}
+		sort.Sort(ordered.ByID(partners))
+		for _, v := range partners {
+			dst.SetEdge(simple.Edge{F: u, T: v, W: weighting(shared[v.ID()])})
+		}
+	}
+}
+
+// countShared returns the number of shared nodes.
+func countShared(shared []graph.Node) float64 {
+	return float64(len(shared))
+}
+
+// NewmanWeighting returns a BipartiteProjection weighting for projections of
+// g that sums 1/(k-1) over the shared neighbors, where k is the degree of the
+// neighbor in g. This is the collaboration weighting described by Newman in
+// doi:10.1103/PhysRevE.64.016132, which discounts neighbors shared by many
+// nodes.
+func NewmanWeighting(g graph.Graph) func(shared []graph.Node) float64 {
+	return func(shared []graph.Node) float64 {
+		var w float64
+		for _, n := range shared {
+			w += 1 / float64(len(g.From(n))-1)
+		}
+		return w
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// userItem returns a bipartite graph of users 0-3 and items 10-13.
+func userItem() *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range [][2]int{
+		{0, 10}, {0, 11}, {0, 12},
+		{1, 10}, {1, 11},
+		{2, 12}, {2, 13},
+		{3, 13},
+	} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 1})
+	}
+	return g
+}
+
+func TestBipartiteProjection(t *testing.T) {
+	g := userItem()
+	users := []graph.Node{simple.Node(3), simple.Node(0), simple.Node(1), simple.Node(2), simple.Node(0)}
+
+	var gotShared [][]int
+	dst := simple.NewUndirectedGraph(0, math.Inf(1))
+	BipartiteProjection(dst, g, users, func(shared []graph.Node) float64 {
+		ids := make([]int, len(shared))
+		for i, n := range shared {
+			ids[i] = n.ID()
+		}
+		gotShared = append(gotShared, ids)
+		return float64(len(shared))
+	})
+	wantShared := [][]int{{10, 11}, {12}, {13}}
+	if !reflect.DeepEqual(gotShared, wantShared) {
+		t.Errorf("unexpected shared neighbors: got:%v want:%v", gotShared, wantShared)
+	}
+
+	if len(dst.Nodes()) != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", len(dst.Nodes()))
+	}
+	for _, test := range []struct {
+		u, v int
+		w    float64
+	}{
+		{u: 0, v: 1, w: 2},
+		{u: 0, v: 2, w: 1},
+		{u: 2, v: 3, w: 1},
+		{u: 0, v: 3, w: math.NaN()},
+		{u: 1, v: 2, w: math.NaN()},
+	} {
+		e := dst.EdgeBetween(simple.Node(test.u), simple.Node(test.v))
+		if math.IsNaN(test.w) {
+			if e != nil {
+				t.Errorf("unexpected edge %d-%d", test.u, test.v)
+			}
+			continue
+		}
+		if e == nil {
+			t.Errorf("missing edge %d-%d", test.u, test.v)
+			continue
+		}
+		if e.Weight() != test.w {
+			t.Errorf("unexpected weight for %d-%d: got:%v want:%v", test.u, test.v, e.Weight(), test.w)
+		}
+	}
+
+	items := simple.NewUndirectedGraph(0, math.Inf(1))
+	BipartiteProjection(items, g, []graph.Node{simple.Node(10), simple.Node(11), simple.Node(12), simple.Node(13)}, NewmanWeighting(g))
+	for _, test := range []struct {
+		u, v int
+		w    float64
+	}{
+		// Items 10 and 11 share users 0 and 1,
+		// which have degrees 3 and 2.
+		{u: 10, v: 11, w: 1.0/2 + 1},
+		{u: 10, v: 12, w: 1.0 / 2},
+		{u: 12, v: 13, w: 1},
+	} {
+		e := items.EdgeBetween(simple.Node(test.u), simple.Node(test.v))
+		if e == nil {
+			t.Errorf("missing item edge %d-%d", test.u, test.v)
+			continue
+		}
+		if e.Weight() != test.w {
+			t.Errorf("unexpected item weight for %d-%d: got:%v want:%v", test.u, test.v, e.Weight(), test.w)
+		}
+	}
+}
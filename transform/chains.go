@@ -0,0 +1,197 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package transform provides graph transformation functions.
+package transform
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+// Segments maps the edges of a simplified graph to the paths in the original
+// graph that they replace. Keys are the IDs of the edge's terminal nodes with
+// the lower ID first.
+type Segments map[[2]int][]graph.Node
+
+// Path returns the path in the original graph replaced by the edge between u
+// and v in the simplified graph, oriented from u to v. If no such edge exists,
+// Path returns nil.
+func (s Segments) Path(u, v graph.Node) []graph.Node {
+	uid, vid := u.ID(), v.ID()
+	if uid > vid {
+		uid, vid = vid, uid
+	}
+	p, ok := s[[2]int{uid, vid}]
+	if !ok {
+		return nil
+	}
+	p = append([]graph.Node(nil), p...)
+	if p[0].ID() != u.ID() {
+		for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+			p[i], p[j] = p[j], p[i]
+		}
+	}
+	return p
+}
+
+// SimplifyChains places in dst a copy of the undirected graph g with each
+// chain of degree-2 nodes contracted into a single edge, and returns the
+// mapping from the edges of dst to the paths they replace in g. The weight of
+// each edge added to dst is the sum of the weights of the edges it replaces.
+// If g does not implement graph.Weighter, path.UniformCost is used.
+//
+// Degree-2 nodes are retained where contraction would create a self edge or
+// join a pair of nodes already joined by another edge, so dst is topologically
+// equivalent to g. A cycle of degree-2 nodes with no other attached nodes is
+// retained as a triangle.
+func SimplifyChains(dst graph.UndirectedBuilder, g graph.Undirected) Segments {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	keep := make(map[int]bool)
+	for _, n := range nodes {
+		if len(g.From(n)) != 2 {
+			keep[n.ID()] = true
+		}
+	}
+
+	s := make(Segments)
+	add := func(p []graph.Node) {
+		var w float64
+		for i := 1; i < len(p); i++ {
+			ew, ok := weight(p[i-1], p[i])
+			if !ok {
+				panic("transform: unexpected invalid weight")
+			}
+			w += ew
+		}
+		u, v := p[0], p[len(p)-1]
+		for _, n := range []graph.Node{u, v} {
+			if !dst.Has(n) {
+				dst.AddNode(n)
+			}
+		}
+		dst.SetEdge(simple.Edge{F: u, T: v, W: w})
+		uid, vid := u.ID(), v.ID()
+		if uid > vid {
+			uid, vid = vid, uid
+			p = append([]graph.Node(nil), p...)
+			for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+				p[i], p[j] = p[j], p[i]
+			}
+		}
+		s[[2]int{uid, vid}] = p
+	}
+
+	// walked holds the edges already included in a chain.
+	walked := make(map[[2]int]bool)
+	mark := func(u, v graph.Node) {
+		uid, vid := u.ID(), v.ID()
+		if uid > vid {
+			uid, vid = vid, uid
+		}
+		walked[[2]int{uid, vid}] = true
+	}
+	isWalked := func(u, v graph.Node) bool {
+		uid, vid := u.ID(), v.ID()
+		if uid > vid {
+			uid, vid = vid, uid
+		}
+		return walked[[2]int{uid, vid}]
+	}
+
+	chainsFrom := func(u graph.Node) {
+		to := g.From(u)
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if isWalked(u, v) {
+				continue
+			}
+			// Follow the chain from u through v.
+			p := []graph.Node{u, v}
+			mark(u, v)
+			for !keep[p[len(p)-1].ID()] {
+				cur, prev := p[len(p)-1], p[len(p)-2]
+				var next graph.Node
+				for _, n := range g.From(cur) {
+					if n.ID() != prev.ID() {
+						next = n
+						break
+					}
+				}
+				if next == nil {
+					// This can only happen if cur has
+					// reciprocal edges to prev.
+					next = prev
+				}
+				mark(cur, next)
+				p = append(p, next)
+			}
+
+			last := p[len(p)-1]
+			switch {
+			case last.ID() == u.ID():
+				// Retain the first and last interior
+				// nodes to avoid creating a self edge.
+				add(p[:2])
+				add(p[1 : len(p)-1])
+				add(p[len(p)-2:])
+			case dst.Has(u) && dst.Has(last) && dst.HasEdgeBetween(u, last):
+				// Retain the first interior node to
+				// avoid creating a parallel edge.
+				add(p[:2])
+				add(p[1:])
+			default:
+				add(p)
+			}
+		}
+	}
+
+	// Add edges directly joining retained nodes first
+	// so that parallel chains can be detected.
+	for _, u := range nodes {
+		if !keep[u.ID()] {
+			continue
+		}
+		if !dst.Has(u) {
+			dst.AddNode(u)
+		}
+		for _, v := range g.From(u) {
+			if keep[v.ID()] && !isWalked(u, v) {
+				mark(u, v)
+				add([]graph.Node{u, v})
+			}
+		}
+	}
+	for _, n := range nodes {
+		if keep[n.ID()] {
+			chainsFrom(n)
+		}
+	}
+	// Handle isolated cycles of degree-2 nodes.
+	for _, n := range nodes {
+		if keep[n.ID()] {
+			continue
+		}
+		to := g.From(n)
+		if isWalked(n, to[0]) {
+			continue
+		}
+		keep[n.ID()] = true
+		chainsFrom(n)
+	}
+
+	return s
+}
@@ -0,0 +1,146 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+	"github.com/gonum/graph/topo"
+)
+
+var simplifyChainsTests = []struct {
+	name  string
+	edges []simple.Edge
+
+	wantNodes int
+	wantEdges int
+}{
+	{
+		name: "path",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 2},
+			{F: simple.Node(2), T: simple.Node(3), W: 3},
+		},
+		wantNodes: 2,
+		wantEdges: 1,
+	},
+	{
+		name: "star with long arms",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(0), T: simple.Node(3), W: 1},
+			{F: simple.Node(3), T: simple.Node(4), W: 1},
+			{F: simple.Node(0), T: simple.Node(5), W: 1},
+			{F: simple.Node(5), T: simple.Node(6), W: 1},
+		},
+		wantNodes: 4,
+		wantEdges: 3,
+	},
+	{
+		name: "parallel chains",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(0), T: simple.Node(3), W: 4},
+			{F: simple.Node(3), T: simple.Node(2), W: 4},
+			{F: simple.Node(0), T: simple.Node(2), W: 5},
+			{F: simple.Node(0), T: simple.Node(9), W: 1},
+			{F: simple.Node(2), T: simple.Node(8), W: 1},
+		},
+		wantNodes: 6,
+		wantEdges: 7,
+	},
+	{
+		name: "loop",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+			{F: simple.Node(3), T: simple.Node(0), W: 1},
+			{F: simple.Node(0), T: simple.Node(4), W: 1},
+		},
+		wantNodes: 4,
+		wantEdges: 4,
+	},
+	{
+		name: "isolated cycle",
+		edges: []simple.Edge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+			{F: simple.Node(3), T: simple.Node(4), W: 1},
+			{F: simple.Node(4), T: simple.Node(0), W: 1},
+		},
+		wantNodes: 3,
+		wantEdges: 3,
+	},
+}
+
+func TestSimplifyChains(t *testing.T) {
+	for _, test := range simplifyChainsTests {
+		g := simple.NewUndirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetEdge(e)
+		}
+		dst := simple.NewUndirectedGraph(0, math.Inf(1))
+		segs := SimplifyChains(dst, g)
+
+		if n := len(dst.Nodes()); n != test.wantNodes {
+			t.Errorf("%q: unexpected number of nodes: got:%d want:%d", test.name, n, test.wantNodes)
+		}
+		if n := len(dst.Edges()); n != test.wantEdges {
+			t.Errorf("%q: unexpected number of edges: got:%d want:%d", test.name, n, test.wantEdges)
+		}
+		if len(segs) != len(dst.Edges()) {
+			t.Errorf("%q: segment count does not match edge count: %d != %d", test.name, len(segs), len(dst.Edges()))
+		}
+
+		covered := make(map[[2]int]bool)
+		for _, e := range dst.Edges() {
+			p := segs.Path(e.From(), e.To())
+			if p[0].ID() != e.From().ID() || p[len(p)-1].ID() != e.To().ID() {
+				t.Errorf("%q: unexpected segment orientation: %v for %d-%d", test.name, p, e.From().ID(), e.To().ID())
+			}
+			if !topo.IsPathIn(g, p) {
+				t.Errorf("%q: segment is not a path in the original graph: %v", test.name, p)
+			}
+			var w float64
+			for i := 1; i < len(p); i++ {
+				ew, _ := g.Weight(p[i-1], p[i])
+				w += ew
+				a, b := p[i-1].ID(), p[i].ID()
+				if a > b {
+					a, b = b, a
+				}
+				if covered[[2]int{a, b}] {
+					t.Errorf("%q: original edge %d-%d covered more than once", test.name, a, b)
+				}
+				covered[[2]int{a, b}] = true
+			}
+			if w != e.Weight() {
+				t.Errorf("%q: unexpected segment weight: got:%v want:%v", test.name, e.Weight(), w)
+			}
+		}
+		if len(covered) != len(test.edges) {
+			t.Errorf("%q: unexpected number of covered edges: got:%d want:%d", test.name, len(covered), len(test.edges))
+		}
+
+		// Shortest paths between retained nodes are preserved.
+		for _, u := range dst.Nodes() {
+			want := path.DijkstraFrom(u, g)
+			got := path.DijkstraFrom(u, dst)
+			for _, v := range dst.Nodes() {
+				if got.WeightTo(v) != want.WeightTo(v) {
+					t.Errorf("%q: unexpected distance %d-%d: got:%v want:%v", test.name, u.ID(), v.ID(), got.WeightTo(v), want.WeightTo(v))
+				}
+			}
+		}
+	}
+}
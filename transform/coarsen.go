@@ -0,0 +1,199 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+	"github.com/gonum/graph/path"
+	"github.com/gonum/graph/simple"
+)
+
+// MatchStrategy is a strategy for matching nodes during coarsening.
+type MatchStrategy int
+
+const (
+	// HeavyEdge matches each node with the
+	// unmatched neighbor joined to it by the
+	// heaviest edge, as described by Karypis
+	// and Kumar, doi:10.1137/S1064827595287997.
+	HeavyEdge MatchStrategy = iota
+
+	// RandomEdge matches each node with a
+	// random unmatched neighbor.
+	RandomEdge
+)
+
+// Hierarchy is a multilevel coarsening of a graph.
+type Hierarchy struct {
+	// Levels holds the graphs of the
+	// hierarchy from finest to coarsest.
+	// The first is the original graph
+	// and the nodes and edges of each
+	// following graph are *MetaNode and
+	// *MetaEdge values whose members are
+	// the nodes of the preceding graph.
+	Levels []graph.Undirected
+
+	// coarse holds the coarse levels and
+	// parent holds the mapping from the
+	// node IDs of each level to the IDs
+	// of the next level.
+	coarse []*simple.UndirectedGraph
+	parent []map[int]int
+}
+
+// Coarsen returns a hierarchy of up to levels coarsenings of g. Each coarser
+// graph is constructed by finding a maximal matching of the nodes of the finer
+// graph, visiting the nodes in random order and using the given strategy, and
+// aggregating each matched pair and each unmatched node into a *MetaNode as
+// by Aggregate. Absent edges in the coarse graphs have zero weight. Coarsening
+// stops early when no nodes can be matched. If g does not implement
+// graph.Weighter, path.UniformCost is used. If src is not nil it is used as
+// the random source, otherwise rand.Perm and rand.Intn are used.
+func Coarsen(g graph.Undirected, levels int, strategy MatchStrategy, src *rand.Rand) Hierarchy {
+	var (
+		perm func(int) []int
+		rnd  func(int) int
+	)
+	if src == nil {
+		perm, rnd = rand.Perm, rand.Intn
+	} else {
+		perm, rnd = src.Perm, src.Intn
+	}
+
+	h := Hierarchy{Levels: []graph.Undirected{g}}
+	for i := 0; i < levels; i++ {
+		fine := h.Levels[i]
+		groups := match(fine, strategy, perm, rnd)
+		if len(groups) == len(fine.Nodes()) {
+			break
+		}
+		coarse := simple.NewUndirectedGraph(0, 0)
+		Aggregate(coarse, fine, groups)
+		parent := make(map[int]int)
+		for id, c := range groups {
+			for _, n := range c {
+				parent[n.ID()] = id
+			}
+		}
+		h.Levels = append(h.Levels, coarse)
+		h.coarse = append(h.coarse, coarse)
+		h.parent = append(h.parent, parent)
+	}
+	return h
+}
+
+// match returns a maximal matching of the nodes of g as groups of matched
+// pairs and unmatched nodes, ordered by their lowest member ID.
+func match(g graph.Undirected, strategy MatchStrategy, perm func(int) []int, rnd func(int) int) [][]graph.Node {
+	var weight path.Weighting
+	if wg, ok := g.(graph.Weighter); ok {
+		weight = wg.Weight
+	} else {
+		weight = path.UniformCost(g)
+	}
+
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	matched := make(map[int]bool, len(nodes))
+	var groups [][]graph.Node
+	for _, i := range perm(len(nodes)) {
+		u := nodes[i]
+		if matched[u.ID()] {
+			continue
+		}
+		matched[u.ID()] = true
+
+		var candidates []graph.Node
+		for _, v := range g.From(u) {
+			if !matched[v.ID()] {
+				candidates = append(candidates, v)
+			}
+		}
+		if len(candidates) == 0 {
+			groups = append(groups, []graph.Node{u})
+			continue
+		}
+		sort.Sort(ordered.ByID(candidates))
+
+		var mate graph.Node
+		switch strategy {
+		case HeavyEdge:
+			var heaviest float64
+			for _, v := range candidates {
+				w, ok := weight(u, v)
+				if !ok {
+					panic("transform: unexpected invalid weight")
+				}
+				if mate == nil || w > heaviest {
+					mate, heaviest = v, w
+				}
+			}
+		case RandomEdge:
+			mate = candidates[rnd(len(candidates))]
+		default:
+			panic("transform: unknown match strategy")
+		}
+		matched[mate.ID()] = true
+		pair := []graph.Node{u, mate}
+		sort.Sort(ordered.ByID(pair))
+		groups = append(groups, pair)
+	}
+	sort.Sort(byFirstID(groups))
+	return groups
+}
+
+// byFirstID sorts node groups by the ID of their first node.
+type byFirstID [][]graph.Node
+
+func (g byFirstID) Len() int           { return len(g) }
+func (g byFirstID) Less(i, j int) bool { return g[i][0].ID() < g[j][0].ID() }
+func (g byFirstID) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+
+// Project returns the node at the level to representing the node n at the
+// level from. If n is not in the graph at level from, Project returns nil.
+// Project will panic if to is a finer level than from.
+func (h Hierarchy) Project(n graph.Node, from, to int) graph.Node {
+	if to < from {
+		panic("transform: projection to finer level")
+	}
+	if !h.Levels[from].Has(n) {
+		return nil
+	}
+	if to == from {
+		return n
+	}
+	id := n.ID()
+	for i := from; i < to; i++ {
+		id = h.parent[i][id]
+	}
+	return h.coarse[to-1].Node(id)
+}
+
+// Expand returns the nodes of the original graph represented by the node n
+// at the given level, ordered by ID. If n is not in the graph at that level,
+// Expand returns nil.
+func (h Hierarchy) Expand(n graph.Node, level int) []graph.Node {
+	if !h.Levels[level].Has(n) {
+		return nil
+	}
+	if level == 0 {
+		return []graph.Node{n}
+	}
+	nodes := []graph.Node{h.coarse[level-1].Node(n.ID())}
+	for i := level; i > 0; i-- {
+		var members []graph.Node
+		for _, m := range nodes {
+			members = append(members, m.(*MetaNode).Members...)
+		}
+		nodes = members
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return nodes
+}
@@ -0,0 +1,112 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestCoarsenHeavyEdge(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.Edge{
+		{F: simple.Node(0), T: simple.Node(1), W: 10},
+		{F: simple.Node(0), T: simple.Node(2), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(2), T: simple.Node(3), W: 10},
+	} {
+		g.SetEdge(e)
+	}
+	for seed := int64(0); seed < 10; seed++ {
+		h := Coarsen(g, 5, HeavyEdge, rand.New(rand.NewSource(seed)))
+		if len(h.Levels) != 3 {
+			t.Fatalf("unexpected number of levels: got:%d want:3", len(h.Levels))
+		}
+		c := h.Levels[1]
+		if n := len(c.Nodes()); n != 2 {
+			t.Fatalf("unexpected number of coarse nodes: got:%d want:2", n)
+		}
+		e := c.EdgeBetween(h.Project(simple.Node(0), 0, 1), h.Project(simple.Node(3), 0, 1))
+		if e == nil || e.Weight() != 2 || e.(*MetaEdge).Count != 2 {
+			t.Errorf("unexpected coarse edge: got:%v", e)
+		}
+		for _, want := range [][]graph.Node{
+			{simple.Node(0), simple.Node(1)},
+			{simple.Node(2), simple.Node(3)},
+		} {
+			if got := h.Expand(h.Project(want[0], 0, 1), 1); !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected expansion: got:%v want:%v", got, want)
+			}
+		}
+	}
+}
+
+func TestCoarsen(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 200
+	g := simple.NewUndirectedGraph(0, math.Inf(1))
+	var total float64
+	for i := 0; i < 3*n; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u == v || g.HasEdgeBetween(simple.Node(u), simple.Node(v)) {
+			continue
+		}
+		w := float64(1 + rnd.Intn(5))
+		g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v), W: w})
+		total += w
+	}
+
+	for _, strategy := range []MatchStrategy{HeavyEdge, RandomEdge} {
+		h := Coarsen(g, 4, strategy, rnd)
+		if len(h.Levels) != 5 {
+			t.Fatalf("unexpected number of levels: got:%d want:5", len(h.Levels))
+		}
+		var internal float64
+		for i := 1; i < len(h.Levels); i++ {
+			fine, coarse := len(h.Levels[i-1].Nodes()), len(h.Levels[i].Nodes())
+			if coarse >= fine || 2*coarse < fine {
+				t.Errorf("unexpected coarsening at level %d: %d nodes to %d", i, fine, coarse)
+			}
+			for _, m := range h.Levels[i].Nodes() {
+				internal += m.(*MetaNode).Weight
+			}
+		}
+		last := h.Levels[len(h.Levels)-1]
+		var external float64
+		for _, e := range last.(*simple.UndirectedGraph).Edges() {
+			external += e.Weight()
+		}
+		if internal+external != total {
+			t.Errorf("unexpected total weight: got:%v want:%v", internal+external, total)
+		}
+
+		// Every original node is represented by exactly
+		// one node at each level.
+		for level := range h.Levels {
+			seen := make(map[int]int)
+			for _, c := range h.Levels[level].Nodes() {
+				for _, m := range h.Expand(c, level) {
+					seen[m.ID()]++
+					if p := h.Project(m, 0, level); p.ID() != c.ID() {
+						t.Errorf("unexpected projection of %d to level %d: got:%d want:%d", m.ID(), level, p.ID(), c.ID())
+					}
+				}
+			}
+			if len(seen) != len(g.Nodes()) {
+				t.Errorf("unexpected number of expanded nodes at level %d: got:%d want:%d", level, len(seen), len(g.Nodes()))
+			}
+			for id, k := range seen {
+				if k != 1 {
+					t.Errorf("node %d represented %d times at level %d", id, k, level)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+// Bitset is a dense VisitSet for graphs with node IDs in a contiguous range
+// starting at zero. Its memory use is proportional to the largest ID
+// inserted, so it is faster than the default sparse set when node IDs are
+// dense, and a Bitset may be reused by traversals over the same graph
+// without reallocation.
+type Bitset struct {
+	words []uint64
+	n     int
+}
+
+// NewBitset returns a Bitset with space for IDs in [0, n). The Bitset grows
+// if larger IDs are inserted.
+func NewBitset(n int) *Bitset {
+	if n < 0 {
+		n = 0
+	}
+	return &Bitset{words: make([]uint64, (n+63)/64)}
+}
+
+// Insert adds id to the set and returns whether it was added. Insert will
+// panic if id is negative.
+func (s *Bitset) Insert(id int) bool {
+	if id < 0 {
+		panic("traverse: negative ID in bitset")
+	}
+	w := id / 64
+	if w >= len(s.words) {
+		words := make([]uint64, w+1, 2*(w+1))
+		copy(words, s.words)
+		s.words = words
+	}
+	mask := uint64(1) << uint(id%64)
+	if s.words[w]&mask != 0 {
+		return false
+	}
+	s.words[w] |= mask
+	s.n++
+	return true
+}
+
+// Has returns whether id is in the set.
+func (s *Bitset) Has(id int) bool {
+	if id < 0 || id/64 >= len(s.words) {
+		return false
+	}
+	return s.words[id/64]&(uint64(1)<<uint(id%64)) != 0
+}
+
+// Len returns the number of elements in the set.
+func (s *Bitset) Len() int { return s.n }
+
+// Clear removes all elements from the set, retaining its storage.
+func (s *Bitset) Clear() {
+	for i := range s.words {
+		s.words[i] = 0
+	}
+	s.n = 0
+}
@@ -0,0 +1,63 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+func TestBitset(t *testing.T) {
+	s := NewBitset(10)
+	for _, id := range []int{0, 3, 63, 64, 200} {
+		if !s.Insert(id) {
+			t.Errorf("failed to insert %d", id)
+		}
+		if s.Insert(id) {
+			t.Errorf("unexpected reinsertion of %d", id)
+		}
+	}
+	if s.Len() != 5 {
+		t.Errorf("unexpected length: got:%d want:5", s.Len())
+	}
+	for id := -1; id < 300; id++ {
+		want := id == 0 || id == 3 || id == 63 || id == 64 || id == 200
+		if s.Has(id) != want {
+			t.Errorf("unexpected membership of %d: got:%t want:%t", id, !want, want)
+		}
+	}
+	s.Clear()
+	if s.Len() != 0 || s.Has(200) {
+		t.Error("set not empty after clear")
+	}
+}
+
+func TestWalkReuseBitset(t *testing.T) {
+	g := simple.NewUndirectedGraph(0, 0)
+	for u, e := range wpBronKerboschGraph {
+		if !g.Has(simple.Node(u)) {
+			g.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	n := len(g.Nodes())
+
+	set := NewBitset(n)
+	bf := BreadthFirst{Set: set}
+	df := DepthFirst{Set: set}
+	for i := 0; i < 3; i++ {
+		bf.Reset()
+		bf.Walk(g, simple.Node(i), nil)
+		bfVisited := set.Len()
+		df.Reset()
+		df.Walk(g, simple.Node(i), nil)
+		if set.Len() != bfVisited {
+			t.Errorf("unexpected number of nodes visited from %d: breadth first:%d depth first:%d", i, bfVisited, set.Len())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import "math"
+
+// Bloom is a Bloom filter VisitSet. It uses a fixed amount of memory
+// regardless of the range of node IDs inserted, at the cost of a small
+// probability that Has reports an ID that was never inserted.
+//
+// When a Bloom filter is used as the visited set of a traversal, a false
+// positive causes the traversal to treat an unvisited node as visited, so
+// that node and any nodes only reachable through it may not be visited.
+type Bloom struct {
+	bits []uint64
+	k    int
+	n    int
+}
+
+// NewBloom returns a Bloom filter sized to hold n elements with a false
+// positive probability of at most approximately p once n elements have been
+// inserted. NewBloom will panic if n is not positive or p is not in (0, 1).
+func NewBloom(n int, p float64) *Bloom {
+	if n <= 0 {
+		panic("traverse: non-positive bloom filter size")
+	}
+	if !(0 < p && p < 1) {
+		panic("traverse: bloom filter probability out of range")
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := int(math.Ceil(m / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &Bloom{bits: make([]uint64, (int(m)+63)/64), k: k}
+}
+
+// Insert adds id to the filter and returns whether it was added. Insert
+// returns false if id was already present or is a false positive.
+func (b *Bloom) Insert(id int) bool {
+	m := uint64(len(b.bits)) * 64
+	h1, h2 := hashes(id)
+	added := false
+	for i := 0; i < b.k; i++ {
+		bit := (h1 + uint64(i)*h2) % m
+		w, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[w]&mask == 0 {
+			b.bits[w] |= mask
+			added = true
+		}
+	}
+	if added {
+		b.n++
+	}
+	return added
+}
+
+// Has returns whether id may be in the filter. Has never returns false for
+// an inserted ID.
+func (b *Bloom) Has(id int) bool {
+	m := uint64(len(b.bits)) * 64
+	h1, h2 := hashes(id)
+	for i := 0; i < b.k; i++ {
+		bit := (h1 + uint64(i)*h2) % m
+		if b.bits[bit/64]&(uint64(1)<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of successful insertions into the filter.
+func (b *Bloom) Len() int { return b.n }
+
+// Clear removes all elements from the filter.
+func (b *Bloom) Clear() {
+	b.n = 0
+	for i := range b.bits {
+		b.bits[i] = 0
+	}
+}
+
+// hashes returns two independent hashes of id for double hashing. The
+// second hash is odd so that it is never zero.
+func hashes(id int) (h1, h2 uint64) {
+	x := uint64(id)
+	h1 = splitmix64(x)
+	h2 = splitmix64(h1) | 1
+	return h1, h2
+}
+
+// splitmix64 is the finaliser of the SplitMix64 generator.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
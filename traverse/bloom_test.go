@@ -0,0 +1,86 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestBloom(t *testing.T) {
+	const (
+		n = 10000
+		p = 0.01
+	)
+	b := NewBloom(n, p)
+	rnd := rand.New(rand.NewSource(1))
+	inserted := make(map[int]bool)
+	for len(inserted) < n {
+		id := rnd.Int() >> 8
+		inserted[id] = true
+		b.Insert(id)
+	}
+	for id := range inserted {
+		if !b.Has(id) {
+			t.Fatalf("false negative for %d", id)
+		}
+	}
+
+	var tests, fp int
+	for tests < 100000 {
+		id := rnd.Int() >> 8
+		if inserted[id] {
+			continue
+		}
+		tests++
+		if b.Has(id) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / float64(tests); rate > 2*p {
+		t.Errorf("unexpected false positive rate: got:%v want:<=%v", rate, 2*p)
+	}
+
+	b.Clear()
+	for id := range inserted {
+		if b.Has(id) {
+			t.Fatalf("unexpected element %d after clear", id)
+		}
+	}
+	if !b.Insert(1) {
+		t.Error("failed to insert into cleared filter")
+	}
+	if b.Insert(1) {
+		t.Error("unexpected reinsertion")
+	}
+}
+
+func TestBreadthFirstBloom(t *testing.T) {
+	const n = 1000
+	g := simple.NewUndirectedGraph(0, 0)
+	for i := 0; i < n; i++ {
+		// Spread node IDs over a large range.
+		g.AddNode(simple.Node(i << 20))
+	}
+	for i := 1; i < n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node((i - 1) << 20), T: simple.Node(i << 20), W: 1})
+	}
+
+	w := BreadthFirst{Set: NewBloom(n, 1e-6)}
+	var got int
+	w.Walk(g, simple.Node(0), func(graph.Node, int) bool {
+		got++
+		return false
+	})
+	if got != n {
+		t.Errorf("unexpected number of visited nodes: got:%d want:%d", got, n)
+	}
+	if !w.Visited(simple.Node((n - 1) << 20)) {
+		t.Error("expected last node to be visited")
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"sort"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/internal/ordered"
+)
+
+const (
+	// defaultAlpha and defaultBeta are the switching
+	// parameters recommended by Beamer et al.
+	defaultAlpha = 14
+	defaultBeta  = 24
+)
+
+// DirectionOptimizing implements direction-optimizing breadth-first search
+// as described by Beamer, Asanović and Patterson in doi:10.1109/SC.2012.50.
+// Each level of the search is expanded either top-down, by following the
+// out-edges of the frontier, or bottom-up, by searching the in-edges of
+// unvisited nodes for a parent in the frontier. Bottom-up steps are much
+// cheaper when the frontier is a large fraction of the graph, as it is in
+// the middle levels of a search of a low-diameter graph.
+//
+// The adjacency of the graph is copied into compressed sparse row form when
+// the DirectionOptimizing is created, so repeated searches do not query the
+// graph. Changes to the graph after creation are not seen.
+type DirectionOptimizing struct {
+	// Alpha and Beta control switching
+	// between top-down and bottom-up steps.
+	// The search switches to bottom-up when
+	// the number of edges leaving the frontier
+	// exceeds the number leaving unvisited
+	// nodes divided by Alpha, and back to
+	// top-down when the frontier holds fewer
+	// than the number of nodes divided by
+	// Beta. If zero, Alpha is 14 and Beta
+	// is 24.
+	Alpha, Beta float64
+
+	nodes   []graph.Node
+	indexOf map[int]int
+
+	outStart, out []int
+	inStart, in   []int
+
+	depth  []int
+	parent []int
+
+	// bottomUp is the number of bottom-up
+	// steps taken by the last search.
+	bottomUp int
+}
+
+// NewDirectionOptimizing returns a DirectionOptimizing for searching g.
+func NewDirectionOptimizing(g graph.Directed) *DirectionOptimizing {
+	nodes := g.Nodes()
+	sort.Sort(ordered.ByID(nodes))
+	d := &DirectionOptimizing{
+		nodes:    nodes,
+		indexOf:  make(map[int]int, len(nodes)),
+		outStart: make([]int, len(nodes)+1),
+		inStart:  make([]int, len(nodes)+1),
+		depth:    make([]int, len(nodes)),
+		parent:   make([]int, len(nodes)),
+	}
+	for i, n := range nodes {
+		d.indexOf[n.ID()] = i
+	}
+	for i, u := range nodes {
+		for _, v := range g.From(u) {
+			d.out = append(d.out, d.indexOf[v.ID()])
+		}
+		d.outStart[i+1] = len(d.out)
+		for _, v := range g.To(u) {
+			d.in = append(d.in, d.indexOf[v.ID()])
+		}
+		d.inStart[i+1] = len(d.in)
+	}
+	for i := range d.depth {
+		d.depth[i] = -1
+		d.parent[i] = -1
+	}
+	return d
+}
+
+// Walk performs a breadth-first search from the node from and returns the
+// nodes reached at each depth, starting with from at depth zero. Walk will
+// panic if from is not in the graph.
+func (d *DirectionOptimizing) Walk(from graph.Node) [][]graph.Node {
+	s, ok := d.indexOf[from.ID()]
+	if !ok {
+		panic("traverse: search from node not in graph")
+	}
+	alpha, beta := d.Alpha, d.Beta
+	if alpha == 0 {
+		alpha = defaultAlpha
+	}
+	if beta == 0 {
+		beta = defaultBeta
+	}
+	for i := range d.depth {
+		d.depth[i] = -1
+		d.parent[i] = -1
+	}
+	d.bottomUp = 0
+
+	d.depth[s] = 0
+	frontier := []int{s}
+	levels := [][]graph.Node{{from}}
+
+	// unexplored is the number of edges
+	// leaving unvisited nodes.
+	unexplored := len(d.out) - d.outDegree(s)
+	bottomUp := false
+	for level := 1; len(frontier) != 0; level++ {
+		var edges int
+		for _, u := range frontier {
+			edges += d.outDegree(u)
+		}
+		if !bottomUp && float64(edges) > float64(unexplored)/alpha {
+			bottomUp = true
+		} else if bottomUp && float64(len(frontier)) < float64(len(d.nodes))/beta {
+			bottomUp = false
+		}
+
+		var next []int
+		if bottomUp {
+			d.bottomUp++
+			next = d.bottomUpStep(level)
+		} else {
+			next = d.topDownStep(frontier, level)
+		}
+		if len(next) == 0 {
+			break
+		}
+		nodes := make([]graph.Node, len(next))
+		for i, v := range next {
+			nodes[i] = d.nodes[v]
+			unexplored -= d.outDegree(v)
+		}
+		levels = append(levels, nodes)
+		frontier = next
+	}
+	return levels
+}
+
+// topDownStep visits the unvisited out-neighbors of the frontier.
+func (d *DirectionOptimizing) topDownStep(frontier []int, level int) []int {
+	var next []int
+	for _, u := range frontier {
+		for _, v := range d.out[d.outStart[u]:d.outStart[u+1]] {
+			if d.depth[v] >= 0 {
+				continue
+			}
+			d.depth[v] = level
+			d.parent[v] = u
+			next = append(next, v)
+		}
+	}
+	return next
+}
+
+// bottomUpStep visits the unvisited nodes with an in-neighbor in the
+// frontier, which is the set of nodes at depth level-1.
+func (d *DirectionOptimizing) bottomUpStep(level int) []int {
+	var next []int
+	for v, dv := range d.depth {
+		if dv >= 0 {
+			continue
+		}
+		for _, u := range d.in[d.inStart[v]:d.inStart[v+1]] {
+			if d.depth[u] == level-1 {
+				d.depth[v] = level
+				d.parent[v] = u
+				next = append(next, v)
+				break
+			}
+		}
+	}
+	return next
+}
+
+func (d *DirectionOptimizing) outDegree(u int) int {
+	return d.outStart[u+1] - d.outStart[u]
+}
+
+// Depth returns the depth of n in the last search, or -1 if n was not
+// reached.
+func (d *DirectionOptimizing) Depth(n graph.Node) int {
+	i, ok := d.indexOf[n.ID()]
+	if !ok {
+		return -1
+	}
+	return d.depth[i]
+}
+
+// Parent returns the parent of n in the breadth-first tree of the last
+// search, or nil if n is the root of the search or was not reached.
+func (d *DirectionOptimizing) Parent(n graph.Node) graph.Node {
+	i, ok := d.indexOf[n.ID()]
+	if !ok || d.parent[i] < 0 {
+		return nil
+	}
+	return d.nodes[d.parent[i]]
+}
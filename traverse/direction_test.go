@@ -0,0 +1,112 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package traverse
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/graphs/gen"
+	"github.com/gonum/graph/simple"
+)
+
+func gnpDirected(n int, p float64, seed int64) graph.Directed {
+	g := simple.NewDirectedGraph(0, 0)
+	gen.Gnp(g, n, p, rand.New(rand.NewSource(seed)))
+	return g
+}
+
+var directionOptimizingTests = []struct {
+	n     int
+	p     float64
+	alpha float64
+
+	wantBottomUp bool
+}{
+	{n: 10, p: 0.2, alpha: 1e-9},
+	{n: 100, p: 0.05, alpha: 1e-9},
+	{n: 1000, p: 0.01, alpha: 1e-9},
+	{n: 100, p: 0.05, wantBottomUp: true},
+	{n: 1000, p: 0.01, wantBottomUp: true},
+	{n: 1000, p: 0.002},
+}
+
+func TestDirectionOptimizing(t *testing.T) {
+	for i, test := range directionOptimizingTests {
+		g := gnpDirected(test.n, test.p, int64(i))
+		d := NewDirectionOptimizing(g)
+		d.Alpha = test.alpha
+		for _, from := range g.Nodes()[:5] {
+			want := make(map[int]int)
+			var bf BreadthFirst
+			bf.Walk(g, from, func(n graph.Node, depth int) bool {
+				want[n.ID()] = depth
+				return false
+			})
+
+			levels := d.Walk(from)
+			if test.wantBottomUp && d.bottomUp == 0 {
+				t.Errorf("test %d: expected bottom-up steps from %d", i, from.ID())
+			}
+			var reached int
+			for depth, level := range levels {
+				for _, n := range level {
+					reached++
+					if want[n.ID()] != depth {
+						t.Errorf("test %d: unexpected depth for %d from %d: got:%d want:%d",
+							i, n.ID(), from.ID(), depth, want[n.ID()])
+					}
+					if d.Depth(n) != depth {
+						t.Errorf("test %d: unexpected Depth for %d from %d: got:%d want:%d",
+							i, n.ID(), from.ID(), d.Depth(n), depth)
+					}
+					p := d.Parent(n)
+					if depth == 0 {
+						if p != nil {
+							t.Errorf("test %d: unexpected parent for root %d", i, n.ID())
+						}
+						continue
+					}
+					if p == nil || !g.HasEdgeFromTo(p, n) || d.Depth(p) != depth-1 {
+						t.Errorf("test %d: invalid parent for %d from %d", i, n.ID(), from.ID())
+					}
+				}
+			}
+			if reached != len(want) {
+				t.Errorf("test %d: unexpected number of nodes reached from %d: got:%d want:%d",
+					i, from.ID(), reached, len(want))
+			}
+		}
+	}
+}
+
+func benchmarkBreadthFirstDirected(b *testing.B, g graph.Directed) {
+	from := g.Nodes()[0]
+	var bf BreadthFirst
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bf.Reset()
+		bf.Walk(g, from, nil)
+	}
+}
+
+func benchmarkDirectionOptimizing(b *testing.B, g graph.Directed) {
+	from := g.Nodes()[0]
+	d := NewDirectionOptimizing(g)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Walk(from)
+	}
+}
+
+var gnpDirected_10000_thousandth = gnpDirected(10000, 0.001, 1)
+
+func BenchmarkBreadthFirstGnpDirected_10000_thousandth(b *testing.B) {
+	benchmarkBreadthFirstDirected(b, gnpDirected_10000_thousandth)
+}
+func BenchmarkDirectionOptimizingGnpDirected_10000_thousandth(b *testing.B) {
+	benchmarkDirectionOptimizing(b, gnpDirected_10000_thousandth)
+}
@@ -12,12 +12,38 @@ import (
 	"github.com/gonum/graph/internal/linear"
 )
 
+// VisitSet is a set of node IDs used to record visited nodes during
+// a traversal. *intsets.Sparse satisfies VisitSet.
+type VisitSet interface {
+	// Insert adds id to the set and
+	// returns whether it was added.
+	Insert(id int) bool
+
+	// Has returns whether id may be
+	// in the set.
+	Has(id int) bool
+
+	// Len returns the number of
+	// elements in the set.
+	Len() int
+
+	// Clear removes all elements
+	// from the set.
+	Clear()
+}
+
 // BreadthFirst implements stateful breadth-first graph traversal.
 type BreadthFirst struct {
 	EdgeFilter func(graph.Edge) bool
 	Visit      func(u, v graph.Node)
-	queue      linear.NodeQueue
-	visited    *intsets.Sparse
+
+	// Set is used to record visited
+	// nodes if it is not nil. Otherwise
+	// a sparse bitset is used.
+	Set VisitSet
+
+	queue   linear.NodeQueue
+	visited VisitSet
 }
 
 // Walk performs a breadth-first traversal of the graph g starting from the given node,
@@ -27,7 +53,7 @@ type BreadthFirst struct {
 // non-nil, it is called with the nodes joined by each followed edge.
 func (b *BreadthFirst) Walk(g graph.Graph, from graph.Node, until func(n graph.Node, d int) bool) graph.Node {
 	if b.visited == nil {
-		b.visited = &intsets.Sparse{}
+		b.visited = newVisitSet(b.Set)
 	}
 	b.queue.Enqueue(from)
 	b.visited.Insert(from.ID())
@@ -96,9 +122,14 @@ func (b *BreadthFirst) Visited(n graph.Node) bool {
 	return b.visited != nil && b.visited.Has(n.ID())
 }
 
-// Reset resets the state of the traverser for reuse.
+// Reset resets the state of the traverser for reuse. The storage of the
+// frontier and the visited set is retained, and the Set field, if non-nil,
+// becomes the visited set.
 func (b *BreadthFirst) Reset() {
 	b.queue.Reset()
+	if b.Set != nil {
+		b.visited = b.Set
+	}
 	if b.visited != nil {
 		b.visited.Clear()
 	}
@@ -108,8 +139,14 @@ func (b *BreadthFirst) Reset() {
 type DepthFirst struct {
 	EdgeFilter func(graph.Edge) bool
 	Visit      func(u, v graph.Node)
-	stack      linear.NodeStack
-	visited    *intsets.Sparse
+
+	// Set is used to record visited
+	// nodes if it is not nil. Otherwise
+	// a sparse bitset is used.
+	Set VisitSet
+
+	stack   linear.NodeStack
+	visited VisitSet
 }
 
 // Walk performs a depth-first traversal of the graph g starting from the given node,
@@ -119,7 +156,7 @@ type DepthFirst struct {
 // is called with the nodes joined by each followed edge.
 func (d *DepthFirst) Walk(g graph.Graph, from graph.Node, until func(graph.Node) bool) graph.Node {
 	if d.visited == nil {
-		d.visited = &intsets.Sparse{}
+		d.visited = newVisitSet(d.Set)
 	}
 	d.stack.Push(from)
 	d.visited.Insert(from.ID())
@@ -177,10 +214,22 @@ func (d *DepthFirst) Visited(n graph.Node) bool {
 	return d.visited != nil && d.visited.Has(n.ID())
 }
 
-// Reset resets the state of the traverser for reuse.
+// Reset resets the state of the traverser for reuse. The storage of the
+// frontier and the visited set is retained, and the Set field, if non-nil,
+// becomes the visited set.
 func (d *DepthFirst) Reset() {
 	d.stack = d.stack[:0]
+	if d.Set != nil {
+		d.visited = d.Set
+	}
 	if d.visited != nil {
 		d.visited.Clear()
 	}
 }
+
+func newVisitSet(s VisitSet) VisitSet {
+	if s != nil {
+		return s
+	}
+	return &intsets.Sparse{}
+}
@@ -0,0 +1,95 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versioned
+
+// Tx is a transaction over a Graph. Mutations made through the Tx are applied
+// to the Graph immediately and are either kept as a single step of the Graph's
+// history by Commit or reverted by Abort. Mutations must not be made to the
+// Graph except through the Tx while the transaction is open.
+type Tx struct {
+	*Graph
+
+	// pos is the length of the
+	// history when the transaction
+	// began.
+	pos    int
+	closed bool
+}
+
+// Begin starts a transaction on g. Undone steps of g are discarded.
+func (g *Graph) Begin() *Tx {
+	g.discardUndone()
+	return &Tx{Graph: g, pos: len(g.done)}
+}
+
+// Begin starts a transaction on the graph g, which need not be a Graph. The
+// returned Tx is backed by a new Graph wrapping g.
+func Begin(g Mutable) *Tx {
+	return New(g).Begin()
+}
+
+// Commit closes the transaction, keeping its mutations as a single step of the
+// history. Checkpoints made during the transaction are discarded. Commit panics
+// if the transaction has already been closed.
+func (tx *Tx) Commit() {
+	tx.close()
+	if len(tx.done) <= tx.pos {
+		return
+	}
+	var step []Mutation
+	for _, s := range tx.done[tx.pos:] {
+		step = append(step, s...)
+	}
+	tx.done = append(tx.done[:tx.pos], step)
+}
+
+// Abort closes the transaction, reverting its mutations and leaving the graph
+// in the state it had when the transaction began. Checkpoints made during the
+// transaction are discarded. Abort panics if the transaction has already been
+// closed.
+func (tx *Tx) Abort() {
+	tx.close()
+	for len(tx.done) > tx.pos {
+		tx.Undo()
+	}
+	tx.undone = nil
+}
+
+func (tx *Tx) close() {
+	if tx.closed {
+		panic("versioned: transaction closed")
+	}
+	tx.closed = true
+	for tag, pos := range tx.checkpoints {
+		if pos > tx.pos {
+			delete(tx.checkpoints, tag)
+		}
+	}
+}
+
+// Atomic calls fn with a transaction on g. If fn returns a non-nil error or
+// panics, the mutations made by fn are reverted; the error is returned or the
+// panic is propagated. Otherwise the mutations are committed.
+func Atomic(g Mutable, fn func(tx *Tx) error) (err error) {
+	var tx *Tx
+	if vg, ok := g.(*Graph); ok {
+		tx = vg.Begin()
+	} else {
+		tx = Begin(g)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Abort()
+			panic(r)
+		}
+	}()
+	err = fn(tx)
+	if err != nil {
+		tx.Abort()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
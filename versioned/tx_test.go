@@ -0,0 +1,120 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versioned
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+func TestTxCommit(t *testing.T) {
+	g := New(simple.NewDirectedGraph(0, math.Inf(1)))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	before := simple.NewDirectedGraph(0, math.Inf(1))
+	graph.Copy(before, g)
+
+	tx := g.Begin()
+	tx.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	tx.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3), W: 1})
+	tx.RemoveNode(simple.Node(0))
+	tx.Commit()
+
+	if g.Has(simple.Node(0)) || !g.HasEdgeBetween(simple.Node(2), simple.Node(3)) {
+		t.Errorf("transaction mutations not applied")
+	}
+	if !g.Undo() {
+		t.Fatalf("unexpected failed undo")
+	}
+	if !sameState(g, before) {
+		t.Errorf("committed transaction not undone as a single step")
+	}
+	if !g.Undo() || g.Undo() {
+		t.Errorf("unexpected history length after commit")
+	}
+}
+
+func TestTxAbort(t *testing.T) {
+	base := simple.NewUndirectedGraph(0, math.Inf(1))
+	base.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	before := simple.NewUndirectedGraph(0, math.Inf(1))
+	graph.Copy(before, base)
+
+	tx := Begin(base)
+	tx.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 5})
+	tx.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	tx.RemoveNode(simple.Node(0))
+	tag := tx.Checkpoint()
+	tx.Abort()
+
+	if !sameState(base, before) {
+		t.Errorf("aborted transaction altered graph")
+	}
+	if tx.Redo() {
+		t.Errorf("unexpected redo after abort")
+	}
+	if err := tx.Rollback(tag); err != ErrUnknownCheckpoint {
+		t.Errorf("expected checkpoint in aborted transaction to be discarded: got:%v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic for closed transaction")
+			}
+		}()
+		tx.Commit()
+	}()
+}
+
+func TestAtomic(t *testing.T) {
+	g := simple.NewDirectedGraph(0, math.Inf(1))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	before := simple.NewDirectedGraph(0, math.Inf(1))
+	graph.Copy(before, g)
+
+	errInvalid := errors.New("invalid")
+	err := Atomic(g, func(tx *Tx) error {
+		tx.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+		return errInvalid
+	})
+	if err != errInvalid {
+		t.Errorf("unexpected error: got:%v want:%v", err, errInvalid)
+	}
+	if !sameState(g, before) {
+		t.Errorf("failed Atomic call altered graph")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic to propagate")
+			}
+		}()
+		Atomic(g, func(tx *Tx) error {
+			tx.RemoveNode(simple.Node(0))
+			// Adding an existing node panics.
+			tx.AddNode(simple.Node(1))
+			return nil
+		})
+	}()
+	if !sameState(g, before) {
+		t.Errorf("panicking Atomic call altered graph")
+	}
+
+	err = Atomic(g, func(tx *Tx) error {
+		tx.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !g.HasEdgeFromTo(simple.Node(1), simple.Node(2)) {
+		t.Errorf("successful Atomic call not applied")
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package versioned provides a graph wrapper that records mutations,
+// allowing them to be undone and redone.
+package versioned
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gonum/graph"
+)
+
+// Mutable is a graph that can have nodes and edges added and removed.
+type Mutable interface {
+	graph.Graph
+	graph.Builder
+	graph.NodeRemover
+	graph.EdgeRemover
+}
+
+// Op is the kind of a graph mutation.
+type Op int
+
+const (
+	// AddNode is the addition of a node.
+	AddNode Op = iota
+	// RemoveNode is the removal of a node
+	// and its incident edges.
+	RemoveNode
+	// SetEdge is the addition or
+	// replacement of an edge.
+	SetEdge
+	// RemoveEdge is the removal of an edge.
+	RemoveEdge
+)
+
+func (o Op) String() string {
+	switch o {
+	case AddNode:
+		return "add node"
+	case RemoveNode:
+		return "remove node"
+	case SetEdge:
+		return "set edge"
+	case RemoveEdge:
+		return "remove edge"
+	}
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+// Mutation is a recorded mutation of a graph. Node is set for node
+// mutations and Edge is set for edge mutations.
+type Mutation struct {
+	Op   Op
+	Node graph.Node
+	Edge graph.Edge
+
+	// prev is the edge replaced by
+	// SetEdge, or nil if there was
+	// no edge.
+	prev graph.Edge
+
+	// incident holds the edges
+	// removed with a node.
+	incident []graph.Edge
+}
+
+// Graph is a graph wrapper that records the mutations made through it. Each
+// mutation is a step in the history of the graph that may be undone and redone.
+// Mutations must not be made to the wrapped graph except through the Graph.
+type Graph struct {
+	Mutable
+
+	// done and undone hold the steps
+	// of the history before and after
+	// the current state.
+	done, undone [][]Mutation
+
+	// checkpoints holds the length of
+	// done at each live checkpoint.
+	checkpoints map[int]int
+	nextTag     int
+
+	subs subscribers
+}
+
+// New returns a Graph wrapping g with an empty history.
+func New(g Mutable) *Graph {
+	return &Graph{Mutable: g, checkpoints: make(map[int]int)}
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing
+// node ID.
+func (g *Graph) AddNode(n graph.Node) {
+	g.record([]Mutation{g.addNode(n)})
+}
+
+func (g *Graph) addNode(n graph.Node) Mutation {
+	m := Mutation{Op: AddNode, Node: n}
+	g.apply(m)
+	return m
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it. If the
+// node is not in the graph it is a no-op and no step is recorded.
+func (g *Graph) RemoveNode(n graph.Node) {
+	if !g.Has(n) {
+		return
+	}
+	m := Mutation{Op: RemoveNode, Node: n}
+	for _, v := range g.From(n) {
+		m.incident = append(m.incident, g.Edge(n, v))
+	}
+	if d, ok := g.Mutable.(graph.Directed); ok {
+		for _, u := range d.To(n) {
+			if u.ID() == n.ID() {
+				continue
+			}
+			m.incident = append(m.incident, g.Edge(u, n))
+		}
+	}
+	g.apply(m)
+	g.record([]Mutation{m})
+}
+
+// SetEdge adds e to the graph, replacing any existing edge between its terminal
+// nodes. Terminal nodes that are not in the graph are added, and the additions are
+// recorded as part of the same step.
+func (g *Graph) SetEdge(e graph.Edge) {
+	var step []Mutation
+	for _, n := range []graph.Node{e.From(), e.To()} {
+		if !g.Has(n) {
+			step = append(step, g.addNode(n))
+		}
+	}
+	m := Mutation{Op: SetEdge, Edge: e, prev: g.Edge(e.From(), e.To())}
+	g.apply(m)
+	g.record(append(step, m))
+}
+
+// RemoveEdge removes the edge between the terminal nodes of e, leaving the terminal
+// nodes. If the edge does not exist it is a no-op and no step is recorded.
+func (g *Graph) RemoveEdge(e graph.Edge) {
+	if !g.Has(e.From()) || !g.Has(e.To()) {
+		return
+	}
+	old := g.Edge(e.From(), e.To())
+	if old == nil {
+		return
+	}
+	m := Mutation{Op: RemoveEdge, Edge: old}
+	g.apply(m)
+	g.record([]Mutation{m})
+}
+
+// apply applies m to the wrapped graph and notifies subscribers.
+func (g *Graph) apply(m Mutation) {
+	do(g.Mutable, m)
+	g.notify(Event{Op: m.Op, Node: m.Node, Edge: m.Edge})
+}
+
+// revert reverts the effect of m on the wrapped graph.
+func (g *Graph) revert(m Mutation) {
+	switch m.Op {
+	case AddNode:
+		g.apply(Mutation{Op: RemoveNode, Node: m.Node})
+	case RemoveNode:
+		g.apply(Mutation{Op: AddNode, Node: m.Node})
+		for _, e := range m.incident {
+			g.apply(Mutation{Op: SetEdge, Edge: e})
+		}
+	case SetEdge:
+		if m.prev == nil {
+			g.apply(Mutation{Op: RemoveEdge, Edge: m.Edge})
+		} else {
+			g.apply(Mutation{Op: SetEdge, Edge: m.prev})
+		}
+	case RemoveEdge:
+		g.apply(Mutation{Op: SetEdge, Edge: m.Edge})
+	default:
+		panic("versioned: unknown mutation")
+	}
+}
+
+// record adds step to the history, discarding any undone steps and the
+// checkpoints that refer to them.
+func (g *Graph) record(step []Mutation) {
+	g.discardUndone()
+	g.done = append(g.done, step)
+}
+
+// discardUndone discards the undone steps and the checkpoints that
+// refer to them.
+func (g *Graph) discardUndone() {
+	if len(g.undone) == 0 {
+		return
+	}
+	g.undone = nil
+	for tag, pos := range g.checkpoints {
+		if pos > len(g.done) {
+			delete(g.checkpoints, tag)
+		}
+	}
+}
+
+// Undo undoes the most recent step and returns whether there was a step to undo.
+func (g *Graph) Undo() bool {
+	if len(g.done) == 0 {
+		return false
+	}
+	step := g.done[len(g.done)-1]
+	g.done = g.done[:len(g.done)-1]
+	for i := len(step) - 1; i >= 0; i-- {
+		g.revert(step[i])
+	}
+	g.undone = append(g.undone, step)
+	return true
+}
+
+// Redo redoes the most recently undone step and returns whether there was a step
+// to redo. Undone steps are discarded when a new mutation is made.
+func (g *Graph) Redo() bool {
+	if len(g.undone) == 0 {
+		return false
+	}
+	step := g.undone[len(g.undone)-1]
+	g.undone = g.undone[:len(g.undone)-1]
+	for _, m := range step {
+		g.apply(m)
+	}
+	g.done = append(g.done, step)
+	return true
+}
+
+// Checkpoint returns a tag identifying the current state of the graph that may be
+// passed to Rollback.
+func (g *Graph) Checkpoint() int {
+	tag := g.nextTag
+	g.nextTag++
+	g.checkpoints[tag] = len(g.done)
+	return tag
+}
+
+// ErrUnknownCheckpoint is returned by Rollback when the checkpoint tag is not
+// known or refers to a state that has been discarded from the history.
+var ErrUnknownCheckpoint = errors.New("versioned: unknown checkpoint")
+
+// Rollback undoes steps until the graph is in the state identified by the
+// checkpoint tag. The undone steps may be redone. Rolling back to a checkpoint
+// that is ahead of the current state redoes steps to reach it.
+func (g *Graph) Rollback(tag int) error {
+	pos, ok := g.checkpoints[tag]
+	if !ok {
+		return ErrUnknownCheckpoint
+	}
+	for len(g.done) > pos {
+		g.Undo()
+	}
+	for len(g.done) < pos {
+		g.Redo()
+	}
+	return nil
+}
+
+// Log returns the mutations that produced the current state of the graph from
+// its state when it was wrapped, in order. The log does not include undone steps.
+func (g *Graph) Log() []Mutation {
+	var log []Mutation
+	for _, step := range g.done {
+		log = append(log, step...)
+	}
+	return log
+}
+
+// Replay applies the mutations in log to dst in order.
+func Replay(dst Mutable, log []Mutation) {
+	for _, m := range log {
+		do(dst, m)
+	}
+}
+
+func do(dst Mutable, m Mutation) {
+	switch m.Op {
+	case AddNode:
+		dst.AddNode(m.Node)
+	case RemoveNode:
+		dst.RemoveNode(m.Node)
+	case SetEdge:
+		dst.SetEdge(m.Edge)
+	case RemoveEdge:
+		dst.RemoveEdge(m.Edge)
+	default:
+		panic("versioned: unknown mutation")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versioned
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gonum/graph"
+	"github.com/gonum/graph/simple"
+)
+
+// state returns a canonical description of the nodes
+// and weighted edges of g.
+func state(g graph.Graph) (nodes []int, edges map[[2]int]float64) {
+	edges = make(map[[2]int]float64)
+	for _, u := range g.Nodes() {
+		nodes = append(nodes, u.ID())
+		for _, v := range g.From(u) {
+			edges[[2]int{u.ID(), v.ID()}] = g.Edge(u, v).Weight()
+		}
+	}
+	sort.Ints(nodes)
+	return nodes, edges
+}
+
+func sameState(a, b graph.Graph) bool {
+	an, ae := state(a)
+	bn, be := state(b)
+	return reflect.DeepEqual(an, bn) && reflect.DeepEqual(ae, be)
+}
+
+func TestUndoRedo(t *testing.T) {
+	for _, newGraph := range []func() Mutable{
+		func() Mutable { return simple.NewDirectedGraph(0, math.Inf(1)) },
+		func() Mutable { return simple.NewUndirectedGraph(0, math.Inf(1)) },
+	} {
+		g := New(newGraph())
+		var snapshots []Mutable
+		snapshot := func() {
+			s := newGraph()
+			graph.Copy(s, g)
+			snapshots = append(snapshots, s)
+		}
+
+		snapshot()
+		g.AddNode(simple.Node(0))
+		snapshot()
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+		snapshot()
+		g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 2})
+		snapshot()
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 3})
+		snapshot()
+		g.RemoveNode(simple.Node(1))
+		snapshot()
+		g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0), W: 4})
+		snapshot()
+		g.RemoveEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+		snapshot()
+
+		// No-op mutations are not recorded.
+		g.RemoveEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+		g.RemoveNode(simple.Node(10))
+
+		for i := len(snapshots) - 2; i >= 0; i-- {
+			if !g.Undo() {
+				t.Fatalf("unexpected failed undo to state %d", i)
+			}
+			if !sameState(g, snapshots[i]) {
+				t.Errorf("unexpected state after undo to state %d", i)
+			}
+		}
+		if g.Undo() {
+			t.Errorf("unexpected undo of empty history")
+		}
+		for i := 1; i < len(snapshots); i++ {
+			if !g.Redo() {
+				t.Fatalf("unexpected failed redo to state %d", i)
+			}
+			if !sameState(g, snapshots[i]) {
+				t.Errorf("unexpected state after redo to state %d", i)
+			}
+		}
+		if g.Redo() {
+			t.Errorf("unexpected redo with no undone steps")
+		}
+
+		replayed := newGraph()
+		Replay(replayed, g.Log())
+		if !sameState(replayed, g) {
+			t.Errorf("replayed log does not reproduce graph")
+		}
+	}
+}
+
+func TestCheckpointRollback(t *testing.T) {
+	g := New(simple.NewDirectedGraph(0, math.Inf(1)))
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	start := g.Checkpoint()
+	want := simple.NewDirectedGraph(0, math.Inf(1))
+	graph.Copy(want, g)
+
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2), W: 1})
+	g.RemoveNode(simple.Node(0))
+	end := g.Checkpoint()
+	wantEnd := simple.NewDirectedGraph(0, math.Inf(1))
+	graph.Copy(wantEnd, g)
+
+	if err := g.Rollback(start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sameState(g, want) {
+		t.Errorf("unexpected state after rollback")
+	}
+	if err := g.Rollback(end); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sameState(g, wantEnd) {
+		t.Errorf("unexpected state after rolling forward")
+	}
+
+	g.Rollback(start)
+	g.AddNode(simple.Node(5))
+	if err := g.Rollback(end); err != ErrUnknownCheckpoint {
+		t.Errorf("expected error for discarded checkpoint: got:%v", err)
+	}
+	if err := g.Rollback(start); err != nil {
+		t.Errorf("unexpected error for live checkpoint: %v", err)
+	}
+	if !sameState(g, want) {
+		t.Errorf("unexpected state after rollback past new mutation")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versioned
+
+import (
+	"sync"
+
+	"github.com/gonum/graph"
+)
+
+// Event is a change made to a Graph. Node is set for node changes and Edge is
+// set for edge changes. Undoing a step delivers the events that revert it.
+type Event struct {
+	Op   Op
+	Node graph.Node
+	Edge graph.Edge
+}
+
+// Backpressure is the behavior of a subscription when its buffer is full.
+type Backpressure int
+
+const (
+	// Block blocks mutation of the graph
+	// until the subscriber receives the
+	// event or cancels the subscription.
+	Block Backpressure = iota
+	// DropNewest discards the new event.
+	DropNewest
+	// DropOldest discards the oldest
+	// buffered event to make room for
+	// the new event.
+	DropOldest
+)
+
+// SubscribeOptions holds options for Subscribe.
+type SubscribeOptions struct {
+	// Buffer is the capacity of
+	// the event channel.
+	Buffer int
+
+	// Backpressure is the behavior
+	// when the buffer is full.
+	Backpressure Backpressure
+}
+
+// Subscribe returns a channel on which the changes made to g are delivered in
+// order, and a function that cancels the subscription and closes the channel.
+// The cancel function may be called more than once and from any goroutine.
+//
+// With the Block policy a subscriber that stops receiving events stalls
+// mutation of g until it cancels the subscription. The drop policies never
+// stall mutation but may lose events, so subscribers that must see every event
+// should use Block.
+func (g *Graph) Subscribe(opts SubscribeOptions) (events <-chan Event, cancel func()) {
+	if opts.Backpressure == DropOldest && opts.Buffer < 1 {
+		panic("versioned: DropOldest requires a buffer")
+	}
+	s := &subscriber{
+		events: make(chan Event, opts.Buffer),
+		done:   make(chan struct{}),
+		policy: opts.Backpressure,
+	}
+	g.subs.add(s)
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			close(s.done)
+			g.subs.remove(s)
+			close(s.events)
+		})
+	}
+	return s.events, cancel
+}
+
+func (g *Graph) notify(e Event) { g.subs.send(e) }
+
+// subscribers is a set of subscriptions safe for concurrent use.
+type subscribers struct {
+	mu   sync.Mutex
+	list []*subscriber
+}
+
+type subscriber struct {
+	events chan Event
+
+	// done is closed when the
+	// subscription is cancelled.
+	done chan struct{}
+
+	policy Backpressure
+}
+
+func (s *subscribers) add(sub *subscriber) {
+	s.mu.Lock()
+	s.list = append(s.list, sub)
+	s.mu.Unlock()
+}
+
+func (s *subscribers) remove(sub *subscriber) {
+	s.mu.Lock()
+	for i, o := range s.list {
+		if o == sub {
+			s.list = append(s.list[:i], s.list[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+}
+
+// send delivers e to each subscriber according to its policy. The
+// lock is held during delivery so a subscription's channel is not
+// closed while an event is being sent on it.
+func (s *subscribers) send(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.list {
+		switch sub.policy {
+		case Block:
+			select {
+			case sub.events <- e:
+			case <-sub.done:
+			}
+		case DropNewest:
+			select {
+			case sub.events <- e:
+			default:
+			}
+		case DropOldest:
+			for {
+				select {
+				case sub.events <- e:
+				default:
+					select {
+					case <-sub.events:
+					default:
+					}
+					continue
+				}
+				break
+			}
+		default:
+			panic("versioned: unknown backpressure policy")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright ©2016 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versioned
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/graph/simple"
+)
+
+func TestSubscribe(t *testing.T) {
+	g := New(simple.NewDirectedGraph(0, math.Inf(1)))
+	events, cancel := g.Subscribe(SubscribeOptions{Buffer: 16})
+
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1), W: 1})
+	g.Undo()
+
+	want := []Op{AddNode, AddNode, SetEdge, RemoveEdge, RemoveNode, RemoveNode}
+	for i, op := range want {
+		e := <-events
+		if e.Op != op {
+			t.Errorf("unexpected event %d: got:%v want:%v", i, e.Op, op)
+		}
+	}
+	cancel()
+	cancel()
+	if _, ok := <-events; ok {
+		t.Errorf("expected closed channel after cancel")
+	}
+
+	// Mutations after cancellation do not block.
+	g.AddNode(simple.Node(5))
+}
+
+func TestSubscribeBlock(t *testing.T) {
+	g := New(simple.NewDirectedGraph(0, math.Inf(1)))
+	events, cancel := g.Subscribe(SubscribeOptions{})
+
+	done := make(chan struct{})
+	go func() {
+		g.AddNode(simple.Node(0))
+		g.AddNode(simple.Node(1))
+		close(done)
+	}()
+	for _, id := range []int{0, 1} {
+		if e := <-events; e.Node.ID() != id {
+			t.Errorf("unexpected node in event: got:%d want:%d", e.Node.ID(), id)
+		}
+	}
+	<-done
+
+	// Cancelling unblocks a stalled mutation.
+	done = make(chan struct{})
+	go func() {
+		g.AddNode(simple.Node(2))
+		close(done)
+	}()
+	cancel()
+	<-done
+}
+
+func TestSubscribeDrop(t *testing.T) {
+	g := New(simple.NewDirectedGraph(0, math.Inf(1)))
+	newest, cancelNewest := g.Subscribe(SubscribeOptions{Buffer: 2, Backpressure: DropNewest})
+	defer cancelNewest()
+	oldest, cancelOldest := g.Subscribe(SubscribeOptions{Buffer: 2, Backpressure: DropOldest})
+	defer cancelOldest()
+
+	for id := 0; id < 5; id++ {
+		g.AddNode(simple.Node(id))
+	}
+
+	for _, test := range []struct {
+		name   string
+		events <-chan Event
+		want   []int
+	}{
+		{name: "DropNewest", events: newest, want: []int{0, 1}},
+		{name: "DropOldest", events: oldest, want: []int{3, 4}},
+	} {
+		if len(test.events) != len(test.want) {
+			t.Errorf("unexpected number of buffered events for %s: got:%d want:%d", test.name, len(test.events), len(test.want))
+			continue
+		}
+		for _, id := range test.want {
+			if e := <-test.events; e.Node.ID() != id {
+				t.Errorf("unexpected node in event for %s: got:%d want:%d", test.name, e.Node.ID(), id)
+			}
+		}
+	}
+}